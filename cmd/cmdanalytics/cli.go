@@ -0,0 +1,133 @@
+package cmdanalytics
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ctwj/urldb/db"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// analyticsCmd 统计日汇总命令
+var analyticsCmd = &cobra.Command{
+	Use:   "analytics",
+	Short: "统计日汇总管理命令",
+	Long:  `汇总访问、搜索与API访问记录到日汇总表，支持补汇总历史数据与重新汇总指定日期`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// GetAnalyticsCmd 获取统计日汇总命令
+func GetAnalyticsCmd() *cobra.Command {
+	return analyticsCmd
+}
+
+// backfillCmd 补汇总命令
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "补汇总日期区间内的统计数据",
+	Long: `汇总 [from, to] 内的日期，默认从原始记录最早的日期到昨天，已汇总的日期默认跳过
+
+示例:
+  urldb analytics backfill
+  urldb analytics backfill --from 2026-01-01 --to 2026-01-31 --force`,
+	Run: runBackfill,
+}
+
+// rollupCmd 重新汇总指定日期命令
+var rollupCmd = &cobra.Command{
+	Use:   "rollup",
+	Short: "重新汇总指定日期",
+	Long: `删除指定日期的汇总结果后按原始记录重新生成，可重复执行
+
+示例:
+  urldb analytics rollup --date 2026-01-15`,
+	Run: runRollup,
+}
+
+// nightlyCmd 每日任务命令
+var nightlyCmd = &cobra.Command{
+	Use:   "nightly",
+	Short: "执行一次每日任务（补齐未汇总日期并按保留天数清理原始记录）",
+	Run:   runNightly,
+}
+
+// InitAnalyticsCommands 初始化统计日汇总命令
+func InitAnalyticsCommands() {
+	backfillCmd.Flags().String("from", "", "开始日期 YYYY-MM-DD，默认原始记录最早的日期")
+	backfillCmd.Flags().String("to", "", "结束日期 YYYY-MM-DD，默认昨天")
+	backfillCmd.Flags().Bool("force", false, "重新汇总已汇总的日期")
+	rollupCmd.Flags().String("date", "", "日期 YYYY-MM-DD")
+	rollupCmd.MarkFlagRequired("date")
+
+	analyticsCmd.AddCommand(backfillCmd)
+	analyticsCmd.AddCommand(rollupCmd)
+	analyticsCmd.AddCommand(nightlyCmd)
+}
+
+// newService 加载环境变量、连接数据库并创建统计服务
+func newService() *services.AnalyticsService {
+	if err := godotenv.Load(); err != nil {
+		utils.Info("未找到.env文件，使用默认配置")
+	}
+	utils.InitTimezone()
+	if err := db.InitDB(); err != nil {
+		utils.Error("连接数据库失败: %v", err)
+		os.Exit(1)
+	}
+	repoManager := repo.NewRepositoryManager(db.DB)
+	return services.NewAnalyticsService(repoManager.AnalyticsRepository, repoManager.SystemConfigRepository)
+}
+
+// runBackfill 运行补汇总命令
+func runBackfill(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	force, _ := cmd.Flags().GetBool("force")
+
+	result, err := newService().Backfill(context.Background(), from, to, force)
+	if err != nil {
+		utils.Error("补汇总失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("日期区间: %s ~ %s\n", result.From, result.To)
+	fmt.Printf("已汇总: %d 天, 跳过: %d 天, 失败: %d 天\n", len(result.Rolled), result.Skipped, len(result.Failed))
+	for _, day := range result.Failed {
+		fmt.Printf("  失败: %s\n", day)
+	}
+	if len(result.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runRollup 运行重新汇总命令
+func runRollup(cmd *cobra.Command, args []string) {
+	date, _ := cmd.Flags().GetString("date")
+
+	day, err := newService().RollupDay(date)
+	if err != nil {
+		utils.Error("汇总失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s: 访问 %d (独立IP %d), 搜索 %d, API请求 %d (错误 %d)\n", date,
+		day.Views, day.UniqueVisitors, day.Searches, day.APIRequests, day.APIErrors)
+}
+
+// runNightly 运行每日任务命令
+func runNightly(cmd *cobra.Command, args []string) {
+	result, err := newService().RunNightly(context.Background())
+	if err != nil {
+		utils.Error("执行每日任务失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("已汇总: %d 天, 失败: %d 天\n", len(result.Backfill.Rolled), len(result.Backfill.Failed))
+	for table, n := range result.Purged {
+		fmt.Printf("清理 %s: %d 条\n", table, n)
+	}
+}
@@ -0,0 +1,298 @@
+package cmdbackup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/backup"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// backupCmd 备份与恢复命令
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "备份与恢复命令",
+	Long: `备份数据库、系统配置、上传目录与插件目录到本地目录或 S3 兼容存储，并从备份恢复
+
+存储通过环境变量配置: BACKUP_STORAGE=local|s3, BACKUP_DIR, BACKUP_S3_*`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// GetBackupCmd 获取备份命令
+func GetBackupCmd() *cobra.Command {
+	return backupCmd
+}
+
+// runCmd 立即备份命令
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "立即备份并按保留规则清理旧备份",
+	Run:   runRun,
+}
+
+// listCmd 列出备份命令
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有备份",
+	Run:   runList,
+}
+
+// verifyCmd 校验备份命令
+var verifyCmd = &cobra.Command{
+	Use:   "verify <name>",
+	Short: "下载并校验备份中每个文件的大小与 SHA-256",
+	Args:  cobra.ExactArgs(1),
+	Run:   runVerify,
+}
+
+// restoreCmd 恢复命令
+var restoreCmd = &cobra.Command{
+	Use:   "restore [name]",
+	Short: "从备份恢复",
+	Long: `从指定备份或指定时间点之前最近的备份恢复，恢复前完整校验归档。
+数据库恢复会覆盖当前数据，请先停止服务。
+
+示例:
+  urldb backup restore urldb-20260501-030000.tar.gz
+  urldb backup restore --at "2026-05-01 12:00:00" --only db,config
+  urldb backup restore --at 2026-05-01 --only files --yes`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runRestore,
+}
+
+// pruneCmd 清理命令
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "按保留规则清理旧备份",
+	Run:   runPrune,
+}
+
+// InitBackupCommands 初始化备份命令
+func InitBackupCommands() {
+	restoreCmd.Flags().String("at", "", "恢复不晚于该时间的最近一个备份，格式 YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS")
+	restoreCmd.Flags().String("only", "db,files,config", "恢复的内容，逗号分隔: db, files, config")
+	restoreCmd.Flags().Bool("yes", false, "跳过确认")
+
+	backupCmd.AddCommand(runCmd)
+	backupCmd.AddCommand(listCmd)
+	backupCmd.AddCommand(verifyCmd)
+	backupCmd.AddCommand(restoreCmd)
+	backupCmd.AddCommand(pruneCmd)
+}
+
+// newService 加载环境变量、连接数据库并创建备份服务
+func newService() *services.BackupService {
+	if err := godotenv.Load(); err != nil {
+		utils.Info("未找到.env文件，使用默认配置")
+	}
+	utils.InitTimezone()
+	storage, err := services.NewBackupStorageFromEnv()
+	if err != nil {
+		utils.Error("初始化备份存储失败: %v", err)
+		os.Exit(1)
+	}
+	if err := db.InitDB(); err != nil {
+		utils.Error("连接数据库失败: %v", err)
+		os.Exit(1)
+	}
+	repoManager := repo.NewRepositoryManager(db.DB)
+	return services.NewBackupService(storage, backup.NewPostgres(db.DSN()), repoManager.SystemConfigRepository, services.DefaultBackupSources())
+}
+
+// runRun 运行立即备份命令
+func runRun(cmd *cobra.Command, args []string) {
+	service := newService()
+	result, err := service.Run(context.Background())
+	if err != nil {
+		utils.Error("备份失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("备份完成: %s\n", result.Name)
+	fmt.Printf("大小 %s, 数据库导出方式 %s, 文件 %d 个, 目录 %s, 耗时 %s\n",
+		formatSize(result.Size), result.DBMode, result.Files, strings.Join(result.Sources, ","), result.Duration)
+	for _, name := range result.Pruned {
+		fmt.Printf("已清理: %s\n", name)
+	}
+}
+
+// runList 运行列出备份命令
+func runList(cmd *cobra.Command, args []string) {
+	service := newService()
+	objects, err := service.List(context.Background())
+	if err != nil {
+		utils.Error("获取备份列表失败: %v", err)
+		os.Exit(1)
+	}
+	if len(objects) == 0 {
+		fmt.Println("暂无备份")
+		return
+	}
+	for _, o := range objects {
+		fmt.Printf("%s\t%s\n", o.Name, formatSize(o.Size))
+	}
+	fmt.Printf("共 %d 个备份\n", len(objects))
+}
+
+// runVerify 运行校验命令
+func runVerify(cmd *cobra.Command, args []string) {
+	service := newService()
+	manifest, err := service.Verify(context.Background(), args[0])
+	if err != nil {
+		utils.Error("校验失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("校验通过: %s\n", args[0])
+	printManifest(manifest)
+}
+
+// runRestore 运行恢复命令
+func runRestore(cmd *cobra.Command, args []string) {
+	at, _ := cmd.Flags().GetString("at")
+	only, _ := cmd.Flags().GetString("only")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if (len(args) == 0) == (at == "") {
+		utils.Error("参数错误: 需要指定备份名或 --at 其中之一")
+		os.Exit(1)
+	}
+	opts, err := parseOnly(only)
+	if err != nil {
+		utils.Error("参数错误: --only %v", err)
+		os.Exit(1)
+	}
+
+	service := newService()
+	ctx := context.Background()
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		t, err := parseTime(at)
+		if err != nil {
+			utils.Error("参数错误: --at %v", err)
+			os.Exit(1)
+		}
+		if name, err = service.FindAt(ctx, t); err != nil {
+			utils.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	if !yes && !confirm(fmt.Sprintf("将从 %s 恢复 %s，当前数据会被覆盖，请确认服务已停止。继续？(yes/no): ", name, only)) {
+		fmt.Println("已取消")
+		return
+	}
+
+	result, err := service.Restore(ctx, name, opts)
+	if err != nil {
+		utils.Error("恢复失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("已从 %s 恢复\n", result.Name)
+	if result.Database != nil {
+		fmt.Printf("数据库: 方式 %s, 表 %d 个, 行 %d\n", result.Database.Mode, result.Database.Tables, result.Database.Rows)
+		if len(result.Database.Skipped) > 0 {
+			fmt.Printf("当前数据库中不存在而跳过的表: %s\n", strings.Join(result.Database.Skipped, ", "))
+		}
+	}
+	if opts.Config {
+		fmt.Printf("系统配置: %d 项\n", result.Configs)
+	}
+	if opts.Files {
+		fmt.Printf("文件: %d 个\n", result.Files)
+	}
+}
+
+// runPrune 运行清理命令
+func runPrune(cmd *cobra.Command, args []string) {
+	service := newService()
+	pruned, err := service.ApplyRetention(context.Background())
+	for _, name := range pruned {
+		fmt.Printf("已清理: %s\n", name)
+	}
+	if err != nil {
+		utils.Error("清理失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("共清理 %d 个备份\n", len(pruned))
+}
+
+// parseOnly 解析 --only
+func parseOnly(only string) (services.BackupRestoreOptions, error) {
+	var opts services.BackupRestoreOptions
+	for _, part := range strings.Split(only, ",") {
+		switch strings.TrimSpace(part) {
+		case "db":
+			opts.Database = true
+		case "files":
+			opts.Files = true
+		case "config":
+			opts.Config = true
+		case "":
+		default:
+			return opts, fmt.Errorf("未知的内容 %s，可选 db, files, config", part)
+		}
+	}
+	if !opts.Database && !opts.Files && !opts.Config {
+		return opts, fmt.Errorf("至少指定一项")
+	}
+	return opts, nil
+}
+
+// parseTime 按本地时区解析时间，只有日期时取当天结束
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(utils.TimeFormatDateTime, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(utils.TimeFormatDate, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("时间格式应为 YYYY-MM-DD 或 YYYY-MM-DD HH:MM:SS")
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+// confirm 从标准输入读取确认
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "yes" || answer == "y"
+}
+
+// printManifest 输出备份清单摘要
+func printManifest(m *backup.Manifest) {
+	fmt.Printf("创建时间: %s, 程序版本: %s, 数据库导出方式: %s\n",
+		utils.FormatTime(m.CreatedAt, utils.TimeFormatDateTime), m.AppVersion, m.DBMode)
+	var total int64
+	for _, f := range m.Files {
+		total += f.Size
+	}
+	fmt.Printf("文件 %d 个, 共 %s, 目录 %s\n", len(m.Files), formatSize(total), strings.Join(m.Sources, ","))
+	for _, t := range m.Tables {
+		fmt.Printf("  表 %s: %d 行\n", t.Name, t.Rows)
+	}
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.2fGB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.2fMB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.2fKB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", size)
+	}
+}
@@ -0,0 +1,276 @@
+package cmdcatalog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ctwj/urldb/db"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/catalog"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// catalogCmd 资源目录导出/导入命令
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "资源目录导出/导入命令",
+	Long:  `导出资源目录（含分类、标签、网盘、资源组key、影视元数据）到 JSONL/CSV/XLSX 文件，或从文件导入到本实例`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// GetCatalogCmd 获取资源目录命令
+func GetCatalogCmd() *cobra.Command {
+	return catalogCmd
+}
+
+// exportCmd 导出命令
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出资源目录",
+	Long: `按与资源列表相同的筛选条件导出资源，格式默认按输出文件扩展名识别
+
+示例:
+  urldb catalog export --out resources.jsonl
+  urldb catalog export --out valid.xlsx --is-valid true --category 电影`,
+	Run: runExport,
+}
+
+// importCmd 导入命令
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "导入资源目录",
+	Long: `从目录文件导入资源，按规范化链接去重，可映射分类和标签名称
+
+示例:
+  urldb catalog import --file resources.jsonl --dry-run
+  urldb catalog import --file resources.csv --category-map 电影=影视 --tag-map 广告= --create-missing`,
+	Run: runImport,
+}
+
+// InitCatalogCommands 初始化资源目录命令
+func InitCatalogCommands() {
+	exportCmd.Flags().String("out", "", "输出文件路径")
+	exportCmd.Flags().String("format", "", "格式 jsonl/csv/xlsx，默认按输出文件扩展名识别")
+	exportCmd.Flags().String("search", "", "标题或描述包含的关键词")
+	exportCmd.Flags().Uint("category-id", 0, "分类ID")
+	exportCmd.Flags().String("category", "", "分类名称")
+	exportCmd.Flags().String("tag", "", "标签名称")
+	exportCmd.Flags().String("tag-ids", "", "标签ID列表，逗号分隔")
+	exportCmd.Flags().Uint("pan-id", 0, "网盘ID")
+	exportCmd.Flags().String("pan-name", "", "网盘名称")
+	exportCmd.Flags().String("is-valid", "", "是否有效 true/false")
+	exportCmd.Flags().String("is-public", "", "是否公开 true/false")
+	exportCmd.Flags().String("has-save-url", "", "是否有转存链接 true/false")
+	exportCmd.Flags().Int("year", 0, "影视年份")
+	exportCmd.Flags().String("region", "", "影视地区")
+	exportCmd.Flags().String("genre", "", "影视类型")
+	exportCmd.Flags().String("media-type", "", "影视类别 movie/tv")
+	exportCmd.Flags().Float64("min-rating", 0, "最低评分")
+	exportCmd.MarkFlagRequired("out")
+
+	importCmd.Flags().String("file", "", "目录文件路径")
+	importCmd.Flags().String("format", "", "格式 jsonl/csv/xlsx，默认按文件扩展名识别")
+	importCmd.Flags().StringArray("category-map", nil, "分类映射 源名称=目标名称，目标为空表示丢弃，可重复")
+	importCmd.Flags().StringArray("tag-map", nil, "标签映射 源名称=目标名称，目标为空表示丢弃，可重复")
+	importCmd.Flags().Bool("create-missing", false, "自动创建不存在的分类和标签")
+	importCmd.Flags().Bool("dry-run", false, "只生成报告不写入")
+	importCmd.MarkFlagRequired("file")
+
+	catalogCmd.AddCommand(exportCmd)
+	catalogCmd.AddCommand(importCmd)
+}
+
+// newService 加载环境变量、连接数据库并创建资源目录服务
+func newService() *services.CatalogService {
+	if err := godotenv.Load(); err != nil {
+		utils.Info("未找到.env文件，使用默认配置")
+	}
+	utils.InitTimezone()
+	if err := db.InitDB(); err != nil {
+		utils.Error("连接数据库失败: %v", err)
+		os.Exit(1)
+	}
+	repoManager := repo.NewRepositoryManager(db.DB)
+	return services.NewCatalogService(
+		repoManager.ResourceRepository,
+		repoManager.CategoryRepository,
+		repoManager.TagRepository,
+		repoManager.PanRepository,
+		repoManager.ResourceMetadataRepository,
+	)
+}
+
+// runExport 运行导出命令
+func runExport(cmd *cobra.Command, args []string) {
+	out, _ := cmd.Flags().GetString("out")
+	format := resolveFormat(cmd, out)
+	filter, err := exportFilter(cmd)
+	if err != nil {
+		utils.Error("参数错误: %v", err)
+		os.Exit(1)
+	}
+
+	service := newService()
+	f, err := os.Create(out)
+	if err != nil {
+		utils.Error("创建输出文件失败: %v", err)
+		os.Exit(1)
+	}
+	count, err := service.Export(context.Background(), f, format, filter, func(processed, total int) {
+		fmt.Printf("\r已导出 %d / %d", processed, total)
+	})
+	fmt.Println()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out)
+		utils.Error("导出失败: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("已导出 %d 条资源到 %s\n", count, out)
+}
+
+// runImport 运行导入命令
+func runImport(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	categoryPairs, _ := cmd.Flags().GetStringArray("category-map")
+	tagPairs, _ := cmd.Flags().GetStringArray("tag-map")
+	createMissing, _ := cmd.Flags().GetBool("create-missing")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	opts := services.CatalogImportOptions{
+		Format:        resolveFormat(cmd, file),
+		CreateMissing: createMissing,
+		DryRun:        dryRun,
+	}
+	var err error
+	if opts.CategoryMap, err = parseMapping(categoryPairs); err != nil {
+		utils.Error("参数错误: --category-map %v", err)
+		os.Exit(1)
+	}
+	if opts.TagMap, err = parseMapping(tagPairs); err != nil {
+		utils.Error("参数错误: --tag-map %v", err)
+		os.Exit(1)
+	}
+
+	service := newService()
+	f, err := os.Open(file)
+	if err != nil {
+		utils.Error("打开文件失败: %v", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	report, err := service.Import(context.Background(), f, opts, func(processed, total int) {
+		fmt.Printf("\r已处理 %d 条", processed)
+	})
+	fmt.Println()
+	if report != nil {
+		printReport(report)
+	}
+	if err != nil {
+		utils.Error("导入失败: %v", err)
+		os.Exit(1)
+	}
+}
+
+// resolveFormat 取 --format，未指定时按文件扩展名识别
+func resolveFormat(cmd *cobra.Command, path string) string {
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = catalog.FormatFromName(path)
+	}
+	format = strings.ToLower(format)
+	for _, f := range catalog.Formats {
+		if f == format {
+			return format
+		}
+	}
+	utils.Error("无法识别文件格式，请通过 --format 指定 %s", strings.Join(catalog.Formats, "/"))
+	os.Exit(1)
+	return ""
+}
+
+// exportFilter 从命令行参数构建筛选条件
+func exportFilter(cmd *cobra.Command) (services.CatalogFilter, error) {
+	flags := cmd.Flags()
+	var filter services.CatalogFilter
+	filter.Search, _ = flags.GetString("search")
+	filter.CategoryID, _ = flags.GetUint("category-id")
+	filter.Category, _ = flags.GetString("category")
+	filter.Tag, _ = flags.GetString("tag")
+	filter.TagIDs, _ = flags.GetString("tag-ids")
+	filter.PanID, _ = flags.GetUint("pan-id")
+	filter.PanName, _ = flags.GetString("pan-name")
+	filter.Year, _ = flags.GetInt("year")
+	filter.Region, _ = flags.GetString("region")
+	filter.Genre, _ = flags.GetString("genre")
+	filter.MediaType, _ = flags.GetString("media-type")
+	filter.MinRating, _ = flags.GetFloat64("min-rating")
+
+	for name, target := range map[string]**bool{
+		"is-valid":     &filter.IsValid,
+		"is-public":    &filter.IsPublic,
+		"has-save-url": &filter.HasSaveURL,
+	} {
+		raw, _ := flags.GetString(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("--%s 只能是 true 或 false", name)
+		}
+		*target = &v
+	}
+	return filter, nil
+}
+
+// parseMapping 解析 源名称=目标名称 形式的映射
+func parseMapping(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	mapping := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(from) == "" {
+			return nil, fmt.Errorf("格式应为 源名称=目标名称: %s", pair)
+		}
+		mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return mapping, nil
+}
+
+// printReport 输出导入报告
+func printReport(r *services.CatalogImportReport) {
+	if r.DryRun {
+		fmt.Println("预检模式，未写入任何数据")
+	}
+	fmt.Printf("共 %d 条: 新增 %d, 重复 %d, 无效 %d, 失败 %d, 重新分配key %d\n",
+		r.Total, r.Created, r.Duplicates, r.Invalid, r.Failed, r.KeysRemapped)
+	printList := func(label string, names []string) {
+		if len(names) > 0 {
+			fmt.Printf("%s: %s\n", label, strings.Join(names, ", "))
+		}
+	}
+	printList("新建分类", r.CreatedCategories)
+	printList("新建标签", r.CreatedTags)
+	printList("未匹配的分类", r.UnmappedCategories)
+	printList("未匹配的标签", r.UnmappedTags)
+	for _, e := range r.Errors {
+		fmt.Printf("  第 %d 行 %s: %s\n", e.Line, e.URL, e.Error)
+	}
+	if r.ErrorsTruncated {
+		fmt.Printf("  仅显示前 %d 条错误\n", len(r.Errors))
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
 	"github.com/ctwj/urldb/utils"
 	"github.com/dustinxie/ecc"
 )
@@ -350,6 +351,13 @@ func (a *AlipanService) alipanSign() string {
 // markInvalid 标记账号失效并写日志（FR-016；不主动推送，由运维巡检）
 func (a *AlipanService) markInvalid(reason string) {
 	utils.Error("[Alipan] 账号标记失效 accountID=%d reason=%s", a.cksEntity.ID, reason)
+	eventbus.Publish(eventbus.TypeAccountFailed, map[string]interface{}{
+		"account_id": a.cksEntity.ID,
+		"platform":   "alipan",
+		"username":   a.cksEntity.Username,
+		"reason":     reason,
+		"source":     "token_refresh",
+	})
 	if !a.hasRepo || a.cksEntity.ID == 0 {
 		return
 	}
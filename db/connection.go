@@ -16,8 +16,8 @@ import (
 
 var DB *gorm.DB
 
-// InitDB 初始化数据库连接
-func InitDB() error {
+// DSN 根据环境变量生成 PostgreSQL 连接串
+func DSN() string {
 	host := os.Getenv("DB_HOST")
 	if host == "" {
 		host = "localhost"
@@ -43,8 +43,13 @@ func InitDB() error {
 		dbname = "url_db"
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
+}
+
+// InitDB 初始化数据库连接
+func InitDB() error {
+	dsn := DSN()
 
 	var err error
 	// 配置慢查询日志
@@ -111,6 +116,35 @@ func InitDB() error {
 			&entity.APIAccessLogSummary{},
 			&entity.Report{},
 			&entity.CopyrightClaim{},
+			&entity.ContentSource{},
+			&entity.ContentSourceItem{},
+			&entity.TelegramImportChannel{},
+			&entity.ReadyResourcePipeline{},
+			&entity.ReadyResourceStageLog{},
+			&entity.ClassificationRule{},
+			&entity.ClassificationReview{},
+			&entity.ClassifierModel{},
+			&entity.ResourceMetadata{},
+			&entity.SearchEngineSubmission{},
+			&entity.GoogleIndexStatus{},
+			&entity.ViewDailyStat{},
+			&entity.SearchDailyStat{},
+			&entity.APIAccessDailyStat{},
+			&entity.AnalyticsRollupDay{},
+			&entity.SearchEngineQuotaUsage{},
+			&entity.Role{},
+			&entity.Permission{},
+			&entity.PermissionDenial{},
+			&entity.APIKey{},
+			&entity.APIKeyDailyUsage{},
+			&entity.UserSession{},
+			&entity.UserTwoFactor{},
+			&entity.LoginHistory{},
+			&entity.AuditLog{},
+			&entity.ModerationAction{},
+			&entity.BlocklistRule{},
+			&entity.ScheduledJobRun{},
+			&entity.ScheduledJobState{},
 			// 插件系统相关表
 			&entity.PluginConfig{},
 			&entity.PluginLog{},
@@ -214,6 +248,35 @@ func autoMigrate() error {
 		&entity.HotDrama{},
 		&entity.File{},
 		&entity.TelegramChannel{},
+		&entity.ContentSource{},
+		&entity.ContentSourceItem{},
+		&entity.TelegramImportChannel{},
+		&entity.ReadyResourcePipeline{},
+		&entity.ReadyResourceStageLog{},
+		&entity.ClassificationRule{},
+		&entity.ClassificationReview{},
+		&entity.ClassifierModel{},
+		&entity.ResourceMetadata{},
+		&entity.SearchEngineSubmission{},
+		&entity.GoogleIndexStatus{},
+		&entity.ViewDailyStat{},
+		&entity.SearchDailyStat{},
+		&entity.APIAccessDailyStat{},
+		&entity.AnalyticsRollupDay{},
+		&entity.SearchEngineQuotaUsage{},
+		&entity.Role{},
+		&entity.Permission{},
+		&entity.PermissionDenial{},
+		&entity.APIKey{},
+		&entity.APIKeyDailyUsage{},
+		&entity.UserSession{},
+		&entity.UserTwoFactor{},
+		&entity.LoginHistory{},
+		&entity.AuditLog{},
+		&entity.ModerationAction{},
+		&entity.BlocklistRule{},
+		&entity.ScheduledJobRun{},
+		&entity.ScheduledJobState{},
 		// 插件系统相关表
 		&entity.PluginConfig{},
 		&entity.PluginLog{},
@@ -251,6 +314,13 @@ func createIndexes(db *gorm.DB) {
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_ready_resource_key ON ready_resource(key)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_ready_resource_url ON ready_resource(url)")
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_ready_resource_create_time ON ready_resource(create_time DESC)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_ready_resource_source ON ready_resource(source)")
+
+	// 内容源表索引
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_content_sources_enabled ON content_sources(enabled)")
+
+	// 待处理资源阶段记录索引
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_ready_resource_stage_logs_stage_status ON ready_resource_stage_logs(stage, status)")
 
 	// 搜索统计表索引
 	db.Exec("CREATE INDEX IF NOT EXISTS idx_search_stats_keyword ON search_stats(keyword)")
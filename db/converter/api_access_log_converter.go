@@ -19,6 +19,7 @@ func ToAPIAccessLogResponse(log *entity.APIAccessLog) dto.APIAccessLogResponse {
 		ProcessCount:   log.ProcessCount,
 		ErrorMessage:   log.ErrorMessage,
 		ProcessingTime: log.ProcessingTime,
+		APIKeyID:       log.APIKeyID,
 		CreatedAt:      log.CreatedAt,
 	}
 }
@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+)
+
+// ToAPIKeyResponse 将APIKey实体转换为APIKeyResponse
+func ToAPIKeyResponse(key *entity.APIKey, now time.Time) dto.APIKeyResponse {
+	scopes := key.ScopeList()
+	if scopes == nil {
+		scopes = []string{}
+	}
+	allowedIPs := key.AllowedIPList()
+	if allowedIPs == nil {
+		allowedIPs = []string{}
+	}
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		RateLimit:  key.RateLimit,
+		DailyQuota: key.DailyQuota,
+		AllowedIPs: allowedIPs,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		UsageCount: key.UsageCount,
+		Status:     key.Status(now),
+		Note:       key.Note,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
+
+// ToAPIKeyResponseList 将APIKey实体列表转换为APIKeyResponse列表
+func ToAPIKeyResponseList(keys []entity.APIKey, now time.Time) []dto.APIKeyResponse {
+	responses := make([]dto.APIKeyResponse, len(keys))
+	for i := range keys {
+		responses[i] = ToAPIKeyResponse(&keys[i], now)
+	}
+	return responses
+}
@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+)
+
+// BlocklistRuleToResponse 将黑名单规则转换为响应对象
+func BlocklistRuleToResponse(rule *entity.BlocklistRule) *dto.BlocklistRuleResponse {
+	response := &dto.BlocklistRuleResponse{
+		ID:        rule.ID,
+		Kind:      rule.Kind,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		Source:    rule.Source,
+		SourceID:  rule.SourceID,
+		Enabled:   rule.Enabled,
+		Note:      rule.Note,
+		HitCount:  rule.HitCount,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: rule.UpdatedAt.Format(time.RFC3339),
+	}
+	if rule.LastHitAt != nil {
+		response.LastHitAt = rule.LastHitAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// BlocklistRulesToResponse 批量转换黑名单规则
+func BlocklistRulesToResponse(rules []entity.BlocklistRule) []*dto.BlocklistRuleResponse {
+	responses := make([]*dto.BlocklistRuleResponse, 0, len(rules))
+	for i := range rules {
+		responses = append(responses, BlocklistRuleToResponse(&rules[i]))
+	}
+	return responses
+}
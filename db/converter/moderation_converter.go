@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+)
+
+// ModerationActionToResponse 将处置记录转换为响应对象
+func ModerationActionToResponse(action *entity.ModerationAction) *dto.ModerationActionResponse {
+	if action == nil {
+		return nil
+	}
+	response := &dto.ModerationActionResponse{
+		ID:           action.ID,
+		SourceType:   action.SourceType,
+		SourceID:     action.SourceID,
+		ResourceKey:  action.ResourceKey,
+		Action:       action.Action,
+		ResourceIDs:  action.ResourceIDList(),
+		BlockTitle:   action.BlockTitle,
+		DeleteFiles:  action.DeleteFiles,
+		FilesDeleted: action.FilesDeleted,
+		FileErrors:   action.FileErrors,
+		Note:         action.Note,
+		OperatorID:   action.OperatorID,
+		Reverted:     action.Reverted,
+		RevertedBy:   action.RevertedBy,
+		CreatedAt:    action.CreatedAt.Format(time.RFC3339),
+	}
+	if action.RevertedAt != nil {
+		response.RevertedAt = action.RevertedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// ModerationActionsToResponse 批量转换处置记录
+func ModerationActionsToResponse(actions []entity.ModerationAction) []*dto.ModerationActionResponse {
+	responses := make([]*dto.ModerationActionResponse, 0, len(actions))
+	for i := range actions {
+		responses = append(responses, ModerationActionToResponse(&actions[i]))
+	}
+	return responses
+}
@@ -2,6 +2,7 @@ package converter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -91,6 +92,7 @@ func TelegramBotConfigToResponse(
 	proxyPassword string,
 	welcomeEnabled bool,
 	welcomeMessage string,
+	adminChatID int64,
 ) dto.TelegramBotConfigResponse {
 	return dto.TelegramBotConfigResponse{
 		BotEnabled:         botEnabled,
@@ -108,6 +110,7 @@ func TelegramBotConfigToResponse(
 		ProxyPassword:      proxyPassword,
 		WelcomeEnabled:     welcomeEnabled,
 		WelcomeMessage:     welcomeMessage,
+		AdminChatID:        adminChatID,
 	}
 }
 
@@ -128,6 +131,7 @@ func SystemConfigToTelegramBotConfig(configs []entity.SystemConfig) dto.Telegram
 	proxyPassword := ""
 	welcomeEnabled := false
 	welcomeMessage := entity.ConfigDefaultTelegramWelcomeMessage
+	adminChatID := int64(0)
 
 	for _, config := range configs {
 		switch config.Key {
@@ -181,6 +185,10 @@ func SystemConfigToTelegramBotConfig(configs []entity.SystemConfig) dto.Telegram
 			if config.Value != "" {
 				welcomeMessage = config.Value
 			}
+		case entity.ConfigKeyTelegramAdminChatID:
+			if val, err := strconv.ParseInt(config.Value, 10, 64); err == nil {
+				adminChatID = val
+			}
 		}
 	}
 
@@ -200,6 +208,7 @@ func SystemConfigToTelegramBotConfig(configs []entity.SystemConfig) dto.Telegram
 		proxyPassword,
 		welcomeEnabled,
 		welcomeMessage,
+		adminChatID,
 	)
 }
 
@@ -338,6 +347,14 @@ func TelegramBotConfigRequestToSystemConfigs(req dto.TelegramBotConfigRequest) [
 		})
 	}
 
+	if req.AdminChatID != nil {
+		configs = append(configs, entity.SystemConfig{
+			Key:   entity.ConfigKeyTelegramAdminChatID,
+			Value: strconv.FormatInt(*req.AdminChatID, 10),
+			Type:  entity.ConfigTypeInt,
+		})
+	}
+
 	utils.Debug("[TELEGRAM:CONVERTER] 转换完成，共生成 %d 个配置项", len(configs))
 	for i, config := range configs {
 		if strings.Contains(config.Key, "proxy") {
@@ -27,3 +27,20 @@ func ToUserResponseList(users []entity.User) []dto.UserResponse {
 	}
 	return responses
 }
+
+// ToUserSessionResponseList 将会话列表转换为响应，标记当前请求所属会话
+func ToUserSessionResponseList(sessions []entity.UserSession, currentSessionID string) []dto.UserSessionResponse {
+	responses := make([]dto.UserSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = dto.UserSessionResponse{
+			SessionID:  session.SessionID,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+			Current:    session.SessionID == currentSessionID,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,16 @@
+package dto
+
+// AnalyticsConfigRequest 统计日汇总配置请求
+type AnalyticsConfigRequest struct {
+	RollupEnabled       bool `json:"rollup_enabled"`
+	ViewRetentionDays   int  `json:"view_retention_days" validate:"min=0,max=3650"`
+	SearchRetentionDays int  `json:"search_retention_days" validate:"min=0,max=3650"`
+	APILogRetentionDays int  `json:"api_log_retention_days" validate:"min=0,max=3650"`
+}
+
+// AnalyticsBackfillRequest 补汇总请求，日期格式 YYYY-MM-DD
+type AnalyticsBackfillRequest struct {
+	From  string `json:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `json:"to" validate:"omitempty,datetime=2006-01-02"`
+	Force bool   `json:"force"`
+}
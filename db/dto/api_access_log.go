@@ -15,6 +15,7 @@ type APIAccessLogResponse struct {
 	ProcessCount   int       `json:"process_count"`
 	ErrorMessage   string    `json:"error_message"`
 	ProcessingTime int64     `json:"processing_time"`
+	APIKeyID       uint      `json:"api_key_id"`
 	CreatedAt      time.Time `json:"created_at"`
 }
 
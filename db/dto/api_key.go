@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// APIKeyRequest 签发或更新公开API密钥请求
+type APIKeyRequest struct {
+	Name       string     `json:"name" validate:"required,max=100"`
+	Scopes     []string   `json:"scopes" validate:"required,min=1,dive,oneof=search batch_add hot_dramas"`
+	RateLimit  int        `json:"rate_limit" validate:"min=0,max=100000"`
+	DailyQuota int        `json:"daily_quota" validate:"min=0"`
+	AllowedIPs []string   `json:"allowed_ips" validate:"dive,ip|cidr"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Note       string     `json:"note" validate:"max=255"`
+}
+
+// APIKeyResponse 公开API密钥响应，不包含密钥明文
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rate_limit"`
+	DailyQuota int        `json:"daily_quota"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	UsageCount int64      `json:"usage_count"`
+	Status     string     `json:"status"`
+	Note       string     `json:"note"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// APIKeySecretResponse 签发或轮换后返回的密钥，明文只返回这一次
+type APIKeySecretResponse struct {
+	Key   APIKeyResponse `json:"key"`
+	Token string         `json:"token"`
+}
+
+// APIKeyUsageResponse 公开API密钥用量
+type APIKeyUsageResponse struct {
+	Days          int                   `json:"days"`
+	TotalRequests int64                 `json:"total_requests"`
+	TotalRejected int64                 `json:"total_rejected"`
+	TodayRequests int64                 `json:"today_requests"`
+	Daily         []APIKeyDailyUsageDTO `json:"daily"`
+}
+
+// APIKeyDailyUsageDTO 公开API密钥单日用量
+type APIKeyDailyUsageDTO struct {
+	Date     string `json:"date"`
+	Requests int64  `json:"requests"`
+	Rejected int64  `json:"rejected"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// AuditLogListRequest 审计日志查询请求，日期格式 YYYY-MM-DD，结束日期包含当天
+type AuditLogListRequest struct {
+	Page       int    `form:"page" validate:"min=1"`
+	PageSize   int    `form:"page_size" validate:"min=1,max=100"`
+	UserID     uint   `form:"user_id"`
+	Username   string `form:"username"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	IP         string `form:"ip"`
+	Success    *bool  `form:"success"`
+	StartDate  string `form:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate    string `form:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// AuditLogConfigRequest 审计日志配置请求
+type AuditLogConfigRequest struct {
+	RetentionDays int `json:"retention_days" validate:"min=0,max=3650"`
+}
+
+// AuditLogConfigResponse 审计日志配置响应
+type AuditLogConfigResponse struct {
+	RetentionDays int `json:"retention_days"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// BackupConfigRequest 备份配置请求
+type BackupConfigRequest struct {
+	Enabled        bool   `json:"enabled"`
+	IntervalHours  int    `json:"interval_hours" validate:"min=1,max=720"`
+	KeepLast       int    `json:"keep_last" validate:"min=0,max=1000"`
+	KeepDaily      int    `json:"keep_daily" validate:"min=0,max=1000"`
+	KeepWeekly     int    `json:"keep_weekly" validate:"min=0,max=1000"`
+	IncludeUploads bool   `json:"include_uploads"`
+	IncludePlugins bool   `json:"include_plugins"`
+	DBMode         string `json:"db_mode" validate:"oneof=auto pg_dump native"`
+}
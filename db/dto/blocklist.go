@@ -0,0 +1,53 @@
+package dto
+
+// BlocklistRuleListRequest 黑名单规则查询请求
+type BlocklistRuleListRequest struct {
+	Page     int    `form:"page" validate:"min=1"`
+	PageSize int    `form:"page_size" validate:"min=1,max=100"`
+	Kind     string `form:"kind" validate:"omitempty,oneof=url share_id title author domain"`
+	Action   string `form:"action" validate:"omitempty,oneof=reject hide mask"`
+	Source   string `form:"source" validate:"omitempty,oneof=manual moderation"`
+	Keyword  string `form:"keyword"`
+}
+
+// BlocklistRuleRequest 创建或更新黑名单规则请求
+type BlocklistRuleRequest struct {
+	Kind    string `json:"kind" validate:"required,oneof=url share_id title author domain"`
+	Pattern string `json:"pattern" validate:"required,max=500"` // 标题规则为正则表达式
+	Action  string `json:"action" validate:"required,oneof=reject hide mask"`
+	Enabled *bool  `json:"enabled"` // 为空时创建默认启用、更新保持不变
+	Note    string `json:"note" validate:"max=255"`
+}
+
+// BlocklistRuleResponse 黑名单规则响应
+type BlocklistRuleResponse struct {
+	ID        uint   `json:"id"`
+	Kind      string `json:"kind"`
+	Pattern   string `json:"pattern"`
+	Action    string `json:"action"`
+	Source    string `json:"source"`
+	SourceID  uint   `json:"source_id"`
+	Enabled   bool   `json:"enabled"`
+	Note      string `json:"note"`
+	HitCount  int64  `json:"hit_count"`
+	LastHitAt string `json:"last_hit_at,omitempty"`
+	CreatedBy uint   `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// BlocklistTestRequest 黑名单匹配测试请求
+type BlocklistTestRequest struct {
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+// BlocklistTestResponse 黑名单匹配测试结果
+type BlocklistTestResponse struct {
+	Matched     bool                     `json:"matched"`
+	Action      string                   `json:"action"`
+	Reason      string                   `json:"reason"`
+	MaskedTitle string                   `json:"masked_title"`
+	Rules       []*BlocklistRuleResponse `json:"rules"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+// ClassificationRuleRequest 自动分类规则创建/更新请求
+type ClassificationRuleRequest struct {
+	Name       string  `json:"name" validate:"omitempty,max=100"`
+	Target     string  `json:"target" validate:"required,oneof=category tag"`
+	CategoryID *uint   `json:"category_id"`
+	TagID      *uint   `json:"tag_id"`
+	MatchType  string  `json:"match_type" validate:"required,oneof=keyword regex"`
+	Pattern    string  `json:"pattern" validate:"required,max=2000"`
+	Fields     string  `json:"fields" validate:"omitempty,max=100"` // 逗号分隔 title/description/files
+	Confidence float64 `json:"confidence" validate:"omitempty,gt=0,lte=1"`
+	Enabled    *bool   `json:"enabled"`
+	Remark     string  `json:"remark" validate:"omitempty,max=255"`
+}
+
+// ClassificationTestRequest 分类测试请求
+type ClassificationTestRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Files       []string `json:"files"`
+	UseBayes    *bool    `json:"use_bayes"`
+}
+
+// ClassificationOptionsRequest 分类阈值与选项，未填写时使用默认值
+type ClassificationOptionsRequest struct {
+	AssignThreshold float64 `json:"assign_threshold" validate:"omitempty,gt=0,lte=1"`
+	ReviewThreshold float64 `json:"review_threshold" validate:"omitempty,gt=0,lte=1"`
+	UseBayes        *bool   `json:"use_bayes"`
+	Overwrite       bool    `json:"overwrite"`
+	FetchFiles      *bool   `json:"fetch_files"`
+}
+
+// ClassificationBatchRequest 批量分类未分类资源请求
+type ClassificationBatchRequest struct {
+	ClassificationOptionsRequest
+	Limit int `json:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// ClassificationReviewApproveRequest 审核采纳请求，为空时采纳建议值
+type ClassificationReviewApproveRequest struct {
+	CategoryID *uint  `json:"category_id"`
+	TagIDs     []uint `json:"tag_ids"`
+}
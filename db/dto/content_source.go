@@ -0,0 +1,19 @@
+package dto
+
+// ContentSourceRequest 内容源创建/更新请求
+type ContentSourceRequest struct {
+	Name            string `json:"name" validate:"required,max=100"`
+	Type            string `json:"type" validate:"required,oneof=rss telegram_export html json_api"`
+	URL             string `json:"url" validate:"required,max=1000"`
+	Config          string `json:"config"` // 采集配置JSON，见 pkg/crawler.Config
+	Category        string `json:"category" validate:"omitempty,max=100"`
+	Tags            string `json:"tags" validate:"omitempty,max=500"`
+	IntervalMinutes int    `json:"interval_minutes" validate:"omitempty,min=5,max=10080"`
+	Enabled         *bool  `json:"enabled"`
+}
+
+// ContentSourceListRequest 内容源列表请求
+type ContentSourceListRequest struct {
+	Page     int `form:"page" validate:"omitempty,min=1"`
+	PageSize int `form:"page_size" validate:"omitempty,min=1,max=100"`
+}
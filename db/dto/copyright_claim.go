@@ -17,6 +17,7 @@ type CopyrightClaimCreateRequest struct {
 type CopyrightClaimUpdateRequest struct {
 	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
 	Note   string `json:"note" validate:"omitempty,max=1000"`
+	ModerationFields
 }
 
 // CopyrightClaimResponse 版权申述响应
@@ -36,6 +37,8 @@ type CopyrightClaimResponse struct {
 	CreatedAt    string        `json:"created_at"`
 	UpdatedAt    string        `json:"updated_at"`
 	Resources    []ResourceInfo `json:"resources"`
+	// ModerationAction 本次处理新建的资源处置记录
+	ModerationAction *ModerationActionResponse `json:"moderation_action,omitempty"`
 }
 
 // CopyrightClaimListRequest 版权申述列表请求
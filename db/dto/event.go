@@ -0,0 +1,12 @@
+package dto
+
+// EventSubscriptionsRequest 实时事件订阅设置请求，为空表示订阅全部
+type EventSubscriptionsRequest struct {
+	Types []string `json:"types" validate:"max=12,dive,required,max=32"`
+}
+
+// EventSubscriptionsResponse 实时事件订阅设置
+type EventSubscriptionsResponse struct {
+	Types     []string `json:"types"`
+	Available []string `json:"available"`
+}
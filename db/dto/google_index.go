@@ -185,4 +185,9 @@ type GoogleIndexTaskItemPageResponse struct {
 	Total int64                       `json:"total"`
 	Page  int                         `json:"page"`
 	Size  int                         `json:"size"`
-}
\ No newline at end of file
+}
+
+// GoogleIndexResubmitRequest 重新提交资源详情页请求
+type GoogleIndexResubmitRequest struct {
+	ResourceIDs []uint `json:"resource_ids" validate:"required,min=1,max=200"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// MetadataConfigRequest 影视元数据配置更新请求
+type MetadataConfigRequest struct {
+	Enabled     bool    `json:"enabled"`
+	Provider    string  `json:"provider" validate:"required,oneof=douban tmdb auto"`
+	TMDBApiKey  *string `json:"tmdb_api_key"` // 为空时保留原值
+	RefreshDays int     `json:"refresh_days" validate:"omitempty,min=1,max=365"`
+	MinScore    float64 `json:"min_score" validate:"omitempty,gt=0,lte=1"`
+	BatchSize   int     `json:"batch_size" validate:"omitempty,min=1,max=200"`
+}
+
+// MetadataBindRequest 手动绑定影视条目请求
+type MetadataBindRequest struct {
+	Provider  string `json:"provider" validate:"required,oneof=douban tmdb"`
+	MediaType string `json:"media_type" validate:"required,oneof=movie tv"`
+	SubjectID string `json:"subject_id" validate:"required,max=50"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+// ModerationFields 处理举报或版权申述时对资源的处置选项，仅在状态为 approved 时生效
+type ModerationFields struct {
+	Action      string `json:"action" validate:"omitempty,oneof=hide delete"` // 版权申述通过时默认 hide
+	DeleteFiles bool   `json:"delete_files"`                                  // 同时删除转存到网盘账号中的文件，不可撤销
+	BlockTitle  bool   `json:"block_title"`                                   // 同时禁止相同标题重新入库
+}
+
+// ModerationActionListRequest 处置记录查询请求
+type ModerationActionListRequest struct {
+	Page        int    `form:"page" validate:"min=1"`
+	PageSize    int    `form:"page_size" validate:"min=1,max=100"`
+	SourceType  string `form:"source_type" validate:"omitempty,oneof=report copyright_claim"`
+	SourceID    uint   `form:"source_id"`
+	ResourceKey string `form:"resource_key"`
+}
+
+// ModerationActionResponse 处置记录响应
+type ModerationActionResponse struct {
+	ID           uint   `json:"id"`
+	SourceType   string `json:"source_type"`
+	SourceID     uint   `json:"source_id"`
+	ResourceKey  string `json:"resource_key"`
+	Action       string `json:"action"`
+	ResourceIDs  []uint `json:"resource_ids"`
+	BlockTitle   bool   `json:"block_title"`
+	DeleteFiles  bool   `json:"delete_files"`
+	FilesDeleted int    `json:"files_deleted"`
+	FileErrors   string `json:"file_errors"`
+	Note         string `json:"note"`
+	OperatorID   uint   `json:"operator_id"`
+	Reverted     bool   `json:"reverted"`
+	RevertedAt   string `json:"reverted_at,omitempty"`
+	RevertedBy   uint   `json:"reverted_by"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// ModerationConfigRequest 内容处置配置请求
+type ModerationConfigRequest struct {
+	EscalationThreshold int `json:"escalation_threshold" validate:"min=0,max=1000"`
+}
+
+// ModerationConfigResponse 内容处置配置响应
+type ModerationConfigResponse struct {
+	EscalationThreshold int `json:"escalation_threshold"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/ctwj/urldb/pkg/ogimage"
+
+// OGImageSettingsRequest OG图片设置更新请求
+type OGImageSettingsRequest struct {
+	ActiveTemplate   string `json:"active_template" validate:"required,max=50"`
+	PrerenderEnabled bool   `json:"prerender_enabled"`
+}
+
+// OGImagePreviewRequest OG图片模板预览请求，Key 为空时使用 Title/Description 作为示例内容
+type OGImagePreviewRequest struct {
+	Template    ogimage.Template `json:"template"`
+	Key         string           `json:"key" validate:"max=64"`
+	Title       string           `json:"title" validate:"max=200"`
+	Description string           `json:"description" validate:"max=500"`
+	Format      string           `json:"format" validate:"omitempty,oneof=png webp"`
+}
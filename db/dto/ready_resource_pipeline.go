@@ -0,0 +1,18 @@
+package dto
+
+import "encoding/json"
+
+// ReadyResourcePipelineRequest 待处理资源流水线配置请求
+type ReadyResourcePipelineRequest struct {
+	Stages json.RawMessage `json:"stages" validate:"required"` // 阶段配置数组，见 services.PipelineStageConfig
+	Remark string          `json:"remark" validate:"omitempty,max=255"`
+}
+
+// ReadyResourceStageLogListRequest 阶段记录列表请求
+type ReadyResourceStageLogListRequest struct {
+	Source   string `form:"source"`
+	Stage    string `form:"stage"`
+	Status   string `form:"status" validate:"omitempty,oneof=success failed warning skipped"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"page_size" validate:"omitempty,min=1,max=100"`
+}
@@ -12,8 +12,9 @@ type ReportCreateRequest struct {
 
 // ReportUpdateRequest 举报更新请求
 type ReportUpdateRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
+	Status string `json:"status" validate:"required,oneof=pending escalated approved rejected"`
 	Note   string `json:"note" validate:"omitempty,max=1000"`
+	ModerationFields
 }
 
 // ResourceInfo 资源信息
@@ -45,11 +46,13 @@ type ReportResponse struct {
 	CreatedAt   string         `json:"created_at"`
 	UpdatedAt   string         `json:"updated_at"`
 	Resources   []ResourceInfo `json:"resources"` // 关联的资源列表
+	// ModerationAction 本次处理新建的资源处置记录
+	ModerationAction *ModerationActionResponse `json:"moderation_action,omitempty"`
 }
 
 // ReportListRequest 举报列表请求
 type ReportListRequest struct {
 	Page     int    `query:"page" validate:"omitempty,min=1"`
 	PageSize int    `query:"page_size" validate:"omitempty,min=1,max=100"`
-	Status   string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Status   string `query:"status" validate:"omitempty,oneof=pending escalated approved rejected"`
 }
\ No newline at end of file
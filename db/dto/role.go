@@ -0,0 +1,24 @@
+package dto
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=20"`
+	DisplayName string   `json:"display_name" validate:"required,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest 更新角色请求，角色标识不可修改
+type UpdateRoleRequest struct {
+	DisplayName string   `json:"display_name" validate:"required,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionDenialListRequest 权限拒绝记录查询请求
+type PermissionDenialListRequest struct {
+	Page       int    `form:"page" validate:"min=1"`
+	PageSize   int    `form:"page_size" validate:"min=1,max=100"`
+	Username   string `form:"username"`
+	Permission string `form:"permission"`
+}
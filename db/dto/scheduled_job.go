@@ -0,0 +1,13 @@
+package dto
+
+// ScheduledJobRunListRequest 定时任务执行记录查询请求
+type ScheduledJobRunListRequest struct {
+	Page     int    `form:"page" validate:"min=1"`
+	PageSize int    `form:"page_size" validate:"min=1,max=100"`
+	Status   string `form:"status" validate:"omitempty,oneof=success failed skipped"`
+}
+
+// ScheduledJobScheduleRequest 修改定时任务执行计划请求，spec 为空时恢复默认
+type ScheduledJobScheduleRequest struct {
+	Spec string `json:"spec" validate:"max=100"`
+}
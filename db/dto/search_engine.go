@@ -0,0 +1,23 @@
+package dto
+
+// SearchEngineSettingRequest 单个搜索引擎的开关与每日配额
+type SearchEngineSettingRequest struct {
+	Enabled    bool `json:"enabled"`
+	DailyQuota int  `json:"daily_quota" validate:"min=0,max=100000"`
+}
+
+// SearchEngineConfigRequest 搜索引擎URL提交配置更新请求
+type SearchEngineConfigRequest struct {
+	AutoSubmit       bool                                  `json:"auto_submit"`
+	IntervalMinutes  int                                   `json:"interval_minutes" validate:"required,min=5,max=1440"`
+	MaxAttempts      int                                   `json:"max_attempts" validate:"required,min=1,max=10"`
+	Engines          map[string]SearchEngineSettingRequest `json:"engines" validate:"dive"`
+	IndexNowKey      string                                `json:"indexnow_key" validate:"omitempty,max=128"`
+	IndexNowEndpoint string                                `json:"indexnow_endpoint" validate:"omitempty,url"`
+	BaiduToken       *string                               `json:"baidu_token"` // 为空时保留原值
+}
+
+// SearchEngineSubmitRequest 手动提交URL请求
+type SearchEngineSubmitRequest struct {
+	URLs []string `json:"urls" validate:"required,min=1,max=500,dive,url"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// SEOConfigRequest 资源页SEO配置更新请求
+type SEOConfigRequest struct {
+	TitleTemplate       string   `json:"title_template" validate:"required,max=200"`
+	DescriptionTemplate string   `json:"description_template" validate:"max=500"`
+	Hreflang            []string `json:"hreflang" validate:"max=10"`
+}
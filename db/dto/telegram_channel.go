@@ -71,6 +71,7 @@ type TelegramBotConfigRequest struct {
 	ProxyPassword      *string `json:"proxy_password"`
 	WelcomeEnabled     *bool   `json:"welcome_enabled"`
 	WelcomeMessage     *string `json:"welcome_message"`
+	AdminChatID        *int64  `json:"admin_chat_id"` // 管理员通知会话ID，0 表示不通知
 }
 
 // TelegramBotConfigResponse Telegram 机器人配置响应
@@ -90,6 +91,7 @@ type TelegramBotConfigResponse struct {
 	ProxyPassword      string `json:"proxy_password"`
 	WelcomeEnabled     bool   `json:"welcome_enabled"`
 	WelcomeMessage     string `json:"welcome_message"`
+	AdminChatID        int64  `json:"admin_chat_id"`
 }
 
 // ValidateTelegramApiKeyRequest 验证 Telegram API Key 请求
@@ -109,3 +111,17 @@ type ValidateTelegramApiKeyResponse struct {
 	Error   string                 `json:"error,omitempty"`
 	BotInfo map[string]interface{} `json:"bot_info,omitempty"`
 }
+
+// TelegramImportChannelRequest Telegram 采集频道创建/更新请求
+type TelegramImportChannelRequest struct {
+	ChatID          int64  `json:"chat_id" validate:"required"`
+	ChatUsername    string `json:"chat_username" validate:"omitempty,max=100"`
+	ChatTitle       string `json:"chat_title" validate:"omitempty,max=255"`
+	Enabled         *bool  `json:"enabled"`
+	IncludeKeywords string `json:"include_keywords"`
+	ExcludeKeywords string `json:"exclude_keywords"`
+	Platforms       string `json:"platforms" validate:"omitempty,max=255"`
+	MinPostDate     string `json:"min_post_date"` // 格式 2006-01-02，为空不限制
+	Category        string `json:"category" validate:"omitempty,max=100"`
+	Tags            string `json:"tags" validate:"omitempty,max=500"`
+}
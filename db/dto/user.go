@@ -6,6 +6,8 @@ import "time"
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// TwoFactorCode 启用两步验证后需要：验证器中的 6 位验证码或一次性恢复码
+	TwoFactorCode string `json:"two_factor_code"`
 }
 
 // RegisterRequest 注册请求
@@ -47,10 +49,64 @@ type UserResponse struct {
 	LastLogin *time.Time `json:"last_login"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
+	// Permissions 当前用户的权限码，仅登录与个人资料接口返回
+	Permissions []string `json:"permissions,omitempty"`
 }
 
-// LoginResponse 登录响应
+// LoginResponse 登录响应，刷新令牌接口返回相同结构。
+// 密码正确但缺少两步验证码时仅返回 TwoFactorRequired，不签发令牌
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token             string       `json:"token,omitempty"`
+	RefreshToken      string       `json:"refresh_token,omitempty"`
+	ExpiresAt         time.Time    `json:"expires_at"`
+	User              UserResponse `json:"user"`
+	TwoFactorRequired bool         `json:"two_factor_required,omitempty"`
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// UserSessionResponse 登录会话响应
+type UserSessionResponse struct {
+	SessionID  string    `json:"session_id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"`
+}
+
+// TwoFactorStatusResponse 两步验证状态
+type TwoFactorStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse 两步验证初始化响应
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"`
+}
+
+// TwoFactorCodeRequest 两步验证码请求，可为 6 位验证码或恢复码（启用时仅接受验证码）
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse 恢复码响应，明文仅返回这一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginHistoryListRequest 登录记录查询请求
+type LoginHistoryListRequest struct {
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+	Username string `form:"username"`
+	IP       string `form:"ip"`
+	Success  *bool  `form:"success"`
 }
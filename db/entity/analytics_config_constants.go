@@ -0,0 +1,17 @@
+package entity
+
+// AnalyticsConfigKeys 统计日汇总配置键常量
+const (
+	AnalyticsConfigKeyRollupEnabled       = "analytics_rollup_enabled"         // 是否每日汇总统计数据并清理过期原始记录
+	AnalyticsConfigKeyViewRetentionDays   = "analytics_view_retention_days"    // resource_views 原始记录保留天数，0 表示不清理
+	AnalyticsConfigKeySearchRetentionDays = "analytics_search_retention_days"  // search_stats 原始记录保留天数，0 表示不清理
+	AnalyticsConfigKeyAPILogRetentionDays = "analytics_api_log_retention_days" // api_access_logs 原始记录保留天数，0 表示不清理
+)
+
+// 统计日汇总配置默认值
+const (
+	AnalyticsConfigDefaultRollupEnabled       = true
+	AnalyticsConfigDefaultViewRetentionDays   = 90
+	AnalyticsConfigDefaultSearchRetentionDays = 90
+	AnalyticsConfigDefaultAPILogRetentionDays = 30
+)
@@ -0,0 +1,100 @@
+package entity
+
+import "time"
+
+// ViewDailyStat 资源访问日汇总（日期 × 资源 × 来源），由 resource_views 汇总生成
+type ViewDailyStat struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Date           time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_view_daily_unique,priority:1;comment:统计日期"`
+	ResourceID     uint      `json:"resource_id" gorm:"not null;uniqueIndex:idx_view_daily_unique,priority:2;index;comment:资源ID"`
+	Source         string    `json:"source" gorm:"size:32;not null;default:'';uniqueIndex:idx_view_daily_unique,priority:3;comment:访问来源"`
+	CategoryID     *uint     `json:"category_id" gorm:"index;comment:分类ID（汇总时的快照）"`
+	PanID          *uint     `json:"pan_id" gorm:"index;comment:网盘ID（汇总时的快照）"`
+	Views          int64     `json:"views" gorm:"not null;default:0;comment:访问次数"`
+	UniqueVisitors int64     `json:"unique_visitors" gorm:"not null;default:0;comment:独立IP数"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ViewDailyStat) TableName() string {
+	return "view_daily_stats"
+}
+
+// SearchDailyStat 搜索日汇总（日期 × 关键词 × 来源），由 search_stats 汇总生成
+type SearchDailyStat struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_search_daily_unique,priority:1;comment:统计日期"`
+	Keyword   string    `json:"keyword" gorm:"size:255;not null;uniqueIndex:idx_search_daily_unique,priority:2;index;comment:搜索关键词"`
+	Source    string    `json:"source" gorm:"size:32;not null;default:'';uniqueIndex:idx_search_daily_unique,priority:3;comment:搜索来源"`
+	Searches  int64     `json:"searches" gorm:"not null;default:0;comment:搜索次数"`
+	UniqueIPs int64     `json:"unique_ips" gorm:"not null;default:0;comment:独立IP数"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (SearchDailyStat) TableName() string {
+	return "search_daily_stats"
+}
+
+// APIAccessDailyStat 公开API访问日汇总（日期 × 接口 × 方法），由 api_access_logs 汇总生成
+type APIAccessDailyStat struct {
+	ID                  uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Date                time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_api_daily_unique,priority:1;comment:统计日期"`
+	Endpoint            string    `json:"endpoint" gorm:"size:255;not null;uniqueIndex:idx_api_daily_unique,priority:2;comment:接口路径"`
+	Method              string    `json:"method" gorm:"size:10;not null;uniqueIndex:idx_api_daily_unique,priority:3;comment:HTTP方法"`
+	Requests            int64     `json:"requests" gorm:"not null;default:0;comment:请求次数"`
+	Errors              int64     `json:"errors" gorm:"not null;default:0;comment:错误次数（状态码>=400）"`
+	TotalProcessingTime int64     `json:"total_processing_time" gorm:"not null;default:0;comment:处理时间合计(毫秒)"`
+	UniqueIPs           int64     `json:"unique_ips" gorm:"not null;default:0;comment:独立IP数"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (APIAccessDailyStat) TableName() string {
+	return "api_access_daily_stats"
+}
+
+// AnalyticsRollupDay 已完成汇总的日期，记录当日总量，便于核对与判断是否可清理原始数据
+type AnalyticsRollupDay struct {
+	Date            time.Time `json:"date" gorm:"type:date;primaryKey;comment:统计日期"`
+	Views           int64     `json:"views" gorm:"not null;default:0;comment:访问次数"`
+	UniqueVisitors  int64     `json:"unique_visitors" gorm:"not null;default:0;comment:全站独立访问IP数"`
+	Searches        int64     `json:"searches" gorm:"not null;default:0;comment:搜索次数"`
+	UniqueSearchers int64     `json:"unique_searchers" gorm:"not null;default:0;comment:全站独立搜索IP数"`
+	APIRequests     int64     `json:"api_requests" gorm:"not null;default:0;comment:API请求次数"`
+	APIErrors       int64     `json:"api_errors" gorm:"not null;default:0;comment:API错误次数"`
+	RolledAt        time.Time `json:"rolled_at" gorm:"not null;comment:最近一次汇总时间"`
+
+	// 原始数据清理时间，清理后重新汇总会保留该部分已有的汇总结果
+	ViewsPurgedAt    *time.Time `json:"views_purged_at" gorm:"comment:resource_views 清理时间"`
+	SearchesPurgedAt *time.Time `json:"searches_purged_at" gorm:"comment:search_stats 清理时间"`
+	APILogsPurgedAt  *time.Time `json:"api_logs_purged_at" gorm:"comment:api_access_logs 清理时间"`
+}
+
+// 可清理的原始数据表
+const (
+	AnalyticsRawViews    = "resource_views"
+	AnalyticsRawSearches = "search_stats"
+	AnalyticsRawAPILogs  = "api_access_logs"
+)
+
+// TableName 指定表名
+func (AnalyticsRollupDay) TableName() string {
+	return "analytics_rollup_days"
+}
+
+// AnalyticsSeriesPoint 趋势数据点，Key 为分组维度取值（不分组时为空）
+type AnalyticsSeriesPoint struct {
+	Date  string `json:"date"`
+	Key   string `json:"key,omitempty"`
+	Value int64  `json:"value"`
+}
+
+// AnalyticsLeaderboardItem 排行榜条目
+type AnalyticsLeaderboardItem struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Value  int64  `json:"value"`
+	Unique int64  `json:"unique"`
+	Errors int64  `json:"errors,omitempty"`
+}
@@ -19,6 +19,7 @@ type APIAccessLog struct {
 	ProcessCount   int            `json:"process_count" gorm:"default:0;comment:处理数量(查询结果数或添加的数量)"`
 	ErrorMessage   string         `json:"error_message" gorm:"size:500;comment:错误消息"`
 	ProcessingTime int64          `json:"processing_time" gorm:"comment:处理时间(毫秒)"`
+	APIKeyID       uint           `json:"api_key_id" gorm:"index;default:0;comment:公开API密钥ID，0表示旧版全局Token或未识别"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"index"`
@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// 公开API作用域
+const (
+	APIScopeSearch    = "search"
+	APIScopeBatchAdd  = "batch_add"
+	APIScopeHotDramas = "hot_dramas"
+)
+
+// APIScopes 全部公开API作用域
+var APIScopes = []string{APIScopeSearch, APIScopeBatchAdd, APIScopeHotDramas}
+
+// APIKey 公开API密钥，只保存密钥的 SHA-256，明文仅在签发与轮换时返回一次
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string     `json:"name" gorm:"size:100;not null;comment:名称，如合作站点或机器人"`
+	Prefix     string     `json:"prefix" gorm:"size:20;not null;uniqueIndex;comment:密钥前缀，用于识别与查找"`
+	KeyHash    string     `json:"-" gorm:"size:64;not null;comment:密钥SHA-256"`
+	Scopes     string     `json:"scopes" gorm:"size:255;not null;comment:作用域，逗号分隔"`
+	RateLimit  int        `json:"rate_limit" gorm:"default:0;comment:每分钟请求数上限，0表示不限"`
+	DailyQuota int        `json:"daily_quota" gorm:"default:0;comment:每日请求数上限，0表示不限"`
+	AllowedIPs string     `json:"allowed_ips" gorm:"size:1000;default:'';comment:IP白名单（IP或CIDR，逗号分隔），为空表示不限"`
+	ExpiresAt  *time.Time `json:"expires_at" gorm:"comment:过期时间，为空表示永不过期"`
+	RevokedAt  *time.Time `json:"revoked_at" gorm:"comment:吊销时间"`
+	LastUsedAt *time.Time `json:"last_used_at" gorm:"comment:最后使用时间"`
+	LastUsedIP string     `json:"last_used_ip" gorm:"size:45;comment:最后使用IP"`
+	UsageCount int64      `json:"usage_count" gorm:"default:0;comment:累计请求数"`
+	Note       string     `json:"note" gorm:"size:255;comment:备注"`
+	CreatedBy  string     `json:"created_by" gorm:"size:50;comment:签发人"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList 返回作用域列表
+func (k *APIKey) ScopeList() []string {
+	return splitList(k.Scopes)
+}
+
+// HasScope 判断是否拥有作用域
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedIPList 返回IP白名单列表
+func (k *APIKey) AllowedIPList() []string {
+	return splitList(k.AllowedIPs)
+}
+
+// IPAllowed 判断IP是否在白名单内，白名单为空时允许所有IP
+func (k *APIKey) IPAllowed(ip string) bool {
+	entries := k.AllowedIPList()
+	if len(entries) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status 返回密钥状态：active、expired 或 revoked
+func (k *APIKey) Status(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return "revoked"
+	case k.ExpiresAt != nil && !now.Before(*k.ExpiresAt):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// APIKeyDailyUsage 公开API密钥每日用量，用于每日配额与用量统计
+type APIKeyDailyUsage struct {
+	ID       uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	APIKeyID uint      `json:"api_key_id" gorm:"not null;uniqueIndex:idx_api_key_usage_day,priority:1;comment:密钥ID"`
+	Date     time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_api_key_usage_day,priority:2;comment:日期"`
+	Requests int64     `json:"requests" gorm:"not null;default:0;comment:放行请求数"`
+	Rejected int64     `json:"rejected" gorm:"not null;default:0;comment:被拒绝请求数（作用域、IP、限流、配额）"`
+}
+
+// TableName 指定表名
+func (APIKeyDailyUsage) TableName() string {
+	return "api_key_daily_usages"
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// 审计日志配置键
+const (
+	AuditLogConfigKeyRetentionDays = "audit_log_retention_days" // 审计日志保留天数，0 表示永久保留
+)
+
+// 审计日志配置默认值
+const (
+	AuditLogConfigDefaultRetentionDays = 365
+)
+
+// AuditLog 后台操作审计日志，记录操作人、操作、对象、修改前后差异（敏感字段已脱敏）与来源IP
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint      `json:"user_id" gorm:"index;comment:操作人ID"`
+	Username   string    `json:"username" gorm:"size:50;index;comment:操作人用户名"`
+	Role       string    `json:"role" gorm:"size:32;comment:操作人角色"`
+	Action     string    `json:"action" gorm:"size:100;index;comment:操作，如 cks.update"`
+	TargetType string    `json:"target_type" gorm:"size:50;index;comment:对象类型"`
+	TargetID   string    `json:"target_id" gorm:"size:100;index;comment:对象ID"`
+	Method     string    `json:"method" gorm:"size:10;comment:HTTP方法"`
+	Path       string    `json:"path" gorm:"size:255;comment:请求路径"`
+	StatusCode int       `json:"status_code" gorm:"comment:响应状态码"`
+	Success    bool      `json:"success" gorm:"index;comment:是否成功"`
+	IP         string    `json:"ip" gorm:"size:64;index;comment:来源IP"`
+	UserAgent  string    `json:"user_agent" gorm:"size:255;comment:客户端UA"`
+	Changes    string    `json:"changes" gorm:"type:text;comment:修改前后差异JSON，字段名到 {old,new}"`
+	Detail     string    `json:"detail" gorm:"type:text;comment:补充说明"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogFilter 审计日志查询条件
+type AuditLogFilter struct {
+	UserID     uint
+	Username   string
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string
+	Success    *bool
+	StartTime  *time.Time
+	EndTime    *time.Time
+}
@@ -0,0 +1,25 @@
+package entity
+
+// BackupConfigKeys 备份配置键常量
+const (
+	BackupConfigKeyEnabled        = "backup_enabled"         // 是否启用定时备份
+	BackupConfigKeyIntervalHours  = "backup_interval_hours"  // 定时备份间隔（小时）
+	BackupConfigKeyKeepLast       = "backup_keep_last"       // 保留最近 N 个备份
+	BackupConfigKeyKeepDaily      = "backup_keep_daily"      // 保留最近 N 天每天最后一个备份
+	BackupConfigKeyKeepWeekly     = "backup_keep_weekly"     // 保留最近 N 周每周最后一个备份
+	BackupConfigKeyIncludeUploads = "backup_include_uploads" // 是否备份上传目录
+	BackupConfigKeyIncludePlugins = "backup_include_plugins" // 是否备份插件目录
+	BackupConfigKeyDBMode         = "backup_db_mode"         // 数据库导出方式 auto/pg_dump/native
+)
+
+// 备份配置默认值
+const (
+	BackupConfigDefaultEnabled        = false
+	BackupConfigDefaultIntervalHours  = 24
+	BackupConfigDefaultKeepLast       = 7
+	BackupConfigDefaultKeepDaily      = 7
+	BackupConfigDefaultKeepWeekly     = 4
+	BackupConfigDefaultIncludeUploads = true
+	BackupConfigDefaultIncludePlugins = true
+	BackupConfigDefaultDBMode         = "auto"
+)
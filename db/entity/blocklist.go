@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// 黑名单规则匹配类型
+const (
+	BlocklistKindURL     = "url"      // 分享链接，完全匹配
+	BlocklistKindShareID = "share_id" // 网盘分享ID，同一分享的不同链接形式都会命中
+	BlocklistKindTitle   = "title"    // 标题正则，不区分大小写
+	BlocklistKindAuthor  = "author"   // 上传者/作者，不区分大小写完全匹配
+	BlocklistKindDomain  = "domain"   // 链接域名，包含子域名
+)
+
+// 黑名单规则命中后的处理方式，优先级 reject > hide > mask
+const (
+	BlocklistActionReject = "reject" // 拒绝入库，搜索与推送中不出现
+	BlocklistActionHide   = "hide"   // 入库但不公开，搜索与推送中不出现
+	BlocklistActionMask   = "mask"   // 标题中命中的部分替换为 *，仅适用于标题规则
+)
+
+// 黑名单规则来源
+const (
+	BlocklistSourceManual     = "manual"     // 管理员手动添加
+	BlocklistSourceModeration = "moderation" // 举报/版权申述处置自动生成，撤销处置时删除
+)
+
+// BlocklistRule 黑名单规则，在入库、搜索、机器人与频道推送中统一生效
+type BlocklistRule struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind      string     `json:"kind" gorm:"size:20;not null;index;comment:匹配类型 url/share_id/title/author/domain"`
+	Pattern   string     `json:"pattern" gorm:"size:500;not null;comment:匹配内容，标题规则为正则表达式"`
+	Action    string     `json:"action" gorm:"size:20;not null;comment:处理方式 reject/hide/mask"`
+	Source    string     `json:"source" gorm:"size:20;not null;default:'manual';index:idx_blocklist_source;comment:来源 manual/moderation"`
+	SourceID  uint       `json:"source_id" gorm:"default:0;index:idx_blocklist_source;comment:来源ID，处置生成的规则为处置记录ID"`
+	Enabled   bool       `json:"enabled" gorm:"index;comment:是否启用"`
+	Note      string     `json:"note" gorm:"size:255;comment:备注"`
+	HitCount  int64      `json:"hit_count" gorm:"default:0;comment:入库时命中次数"`
+	LastHitAt *time.Time `json:"last_hit_at" gorm:"comment:最近命中时间"`
+	CreatedBy uint       `json:"created_by" gorm:"default:0;comment:创建人ID"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (BlocklistRule) TableName() string {
+	return "blocklist_rules"
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 分类审核状态
+const (
+	ClassificationReviewPending  = "pending"  // 待审核
+	ClassificationReviewApproved = "approved" // 已采纳
+	ClassificationReviewRejected = "rejected" // 已驳回
+)
+
+// ClassificationRule 自动分类/打标签规则
+type ClassificationRule struct {
+	ID         uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string         `json:"name" gorm:"size:100;comment:规则名称"`
+	Target     string         `json:"target" gorm:"size:20;not null;comment:规则目标 category/tag"`
+	CategoryID *uint          `json:"category_id" gorm:"index;comment:目标分类ID"`
+	TagID      *uint          `json:"tag_id" gorm:"index;comment:目标标签ID"`
+	MatchType  string         `json:"match_type" gorm:"size:20;not null;comment:匹配方式 keyword/regex"`
+	Pattern    string         `json:"pattern" gorm:"type:text;not null;comment:关键词或正则表达式"`
+	Fields     string         `json:"fields" gorm:"size:100;comment:匹配字段，逗号分隔 title/description/files，为空表示全部"`
+	Confidence float64        `json:"confidence" gorm:"default:0.8;comment:命中置信度"`
+	Enabled    bool           `json:"enabled" gorm:"default:true;comment:是否启用"`
+	HitCount   int64          `json:"hit_count" gorm:"default:0;comment:命中次数"`
+	Remark     string         `json:"remark" gorm:"size:255;comment:备注"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// 关联关系
+	Category *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Tag      *Tag      `json:"tag,omitempty" gorm:"foreignKey:TagID"`
+}
+
+// TableName 指定表名
+func (ClassificationRule) TableName() string {
+	return "classification_rules"
+}
+
+// ClassificationReview 低置信度分类建议的审核队列
+type ClassificationReview struct {
+	ID                 uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ResourceID         uint       `json:"resource_id" gorm:"not null;index;comment:资源ID"`
+	ResourceTitle      string     `json:"resource_title" gorm:"size:255;comment:资源标题"`
+	CategoryID         *uint      `json:"category_id" gorm:"comment:建议分类ID"`
+	CategoryName       string     `json:"category_name" gorm:"size:100;comment:建议分类名称"`
+	CategoryConfidence float64    `json:"category_confidence" gorm:"comment:分类置信度"`
+	TagIDs             string     `json:"tag_ids" gorm:"size:500;comment:建议标签ID，逗号分隔"`
+	TagNames           string     `json:"tag_names" gorm:"size:500;comment:建议标签名称，逗号分隔"`
+	Suggestions        string     `json:"suggestions" gorm:"type:text;comment:完整建议JSON"`
+	Origin             string     `json:"origin" gorm:"size:20;comment:产生方式 pipeline/manual/batch"`
+	Status             string     `json:"status" gorm:"size:20;not null;default:pending;index;comment:审核状态"`
+	ReviewedAt         *time.Time `json:"reviewed_at" gorm:"comment:审核时间"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ClassificationReview) TableName() string {
+	return "classification_reviews"
+}
+
+// ClassifierModel 本地分类模型（朴素贝叶斯），Name 唯一
+type ClassifierModel struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"size:50;not null;uniqueIndex;comment:模型名称"`
+	Model     string    `json:"-" gorm:"type:text;comment:模型数据JSON"`
+	Samples   int       `json:"samples" gorm:"comment:训练样本数"`
+	Labels    int       `json:"labels" gorm:"comment:类别数"`
+	VocabSize int       `json:"vocab_size" gorm:"comment:特征数"`
+	TrainedAt time.Time `json:"trained_at" gorm:"comment:训练时间"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ClassifierModel) TableName() string {
+	return "classifier_models"
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ContentSource 内容源（声明式采集配置）
+// Type 取值见 SourceRSS / SourceTelegramExport / SourceHTML / SourceJSONAPI，
+// Config 为 JSON，保存选择器与字段映射等类型相关配置（见 pkg/crawler.Config）。
+type ContentSource struct {
+	ID               uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name             string         `json:"name" gorm:"size:100;not null;comment:内容源名称"`
+	Type             string         `json:"type" gorm:"size:50;not null;comment:内容源类型"`
+	URL              string         `json:"url" gorm:"size:1000;not null;comment:抓取地址"`
+	Config           string         `json:"config" gorm:"type:text;comment:采集配置JSON"`
+	Category         string         `json:"category" gorm:"size:100;comment:默认分类"`
+	Tags             string         `json:"tags" gorm:"size:500;comment:默认标签，多个标签用逗号分隔"`
+	IntervalMinutes  int            `json:"interval_minutes" gorm:"default:60;comment:抓取间隔（分钟）"`
+	Enabled          bool           `json:"enabled" gorm:"default:true;comment:是否启用"`
+	Cursor           string         `json:"cursor" gorm:"size:100;comment:抓取游标（已处理的最新发布时间）"`
+	LastRunAt        *time.Time     `json:"last_run_at" gorm:"comment:最近抓取时间"`
+	LastError        string         `json:"last_error" gorm:"type:text;comment:最近一次抓取错误"`
+	LastFetchedCount int            `json:"last_fetched_count" gorm:"default:0;comment:最近一次抓取条目数"`
+	LastCreatedCount int            `json:"last_created_count" gorm:"default:0;comment:最近一次新增待处理资源数"`
+	TotalCreated     int64          `json:"total_created" gorm:"default:0;comment:累计新增待处理资源数"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (ContentSource) TableName() string {
+	return "content_sources"
+}
+
+// ContentSourceItem 内容源已处理条目（按源去重）
+type ContentSourceItem struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SourceID  uint      `json:"source_id" gorm:"not null;uniqueIndex:idx_content_source_item_hash;comment:内容源ID"`
+	ItemHash  string    `json:"item_hash" gorm:"size:64;not null;uniqueIndex:idx_content_source_item_hash;comment:条目去重哈希"`
+	Title     string    `json:"title" gorm:"size:255;comment:条目标题"`
+	ReadyKey  string    `json:"ready_key" gorm:"size:64;comment:生成的待处理资源组key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ContentSourceItem) TableName() string {
+	return "content_source_items"
+}
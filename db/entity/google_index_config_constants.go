@@ -29,11 +29,23 @@ const (
 	GoogleIndexConfigKeySitemapSchedule   = "google_index_sitemap_schedule"    // 网站地图调度
 )
 
+// Google索引覆盖率配置
+const (
+	GoogleIndexConfigKeyInspectQuota  = "google_index_inspect_quota"  // 每日URL检查配额（URL Inspection API 每站点每日2000次）
+	GoogleIndexConfigKeyStaleDays     = "google_index_stale_days"     // 检查结果超过该天数后重新检查
+	GoogleIndexConfigKeyAutoResubmit  = "google_index_auto_resubmit"  // 已抓取未索引的页面更新后自动重新提交
+	GoogleIndexConfigKeyResubmitLimit = "google_index_resubmit_limit" // 每轮最多重新提交的URL数
+)
+
 // Google索引配置默认值
 const (
 	GoogleIndexConfigDefaultCheckInterval = 60
 	GoogleIndexConfigDefaultBatchSize    = 100
 	GoogleIndexConfigDefaultConcurrency  = 5
+
+	GoogleIndexConfigDefaultInspectQuota  = 1500
+	GoogleIndexConfigDefaultStaleDays     = 14
+	GoogleIndexConfigDefaultResubmitLimit = 50
 )
 
 // BingIndexConfigKeys Bing索引配置键常量
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// Google URL 检查结果中的常用取值
+const (
+	GoogleIndexVerdictPass               = "PASS"                            // 已被索引
+	GoogleIndexCoverageCrawledNotIndexed = "Crawled - currently not indexed" // 已抓取但未编入索引
+)
+
+// GoogleIndexStatus 资源详情页在 Google 的索引状态，每个资源一条记录
+type GoogleIndexStatus struct {
+	ID             uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ResourceID     uint       `json:"resource_id" gorm:"not null;uniqueIndex;comment:资源ID"`
+	URL            string     `json:"url" gorm:"size:500;not null;index;comment:资源详情页地址"`
+	Verdict        string     `json:"verdict" gorm:"size:30;index;comment:检查结论 PASS/NEUTRAL/FAIL"`
+	CoverageState  string     `json:"coverage_state" gorm:"size:100;index;comment:覆盖状态"`
+	IndexingState  string     `json:"indexing_state" gorm:"size:50;comment:是否允许索引"`
+	PageFetchState string     `json:"page_fetch_state" gorm:"size:50;comment:页面抓取状态"`
+	LastCrawledAt  *time.Time `json:"last_crawled_at" gorm:"comment:Google最后抓取时间"`
+	InspectedAt    *time.Time `json:"inspected_at" gorm:"index;comment:最近一次检查时间"`
+	ErrorMessage   string     `json:"error_message" gorm:"size:500;comment:最近一次检查的错误"`
+	ResubmittedAt  *time.Time `json:"resubmitted_at" gorm:"comment:最近一次重新提交时间"`
+	ResubmitCount  int        `json:"resubmit_count" gorm:"default:0;comment:重新提交次数"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (GoogleIndexStatus) TableName() string {
+	return "google_index_statuses"
+}
+
+// GoogleIndexInspection 一次 URL 检查的结果，按 URL 写入所有共用该详情页的资源记录
+type GoogleIndexInspection struct {
+	Verdict        string
+	CoverageState  string
+	IndexingState  string
+	PageFetchState string
+	LastCrawledAt  *time.Time
+	InspectedAt    time.Time
+	ErrorMessage   string
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// 登录失败原因
+const (
+	LoginFailUserNotFound = "user_not_found"
+	LoginFailBadPassword  = "bad_password"
+	LoginFailDisabled     = "disabled"
+	LoginFailBadTwoFactor = "bad_two_factor"
+	LoginFailThrottled    = "throttled"
+)
+
+// UserTwoFactor 用户两步验证设置，Secret 在启用前为待确认的新密钥
+type UserTwoFactor struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        uint       `json:"user_id" gorm:"not null;uniqueIndex;comment:用户ID"`
+	Secret        string     `json:"-" gorm:"size:64;not null;comment:TOTP密钥(Base32)"`
+	Enabled       bool       `json:"enabled" gorm:"default:false;comment:是否已启用"`
+	EnabledAt     *time.Time `json:"enabled_at" gorm:"comment:启用时间"`
+	LastUsedStep  int64      `json:"-" gorm:"default:0;comment:最近一次通过校验的时间步，防止验证码重放"`
+	RecoveryCodes string     `json:"-" gorm:"type:text;comment:恢复码SHA-256，逗号分隔，使用后移除"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCodeHashes 未使用的恢复码哈希
+func (t *UserTwoFactor) RecoveryCodeHashes() []string {
+	return splitList(t.RecoveryCodes)
+}
+
+// SetRecoveryCodeHashes 设置恢复码哈希
+func (t *UserTwoFactor) SetRecoveryCodeHashes(hashes []string) {
+	t.RecoveryCodes = strings.Join(hashes, ",")
+}
+
+// LoginHistory 登录记录，同时作为登录失败限流的计数依据
+type LoginHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        uint      `json:"user_id" gorm:"index;default:0;comment:用户ID，用户不存在时为0"`
+	Username      string    `json:"username" gorm:"size:50;index;comment:登录用户名"`
+	IP            string    `json:"ip" gorm:"size:64;index;comment:登录IP"`
+	UserAgent     string    `json:"user_agent" gorm:"size:255;comment:客户端UA"`
+	Success       bool      `json:"success" gorm:"index;comment:是否成功"`
+	FailureReason string    `json:"failure_reason" gorm:"size:32;default:'';comment:失败原因"`
+	TwoFactor     bool      `json:"two_factor" gorm:"default:false;comment:是否经过两步验证"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (LoginHistory) TableName() string {
+	return "login_histories"
+}
@@ -0,0 +1,19 @@
+package entity
+
+// MetadataConfigKeys 影视元数据配置键常量
+const (
+	MetadataConfigKeyEnabled     = "metadata_enabled"      // 是否启用定时补全与刷新
+	MetadataConfigKeyProvider    = "metadata_provider"     // 数据源 douban/tmdb/auto
+	MetadataConfigKeyTMDBApiKey  = "metadata_tmdb_api_key" // TMDB API Key
+	MetadataConfigKeyRefreshDays = "metadata_refresh_days" // 刷新周期（天）
+	MetadataConfigKeyMinScore    = "metadata_min_score"    // 自动匹配最低匹配度
+	MetadataConfigKeyBatchSize   = "metadata_batch_size"   // 每轮处理数量
+)
+
+// 影视元数据配置默认值
+const (
+	MetadataConfigDefaultProvider    = "douban"
+	MetadataConfigDefaultRefreshDays = 30
+	MetadataConfigDefaultMinScore    = 0.75
+	MetadataConfigDefaultBatchSize   = 20
+)
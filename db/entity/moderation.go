@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 举报与版权申述的处理状态
+const (
+	ModerationStatusPending   = "pending"   // 待处理
+	ModerationStatusEscalated = "escalated" // 同一资源举报过多，已升级为优先处理
+	ModerationStatusApproved  = "approved"  // 已通过
+	ModerationStatusRejected  = "rejected"  // 已驳回
+)
+
+// 处置来源
+const (
+	ModerationSourceReport         = "report"
+	ModerationSourceCopyrightClaim = "copyright_claim"
+)
+
+// 对资源的处置方式
+const (
+	ModerationActionHide   = "hide"   // 设为不公开
+	ModerationActionDelete = "delete" // 删除（软删除，可恢复）
+)
+
+// 内容处置配置键
+const (
+	ModerationConfigKeyEscalationThreshold     = "moderation_report_escalation_threshold" // 同一资源待处理举报达到该数量时自动升级，0 表示不升级
+	ModerationConfigDefaultEscalationThreshold = 3
+)
+
+// ModerationAction 一次对资源的处置记录，保存处置前的状态用于撤销
+type ModerationAction struct {
+	ID           uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	SourceType   string     `json:"source_type" gorm:"size:20;not null;index:idx_moderation_source;comment:来源类型 report/copyright_claim"`
+	SourceID     uint       `json:"source_id" gorm:"not null;index:idx_moderation_source;comment:举报或申述ID"`
+	ResourceKey  string     `json:"resource_key" gorm:"size:255;not null;index;comment:资源组标识"`
+	Action       string     `json:"action" gorm:"size:20;not null;comment:处置方式 hide/delete"`
+	ResourceIDs  string     `json:"resource_ids" gorm:"type:text;comment:被处置的资源ID，逗号分隔"`
+	HiddenIDs    string     `json:"-" gorm:"type:text;comment:处置前为公开状态、撤销时需恢复公开的资源ID"`
+	BlockTitle   bool       `json:"block_title" gorm:"default:false;comment:是否同时禁止相同标题重新入库"`
+	DeleteFiles  bool       `json:"delete_files" gorm:"default:false;comment:是否删除转存到网盘账号中的文件"`
+	FilesDeleted int        `json:"files_deleted" gorm:"default:0;comment:已删除的转存文件数"`
+	FileErrors   string     `json:"file_errors" gorm:"type:text;comment:删除转存文件失败信息"`
+	Note         string     `json:"note" gorm:"type:text;comment:处置备注"`
+	OperatorID   uint       `json:"operator_id" gorm:"default:0;comment:处置人ID"`
+	Reverted     bool       `json:"reverted" gorm:"default:false;index;comment:是否已撤销"`
+	RevertedAt   *time.Time `json:"reverted_at" gorm:"comment:撤销时间"`
+	RevertedBy   uint       `json:"reverted_by" gorm:"default:0;comment:撤销人ID"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ModerationAction) TableName() string {
+	return "moderation_actions"
+}
+
+// ResourceIDList 被处置的资源ID
+func (a *ModerationAction) ResourceIDList() []uint {
+	return parseUintList(a.ResourceIDs)
+}
+
+// HiddenIDList 撤销时需恢复公开的资源ID
+func (a *ModerationAction) HiddenIDList() []uint {
+	return parseUintList(a.HiddenIDs)
+}
+
+// SetResourceIDList 设置被处置的资源ID
+func (a *ModerationAction) SetResourceIDList(ids []uint) {
+	a.ResourceIDs = joinUintList(ids)
+}
+
+// SetHiddenIDList 设置撤销时需恢复公开的资源ID
+func (a *ModerationAction) SetHiddenIDList(ids []uint) {
+	a.HiddenIDs = joinUintList(ids)
+}
+
+func parseUintList(s string) []uint {
+	var ids []uint
+	for _, item := range splitList(s) {
+		if id, err := strconv.ParseUint(item, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+func joinUintList(ids []uint) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,16 @@
+package entity
+
+// OGImageConfigKeys OG图片配置键常量
+const (
+	OGImageConfigKeyTemplates        = "og_image_templates"         // 自定义模板（JSON数组），同名时覆盖内置主题
+	OGImageConfigKeyActiveTemplate   = "og_image_active_template"   // 资源页默认使用的模板名称
+	OGImageConfigKeyPrerenderEnabled = "og_image_prerender_enabled" // 是否为新增/更新的资源预生成图片
+	OGImageConfigKeyPrerenderCursor  = "og_image_prerender_cursor"  // 已预生成到的资源更新时间（内部使用）
+)
+
+// OG图片配置默认值
+const (
+	OGImageConfigDefaultTemplates        = "[]"
+	OGImageConfigDefaultActiveTemplate   = "default"
+	OGImageConfigDefaultPrerenderEnabled = true
+)
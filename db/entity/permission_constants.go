@@ -0,0 +1,133 @@
+package entity
+
+// 内置角色
+const (
+	RoleAdmin     = "admin"
+	RoleEditor    = "editor"
+	RoleModerator = "moderator"
+	RoleViewer    = "viewer"
+	RoleUser      = "user"
+)
+
+// 权限码，格式为 模块:操作，view 为只读，manage 包含读写
+const (
+	// 内容
+	PermissionResourceManage       = "resource:manage"
+	PermissionTaxonomyManage       = "taxonomy:manage"
+	PermissionReadyResourceView    = "ready_resource:view"
+	PermissionReadyResourceManage  = "ready_resource:manage"
+	PermissionContentSourceManage  = "content_source:manage"
+	PermissionClassificationManage = "classification:manage"
+	PermissionMetadataManage       = "metadata:manage"
+	PermissionHotDramaManage       = "hot_drama:manage"
+	PermissionTaskView             = "task:view"
+	PermissionTaskManage           = "task:manage"
+	PermissionFileUpload           = "file:upload"
+	PermissionFileManage           = "file:manage"
+
+	// 网盘
+	PermissionPanManage = "pan:manage"
+	PermissionCksManage = "cks:manage"
+
+	// 运营
+	PermissionStatsView         = "stats:view"
+	PermissionAnalyticsManage   = "analytics:manage"
+	PermissionReportView        = "report:view"
+	PermissionReportManage      = "report:manage"
+	PermissionSEOManage         = "seo:manage"
+	PermissionSearchIndexView   = "search_index:view"
+	PermissionSearchIndexManage = "search_index:manage"
+	PermissionBotManage         = "bot:manage"
+	PermissionEventSubscribe    = "event:subscribe"
+
+	// 系统
+	PermissionUserManage         = "user:manage"
+	PermissionRoleManage         = "role:manage"
+	PermissionSystemConfigManage = "system_config:manage"
+	PermissionLogView            = "log:view"
+	PermissionLogManage          = "log:manage"
+	PermissionPluginManage       = "plugin:manage"
+	PermissionBackupManage       = "backup:manage"
+	PermissionJobManage          = "job:manage"
+	PermissionAPIKeyManage       = "api_key:manage"
+	PermissionAuditView          = "audit:view"
+)
+
+// PermissionCatalog 全部权限定义，启动时同步到 permissions 表
+var PermissionCatalog = []Permission{
+	{Code: PermissionResourceManage, Name: "管理资源", Group: "内容"},
+	{Code: PermissionTaxonomyManage, Name: "管理分类与标签", Group: "内容"},
+	{Code: PermissionReadyResourceView, Name: "查看待处理资源", Group: "内容"},
+	{Code: PermissionReadyResourceManage, Name: "管理待处理资源与处理管线", Group: "内容"},
+	{Code: PermissionContentSourceManage, Name: "管理内容源与采集频道", Group: "内容"},
+	{Code: PermissionClassificationManage, Name: "管理自动分类", Group: "内容"},
+	{Code: PermissionMetadataManage, Name: "管理影视元数据", Group: "内容"},
+	{Code: PermissionHotDramaManage, Name: "管理热播剧", Group: "内容"},
+	{Code: PermissionTaskView, Name: "查看任务", Group: "内容"},
+	{Code: PermissionTaskManage, Name: "管理任务与目录导入导出", Group: "内容"},
+	{Code: PermissionFileUpload, Name: "上传与管理自己的文件", Group: "内容"},
+	{Code: PermissionFileManage, Name: "管理所有用户的文件", Group: "内容"},
+
+	{Code: PermissionPanManage, Name: "管理网盘平台", Group: "网盘"},
+	{Code: PermissionCksManage, Name: "管理网盘账号Cookie", Group: "网盘"},
+
+	{Code: PermissionStatsView, Name: "查看统计与分析", Group: "运营"},
+	{Code: PermissionAnalyticsManage, Name: "管理统计汇总", Group: "运营"},
+	{Code: PermissionReportView, Name: "查看举报与版权申诉", Group: "运营"},
+	{Code: PermissionReportManage, Name: "处理举报与版权申诉", Group: "运营"},
+	{Code: PermissionSEOManage, Name: "管理SEO、站点地图与搜索引擎提交", Group: "运营"},
+	{Code: PermissionSearchIndexView, Name: "查看搜索索引状态", Group: "运营"},
+	{Code: PermissionSearchIndexManage, Name: "管理搜索索引", Group: "运营"},
+	{Code: PermissionBotManage, Name: "管理Telegram与公众号机器人", Group: "运营"},
+	{Code: PermissionEventSubscribe, Name: "订阅实时事件", Group: "运营"},
+
+	{Code: PermissionUserManage, Name: "管理用户", Group: "系统"},
+	{Code: PermissionRoleManage, Name: "管理角色与权限", Group: "系统"},
+	{Code: PermissionSystemConfigManage, Name: "管理系统配置", Group: "系统"},
+	{Code: PermissionLogView, Name: "查看日志", Group: "系统"},
+	{Code: PermissionLogManage, Name: "清理日志", Group: "系统"},
+	{Code: PermissionPluginManage, Name: "管理插件", Group: "系统"},
+	{Code: PermissionBackupManage, Name: "管理备份", Group: "系统"},
+	{Code: PermissionJobManage, Name: "手动执行、暂停与调整定时任务", Group: "系统"},
+	{Code: PermissionAPIKeyManage, Name: "管理公开API密钥", Group: "系统"},
+	{Code: PermissionAuditView, Name: "查看与导出操作审计日志", Group: "系统"},
+}
+
+// DefaultRoles 内置角色，仅在角色不存在时创建，之后的权限调整以数据库为准；
+// admin 始终拥有全部权限，不受 role_permissions 影响
+var DefaultRoles = []struct {
+	Role        Role
+	Permissions []string
+}{
+	{
+		Role: Role{Name: RoleAdmin, DisplayName: "管理员", Description: "拥有全部权限", IsSystem: true},
+	},
+	{
+		Role: Role{Name: RoleEditor, DisplayName: "编辑", Description: "管理资源、待处理资源与内容采集，不能管理网盘账号、系统配置与插件", IsSystem: true},
+		Permissions: []string{
+			PermissionResourceManage, PermissionTaxonomyManage,
+			PermissionReadyResourceView, PermissionReadyResourceManage,
+			PermissionContentSourceManage, PermissionClassificationManage, PermissionMetadataManage,
+			PermissionHotDramaManage, PermissionTaskView, PermissionTaskManage, PermissionFileUpload,
+			PermissionStatsView, PermissionReportView, PermissionSearchIndexView, PermissionEventSubscribe,
+		},
+	},
+	{
+		Role: Role{Name: RoleModerator, DisplayName: "审核员", Description: "处理举报与版权申诉，可下架资源", IsSystem: true},
+		Permissions: []string{
+			PermissionResourceManage, PermissionReadyResourceView,
+			PermissionReportView, PermissionReportManage,
+			PermissionStatsView, PermissionLogView, PermissionEventSubscribe,
+		},
+	},
+	{
+		Role: Role{Name: RoleViewer, DisplayName: "只读", Description: "只能查看统计、任务、举报与日志", IsSystem: true},
+		Permissions: []string{
+			PermissionReadyResourceView, PermissionTaskView, PermissionStatsView,
+			PermissionReportView, PermissionSearchIndexView, PermissionLogView,
+		},
+	},
+	{
+		Role: Role{Name: RoleUser, DisplayName: "普通用户", Description: "注册用户，无后台权限", IsSystem: true},
+	},
+}
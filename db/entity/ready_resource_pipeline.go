@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReadyResourcePipelineDefaultSource 默认流水线的来源标识，未单独配置的数据来源使用该流水线
+const ReadyResourcePipelineDefaultSource = "default"
+
+// 阶段执行状态
+const (
+	StageStatusSuccess = "success" // 执行成功
+	StageStatusFailed  = "failed"  // 执行失败，资源被拒绝
+	StageStatusWarning = "warning" // 执行失败但允许继续
+	StageStatusSkipped = "skipped" // 资源被跳过（如已存在），不再执行后续阶段
+)
+
+// ReadyResourcePipeline 待处理资源处理流水线配置（按数据来源）
+type ReadyResourcePipeline struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Source    string    `json:"source" gorm:"size:100;not null;uniqueIndex;comment:数据来源，default表示默认流水线"`
+	Stages    string    `json:"stages" gorm:"type:text;comment:阶段配置JSON，数组顺序即执行顺序"`
+	Remark    string    `json:"remark" gorm:"size:255;comment:备注"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ReadyResourcePipeline) TableName() string {
+	return "ready_resource_pipelines"
+}
+
+// ReadyResourceStageLog 待处理资源阶段执行记录
+type ReadyResourceStageLog struct {
+	ID              uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ReadyResourceID uint      `json:"ready_resource_id" gorm:"not null;index;comment:待处理资源ID"`
+	ResourceID      *uint     `json:"resource_id" gorm:"comment:入库后的正式资源ID"`
+	Key             string    `json:"key" gorm:"size:64;comment:资源组标识"`
+	URL             string    `json:"url" gorm:"size:500;comment:资源链接"`
+	Source          string    `json:"source" gorm:"size:100;comment:数据来源"`
+	Stage           string    `json:"stage" gorm:"size:50;not null;comment:阶段名称"`
+	Seq             int       `json:"seq" gorm:"comment:阶段执行序号"`
+	Status          string    `json:"status" gorm:"size:20;not null;comment:执行状态"`
+	DurationMs      int64     `json:"duration_ms" gorm:"comment:耗时(毫秒)"`
+	Error           string    `json:"error" gorm:"type:text;comment:失败原因"`
+	CreatedAt       time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (ReadyResourceStageLog) TableName() string {
+	return "ready_resource_stage_logs"
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// 影视元数据匹配状态
+const (
+	ResourceMetadataMatched  = "matched"   // 自动匹配成功
+	ResourceMetadataManual   = "manual"    // 手动绑定，刷新时不重新匹配
+	ResourceMetadataNotFound = "not_found" // 未找到匹配条目
+	ResourceMetadataFailed   = "failed"    // 请求数据源失败
+)
+
+// ResourceMetadata 资源的影视元数据（豆瓣/TMDB），每个资源一条
+type ResourceMetadata struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ResourceID    uint       `json:"resource_id" gorm:"not null;uniqueIndex;comment:资源ID"`
+	Provider      string     `json:"provider" gorm:"size:20;index:idx_resource_metadata_subject;comment:数据来源 douban/tmdb"`
+	SubjectID     string     `json:"subject_id" gorm:"size:50;index:idx_resource_metadata_subject;comment:豆瓣条目ID/TMDB作品ID"`
+	MediaType     string     `json:"media_type" gorm:"size:10;index;comment:类型 movie/tv"`
+	Title         string     `json:"title" gorm:"size:255;comment:条目标题"`
+	OriginalTitle string     `json:"original_title" gorm:"size:255;comment:原名"`
+	Year          int        `json:"year" gorm:"index;comment:年份"`
+	Regions       string     `json:"regions" gorm:"size:255;comment:地区，逗号分隔"`
+	Genres        string     `json:"genres" gorm:"size:255;comment:类型，逗号分隔"`
+	Rating        float64    `json:"rating" gorm:"index;comment:评分"`
+	RatingCount   int        `json:"rating_count" gorm:"comment:评分人数"`
+	PosterURL     string     `json:"poster_url" gorm:"size:500;comment:海报"`
+	Episodes      int        `json:"episodes" gorm:"comment:集数"`
+	Directors     string     `json:"directors" gorm:"size:500;comment:导演，逗号分隔"`
+	Actors        string     `json:"actors" gorm:"size:1000;comment:主演，逗号分隔"`
+	Summary       string     `json:"summary" gorm:"type:text;comment:简介"`
+	SubjectURL    string     `json:"subject_url" gorm:"size:255;comment:条目链接"`
+	MatchQuery    string     `json:"match_query" gorm:"size:255;comment:匹配使用的规范化片名"`
+	MatchScore    float64    `json:"match_score" gorm:"comment:匹配度"`
+	Status        string     `json:"status" gorm:"size:20;not null;index;comment:匹配状态"`
+	Error         string     `json:"error" gorm:"size:255;comment:最近一次失败原因"`
+	FetchedAt     *time.Time `json:"fetched_at" gorm:"comment:最近抓取时间"`
+	NextRefreshAt *time.Time `json:"next_refresh_at" gorm:"index;comment:下次刷新时间"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ResourceMetadata) TableName() string {
+	return "resource_metadata"
+}
+
+// HasSubject 是否已关联到影视条目
+func (m *ResourceMetadata) HasSubject() bool {
+	return m != nil && (m.Status == ResourceMetadataMatched || m.Status == ResourceMetadataManual)
+}
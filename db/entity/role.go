@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+)
+
+// Role 角色
+type Role struct {
+	ID          uint         `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string       `json:"name" gorm:"size:20;not null;uniqueIndex;comment:角色标识，对应users.role"`
+	DisplayName string       `json:"display_name" gorm:"size:50;not null;comment:显示名称"`
+	Description string       `json:"description" gorm:"size:255;comment:描述"`
+	IsSystem    bool         `json:"is_system" gorm:"default:false;comment:是否内置角色，内置角色不可删除"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions;"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// PermissionCodes 返回角色拥有的权限码
+func (r *Role) PermissionCodes() []string {
+	codes := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		codes = append(codes, p.Code)
+	}
+	return codes
+}
+
+// Permission 权限
+type Permission struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code      string    `json:"code" gorm:"size:50;not null;uniqueIndex;comment:权限码，如 cks:manage"`
+	Name      string    `json:"name" gorm:"size:50;not null;comment:权限名称"`
+	Group     string    `json:"group" gorm:"column:group_name;size:20;comment:权限分组"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// PermissionDenial 权限拒绝记录
+type PermissionDenial struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint      `json:"user_id" gorm:"index;comment:用户ID"`
+	Username   string    `json:"username" gorm:"size:50;index;comment:用户名"`
+	Role       string    `json:"role" gorm:"size:20;comment:角色"`
+	Permission string    `json:"permission" gorm:"size:50;index;comment:缺少的权限码"`
+	Method     string    `json:"method" gorm:"size:10;comment:请求方法"`
+	Path       string    `json:"path" gorm:"size:255;comment:请求路径"`
+	IP         string    `json:"ip" gorm:"size:45;comment:客户端IP"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (PermissionDenial) TableName() string {
+	return "permission_denials"
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// ScheduledJobRun 定时任务执行记录，每次执行结束时写入（定时执行被跳过时不记录）
+type ScheduledJobRun struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Job        string     `json:"job" gorm:"size:100;not null;index:idx_scheduled_job_runs_job_started,priority:1;comment:任务名"`
+	Trigger    string     `json:"trigger" gorm:"size:20;comment:执行来源 schedule/catch_up/manual"`
+	Node       string     `json:"node" gorm:"size:100;comment:执行节点"`
+	Status     string     `json:"status" gorm:"size:20;index;comment:结果 success/failed/skipped"`
+	Summary    string     `json:"summary" gorm:"type:text;comment:结果摘要"`
+	Error      string     `json:"error" gorm:"type:text;comment:错误信息"`
+	StartedAt  time.Time  `json:"started_at" gorm:"index:idx_scheduled_job_runs_job_started,priority:2;index;comment:开始时间"`
+	FinishedAt *time.Time `json:"finished_at" gorm:"comment:结束时间"`
+	DurationMs int64      `json:"duration_ms" gorm:"comment:耗时(毫秒)"`
+}
+
+// TableName 指定表名
+func (ScheduledJobRun) TableName() string {
+	return "scheduled_job_runs"
+}
+
+// ScheduledJobState 定时任务的暂停状态与表达式覆盖，多副本间共享
+type ScheduledJobState struct {
+	Name      string    `json:"name" gorm:"primaryKey;size:100;comment:任务名"`
+	Spec      string    `json:"spec" gorm:"size:100;comment:覆盖的cron表达式，为空时使用默认表达式"`
+	Paused    bool      `json:"paused" gorm:"default:false;comment:是否暂停定时执行"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ScheduledJobState) TableName() string {
+	return "scheduled_job_states"
+}
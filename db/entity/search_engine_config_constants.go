@@ -0,0 +1,34 @@
+package entity
+
+// SearchEngineConfigKeys 搜索引擎 URL 提交配置键常量
+// Google 凭据复用 GoogleIndexConfigKeyCredentialsFile，Bing 密钥复用 BingIndexConfigKeyAPIKey
+const (
+	SearchEngineConfigKeyAutoSubmit        = "search_engine_auto_submit"         // 是否自动提交新增/更新的资源页面
+	SearchEngineConfigKeyIntervalMinutes   = "search_engine_interval_minutes"    // 自动提交间隔（分钟）
+	SearchEngineConfigKeyCursor            = "search_engine_submit_cursor"       // 已扫描到的资源更新时间（内部使用）
+	SearchEngineConfigKeyMaxAttempts       = "search_engine_max_attempts"        // 单个URL最多重试次数
+	SearchEngineConfigKeyGoogleEnabled     = "search_engine_google_enabled"      // 启用 Google Indexing API
+	SearchEngineConfigKeyGoogleQuota       = "search_engine_google_quota"        // Google 每日配额
+	SearchEngineConfigKeyBingEnabled       = "search_engine_bing_enabled"        // 启用 Bing Webmaster URL 提交
+	SearchEngineConfigKeyBingQuota         = "search_engine_bing_quota"          // Bing 每日配额
+	SearchEngineConfigKeyIndexNowEnabled   = "search_engine_indexnow_enabled"    // 启用 IndexNow
+	SearchEngineConfigKeyIndexNowKey       = "search_engine_indexnow_key"        // IndexNow 密钥
+	SearchEngineConfigKeyIndexNowURL       = "search_engine_indexnow_endpoint"   // IndexNow 接口地址
+	SearchEngineConfigKeyIndexNowQuota     = "search_engine_indexnow_quota"      // IndexNow 每日配额
+	SearchEngineConfigKeyBaiduEnabled      = "search_engine_baidu_enabled"       // 启用百度普通收录
+	SearchEngineConfigKeyBaiduToken        = "search_engine_baidu_token"         // 百度推送 token
+	SearchEngineConfigKeyBaiduQuota        = "search_engine_baidu_quota"         // 百度普通收录每日配额
+	SearchEngineConfigKeyBaiduDailyEnabled = "search_engine_baidu_daily_enabled" // 启用百度快速收录
+	SearchEngineConfigKeyBaiduDailyQuota   = "search_engine_baidu_daily_quota"   // 百度快速收录每日配额
+)
+
+// 搜索引擎 URL 提交配置默认值
+const (
+	SearchEngineConfigDefaultIntervalMinutes = 30
+	SearchEngineConfigDefaultMaxAttempts     = 3
+	SearchEngineConfigDefaultGoogleQuota     = 200
+	SearchEngineConfigDefaultBingQuota       = 100
+	SearchEngineConfigDefaultIndexNowQuota   = 10000
+	SearchEngineConfigDefaultBaiduQuota      = 10
+	SearchEngineConfigDefaultBaiduDailyQuota = 10
+)
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// 搜索引擎提交状态
+const (
+	SearchEngineSubmissionPending = "pending" // 等待提交
+	SearchEngineSubmissionSuccess = "success" // 提交成功
+	SearchEngineSubmissionFailed  = "failed"  // 提交失败（未超过重试次数时会再次提交）
+)
+
+// SearchEngineSubmission 搜索引擎 URL 提交台账，每个搜索引擎的每个 URL 一条记录
+type SearchEngineSubmission struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Engine      string     `json:"engine" gorm:"size:20;not null;uniqueIndex:idx_search_engine_submission_engine_url,priority:1;comment:搜索引擎"`
+	URL         string     `json:"url" gorm:"size:500;not null;uniqueIndex:idx_search_engine_submission_engine_url,priority:2;comment:页面地址"`
+	ResourceID  *uint      `json:"resource_id" gorm:"index;comment:关联资源ID"`
+	Status      string     `json:"status" gorm:"size:20;not null;default:'pending';index;comment:状态 pending/success/failed"`
+	Attempts    int        `json:"attempts" gorm:"default:0;comment:本次排队后的提交次数"`
+	Message     string     `json:"message" gorm:"size:500;comment:最近一次提交结果"`
+	QueuedAt    time.Time  `json:"queued_at" gorm:"index;comment:排队时间"`
+	SubmittedAt *time.Time `json:"submitted_at" gorm:"comment:最近一次提交时间"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SearchEngineSubmission) TableName() string {
+	return "search_engine_submissions"
+}
+
+// SearchEngineQuotaUsage 搜索引擎每日配额使用量
+type SearchEngineQuotaUsage struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Engine    string    `json:"engine" gorm:"size:20;not null;uniqueIndex:idx_search_engine_quota_engine_date,priority:1;comment:搜索引擎"`
+	Date      string    `json:"date" gorm:"size:10;not null;uniqueIndex:idx_search_engine_quota_engine_date,priority:2;comment:日期 YYYY-MM-DD"`
+	Used      int       `json:"used" gorm:"default:0;comment:已提交URL数"`
+	Exhausted bool      `json:"exhausted" gorm:"default:false;comment:搜索引擎是否已返回配额用尽"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SearchEngineQuotaUsage) TableName() string {
+	return "search_engine_quota_usages"
+}
@@ -0,0 +1,16 @@
+package entity
+
+// SEOConfigKeys 资源页 SEO 配置键常量
+// 站点名称与地址复用 ConfigKeySiteTitle、ConfigKeyWebsiteURL
+const (
+	SEOConfigKeyTitleTemplate       = "seo_title_template"       // meta 标题模板
+	SEOConfigKeyDescriptionTemplate = "seo_description_template" // meta 描述模板
+	SEOConfigKeyHreflang            = "seo_hreflang"             // hreflang 语言列表，逗号分隔
+)
+
+// 资源页 SEO 配置默认值
+const (
+	SEOConfigDefaultTitleTemplate       = "{title} - {category} - {site_title}"
+	SEOConfigDefaultDescriptionTemplate = "{title}，{summary}，{description}"
+	SEOConfigDefaultHreflang            = "zh-CN"
+)
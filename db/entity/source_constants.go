@@ -9,8 +9,17 @@ const (
 	SourceWeb      = "web"      // 网页前端
 	SourceWechat   = "wechat"   // 微信公众号
 	SourceTelegram = "telegram" // 电报机器人（011-telegram-bot-enhance）
-	// 预留（首期不实现，仅占位以保证取值可扩展）：
-	// SourceAPI = "api"
+	SourceAPI      = "api"      // 公开 API
+)
+
+// 资源采集来源常量（ReadyResource.Source）
+// 内容源采集器按 ContentSource.Type 写入对应取值，便于在待处理资源中按来源筛选与统计。
+const (
+	SourceRSS             = "rss"              // RSS / Atom 订阅
+	SourceTelegramExport  = "telegram_export"  // Telegram 频道导出历史
+	SourceHTML            = "html"             // 网页列表（CSS / XPath 选择器）
+	SourceJSONAPI         = "json_api"         // JSON 接口字段映射
+	SourceTelegramChannel = "telegram_channel" // Telegram 采集频道（channel_post 或导出历史）
 )
 
 // SourceDisplayName 返回来源渠道的中文展示名；未知来源原样返回。
@@ -22,6 +31,18 @@ func SourceDisplayName(source string) string {
 		return "公众号"
 	case SourceTelegram:
 		return "电报"
+	case SourceAPI:
+		return "API"
+	case SourceRSS:
+		return "RSS订阅"
+	case SourceTelegramExport:
+		return "电报导出"
+	case SourceHTML:
+		return "网页采集"
+	case SourceJSONAPI:
+		return "JSON接口"
+	case SourceTelegramChannel:
+		return "电报频道"
 	default:
 		return source
 	}
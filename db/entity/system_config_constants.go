@@ -59,6 +59,7 @@ const (
 	ConfigKeyTelegramSearchPageSize     = "telegram_search_page_size" // 011-telegram-bot-enhance：搜索每页条数
 	ConfigKeyTelegramWelcomeEnabled     = "telegram_welcome_enabled"      // 入群欢迎消息开关
 	ConfigKeyTelegramWelcomeMessage     = "telegram_welcome_message"      // 入群欢迎消息模板
+	ConfigKeyTelegramAdminChatID        = "telegram_admin_chat_id"        // 管理员通知会话ID（登录告警等）
 
 	// 微信公众号配置
 	ConfigKeyWechatBotEnabled       = "wechat_bot_enabled"
@@ -97,6 +98,9 @@ const (
 	ConfigKeyAutoCleanupEnabled         = "auto_cleanup_enabled"
 	ConfigKeyAutoCleanupRetentionDays   = "auto_cleanup_retention_days"
 	ConfigKeyAutoCleanupIntervalMinutes = "auto_cleanup_interval_minutes"
+
+	// JWT签名密钥，未设置 JWT_SECRET 环境变量时自动生成并保存，不在配置接口中返回
+	ConfigKeyJWTSecret = "jwt_secret"
 )
 
 // ConfigType 配置类型常量
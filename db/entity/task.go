@@ -25,6 +25,8 @@ const (
 	TaskTypeBatchTransfer TaskType = "batch_transfer" // 批量转存
 	TaskTypeExpansion     TaskType = "expansion"     // 账号扩容
 	TaskTypeGoogleIndex   TaskType = "google_index"  // Google索引
+	TaskTypeCatalogExport TaskType = "catalog_export" // 资源目录导出
+	TaskTypeCatalogImport TaskType = "catalog_import" // 资源目录导入
 )
 
 // Task 任务表
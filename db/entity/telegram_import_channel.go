@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TelegramImportChannel Telegram 采集频道（从频道帖子导入待处理资源）
+// 机器人需为频道管理员才能收到 channel_post 更新；也可上传频道导出的 JSON 历史批量导入。
+// 多个采集频道互相独立，各自维护过滤条件与默认分类/标签。
+type TelegramImportChannel struct {
+	ID           uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	ChatID       int64  `json:"chat_id" gorm:"uniqueIndex;not null;comment:Telegram 频道ID（-100 开头）"`
+	ChatUsername string `json:"chat_username" gorm:"size:100;comment:频道用户名（不含@），用于生成帖子链接"`
+	ChatTitle    string `json:"chat_title" gorm:"size:255;comment:频道名称"`
+	Enabled      bool   `json:"enabled" gorm:"default:true;comment:是否启用采集"`
+
+	// 过滤条件
+	IncludeKeywords string     `json:"include_keywords" gorm:"type:text;comment:包含关键词（任一命中即采集），逗号或换行分隔，为空不限制"`
+	ExcludeKeywords string     `json:"exclude_keywords" gorm:"type:text;comment:排除关键词（任一命中即跳过），逗号或换行分隔"`
+	Platforms       string     `json:"platforms" gorm:"size:255;comment:允许的网盘类型（quark,baidu,alipan...），为空不限制"`
+	MinPostDate     *time.Time `json:"min_post_date" gorm:"comment:最早帖子时间，早于该时间的帖子跳过"`
+
+	// 导入默认值
+	Category string `json:"category" gorm:"size:100;comment:默认分类"`
+	Tags     string `json:"tags" gorm:"size:500;comment:默认标签，多个标签用逗号分隔"`
+
+	// 采集状态
+	LastMessageID int64      `json:"last_message_id" gorm:"default:0;comment:最近导入的消息ID"`
+	LastImportAt  *time.Time `json:"last_import_at" gorm:"comment:最近导入时间"`
+	ImportedCount int64      `json:"imported_count" gorm:"default:0;comment:累计导入待处理资源数"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (TelegramImportChannel) TableName() string {
+	return "telegram_import_channels"
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// 会话吊销原因
+const (
+	SessionRevokeLogout       = "logout"
+	SessionRevokeManual       = "revoked"
+	SessionRevokeRefreshReuse = "refresh_reuse"
+	SessionRevokePassword     = "password_changed"
+	SessionRevokeUserDisabled = "user_disabled"
+	SessionRevokeUserDeleted  = "user_deleted"
+	SessionRevokeRoleChanged  = "role_changed"
+)
+
+// UserSession 登录会话，服务端保存刷新令牌哈希，访问令牌通过 sid 关联
+type UserSession struct {
+	ID                uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	SessionID         string     `json:"session_id" gorm:"size:32;not null;uniqueIndex;comment:会话标识，对应JWT的sid"`
+	UserID            uint       `json:"user_id" gorm:"not null;index;comment:用户ID"`
+	RefreshTokenHash  string     `json:"-" gorm:"size:64;not null;comment:当前刷新令牌SHA-256"`
+	PreviousTokenHash string     `json:"-" gorm:"size:64;default:'';comment:上一个刷新令牌SHA-256，用于重放检测"`
+	RotatedAt         *time.Time `json:"rotated_at" gorm:"comment:最近一次刷新时间"`
+	IP                string     `json:"ip" gorm:"size:64;comment:最近使用IP"`
+	UserAgent         string     `json:"user_agent" gorm:"size:255;comment:客户端UA"`
+	LastUsedAt        time.Time  `json:"last_used_at" gorm:"comment:最后刷新时间"`
+	ExpiresAt         time.Time  `json:"expires_at" gorm:"not null;index;comment:刷新令牌过期时间"`
+	RevokedAt         *time.Time `json:"revoked_at" gorm:"comment:吊销时间"`
+	RevokeReason      string     `json:"revoke_reason" gorm:"size:32;default:'';comment:吊销原因"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsActive 会话未吊销且未过期
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,335 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+)
+
+// 趋势指标
+const (
+	AnalyticsMetricViews       = "views"
+	AnalyticsMetricVisitors    = "visitors"
+	AnalyticsMetricSearches    = "searches"
+	AnalyticsMetricAPIRequests = "api_requests"
+)
+
+// 排行榜维度
+const (
+	AnalyticsDimensionResource = "resource"
+	AnalyticsDimensionCategory = "category"
+	AnalyticsDimensionPan      = "pan"
+	AnalyticsDimensionSource   = "source"
+	AnalyticsDimensionKeyword  = "keyword"
+	AnalyticsDimensionEndpoint = "endpoint"
+)
+
+// AnalyticsRepository 统计日汇总Repository接口
+//
+// 日期参数均为 YYYY-MM-DD 字符串，按数据库 DATE() 口径划分自然日，与原有实时统计一致。
+// 查询接口的 liveFrom 表示从该日（含）起改为直接统计原始表，用于当天及尚未汇总的日期；
+// from 为空表示不限起始日期。
+type AnalyticsRepository interface {
+	BaseRepository[entity.AnalyticsRollupDay]
+	RollupDay(day string) (*entity.AnalyticsRollupDay, error)
+	FindRollupDays(from, to string) ([]entity.AnalyticsRollupDay, error)
+	EarliestRawDate() (string, error)
+	PurgeRaw(table, before string, batchSize int) (int64, error)
+	Series(metric, groupBy, from, liveFrom, to string) ([]entity.AnalyticsSeriesPoint, error)
+	Leaderboard(dimension, from, liveFrom, to string, limit int) ([]entity.AnalyticsLeaderboardItem, error)
+}
+
+// AnalyticsRepositoryImpl 统计日汇总Repository实现
+type AnalyticsRepositoryImpl struct {
+	BaseRepositoryImpl[entity.AnalyticsRollupDay]
+}
+
+// NewAnalyticsRepository 创建统计日汇总Repository
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &AnalyticsRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.AnalyticsRollupDay]{db: db},
+	}
+}
+
+// RollupDay 汇总指定日期：删除当日已有汇总后按原始表重新生成，可重复执行。
+// 原始数据已被清理的表保留原汇总结果，避免重新汇总时把历史数据清空。
+func (r *AnalyticsRepositoryImpl) RollupDay(day string) (*entity.AnalyticsRollupDay, error) {
+	var result entity.AnalyticsRollupDay
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var existing entity.AnalyticsRollupDay
+		found := true
+		if err := tx.Where("date = ?", day).First(&existing).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				return err
+			}
+			found = false
+		}
+		result = existing
+
+		if !found || existing.ViewsPurgedAt == nil {
+			if err := tx.Exec(`DELETE FROM view_daily_stats WHERE date = ?`, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO view_daily_stats (date, resource_id, source, category_id, pan_id, views, unique_visitors, created_at)
+				SELECT ?::date, rv.resource_id, COALESCE(rv.source, ''), r.category_id, r.pan_id, COUNT(*), COUNT(DISTINCT rv.ip_address), NOW()
+				FROM resource_views rv LEFT JOIN resources r ON r.id = rv.resource_id
+				WHERE rv.deleted_at IS NULL AND DATE(rv.created_at) = ?
+				GROUP BY rv.resource_id, COALESCE(rv.source, ''), r.category_id, r.pan_id`, day, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Raw(`SELECT COUNT(*) AS views, COUNT(DISTINCT ip_address) AS unique_visitors
+				FROM resource_views WHERE deleted_at IS NULL AND DATE(created_at) = ?`, day).
+				Row().Scan(&result.Views, &result.UniqueVisitors); err != nil {
+				return err
+			}
+		}
+
+		if !found || existing.SearchesPurgedAt == nil {
+			if err := tx.Exec(`DELETE FROM search_daily_stats WHERE date = ?`, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO search_daily_stats (date, keyword, source, searches, unique_ips, created_at)
+				SELECT ?::date, keyword, COALESCE(source, ''), SUM(count), COUNT(DISTINCT ip), NOW()
+				FROM search_stats WHERE deleted_at IS NULL AND date = ?
+				GROUP BY keyword, COALESCE(source, '')`, day, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Raw(`SELECT COALESCE(SUM(count), 0) AS searches, COUNT(DISTINCT ip) AS unique_searchers
+				FROM search_stats WHERE deleted_at IS NULL AND date = ?`, day).
+				Row().Scan(&result.Searches, &result.UniqueSearchers); err != nil {
+				return err
+			}
+		}
+
+		if !found || existing.APILogsPurgedAt == nil {
+			if err := tx.Exec(`DELETE FROM api_access_daily_stats WHERE date = ?`, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO api_access_daily_stats (date, endpoint, method, requests, errors, total_processing_time, unique_ips, created_at)
+				SELECT ?::date, endpoint, method, COUNT(*), SUM(CASE WHEN response_status >= 400 THEN 1 ELSE 0 END),
+					COALESCE(SUM(processing_time), 0), COUNT(DISTINCT ip), NOW()
+				FROM api_access_logs WHERE deleted_at IS NULL AND DATE(created_at) = ?
+				GROUP BY endpoint, method`, day, day).Error; err != nil {
+				return err
+			}
+			if err := tx.Raw(`SELECT COUNT(*) AS requests, COALESCE(SUM(CASE WHEN response_status >= 400 THEN 1 ELSE 0 END), 0) AS errors
+				FROM api_access_logs WHERE deleted_at IS NULL AND DATE(created_at) = ?`, day).
+				Row().Scan(&result.APIRequests, &result.APIErrors); err != nil {
+				return err
+			}
+		}
+
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return err
+		}
+		result.Date = date
+		result.RolledAt = time.Now()
+		return tx.Exec(`INSERT INTO analytics_rollup_days (date, views, unique_visitors, searches, unique_searchers, api_requests, api_errors, rolled_at)
+			VALUES (?::date, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (date) DO UPDATE SET views = EXCLUDED.views, unique_visitors = EXCLUDED.unique_visitors,
+				searches = EXCLUDED.searches, unique_searchers = EXCLUDED.unique_searchers,
+				api_requests = EXCLUDED.api_requests, api_errors = EXCLUDED.api_errors, rolled_at = EXCLUDED.rolled_at`,
+			day, result.Views, result.UniqueVisitors, result.Searches, result.UniqueSearchers,
+			result.APIRequests, result.APIErrors, result.RolledAt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindRollupDays 查询日期范围内已汇总的日期，按日期升序
+func (r *AnalyticsRepositoryImpl) FindRollupDays(from, to string) ([]entity.AnalyticsRollupDay, error) {
+	var days []entity.AnalyticsRollupDay
+	query := r.db.Model(&entity.AnalyticsRollupDay{})
+	if from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	err := query.Order("date ASC").Find(&days).Error
+	return days, err
+}
+
+// EarliestRawDate 原始表中最早的日期，无数据时返回空字符串
+func (r *AnalyticsRepositoryImpl) EarliestRawDate() (string, error) {
+	var earliest *time.Time
+	err := r.db.Raw(`SELECT MIN(d) FROM (
+			SELECT MIN(DATE(created_at)) AS d FROM resource_views WHERE deleted_at IS NULL
+			UNION ALL SELECT MIN(date) FROM search_stats WHERE deleted_at IS NULL
+			UNION ALL SELECT MIN(DATE(created_at)) FROM api_access_logs WHERE deleted_at IS NULL
+		) t`).Row().Scan(&earliest)
+	if err != nil || earliest == nil {
+		return "", err
+	}
+	return earliest.Format("2006-01-02"), nil
+}
+
+// PurgeRaw 分批物理删除 before 之前、且当日已完成汇总的原始记录，并标记该日已清理，返回删除条数
+func (r *AnalyticsRepositoryImpl) PurgeRaw(table, before string, batchSize int) (int64, error) {
+	var deleteSQL, purgedColumn string
+	switch table {
+	case entity.AnalyticsRawViews:
+		deleteSQL = `DELETE FROM resource_views WHERE id IN (SELECT id FROM resource_views WHERE DATE(created_at) = ? LIMIT ?)`
+		purgedColumn = "views_purged_at"
+	case entity.AnalyticsRawSearches:
+		deleteSQL = `DELETE FROM search_stats WHERE id IN (SELECT id FROM search_stats WHERE date = ? LIMIT ?)`
+		purgedColumn = "searches_purged_at"
+	case entity.AnalyticsRawAPILogs:
+		deleteSQL = `DELETE FROM api_access_logs WHERE id IN (SELECT id FROM api_access_logs WHERE DATE(created_at) = ? LIMIT ?)`
+		purgedColumn = "api_logs_purged_at"
+	default:
+		return 0, fmt.Errorf("不支持清理的数据表: %s", table)
+	}
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	var days []string
+	if err := r.db.Model(&entity.AnalyticsRollupDay{}).
+		Where("date < ? AND "+purgedColumn+" IS NULL", before).
+		Order("date ASC").
+		Pluck("TO_CHAR(date, 'YYYY-MM-DD')", &days).Error; err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, day := range days {
+		for {
+			result := r.db.Exec(deleteSQL, day, batchSize)
+			if result.Error != nil {
+				return total, result.Error
+			}
+			total += result.RowsAffected
+			if result.RowsAffected < int64(batchSize) {
+				break
+			}
+		}
+		if err := r.db.Model(&entity.AnalyticsRollupDay{}).Where("date = ?", day).
+			Update(purgedColumn, time.Now()).Error; err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Series 按日统计趋势，groupBy 为空或 source。liveFrom 之前取汇总表，之后取原始表。
+// visitors 指标为每日全站独立IP数，不支持分组。
+func (r *AnalyticsRepositoryImpl) Series(metric, groupBy, from, liveFrom, to string) ([]entity.AnalyticsSeriesPoint, error) {
+	if groupBy != "" && groupBy != AnalyticsDimensionSource {
+		return nil, fmt.Errorf("不支持的分组维度: %s", groupBy)
+	}
+	var rollupSQL, liveSQL string
+	switch metric {
+	case AnalyticsMetricViews:
+		rollupSQL = `SELECT date AS day, %s AS key, SUM(views) AS value FROM view_daily_stats WHERE %s GROUP BY 1, 2`
+		liveSQL = `SELECT DATE(created_at) AS day, %s AS key, COUNT(*) AS value FROM resource_views WHERE deleted_at IS NULL AND %s GROUP BY 1, 2`
+	case AnalyticsMetricSearches:
+		rollupSQL = `SELECT date AS day, %s AS key, SUM(searches) AS value FROM search_daily_stats WHERE %s GROUP BY 1, 2`
+		liveSQL = `SELECT date AS day, %s AS key, SUM(count) AS value FROM search_stats WHERE deleted_at IS NULL AND %s GROUP BY 1, 2`
+	case AnalyticsMetricVisitors, AnalyticsMetricAPIRequests:
+		if groupBy != "" {
+			return nil, fmt.Errorf("指标 %s 不支持分组", metric)
+		}
+		if metric == AnalyticsMetricVisitors {
+			rollupSQL = `SELECT date AS day, %s AS key, unique_visitors AS value FROM analytics_rollup_days WHERE %s`
+			liveSQL = `SELECT DATE(created_at) AS day, %s AS key, COUNT(DISTINCT ip_address) AS value FROM resource_views WHERE deleted_at IS NULL AND %s GROUP BY 1, 2`
+		} else {
+			rollupSQL = `SELECT date AS day, %s AS key, SUM(requests) AS value FROM api_access_daily_stats WHERE %s GROUP BY 1, 2`
+			liveSQL = `SELECT DATE(created_at) AS day, %s AS key, COUNT(*) AS value FROM api_access_logs WHERE deleted_at IS NULL AND %s GROUP BY 1, 2`
+		}
+	default:
+		return nil, fmt.Errorf("不支持的统计指标: %s", metric)
+	}
+
+	key := "''"
+	if groupBy == AnalyticsDimensionSource {
+		key = "COALESCE(source, '')"
+	}
+	liveDay := "DATE(created_at)"
+	if metric == AnalyticsMetricSearches {
+		liveDay = "date"
+	}
+	rollupWhere, rollupArgs := dayRange("date", from, liveFrom, false)
+	liveWhere, liveArgs := dayRange(liveDay, liveFrom, to, true)
+
+	query := fmt.Sprintf(`SELECT TO_CHAR(t.day, 'YYYY-MM-DD') AS date, t.key, CAST(SUM(t.value) AS BIGINT) AS value FROM (`+
+		rollupSQL+` UNION ALL `+liveSQL+`) t GROUP BY t.day, t.key ORDER BY t.day ASC, value DESC`,
+		key, rollupWhere, key, liveWhere)
+	var points []entity.AnalyticsSeriesPoint
+	err := r.db.Raw(query, append(rollupArgs, liveArgs...)...).Scan(&points).Error
+	return points, err
+}
+
+// Leaderboard 排行榜，value 为区间内合计；unique 为各日独立IP数之和（跨日不去重）
+func (r *AnalyticsRepositoryImpl) Leaderboard(dimension, from, liveFrom, to string, limit int) ([]entity.AnalyticsLeaderboardItem, error) {
+	var rollupSQL, liveSQL, nameJoin, nameExpr string
+	rollupDay, liveDay := "date", "DATE(rv.created_at)"
+	switch dimension {
+	case AnalyticsDimensionResource, AnalyticsDimensionCategory, AnalyticsDimensionPan, AnalyticsDimensionSource:
+		rollupKey, liveKey := "CAST(resource_id AS TEXT)", "CAST(rv.resource_id AS TEXT)"
+		nameJoin, nameExpr = "LEFT JOIN resources n ON CAST(n.id AS TEXT) = t.key", "n.title"
+		switch dimension {
+		case AnalyticsDimensionCategory:
+			rollupKey, liveKey = "COALESCE(CAST(category_id AS TEXT), '')", "COALESCE(CAST(r.category_id AS TEXT), '')"
+			nameJoin, nameExpr = "LEFT JOIN categories n ON CAST(n.id AS TEXT) = t.key", "COALESCE(n.name, '未分类')"
+		case AnalyticsDimensionPan:
+			rollupKey, liveKey = "COALESCE(CAST(pan_id AS TEXT), '')", "COALESCE(CAST(r.pan_id AS TEXT), '')"
+			nameJoin, nameExpr = "LEFT JOIN pan n ON CAST(n.id AS TEXT) = t.key", "COALESCE(NULLIF(n.remark, ''), n.name, '未知')"
+		case AnalyticsDimensionSource:
+			rollupKey, liveKey = "COALESCE(source, '')", "COALESCE(rv.source, '')"
+			nameJoin, nameExpr = "", "t.key"
+		}
+		rollupSQL = `SELECT ` + rollupKey + ` AS key, SUM(views) AS value, SUM(unique_visitors) AS uniq, 0 AS errors FROM view_daily_stats WHERE %s GROUP BY 1`
+		liveSQL = `SELECT ` + liveKey + ` AS key, COUNT(*) AS value, COUNT(DISTINCT rv.ip_address) AS uniq, 0 AS errors
+			FROM resource_views rv LEFT JOIN resources r ON r.id = rv.resource_id WHERE rv.deleted_at IS NULL AND %s GROUP BY 1`
+	case AnalyticsDimensionKeyword:
+		liveDay = "date"
+		nameExpr = "t.key"
+		rollupSQL = `SELECT keyword AS key, SUM(searches) AS value, SUM(unique_ips) AS uniq, 0 AS errors FROM search_daily_stats WHERE %s GROUP BY 1`
+		liveSQL = `SELECT keyword AS key, SUM(count) AS value, COUNT(DISTINCT ip) AS uniq, 0 AS errors FROM search_stats WHERE deleted_at IS NULL AND %s GROUP BY 1`
+	case AnalyticsDimensionEndpoint:
+		liveDay = "DATE(created_at)"
+		nameExpr = "t.key"
+		rollupSQL = `SELECT method || ' ' || endpoint AS key, SUM(requests) AS value, SUM(unique_ips) AS uniq, SUM(errors) AS errors FROM api_access_daily_stats WHERE %s GROUP BY 1`
+		liveSQL = `SELECT method || ' ' || endpoint AS key, COUNT(*) AS value, COUNT(DISTINCT ip) AS uniq,
+			SUM(CASE WHEN response_status >= 400 THEN 1 ELSE 0 END) AS errors
+			FROM api_access_logs WHERE deleted_at IS NULL AND %s GROUP BY 1`
+	default:
+		return nil, fmt.Errorf("不支持的排行维度: %s", dimension)
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rollupWhere, rollupArgs := dayRange(rollupDay, from, liveFrom, false)
+	liveWhere, liveArgs := dayRange(liveDay, liveFrom, to, true)
+	query := `SELECT t.key, ` + nameExpr + ` AS name, CAST(SUM(t.value) AS BIGINT) AS value, CAST(SUM(t.uniq) AS BIGINT) AS "unique",
+		CAST(SUM(t.errors) AS BIGINT) AS errors FROM (` +
+		fmt.Sprintf(rollupSQL, rollupWhere) + ` UNION ALL ` + fmt.Sprintf(liveSQL, liveWhere) + `) t ` + nameJoin +
+		` GROUP BY t.key, ` + nameExpr + ` ORDER BY value DESC, t.key ASC LIMIT ?`
+
+	var items []entity.AnalyticsLeaderboardItem
+	err := r.db.Raw(query, append(append(rollupArgs, liveArgs...), limit)...).Scan(&items).Error
+	return items, err
+}
+
+// dayRange 生成日期区间条件，from 为空表示不限起始；inclusive 控制是否包含 to 当天
+func dayRange(column, from, to string, inclusive bool) (string, []interface{}) {
+	where, args := "1 = 1", []interface{}{}
+	if from != "" {
+		where += " AND " + column + " >= ?"
+		args = append(args, from)
+	}
+	if inclusive {
+		where += " AND " + column + " <= ?"
+	} else {
+		where += " AND " + column + " < ?"
+	}
+	args = append(args, to)
+	return where, args
+}
@@ -16,7 +16,7 @@ type APIAccessLogRepository interface {
 	RecordAccess(ip, userAgent, endpoint, method string, requestParams interface{}, responseStatus int, responseData interface{}, processCount int, errorMessage string, processingTime int64) error
 	GetSummary() (*entity.APIAccessLogSummary, error)
 	GetStatsByEndpoint() ([]entity.APIAccessLogStats, error)
-	FindWithFilters(page, limit int, startDate, endDate *time.Time, endpoint, ip string) ([]entity.APIAccessLog, int64, error)
+	FindWithFilters(page, limit int, startDate, endDate *time.Time, endpoint, ip string, apiKeyID uint) ([]entity.APIAccessLog, int64, error)
 	ClearOldLogs(days int) error
 }
 
@@ -131,7 +131,7 @@ func (r *APIAccessLogRepositoryImpl) GetStatsByEndpoint() ([]entity.APIAccessLog
 }
 
 // FindWithFilters 带过滤条件的分页查找访问日志
-func (r *APIAccessLogRepositoryImpl) FindWithFilters(page, limit int, startDate, endDate *time.Time, endpoint, ip string) ([]entity.APIAccessLog, int64, error) {
+func (r *APIAccessLogRepositoryImpl) FindWithFilters(page, limit int, startDate, endDate *time.Time, endpoint, ip string, apiKeyID uint) ([]entity.APIAccessLog, int64, error) {
 	var logs []entity.APIAccessLog
 	var total int64
 
@@ -151,6 +151,9 @@ func (r *APIAccessLogRepositoryImpl) FindWithFilters(page, limit int, startDate,
 	if ip != "" {
 		query = query.Where("ip = ?", ip)
 	}
+	if apiKeyID > 0 {
+		query = query.Where("api_key_id = ?", apiKeyID)
+	}
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
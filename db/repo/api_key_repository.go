@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository 公开API密钥Repository接口
+type APIKeyRepository interface {
+	BaseRepository[entity.APIKey]
+	FindByPrefix(prefix string) (*entity.APIKey, error)
+	FindAllOrdered() ([]entity.APIKey, error)
+	TouchUsage(id uint, ip string, at time.Time) error
+	IncrementUsage(id uint, day string, quota int) (bool, error)
+	IncrementRejected(id uint, day string) error
+	FindDailyUsage(id uint, from string) ([]entity.APIKeyDailyUsage, error)
+}
+
+// APIKeyRepositoryImpl 公开API密钥Repository实现
+type APIKeyRepositoryImpl struct {
+	BaseRepositoryImpl[entity.APIKey]
+}
+
+// NewAPIKeyRepository 创建公开API密钥Repository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.APIKey]{db: db},
+	}
+}
+
+// FindByPrefix 根据密钥前缀查找
+func (r *APIKeyRepositoryImpl) FindByPrefix(prefix string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindAllOrdered 按创建时间倒序查找所有密钥
+func (r *APIKeyRepositoryImpl) FindAllOrdered() ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := r.db.Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// TouchUsage 更新最后使用时间、IP并累计请求数
+func (r *APIKeyRepositoryImpl) TouchUsage(id uint, ip string, at time.Time) error {
+	return r.db.Model(&entity.APIKey{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"last_used_at": at,
+		"last_used_ip": ip,
+		"usage_count":  gorm.Expr("usage_count + 1"),
+	}).Error
+}
+
+// IncrementUsage 原子地累计当日放行请求数，quota 大于 0 且当日已达上限时不累计并返回 false
+func (r *APIKeyRepositoryImpl) IncrementUsage(id uint, day string, quota int) (bool, error) {
+	var counts []int64
+	err := r.db.Raw(`INSERT INTO api_key_daily_usages (api_key_id, date, requests, rejected)
+		VALUES (?, ?::date, 1, 0)
+		ON CONFLICT (api_key_id, date) DO UPDATE SET requests = api_key_daily_usages.requests + 1
+		WHERE ? = 0 OR api_key_daily_usages.requests < ?
+		RETURNING requests`, id, day, quota, quota).Scan(&counts).Error
+	if err != nil {
+		return false, err
+	}
+	return len(counts) > 0, nil
+}
+
+// IncrementRejected 累计当日被拒绝请求数
+func (r *APIKeyRepositoryImpl) IncrementRejected(id uint, day string) error {
+	return r.db.Exec(`INSERT INTO api_key_daily_usages (api_key_id, date, requests, rejected)
+		VALUES (?, ?::date, 0, 1)
+		ON CONFLICT (api_key_id, date) DO UPDATE SET rejected = api_key_daily_usages.rejected + 1`, id, day).Error
+}
+
+// FindDailyUsage 查找指定日期（含）之后的每日用量，按日期升序
+func (r *APIKeyRepositoryImpl) FindDailyUsage(id uint, from string) ([]entity.APIKeyDailyUsage, error) {
+	var usages []entity.APIKeyDailyUsage
+	err := r.db.Where("api_key_id = ? AND date >= ?::date", id, from).Order("date ASC").Find(&usages).Error
+	return usages, err
+}
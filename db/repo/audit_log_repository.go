@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 审计日志Repository接口
+type AuditLogRepository interface {
+	BaseRepository[entity.AuditLog]
+	Search(filter entity.AuditLogFilter, page, pageSize int) ([]entity.AuditLog, int64, error)
+	FindForExport(filter entity.AuditLogFilter, limit int) ([]entity.AuditLog, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// AuditLogRepositoryImpl 审计日志Repository实现
+type AuditLogRepositoryImpl struct {
+	BaseRepositoryImpl[entity.AuditLog]
+}
+
+// NewAuditLogRepository 创建审计日志Repository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.AuditLog]{db: db},
+	}
+}
+
+func (r *AuditLogRepositoryImpl) filtered(filter entity.AuditLogFilter) *gorm.DB {
+	query := r.db.Model(&entity.AuditLog{})
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Username != "" {
+		query = query.Where("username = ?", filter.Username)
+	}
+	if filter.Action != "" {
+		// 支持按前缀查询，如 cks 匹配 cks.update、cks.delete
+		query = query.Where("(action = ? OR action LIKE ?)", filter.Action, filter.Action+".%")
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at < ?", *filter.EndTime)
+	}
+	return query
+}
+
+// Search 分页查询审计日志
+func (r *AuditLogRepositoryImpl) Search(filter entity.AuditLogFilter, page, pageSize int) ([]entity.AuditLog, int64, error) {
+	var logs []entity.AuditLog
+	var total int64
+
+	query := r.filtered(filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error
+	return logs, total, err
+}
+
+// FindForExport 按条件查询最近的审计日志用于导出，最多 limit 条
+func (r *AuditLogRepositoryImpl) FindForExport(filter entity.AuditLogFilter, limit int) ([]entity.AuditLog, error) {
+	var logs []entity.AuditLog
+	err := r.filtered(filter).Order("created_at DESC, id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// DeleteBefore 删除指定时间之前的记录
+func (r *AuditLogRepositoryImpl) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.AuditLog{})
+	return result.RowsAffected, result.Error
+}
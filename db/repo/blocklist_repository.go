@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// BlocklistRuleRepository 黑名单规则Repository接口
+type BlocklistRuleRepository interface {
+	BaseRepository[entity.BlocklistRule]
+	FindEnabled() ([]entity.BlocklistRule, error)
+	Search(kind, action, source, keyword string, page, pageSize int) ([]entity.BlocklistRule, int64, error)
+	CreateBatch(rules []entity.BlocklistRule) error
+	Save(rule *entity.BlocklistRule) error
+	DeleteBySource(source string, sourceID uint) error
+	RecordHits(ids []uint, hitAt time.Time) error
+}
+
+// BlocklistRuleRepositoryImpl 黑名单规则Repository实现
+type BlocklistRuleRepositoryImpl struct {
+	BaseRepositoryImpl[entity.BlocklistRule]
+}
+
+// NewBlocklistRuleRepository 创建黑名单规则Repository
+func NewBlocklistRuleRepository(db *gorm.DB) BlocklistRuleRepository {
+	return &BlocklistRuleRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.BlocklistRule]{db: db},
+	}
+}
+
+// FindEnabled 查找全部启用的规则
+func (r *BlocklistRuleRepositoryImpl) FindEnabled() ([]entity.BlocklistRule, error) {
+	var rules []entity.BlocklistRule
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// Search 分页查询规则
+func (r *BlocklistRuleRepositoryImpl) Search(kind, action, source, keyword string, page, pageSize int) ([]entity.BlocklistRule, int64, error) {
+	var rules []entity.BlocklistRule
+	var total int64
+
+	query := r.db.Model(&entity.BlocklistRule{})
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("pattern ILIKE ? OR note ILIKE ?", like, like)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&rules).Error
+	return rules, total, err
+}
+
+// CreateBatch 批量创建规则
+func (r *BlocklistRuleRepositoryImpl) CreateBatch(rules []entity.BlocklistRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	return r.db.Create(&rules).Error
+}
+
+// Save 保存全部字段（包括零值）
+func (r *BlocklistRuleRepositoryImpl) Save(rule *entity.BlocklistRule) error {
+	return r.db.Save(rule).Error
+}
+
+// DeleteBySource 删除来源生成的规则
+func (r *BlocklistRuleRepositoryImpl) DeleteBySource(source string, sourceID uint) error {
+	return r.db.Where("source = ? AND source_id = ?", source, sourceID).Delete(&entity.BlocklistRule{}).Error
+}
+
+// RecordHits 累加命中次数并更新最近命中时间
+func (r *BlocklistRuleRepositoryImpl) RecordHits(ids []uint, hitAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.BlocklistRule{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"hit_count":   gorm.Expr("hit_count + 1"),
+		"last_hit_at": hitAt,
+	}).Error
+}
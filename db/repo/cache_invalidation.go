@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ctwj/urldb/pkg/cache"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cacheTagKinds 表名与缓存标签类型的对应关系，只有被缓存的数据需要登记
+var cacheTagKinds = map[string]string{
+	"resources":      "resource",
+	"resource_tags":  "resource",
+	"categories":     "category",
+	"tags":           "tag",
+	"pans":           "pan",
+	"system_configs": "config",
+}
+
+// cacheCollectionTables 主键不是对应类型 ID 的表（如关联表），写入时总是失效整个集合
+var cacheCollectionTables = map[string]bool{
+	"resource_tags": true,
+}
+
+// cacheIgnoredColumns 只更新这些列时不失效缓存（如浏览量计数），缓存中的计数在过期后刷新
+var cacheIgnoredColumns = map[string]bool{
+	"view_count": true,
+	"updated_at": true,
+}
+
+const cacheInvalidationCallback = "urldb:cache_invalidation"
+
+// maxCacheTagsPerStatement 单条语句影响的记录超过该数量时直接失效整个集合
+const maxCacheTagsPerStatement = 50
+
+// registerCacheInvalidation 注册写库后的缓存失效回调：
+// 能取到主键时失效 "类型:ID"（连带该类型的列表），否则失效 "类型:*"
+func registerCacheInvalidation(db *gorm.DB) {
+	callbacks := db.Callback()
+	if callbacks.Create().Get(cacheInvalidationCallback) != nil {
+		return
+	}
+	_ = callbacks.Create().After("gorm:create").Register(cacheInvalidationCallback, invalidateCacheAfterWrite)
+	_ = callbacks.Update().After("gorm:update").Register(cacheInvalidationCallback, invalidateCacheAfterWrite)
+	_ = callbacks.Delete().After("gorm:delete").Register(cacheInvalidationCallback, invalidateCacheAfterWrite)
+}
+
+func invalidateCacheAfterWrite(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	table := db.Statement.Schema.Table
+	kind, ok := cacheTagKinds[table]
+	if !ok {
+		return
+	}
+	if onlyIgnoredColumns(db) {
+		return
+	}
+	if cacheCollectionTables[table] {
+		cache.Default().Invalidate(context.Background(), cache.AllTag(kind))
+		return
+	}
+	cache.Default().Invalidate(context.Background(), statementCacheTags(db, kind)...)
+}
+
+// onlyIgnoredColumns 判断更新语句是否只修改了计数类的列
+func onlyIgnoredColumns(db *gorm.DB) bool {
+	c, ok := db.Statement.Clauses["SET"]
+	if !ok {
+		return false
+	}
+	set, ok := c.Expression.(clause.Set)
+	if !ok || len(set) == 0 {
+		return false
+	}
+	for _, assignment := range set {
+		if !cacheIgnoredColumns[assignment.Column.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// statementCacheTags 从语句的目标对象中取主键生成标签
+func statementCacheTags(db *gorm.DB, kind string) []string {
+	all := []string{cache.AllTag(kind)}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || !db.Statement.ReflectValue.IsValid() {
+		return all
+	}
+
+	ctx := db.Statement.Context
+	rv := db.Statement.ReflectValue
+	switch {
+	case rv.Kind() == reflect.Struct:
+		if id, zero := field.ValueOf(ctx, rv); !zero {
+			return []string{cache.Tag(kind, id)}
+		}
+	case rv.Kind() == reflect.Slice && rv.Len() > 0 && rv.Len() <= maxCacheTagsPerStatement:
+		tags := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			id, zero := field.ValueOf(ctx, reflect.Indirect(rv.Index(i)))
+			if zero {
+				return all
+			}
+			tags = append(tags, cache.Tag(kind, id))
+		}
+		return tags
+	}
+	return all
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClassificationRuleRepository 自动分类规则Repository接口
+type ClassificationRuleRepository interface {
+	BaseRepository[entity.ClassificationRule]
+	FindEnabled() ([]entity.ClassificationRule, error)
+	FindAllWithTargets() ([]entity.ClassificationRule, error)
+	IncrementHitCount(ids []uint) error
+}
+
+// ClassificationRuleRepositoryImpl 自动分类规则Repository实现
+type ClassificationRuleRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ClassificationRule]
+}
+
+// NewClassificationRuleRepository 创建自动分类规则Repository
+func NewClassificationRuleRepository(db *gorm.DB) ClassificationRuleRepository {
+	return &ClassificationRuleRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ClassificationRule]{db: db},
+	}
+}
+
+// FindEnabled 获取启用的规则（含目标分类/标签）
+func (r *ClassificationRuleRepositoryImpl) FindEnabled() ([]entity.ClassificationRule, error) {
+	var rules []entity.ClassificationRule
+	err := r.db.Preload("Category").Preload("Tag").Where("enabled = ?", true).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindAllWithTargets 获取全部规则（含目标分类/标签）
+func (r *ClassificationRuleRepositoryImpl) FindAllWithTargets() ([]entity.ClassificationRule, error) {
+	var rules []entity.ClassificationRule
+	err := r.db.Preload("Category").Preload("Tag").Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// IncrementHitCount 累加规则命中次数
+func (r *ClassificationRuleRepositoryImpl) IncrementHitCount(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&entity.ClassificationRule{}).Where("id IN ?", ids).
+		UpdateColumn("hit_count", gorm.Expr("hit_count + 1")).Error
+}
+
+// ClassificationReviewRepository 分类审核队列Repository接口
+type ClassificationReviewRepository interface {
+	BaseRepository[entity.ClassificationReview]
+	FindByStatus(status string, page, pageSize int) ([]entity.ClassificationReview, int64, error)
+	FindPendingByResourceID(resourceID uint) (*entity.ClassificationReview, error)
+	CountPending() (int64, error)
+}
+
+// ClassificationReviewRepositoryImpl 分类审核队列Repository实现
+type ClassificationReviewRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ClassificationReview]
+}
+
+// NewClassificationReviewRepository 创建分类审核队列Repository
+func NewClassificationReviewRepository(db *gorm.DB) ClassificationReviewRepository {
+	return &ClassificationReviewRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ClassificationReview]{db: db},
+	}
+}
+
+// FindByStatus 按状态分页查询，status 为空时查询全部
+func (r *ClassificationReviewRepositoryImpl) FindByStatus(status string, page, pageSize int) ([]entity.ClassificationReview, int64, error) {
+	query := r.db.Model(&entity.ClassificationReview{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []entity.ClassificationReview
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// FindPendingByResourceID 获取资源待审核的记录
+func (r *ClassificationReviewRepositoryImpl) FindPendingByResourceID(resourceID uint) (*entity.ClassificationReview, error) {
+	var review entity.ClassificationReview
+	err := r.db.Where("resource_id = ? AND status = ?", resourceID, entity.ClassificationReviewPending).First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// CountPending 待审核数量
+func (r *ClassificationReviewRepositoryImpl) CountPending() (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.ClassificationReview{}).Where("status = ?", entity.ClassificationReviewPending).Count(&count).Error
+	return count, err
+}
+
+// ClassifierModelRepository 本地分类模型Repository接口
+type ClassifierModelRepository interface {
+	BaseRepository[entity.ClassifierModel]
+	FindByName(name string) (*entity.ClassifierModel, error)
+	Save(model *entity.ClassifierModel) error
+}
+
+// ClassifierModelRepositoryImpl 本地分类模型Repository实现
+type ClassifierModelRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ClassifierModel]
+}
+
+// NewClassifierModelRepository 创建本地分类模型Repository
+func NewClassifierModelRepository(db *gorm.DB) ClassifierModelRepository {
+	return &ClassifierModelRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ClassifierModel]{db: db},
+	}
+}
+
+// FindByName 根据名称获取模型
+func (r *ClassifierModelRepositoryImpl) FindByName(name string) (*entity.ClassifierModel, error) {
+	var model entity.ClassifierModel
+	err := r.db.Where("name = ?", name).First(&model).Error
+	if err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Save 按名称创建或覆盖模型
+func (r *ClassifierModelRepositoryImpl) Save(model *entity.ClassifierModel) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"model", "samples", "labels", "vocab_size", "trained_at", "updated_at"}),
+	}).Create(model).Error
+}
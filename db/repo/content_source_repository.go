@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ContentSourceRepository 内容源Repository接口
+type ContentSourceRepository interface {
+	BaseRepository[entity.ContentSource]
+	FindEnabled() ([]entity.ContentSource, error)
+	FindDue(now time.Time) ([]entity.ContentSource, error)
+	UpdateRunResult(id uint, runAt time.Time, cursor string, fetched, created int, lastError string) error
+	FilterNewItemHashes(sourceID uint, hashes []string) ([]string, error)
+	CreateItems(items []entity.ContentSourceItem) error
+	DeleteItemsBySourceID(sourceID uint) error
+}
+
+// ContentSourceRepositoryImpl 内容源Repository实现
+type ContentSourceRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ContentSource]
+}
+
+// NewContentSourceRepository 创建内容源Repository
+func NewContentSourceRepository(db *gorm.DB) ContentSourceRepository {
+	return &ContentSourceRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ContentSource]{db: db},
+	}
+}
+
+// FindEnabled 查找所有启用的内容源
+func (r *ContentSourceRepositoryImpl) FindEnabled() ([]entity.ContentSource, error) {
+	var sources []entity.ContentSource
+	err := r.db.Where("enabled = ?", true).Order("id ASC").Find(&sources).Error
+	return sources, err
+}
+
+// FindDue 查找已到抓取时间的启用内容源（从未抓取或距上次抓取已超过间隔）
+func (r *ContentSourceRepositoryImpl) FindDue(now time.Time) ([]entity.ContentSource, error) {
+	sources, err := r.FindEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	var due []entity.ContentSource
+	for _, s := range sources {
+		interval := s.IntervalMinutes
+		if interval <= 0 {
+			interval = 60
+		}
+		if s.LastRunAt == nil || !now.Before(s.LastRunAt.Add(time.Duration(interval)*time.Minute)) {
+			due = append(due, s)
+		}
+	}
+	return due, nil
+}
+
+// UpdateRunResult 记录一次抓取结果；cursor 为空时保留原游标
+func (r *ContentSourceRepositoryImpl) UpdateRunResult(id uint, runAt time.Time, cursor string, fetched, created int, lastError string) error {
+	updates := map[string]interface{}{
+		"last_run_at":        runAt,
+		"last_error":         lastError,
+		"last_fetched_count": fetched,
+		"last_created_count": created,
+		"total_created":      gorm.Expr("total_created + ?", created),
+	}
+	if cursor != "" {
+		updates["cursor"] = cursor
+	}
+	return r.db.Model(&entity.ContentSource{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// FilterNewItemHashes 返回尚未处理过的条目哈希（保持入参顺序）
+func (r *ContentSourceRepositoryImpl) FilterNewItemHashes(sourceID uint, hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	var existing []string
+	if err := r.db.Model(&entity.ContentSourceItem{}).
+		Where("source_id = ? AND item_hash IN ?", sourceID, hashes).
+		Pluck("item_hash", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, h := range existing {
+		seen[h] = true
+	}
+	var fresh []string
+	for _, h := range hashes {
+		if !seen[h] {
+			seen[h] = true
+			fresh = append(fresh, h)
+		}
+	}
+	return fresh, nil
+}
+
+// CreateItems 记录已处理条目，重复条目忽略
+func (r *ContentSourceRepositoryImpl) CreateItems(items []entity.ContentSourceItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(items, 100).Error
+}
+
+// DeleteItemsBySourceID 清空内容源的去重记录（用于重置游标后全量重抓）
+func (r *ContentSourceRepositoryImpl) DeleteItemsBySourceID(sourceID uint) error {
+	return r.db.Where("source_id = ?", sourceID).Delete(&entity.ContentSourceItem{}).Error
+}
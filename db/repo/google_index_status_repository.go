@@ -0,0 +1,231 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+)
+
+// visibleResourceWhere 可被搜索引擎收录的资源：有效、公开、未删除且有详情页
+const visibleResourceWhere = "r.is_valid = true AND r.is_public = true AND r.deleted_at IS NULL AND r.key <> ''"
+
+// GoogleIndexCoverageRow 按维度汇总的索引覆盖率
+type GoogleIndexCoverageRow struct {
+	GroupID           string `json:"group_id"`
+	GroupName         string `json:"group_name"`
+	Total             int64  `json:"total"`
+	Indexed           int64  `json:"indexed"`
+	NotIndexed        int64  `json:"not_indexed"`
+	CrawledNotIndexed int64  `json:"crawled_not_indexed"`
+	NeverInspected    int64  `json:"never_inspected"`
+	Errors            int64  `json:"errors"`
+}
+
+// GoogleIndexStateCount 按覆盖状态统计的数量
+type GoogleIndexStateCount struct {
+	CoverageState string `json:"coverage_state"`
+	Count         int64  `json:"count"`
+}
+
+// GoogleIndexStatusRepository Google索引状态Repository接口
+type GoogleIndexStatusRepository interface {
+	BaseRepository[entity.GoogleIndexStatus]
+	SyncResources(siteURL string) (int64, error)
+	FindForInspection(staleBefore time.Time, limit int) ([]entity.GoogleIndexStatus, error)
+	CountInspectedSince(since time.Time) (int64, error)
+	RecordInspection(url string, inspection entity.GoogleIndexInspection) error
+	FindResubmitCandidates(limit int) ([]entity.GoogleIndexStatus, error)
+	FindByResourceIDs(resourceIDs []uint) ([]entity.GoogleIndexStatus, error)
+	MarkResubmitted(url string, at time.Time) error
+	Coverage(groupBy string, since *time.Time) ([]GoogleIndexCoverageRow, error)
+	CountByCoverageState() ([]GoogleIndexStateCount, error)
+	FindWithFilters(verdict, coverageState, keyword string, page, pageSize int) ([]entity.GoogleIndexStatus, int64, error)
+}
+
+// GoogleIndexStatusRepositoryImpl Google索引状态Repository实现
+type GoogleIndexStatusRepositoryImpl struct {
+	BaseRepositoryImpl[entity.GoogleIndexStatus]
+}
+
+// NewGoogleIndexStatusRepository 创建Google索引状态Repository
+func NewGoogleIndexStatusRepository(db *gorm.DB) GoogleIndexStatusRepository {
+	return &GoogleIndexStatusRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.GoogleIndexStatus]{db: db},
+	}
+}
+
+// SyncResources 为尚无记录的可收录资源创建状态记录，并在站点地址变化时更新已有记录的URL，返回新增数量
+func (r *GoogleIndexStatusRepositoryImpl) SyncResources(siteURL string) (int64, error) {
+	var created int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO google_index_statuses (resource_id, url, resubmit_count, created_at, updated_at)
+			SELECT r.id, ? || '/r/' || r.key, 0, NOW(), NOW() FROM resources r
+			WHERE `+visibleResourceWhere+`
+			AND NOT EXISTS (SELECT 1 FROM google_index_statuses s WHERE s.resource_id = r.id)`, siteURL)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected
+
+		return tx.Exec(`UPDATE google_index_statuses s SET url = ? || '/r/' || r.key, updated_at = NOW()
+			FROM resources r
+			WHERE s.resource_id = r.id AND r.key <> '' AND s.url <> ? || '/r/' || r.key`, siteURL, siteURL).Error
+	})
+	return created, err
+}
+
+// FindForInspection 查找待检查的记录：从未检查的优先，其次是检查后资源有更新的，最后是检查结果已过期的
+func (r *GoogleIndexStatusRepositoryImpl) FindForInspection(staleBefore time.Time, limit int) ([]entity.GoogleIndexStatus, error) {
+	var list []entity.GoogleIndexStatus
+	err := r.db.Table("google_index_statuses s").
+		Select("s.*").
+		Joins("JOIN resources r ON r.id = s.resource_id").
+		Where(visibleResourceWhere).
+		Where("s.inspected_at IS NULL OR s.inspected_at < ? OR r.updated_at > s.inspected_at", staleBefore).
+		Order("CASE WHEN s.inspected_at IS NULL THEN 0 WHEN r.updated_at > s.inspected_at THEN 1 ELSE 2 END, s.inspected_at ASC NULLS FIRST, s.id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// CountInspectedSince 统计某时间之后检查过的URL数，用于计算当日已用配额
+func (r *GoogleIndexStatusRepositoryImpl) CountInspectedSince(since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.GoogleIndexStatus{}).
+		Where("inspected_at >= ?", since).
+		Distinct("url").
+		Count(&count).Error
+	return count, err
+}
+
+// RecordInspection 将检查结果写入所有使用该URL的记录
+func (r *GoogleIndexStatusRepositoryImpl) RecordInspection(url string, inspection entity.GoogleIndexInspection) error {
+	return r.db.Model(&entity.GoogleIndexStatus{}).
+		Where("url = ?", url).
+		Updates(map[string]interface{}{
+			"verdict":          inspection.Verdict,
+			"coverage_state":   inspection.CoverageState,
+			"indexing_state":   inspection.IndexingState,
+			"page_fetch_state": inspection.PageFetchState,
+			"last_crawled_at":  inspection.LastCrawledAt,
+			"inspected_at":     inspection.InspectedAt,
+			"error_message":    inspection.ErrorMessage,
+		}).Error
+}
+
+// FindResubmitCandidates 查找已抓取未索引、且在检查（或上次重新提交）之后有更新的记录
+func (r *GoogleIndexStatusRepositoryImpl) FindResubmitCandidates(limit int) ([]entity.GoogleIndexStatus, error) {
+	var list []entity.GoogleIndexStatus
+	err := r.db.Table("google_index_statuses s").
+		Select("s.*").
+		Joins("JOIN resources r ON r.id = s.resource_id").
+		Where(visibleResourceWhere).
+		Where("s.coverage_state = ?", entity.GoogleIndexCoverageCrawledNotIndexed).
+		Where("r.updated_at > s.inspected_at").
+		Where("s.resubmitted_at IS NULL OR r.updated_at > s.resubmitted_at").
+		Order("r.updated_at DESC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// FindByResourceIDs 根据资源ID查找记录
+func (r *GoogleIndexStatusRepositoryImpl) FindByResourceIDs(resourceIDs []uint) ([]entity.GoogleIndexStatus, error) {
+	var list []entity.GoogleIndexStatus
+	if len(resourceIDs) == 0 {
+		return list, nil
+	}
+	err := r.db.Where("resource_id IN ?", resourceIDs).Find(&list).Error
+	return list, err
+}
+
+// MarkResubmitted 记录URL已重新提交
+func (r *GoogleIndexStatusRepositoryImpl) MarkResubmitted(url string, at time.Time) error {
+	return r.db.Model(&entity.GoogleIndexStatus{}).
+		Where("url = ?", url).
+		Updates(map[string]interface{}{
+			"resubmitted_at": at,
+			"resubmit_count": gorm.Expr("resubmit_count + 1"),
+		}).Error
+}
+
+// Coverage 按分类（category）、网盘（pan）或资源创建日期（date）汇总索引覆盖率，groupBy 为空时返回总体
+func (r *GoogleIndexStatusRepositoryImpl) Coverage(groupBy string, since *time.Time) ([]GoogleIndexCoverageRow, error) {
+	query := r.db.Table("resources r").
+		Joins("LEFT JOIN google_index_statuses s ON s.resource_id = r.id").
+		Where(visibleResourceWhere)
+	if since != nil {
+		query = query.Where("r.created_at >= ?", *since)
+	}
+
+	aggregates := `COUNT(*) AS total,
+		COALESCE(SUM(CASE WHEN s.verdict = ? THEN 1 ELSE 0 END), 0) AS indexed,
+		COALESCE(SUM(CASE WHEN s.inspected_at IS NOT NULL AND COALESCE(s.error_message, '') = '' AND s.verdict <> ? THEN 1 ELSE 0 END), 0) AS not_indexed,
+		COALESCE(SUM(CASE WHEN s.coverage_state = ? THEN 1 ELSE 0 END), 0) AS crawled_not_indexed,
+		COALESCE(SUM(CASE WHEN s.inspected_at IS NULL THEN 1 ELSE 0 END), 0) AS never_inspected,
+		COALESCE(SUM(CASE WHEN COALESCE(s.error_message, '') <> '' THEN 1 ELSE 0 END), 0) AS errors`
+	args := []interface{}{entity.GoogleIndexVerdictPass, entity.GoogleIndexVerdictPass, entity.GoogleIndexCoverageCrawledNotIndexed}
+
+	switch groupBy {
+	case "category":
+		query = query.Joins("LEFT JOIN categories c ON c.id = r.category_id").
+			Select("COALESCE(CAST(r.category_id AS TEXT), '') AS group_id, COALESCE(MAX(c.name), '未分类') AS group_name, "+aggregates, args...).
+			Group("r.category_id").
+			Order("total DESC")
+	case "pan":
+		query = query.Joins("LEFT JOIN pan p ON p.id = r.pan_id").
+			Select("COALESCE(CAST(r.pan_id AS TEXT), '') AS group_id, COALESCE(MAX(p.remark), MAX(p.name), '未知') AS group_name, "+aggregates, args...).
+			Group("r.pan_id").
+			Order("total DESC")
+	case "date":
+		query = query.Select("TO_CHAR(DATE(r.created_at), 'YYYY-MM-DD') AS group_id, TO_CHAR(DATE(r.created_at), 'YYYY-MM-DD') AS group_name, "+aggregates, args...).
+			Group("DATE(r.created_at)").
+			Order("group_id DESC")
+	default:
+		query = query.Select("'all' AS group_id, '全部' AS group_name, "+aggregates, args...)
+	}
+
+	var rows []GoogleIndexCoverageRow
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+// CountByCoverageState 按覆盖状态统计已检查的记录
+func (r *GoogleIndexStatusRepositoryImpl) CountByCoverageState() ([]GoogleIndexStateCount, error) {
+	var rows []GoogleIndexStateCount
+	err := r.db.Model(&entity.GoogleIndexStatus{}).
+		Select("coverage_state, COUNT(*) AS count").
+		Where("inspected_at IS NOT NULL").
+		Group("coverage_state").
+		Order("count DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// FindWithFilters 分页查询索引状态
+func (r *GoogleIndexStatusRepositoryImpl) FindWithFilters(verdict, coverageState, keyword string, page, pageSize int) ([]entity.GoogleIndexStatus, int64, error) {
+	var list []entity.GoogleIndexStatus
+	var total int64
+
+	query := r.db.Model(&entity.GoogleIndexStatus{})
+	switch verdict {
+	case "":
+	case "uninspected":
+		query = query.Where("inspected_at IS NULL")
+	default:
+		query = query.Where("verdict = ?", verdict)
+	}
+	if coverageState != "" {
+		query = query.Where("coverage_state = ?", coverageState)
+	}
+	if keyword != "" {
+		query = query.Where("url ILIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("inspected_at DESC NULLS LAST, id DESC").Offset(offset).Limit(pageSize).Find(&list).Error
+	return list, total, err
+}
@@ -14,6 +14,7 @@ type HotDramaRepository interface {
 	FindByCategory(category string, page, pageSize int) ([]entity.HotDrama, int64, error)
 	FindByCategoryAndSubType(category, subType string, page, pageSize int) ([]entity.HotDrama, int64, error)
 	FindByDoubanID(doubanID string) (*entity.HotDrama, error)
+	FindByTitle(title string) (*entity.HotDrama, error)
 	Upsert(drama *entity.HotDrama) error
 	Delete(id uint) error
 	DeleteByDoubanID(doubanID string) error
@@ -110,6 +111,16 @@ func (r *hotDramaRepository) FindByCategoryAndSubType(category, subType string,
 	return dramas, total, nil
 }
 
+// FindByTitle 根据剧名查找热播剧（优先返回有海报的最新记录）
+func (r *hotDramaRepository) FindByTitle(title string) (*entity.HotDrama, error) {
+	var drama entity.HotDrama
+	err := r.db.Where("title = ?", title).Order("poster_url = '' ASC, id DESC").First(&drama).Error
+	if err != nil {
+		return nil, err
+	}
+	return &drama, nil
+}
+
 // FindByDoubanID 根据豆瓣ID查找热播剧
 func (r *hotDramaRepository) FindByDoubanID(doubanID string) (*entity.HotDrama, error) {
 	var drama entity.HotDrama
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// UserTwoFactorRepository 两步验证Repository接口
+type UserTwoFactorRepository interface {
+	BaseRepository[entity.UserTwoFactor]
+	FindByUserID(userID uint) (*entity.UserTwoFactor, error)
+	Save(twoFactor *entity.UserTwoFactor) error
+	DeleteByUserID(userID uint) error
+	AdvanceStep(id uint, step int64) (bool, error)
+}
+
+// UserTwoFactorRepositoryImpl 两步验证Repository实现
+type UserTwoFactorRepositoryImpl struct {
+	BaseRepositoryImpl[entity.UserTwoFactor]
+}
+
+// NewUserTwoFactorRepository 创建两步验证Repository
+func NewUserTwoFactorRepository(db *gorm.DB) UserTwoFactorRepository {
+	return &UserTwoFactorRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.UserTwoFactor]{db: db},
+	}
+}
+
+// FindByUserID 根据用户ID查找
+func (r *UserTwoFactorRepositoryImpl) FindByUserID(userID uint) (*entity.UserTwoFactor, error) {
+	var twoFactor entity.UserTwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error
+	if err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// Save 保存全部字段（包括零值）
+func (r *UserTwoFactorRepositoryImpl) Save(twoFactor *entity.UserTwoFactor) error {
+	return r.db.Save(twoFactor).Error
+}
+
+// DeleteByUserID 删除用户的两步验证设置
+func (r *UserTwoFactorRepositoryImpl) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{}).Error
+}
+
+// AdvanceStep 仅当 step 大于已记录的时间步时更新，返回 false 表示验证码已被使用
+func (r *UserTwoFactorRepositoryImpl) AdvanceStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&entity.UserTwoFactor{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		UpdateColumn("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// LoginHistoryRepository 登录记录Repository接口
+type LoginHistoryRepository interface {
+	BaseRepository[entity.LoginHistory]
+	CountFailuresByUsername(username string, since time.Time) (int64, *time.Time, error)
+	CountFailuresByIP(ip string, since time.Time) (int64, *time.Time, error)
+	LastSuccessAt(username string) (*time.Time, error)
+	CountSuccessFromIP(userID uint, ip string) (int64, error)
+	Search(userID uint, username, ip string, success *bool, page, pageSize int) ([]entity.LoginHistory, int64, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// LoginHistoryRepositoryImpl 登录记录Repository实现
+type LoginHistoryRepositoryImpl struct {
+	BaseRepositoryImpl[entity.LoginHistory]
+}
+
+// NewLoginHistoryRepository 创建登录记录Repository
+func NewLoginHistoryRepository(db *gorm.DB) LoginHistoryRepository {
+	return &LoginHistoryRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.LoginHistory]{db: db},
+	}
+}
+
+// CountFailuresByUsername 统计用户名自 since 起的失败次数及最近一次失败时间
+func (r *LoginHistoryRepositoryImpl) CountFailuresByUsername(username string, since time.Time) (int64, *time.Time, error) {
+	return r.countFailures("username = ?", username, since)
+}
+
+// CountFailuresByIP 统计IP自 since 起的失败次数及最近一次失败时间
+func (r *LoginHistoryRepositoryImpl) CountFailuresByIP(ip string, since time.Time) (int64, *time.Time, error) {
+	return r.countFailures("ip = ?", ip, since)
+}
+
+func (r *LoginHistoryRepositoryImpl) countFailures(cond, value string, since time.Time) (int64, *time.Time, error) {
+	var result struct {
+		Count  int64
+		Latest *time.Time
+	}
+	err := r.db.Model(&entity.LoginHistory{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS latest").
+		Where(cond, value).
+		Where("success = ? AND created_at >= ?", false, since).
+		Scan(&result).Error
+	return result.Count, result.Latest, err
+}
+
+// LastSuccessAt 用户名最近一次成功登录时间，没有时返回 nil
+func (r *LoginHistoryRepositoryImpl) LastSuccessAt(username string) (*time.Time, error) {
+	var latest *time.Time
+	err := r.db.Model(&entity.LoginHistory{}).
+		Select("MAX(created_at)").
+		Where("username = ? AND success = ?", username, true).
+		Scan(&latest).Error
+	return latest, err
+}
+
+// CountSuccessFromIP 统计用户从该IP成功登录的次数
+func (r *LoginHistoryRepositoryImpl) CountSuccessFromIP(userID uint, ip string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.LoginHistory{}).
+		Where("user_id = ? AND ip = ? AND success = ?", userID, ip, true).
+		Count(&count).Error
+	return count, err
+}
+
+// Search 分页查询登录记录
+func (r *LoginHistoryRepositoryImpl) Search(userID uint, username, ip string, success *bool, page, pageSize int) ([]entity.LoginHistory, int64, error) {
+	var histories []entity.LoginHistory
+	var total int64
+
+	query := r.db.Model(&entity.LoginHistory{})
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if ip != "" {
+		query = query.Where("ip = ?", ip)
+	}
+	if success != nil {
+		query = query.Where("success = ?", *success)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&histories).Error
+	return histories, total, err
+}
+
+// DeleteBefore 删除指定时间之前的记录
+func (r *LoginHistoryRepositoryImpl) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.LoginHistory{})
+	return result.RowsAffected, result.Error
+}
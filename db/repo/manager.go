@@ -6,53 +6,99 @@ import (
 
 // RepositoryManager Repository管理器
 type RepositoryManager struct {
-	PanRepository             PanRepository
-	CksRepository             CksRepository
-	ResourceRepository        ResourceRepository
-	CategoryRepository        CategoryRepository
-	TagRepository             TagRepository
-	ReadyResourceRepository   ReadyResourceRepository
-	UserRepository            UserRepository
-	SearchStatRepository      SearchStatRepository
-	SystemConfigRepository    SystemConfigRepository
-	HotDramaRepository        HotDramaRepository
-	ResourceViewRepository    ResourceViewRepository
-	TaskRepository            TaskRepository
-	TaskItemRepository        TaskItemRepository
-	FileRepository            FileRepository
-	TelegramChannelRepository TelegramChannelRepository
-	APIAccessLogRepository    APIAccessLogRepository
-	ReportRepository          ReportRepository
-	CopyrightClaimRepository  CopyrightClaimRepository
-	PluginConfigRepository    *PluginConfigRepository
-	PluginLogRepository       *PluginLogRepository
-	CronJobRepository         *CronJobRepository
+	PanRepository                    PanRepository
+	CksRepository                    CksRepository
+	ResourceRepository               ResourceRepository
+	CategoryRepository               CategoryRepository
+	TagRepository                    TagRepository
+	ReadyResourceRepository          ReadyResourceRepository
+	UserRepository                   UserRepository
+	SearchStatRepository             SearchStatRepository
+	SystemConfigRepository           SystemConfigRepository
+	HotDramaRepository               HotDramaRepository
+	ResourceViewRepository           ResourceViewRepository
+	TaskRepository                   TaskRepository
+	TaskItemRepository               TaskItemRepository
+	FileRepository                   FileRepository
+	TelegramChannelRepository        TelegramChannelRepository
+	APIAccessLogRepository           APIAccessLogRepository
+	ReportRepository                 ReportRepository
+	CopyrightClaimRepository         CopyrightClaimRepository
+	ContentSourceRepository          ContentSourceRepository
+	TelegramImportChannelRepository  TelegramImportChannelRepository
+	ReadyResourcePipelineRepository  ReadyResourcePipelineRepository
+	ReadyResourceStageLogRepository  ReadyResourceStageLogRepository
+	ClassificationRuleRepository     ClassificationRuleRepository
+	ClassificationReviewRepository   ClassificationReviewRepository
+	ClassifierModelRepository        ClassifierModelRepository
+	ResourceMetadataRepository       ResourceMetadataRepository
+	SearchEngineSubmissionRepository SearchEngineSubmissionRepository
+	GoogleIndexStatusRepository      GoogleIndexStatusRepository
+	AnalyticsRepository              AnalyticsRepository
+	RoleRepository                   RoleRepository
+	PermissionRepository             PermissionRepository
+	PermissionDenialRepository       PermissionDenialRepository
+	APIKeyRepository                 APIKeyRepository
+	UserSessionRepository            UserSessionRepository
+	UserTwoFactorRepository          UserTwoFactorRepository
+	LoginHistoryRepository           LoginHistoryRepository
+	AuditLogRepository               AuditLogRepository
+	ModerationActionRepository       ModerationActionRepository
+	BlocklistRuleRepository          BlocklistRuleRepository
+	ScheduledJobRepository           ScheduledJobRepository
+	PluginConfigRepository           *PluginConfigRepository
+	PluginLogRepository              *PluginLogRepository
+	CronJobRepository                *CronJobRepository
 }
 
 // NewRepositoryManager 创建Repository管理器
 func NewRepositoryManager(db *gorm.DB) *RepositoryManager {
+	registerCacheInvalidation(db)
+
 	return &RepositoryManager{
-		PanRepository:             NewPanRepository(db),
-		CksRepository:             NewCksRepository(db),
-		ResourceRepository:        NewResourceRepository(db),
-		CategoryRepository:        NewCategoryRepository(db),
-		TagRepository:             NewTagRepository(db),
-		ReadyResourceRepository:   NewReadyResourceRepository(db),
-		UserRepository:            NewUserRepository(db),
-		SearchStatRepository:      NewSearchStatRepository(db),
-		SystemConfigRepository:    NewSystemConfigRepository(db),
-		HotDramaRepository:        NewHotDramaRepository(db),
-		ResourceViewRepository:    NewResourceViewRepository(db),
-		TaskRepository:            NewTaskRepository(db),
-		TaskItemRepository:        NewTaskItemRepository(db),
-		FileRepository:            NewFileRepository(db),
-		TelegramChannelRepository: NewTelegramChannelRepository(db),
-		APIAccessLogRepository:    NewAPIAccessLogRepository(db),
-		ReportRepository:          NewReportRepository(db),
-		CopyrightClaimRepository:  NewCopyrightClaimRepository(db),
-		PluginConfigRepository:    NewPluginConfigRepository(db),
-		PluginLogRepository:       NewPluginLogRepository(db),
-		CronJobRepository:         NewCronJobRepository(db),
+		PanRepository:                    NewPanRepository(db),
+		CksRepository:                    NewCksRepository(db),
+		ResourceRepository:               NewResourceRepository(db),
+		CategoryRepository:               NewCategoryRepository(db),
+		TagRepository:                    NewTagRepository(db),
+		ReadyResourceRepository:          NewReadyResourceRepository(db),
+		UserRepository:                   NewUserRepository(db),
+		SearchStatRepository:             NewSearchStatRepository(db),
+		SystemConfigRepository:           NewSystemConfigRepository(db),
+		HotDramaRepository:               NewHotDramaRepository(db),
+		ResourceViewRepository:           NewResourceViewRepository(db),
+		TaskRepository:                   NewTaskRepository(db),
+		TaskItemRepository:               NewTaskItemRepository(db),
+		FileRepository:                   NewFileRepository(db),
+		TelegramChannelRepository:        NewTelegramChannelRepository(db),
+		APIAccessLogRepository:           NewAPIAccessLogRepository(db),
+		ReportRepository:                 NewReportRepository(db),
+		CopyrightClaimRepository:         NewCopyrightClaimRepository(db),
+		ContentSourceRepository:          NewContentSourceRepository(db),
+		TelegramImportChannelRepository:  NewTelegramImportChannelRepository(db),
+		ReadyResourcePipelineRepository:  NewReadyResourcePipelineRepository(db),
+		ReadyResourceStageLogRepository:  NewReadyResourceStageLogRepository(db),
+		ClassificationRuleRepository:     NewClassificationRuleRepository(db),
+		ClassificationReviewRepository:   NewClassificationReviewRepository(db),
+		ClassifierModelRepository:        NewClassifierModelRepository(db),
+		ResourceMetadataRepository:       NewResourceMetadataRepository(db),
+		SearchEngineSubmissionRepository: NewSearchEngineSubmissionRepository(db),
+		GoogleIndexStatusRepository:      NewGoogleIndexStatusRepository(db),
+		AnalyticsRepository:              NewAnalyticsRepository(db),
+		RoleRepository:                   NewRoleRepository(db),
+		PermissionRepository:             NewPermissionRepository(db),
+		PermissionDenialRepository:       NewPermissionDenialRepository(db),
+		APIKeyRepository:                 NewAPIKeyRepository(db),
+		UserSessionRepository:            NewUserSessionRepository(db),
+		UserTwoFactorRepository:          NewUserTwoFactorRepository(db),
+		LoginHistoryRepository:           NewLoginHistoryRepository(db),
+		AuditLogRepository:               NewAuditLogRepository(db),
+		ModerationActionRepository:       NewModerationActionRepository(db),
+		BlocklistRuleRepository:          NewBlocklistRuleRepository(db),
+		ScheduledJobRepository:           NewScheduledJobRepository(db),
+		PluginConfigRepository:           NewPluginConfigRepository(db),
+		PluginLogRepository:              NewPluginLogRepository(db),
+		CronJobRepository:                NewCronJobRepository(db),
 	}
 }
 
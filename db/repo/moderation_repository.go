@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// ModerationActionRepository 内容处置记录Repository接口
+type ModerationActionRepository interface {
+	BaseRepository[entity.ModerationAction]
+	Search(sourceType string, sourceID uint, resourceKey string, page, pageSize int) ([]entity.ModerationAction, int64, error)
+	FindActiveBySource(sourceType string, sourceID uint) ([]entity.ModerationAction, error)
+	Save(action *entity.ModerationAction) error
+	MarkReverted(id, revertedBy uint, revertedAt time.Time) error
+}
+
+// ModerationActionRepositoryImpl 内容处置记录Repository实现
+type ModerationActionRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ModerationAction]
+}
+
+// NewModerationActionRepository 创建内容处置记录Repository
+func NewModerationActionRepository(db *gorm.DB) ModerationActionRepository {
+	return &ModerationActionRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ModerationAction]{db: db},
+	}
+}
+
+// Search 分页查询处置记录
+func (r *ModerationActionRepositoryImpl) Search(sourceType string, sourceID uint, resourceKey string, page, pageSize int) ([]entity.ModerationAction, int64, error) {
+	var actions []entity.ModerationAction
+	var total int64
+
+	query := r.db.Model(&entity.ModerationAction{})
+	if sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+	if sourceID > 0 {
+		query = query.Where("source_id = ?", sourceID)
+	}
+	if resourceKey != "" {
+		query = query.Where("resource_key = ?", resourceKey)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&actions).Error
+	return actions, total, err
+}
+
+// FindActiveBySource 查找来源下未撤销的处置记录
+func (r *ModerationActionRepositoryImpl) FindActiveBySource(sourceType string, sourceID uint) ([]entity.ModerationAction, error) {
+	var actions []entity.ModerationAction
+	err := r.db.Where("source_type = ? AND source_id = ? AND reverted = ?", sourceType, sourceID, false).
+		Order("id ASC").Find(&actions).Error
+	return actions, err
+}
+
+// Save 保存全部字段（包括零值）
+func (r *ModerationActionRepositoryImpl) Save(action *entity.ModerationAction) error {
+	return r.db.Save(action).Error
+}
+
+// MarkReverted 标记为已撤销
+func (r *ModerationActionRepositoryImpl) MarkReverted(id, revertedBy uint, revertedAt time.Time) error {
+	return r.db.Model(&entity.ModerationAction{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"reverted":    true,
+		"reverted_at": revertedAt,
+		"reverted_by": revertedBy,
+	}).Error
+}
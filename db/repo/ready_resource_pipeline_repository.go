@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+)
+
+// ReadyResourcePipelineRepository 待处理资源流水线配置Repository接口
+type ReadyResourcePipelineRepository interface {
+	BaseRepository[entity.ReadyResourcePipeline]
+	FindBySource(source string) (*entity.ReadyResourcePipeline, error)
+	DeleteBySource(source string) error
+}
+
+// ReadyResourcePipelineRepositoryImpl 待处理资源流水线配置Repository实现
+type ReadyResourcePipelineRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ReadyResourcePipeline]
+}
+
+// NewReadyResourcePipelineRepository 创建待处理资源流水线配置Repository
+func NewReadyResourcePipelineRepository(db *gorm.DB) ReadyResourcePipelineRepository {
+	return &ReadyResourcePipelineRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ReadyResourcePipeline]{db: db},
+	}
+}
+
+// FindBySource 根据数据来源查找流水线配置
+func (r *ReadyResourcePipelineRepositoryImpl) FindBySource(source string) (*entity.ReadyResourcePipeline, error) {
+	var pipeline entity.ReadyResourcePipeline
+	err := r.db.Where("source = ?", source).First(&pipeline).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pipeline, nil
+}
+
+// DeleteBySource 删除指定数据来源的流水线配置
+func (r *ReadyResourcePipelineRepositoryImpl) DeleteBySource(source string) error {
+	return r.db.Where("source = ?", source).Delete(&entity.ReadyResourcePipeline{}).Error
+}
+
+// ReadyResourceStageLogRepository 待处理资源阶段执行记录Repository接口
+type ReadyResourceStageLogRepository interface {
+	BaseRepository[entity.ReadyResourceStageLog]
+	BatchCreate(logs []entity.ReadyResourceStageLog) error
+	FindByReadyResourceID(readyResourceID uint) ([]entity.ReadyResourceStageLog, error)
+	FindWithFilters(params map[string]interface{}) ([]entity.ReadyResourceStageLog, int64, error)
+	GetStageStats(since time.Time) ([]StageStat, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// StageStat 阶段执行统计
+type StageStat struct {
+	Stage         string  `json:"stage"`
+	Status        string  `json:"status"`
+	Count         int64   `json:"count"`
+	AvgDurationMs float64 `json:"avg_duration_ms"`
+	MaxDurationMs int64   `json:"max_duration_ms"`
+}
+
+// ReadyResourceStageLogRepositoryImpl 待处理资源阶段执行记录Repository实现
+type ReadyResourceStageLogRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ReadyResourceStageLog]
+}
+
+// NewReadyResourceStageLogRepository 创建待处理资源阶段执行记录Repository
+func NewReadyResourceStageLogRepository(db *gorm.DB) ReadyResourceStageLogRepository {
+	return &ReadyResourceStageLogRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ReadyResourceStageLog]{db: db},
+	}
+}
+
+// BatchCreate 批量创建阶段执行记录
+func (r *ReadyResourceStageLogRepositoryImpl) BatchCreate(logs []entity.ReadyResourceStageLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(logs, 100).Error
+}
+
+// FindByReadyResourceID 获取某个待处理资源的全部阶段记录（按执行顺序）
+func (r *ReadyResourceStageLogRepositoryImpl) FindByReadyResourceID(readyResourceID uint) ([]entity.ReadyResourceStageLog, error) {
+	var logs []entity.ReadyResourceStageLog
+	err := r.db.Where("ready_resource_id = ?", readyResourceID).Order("id ASC").Find(&logs).Error
+	return logs, err
+}
+
+// FindWithFilters 按条件分页查询阶段记录
+// 支持参数：source、stage、status、ready_resource_id、page、page_size
+func (r *ReadyResourceStageLogRepositoryImpl) FindWithFilters(params map[string]interface{}) ([]entity.ReadyResourceStageLog, int64, error) {
+	query := r.db.Model(&entity.ReadyResourceStageLog{})
+	if source, ok := params["source"].(string); ok && source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if stage, ok := params["stage"].(string); ok && stage != "" {
+		query = query.Where("stage = ?", stage)
+	}
+	if status, ok := params["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if id, ok := params["ready_resource_id"].(uint); ok && id > 0 {
+		query = query.Where("ready_resource_id = ?", id)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page, _ := params["page"].(int)
+	pageSize, _ := params["page_size"].(int)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var logs []entity.ReadyResourceStageLog
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&logs).Error
+	return logs, total, err
+}
+
+// GetStageStats 统计指定时间之后各阶段的执行次数与耗时
+func (r *ReadyResourceStageLogRepositoryImpl) GetStageStats(since time.Time) ([]StageStat, error) {
+	var stats []StageStat
+	err := r.db.Model(&entity.ReadyResourceStageLog{}).
+		Select("stage, status, COUNT(*) AS count, AVG(duration_ms) AS avg_duration_ms, MAX(duration_ms) AS max_duration_ms").
+		Where("created_at >= ?", since).
+		Group("stage, status").
+		Order("stage, status").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// DeleteBefore 删除指定时间之前的阶段记录
+func (r *ReadyResourceStageLogRepositoryImpl) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.ReadyResourceStageLog{})
+	return result.RowsAffected, result.Error
+}
@@ -11,6 +11,8 @@ type ReportRepository interface {
 	GetByResourceKey(resourceKey string) ([]*entity.Report, error)
 	List(status string, page, pageSize int) ([]*entity.Report, int64, error)
 	UpdateStatus(id uint, status string, processedBy *uint, note string) error
+	CountByResourceKey(resourceKey string, statuses ...string) (int64, error)
+	UpdateStatusByResourceKey(resourceKey, fromStatus, toStatus string) (int64, error)
 	// 兼容原有方法名
 	GetByID(id uint) (*entity.Report, error)
 }
@@ -81,6 +83,25 @@ func (r *ReportRepositoryImpl) UpdateStatus(id uint, status string, processedBy
 	}).Error
 }
 
+// CountByResourceKey 统计资源的举报数，statuses 为空时统计全部状态
+func (r *ReportRepositoryImpl) CountByResourceKey(resourceKey string, statuses ...string) (int64, error) {
+	var count int64
+	query := r.GetDB().Model(&entity.Report{}).Where("resource_key = ?", resourceKey)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// UpdateStatusByResourceKey 将资源下处于 fromStatus 的举报改为 toStatus，返回更新条数
+func (r *ReportRepositoryImpl) UpdateStatusByResourceKey(resourceKey, fromStatus, toStatus string) (int64, error) {
+	result := r.GetDB().Model(&entity.Report{}).
+		Where("resource_key = ? AND status = ?", resourceKey, fromStatus).
+		Update("status", toStatus)
+	return result.RowsAffected, result.Error
+}
+
 // Delete 删除举报
 func (r *ReportRepositoryImpl) Delete(id uint) error {
 	return r.GetDB().Delete(&entity.Report{}, id).Error
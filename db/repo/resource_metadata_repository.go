@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResourceMetadataRepository 资源影视元数据Repository接口
+type ResourceMetadataRepository interface {
+	BaseRepository[entity.ResourceMetadata]
+	FindByResourceID(resourceID uint) (*entity.ResourceMetadata, error)
+	FindByResourceIDs(resourceIDs []uint) ([]entity.ResourceMetadata, error)
+	FindByKey(key string) (*entity.ResourceMetadata, error)
+	Upsert(metadata *entity.ResourceMetadata) error
+	DeleteByResourceID(resourceID uint) error
+	FindDueForRefresh(now time.Time, limit int) ([]entity.ResourceMetadata, error)
+	FindResourcesWithoutMetadata(limit int) ([]entity.Resource, error)
+	CountByStatus() (map[string]int64, error)
+}
+
+// ResourceMetadataRepositoryImpl 资源影视元数据Repository实现
+type ResourceMetadataRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ResourceMetadata]
+}
+
+// NewResourceMetadataRepository 创建资源影视元数据Repository
+func NewResourceMetadataRepository(db *gorm.DB) ResourceMetadataRepository {
+	return &ResourceMetadataRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ResourceMetadata]{db: db},
+	}
+}
+
+// FindByResourceID 根据资源ID查找元数据
+func (r *ResourceMetadataRepositoryImpl) FindByResourceID(resourceID uint) (*entity.ResourceMetadata, error) {
+	var metadata entity.ResourceMetadata
+	err := r.db.Where("resource_id = ?", resourceID).First(&metadata).Error
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// FindByResourceIDs 批量查找元数据
+func (r *ResourceMetadataRepositoryImpl) FindByResourceIDs(resourceIDs []uint) ([]entity.ResourceMetadata, error) {
+	var list []entity.ResourceMetadata
+	if len(resourceIDs) == 0 {
+		return list, nil
+	}
+	err := r.db.Where("resource_id IN ?", resourceIDs).Find(&list).Error
+	return list, err
+}
+
+// FindByKey 查找资源组中已匹配到条目的元数据
+func (r *ResourceMetadataRepositoryImpl) FindByKey(key string) (*entity.ResourceMetadata, error) {
+	var metadata entity.ResourceMetadata
+	err := r.db.Joins("JOIN resources ON resources.id = resource_metadata.resource_id").
+		Where("resources.key = ? AND resources.deleted_at IS NULL", key).
+		Where("resource_metadata.status IN ?", []string{entity.ResourceMetadataMatched, entity.ResourceMetadataManual}).
+		Order("resource_metadata.rating_count DESC").
+		First(&metadata).Error
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// Upsert 按资源ID创建或更新元数据
+func (r *ResourceMetadataRepositoryImpl) Upsert(metadata *entity.ResourceMetadata) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "resource_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"provider", "subject_id", "media_type", "title", "original_title", "year", "regions", "genres",
+			"rating", "rating_count", "poster_url", "episodes", "directors", "actors", "summary", "subject_url",
+			"match_query", "match_score", "status", "error", "fetched_at", "next_refresh_at", "updated_at",
+		}),
+	}).Create(metadata).Error
+}
+
+// DeleteByResourceID 删除资源的元数据
+func (r *ResourceMetadataRepositoryImpl) DeleteByResourceID(resourceID uint) error {
+	return r.db.Where("resource_id = ?", resourceID).Delete(&entity.ResourceMetadata{}).Error
+}
+
+// FindDueForRefresh 查找到期需要刷新的元数据，最早到期的优先
+func (r *ResourceMetadataRepositoryImpl) FindDueForRefresh(now time.Time, limit int) ([]entity.ResourceMetadata, error) {
+	var list []entity.ResourceMetadata
+	err := r.db.Where("next_refresh_at IS NOT NULL AND next_refresh_at <= ?", now).
+		Order("next_refresh_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// FindResourcesWithoutMetadata 查找尚未匹配元数据的有效资源，最新的优先
+func (r *ResourceMetadataRepositoryImpl) FindResourcesWithoutMetadata(limit int) ([]entity.Resource, error) {
+	var resources []entity.Resource
+	err := r.db.Model(&entity.Resource{}).
+		Where("is_valid = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM resource_metadata rm WHERE rm.resource_id = resources.id)").
+		Order("id DESC").
+		Limit(limit).
+		Find(&resources).Error
+	return resources, err
+}
+
+// CountByStatus 按匹配状态统计数量
+func (r *ResourceMetadataRepositoryImpl) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.Model(&entity.ResourceMetadata{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
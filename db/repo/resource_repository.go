@@ -1,12 +1,14 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/cache"
 	"github.com/ctwj/urldb/utils"
 	"gorm.io/gorm"
 )
@@ -60,6 +62,7 @@ type ResourceRepository interface {
 	CountResourcesByCkID(ckID uint) (int64, error)
 	FindByResourceKey(key string) ([]entity.Resource, error)
 	FindByKey(key string) ([]entity.Resource, error)
+	FindUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error)
 	GetHotResources(limit int) ([]entity.Resource, error)
 	GetTotalCount() (int64, error)
 	GetAllValidResources() ([]entity.Resource, error)
@@ -69,21 +72,25 @@ type ResourceRepository interface {
 	MarkCleanError(id uint, errMsg string, errAt time.Time) error
 	// UpdateFields 按主键更新指定字段；用于重转等场景部分更新（避免 GORM Updates 跳过零值）
 	UpdateFields(id uint, fields map[string]interface{}) error
+	// Restore 恢复已软删除的资源（撤销内容处置时使用）
+	Restore(ids []uint) error
+	FindForClassifierTraining(limit int) ([]entity.Resource, error)
+	FindUncategorized(limit int) ([]entity.Resource, error)
 	// GenerateUniqueKey 生成唯一的6位Base62资源Key（复用 BaseRepositoryImpl 实现）
 	GenerateUniqueKey() (string, error)
+	// KeyExists 检查资源Key是否已被占用（含失效资源）
+	KeyExists(key string) (bool, error)
 }
 
 // ResourceRepositoryImpl Resource的Repository实现
 type ResourceRepositoryImpl struct {
 	BaseRepositoryImpl[entity.Resource]
-	cache map[string]interface{}
 }
 
 // NewResourceRepository 创建Resource Repository
 func NewResourceRepository(db *gorm.DB) ResourceRepository {
 	return &ResourceRepositoryImpl{
 		BaseRepositoryImpl: BaseRepositoryImpl[entity.Resource]{db: db},
-		cache:              make(map[string]interface{}),
 	}
 }
 
@@ -325,6 +332,26 @@ func (r *ResourceRepositoryImpl) SearchWithFilters(params map[string]interface{}
 					db = db.Where("pan_id = ?", panEntity.ID)
 				}
 			}
+		case "year": // 影视元数据：年份
+			if year, ok := value.(int); ok && year > 0 {
+				db = db.Where("resources.id IN (?)", r.matchedMetadata().Where("year = ?", year))
+			}
+		case "region": // 影视元数据：地区
+			if region, ok := value.(string); ok && region != "" {
+				db = db.Where("resources.id IN (?)", r.matchedMetadata().Where("regions ILIKE ?", "%"+region+"%"))
+			}
+		case "genre": // 影视元数据：类型
+			if genre, ok := value.(string); ok && genre != "" {
+				db = db.Where("resources.id IN (?)", r.matchedMetadata().Where("genres ILIKE ?", "%"+genre+"%"))
+			}
+		case "media_type": // 影视元数据：movie/tv
+			if mediaType, ok := value.(string); ok && mediaType != "" {
+				db = db.Where("resources.id IN (?)", r.matchedMetadata().Where("media_type = ?", mediaType))
+			}
+		case "min_rating": // 影视元数据：最低评分
+			if minRating, ok := value.(float64); ok && minRating > 0 {
+				db = db.Where("resources.id IN (?)", r.matchedMetadata().Where("rating >= ?", minRating))
+			}
 		case "exclude_ids": // 添加exclude_ids参数支持
 			if excludeIDs, ok := value.([]uint); ok && len(excludeIDs) > 0 {
 				// 限制排除ID的数量，避免SQL语句过长
@@ -415,6 +442,12 @@ func (r *ResourceRepositoryImpl) SearchWithFilters(params map[string]interface{}
 	return resources, total, err
 }
 
+// matchedMetadata 已匹配到影视条目的资源ID子查询，用于按元数据筛选
+func (r *ResourceRepositoryImpl) matchedMetadata() *gorm.DB {
+	return r.db.Model(&entity.ResourceMetadata{}).Select("resource_id").
+		Where("status IN ?", []string{entity.ResourceMetadataMatched, entity.ResourceMetadataManual})
+}
+
 // IncrementViewCount 增加浏览次数
 func (r *ResourceRepositoryImpl) IncrementViewCount(id uint) error {
 	return r.db.Model(&entity.Resource{}).Where("id = ?", id).
@@ -463,36 +496,18 @@ func (r *ResourceRepositoryImpl) GetLatestResources(limit int) ([]entity.Resourc
 	return resources, err
 }
 
-// GetCachedLatestResources 获取缓存的最新资源
+// GetCachedLatestResources 获取缓存的最新资源（5分钟过期，资源写入后失效）
 func (r *ResourceRepositoryImpl) GetCachedLatestResources(limit int) ([]entity.Resource, error) {
-	cacheKey := fmt.Sprintf("latest_resources_%d", limit)
-
-	// 检查缓存
-	if cached, exists := r.cache[cacheKey]; exists {
-		if resources, ok := cached.([]entity.Resource); ok {
-			return resources, nil
-		}
-	}
-
-	// 从数据库获取
-	resources, err := r.GetLatestResources(limit)
-	if err != nil {
-		return nil, err
-	}
-
-	// 缓存结果（5分钟过期）
-	r.cache[cacheKey] = resources
-	go func() {
-		time.Sleep(5 * time.Minute)
-		delete(r.cache, cacheKey)
-	}()
-
-	return resources, nil
+	key := fmt.Sprintf("resource:latest:%d", limit)
+	opts := cache.Options{TTL: 5 * time.Minute, Tags: []string{cache.AllTag("resource")}}
+	return cache.GetOrLoad(context.Background(), cache.Default(), key, opts, func(context.Context) ([]entity.Resource, error) {
+		return r.GetLatestResources(limit)
+	})
 }
 
-// InvalidateCache 清除缓存
+// InvalidateCache 清除资源相关缓存
 func (r *ResourceRepositoryImpl) InvalidateCache() error {
-	r.cache = make(map[string]interface{})
+	cache.Default().Invalidate(context.Background(), cache.AllTag("resource"))
 	return nil
 }
 
@@ -854,6 +869,17 @@ func (r *ResourceRepositoryImpl) FindByKey(key string) ([]entity.Resource, error
 	return resources, err
 }
 
+// FindUpdatedAfter 按 (updated_at, id) 游标查找之后新增或更新的有效资源
+func (r *ResourceRepositoryImpl) FindUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error) {
+	var resources []entity.Resource
+	err := r.db.Where("is_valid = ? AND key <> ''", true).
+		Where("updated_at > ? OR (updated_at = ? AND id > ?)", updatedAt, updatedAt, id).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&resources).Error
+	return resources, err
+}
+
 // GetHotResources 获取热门资源（按查看次数排序，去重，限制数量）
 func (r *ResourceRepositoryImpl) GetHotResources(limit int) ([]entity.Resource, error) {
 	var resources []entity.Resource
@@ -993,8 +1019,41 @@ func (r *ResourceRepositoryImpl) UpdateFields(id uint, fields map[string]interfa
 	return r.db.Model(&entity.Resource{}).Where("id = ?", id).Updates(fields).Error
 }
 
+// Restore 恢复已软删除的资源
+func (r *ResourceRepositoryImpl) Restore(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Unscoped().Model(&entity.Resource{}).Where("id IN ?", ids).Update("deleted_at", nil).Error
+}
+
+// FindForClassifierTraining 获取用于训练分类模型的已分类资源（仅标题、描述与分类，按创建时间倒序）
+func (r *ResourceRepositoryImpl) FindForClassifierTraining(limit int) ([]entity.Resource, error) {
+	var resources []entity.Resource
+	err := r.db.Select("id", "title", "description", "category_id").
+		Where("category_id IS NOT NULL").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&resources).Error
+	return resources, err
+}
+
+// FindUncategorized 获取未分类的资源（按创建时间倒序）
+func (r *ResourceRepositoryImpl) FindUncategorized(limit int) ([]entity.Resource, error) {
+	var resources []entity.Resource
+	err := r.db.Where("category_id IS NULL").Order("created_at DESC").Limit(limit).Find(&resources).Error
+	return resources, err
+}
+
 // GenerateUniqueKey 生成唯一的6位Base62资源Key，用于 /r/:key 短链访问。
 // 委托给 BaseRepositoryImpl.GenerateUniqueKey（泛型实现，按 resource.key 字段查重）。
 func (r *ResourceRepositoryImpl) GenerateUniqueKey() (string, error) {
 	return r.BaseRepositoryImpl.GenerateUniqueKey("key")
 }
+
+// KeyExists 检查资源Key是否已被占用（含失效资源）
+func (r *ResourceRepositoryImpl) KeyExists(key string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Resource{}).Where("key = ?", key).Count(&count).Error
+	return count > 0, err
+}
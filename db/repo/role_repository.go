@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleRepository 角色Repository接口
+type RoleRepository interface {
+	BaseRepository[entity.Role]
+	FindAllWithPermissions() ([]entity.Role, error)
+	FindByName(name string) (*entity.Role, error)
+	ReplacePermissions(role *entity.Role, codes []string) error
+	DeleteWithPermissions(id uint) error
+	CountUsers(name string) (int64, error)
+}
+
+// RoleRepositoryImpl 角色Repository实现
+type RoleRepositoryImpl struct {
+	BaseRepositoryImpl[entity.Role]
+}
+
+// NewRoleRepository 创建角色Repository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &RoleRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.Role]{db: db},
+	}
+}
+
+// FindAllWithPermissions 查找所有角色及其权限
+func (r *RoleRepositoryImpl) FindAllWithPermissions() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Preload("Permissions").Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByName 根据角色标识查找，包含权限
+func (r *RoleRepositoryImpl) FindByName(name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// ReplacePermissions 用给定权限码替换角色的全部权限，未知权限码会被忽略
+func (r *RoleRepositoryImpl) ReplacePermissions(role *entity.Role, codes []string) error {
+	var permissions []entity.Permission
+	if len(codes) > 0 {
+		if err := r.db.Where("code IN ?", codes).Find(&permissions).Error; err != nil {
+			return err
+		}
+	}
+	if err := r.db.Model(role).Association("Permissions").Replace(permissions); err != nil {
+		return err
+	}
+	role.Permissions = permissions
+	return nil
+}
+
+// DeleteWithPermissions 删除角色及其权限关联
+func (r *RoleRepositoryImpl) DeleteWithPermissions(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Role{}, id).Error
+	})
+}
+
+// CountUsers 统计使用该角色的用户数
+func (r *RoleRepositoryImpl) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
+
+// PermissionRepository 权限Repository接口
+type PermissionRepository interface {
+	BaseRepository[entity.Permission]
+	Sync(permissions []entity.Permission) error
+}
+
+// PermissionRepositoryImpl 权限Repository实现
+type PermissionRepositoryImpl struct {
+	BaseRepositoryImpl[entity.Permission]
+}
+
+// NewPermissionRepository 创建权限Repository
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &PermissionRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.Permission]{db: db},
+	}
+}
+
+// Sync 按权限码写入权限定义，已存在的更新名称与分组
+func (r *PermissionRepositoryImpl) Sync(permissions []entity.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "group_name", "updated_at"}),
+	}).Create(&permissions).Error
+}
+
+// PermissionDenialRepository 权限拒绝记录Repository接口
+type PermissionDenialRepository interface {
+	BaseRepository[entity.PermissionDenial]
+	Search(username, permission string, page, pageSize int) ([]entity.PermissionDenial, int64, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// PermissionDenialRepositoryImpl 权限拒绝记录Repository实现
+type PermissionDenialRepositoryImpl struct {
+	BaseRepositoryImpl[entity.PermissionDenial]
+}
+
+// NewPermissionDenialRepository 创建权限拒绝记录Repository
+func NewPermissionDenialRepository(db *gorm.DB) PermissionDenialRepository {
+	return &PermissionDenialRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.PermissionDenial]{db: db},
+	}
+}
+
+// Search 按用户名与权限码分页查询，按时间倒序
+func (r *PermissionDenialRepositoryImpl) Search(username, permission string, page, pageSize int) ([]entity.PermissionDenial, int64, error) {
+	var denials []entity.PermissionDenial
+	var total int64
+
+	query := r.db.Model(&entity.PermissionDenial{})
+	if username != "" {
+		query = query.Where("username = ?", username)
+	}
+	if permission != "" {
+		query = query.Where("permission = ?", permission)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&denials).Error
+	return denials, total, err
+}
+
+// DeleteBefore 删除指定时间之前的记录
+func (r *PermissionDenialRepositoryImpl) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&entity.PermissionDenial{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduledJobRepository 定时任务执行记录与状态Repository接口
+type ScheduledJobRepository interface {
+	BaseRepository[entity.ScheduledJobRun]
+	ListRuns(job, status string, page, pageSize int) ([]entity.ScheduledJobRun, int64, error)
+	LastRun(job string) (*entity.ScheduledJobRun, error)
+	DeleteRunsBefore(before time.Time) (int64, error)
+	GetState(name string) (*entity.ScheduledJobState, error)
+	SaveState(state *entity.ScheduledJobState) error
+}
+
+// ScheduledJobRepositoryImpl 定时任务Repository实现
+type ScheduledJobRepositoryImpl struct {
+	BaseRepositoryImpl[entity.ScheduledJobRun]
+}
+
+// NewScheduledJobRepository 创建定时任务Repository
+func NewScheduledJobRepository(db *gorm.DB) ScheduledJobRepository {
+	return &ScheduledJobRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.ScheduledJobRun]{db: db},
+	}
+}
+
+// ListRuns 分页查询执行记录，job、status 为空时不过滤
+func (r *ScheduledJobRepositoryImpl) ListRuns(job, status string, page, pageSize int) ([]entity.ScheduledJobRun, int64, error) {
+	var runs []entity.ScheduledJobRun
+	var total int64
+
+	query := r.db.Model(&entity.ScheduledJobRun{})
+	if job != "" {
+		query = query.Where("job = ?", job)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("started_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&runs).Error
+	return runs, total, err
+}
+
+// LastRun 获取任务最近一次执行记录，没有时返回 nil
+func (r *ScheduledJobRepositoryImpl) LastRun(job string) (*entity.ScheduledJobRun, error) {
+	var run entity.ScheduledJobRun
+	err := r.db.Where("job = ?", job).Order("started_at DESC, id DESC").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// DeleteRunsBefore 删除指定时间之前开始的执行记录
+func (r *ScheduledJobRepositoryImpl) DeleteRunsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("started_at < ?", before).Delete(&entity.ScheduledJobRun{})
+	return result.RowsAffected, result.Error
+}
+
+// GetState 获取任务状态，没有时返回 nil
+func (r *ScheduledJobRepositoryImpl) GetState(name string) (*entity.ScheduledJobState, error) {
+	var state entity.ScheduledJobState
+	err := r.db.Where("name = ?", name).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveState 保存任务状态
+func (r *ScheduledJobRepositoryImpl) SaveState(state *entity.ScheduledJobState) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"spec", "paused", "updated_at"}),
+	}).Create(state).Error
+}
@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchEngineSubmissionCount 按搜索引擎和状态统计的数量
+type SearchEngineSubmissionCount struct {
+	Engine string `json:"engine"`
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// SearchEngineSubmissionRepository 搜索引擎提交台账Repository接口
+type SearchEngineSubmissionRepository interface {
+	BaseRepository[entity.SearchEngineSubmission]
+	FindByEngineAndURLs(engine string, urls []string) ([]entity.SearchEngineSubmission, error)
+	SaveAll(list []entity.SearchEngineSubmission) error
+	FindSubmittable(engine string, maxAttempts, limit int) ([]entity.SearchEngineSubmission, error)
+	FindWithFilters(engine, status, keyword string, page, pageSize int) ([]entity.SearchEngineSubmission, int64, error)
+	CountByEngineStatus() ([]SearchEngineSubmissionCount, error)
+	RequeueFailed(engine string, now time.Time) (int64, error)
+	FindUsageByDate(date string) ([]entity.SearchEngineQuotaUsage, error)
+	AddUsage(engine, date string, used int, exhausted bool) error
+	FindResourcesUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error)
+}
+
+// SearchEngineSubmissionRepositoryImpl 搜索引擎提交台账Repository实现
+type SearchEngineSubmissionRepositoryImpl struct {
+	BaseRepositoryImpl[entity.SearchEngineSubmission]
+}
+
+// NewSearchEngineSubmissionRepository 创建搜索引擎提交台账Repository
+func NewSearchEngineSubmissionRepository(db *gorm.DB) SearchEngineSubmissionRepository {
+	return &SearchEngineSubmissionRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.SearchEngineSubmission]{db: db},
+	}
+}
+
+// FindByEngineAndURLs 查找指定搜索引擎下已有的URL记录
+func (r *SearchEngineSubmissionRepositoryImpl) FindByEngineAndURLs(engine string, urls []string) ([]entity.SearchEngineSubmission, error) {
+	var list []entity.SearchEngineSubmission
+	if len(urls) == 0 {
+		return list, nil
+	}
+	err := r.db.Where("engine = ? AND url IN ?", engine, urls).Find(&list).Error
+	return list, err
+}
+
+// SaveAll 批量保存记录（新记录插入，已有记录更新）
+func (r *SearchEngineSubmissionRepositoryImpl) SaveAll(list []entity.SearchEngineSubmission) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range list {
+			if err := tx.Save(&list[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindSubmittable 查找待提交的记录：排队中的，以及未超过重试次数的失败记录，先排队的优先
+func (r *SearchEngineSubmissionRepositoryImpl) FindSubmittable(engine string, maxAttempts, limit int) ([]entity.SearchEngineSubmission, error) {
+	var list []entity.SearchEngineSubmission
+	err := r.db.Where("engine = ?", engine).
+		Where("status = ? OR (status = ? AND attempts < ?)", entity.SearchEngineSubmissionPending, entity.SearchEngineSubmissionFailed, maxAttempts).
+		Order("queued_at ASC, id ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// FindWithFilters 分页查询提交台账
+func (r *SearchEngineSubmissionRepositoryImpl) FindWithFilters(engine, status, keyword string, page, pageSize int) ([]entity.SearchEngineSubmission, int64, error) {
+	var list []entity.SearchEngineSubmission
+	var total int64
+
+	query := r.db.Model(&entity.SearchEngineSubmission{})
+	if engine != "" {
+		query = query.Where("engine = ?", engine)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		query = query.Where("url ILIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("updated_at DESC").Offset(offset).Limit(pageSize).Find(&list).Error
+	return list, total, err
+}
+
+// CountByEngineStatus 按搜索引擎和状态统计
+func (r *SearchEngineSubmissionRepositoryImpl) CountByEngineStatus() ([]SearchEngineSubmissionCount, error) {
+	var rows []SearchEngineSubmissionCount
+	err := r.db.Model(&entity.SearchEngineSubmission{}).
+		Select("engine, status, COUNT(*) AS count").
+		Group("engine, status").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// RequeueFailed 将失败记录重新排队，engine 为空时处理所有搜索引擎
+func (r *SearchEngineSubmissionRepositoryImpl) RequeueFailed(engine string, now time.Time) (int64, error) {
+	query := r.db.Model(&entity.SearchEngineSubmission{}).Where("status = ?", entity.SearchEngineSubmissionFailed)
+	if engine != "" {
+		query = query.Where("engine = ?", engine)
+	}
+	result := query.Updates(map[string]interface{}{
+		"status":    entity.SearchEngineSubmissionPending,
+		"attempts":  0,
+		"queued_at": now,
+	})
+	return result.RowsAffected, result.Error
+}
+
+// FindUsageByDate 获取某天各搜索引擎的配额使用量
+func (r *SearchEngineSubmissionRepositoryImpl) FindUsageByDate(date string) ([]entity.SearchEngineQuotaUsage, error) {
+	var list []entity.SearchEngineQuotaUsage
+	err := r.db.Where("date = ?", date).Find(&list).Error
+	return list, err
+}
+
+// AddUsage 累加某天的配额使用量，exhausted 为 true 时标记当天配额已用尽
+func (r *SearchEngineSubmissionRepositoryImpl) AddUsage(engine, date string, used int, exhausted bool) error {
+	usage := entity.SearchEngineQuotaUsage{Engine: engine, Date: date, Used: used, Exhausted: exhausted}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "engine"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"used":       gorm.Expr("search_engine_quota_usages.used + ?", used),
+			"exhausted":  gorm.Expr("search_engine_quota_usages.exhausted OR ?", exhausted),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&usage).Error
+}
+
+// FindResourcesUpdatedAfter 按 (updated_at, id) 游标查找之后新增或更新的公开有效资源
+func (r *SearchEngineSubmissionRepositoryImpl) FindResourcesUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error) {
+	var resources []entity.Resource
+	err := r.db.Model(&entity.Resource{}).
+		Where("is_valid = ? AND is_public = ?", true, true).
+		Where("key <> ''").
+		Where("updated_at > ? OR (updated_at = ? AND id > ?)", updatedAt, updatedAt, id).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&resources).Error
+	return resources, err
+}
@@ -1,11 +1,12 @@
 package repo
 
 import (
+	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/cache"
 	"github.com/ctwj/urldb/utils"
 
 	"gorm.io/gorm"
@@ -30,18 +31,18 @@ type SystemConfigRepository interface {
 // SystemConfigRepositoryImpl 系统配置Repository实现
 type SystemConfigRepositoryImpl struct {
 	BaseRepositoryImpl[entity.SystemConfig]
-
-	// 配置缓存
-	configCache      map[string]string // key -> value
-	configCacheOnce  sync.Once
-	configCacheMutex sync.RWMutex
 }
 
+// 全部配置以一个缓存项保存，配置写入后按 "config:*" 标签失效（多副本时经 Redis 通知）
+const (
+	configCacheKey = "config:all"
+	configCacheTTL = 10 * time.Minute
+)
+
 // NewSystemConfigRepository 创建系统配置Repository
 func NewSystemConfigRepository(db *gorm.DB) SystemConfigRepository {
 	return &SystemConfigRepositoryImpl{
 		BaseRepositoryImpl: BaseRepositoryImpl[entity.SystemConfig]{db: db},
-		configCache:        make(map[string]string),
 	}
 }
 
@@ -65,7 +66,7 @@ func (r *SystemConfigRepositoryImpl) FindByKey(key string) (*entity.SystemConfig
 // UpsertConfigs 批量创建或更新配置
 func (r *SystemConfigRepositoryImpl) UpsertConfigs(configs []entity.SystemConfig) error {
 	// 使用事务确保数据一致性
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
 		// 在更新前备份当前配置
 		var existingConfigs []entity.SystemConfig
 		if err := tx.Find(&existingConfigs).Error; err != nil {
@@ -93,10 +94,15 @@ func (r *SystemConfigRepositoryImpl) UpsertConfigs(configs []entity.SystemConfig
 			}
 		}
 
-		// 更新成功后刷新缓存
-		r.refreshConfigCache()
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// 提交后再失效一次，避免事务期间的并发读取把旧配置写回缓存
+	r.ClearConfigCache()
+	return nil
 }
 
 // GetOrCreateDefault 获取配置或创建默认配置
@@ -148,6 +154,7 @@ func (r *SystemConfigRepositoryImpl) GetOrCreateDefault() ([]entity.SystemConfig
 			{Key: entity.ConfigKeyTelegramSearchPageSize, Value: entity.ConfigDefaultTelegramSearchPageSize, Type: entity.ConfigTypeInt},
 		{Key: entity.ConfigKeyTelegramWelcomeEnabled, Value: entity.ConfigDefaultTelegramWelcomeEnabled, Type: entity.ConfigTypeBool},
 		{Key: entity.ConfigKeyTelegramWelcomeMessage, Value: entity.ConfigDefaultTelegramWelcomeMessage, Type: entity.ConfigTypeString},
+		{Key: entity.ConfigKeyTelegramAdminChatID, Value: "0", Type: entity.ConfigTypeInt},
 			// 自动清理转存文件默认配置（002-auto-cleanup-transfer）
 			{Key: entity.ConfigKeyAutoCleanupEnabled, Value: entity.ConfigDefaultAutoCleanupEnabled, Type: entity.ConfigTypeBool},
 			{Key: entity.ConfigKeyAutoCleanupRetentionDays, Value: entity.ConfigDefaultAutoCleanupRetentionDays, Type: entity.ConfigTypeInt},
@@ -213,6 +220,7 @@ func (r *SystemConfigRepositoryImpl) GetOrCreateDefault() ([]entity.SystemConfig
 		entity.ConfigKeyTelegramSearchPageSize: {Key: entity.ConfigKeyTelegramSearchPageSize, Value: entity.ConfigDefaultTelegramSearchPageSize, Type: entity.ConfigTypeInt},
 	entity.ConfigKeyTelegramWelcomeEnabled: {Key: entity.ConfigKeyTelegramWelcomeEnabled, Value: entity.ConfigDefaultTelegramWelcomeEnabled, Type: entity.ConfigTypeBool},
 	entity.ConfigKeyTelegramWelcomeMessage: {Key: entity.ConfigKeyTelegramWelcomeMessage, Value: entity.ConfigDefaultTelegramWelcomeMessage, Type: entity.ConfigTypeString},
+	entity.ConfigKeyTelegramAdminChatID:    {Key: entity.ConfigKeyTelegramAdminChatID, Value: "0", Type: entity.ConfigTypeInt},
 		// PanCheck 链接检测服务配置
 		entity.ConfigKeyPanCheckEnabled:        {Key: entity.ConfigKeyPanCheckEnabled, Value: entity.ConfigDefaultPanCheckEnabled, Type: entity.ConfigTypeBool},
 		entity.ConfigKeyPanCheckHost:           {Key: entity.ConfigKeyPanCheckHost, Value: entity.ConfigDefaultPanCheckHost, Type: entity.ConfigTypeString},
@@ -233,6 +241,57 @@ func (r *SystemConfigRepositoryImpl) GetOrCreateDefault() ([]entity.SystemConfig
 		entity.GoogleIndexConfigKeyRetryDelay:    {Key: entity.GoogleIndexConfigKeyRetryDelay, Value: "2", Type: entity.ConfigTypeInt},
 		entity.GoogleIndexConfigKeyAutoSitemap:   {Key: entity.GoogleIndexConfigKeyAutoSitemap, Value: "false", Type: entity.ConfigTypeBool},
 		entity.GoogleIndexConfigKeySitemapPath:   {Key: entity.GoogleIndexConfigKeySitemapPath, Value: "/sitemap.xml", Type: entity.ConfigTypeString},
+		// Google索引覆盖率配置
+		entity.GoogleIndexConfigKeyInspectQuota:  {Key: entity.GoogleIndexConfigKeyInspectQuota, Value: "1500", Type: entity.ConfigTypeInt},
+		entity.GoogleIndexConfigKeyStaleDays:     {Key: entity.GoogleIndexConfigKeyStaleDays, Value: "14", Type: entity.ConfigTypeInt},
+		entity.GoogleIndexConfigKeyAutoResubmit:  {Key: entity.GoogleIndexConfigKeyAutoResubmit, Value: "true", Type: entity.ConfigTypeBool},
+		entity.GoogleIndexConfigKeyResubmitLimit: {Key: entity.GoogleIndexConfigKeyResubmitLimit, Value: "50", Type: entity.ConfigTypeInt},
+		// 影视元数据配置
+		entity.MetadataConfigKeyEnabled:     {Key: entity.MetadataConfigKeyEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.MetadataConfigKeyProvider:    {Key: entity.MetadataConfigKeyProvider, Value: entity.MetadataConfigDefaultProvider, Type: entity.ConfigTypeString},
+		entity.MetadataConfigKeyTMDBApiKey:  {Key: entity.MetadataConfigKeyTMDBApiKey, Value: "", Type: entity.ConfigTypeString},
+		entity.MetadataConfigKeyRefreshDays: {Key: entity.MetadataConfigKeyRefreshDays, Value: "30", Type: entity.ConfigTypeInt},
+		entity.MetadataConfigKeyMinScore:    {Key: entity.MetadataConfigKeyMinScore, Value: "0.75", Type: entity.ConfigTypeString},
+		entity.MetadataConfigKeyBatchSize:   {Key: entity.MetadataConfigKeyBatchSize, Value: "20", Type: entity.ConfigTypeInt},
+		// 搜索引擎URL提交配置
+		entity.SearchEngineConfigKeyAutoSubmit:        {Key: entity.SearchEngineConfigKeyAutoSubmit, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyIntervalMinutes:   {Key: entity.SearchEngineConfigKeyIntervalMinutes, Value: "30", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyMaxAttempts:       {Key: entity.SearchEngineConfigKeyMaxAttempts, Value: "3", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyGoogleEnabled:     {Key: entity.SearchEngineConfigKeyGoogleEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyGoogleQuota:       {Key: entity.SearchEngineConfigKeyGoogleQuota, Value: "200", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyBingEnabled:       {Key: entity.SearchEngineConfigKeyBingEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyBingQuota:         {Key: entity.SearchEngineConfigKeyBingQuota, Value: "100", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyIndexNowEnabled:   {Key: entity.SearchEngineConfigKeyIndexNowEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyIndexNowKey:       {Key: entity.SearchEngineConfigKeyIndexNowKey, Value: "", Type: entity.ConfigTypeString},
+		entity.SearchEngineConfigKeyIndexNowURL:       {Key: entity.SearchEngineConfigKeyIndexNowURL, Value: "", Type: entity.ConfigTypeString},
+		entity.SearchEngineConfigKeyIndexNowQuota:     {Key: entity.SearchEngineConfigKeyIndexNowQuota, Value: "10000", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyBaiduEnabled:      {Key: entity.SearchEngineConfigKeyBaiduEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyBaiduToken:        {Key: entity.SearchEngineConfigKeyBaiduToken, Value: "", Type: entity.ConfigTypeString},
+		entity.SearchEngineConfigKeyBaiduQuota:        {Key: entity.SearchEngineConfigKeyBaiduQuota, Value: "10", Type: entity.ConfigTypeInt},
+		entity.SearchEngineConfigKeyBaiduDailyEnabled: {Key: entity.SearchEngineConfigKeyBaiduDailyEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.SearchEngineConfigKeyBaiduDailyQuota:   {Key: entity.SearchEngineConfigKeyBaiduDailyQuota, Value: "10", Type: entity.ConfigTypeInt},
+		// 资源页SEO配置
+		entity.SEOConfigKeyTitleTemplate:       {Key: entity.SEOConfigKeyTitleTemplate, Value: entity.SEOConfigDefaultTitleTemplate, Type: entity.ConfigTypeString},
+		entity.SEOConfigKeyDescriptionTemplate: {Key: entity.SEOConfigKeyDescriptionTemplate, Value: entity.SEOConfigDefaultDescriptionTemplate, Type: entity.ConfigTypeString},
+		entity.SEOConfigKeyHreflang:            {Key: entity.SEOConfigKeyHreflang, Value: entity.SEOConfigDefaultHreflang, Type: entity.ConfigTypeString},
+		// OG图片配置
+		entity.OGImageConfigKeyTemplates:        {Key: entity.OGImageConfigKeyTemplates, Value: entity.OGImageConfigDefaultTemplates, Type: entity.ConfigTypeJSON},
+		entity.OGImageConfigKeyActiveTemplate:   {Key: entity.OGImageConfigKeyActiveTemplate, Value: entity.OGImageConfigDefaultActiveTemplate, Type: entity.ConfigTypeString},
+		entity.OGImageConfigKeyPrerenderEnabled: {Key: entity.OGImageConfigKeyPrerenderEnabled, Value: "true", Type: entity.ConfigTypeBool},
+		// 统计日汇总配置
+		entity.AnalyticsConfigKeyRollupEnabled:       {Key: entity.AnalyticsConfigKeyRollupEnabled, Value: "true", Type: entity.ConfigTypeBool},
+		entity.AnalyticsConfigKeyViewRetentionDays:   {Key: entity.AnalyticsConfigKeyViewRetentionDays, Value: "90", Type: entity.ConfigTypeInt},
+		entity.AnalyticsConfigKeySearchRetentionDays: {Key: entity.AnalyticsConfigKeySearchRetentionDays, Value: "90", Type: entity.ConfigTypeInt},
+		entity.AnalyticsConfigKeyAPILogRetentionDays: {Key: entity.AnalyticsConfigKeyAPILogRetentionDays, Value: "30", Type: entity.ConfigTypeInt},
+		// 备份配置
+		entity.BackupConfigKeyEnabled:        {Key: entity.BackupConfigKeyEnabled, Value: "false", Type: entity.ConfigTypeBool},
+		entity.BackupConfigKeyIntervalHours:  {Key: entity.BackupConfigKeyIntervalHours, Value: "24", Type: entity.ConfigTypeInt},
+		entity.BackupConfigKeyKeepLast:       {Key: entity.BackupConfigKeyKeepLast, Value: "7", Type: entity.ConfigTypeInt},
+		entity.BackupConfigKeyKeepDaily:      {Key: entity.BackupConfigKeyKeepDaily, Value: "7", Type: entity.ConfigTypeInt},
+		entity.BackupConfigKeyKeepWeekly:     {Key: entity.BackupConfigKeyKeepWeekly, Value: "4", Type: entity.ConfigTypeInt},
+		entity.BackupConfigKeyIncludeUploads: {Key: entity.BackupConfigKeyIncludeUploads, Value: "true", Type: entity.ConfigTypeBool},
+		entity.BackupConfigKeyIncludePlugins: {Key: entity.BackupConfigKeyIncludePlugins, Value: "true", Type: entity.ConfigTypeBool},
+		entity.BackupConfigKeyDBMode:         {Key: entity.BackupConfigKeyDBMode, Value: entity.BackupConfigDefaultDBMode, Type: entity.ConfigTypeString},
 	}
 
 	// 检查现有配置中是否有缺失的配置项
@@ -272,43 +331,27 @@ func (r *SystemConfigRepositoryImpl) GetOrCreateDefault() ([]entity.SystemConfig
 	return configs, nil
 }
 
-// initConfigCache 初始化配置缓存
-func (r *SystemConfigRepositoryImpl) initConfigCache() {
-	r.configCacheOnce.Do(func() {
-		// 获取所有配置
+// cachedConfigs 读取缓存的全部配置，未命中时从数据库加载（没有配置时创建默认配置）
+func (r *SystemConfigRepositoryImpl) cachedConfigs() (map[string]string, error) {
+	opts := cache.Options{TTL: configCacheTTL, Tags: []string{cache.AllTag("config")}}
+	return cache.GetOrLoad(context.Background(), cache.Default(), configCacheKey, opts, func(context.Context) (map[string]string, error) {
 		configs, err := r.FindAll()
 		if err != nil {
 			// 如果获取失败，尝试创建默认配置
 			configs, err = r.GetOrCreateDefault()
 			if err != nil {
-				return
+				return nil, err
 			}
 		}
 
-		// 初始化缓存
-		r.configCacheMutex.Lock()
-		defer r.configCacheMutex.Unlock()
-
+		values := make(map[string]string, len(configs))
 		for _, config := range configs {
-			r.configCache[config.Key] = config.Value
+			values[config.Key] = config.Value
 		}
+		return values, nil
 	})
 }
 
-// refreshConfigCache 刷新配置缓存
-func (r *SystemConfigRepositoryImpl) refreshConfigCache() {
-	// 重置Once，允许重新初始化
-	r.configCacheOnce = sync.Once{}
-
-	// 清空缓存
-	r.configCacheMutex.Lock()
-	r.configCache = make(map[string]string)
-	r.configCacheMutex.Unlock()
-
-	// 重新初始化缓存
-	r.initConfigCache()
-}
-
 // SafeRefreshConfigCache 安全的刷新配置缓存（带错误处理）
 func (r *SystemConfigRepositoryImpl) SafeRefreshConfigCache() error {
 	defer func() {
@@ -317,8 +360,9 @@ func (r *SystemConfigRepositoryImpl) SafeRefreshConfigCache() error {
 		}
 	}()
 
-	r.refreshConfigCache()
-	return nil
+	r.ClearConfigCache()
+	_, err := r.cachedConfigs()
+	return err
 }
 
 // ValidateConfigIntegrity 验证配置完整性
@@ -374,16 +418,11 @@ func (r *SystemConfigRepositoryImpl) ValidateConfigIntegrity() error {
 
 // GetConfigValue 获取配置值（字符串）
 func (r *SystemConfigRepositoryImpl) GetConfigValue(key string) (string, error) {
-	// 初始化缓存
-	r.initConfigCache()
-
 	// 从缓存中读取
-	r.configCacheMutex.RLock()
-	value, exists := r.configCache[key]
-	r.configCacheMutex.RUnlock()
-
-	if exists {
-		return value, nil
+	if configs, err := r.cachedConfigs(); err == nil {
+		if value, exists := configs[key]; exists {
+			return value, nil
+		}
 	}
 
 	// 如果缓存中没有，尝试从数据库获取（可能是新添加的配置）
@@ -391,12 +430,6 @@ func (r *SystemConfigRepositoryImpl) GetConfigValue(key string) (string, error)
 	if err != nil {
 		return "", err
 	}
-
-	// 更新缓存
-	r.configCacheMutex.Lock()
-	r.configCache[key] = config.Value
-	r.configCacheMutex.Unlock()
-
 	return config.Value, nil
 }
 
@@ -432,26 +465,20 @@ func (r *SystemConfigRepositoryImpl) GetConfigInt(key string) (int, error) {
 
 // GetCachedConfigs 获取所有缓存的配置（用于调试）
 func (r *SystemConfigRepositoryImpl) GetCachedConfigs() map[string]string {
-	r.initConfigCache()
-
-	r.configCacheMutex.RLock()
-	defer r.configCacheMutex.RUnlock()
+	result := make(map[string]string)
+	configs, err := r.cachedConfigs()
+	if err != nil {
+		return result
+	}
 
 	// 返回缓存的副本
-	result := make(map[string]string)
-	for k, v := range r.configCache {
+	for k, v := range configs {
 		result[k] = v
 	}
-
 	return result
 }
 
 // ClearConfigCache 清空配置缓存（用于测试或手动刷新）
 func (r *SystemConfigRepositoryImpl) ClearConfigCache() {
-	r.configCacheMutex.Lock()
-	r.configCache = make(map[string]string)
-	r.configCacheMutex.Unlock()
-
-	// 重置Once，允许重新初始化
-	r.configCacheOnce = sync.Once{}
+	cache.Default().Invalidate(context.Background(), cache.AllTag("config"))
 }
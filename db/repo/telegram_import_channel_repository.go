@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"gorm.io/gorm"
+)
+
+// TelegramImportChannelRepository Telegram 采集频道Repository接口
+type TelegramImportChannelRepository interface {
+	BaseRepository[entity.TelegramImportChannel]
+	FindByChatID(chatID int64) (*entity.TelegramImportChannel, error)
+	RecordImport(id uint, lastMessageID int64, imported int, at time.Time) error
+}
+
+// TelegramImportChannelRepositoryImpl Telegram 采集频道Repository实现
+type TelegramImportChannelRepositoryImpl struct {
+	BaseRepositoryImpl[entity.TelegramImportChannel]
+}
+
+// NewTelegramImportChannelRepository 创建 Telegram 采集频道Repository
+func NewTelegramImportChannelRepository(db *gorm.DB) TelegramImportChannelRepository {
+	return &TelegramImportChannelRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.TelegramImportChannel]{db: db},
+	}
+}
+
+// FindByChatID 根据频道ID查找
+func (r *TelegramImportChannelRepositoryImpl) FindByChatID(chatID int64) (*entity.TelegramImportChannel, error) {
+	var channel entity.TelegramImportChannel
+	err := r.db.Where("chat_id = ?", chatID).First(&channel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// RecordImport 记录一次导入：累加导入数，last_message_id 只增不减
+func (r *TelegramImportChannelRepositoryImpl) RecordImport(id uint, lastMessageID int64, imported int, at time.Time) error {
+	return r.db.Model(&entity.TelegramImportChannel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_message_id": gorm.Expr("GREATEST(last_message_id, ?)", lastMessageID),
+		"imported_count":  gorm.Expr("imported_count + ?", imported),
+		"last_import_at":  at,
+	}).Error
+}
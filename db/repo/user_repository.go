@@ -13,6 +13,7 @@ type UserRepository interface {
 	FindByEmail(email string) (*entity.User, error)
 	UpdateLastLogin(id uint) error
 	FindByRole(role string) ([]entity.User, error)
+	UpdateEventSubscriptions(id uint, subscriptions string) error
 }
 
 // UserRepositoryImpl 用户Repository实现
@@ -53,6 +54,12 @@ func (r *UserRepositoryImpl) UpdateLastLogin(id uint) error {
 		UpdateColumn("last_login", gorm.Expr("CURRENT_TIMESTAMP")).Error
 }
 
+// UpdateEventSubscriptions 更新实时事件订阅
+func (r *UserRepositoryImpl) UpdateEventSubscriptions(id uint, subscriptions string) error {
+	return r.db.Model(&entity.User{}).Where("id = ?", id).
+		UpdateColumn("event_subscriptions", subscriptions).Error
+}
+
 // FindByRole 根据角色查找用户
 func (r *UserRepositoryImpl) FindByRole(role string) ([]entity.User, error) {
 	var users []entity.User
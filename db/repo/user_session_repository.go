@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+
+	"gorm.io/gorm"
+)
+
+// UserSessionRepository 登录会话Repository接口
+type UserSessionRepository interface {
+	BaseRepository[entity.UserSession]
+	FindBySessionID(sessionID string) (*entity.UserSession, error)
+	FindActiveByUser(userID uint, now time.Time) ([]entity.UserSession, error)
+	RotateRefreshToken(sessionID, oldHash, newHash, ip string, at, expiresAt time.Time) (bool, error)
+	Revoke(sessionID, reason string, at time.Time) error
+	RevokeByUser(userID uint, exceptSessionID, reason string, at time.Time) ([]string, error)
+	DeleteExpiredBefore(before time.Time) (int64, error)
+}
+
+// UserSessionRepositoryImpl 登录会话Repository实现
+type UserSessionRepositoryImpl struct {
+	BaseRepositoryImpl[entity.UserSession]
+}
+
+// NewUserSessionRepository 创建登录会话Repository
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepository {
+	return &UserSessionRepositoryImpl{
+		BaseRepositoryImpl: BaseRepositoryImpl[entity.UserSession]{db: db},
+	}
+}
+
+// FindBySessionID 根据会话标识查找
+func (r *UserSessionRepositoryImpl) FindBySessionID(sessionID string) (*entity.UserSession, error) {
+	var session entity.UserSession
+	err := r.db.Where("session_id = ?", sessionID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUser 查找用户未吊销且未过期的会话，最近使用的在前
+func (r *UserSessionRepositoryImpl) FindActiveByUser(userID uint, now time.Time) ([]entity.UserSession, error) {
+	var sessions []entity.UserSession
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RotateRefreshToken 仅当当前刷新令牌仍为 oldHash 时替换为 newHash，并发刷新时只有一个请求成功
+func (r *UserSessionRepositoryImpl) RotateRefreshToken(sessionID, oldHash, newHash, ip string, at, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.UserSession{}).
+		Where("session_id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", sessionID, oldHash).
+		UpdateColumns(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": oldHash,
+			"ip":                  ip,
+			"rotated_at":          at,
+			"last_used_at":        at,
+			"expires_at":          expiresAt,
+			"updated_at":          at,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Revoke 吊销单个会话
+func (r *UserSessionRepositoryImpl) Revoke(sessionID, reason string, at time.Time) error {
+	return r.db.Model(&entity.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		UpdateColumns(map[string]interface{}{"revoked_at": at, "revoke_reason": reason, "updated_at": at}).Error
+}
+
+// RevokeByUser 吊销用户的全部会话（exceptSessionID 除外），返回被吊销的会话标识
+func (r *UserSessionRepositoryImpl) RevokeByUser(userID uint, exceptSessionID, reason string, at time.Time) ([]string, error) {
+	query := r.db.Model(&entity.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptSessionID != "" {
+		query = query.Where("session_id <> ?", exceptSessionID)
+	}
+	var sessionIDs []string
+	if err := query.Pluck("session_id", &sessionIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+	err := r.db.Model(&entity.UserSession{}).
+		Where("session_id IN ? AND revoked_at IS NULL", sessionIDs).
+		UpdateColumns(map[string]interface{}{"revoked_at": at, "revoke_reason": reason, "updated_at": at}).Error
+	return sessionIDs, err
+}
+
+// DeleteExpiredBefore 删除在 before 之前已过期或已吊销的会话
+func (r *UserSessionRepositoryImpl) DeleteExpiredBefore(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ? OR revoked_at < ?", before, before).Delete(&entity.UserSession{})
+	return result.RowsAffected, result.Error
+}
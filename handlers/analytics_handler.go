@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// analyticsBackfillTimeout 后台补汇总的最长执行时间
+const analyticsBackfillTimeout = 6 * time.Hour
+
+// AnalyticsHandler 统计日汇总处理器
+type AnalyticsHandler struct {
+	service  *services.AnalyticsService
+	validate *validator.Validate
+	running  atomic.Bool // 后台补汇总是否在执行
+}
+
+// NewAnalyticsHandler 创建统计日汇总处理器
+func NewAnalyticsHandler(service *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// GetTrend 获取每日趋势
+// @Summary 获取统计趋势
+// @Tags Analytics
+// @Produce json
+// @Param metric query string false "指标 views/visitors/searches/api_requests" default(views)
+// @Param group_by query string false "分组维度，仅 views/searches 支持 source"
+// @Param days query int false "最近N天（含今天），1-366" default(30)
+// @Success 200 {object} Response{data=services.AnalyticsTrend}
+// @Router /analytics/trend [get]
+func (h *AnalyticsHandler) GetTrend(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 366 {
+		ErrorResponse(c, "参数错误: days 须在 1-366 之间", http.StatusBadRequest)
+		return
+	}
+
+	trend, err := h.service.Trend(c.DefaultQuery("metric", "views"), c.Query("group_by"), days)
+	if err != nil {
+		ErrorResponse(c, "获取趋势失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, trend)
+}
+
+// GetLeaderboard 获取排行榜
+// @Summary 获取统计排行榜
+// @Tags Analytics
+// @Produce json
+// @Param dimension query string false "维度 resource/category/pan/source/keyword/endpoint" default(resource)
+// @Param days query int false "最近N天（含今天），0表示全部" default(7)
+// @Param limit query int false "条数，最多100" default(20)
+// @Success 200 {object} Response{data=[]entity.AnalyticsLeaderboardItem}
+// @Router /analytics/leaderboard [get]
+func (h *AnalyticsHandler) GetLeaderboard(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if days < 0 || days > 3650 {
+		ErrorResponse(c, "参数错误: days 须在 0-3650 之间", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	items, err := h.service.Leaderboard(c.DefaultQuery("dimension", "resource"), days, limit)
+	if err != nil {
+		ErrorResponse(c, "获取排行榜失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, items)
+}
+
+// GetStatus 获取汇总状态与配置
+// @Summary 获取统计汇总状态
+// @Tags Analytics
+// @Produce json
+// @Success 200 {object} Response{data=services.AnalyticsStatus}
+// @Router /analytics/status [get]
+func (h *AnalyticsHandler) GetStatus(c *gin.Context) {
+	status, err := h.service.Status()
+	if err != nil {
+		ErrorResponse(c, "获取汇总状态失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{
+		"status":  status,
+		"running": h.running.Load(),
+	})
+}
+
+// UpdateConfig 更新汇总开关与原始记录保留天数
+// @Summary 更新统计汇总配置
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param request body dto.AnalyticsConfigRequest true "配置"
+// @Success 200 {object} Response{data=services.AnalyticsConfig}
+// @Router /analytics/config [put]
+func (h *AnalyticsHandler) UpdateConfig(c *gin.Context) {
+	var req dto.AnalyticsConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := services.AnalyticsConfig{
+		RollupEnabled:       req.RollupEnabled,
+		ViewRetentionDays:   req.ViewRetentionDays,
+		SearchRetentionDays: req.SearchRetentionDays,
+		APILogRetentionDays: req.APILogRetentionDays,
+	}
+	if err := h.service.SaveConfig(cfg); err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, h.service.Config())
+}
+
+// Rollup 重新汇总指定日期
+// @Summary 重新汇总指定日期
+// @Tags Analytics
+// @Produce json
+// @Param date query string true "日期 YYYY-MM-DD"
+// @Success 200 {object} Response{data=entity.AnalyticsRollupDay}
+// @Router /analytics/rollup [post]
+func (h *AnalyticsHandler) Rollup(c *gin.Context) {
+	day, err := h.service.RollupDay(c.Query("date"))
+	if err != nil {
+		ErrorResponse(c, "汇总失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, day)
+}
+
+// Backfill 在后台补汇总日期区间
+// @Summary 补汇总历史统计
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param request body dto.AnalyticsBackfillRequest true "日期区间，均可为空"
+// @Success 200 {object} Response
+// @Router /analytics/backfill [post]
+func (h *AnalyticsHandler) Backfill(c *gin.Context) {
+	var req dto.AnalyticsBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.running.CompareAndSwap(false, true) {
+		ErrorResponse(c, "已有补汇总任务在执行中", http.StatusConflict)
+		return
+	}
+	go func() {
+		defer h.running.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), analyticsBackfillTimeout)
+		defer cancel()
+
+		result, err := h.service.Backfill(ctx, req.From, req.To, req.Force)
+		if err != nil {
+			utils.Error("补汇总统计数据失败: %v", err)
+			return
+		}
+		utils.Info("补汇总统计数据完成: %s ~ %s, 汇总 %d 天, 跳过 %d 天, 失败 %d 天",
+			result.From, result.To, len(result.Rolled), result.Skipped, len(result.Failed))
+	}()
+	SuccessResponse(c, gin.H{"message": "任务已在后台执行"})
+}
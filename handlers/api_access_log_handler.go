@@ -18,6 +18,7 @@ func GetAPIAccessLogs(c *gin.Context) {
 	endDateStr := c.Query("end_date")
 	endpoint := c.Query("endpoint")
 	ip := c.Query("ip")
+	apiKeyID, _ := strconv.ParseUint(c.Query("api_key_id"), 10, 32)
 
 	var startDate, endDate *time.Time
 
@@ -36,7 +37,7 @@ func GetAPIAccessLogs(c *gin.Context) {
 	}
 
 	// 获取分页数据
-	logs, total, err := repoManager.APIAccessLogRepository.FindWithFilters(page, pageSize, startDate, endDate, endpoint, ip)
+	logs, total, err := repoManager.APIAccessLogRepository.FindWithFilters(page, pageSize, startDate, endDate, endpoint, ip, uint(apiKeyID))
 	if err != nil {
 		ErrorResponse(c, "获取API访问日志失败: "+err.Error(), http.StatusInternalServerError)
 		return
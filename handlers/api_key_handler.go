@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/converter"
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// APIKeyHandler 公开API密钥管理处理器
+type APIKeyHandler struct {
+	service  *services.APIKeyService
+	validate *validator.Validate
+}
+
+// NewAPIKeyHandler 创建公开API密钥管理处理器
+func NewAPIKeyHandler(service *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// List 获取全部API密钥
+// @Summary 获取API密钥列表
+// @Tags APIKey
+// @Produce json
+// @Success 200 {object} Response{data=[]dto.APIKeyResponse}
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List()
+	if err != nil {
+		ErrorResponse(c, "获取API密钥列表失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, converter.ToAPIKeyResponseList(keys, utils.GetCurrentTime()))
+}
+
+// Issue 签发API密钥，明文只在响应中返回一次
+// @Summary 签发API密钥
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param body body dto.APIKeyRequest true "密钥设置"
+// @Success 200 {object} Response{data=dto.APIKeySecretResponse}
+// @Failure 400 {object} Response
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Issue(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+	username := c.GetString("username")
+	key, token, err := h.service.Issue(req, username)
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	utils.Info("IssueAPIKey - 签发API密钥 - 操作人: %s, 名称: %s, 前缀: %s, 作用域: %s", username, key.Name, key.Prefix, key.Scopes)
+	SuccessResponse(c, dto.APIKeySecretResponse{Key: converter.ToAPIKeyResponse(key, utils.GetCurrentTime()), Token: token})
+}
+
+// Update 更新API密钥设置
+// @Summary 更新API密钥
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param id path int true "密钥ID"
+// @Param body body dto.APIKeyRequest true "密钥设置"
+// @Success 200 {object} Response{data=dto.APIKeyResponse}
+// @Failure 400 {object} Response
+// @Router /api-keys/{id} [put]
+func (h *APIKeyHandler) Update(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+	key, err := h.service.Update(id, req)
+	if err != nil {
+		ErrorResponse(c, err.Error(), apiKeyErrorStatus(err))
+		return
+	}
+	utils.Info("UpdateAPIKey - 更新API密钥 - 操作人: %s, 前缀: %s", c.GetString("username"), key.Prefix)
+	SuccessResponse(c, converter.ToAPIKeyResponse(key, utils.GetCurrentTime()))
+}
+
+// Rotate 轮换API密钥，旧密钥立即失效
+// @Summary 轮换API密钥
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "密钥ID"
+// @Success 200 {object} Response{data=dto.APIKeySecretResponse}
+// @Failure 400 {object} Response
+// @Router /api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) Rotate(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+	key, token, err := h.service.Rotate(id)
+	if err != nil {
+		ErrorResponse(c, err.Error(), apiKeyErrorStatus(err))
+		return
+	}
+	utils.Info("RotateAPIKey - 轮换API密钥 - 操作人: %s, 名称: %s, 新前缀: %s", c.GetString("username"), key.Name, key.Prefix)
+	SuccessResponse(c, dto.APIKeySecretResponse{Key: converter.ToAPIKeyResponse(key, utils.GetCurrentTime()), Token: token})
+}
+
+// Revoke 吊销API密钥
+// @Summary 吊销API密钥
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "密钥ID"
+// @Success 200 {object} Response{data=dto.APIKeyResponse}
+// @Failure 404 {object} Response
+// @Router /api-keys/{id}/revoke [post]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+	key, err := h.service.Revoke(id)
+	if err != nil {
+		ErrorResponse(c, err.Error(), apiKeyErrorStatus(err))
+		return
+	}
+	utils.Info("RevokeAPIKey - 吊销API密钥 - 操作人: %s, 名称: %s, 前缀: %s", c.GetString("username"), key.Name, key.Prefix)
+	SuccessResponse(c, converter.ToAPIKeyResponse(key, utils.GetCurrentTime()))
+}
+
+// Usage 获取API密钥每日用量
+// @Summary 获取API密钥用量
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "密钥ID"
+// @Param days query int false "天数" default(30)
+// @Success 200 {object} Response{data=dto.APIKeyUsageResponse}
+// @Failure 404 {object} Response
+// @Router /api-keys/{id}/usage [get]
+func (h *APIKeyHandler) Usage(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 366 {
+		ErrorResponse(c, "参数错误: days 取值范围 1-366", http.StatusBadRequest)
+		return
+	}
+	usage, err := h.service.Usage(id, days)
+	if err != nil {
+		ErrorResponse(c, err.Error(), apiKeyErrorStatus(err))
+		return
+	}
+	SuccessResponse(c, usage)
+}
+
+func (h *APIKeyHandler) bindRequest(c *gin.Context) (dto.APIKeyRequest, bool) {
+	var req dto.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+func parseAPIKeyID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrAPIKeyRevoked):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
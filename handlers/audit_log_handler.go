@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// AuditLogHandler 操作审计日志处理器
+type AuditLogHandler struct {
+	service  *services.AuditLogService
+	validate *validator.Validate
+}
+
+// NewAuditLogHandler 创建操作审计日志处理器
+func NewAuditLogHandler(service *services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// bindFilter 解析查询条件，失败时已写入错误响应
+func (h *AuditLogHandler) bindFilter(c *gin.Context) (*dto.AuditLogListRequest, entity.AuditLogFilter, bool) {
+	var req dto.AuditLogListRequest
+	var filter entity.AuditLogFilter
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return nil, filter, false
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return nil, filter, false
+	}
+
+	filter = entity.AuditLogFilter{
+		UserID:     req.UserID,
+		Username:   req.Username,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		IP:         req.IP,
+		Success:    req.Success,
+	}
+	if req.StartDate != "" {
+		start, _ := time.ParseInLocation(utils.TimeFormatDate, req.StartDate, time.Local)
+		filter.StartTime = &start
+	}
+	if req.EndDate != "" {
+		end, _ := time.ParseInLocation(utils.TimeFormatDate, req.EndDate, time.Local)
+		end = end.AddDate(0, 0, 1)
+		filter.EndTime = &end
+	}
+	return &req, filter, true
+}
+
+// List 分页查询审计日志
+// @Summary 查询操作审计日志
+// @Tags AuditLog
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param user_id query int false "操作人ID"
+// @Param username query string false "操作人"
+// @Param action query string false "操作，支持前缀，如 cks"
+// @Param target_type query string false "对象类型"
+// @Param target_id query string false "对象ID"
+// @Param ip query string false "来源IP"
+// @Param success query bool false "是否成功"
+// @Param start_date query string false "开始日期 YYYY-MM-DD"
+// @Param end_date query string false "结束日期 YYYY-MM-DD"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /audit-logs [get]
+func (h *AuditLogHandler) List(c *gin.Context) {
+	req, filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+	logs, total, err := h.service.List(filter, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取审计日志失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, logs, total, req.Page, req.PageSize)
+}
+
+// Export 按查询条件导出审计日志为 CSV
+// @Summary 导出操作审计日志
+// @Tags AuditLog
+// @Produce text/csv
+// @Param username query string false "操作人"
+// @Param action query string false "操作"
+// @Param start_date query string false "开始日期 YYYY-MM-DD"
+// @Param end_date query string false "结束日期 YYYY-MM-DD"
+// @Success 200 {file} file
+// @Router /audit-logs/export [get]
+func (h *AuditLogHandler) Export(c *gin.Context) {
+	_, filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	count, err := h.service.ExportCSV(c.Writer, filter)
+	if err != nil {
+		// 已开始写入响应体，无法再返回 JSON 错误
+		utils.Error("AuditLog - 导出审计日志失败: %v", err)
+		return
+	}
+	utils.Info("AuditLog - 导出审计日志 - 操作人: %s, IP: %s, 条数: %d", c.GetString("username"), c.ClientIP(), count)
+}
+
+// GetConfig 获取审计日志配置
+// @Summary 获取审计日志配置
+// @Tags AuditLog
+// @Produce json
+// @Success 200 {object} Response{data=dto.AuditLogConfigResponse}
+// @Router /audit-logs/config [get]
+func (h *AuditLogHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, dto.AuditLogConfigResponse{RetentionDays: h.service.RetentionDays()})
+}
+
+// UpdateConfig 更新审计日志保留天数
+// @Summary 更新审计日志配置
+// @Tags AuditLog
+// @Accept json
+// @Produce json
+// @Param body body dto.AuditLogConfigRequest true "配置"
+// @Success 200 {object} Response{data=dto.AuditLogConfigResponse}
+// @Failure 400 {object} Response
+// @Router /audit-logs/config [put]
+func (h *AuditLogHandler) UpdateConfig(c *gin.Context) {
+	var req dto.AuditLogConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	before := dto.AuditLogConfigResponse{RetentionDays: h.service.RetentionDays()}
+	if err := h.service.SaveRetentionDays(req.RetentionDays); err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	after := dto.AuditLogConfigResponse{RetentionDays: h.service.RetentionDays()}
+	middleware.SetAuditChange(c, before, after)
+	SuccessResponse(c, after)
+}
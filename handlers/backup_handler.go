@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// backupRunTimeout 手动触发备份的最长执行时间
+const backupRunTimeout = 6 * time.Hour
+
+// BackupHandler 备份管理处理器。恢复会覆盖数据库，只通过命令行 urldb backup restore 执行
+type BackupHandler struct {
+	service  *services.BackupService
+	validate *validator.Validate
+}
+
+// NewBackupHandler 创建备份管理处理器
+func NewBackupHandler(service *services.BackupService) *BackupHandler {
+	return &BackupHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// List 获取备份列表与状态
+// @Summary 获取备份列表
+// @Tags Backup
+// @Produce json
+// @Success 200 {object} Response
+// @Router /backups [get]
+func (h *BackupHandler) List(c *gin.Context) {
+	backups, err := h.service.List(c.Request.Context())
+	if err != nil {
+		ErrorResponse(c, "获取备份列表失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{
+		"backups": backups,
+		"status":  h.service.Status(),
+	})
+}
+
+// Run 在后台立即执行一次备份
+// @Summary 立即备份
+// @Tags Backup
+// @Produce json
+// @Success 200 {object} Response
+// @Router /backups [post]
+func (h *BackupHandler) Run(c *gin.Context) {
+	if h.service.Status().Running {
+		ErrorResponse(c, "备份正在进行中", http.StatusConflict)
+		return
+	}
+	username, _ := c.Get("username")
+	utils.Info("Backup - 用户手动触发备份 - 用户: %s", username)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backupRunTimeout)
+		defer cancel()
+		if _, err := h.service.Run(ctx); err != nil {
+			utils.Error("手动备份失败: %v", err)
+		}
+	}()
+	SuccessResponse(c, gin.H{"message": "备份已在后台执行"})
+}
+
+// Verify 下载并完整校验备份
+// @Summary 校验备份
+// @Tags Backup
+// @Produce json
+// @Param name path string true "备份名"
+// @Success 200 {object} Response
+// @Router /backups/{name}/verify [post]
+func (h *BackupHandler) Verify(c *gin.Context) {
+	manifest, err := h.service.Verify(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		ErrorResponse(c, "校验失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, manifest)
+}
+
+// GetConfig 获取备份配置
+// @Summary 获取备份配置
+// @Tags Backup
+// @Produce json
+// @Success 200 {object} Response{data=services.BackupConfig}
+// @Router /backups/config [get]
+func (h *BackupHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, h.service.Config())
+}
+
+// UpdateConfig 更新备份配置
+// @Summary 更新备份配置
+// @Tags Backup
+// @Accept json
+// @Produce json
+// @Param request body dto.BackupConfigRequest true "配置"
+// @Success 200 {object} Response{data=services.BackupConfig}
+// @Router /backups/config [put]
+func (h *BackupHandler) UpdateConfig(c *gin.Context) {
+	var req dto.BackupConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := services.BackupConfig{
+		Enabled:        req.Enabled,
+		IntervalHours:  req.IntervalHours,
+		KeepLast:       req.KeepLast,
+		KeepDaily:      req.KeepDaily,
+		KeepWeekly:     req.KeepWeekly,
+		IncludeUploads: req.IncludeUploads,
+		IncludePlugins: req.IncludePlugins,
+		DBMode:         req.DBMode,
+	}
+	if err := h.service.SaveConfig(cfg); err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, h.service.Config())
+}
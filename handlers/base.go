@@ -8,6 +8,8 @@ import (
 var repoManager *repo.RepositoryManager
 var meilisearchManager *services.MeilisearchManager
 var linkCheckService services.LinkCheckService
+var metadataService *services.MetadataService
+var ogImageService *services.OGImageService
 
 // SetRepositoryManager 设置Repository管理器
 func SetRepositoryManager(manager *repo.RepositoryManager) {
@@ -23,3 +25,13 @@ func SetMeilisearchManager(manager *services.MeilisearchManager) {
 func SetLinkCheckService(svc services.LinkCheckService) {
 	linkCheckService = svc
 }
+
+// SetMetadataService 设置影视元数据服务
+func SetMetadataService(svc *services.MetadataService) {
+	metadataService = svc
+}
+
+// SetOGImageService 设置OG图片服务，资源更新时用于清理缓存图片
+func SetOGImageService(svc *services.OGImageService) {
+	ogImageService = svc
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/converter"
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// BlocklistHandler 黑名单规则处理器
+type BlocklistHandler struct {
+	service  *services.BlocklistService
+	validate *validator.Validate
+}
+
+// NewBlocklistHandler 创建黑名单规则处理器
+func NewBlocklistHandler(service *services.BlocklistService) *BlocklistHandler {
+	return &BlocklistHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// ListRules 分页查询黑名单规则
+// @Summary 查询黑名单规则
+// @Tags Blocklist
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param kind query string false "匹配类型 url/share_id/title/author/domain"
+// @Param action query string false "处理方式 reject/hide/mask"
+// @Param source query string false "来源 manual/moderation"
+// @Param keyword query string false "匹配内容或备注关键词"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /blocklist [get]
+func (h *BlocklistHandler) ListRules(c *gin.Context) {
+	var req dto.BlocklistRuleListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rules, total, err := h.service.List(req.Kind, req.Action, req.Source, req.Keyword, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取黑名单规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, converter.BlocklistRulesToResponse(rules), total, req.Page, req.PageSize)
+}
+
+// CreateRule 创建黑名单规则
+// @Summary 创建黑名单规则
+// @Tags Blocklist
+// @Accept json
+// @Produce json
+// @Param body body dto.BlocklistRuleRequest true "规则"
+// @Success 200 {object} Response{data=dto.BlocklistRuleResponse}
+// @Failure 400 {object} Response
+// @Router /blocklist [post]
+func (h *BlocklistHandler) CreateRule(c *gin.Context) {
+	req, ok := h.bindRule(c)
+	if !ok {
+		return
+	}
+	rule := &entity.BlocklistRule{
+		Kind:      req.Kind,
+		Pattern:   req.Pattern,
+		Action:    req.Action,
+		Source:    entity.BlocklistSourceManual,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		Note:      req.Note,
+		CreatedBy: c.GetUint("user_id"),
+	}
+	if err := services.ValidateBlocklistRule(rule); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Create(rule); err != nil {
+		ErrorResponse(c, "创建黑名单规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	middleware.SetAuditAction(c, "blocklist.create", "blocklist_rule", strconv.FormatUint(uint64(rule.ID), 10))
+	middleware.SetAuditChange(c, nil, rule)
+	SuccessResponse(c, converter.BlocklistRuleToResponse(rule))
+}
+
+// UpdateRule 更新黑名单规则
+// @Summary 更新黑名单规则
+// @Tags Blocklist
+// @Accept json
+// @Produce json
+// @Param id path int true "规则ID"
+// @Param body body dto.BlocklistRuleRequest true "规则"
+// @Success 200 {object} Response{data=dto.BlocklistRuleResponse}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /blocklist/{id} [put]
+func (h *BlocklistHandler) UpdateRule(c *gin.Context) {
+	rule, ok := h.findRule(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindRule(c)
+	if !ok {
+		return
+	}
+
+	before := *rule
+	rule.Kind = req.Kind
+	rule.Pattern = req.Pattern
+	rule.Action = req.Action
+	rule.Note = req.Note
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := services.ValidateBlocklistRule(rule); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Update(rule); err != nil {
+		ErrorResponse(c, "更新黑名单规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	middleware.SetAuditAction(c, "blocklist.update", "blocklist_rule", strconv.FormatUint(uint64(rule.ID), 10))
+	middleware.SetAuditChange(c, before, rule)
+	SuccessResponse(c, converter.BlocklistRuleToResponse(rule))
+}
+
+// DeleteRule 删除黑名单规则，处置生成的规则删除后对应资源可重新入库
+// @Summary 删除黑名单规则
+// @Tags Blocklist
+// @Produce json
+// @Param id path int true "规则ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /blocklist/{id} [delete]
+func (h *BlocklistHandler) DeleteRule(c *gin.Context) {
+	rule, ok := h.findRule(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(rule.ID); err != nil {
+		ErrorResponse(c, "删除黑名单规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	middleware.SetAuditAction(c, "blocklist.delete", "blocklist_rule", strconv.FormatUint(uint64(rule.ID), 10))
+	middleware.SetAuditChange(c, rule, nil)
+	SuccessResponse(c, gin.H{"message": "黑名单规则删除成功"})
+}
+
+// TestRules 用链接、标题与作者测试当前启用的规则
+// @Summary 测试黑名单匹配
+// @Tags Blocklist
+// @Accept json
+// @Produce json
+// @Param body body dto.BlocklistTestRequest true "待测试的资源信息"
+// @Success 200 {object} Response{data=dto.BlocklistTestResponse}
+// @Failure 400 {object} Response
+// @Router /blocklist/test [post]
+func (h *BlocklistHandler) TestRules(c *gin.Context) {
+	var req dto.BlocklistTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := dto.BlocklistTestResponse{MaskedTitle: req.Title, Rules: []*dto.BlocklistRuleResponse{}}
+	if m := h.service.Match(services.BlocklistTarget{URL: req.URL, Title: req.Title, Author: req.Author}); m != nil {
+		response.Matched = true
+		response.Action = m.Action
+		response.Reason = m.Reason()
+		response.MaskedTitle = m.MaskTitle(req.Title)
+		response.Rules = converter.BlocklistRulesToResponse(m.Rules)
+	}
+	SuccessResponse(c, response)
+}
+
+func (h *BlocklistHandler) bindRule(c *gin.Context) (*dto.BlocklistRuleRequest, bool) {
+	var req dto.BlocklistRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func (h *BlocklistHandler) findRule(c *gin.Context) (*entity.BlocklistRule, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return nil, false
+	}
+	rule, err := h.service.Get(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ErrorResponse(c, "黑名单规则不存在", http.StatusNotFound)
+		} else {
+			ErrorResponse(c, "获取黑名单规则失败: "+err.Error(), http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+	return rule, true
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/catalog"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/task"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// catalogMaxUploadSize 导入文件的最大大小
+const catalogMaxUploadSize = 512 << 20
+
+// CatalogHandler 资源目录导出/导入处理器，导出和导入均作为后台任务执行
+type CatalogHandler struct {
+	repoMgr     *repo.RepositoryManager
+	taskManager *task.TaskManager
+}
+
+// NewCatalogHandler 创建资源目录处理器
+func NewCatalogHandler(repoMgr *repo.RepositoryManager, taskManager *task.TaskManager) *CatalogHandler {
+	return &CatalogHandler{
+		repoMgr:     repoMgr,
+		taskManager: taskManager,
+	}
+}
+
+// CreateExport 创建目录导出任务
+// @Summary 创建资源目录导出任务
+// @Description 按与资源列表相同的筛选参数导出资源（含分类、标签、网盘、资源组key、影视元数据），格式为 jsonl/csv/xlsx
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Param body body object true "format 与筛选参数"
+// @Success 200 {object} map[string]interface{}
+// @Router /catalog/export [post]
+func (h *CatalogHandler) CreateExport(c *gin.Context) {
+	var req struct {
+		Format string `json:"format"`
+		services.CatalogFilter
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Format == "" {
+		req.Format = catalog.FormatJSONL
+	}
+	if !isCatalogFormat(req.Format) {
+		ErrorResponse(c, "参数验证失败: 不支持的格式 "+req.Format, http.StatusBadRequest)
+		return
+	}
+
+	input, _ := json.Marshal(task.CatalogExportInput{Format: req.Format, Filter: req.CatalogFilter})
+	newTask, err := h.startTask(entity.TaskTypeCatalogExport, "资源目录导出 - "+strings.ToUpper(req.Format), string(input))
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	username, _ := c.Get("username")
+	utils.Info("CreateExport - 用户创建目录导出任务 - 用户: %s, 任务ID: %d, 格式: %s", username, newTask.ID, req.Format)
+	SuccessResponse(c, gin.H{
+		"task_id": newTask.ID,
+		"message": "导出任务已创建",
+	})
+}
+
+// CreateImport 创建目录导入任务
+// @Summary 创建资源目录导入任务
+// @Description 上传目录文件导入资源，按规范化链接去重；dry_run=true 时只生成报告。可通过 source_task_id 复用之前上传的文件
+// @Tags Catalog
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file false "目录文件（jsonl/csv/xlsx）"
+// @Param source_task_id formData int false "复用该导入任务上传的文件"
+// @Param format formData string false "文件格式，默认按扩展名识别"
+// @Param category_map formData string false "分类映射 JSON，如 {\"电影\":\"影视\"}"
+// @Param tag_map formData string false "标签映射 JSON，映射为空串表示丢弃"
+// @Param create_missing formData bool false "自动创建不存在的分类和标签"
+// @Param dry_run formData bool false "只生成报告不写入"
+// @Success 200 {object} map[string]interface{}
+// @Router /catalog/import [post]
+func (h *CatalogHandler) CreateImport(c *gin.Context) {
+	input := task.CatalogImportInput{
+		Options: services.CatalogImportOptions{
+			Format:        strings.ToLower(c.PostForm("format")),
+			CreateMissing: c.PostForm("create_missing") == "true",
+			DryRun:        c.PostForm("dry_run") == "true",
+		},
+	}
+	for field, target := range map[string]*map[string]string{
+		"category_map": &input.Options.CategoryMap,
+		"tag_map":      &input.Options.TagMap,
+	} {
+		if raw := strings.TrimSpace(c.PostForm(field)); raw != "" {
+			if err := json.Unmarshal([]byte(raw), target); err != nil {
+				ErrorResponse(c, fmt.Sprintf("参数错误: %s 不是有效的 JSON 对象", field), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	var upload *multipart.FileHeader
+	if sourceID := c.PostForm("source_task_id"); sourceID != "" {
+		source, err := h.importInput(sourceID)
+		if err != nil {
+			ErrorResponse(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		input.File, input.FileName = source.File, source.FileName
+		if input.Options.Format == "" {
+			input.Options.Format = source.Options.Format
+		}
+	} else {
+		var err error
+		if upload, err = c.FormFile("file"); err != nil {
+			ErrorResponse(c, "未提供导入文件", http.StatusBadRequest)
+			return
+		}
+		if upload.Size > catalogMaxUploadSize {
+			ErrorResponse(c, fmt.Sprintf("文件大小不能超过%dMB", catalogMaxUploadSize>>20), http.StatusBadRequest)
+			return
+		}
+		input.FileName = filepath.Base(upload.Filename)
+		if input.Options.Format == "" {
+			input.Options.Format = catalog.FormatFromName(input.FileName)
+		}
+	}
+	if !isCatalogFormat(input.Options.Format) {
+		ErrorResponse(c, "参数验证失败: 无法识别文件格式，请指定 format 为 jsonl/csv/xlsx", http.StatusBadRequest)
+		return
+	}
+
+	if upload != nil {
+		if err := os.MkdirAll(task.CatalogImportDir, 0755); err != nil {
+			ErrorResponse(c, "创建上传目录失败", http.StatusInternalServerError)
+			return
+		}
+		input.File = fmt.Sprintf("import_%d.%s", time.Now().UnixNano(), input.Options.Format)
+		if err := c.SaveUploadedFile(upload, filepath.Join(task.CatalogImportDir, input.File)); err != nil {
+			ErrorResponse(c, "保存文件失败", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	title := "资源目录导入 - " + input.FileName
+	if input.Options.DryRun {
+		title = "资源目录导入预检 - " + input.FileName
+	}
+	inputJSON, _ := json.Marshal(input)
+	newTask, err := h.startTask(entity.TaskTypeCatalogImport, title, string(inputJSON))
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	username, _ := c.Get("username")
+	utils.Info("CreateImport - 用户创建目录导入任务 - 用户: %s, 任务ID: %d, 文件: %s, 预检: %v", username, newTask.ID, input.FileName, input.Options.DryRun)
+	SuccessResponse(c, gin.H{
+		"task_id": newTask.ID,
+		"message": "导入任务已创建",
+	})
+}
+
+// GetResult 获取目录任务的状态与结果
+// @Summary 获取目录导出/导入任务结果
+// @Tags Catalog
+// @Produce json
+// @Param id path int true "任务ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /catalog/tasks/{id} [get]
+func (h *CatalogHandler) GetResult(c *gin.Context) {
+	t, item, err := h.catalogTask(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var output interface{}
+	if item.OutputData != "" {
+		var parsed map[string]interface{}
+		if json.Unmarshal([]byte(item.OutputData), &parsed) == nil {
+			output = parsed
+		}
+	}
+	SuccessResponse(c, gin.H{
+		"task_id": t.ID,
+		"type":    t.Type,
+		"title":   t.Title,
+		"status":  t.Status,
+		"message": t.Message,
+		"output":  output,
+	})
+}
+
+// Download 下载导出文件
+// @Summary 下载目录导出文件
+// @Tags Catalog
+// @Produce octet-stream
+// @Param id path int true "导出任务ID"
+// @Router /catalog/tasks/{id}/download [get]
+func (h *CatalogHandler) Download(c *gin.Context) {
+	t, item, err := h.catalogTask(c.Param("id"))
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	if t.Type != entity.TaskTypeCatalogExport {
+		ErrorResponse(c, "该任务不是导出任务", http.StatusBadRequest)
+		return
+	}
+	var output task.CatalogExportOutput
+	if json.Unmarshal([]byte(item.OutputData), &output) != nil || !output.Success {
+		ErrorResponse(c, "导出尚未完成", http.StatusConflict)
+		return
+	}
+	path := task.CatalogExportPath(t.ID, output.Format)
+	if _, err := os.Stat(path); err != nil {
+		ErrorResponse(c, "导出文件不存在或已过期", http.StatusNotFound)
+		return
+	}
+
+	name := fmt.Sprintf("catalog_%s.%s", t.CreatedAt.Format("20060102_150405"), output.Format)
+	c.Header("Content-Type", catalog.ContentType(output.Format))
+	c.FileAttachment(path, name)
+}
+
+// startTask 创建只有一个任务项的任务并立即启动
+func (h *CatalogHandler) startTask(taskType entity.TaskType, title, inputJSON string) (*entity.Task, error) {
+	newTask, err := h.taskManager.CreateTask(string(taskType), title, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.repoMgr.TaskRepository.UpdateTotalItems(newTask.ID, 1); err != nil {
+		utils.Error("更新任务项数失败: %v", err)
+	}
+	if err := h.taskManager.CreateTaskItems(newTask.ID, []*entity.TaskItem{{
+		Status:    "pending",
+		InputData: inputJSON,
+	}}); err != nil {
+		return nil, err
+	}
+	if err := h.taskManager.StartTask(newTask.ID); err != nil {
+		return nil, fmt.Errorf("启动任务失败: %v", err)
+	}
+	return newTask, nil
+}
+
+// catalogTask 查找目录任务及其唯一的任务项
+func (h *CatalogHandler) catalogTask(idParam string) (*entity.Task, *entity.TaskItem, error) {
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("无效的任务ID")
+	}
+	t, err := h.taskManager.GetTask(uint(id))
+	if err != nil || (t.Type != entity.TaskTypeCatalogExport && t.Type != entity.TaskTypeCatalogImport) {
+		return nil, nil, fmt.Errorf("任务不存在")
+	}
+	items, _, err := h.taskManager.QueryTaskItems(t.ID, 1, 1, "")
+	if err != nil || len(items) == 0 {
+		return nil, nil, fmt.Errorf("任务项不存在")
+	}
+	return t, items[0], nil
+}
+
+// importInput 读取之前导入任务的输入，用于预检后复用同一文件正式导入
+func (h *CatalogHandler) importInput(idParam string) (*task.CatalogImportInput, error) {
+	t, item, err := h.catalogTask(idParam)
+	if err != nil {
+		return nil, err
+	}
+	if t.Type != entity.TaskTypeCatalogImport {
+		return nil, fmt.Errorf("source_task_id 不是导入任务")
+	}
+	var input task.CatalogImportInput
+	if err := json.Unmarshal([]byte(item.InputData), &input); err != nil || input.File == "" {
+		return nil, fmt.Errorf("无法读取源任务的导入文件")
+	}
+	if _, err := os.Stat(filepath.Join(task.CatalogImportDir, filepath.Base(input.File))); err != nil {
+		return nil, fmt.Errorf("源任务的导入文件不存在或已过期")
+	}
+	return &input, nil
+}
+
+func isCatalogFormat(format string) bool {
+	for _, f := range catalog.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/ctwj/urldb/db/converter"
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/pkg/eventbus"
 	"github.com/ctwj/urldb/utils"
 
 	"github.com/gin-gonic/gin"
@@ -360,6 +362,7 @@ func UpdateCks(c *gin.Context) {
 		ErrorResponse(c, "Cookie不存在", http.StatusNotFound)
 		return
 	}
+	before := *cks
 
 	if req.PanID != 0 {
 		cks.PanID = req.PanID
@@ -396,6 +399,7 @@ func UpdateCks(c *gin.Context) {
 		ErrorResponse(c, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	middleware.SetAuditChange(c, before, cks)
 
 	SuccessResponse(c, gin.H{"message": "Cookie更新成功"})
 }
@@ -409,6 +413,10 @@ func DeleteCks(c *gin.Context) {
 		return
 	}
 
+	if cks, err := repoManager.CksRepository.FindByID(uint(id)); err == nil {
+		middleware.SetAuditChange(c, cks, nil)
+	}
+
 	err = repoManager.CksRepository.Delete(uint(id))
 	if err != nil {
 		ErrorResponse(c, err.Error(), http.StatusInternalServerError)
@@ -499,6 +507,13 @@ func RefreshCapacity(c *gin.Context) {
 		userInfo, err = service.GetUserInfo(&cks.Ck)
 	}
 	if err != nil {
+		eventbus.Publish(eventbus.TypeAccountFailed, map[string]interface{}{
+			"account_id": cks.ID,
+			"platform":   pan.Name,
+			"username":   cks.Username,
+			"reason":     err.Error(),
+			"source":     "manual_refresh",
+		})
 		ErrorResponse(c, "无法获取用户信息，刷新失败: "+err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/classifier"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ClassificationHandler 自动分类管理处理器
+type ClassificationHandler struct {
+	service    *services.ClassificationService
+	ruleRepo   repo.ClassificationRuleRepository
+	reviewRepo repo.ClassificationReviewRepository
+	validate   *validator.Validate
+}
+
+// NewClassificationHandler 创建自动分类管理处理器
+func NewClassificationHandler(service *services.ClassificationService, ruleRepo repo.ClassificationRuleRepository, reviewRepo repo.ClassificationReviewRepository) *ClassificationHandler {
+	return &ClassificationHandler{
+		service:    service,
+		ruleRepo:   ruleRepo,
+		reviewRepo: reviewRepo,
+		validate:   validator.New(),
+	}
+}
+
+// ListRules 获取分类规则列表
+// @Summary 获取分类规则列表
+// @Tags Classification
+// @Produce json
+// @Success 200 {object} Response{data=object{list=[]entity.ClassificationRule,total=int}}
+// @Router /classification/rules [get]
+func (h *ClassificationHandler) ListRules(c *gin.Context) {
+	rules, err := h.ruleRepo.FindAllWithTargets()
+	if err != nil {
+		ErrorResponse(c, "获取分类规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ListResponse(c, rules, int64(len(rules)))
+}
+
+// CreateRule 创建分类规则
+// @Summary 创建分类规则
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param request body dto.ClassificationRuleRequest true "规则"
+// @Success 200 {object} Response{data=entity.ClassificationRule}
+// @Router /classification/rules [post]
+func (h *ClassificationHandler) CreateRule(c *gin.Context) {
+	rule := &entity.ClassificationRule{Enabled: true, Confidence: services.DefaultClassifyAssignThreshold}
+	if !h.bindRule(c, rule) {
+		return
+	}
+	if err := h.ruleRepo.Create(rule); err != nil {
+		ErrorResponse(c, "创建分类规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.service.InvalidateRules()
+	SuccessResponse(c, rule)
+}
+
+// UpdateRule 更新分类规则
+// @Summary 更新分类规则
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param id path int true "规则ID"
+// @Param request body dto.ClassificationRuleRequest true "规则"
+// @Success 200 {object} Response{data=entity.ClassificationRule}
+// @Router /classification/rules/{id} [put]
+func (h *ClassificationHandler) UpdateRule(c *gin.Context) {
+	rule, ok := h.findRule(c)
+	if !ok {
+		return
+	}
+	if !h.bindRule(c, rule) {
+		return
+	}
+	rule.Category, rule.Tag = nil, nil
+	if err := h.ruleRepo.Update(rule); err != nil {
+		ErrorResponse(c, "更新分类规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.service.InvalidateRules()
+	SuccessResponse(c, rule)
+}
+
+// DeleteRule 删除分类规则
+// @Summary 删除分类规则
+// @Tags Classification
+// @Param id path int true "规则ID"
+// @Success 200 {object} Response
+// @Router /classification/rules/{id} [delete]
+func (h *ClassificationHandler) DeleteRule(c *gin.Context) {
+	rule, ok := h.findRule(c)
+	if !ok {
+		return
+	}
+	if err := h.ruleRepo.Delete(rule.ID); err != nil {
+		ErrorResponse(c, "删除分类规则失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.service.InvalidateRules()
+	SuccessResponse(c, gin.H{"message": "删除成功"})
+}
+
+// TestClassify 使用当前规则与模型对给定文本分类（不写入）
+// @Summary 分类测试
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param request body dto.ClassificationTestRequest true "待分类文本"
+// @Success 200 {object} Response{data=classifier.Result}
+// @Router /classification/test [post]
+func (h *ClassificationHandler) TestClassify(c *gin.Context) {
+	var req dto.ClassificationTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	useBayes := req.UseBayes == nil || *req.UseBayes
+	doc := classifier.Document{Title: req.Title, Description: req.Description, Files: req.Files}
+	SuccessResponse(c, h.service.Classify(doc, useBayes))
+}
+
+// ClassifyResource 对单个已入库资源分类
+// @Summary 对资源分类
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param id path int true "资源ID"
+// @Param apply query bool false "是否采纳并写入审核队列"
+// @Param request body dto.ClassificationOptionsRequest false "分类选项"
+// @Success 200 {object} Response{data=services.ClassificationDecision}
+// @Router /classification/resources/{id}/classify [post]
+func (h *ClassificationHandler) ClassifyResource(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ClassificationOptionsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apply := c.Query("apply") == "true"
+	decision, err := h.service.ClassifyResource(uint(id), toClassifyOptions(req), apply, services.ClassificationOriginManual)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "资源不存在", http.StatusNotFound)
+			return
+		}
+		ErrorResponse(c, "分类失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, decision)
+}
+
+// ClassifyUncategorized 批量处理未分类资源
+// @Summary 批量分类未分类资源
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param request body dto.ClassificationBatchRequest false "批量选项"
+// @Success 200 {object} Response{data=services.ClassificationBatchResult}
+// @Router /classification/classify-uncategorized [post]
+func (h *ClassificationHandler) ClassifyUncategorized(c *gin.Context) {
+	var req dto.ClassificationBatchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
+
+	result, err := h.service.ClassifyUncategorized(req.Limit, toClassifyOptions(req.ClassificationOptionsRequest))
+	if err != nil {
+		ErrorResponse(c, "批量分类失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+// TrainModel 使用已分类资源训练本地分类模型
+// @Summary 训练分类模型
+// @Tags Classification
+// @Produce json
+// @Success 200 {object} Response{data=entity.ClassifierModel}
+// @Router /classification/model/train [post]
+func (h *ClassificationHandler) TrainModel(c *gin.Context) {
+	model, err := h.service.Train()
+	if err != nil {
+		ErrorResponse(c, "训练失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, model)
+}
+
+// GetModel 获取本地分类模型信息
+// @Summary 获取分类模型信息
+// @Tags Classification
+// @Produce json
+// @Success 200 {object} Response{data=entity.ClassifierModel}
+// @Router /classification/model [get]
+func (h *ClassificationHandler) GetModel(c *gin.Context) {
+	model, err := h.service.ModelInfo()
+	if err != nil {
+		ErrorResponse(c, "获取模型信息失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"trained": model != nil, "model": model})
+}
+
+// ListReviews 获取分类审核队列
+// @Summary 获取分类审核队列
+// @Tags Classification
+// @Produce json
+// @Param status query string false "审核状态" default(pending)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /classification/reviews [get]
+func (h *ClassificationHandler) ListReviews(c *gin.Context) {
+	status := c.DefaultQuery("status", entity.ClassificationReviewPending)
+	if status == "all" {
+		status = ""
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	reviews, total, err := h.reviewRepo.FindByStatus(status, page, pageSize)
+	if err != nil {
+		ErrorResponse(c, "获取审核队列失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, reviews, total, page, pageSize)
+}
+
+// ApproveReview 采纳分类建议
+// @Summary 采纳分类建议
+// @Tags Classification
+// @Accept json
+// @Produce json
+// @Param id path int true "审核记录ID"
+// @Param request body dto.ClassificationReviewApproveRequest false "人工指定的分类与标签"
+// @Success 200 {object} Response{data=entity.ClassificationReview}
+// @Router /classification/reviews/{id}/approve [post]
+func (h *ClassificationHandler) ApproveReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+	var req dto.ClassificationReviewApproveRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	review, err := h.service.ApproveReview(uint(id), req.CategoryID, req.TagIDs)
+	if err != nil {
+		h.reviewError(c, err)
+		return
+	}
+	SuccessResponse(c, review)
+}
+
+// RejectReview 驳回分类建议
+// @Summary 驳回分类建议
+// @Tags Classification
+// @Produce json
+// @Param id path int true "审核记录ID"
+// @Success 200 {object} Response{data=entity.ClassificationReview}
+// @Router /classification/reviews/{id}/reject [post]
+func (h *ClassificationHandler) RejectReview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+	review, err := h.service.RejectReview(uint(id))
+	if err != nil {
+		h.reviewError(c, err)
+		return
+	}
+	SuccessResponse(c, review)
+}
+
+func (h *ClassificationHandler) reviewError(c *gin.Context, err error) {
+	if err == gorm.ErrRecordNotFound {
+		ErrorResponse(c, "审核记录不存在", http.StatusNotFound)
+		return
+	}
+	ErrorResponse(c, "审核失败: "+err.Error(), http.StatusBadRequest)
+}
+
+// findRule 按路径参数 id 查找规则，失败时已写入响应
+func (h *ClassificationHandler) findRule(c *gin.Context) (*entity.ClassificationRule, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return nil, false
+	}
+	rule, err := h.ruleRepo.FindByID(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "分类规则不存在", http.StatusNotFound)
+			return nil, false
+		}
+		ErrorResponse(c, "获取分类规则失败: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return rule, true
+}
+
+// bindRule 绑定并校验请求，写入 rule；失败时已写入响应
+func (h *ClassificationHandler) bindRule(c *gin.Context, rule *entity.ClassificationRule) bool {
+	var req dto.ClassificationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	rule.Name = req.Name
+	rule.Target = req.Target
+	rule.CategoryID = req.CategoryID
+	rule.TagID = req.TagID
+	rule.MatchType = req.MatchType
+	rule.Pattern = req.Pattern
+	rule.Fields = req.Fields
+	rule.Remark = req.Remark
+	if req.Confidence > 0 {
+		rule.Confidence = req.Confidence
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := h.service.ValidateRule(rule); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func toClassifyOptions(req dto.ClassificationOptionsRequest) services.ClassifyOptions {
+	opts := services.DefaultClassifyOptions()
+	if req.AssignThreshold > 0 {
+		opts.AssignThreshold = req.AssignThreshold
+	}
+	if req.ReviewThreshold > 0 {
+		opts.ReviewThreshold = req.ReviewThreshold
+	}
+	if req.UseBayes != nil {
+		opts.UseBayes = *req.UseBayes
+	}
+	if req.FetchFiles != nil {
+		opts.FetchFiles = *req.FetchFiles
+	}
+	opts.Overwrite = req.Overwrite
+	return opts
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/crawler"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// contentSourceRunTimeout 手动抓取的超时时间
+const contentSourceRunTimeout = 2 * time.Minute
+
+// ContentSourceHandler 内容源管理处理器
+type ContentSourceHandler struct {
+	sourceRepo repo.ContentSourceRepository
+	service    *services.ContentSourceService
+	validate   *validator.Validate
+}
+
+// NewContentSourceHandler 创建内容源管理处理器
+func NewContentSourceHandler(sourceRepo repo.ContentSourceRepository, service *services.ContentSourceService) *ContentSourceHandler {
+	return &ContentSourceHandler{
+		sourceRepo: sourceRepo,
+		service:    service,
+		validate:   validator.New(),
+	}
+}
+
+// ListContentSources 获取内容源列表
+// @Summary 获取内容源列表
+// @Tags ContentSource
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response{data=object{list=[]entity.ContentSource,total=int}}
+// @Router /content-sources [get]
+func (h *ContentSourceHandler) ListContentSources(c *gin.Context) {
+	var req dto.ContentSourceListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sources, total, err := h.sourceRepo.FindWithPagination(req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取内容源列表失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, sources, total, req.Page, req.PageSize)
+}
+
+// GetContentSource 获取内容源详情
+// @Summary 获取内容源详情
+// @Tags ContentSource
+// @Produce json
+// @Param id path int true "内容源ID"
+// @Success 200 {object} Response{data=entity.ContentSource}
+// @Router /content-sources/{id} [get]
+func (h *ContentSourceHandler) GetContentSource(c *gin.Context) {
+	source, ok := h.findSource(c)
+	if !ok {
+		return
+	}
+	SuccessResponse(c, source)
+}
+
+// CreateContentSource 创建内容源
+// @Summary 创建内容源
+// @Tags ContentSource
+// @Accept json
+// @Produce json
+// @Param request body dto.ContentSourceRequest true "内容源信息"
+// @Success 200 {object} Response{data=entity.ContentSource}
+// @Router /content-sources [post]
+func (h *ContentSourceHandler) CreateContentSource(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	source := &entity.ContentSource{Enabled: true, IntervalMinutes: 60}
+	applyContentSourceRequest(source, req)
+	if err := h.sourceRepo.Create(source); err != nil {
+		ErrorResponse(c, "创建内容源失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, source)
+}
+
+// UpdateContentSource 更新内容源
+// @Summary 更新内容源
+// @Tags ContentSource
+// @Accept json
+// @Produce json
+// @Param id path int true "内容源ID"
+// @Param request body dto.ContentSourceRequest true "内容源信息"
+// @Success 200 {object} Response{data=entity.ContentSource}
+// @Router /content-sources/{id} [put]
+func (h *ContentSourceHandler) UpdateContentSource(c *gin.Context) {
+	source, ok := h.findSource(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	applyContentSourceRequest(source, req)
+	if err := h.sourceRepo.Update(source); err != nil {
+		ErrorResponse(c, "更新内容源失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, source)
+}
+
+// DeleteContentSource 删除内容源（同时清除其去重记录）
+// @Summary 删除内容源
+// @Tags ContentSource
+// @Param id path int true "内容源ID"
+// @Success 200 {object} Response
+// @Router /content-sources/{id} [delete]
+func (h *ContentSourceHandler) DeleteContentSource(c *gin.Context) {
+	source, ok := h.findSource(c)
+	if !ok {
+		return
+	}
+	if err := h.sourceRepo.Delete(source.ID); err != nil {
+		ErrorResponse(c, "删除内容源失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.sourceRepo.DeleteItemsBySourceID(source.ID); err != nil {
+		ErrorResponse(c, "清除去重记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "删除成功"})
+}
+
+// RunContentSource 立即抓取内容源
+// @Summary 立即抓取内容源
+// @Tags ContentSource
+// @Produce json
+// @Param id path int true "内容源ID"
+// @Success 200 {object} Response{data=services.ContentSourceRunResult}
+// @Router /content-sources/{id}/run [post]
+func (h *ContentSourceHandler) RunContentSource(c *gin.Context) {
+	source, ok := h.findSource(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), contentSourceRunTimeout)
+	defer cancel()
+	result, err := h.service.RunSource(ctx, source)
+	if err != nil {
+		ErrorResponse(c, "抓取失败: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+// PreviewContentSource 预览抓取结果（不写入），用于调试选择器与字段映射
+// @Summary 预览内容源抓取结果
+// @Tags ContentSource
+// @Accept json
+// @Produce json
+// @Param request body dto.ContentSourceRequest true "内容源信息"
+// @Success 200 {object} Response{data=object{list=[]crawler.Item,total=int}}
+// @Router /content-sources/preview [post]
+func (h *ContentSourceHandler) PreviewContentSource(c *gin.Context) {
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	source := &entity.ContentSource{}
+	applyContentSourceRequest(source, req)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), contentSourceRunTimeout)
+	defer cancel()
+	items, err := h.service.Preview(ctx, source)
+	if err != nil {
+		ErrorResponse(c, "预览失败: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	ListResponse(c, items, int64(len(items)))
+}
+
+// ResetContentSourceCursor 重置内容源游标与去重记录
+// @Summary 重置内容源游标
+// @Tags ContentSource
+// @Param id path int true "内容源ID"
+// @Success 200 {object} Response
+// @Router /content-sources/{id}/reset-cursor [post]
+func (h *ContentSourceHandler) ResetContentSourceCursor(c *gin.Context) {
+	source, ok := h.findSource(c)
+	if !ok {
+		return
+	}
+	if err := h.service.ResetCursor(source.ID); err != nil {
+		ErrorResponse(c, "重置游标失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "重置成功"})
+}
+
+// findSource 按路径参数 id 查找内容源，失败时已写入响应
+func (h *ContentSourceHandler) findSource(c *gin.Context) (*entity.ContentSource, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	source, err := h.sourceRepo.FindByID(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "内容源不存在", http.StatusNotFound)
+			return nil, false
+		}
+		ErrorResponse(c, "获取内容源失败: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return source, true
+}
+
+// bindRequest 绑定并校验请求，同时校验采集配置 JSON
+func (h *ContentSourceHandler) bindRequest(c *gin.Context) (*dto.ContentSourceRequest, bool) {
+	var req dto.ContentSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if _, err := crawler.ParseConfig(req.Config); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func applyContentSourceRequest(source *entity.ContentSource, req *dto.ContentSourceRequest) {
+	source.Name = req.Name
+	source.Type = req.Type
+	source.URL = req.URL
+	source.Config = req.Config
+	source.Category = req.Category
+	source.Tags = req.Tags
+	if req.IntervalMinutes > 0 {
+		source.IntervalMinutes = req.IntervalMinutes
+	}
+	if req.Enabled != nil {
+		source.Enabled = *req.Enabled
+	}
+}
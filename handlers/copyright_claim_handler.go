@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"github.com/ctwj/urldb/db/converter"
@@ -8,6 +9,7 @@ import (
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
 	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -214,7 +216,7 @@ func (h *CopyrightClaimHandler) UpdateCopyrightClaim(c *gin.Context) {
 	}
 
 	// 获取当前版权申述
-	_, err = h.copyrightClaimRepo.GetByID(uint(id))
+	claim, err := h.copyrightClaimRepo.GetByID(uint(id))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ErrorResponse(c, "版权申述不存在", http.StatusNotFound)
@@ -230,19 +232,39 @@ func (h *CopyrightClaimHandler) UpdateCopyrightClaim(c *gin.Context) {
 		processedBy = currentUser
 	}
 
-	if err := h.copyrightClaimRepo.UpdateStatus(uint(id), req.Status, &processedBy, req.Note); err != nil {
-		ErrorResponse(c, "更新版权申述状态失败: "+err.Error(), http.StatusInternalServerError)
-		return
+	// 通过处置服务处理：通过时下架同组资源并禁止重新入库，改为驳回时撤销已有处置
+	var action *entity.ModerationAction
+	var updatedClaim *entity.CopyrightClaim
+	if svc := services.GetDefaultModerationService(); svc != nil {
+		updatedClaim, action, err = svc.ModerateClaim(uint(id), moderationDecision(req.Status, req.Note, req.ModerationFields), processedBy)
+		if err != nil {
+			ErrorResponse(c, "处理版权申述失败: "+err.Error(), moderationErrorStatus(err))
+			return
+		}
+	} else {
+		if err := h.copyrightClaimRepo.UpdateStatus(uint(id), req.Status, &processedBy, req.Note); err != nil {
+			ErrorResponse(c, "更新版权申述状态失败: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// 获取更新后的版权申述信息
+		updatedClaim, err = h.copyrightClaimRepo.GetByID(uint(id))
+		if err != nil {
+			ErrorResponse(c, "获取更新后版权申述信息失败: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
-	// 获取更新后的版权申述信息
-	updatedClaim, err := h.copyrightClaimRepo.GetByID(uint(id))
-	if err != nil {
-		ErrorResponse(c, "获取更新后版权申述信息失败: "+err.Error(), http.StatusInternalServerError)
-		return
+	// 审计：按处理结果记录为 copyright_claims.approved / rejected 等
+	middleware.SetAuditAction(c, "copyright_claims."+req.Status, "", "")
+	middleware.SetAuditChange(c, claim, updatedClaim)
+	if action != nil {
+		middleware.SetAuditDetail(c, fmt.Sprintf("处置 #%d: %s 资源 %s", action.ID, action.Action, action.ResourceIDs))
 	}
 
-	SuccessResponse(c, converter.CopyrightClaimToResponse(updatedClaim))
+	response := converter.CopyrightClaimToResponse(updatedClaim)
+	response.ModerationAction = converter.ModerationActionToResponse(action)
+	SuccessResponse(c, response)
 }
 
 // DeleteCopyrightClaim 删除版权申述
@@ -304,9 +326,9 @@ func RegisterCopyrightClaimRoutes(router *gin.RouterGroup, copyrightClaimRepo re
 	{
 		claims.POST("", handler.CreateCopyrightClaim)                    // 创建版权申述
 		claims.GET("/:id", handler.GetCopyrightClaim)                    // 获取版权申述详情
-		claims.GET("", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.ListCopyrightClaims)                      // 获取版权申述列表
-		claims.PUT("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.UpdateCopyrightClaim)                 // 更新版权申述状态
-		claims.DELETE("/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handler.DeleteCopyrightClaim)              // 删除版权申述
+		claims.GET("", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), handler.ListCopyrightClaims)                      // 获取版权申述列表
+		claims.PUT("/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), handler.UpdateCopyrightClaim)                 // 更新版权申述状态
+		claims.DELETE("/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), handler.DeleteCopyrightClaim)              // 删除版权申述
 		claims.GET("/resource/:resource_key", handler.GetCopyrightClaimByResource) // 获取资源版权申述列表
 	}
 }
\ No newline at end of file
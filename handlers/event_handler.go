@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// eventHeartbeatInterval SSE 心跳间隔，需小于反向代理的空闲超时
+	eventHeartbeatInterval = 25 * time.Second
+	// eventStreamBuffer 每个连接的事件缓冲，客户端消费过慢时超出部分丢弃
+	eventStreamBuffer = 256
+)
+
+// EventHandler 管理后台实时事件处理器
+type EventHandler struct {
+	bus      *eventbus.Bus
+	userRepo repo.UserRepository
+	validate *validator.Validate
+}
+
+// NewEventHandler 创建实时事件处理器
+func NewEventHandler(bus *eventbus.Bus, userRepo repo.UserRepository) *EventHandler {
+	return &EventHandler{
+		bus:      bus,
+		userRepo: userRepo,
+		validate: validator.New(),
+	}
+}
+
+// Stream 以 Server-Sent Events 推送实时事件
+// @Summary 实时事件流
+// @Description 推送任务进度、定时任务运行、Meilisearch 同步进度、新举报和账号失效事件。
+// @Description 未指定 types 时使用当前管理员的订阅设置；断线重连时通过 Last-Event-ID 补发缓存中的事件。
+// @Description EventSource 无法设置请求头，可通过 access_token 查询参数传递令牌。
+// @Tags Event
+// @Produce text/event-stream
+// @Param types query string false "事件类型过滤，逗号分隔，支持 task.* 通配"
+// @Param last_event_id query int false "最后收到的事件ID，等同 Last-Event-ID 请求头"
+// @Param access_token query string false "JWT 令牌"
+// @Success 200 {string} string "事件流"
+// @Failure 400 {object} Response
+// @Router /events/stream [get]
+func (h *EventHandler) Stream(c *gin.Context) {
+	filter, err := h.streamFilter(c)
+	if err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	lastID, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+	sub, replay := h.bus.Subscribe(filter, eventStreamBuffer, lastID)
+	defer sub.Close()
+
+	username, _ := c.Get("username")
+	utils.Debug("EventStream - 建立连接 - 用户: %v, 过滤: %s, 补发: %d", username, filter, len(replay))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 nginx 缓冲
+	c.Status(http.StatusOK)
+
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	for _, ev := range replay {
+		if err := writeSSEEvent(c.Writer, ev); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Debug("EventStream - 连接关闭 - 用户: %v, 丢弃事件: %d", username, sub.Dropped())
+			return
+		case ev, ok := <-sub.C():
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(c.Writer, ev); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamFilter 优先使用 types 参数，否则读取当前用户的订阅设置
+func (h *EventHandler) streamFilter(c *gin.Context) (eventbus.Filter, error) {
+	raw, specified := c.GetQuery("types")
+	if !specified {
+		if userID, ok := c.Get("user_id"); ok {
+			if user, err := h.userRepo.FindByID(userID.(uint)); err == nil {
+				raw = user.EventSubscriptions
+			}
+		}
+	}
+	filter := eventbus.ParseFilter(raw)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// writeSSEEvent 按 SSE 格式写出事件，data 为完整事件 JSON
+func writeSSEEvent(w gin.ResponseWriter, ev eventbus.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, payload)
+	return err
+}
+
+// GetTypes 获取可订阅的事件类型
+// @Summary 获取事件类型
+// @Tags Event
+// @Produce json
+// @Success 200 {object} Response
+// @Router /events/types [get]
+func (h *EventHandler) GetTypes(c *gin.Context) {
+	SuccessResponse(c, gin.H{
+		"types": eventbus.Types,
+		"stats": h.bus.Stats(),
+	})
+}
+
+// GetSubscriptions 获取当前管理员的事件订阅
+// @Summary 获取事件订阅
+// @Tags Event
+// @Produce json
+// @Success 200 {object} Response{data=dto.EventSubscriptionsResponse}
+// @Router /events/subscriptions [get]
+func (h *EventHandler) GetSubscriptions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		ErrorResponse(c, "未认证", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userRepo.FindByID(userID.(uint))
+	if err != nil {
+		ErrorResponse(c, "用户不存在", http.StatusNotFound)
+		return
+	}
+	SuccessResponse(c, dto.EventSubscriptionsResponse{
+		Types:     append([]string{}, eventbus.ParseFilter(user.EventSubscriptions)...),
+		Available: eventbus.Types,
+	})
+}
+
+// UpdateSubscriptions 更新当前管理员的事件订阅
+// @Summary 更新事件订阅
+// @Description types 为空表示订阅全部事件
+// @Tags Event
+// @Accept json
+// @Produce json
+// @Param request body dto.EventSubscriptionsRequest true "订阅设置"
+// @Success 200 {object} Response{data=dto.EventSubscriptionsResponse}
+// @Failure 400 {object} Response
+// @Router /events/subscriptions [put]
+func (h *EventHandler) UpdateSubscriptions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		ErrorResponse(c, "未认证", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.EventSubscriptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := eventbus.ParseFilter(strings.Join(req.Types, ","))
+	if err := filter.Validate(); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.userRepo.UpdateEventSubscriptions(userID.(uint), filter.String()); err != nil {
+		ErrorResponse(c, "保存订阅失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, dto.EventSubscriptionsResponse{
+		Types:     append([]string{}, filter...),
+		Available: eventbus.Types,
+	})
+}
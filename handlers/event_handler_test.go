@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/gin-gonic/gin"
+)
+
+// TestEventStreamReplayAndFilter 验证 SSE 输出格式、Last-Event-ID 补发与类型过滤
+func TestEventStreamReplayAndFilter(t *testing.T) {
+	bus := eventbus.New(10)
+	bus.Publish(eventbus.TypeTaskProgress, map[string]int{"processed": 1})
+	bus.Publish(eventbus.TypeReportCreated, nil)
+	bus.Publish(eventbus.TypeTaskStatus, map[string]string{"status": "running"})
+
+	h := NewEventHandler(bus, nil)
+	r := gin.New()
+	r.GET("/events/stream", h.Stream)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events/stream?types=task.*", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() []string {
+		var lines []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read stream: %v", err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" {
+				if len(lines) > 0 && !strings.HasPrefix(lines[0], "retry:") {
+					return lines
+				}
+				lines = nil
+				continue
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	// 补发 ID 1 之后的 task.* 事件，report.created 被过滤
+	if got := readEvent(); got[0] != "id: 3" || got[1] != "event: task.status" || !strings.Contains(got[2], `"status":"running"`) {
+		t.Errorf("replayed event = %v", got)
+	}
+
+	bus.Publish(eventbus.TypeAccountFailed, nil)
+	bus.Publish(eventbus.TypeTaskProgress, map[string]int{"processed": 2})
+	if got := readEvent(); got[0] != "id: 5" || got[1] != "event: task.progress" {
+		t.Errorf("live event = %v", got)
+	}
+}
+
+func TestEventStreamRejectsUnknownType(t *testing.T) {
+	h := NewEventHandler(eventbus.New(0), nil)
+	r := gin.New()
+	r.GET("/events/stream", h.Stream)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/stream?types=nope", nil))
+	body, _ := io.ReadAll(w.Body)
+	if w.Code != http.StatusBadRequest || !strings.Contains(string(body), "nope") {
+		t.Errorf("code = %d, body = %s", w.Code, body)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/utils"
 
 	"github.com/gin-gonic/gin"
@@ -257,7 +258,7 @@ func (h *FileHandler) GetFileList(c *gin.Context) {
 	var total int64
 	var err error
 
-	if role == "admin" {
+	if middleware.HasPermission(c, entity.PermissionFileManage) {
 		// 管理员可以查看所有文件
 		files, total, err = h.fileRepo.SearchFiles(req.Search, req.FileType, req.Status, req.UserID, req.Page, req.PageSize)
 	} else {
@@ -282,22 +283,17 @@ func (h *FileHandler) DeleteFiles(c *gin.Context) {
 		return
 	}
 
-	// 获取当前用户ID和角色
+	// 获取当前用户ID
 	userIDInterface, exists := c.Get("user_id")
-	roleInterface, _ := c.Get("role")
 	if !exists {
 		ErrorResponse(c, "用户未登录", http.StatusUnauthorized)
 		return
 	}
 
 	userID := userIDInterface.(uint)
-	role := ""
-	if roleInterface != nil {
-		role = roleInterface.(string)
-	}
 
 	// 检查权限
-	if role != "admin" {
+	if !middleware.HasPermission(c, entity.PermissionFileManage) {
 		// 普通用户只能删除自己的文件
 		for _, id := range req.IDs {
 			file, err := h.fileRepo.FindByID(id)
@@ -350,19 +346,14 @@ func (h *FileHandler) UpdateFile(c *gin.Context) {
 		return
 	}
 
-	// 获取当前用户ID和角色
+	// 获取当前用户ID
 	userIDInterface, exists := c.Get("user_id")
-	roleInterface, _ := c.Get("role")
 	if !exists {
 		ErrorResponse(c, "用户未登录", http.StatusUnauthorized)
 		return
 	}
 
 	userID := userIDInterface.(uint)
-	role := ""
-	if roleInterface != nil {
-		role = roleInterface.(string)
-	}
 
 	// 查找文件
 	file, err := h.fileRepo.FindByID(req.ID)
@@ -372,7 +363,7 @@ func (h *FileHandler) UpdateFile(c *gin.Context) {
 	}
 
 	// 检查权限
-	if role != "admin" && userID != file.UserID {
+	if !middleware.HasPermission(c, entity.PermissionFileManage) && userID != file.UserID {
 		ErrorResponse(c, "没有权限修改此文件", http.StatusForbidden)
 		return
 	}
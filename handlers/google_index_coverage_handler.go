@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// googleIndexReconcileTimeout 后台对账的最长执行时间
+const googleIndexReconcileTimeout = 2 * time.Hour
+
+// GoogleIndexCoverageHandler Google索引覆盖率处理器
+type GoogleIndexCoverageHandler struct {
+	service  *services.GoogleIndexCoverageService
+	validate *validator.Validate
+	running  atomic.Bool // 后台对账是否在执行
+}
+
+// NewGoogleIndexCoverageHandler 创建Google索引覆盖率处理器
+func NewGoogleIndexCoverageHandler(service *services.GoogleIndexCoverageService) *GoogleIndexCoverageHandler {
+	return &GoogleIndexCoverageHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// GetCoverage 获取索引覆盖率
+// @Summary 获取Google索引覆盖率
+// @Tags GoogleIndex
+// @Produce json
+// @Param group_by query string false "分组方式 category/pan/date，为空表示总体"
+// @Param days query int false "只统计最近N天创建的资源，0表示全部" default(0)
+// @Success 200 {object} Response{data=services.GoogleIndexCoverageReport}
+// @Router /google-index/coverage [get]
+func (h *GoogleIndexCoverageHandler) GetCoverage(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "0"))
+	if days < 0 || days > 3650 {
+		ErrorResponse(c, "参数错误: days 须在 0-3650 之间", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Coverage(c.Query("group_by"), days)
+	if err != nil {
+		ErrorResponse(c, "获取覆盖率失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, gin.H{
+		"report":  report,
+		"config":  h.service.Config(),
+		"running": h.running.Load(),
+	})
+}
+
+// ListStatuses 分页查询资源索引状态
+// @Summary 查询资源索引状态
+// @Tags GoogleIndex
+// @Produce json
+// @Param verdict query string false "检查结论 PASS/NEUTRAL/FAIL，uninspected 表示未检查"
+// @Param coverage_state query string false "覆盖状态"
+// @Param keyword query string false "URL关键字"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /google-index/coverage/statuses [get]
+func (h *GoogleIndexCoverageHandler) ListStatuses(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	list, total, err := h.service.List(c.Query("verdict"), c.Query("coverage_state"), strings.TrimSpace(c.Query("keyword")), page, pageSize)
+	if err != nil {
+		ErrorResponse(c, "查询索引状态失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, list, total, page, pageSize)
+}
+
+// Reconcile 立即执行一轮对账
+// @Summary 立即检查资源索引状态
+// @Tags GoogleIndex
+// @Produce json
+// @Success 200 {object} Response
+// @Router /google-index/coverage/reconcile [post]
+func (h *GoogleIndexCoverageHandler) Reconcile(c *gin.Context) {
+	if !h.running.CompareAndSwap(false, true) {
+		ErrorResponse(c, "已有对账任务在执行中", http.StatusConflict)
+		return
+	}
+	go func() {
+		defer h.running.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), googleIndexReconcileTimeout)
+		defer cancel()
+
+		if _, err := h.service.Reconcile(ctx); err != nil {
+			utils.Error("手动执行Google索引覆盖率对账失败: %v", err)
+		}
+	}()
+	SuccessResponse(c, gin.H{"message": "任务已在后台执行"})
+}
+
+// Resubmit 重新提交指定资源的详情页
+// @Summary 重新提交资源详情页到Google
+// @Tags GoogleIndex
+// @Accept json
+// @Produce json
+// @Param request body dto.GoogleIndexResubmitRequest true "资源ID列表"
+// @Success 200 {object} Response
+// @Router /google-index/coverage/resubmit [post]
+func (h *GoogleIndexCoverageHandler) Resubmit(c *gin.Context) {
+	var req dto.GoogleIndexResubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count, err := h.service.Resubmit(c.Request.Context(), req.ResourceIDs)
+	if err != nil {
+		ErrorResponse(c, "重新提交失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, gin.H{"resubmitted": count})
+}
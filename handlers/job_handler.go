@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/leader"
+	"github.com/ctwj/urldb/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// JobHandler 定时任务处理器：查看任务与执行记录，手动触发、暂停恢复与修改执行计划
+type JobHandler struct {
+	jobs     *cronjob.Scheduler
+	repo     repo.ScheduledJobRepository
+	validate *validator.Validate
+}
+
+// NewJobHandler 创建定时任务处理器
+func NewJobHandler(jobs *cronjob.Scheduler, repo repo.ScheduledJobRepository) *JobHandler {
+	return &JobHandler{
+		jobs:     jobs,
+		repo:     repo,
+		validate: validator.New(),
+	}
+}
+
+// ListJobs 获取全部定时任务
+// @Summary 获取定时任务列表
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} Response{data=[]cronjob.JobInfo}
+// @Router /jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	SuccessResponse(c, h.jobs.Jobs())
+}
+
+// ListRuns 分页查询任务的执行记录
+// @Summary 获取定时任务执行记录
+// @Tags Jobs
+// @Produce json
+// @Param name path string true "任务名"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param status query string false "结果 success/failed/skipped"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /jobs/{name}/runs [get]
+func (h *JobHandler) ListRuns(c *gin.Context) {
+	var req dto.ScheduledJobRunListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, total, err := h.repo.ListRuns(c.Param("name"), req.Status, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取执行记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	runs := make([]cronjob.Run, len(records))
+	for i, record := range records {
+		runs[i] = scheduler.RunFromEntity(record)
+	}
+	PageResponse(c, runs, total, req.Page, req.PageSize)
+}
+
+// TriggerJob 立即执行一次任务，暂停或未启用的任务同样执行。
+// 多副本部署时只在持有任务锁的节点执行，锁在其他节点时返回 409
+// @Summary 手动触发定时任务
+// @Tags Jobs
+// @Produce json
+// @Param name path string true "任务名"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /jobs/{name}/trigger [post]
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.jobs.Trigger(c.Request.Context(), name); err != nil {
+		h.jobError(c, "触发任务失败", err)
+		return
+	}
+	middleware.SetAuditAction(c, "jobs.trigger", "scheduled_job", name)
+	SuccessResponse(c, gin.H{"message": "任务已开始执行"})
+}
+
+// PauseJob 暂停任务的定时执行，所有节点共享
+// @Summary 暂停定时任务
+// @Tags Jobs
+// @Produce json
+// @Param name path string true "任务名"
+// @Success 200 {object} Response{data=cronjob.JobInfo}
+// @Failure 404 {object} Response
+// @Router /jobs/{name}/pause [post]
+func (h *JobHandler) PauseJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.jobs.Pause(c.Request.Context(), name); err != nil {
+		h.jobError(c, "暂停任务失败", err)
+		return
+	}
+	middleware.SetAuditAction(c, "jobs.pause", "scheduled_job", name)
+	h.respondJob(c, name)
+}
+
+// ResumeJob 恢复任务的定时执行
+// @Summary 恢复定时任务
+// @Tags Jobs
+// @Produce json
+// @Param name path string true "任务名"
+// @Success 200 {object} Response{data=cronjob.JobInfo}
+// @Failure 404 {object} Response
+// @Router /jobs/{name}/resume [post]
+func (h *JobHandler) ResumeJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.jobs.Resume(c.Request.Context(), name); err != nil {
+		h.jobError(c, "恢复任务失败", err)
+		return
+	}
+	middleware.SetAuditAction(c, "jobs.resume", "scheduled_job", name)
+	h.respondJob(c, name)
+}
+
+// UpdateSchedule 修改任务的 cron 表达式，为空时恢复默认
+// @Summary 修改定时任务执行计划
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param name path string true "任务名"
+// @Param body body dto.ScheduledJobScheduleRequest true "cron 表达式"
+// @Success 200 {object} Response{data=cronjob.JobInfo}
+// @Failure 400 {object} Response
+// @Failure 404 {object} Response
+// @Router /jobs/{name}/schedule [put]
+func (h *JobHandler) UpdateSchedule(c *gin.Context) {
+	var req dto.ScheduledJobScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Spec != "" {
+		if err := cronjob.Validate(req.Spec); err != nil {
+			ErrorResponse(c, "cron 表达式无效: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	name := c.Param("name")
+	before, ok := h.jobs.Job(name)
+	if !ok {
+		ErrorResponse(c, "任务不存在", http.StatusNotFound)
+		return
+	}
+	if err := h.jobs.SetSpec(c.Request.Context(), name, req.Spec); err != nil {
+		h.jobError(c, "修改执行计划失败", err)
+		return
+	}
+	after, _ := h.jobs.Job(name)
+
+	middleware.SetAuditAction(c, "jobs.schedule", "scheduled_job", name)
+	middleware.SetAuditChange(c, gin.H{"spec": before.Spec}, gin.H{"spec": after.Spec})
+	SuccessResponse(c, after)
+}
+
+func (h *JobHandler) respondJob(c *gin.Context, name string) {
+	info, _ := h.jobs.Job(name)
+	SuccessResponse(c, info)
+}
+
+func (h *JobHandler) jobError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, cronjob.ErrNotFound):
+		ErrorResponse(c, "任务不存在", http.StatusNotFound)
+	case errors.Is(err, cronjob.ErrRunning):
+		ErrorResponse(c, "任务正在执行中", http.StatusConflict)
+	case errors.Is(err, leader.ErrHeldElsewhere):
+		ErrorResponse(c, "任务由其他节点负责执行，请稍后重试: "+err.Error(), http.StatusConflict)
+	default:
+		ErrorResponse(c, message+": "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// metadataBatchTimeout 后台批量补全/刷新的最长执行时间
+const metadataBatchTimeout = 30 * time.Minute
+
+// MetadataHandler 影视元数据管理处理器
+type MetadataHandler struct {
+	service  *services.MetadataService
+	validate *validator.Validate
+	running  atomic.Bool // 后台批量任务是否在执行
+}
+
+// NewMetadataHandler 创建影视元数据管理处理器
+func NewMetadataHandler(service *services.MetadataService) *MetadataHandler {
+	return &MetadataHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// GetConfig 获取影视元数据配置
+// @Summary 获取影视元数据配置
+// @Tags Metadata
+// @Produce json
+// @Success 200 {object} Response{data=services.MetadataConfig}
+// @Router /metadata/config [get]
+func (h *MetadataHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, h.service.Config())
+}
+
+// UpdateConfig 更新影视元数据配置
+// @Summary 更新影视元数据配置
+// @Tags Metadata
+// @Accept json
+// @Produce json
+// @Param request body dto.MetadataConfigRequest true "配置"
+// @Success 200 {object} Response{data=services.MetadataConfig}
+// @Router /metadata/config [put]
+func (h *MetadataHandler) UpdateConfig(c *gin.Context) {
+	var req dto.MetadataConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := h.service.Config()
+	cfg.Enabled = req.Enabled
+	cfg.Provider = req.Provider
+	if req.TMDBApiKey != nil {
+		cfg.TMDBApiKey = *req.TMDBApiKey
+	}
+	if req.RefreshDays > 0 {
+		cfg.RefreshDays = req.RefreshDays
+	}
+	if req.MinScore > 0 {
+		cfg.MinScore = req.MinScore
+	}
+	if req.BatchSize > 0 {
+		cfg.BatchSize = req.BatchSize
+	}
+	if err := h.service.SaveConfig(cfg); err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, h.service.Config())
+}
+
+// GetStats 获取元数据匹配统计
+// @Summary 获取元数据匹配统计
+// @Tags Metadata
+// @Produce json
+// @Success 200 {object} Response
+// @Router /metadata/stats [get]
+func (h *MetadataHandler) GetStats(c *gin.Context) {
+	counts, err := h.service.Stats()
+	if err != nil {
+		ErrorResponse(c, "获取统计失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"counts": counts, "batch_running": h.running.Load()})
+}
+
+// Search 按标题搜索候选条目
+// @Summary 搜索影视条目
+// @Tags Metadata
+// @Produce json
+// @Param title query string true "资源标题"
+// @Success 200 {object} Response
+// @Router /metadata/search [get]
+func (h *MetadataHandler) Search(c *gin.Context) {
+	title := strings.TrimSpace(c.Query("title"))
+	if title == "" {
+		ErrorResponse(c, "标题不能为空", http.StatusBadRequest)
+		return
+	}
+	query, matches, err := h.service.Search(c.Request.Context(), title)
+	if err != nil {
+		ErrorResponse(c, "搜索失败: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	SuccessResponse(c, gin.H{"query": query, "candidates": matches})
+}
+
+// GetResourceMetadata 获取资源的元数据
+// @Summary 获取资源的影视元数据
+// @Tags Metadata
+// @Produce json
+// @Param id path int true "资源ID"
+// @Success 200 {object} Response{data=entity.ResourceMetadata}
+// @Router /resources/{id}/metadata [get]
+func (h *MetadataHandler) GetResourceMetadata(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	m, err := repoManager.ResourceMetadataRepository.FindByResourceID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "该资源尚未匹配元数据", http.StatusNotFound)
+			return
+		}
+		ErrorResponse(c, "获取元数据失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, m)
+}
+
+// RefreshResourceMetadata 立即补全或刷新资源的元数据
+// @Summary 刷新资源的影视元数据
+// @Tags Metadata
+// @Produce json
+// @Param id path int true "资源ID"
+// @Param force query bool false "按标题重新匹配（手动绑定的除外）"
+// @Success 200 {object} Response{data=entity.ResourceMetadata}
+// @Router /resources/{id}/metadata/refresh [post]
+func (h *MetadataHandler) RefreshResourceMetadata(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	resource, err := repoManager.ResourceRepository.FindByID(id)
+	if err != nil {
+		ErrorResponse(c, "资源不存在", http.StatusNotFound)
+		return
+	}
+
+	m, err := h.service.Enrich(c.Request.Context(), resource, c.Query("force") == "true")
+	if err != nil && m == nil {
+		ErrorResponse(c, "刷新失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		ErrorResponse(c, "请求数据源失败: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	SuccessResponse(c, m)
+}
+
+// BindResourceMetadata 手动绑定资源到指定条目
+// @Summary 手动绑定影视条目
+// @Tags Metadata
+// @Accept json
+// @Produce json
+// @Param id path int true "资源ID"
+// @Param request body dto.MetadataBindRequest true "条目"
+// @Success 200 {object} Response{data=entity.ResourceMetadata}
+// @Router /resources/{id}/metadata [put]
+func (h *MetadataHandler) BindResourceMetadata(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	var req dto.MetadataBindRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m, err := h.service.Bind(c.Request.Context(), id, req.Provider, req.MediaType, req.SubjectID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "资源不存在", http.StatusNotFound)
+			return
+		}
+		ErrorResponse(c, "绑定失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, m)
+}
+
+// UnbindResourceMetadata 解除资源与条目的关联
+// @Summary 解除影视条目绑定
+// @Tags Metadata
+// @Produce json
+// @Param id path int true "资源ID"
+// @Success 200 {object} Response
+// @Router /resources/{id}/metadata [delete]
+func (h *MetadataHandler) UnbindResourceMetadata(c *gin.Context) {
+	id, ok := parseResourceID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Unbind(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "该资源尚未匹配元数据", http.StatusNotFound)
+			return
+		}
+		ErrorResponse(c, "解除绑定失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "已解除绑定"})
+}
+
+// RunBatch 在后台补全未匹配资源并刷新到期的元数据
+// @Summary 立即执行元数据补全与刷新
+// @Tags Metadata
+// @Produce json
+// @Param limit query int false "每类最多处理数量" default(50)
+// @Success 200 {object} Response
+// @Router /metadata/run [post]
+func (h *MetadataHandler) RunBatch(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+	if !h.running.CompareAndSwap(false, true) {
+		ErrorResponse(c, "已有元数据任务在执行中", http.StatusConflict)
+		return
+	}
+
+	go func() {
+		defer h.running.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), metadataBatchTimeout)
+		defer cancel()
+
+		if result, err := h.service.RefreshDue(ctx, limit); err != nil {
+			utils.Error("手动刷新影视元数据失败: %v", err)
+		} else {
+			utils.Info("手动刷新影视元数据完成: %+v", *result)
+		}
+		if result, err := h.service.EnrichMissing(ctx, limit); err != nil {
+			utils.Error("手动补全影视元数据失败: %v", err)
+		} else {
+			utils.Info("手动补全影视元数据完成: %+v", *result)
+		}
+	}()
+	SuccessResponse(c, gin.H{"message": "任务已在后台执行"})
+}
+
+func parseResourceID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// applyMetadataFilters 解析按影视元数据筛选的查询参数，返回是否使用了此类筛选
+func applyMetadataFilters(c *gin.Context, params map[string]interface{}) bool {
+	applied := false
+	if year, err := strconv.Atoi(c.Query("year")); err == nil && year > 0 {
+		params["year"] = year
+		applied = true
+	}
+	for _, key := range []string{"region", "genre", "media_type"} {
+		if v := strings.TrimSpace(c.Query(key)); v != "" {
+			params[key] = v
+			applied = true
+		}
+	}
+	if rating, err := strconv.ParseFloat(c.Query("min_rating"), 64); err == nil && rating > 0 {
+		params["min_rating"] = rating
+		applied = true
+	}
+	return applied
+}
+
+// metadataBriefs 批量获取资源的元数据摘要，用于列表响应
+func metadataBriefs(resourceIDs []uint) map[uint]gin.H {
+	briefs := make(map[uint]gin.H)
+	if metadataService == nil || len(resourceIDs) == 0 {
+		return briefs
+	}
+	for id, m := range metadataService.ForResources(resourceIDs) {
+		briefs[id] = metadataBrief(m)
+	}
+	return briefs
+}
+
+// metadataBrief 列表中展示的元数据字段
+func metadataBrief(m *entity.ResourceMetadata) gin.H {
+	return gin.H{
+		"provider":    m.Provider,
+		"subject_id":  m.SubjectID,
+		"media_type":  m.MediaType,
+		"title":       m.Title,
+		"year":        m.Year,
+		"regions":     m.Regions,
+		"genres":      m.Genres,
+		"rating":      m.Rating,
+		"poster_url":  m.PosterURL,
+		"episodes":    m.Episodes,
+		"subject_url": m.SubjectURL,
+		"summary":     services.MetadataSummary(m),
+	}
+}
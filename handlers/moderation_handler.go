@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/converter"
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ModerationHandler 内容处置记录处理器
+type ModerationHandler struct {
+	service  *services.ModerationService
+	validate *validator.Validate
+}
+
+// NewModerationHandler 创建内容处置记录处理器
+func NewModerationHandler(service *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// moderationDecision 由举报/申述更新请求构造处理决定
+func moderationDecision(status, note string, fields dto.ModerationFields) services.ModerationDecision {
+	return services.ModerationDecision{
+		Status:      status,
+		Note:        note,
+		Action:      fields.Action,
+		DeleteFiles: fields.DeleteFiles,
+		BlockTitle:  fields.BlockTitle,
+	}
+}
+
+// moderationErrorStatus 处置错误对应的 HTTP 状态码
+func moderationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrModerationAlreadyApplied), errors.Is(err, services.ErrModerationAlreadyReverted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListActions 分页查询处置记录
+// @Summary 查询内容处置记录
+// @Tags Moderation
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param source_type query string false "来源类型 report/copyright_claim"
+// @Param source_id query int false "举报或申述ID"
+// @Param resource_key query string false "资源Key"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /moderation/actions [get]
+func (h *ModerationHandler) ListActions(c *gin.Context) {
+	var req dto.ModerationActionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	actions, total, err := h.service.ListActions(req.SourceType, req.SourceID, req.ResourceKey, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取处置记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, converter.ModerationActionsToResponse(actions), total, req.Page, req.PageSize)
+}
+
+// RevertAction 撤销处置，恢复资源并解除入库限制，已删除的转存文件无法恢复
+// @Summary 撤销内容处置
+// @Tags Moderation
+// @Produce json
+// @Param id path int true "处置记录ID"
+// @Success 200 {object} Response{data=dto.ModerationActionResponse}
+// @Failure 404 {object} Response
+// @Failure 409 {object} Response
+// @Router /moderation/actions/{id}/revert [post]
+func (h *ModerationHandler) RevertAction(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+
+	action, err := h.service.Revert(uint(id), c.GetUint("user_id"))
+	if err != nil {
+		ErrorResponse(c, "撤销处置失败: "+err.Error(), moderationErrorStatus(err))
+		return
+	}
+	middleware.SetAuditAction(c, "moderation.revert", action.SourceType, strconv.FormatUint(uint64(action.SourceID), 10))
+	middleware.SetAuditDetail(c, "撤销处置 #"+strconv.FormatUint(uint64(action.ID), 10)+"，资源: "+action.ResourceIDs)
+	SuccessResponse(c, converter.ModerationActionToResponse(action))
+}
+
+// GetConfig 获取内容处置配置
+// @Summary 获取内容处置配置
+// @Tags Moderation
+// @Produce json
+// @Success 200 {object} Response{data=dto.ModerationConfigResponse}
+// @Router /moderation/config [get]
+func (h *ModerationHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, dto.ModerationConfigResponse{EscalationThreshold: h.service.EscalationThreshold()})
+}
+
+// UpdateConfig 更新举报自动升级阈值
+// @Summary 更新内容处置配置
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body dto.ModerationConfigRequest true "配置"
+// @Success 200 {object} Response{data=dto.ModerationConfigResponse}
+// @Failure 400 {object} Response
+// @Router /moderation/config [put]
+func (h *ModerationHandler) UpdateConfig(c *gin.Context) {
+	var req dto.ModerationConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	before := dto.ModerationConfigResponse{EscalationThreshold: h.service.EscalationThreshold()}
+	if err := h.service.SaveEscalationThreshold(req.EscalationThreshold); err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	after := dto.ModerationConfigResponse{EscalationThreshold: h.service.EscalationThreshold()}
+	middleware.SetAuditChange(c, before, after)
+	SuccessResponse(c, after)
+}
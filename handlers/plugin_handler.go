@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/plugin-system/manager/plugin"
 	"github.com/gin-gonic/gin"
 )
@@ -521,6 +522,10 @@ func (h *PluginHandler) UpdatePluginConfig(c *gin.Context) {
 
 	// 更新数据库中的配置（使用新的命名系统）
 	configPluginName := h.getPluginConfigName(pluginName)
+	var oldConfig map[string]interface{}
+	if existing, err := h.repoManager.PluginConfigRepository.GetConfig(configPluginName); err == nil {
+		json.Unmarshal([]byte(existing.ConfigJSON), &oldConfig)
+	}
 	err := h.repoManager.PluginConfigRepository.SetConfig(configPluginName, request.Config)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -530,6 +535,8 @@ func (h *PluginHandler) UpdatePluginConfig(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditChange(c, oldConfig, request.Config)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Plugin config updated successfully",
@@ -712,6 +719,8 @@ func (h *PluginHandler) InstallPlugin(c *gin.Context) {
 			return
 		}
 
+		middleware.SetAuditDetail(c, "上传安装: "+filename)
+
 		// 使用插件管理器安装插件
 		if err := h.pluginManager.InstallPlugin(tempPath); err != nil {
 			// 清理临时文件
@@ -752,6 +761,8 @@ func (h *PluginHandler) InstallPlugin(c *gin.Context) {
 		return
 	}
 
+	middleware.SetAuditDetail(c, "安装来源: "+jsonRequest.Source)
+
 	// 使用插件管理器安装插件
 	if err := h.pluginManager.InstallPlugin(jsonRequest.Source); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -770,6 +781,7 @@ func (h *PluginHandler) InstallPlugin(c *gin.Context) {
 // UninstallPlugin 卸载插件
 func (h *PluginHandler) UninstallPlugin(c *gin.Context) {
 	pluginName := c.Param("name")
+	middleware.SetAuditAction(c, "plugins.uninstall", "", "")
 
 	if pluginName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
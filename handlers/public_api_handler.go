@@ -8,6 +8,7 @@ import (
 
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/services"
 
 	"github.com/ctwj/urldb/utils"
 	"github.com/gin-gonic/gin"
@@ -94,7 +95,7 @@ func (h *PublicAPIHandler) logAPIAccess(c *gin.Context, startTime time.Time, pro
 
 	// 记录API访问日志 - 使用简单日志记录
 	h.recordAPIAccessToDB(ip, userAgent, endpoint, method, requestParams,
-		c.Writer.Status(), responseData, processCount, errorMessage, processingTime)
+		c.Writer.Status(), responseData, processCount, errorMessage, processingTime, c.GetUint("api_key_id"))
 }
 
 // AddBatchResources godoc
@@ -159,7 +160,9 @@ func (h *PublicAPIHandler) AddBatchResources(c *gin.Context) {
 		existResourceUrls[r.URL] = struct{}{}
 	}
 
+	blocklist := services.GetDefaultBlocklistService()
 	var createdResources []uint
+	blockedCount := 0
 	for _, resourceReq := range req.Resources {
 		// 生成 key（每组同一个 key）
 		key, err := repoManager.ReadyResourceRepository.GenerateUniqueKey()
@@ -178,6 +181,14 @@ func (h *PublicAPIHandler) AddBatchResources(c *gin.Context) {
 			if _, ok := existResourceUrls[url]; ok {
 				continue
 			}
+			// 命中黑名单拒绝规则的不再接收，隐藏与打码规则在入库时处理
+			if blocklist != nil {
+				if reason := blocklist.CheckReject(services.BlocklistTarget{URL: url, Title: resourceReq.Title}); reason != "" {
+					utils.Info("PublicAPI.AddBatchResources - 拒绝入库 - URL: %s, 原因: %s", url, reason)
+					blockedCount++
+					continue
+				}
+			}
 			readyResource := entity.ReadyResource{
 				Title:       &resourceReq.Title,
 				Description: resourceReq.Description,
@@ -185,7 +196,7 @@ func (h *PublicAPIHandler) AddBatchResources(c *gin.Context) {
 				Category:    resourceReq.Category,
 				Tags:        resourceReq.Tags,
 				Img:         resourceReq.Img,
-				Source:      "api",
+				Source:      entity.SourceAPI,
 				Extra:       resourceReq.Extra,
 				Key:         key,
 			}
@@ -199,6 +210,7 @@ func (h *PublicAPIHandler) AddBatchResources(c *gin.Context) {
 	responseData := gin.H{
 		"created_count": len(createdResources),
 		"created_ids":   createdResources,
+		"blocked_count": blockedCount,
 	}
 	h.logAPIAccess(c, startTime, len(createdResources), responseData, "")
 	SuccessResponse(c, responseData)
@@ -214,6 +226,11 @@ func (h *PublicAPIHandler) AddBatchResources(c *gin.Context) {
 // @Param keyword query string false "搜索关键词"
 // @Param tag query string false "标签过滤"
 // @Param category query string false "分类过滤"
+// @Param year query int false "上映年份"
+// @Param region query string false "制片地区"
+// @Param genre query string false "影视类型"
+// @Param media_type query string false "movie 或 tv"
+// @Param min_rating query number false "最低评分"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20) maximum(100)
 // @Success 200 {object} map[string]interface{} "搜索成功，如果存在违禁词过滤会返回forbidden_words_filtered字段"
@@ -249,8 +266,12 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 	var resources []entity.Resource
 	var total int64
 
+	// 影视元数据筛选只能走数据库搜索
+	metadataFilters := map[string]interface{}{}
+	hasMetadataFilters := applyMetadataFilters(c, metadataFilters)
+
 	// 如果启用了Meilisearch，优先使用Meilisearch搜索
-	if meilisearchManager != nil && meilisearchManager.IsEnabled() {
+	if !hasMetadataFilters && meilisearchManager != nil && meilisearchManager.IsEnabled() {
 		// 构建过滤器
 		filters := make(map[string]interface{})
 		if category != "" {
@@ -289,6 +310,7 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 						Key:         doc.Key,
 						PanID:       doc.PanID,
 						Cover:       doc.Cover,
+						Author:      doc.Author,
 						CreatedAt:   doc.CreatedAt,
 						UpdatedAt:   doc.UpdatedAt,
 					}
@@ -302,7 +324,7 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 	}
 
 	// 如果Meilisearch未启用或搜索失败，使用数据库搜索
-	if meilisearchManager == nil || !meilisearchManager.IsEnabled() || err != nil {
+	if hasMetadataFilters || meilisearchManager == nil || !meilisearchManager.IsEnabled() || err != nil {
 		// 构建搜索条件
 		params := map[string]interface{}{
 			"page":      page,
@@ -326,6 +348,9 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 				params["pan_id"] = uint(id)
 			}
 		}
+		for k, v := range metadataFilters {
+			params[k] = v
+		}
 
 		// 执行数据库搜索
 		resources, total, err = repoManager.ResourceRepository.SearchWithFilters(params)
@@ -336,6 +361,11 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 		}
 	}
 
+	// 排除命中黑名单拒绝或隐藏规则的资源
+	if blocklist := services.GetDefaultBlocklistService(); blocklist != nil {
+		resources = blocklist.FilterResources(resources)
+	}
+
 	// 获取违禁词配置（只获取一次）
 	cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
 		return repoManager.SystemConfigRepository.GetConfigValue(entity.ConfigKeyForbiddenWords)
@@ -345,6 +375,12 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 		cleanWords = []string{} // 如果获取失败，使用空列表
 	}
 
+	resourceIDs := make([]uint, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	briefs := metadataBriefs(resourceIDs)
+
 	// 转换为响应格式并添加违禁词标记
 	var resourceResponses []gin.H
 	for i, processedResource := range resources {
@@ -365,6 +401,9 @@ func (h *PublicAPIHandler) SearchResources(c *gin.Context) {
 		// 添加违禁词标记
 		resourceResponse["has_forbidden_words"] = forbiddenInfo.HasForbiddenWords
 		resourceResponse["forbidden_words"] = forbiddenInfo.ForbiddenWords
+		if brief, ok := briefs[processedResource.ID]; ok {
+			resourceResponse["metadata"] = brief
+		}
 		resourceResponses = append(resourceResponses, resourceResponse)
 	}
 
@@ -454,7 +493,7 @@ func (h *PublicAPIHandler) GetHotDramas(c *gin.Context) {
 // recordAPIAccessToDB 记录API访问日志到数据库
 func (h *PublicAPIHandler) recordAPIAccessToDB(ip, userAgent, endpoint, method string,
 	requestParams interface{}, responseStatus int, responseData interface{},
-	processCount int, errorMessage string, processingTime int64) {
+	processCount int, errorMessage string, processingTime int64, apiKeyID uint) {
 
 	// 判断是否为关键端点，需要强制记录日志
 	isKeyEndpoint := strings.Contains(endpoint, "/api/public/resources/batch-add") ||
@@ -493,6 +532,7 @@ func (h *PublicAPIHandler) recordAPIAccessToDB(ip, userAgent, endpoint, method s
 		ProcessCount:   processCount,
 		ErrorMessage:   errorMessage,
 		ProcessingTime: processingTime,
+		APIKeyID:       apiKeyID,
 	}
 
 	// 异步保存到数据库（避免影响API性能）
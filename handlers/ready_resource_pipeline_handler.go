@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ReadyResourcePipelineHandler 待处理资源流水线管理处理器
+type ReadyResourcePipelineHandler struct {
+	service  *services.ReadyResourcePipelineService
+	logRepo  repo.ReadyResourceStageLogRepository
+	validate *validator.Validate
+}
+
+// NewReadyResourcePipelineHandler 创建待处理资源流水线管理处理器
+func NewReadyResourcePipelineHandler(service *services.ReadyResourcePipelineService, logRepo repo.ReadyResourceStageLogRepository) *ReadyResourcePipelineHandler {
+	return &ReadyResourcePipelineHandler{
+		service:  service,
+		logRepo:  logRepo,
+		validate: validator.New(),
+	}
+}
+
+// ListPipelines 获取可用阶段、内置默认流水线及已保存的流水线配置
+// @Summary 获取待处理资源流水线配置
+// @Tags ReadyResourcePipeline
+// @Produce json
+// @Success 200 {object} Response
+// @Router /ready-resource-pipelines [get]
+func (h *ReadyResourcePipelineHandler) ListPipelines(c *gin.Context) {
+	pipelines, err := h.service.List()
+	if err != nil {
+		ErrorResponse(c, "获取流水线配置失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{
+		"stages":    services.PipelineStageInfos(),
+		"defaults":  services.DefaultPipelineStageConfigs(),
+		"pipelines": pipelines,
+	})
+}
+
+// GetPipeline 获取数据来源当前生效的阶段配置
+// @Summary 获取数据来源生效的流水线
+// @Tags ReadyResourcePipeline
+// @Produce json
+// @Param source path string true "数据来源，default 为默认流水线"
+// @Success 200 {object} Response
+// @Router /ready-resource-pipelines/{source} [get]
+func (h *ReadyResourcePipelineHandler) GetPipeline(c *gin.Context) {
+	source := strings.TrimSpace(c.Param("source"))
+	SuccessResponse(c, gin.H{
+		"source": source,
+		"stages": h.service.Resolve(source),
+	})
+}
+
+// SavePipeline 保存数据来源的流水线配置
+// @Summary 保存待处理资源流水线
+// @Tags ReadyResourcePipeline
+// @Accept json
+// @Produce json
+// @Param source path string true "数据来源，default 为默认流水线"
+// @Param request body dto.ReadyResourcePipelineRequest true "流水线配置"
+// @Success 200 {object} Response{data=entity.ReadyResourcePipeline}
+// @Router /ready-resource-pipelines/{source} [put]
+func (h *ReadyResourcePipelineHandler) SavePipeline(c *gin.Context) {
+	source := strings.TrimSpace(c.Param("source"))
+	if source == "" || len(source) > 100 {
+		ErrorResponse(c, "无效的数据来源", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ReadyResourcePipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var stages []services.PipelineStageConfig
+	if err := json.Unmarshal(req.Stages, &stages); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pipeline, err := h.service.Save(source, stages, req.Remark)
+	if err != nil {
+		ErrorResponse(c, "保存流水线失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, pipeline)
+}
+
+// DeletePipeline 删除数据来源的流水线配置，恢复使用默认流水线
+// @Summary 删除待处理资源流水线
+// @Tags ReadyResourcePipeline
+// @Param source path string true "数据来源"
+// @Success 200 {object} Response
+// @Router /ready-resource-pipelines/{source} [delete]
+func (h *ReadyResourcePipelineHandler) DeletePipeline(c *gin.Context) {
+	if err := h.service.Delete(strings.TrimSpace(c.Param("source"))); err != nil {
+		ErrorResponse(c, "删除流水线失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "删除成功"})
+}
+
+// ListStageLogs 分页查询阶段执行记录
+// @Summary 查询待处理资源阶段记录
+// @Tags ReadyResourcePipeline
+// @Produce json
+// @Param source query string false "数据来源"
+// @Param stage query string false "阶段名称"
+// @Param status query string false "执行状态"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /ready-resources/stage-logs [get]
+func (h *ReadyResourcePipelineHandler) ListStageLogs(c *gin.Context) {
+	var req dto.ReadyResourceStageLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logs, total, err := h.logRepo.FindWithFilters(map[string]interface{}{
+		"source":    req.Source,
+		"stage":     req.Stage,
+		"status":    req.Status,
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	})
+	if err != nil {
+		ErrorResponse(c, "获取阶段记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, logs, total, req.Page, req.PageSize)
+}
+
+// GetReadyResourceStageLogs 获取单个待处理资源的阶段记录
+// @Summary 获取待处理资源的阶段记录
+// @Tags ReadyResourcePipeline
+// @Produce json
+// @Param id path int true "待处理资源ID"
+// @Success 200 {object} Response
+// @Router /ready-resources/{id}/stage-logs [get]
+func (h *ReadyResourcePipelineHandler) GetReadyResourceStageLogs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+
+	logs, err := h.logRepo.FindByReadyResourceID(uint(id))
+	if err != nil {
+		ErrorResponse(c, "获取阶段记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ListResponse(c, logs, int64(len(logs)))
+}
+
+// GetStageStats 统计最近一段时间各阶段的执行次数与耗时
+// @Summary 待处理资源阶段统计
+// @Tags ReadyResourcePipeline
+// @Produce json
+// @Param hours query int false "统计最近多少小时" default(24)
+// @Success 200 {object} Response
+// @Router /ready-resources/stage-stats [get]
+func (h *ReadyResourcePipelineHandler) GetStageStats(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours < 1 || hours > 24*30 {
+		hours = 24
+	}
+
+	stats, err := h.logRepo.GetStageStats(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		ErrorResponse(c, "获取阶段统计失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ListResponse(c, stats, int64(len(stats)))
+}
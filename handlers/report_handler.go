@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"github.com/ctwj/urldb/db/converter"
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -67,6 +71,15 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		ErrorResponse(c, "创建举报失败: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	eventbus.Publish(eventbus.TypeReportCreated, map[string]interface{}{
+		"report_id":    report.ID,
+		"resource_key": report.ResourceKey,
+		"reason":       report.Reason,
+	})
+	// 同一资源举报过多时自动升级
+	if svc := services.GetDefaultModerationService(); svc != nil {
+		svc.HandleReportCreated(report)
+	}
 
 	// 返回响应
 	response := converter.ReportToResponse(report)
@@ -212,7 +225,7 @@ func (h *ReportHandler) UpdateReport(c *gin.Context) {
 	}
 
 	// 获取当前举报
-	_, err = h.reportRepo.GetByID(uint(id))
+	report, err := h.reportRepo.GetByID(uint(id))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ErrorResponse(c, "举报不存在", http.StatusNotFound)
@@ -228,19 +241,38 @@ func (h *ReportHandler) UpdateReport(c *gin.Context) {
 		processedBy = currentUser
 	}
 
-	if err := h.reportRepo.UpdateStatus(uint(id), req.Status, &processedBy, req.Note); err != nil {
-		ErrorResponse(c, "更新举报状态失败: "+err.Error(), http.StatusInternalServerError)
-		return
+	// 通过处置服务处理：通过并指定处置方式时下架同组资源，改为驳回时撤销已有处置
+	var action *entity.ModerationAction
+	var updatedReport *entity.Report
+	if svc := services.GetDefaultModerationService(); svc != nil {
+		updatedReport, action, err = svc.ModerateReport(uint(id), moderationDecision(req.Status, req.Note, req.ModerationFields), processedBy)
+		if err != nil {
+			ErrorResponse(c, "处理举报失败: "+err.Error(), moderationErrorStatus(err))
+			return
+		}
+	} else {
+		if err := h.reportRepo.UpdateStatus(uint(id), req.Status, &processedBy, req.Note); err != nil {
+			ErrorResponse(c, "更新举报状态失败: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// 获取更新后的举报信息
+		updatedReport, err = h.reportRepo.GetByID(uint(id))
+		if err != nil {
+			ErrorResponse(c, "获取更新后举报信息失败: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
-	// 获取更新后的举报信息
-	updatedReport, err := h.reportRepo.GetByID(uint(id))
-	if err != nil {
-		ErrorResponse(c, "获取更新后举报信息失败: "+err.Error(), http.StatusInternalServerError)
-		return
+	middleware.SetAuditAction(c, "reports."+req.Status, "", "")
+	middleware.SetAuditChange(c, report, updatedReport)
+	if action != nil {
+		middleware.SetAuditDetail(c, fmt.Sprintf("处置 #%d: %s 资源 %s", action.ID, action.Action, action.ResourceIDs))
 	}
 
-	SuccessResponse(c, converter.ReportToResponse(updatedReport))
+	response := converter.ReportToResponse(updatedReport)
+	response.ModerationAction = converter.ModerationActionToResponse(action)
+	SuccessResponse(c, response)
 }
 
 // DeleteReport 删除举报
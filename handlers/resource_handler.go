@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,6 +11,8 @@ import (
 	"github.com/ctwj/urldb/db/converter"
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/pkg/cache"
 	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
 	"github.com/ctwj/urldb/services"
 	"github.com/ctwj/urldb/utils"
@@ -72,6 +75,15 @@ func GetResources(c *gin.Context) {
 		}
 	}
 
+	// 影视元数据筛选（年份/地区/类型/评分），Meilisearch 索引中没有这些字段
+	hasMetadataFilters := applyMetadataFilters(c, params)
+
+	// 前台只查看有效资源，此时排除命中黑名单的资源；管理后台不过滤
+	var blocklist *services.BlocklistService
+	if c.Query("is_valid") == "true" {
+		blocklist = services.GetDefaultBlocklistService()
+	}
+
 	// 获取违禁词配置（只获取一次）
 	cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
 		return repoManager.SystemConfigRepository.GetConfigValue(entity.ConfigKeyForbiddenWords)
@@ -85,7 +97,7 @@ func GetResources(c *gin.Context) {
 	var total int64
 
 	// 如果有搜索关键词且启用了Meilisearch，优先使用Meilisearch搜索
-	if search := c.Query("search"); search != "" && meilisearchManager != nil && meilisearchManager.IsEnabled() {
+	if search := c.Query("search"); search != "" && !hasMetadataFilters && meilisearchManager != nil && meilisearchManager.IsEnabled() {
 		// 构建Meilisearch过滤器
 		filters := make(map[string]interface{})
 		if panID := c.Query("pan_id"); panID != "" {
@@ -109,6 +121,9 @@ func GetResources(c *gin.Context) {
 		if service != nil {
 			docs, docTotal, err := service.Search(search, filters, page, pageSize)
 			if err == nil {
+				if blocklist != nil {
+					docs = blocklist.FilterDocuments(docs)
+				}
 
 				// 将Meilisearch文档转换为ResourceResponse（包含高亮信息）并处理违禁词
 				var resourceResponses []dto.ResourceResponse
@@ -155,6 +170,9 @@ func GetResources(c *gin.Context) {
 		ErrorResponse(c, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if blocklist != nil {
+		resources = blocklist.FilterResources(resources)
+	}
 
 	// 处理违禁词替换和标记
 	var processedResources []entity.Resource
@@ -170,6 +188,12 @@ func GetResources(c *gin.Context) {
 		processedResources = resources
 	}
 
+	resourceIDs := make([]uint, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	briefs := metadataBriefs(resourceIDs)
+
 	// 转换为响应格式并添加违禁词标记
 	var resourceResponses []gin.H
 	for i, processedResource := range processedResources {
@@ -207,6 +231,9 @@ func GetResources(c *gin.Context) {
 		}
 		resourceResponse["tags"] = tagResponses
 		resourceResponse["cover"] = originalResource.Cover
+		if brief, ok := briefs[processedResource.ID]; ok {
+			resourceResponse["metadata"] = brief
+		}
 
 		resourceResponses = append(resourceResponses, resourceResponse)
 	}
@@ -306,11 +333,17 @@ func GetResourcesByKey(c *gin.Context) {
 		responses = append(responses, response)
 	}
 
-	SuccessResponse(c, gin.H{
+	data := gin.H{
 		"resources": responses,
 		"total":     len(responses),
 		"key":       key,
-	})
+	}
+	if metadataService != nil {
+		if m := metadataService.ForKey(key); m != nil {
+			data["metadata"] = metadataBrief(m)
+		}
+	}
+	SuccessResponse(c, data)
 }
 
 // CheckResourceExists 检查资源是否存在（测试FindExists函数）
@@ -480,6 +513,13 @@ func UpdateResource(c *gin.Context) {
 		}()
 	}
 
+	// 清理OG图片缓存，预生成任务会按新的内容重新生成
+	if ogImageService != nil && resource.Key != "" {
+		if err := ogImageService.Invalidate(resource.Key); err != nil {
+			utils.Error("清理OG图片缓存失败 (Key: %s): %v", resource.Key, err)
+		}
+	}
+
 	SuccessResponse(c, gin.H{"message": "资源更新成功"})
 }
 
@@ -492,6 +532,10 @@ func DeleteResource(c *gin.Context) {
 		return
 	}
 
+	if resource, err := repoManager.ResourceRepository.FindByID(uint(id)); err == nil {
+		middleware.SetAuditChange(c, resourceAuditSnapshot([]entity.Resource{*resource}), nil)
+	}
+
 	// 使用事务确保删除操作的原子性
 	err = repoManager.ResourceRepository.GetDB().Transaction(func(tx *gorm.DB) error {
 		// 1. 先删除关联的访问记录（resource_views）
@@ -648,6 +692,18 @@ func IncrementResourceViewCount(c *gin.Context) {
 	SuccessResponse(c, gin.H{"message": "浏览次数+1"})
 }
 
+// resourceAuditSnapshot 资源ID到标题与链接的映射，用于审计删除操作
+func resourceAuditSnapshot(resources []entity.Resource) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(resources))
+	for _, resource := range resources {
+		snapshot[strconv.FormatUint(uint64(resource.ID), 10)] = map[string]string{
+			"title": resource.Title,
+			"url":   resource.URL,
+		}
+	}
+	return snapshot
+}
+
 // BatchDeleteResources 批量删除资源
 func BatchDeleteResources(c *gin.Context) {
 	var req struct {
@@ -658,6 +714,11 @@ func BatchDeleteResources(c *gin.Context) {
 		return
 	}
 
+	// 审计：记录被删除资源的标题与链接
+	if resources, err := repoManager.ResourceRepository.FindByIDs(req.IDs); err == nil {
+		middleware.SetAuditChange(c, resourceAuditSnapshot(resources), nil)
+	}
+
 	var deletedCount int64
 
 	// 使用事务确保批量删除操作的原子性
@@ -691,6 +752,7 @@ func BatchDeleteResources(c *gin.Context) {
 	}
 
 	utils.Info("批量物理删除资源及其关联数据成功：删除 %d 个资源", deletedCount)
+	middleware.SetAuditDetail(c, fmt.Sprintf("请求删除 %d 个资源，实际删除 %d 个，ID: %v", len(req.IDs), deletedCount, req.IDs))
 
 	// 如果启用了Meilisearch，异步删除对应的搜索数据
 	if meilisearchManager != nil && meilisearchManager.IsEnabled() && len(req.IDs) > 0 {
@@ -858,92 +920,80 @@ func GetHotResources(c *gin.Context) {
 		limit = 10
 	}
 
-	// 使用公共缓存机制
-	cacheKey := fmt.Sprintf("hot_resources_%d", limit)
-	ttl := time.Hour // 1小时缓存
-	cacheManager := utils.GetHotResourcesCache()
-
-	// 尝试从缓存获取
-	if cachedData, found := cacheManager.Get(cacheKey, ttl); found {
-		utils.Info("使用热门资源缓存 - key: %s", cacheKey)
-		c.Header("Cache-Control", "public, max-age=3600")
-		c.Header("ETag", fmt.Sprintf("hot-resources-%d", len(cachedData.([]gin.H))))
-
-		// 转换为正确的类型
-		if data, ok := cachedData.([]gin.H); ok {
-			SuccessResponse(c, gin.H{
-				"data":   data,
-				"total":  len(data),
-				"limit":  limit,
-				"cached": true,
-			})
-		}
-		return
-	}
-
-	// 缓存未命中，从数据库获取
-	resources, err := repoManager.ResourceRepository.GetHotResources(limit)
-	if err != nil {
-		utils.Error("获取热门资源失败: %v", err)
-		ErrorResponse(c, "获取热门资源失败", http.StatusInternalServerError)
-		return
-	}
-
-	// 获取违禁词配置
-	cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
-		return repoManager.SystemConfigRepository.GetConfigValue(entity.ConfigKeyForbiddenWords)
-	})
-	if err != nil {
-		utils.Error("获取违禁词配置失败: %v", err)
-		cleanWords = []string{}
+	// 热门列表依赖资源、标签与违禁词配置，任一变化时失效
+	cacheKey := fmt.Sprintf("resource:hot:%d", limit)
+	opts := cache.Options{
+		TTL:  time.Hour, // 1小时缓存
+		Tags: []string{cache.AllTag("resource"), cache.AllTag("tag"), cache.AllTag("config")},
 	}
+	cached := true
+	resourceResponses, err := cache.GetOrLoad(c.Request.Context(), cache.Default(), cacheKey, opts, func(context.Context) ([]gin.H, error) {
+		cached = false
+		// 缓存未命中，从数据库获取
+		resources, err := repoManager.ResourceRepository.GetHotResources(limit)
+		if err != nil {
+			return nil, err
+		}
 
-	// 处理违禁词并转换为响应格式
-	var resourceResponses []gin.H
-	for _, resource := range resources {
-		// 检查违禁词
-		forbiddenInfo := utils.CheckResourceForbiddenWords(resource.Title, resource.Description, cleanWords)
-
-		resourceResponse := gin.H{
-			"id":          resource.ID,
-			"key":         resource.Key,
-			"title":       forbiddenInfo.ProcessedTitle,
-			"url":         resource.URL,
-			"description": forbiddenInfo.ProcessedDesc,
-			"pan_id":      resource.PanID,
-			"view_count":  resource.ViewCount,
-			"created_at":  resource.CreatedAt.Format("2006-01-02 15:04:05"),
-			"updated_at":  resource.UpdatedAt.Format("2006-01-02 15:04:05"),
-			"cover":       resource.Cover,
-			"author":      resource.Author,
-			"file_size":   resource.FileSize,
+		// 获取违禁词配置
+		cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
+			return repoManager.SystemConfigRepository.GetConfigValue(entity.ConfigKeyForbiddenWords)
+		})
+		if err != nil {
+			utils.Error("获取违禁词配置失败: %v", err)
+			cleanWords = []string{}
 		}
 
-		// 添加违禁词标记
-		resourceResponse["has_forbidden_words"] = forbiddenInfo.HasForbiddenWords
-		resourceResponse["forbidden_words"] = forbiddenInfo.ForbiddenWords
+		// 处理违禁词并转换为响应格式
+		var resourceResponses []gin.H
+		for _, resource := range resources {
+			// 检查违禁词
+			forbiddenInfo := utils.CheckResourceForbiddenWords(resource.Title, resource.Description, cleanWords)
+
+			resourceResponse := gin.H{
+				"id":          resource.ID,
+				"key":         resource.Key,
+				"title":       forbiddenInfo.ProcessedTitle,
+				"url":         resource.URL,
+				"description": forbiddenInfo.ProcessedDesc,
+				"pan_id":      resource.PanID,
+				"view_count":  resource.ViewCount,
+				"created_at":  resource.CreatedAt.Format("2006-01-02 15:04:05"),
+				"updated_at":  resource.UpdatedAt.Format("2006-01-02 15:04:05"),
+				"cover":       resource.Cover,
+				"author":      resource.Author,
+				"file_size":   resource.FileSize,
+			}
 
-		// 添加标签信息
-		var tagResponses []gin.H
-		if len(resource.Tags) > 0 {
-			for _, tag := range resource.Tags {
-				tagResponse := gin.H{
-					"id":          tag.ID,
-					"name":        tag.Name,
-					"description": tag.Description,
+			// 添加违禁词标记
+			resourceResponse["has_forbidden_words"] = forbiddenInfo.HasForbiddenWords
+			resourceResponse["forbidden_words"] = forbiddenInfo.ForbiddenWords
+
+			// 添加标签信息
+			var tagResponses []gin.H
+			if len(resource.Tags) > 0 {
+				for _, tag := range resource.Tags {
+					tagResponse := gin.H{
+						"id":          tag.ID,
+						"name":        tag.Name,
+						"description": tag.Description,
+					}
+					tagResponses = append(tagResponses, tagResponse)
 				}
-				tagResponses = append(tagResponses, tagResponse)
 			}
-		}
-		resourceResponse["tags"] = tagResponses
+			resourceResponse["tags"] = tagResponses
 
-		resourceResponses = append(resourceResponses, resourceResponse)
+			resourceResponses = append(resourceResponses, resourceResponse)
+		}
+		utils.Info("热门资源已缓存 - key: %s, count: %d", cacheKey, len(resourceResponses))
+		return resourceResponses, nil
+	})
+	if err != nil {
+		utils.Error("获取热门资源失败: %v", err)
+		ErrorResponse(c, "获取热门资源失败", http.StatusInternalServerError)
+		return
 	}
 
-	// 存储到缓存
-	cacheManager.Set(cacheKey, resourceResponses)
-	utils.Info("热门资源已缓存 - key: %s, count: %d", cacheKey, len(resourceResponses))
-
 	// 设置缓存头
 	c.Header("Cache-Control", "public, max-age=3600")
 	c.Header("ETag", fmt.Sprintf("hot-resources-%d", len(resourceResponses)))
@@ -952,7 +1002,7 @@ func GetHotResources(c *gin.Context) {
 		"data":   resourceResponses,
 		"total":  len(resourceResponses),
 		"limit":  limit,
-		"cached": false,
+		"cached": cached,
 	})
 }
 
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// RoleHandler 角色与权限管理处理器
+type RoleHandler struct {
+	service  *services.RBACService
+	validate *validator.Validate
+}
+
+// NewRoleHandler 创建角色与权限管理处理器
+func NewRoleHandler(service *services.RBACService) *RoleHandler {
+	return &RoleHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// ListRoles 获取角色列表
+// @Summary 获取角色列表
+// @Tags Role
+// @Produce json
+// @Success 200 {object} Response{data=[]entity.Role}
+// @Router /roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.service.ListRoles()
+	if err != nil {
+		ErrorResponse(c, "获取角色列表失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, roles)
+}
+
+// ListPermissions 获取全部权限定义
+// @Summary 获取权限列表
+// @Tags Role
+// @Produce json
+// @Success 200 {object} Response{data=[]entity.Permission}
+// @Router /permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	SuccessResponse(c, h.service.ListPermissions())
+}
+
+// CreateRole 创建角色
+// @Summary 创建角色
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateRoleRequest true "角色"
+// @Success 200 {object} Response{data=entity.Role}
+// @Failure 400 {object} Response
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.service.CreateRole(req)
+	if err != nil {
+		ErrorResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	username, _ := c.Get("username")
+	utils.Info("CreateRole - 创建角色 - 操作人: %v, 角色: %s, 权限: %v", username, role.Name, req.Permissions)
+	SuccessResponse(c, role)
+}
+
+// UpdateRole 更新角色名称、描述与权限
+// @Summary 更新角色
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param id path int true "角色ID"
+// @Param body body dto.UpdateRoleRequest true "角色"
+// @Success 200 {object} Response{data=entity.Role}
+// @Failure 400 {object} Response
+// @Router /roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.service.UpdateRole(uint(id), req)
+	if err != nil {
+		ErrorResponse(c, err.Error(), roleErrorStatus(err))
+		return
+	}
+	username, _ := c.Get("username")
+	utils.Info("UpdateRole - 更新角色 - 操作人: %v, 角色: %s, 权限: %v", username, role.Name, req.Permissions)
+	SuccessResponse(c, role)
+}
+
+// DeleteRole 删除自定义角色
+// @Summary 删除角色
+// @Tags Role
+// @Produce json
+// @Param id path int true "角色ID"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteRole(uint(id)); err != nil {
+		ErrorResponse(c, err.Error(), roleErrorStatus(err))
+		return
+	}
+	username, _ := c.Get("username")
+	utils.Info("DeleteRole - 删除角色 - 操作人: %v, 角色ID: %d", username, id)
+	SuccessResponse(c, gin.H{"message": "角色删除成功"})
+}
+
+// ListPermissionDenials 分页查询权限拒绝记录
+// @Summary 获取权限拒绝记录
+// @Tags Role
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param username query string false "用户名"
+// @Param permission query string false "权限码"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /permission-denials [get]
+func (h *RoleHandler) ListPermissionDenials(c *gin.Context) {
+	var req dto.PermissionDenialListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 20
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	denials, total, err := h.service.ListDenials(req.Username, req.Permission, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取权限拒绝记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, denials, total, req.Page, req.PageSize)
+}
+
+// CleanupPermissionDenials 清理指定天数之前的权限拒绝记录
+// @Summary 清理权限拒绝记录
+// @Tags Role
+// @Produce json
+// @Param days query int false "保留天数" default(30)
+// @Success 200 {object} Response
+// @Router /permission-denials [delete]
+func (h *RoleHandler) CleanupPermissionDenials(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 0 {
+		ErrorResponse(c, "参数错误: days 必须为非负整数", http.StatusBadRequest)
+		return
+	}
+	deleted, err := h.service.CleanupDenials(days)
+	if err != nil {
+		ErrorResponse(c, "清理权限拒绝记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"deleted": deleted})
+}
+
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrRoleAdmin), errors.Is(err, services.ErrRoleProtected):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// rolePermissions 返回角色的权限码，角色服务未初始化时为空
+func rolePermissions(role string) []string {
+	if svc := services.GetDefaultRBACService(); svc != nil {
+		return svc.Permissions(role)
+	}
+	return nil
+}
+
+// checkAssignableRole 校验当前用户可以分配该角色：角色必须存在，只有管理员可以分配管理员角色
+func checkAssignableRole(c *gin.Context, role string) bool {
+	if role == entity.RoleAdmin && c.GetString("role") != entity.RoleAdmin {
+		ErrorResponse(c, "只有管理员可以分配管理员角色", http.StatusForbidden)
+		return false
+	}
+	if svc := services.GetDefaultRBACService(); svc != nil && role != entity.RoleAdmin && !svc.RoleExists(role) {
+		ErrorResponse(c, "角色不存在: "+role, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// checkManageableUser 校验当前用户可以管理目标用户：只有管理员可以管理管理员账号
+func checkManageableUser(c *gin.Context, user *entity.User) bool {
+	if user.Role == entity.RoleAdmin && c.GetString("role") != entity.RoleAdmin {
+		ErrorResponse(c, "只有管理员可以管理管理员账号", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -22,11 +22,16 @@ func GetSchedulerStatus(c *gin.Context) {
 		repoManager.TaskRepository,
 	)
 
+	// 多副本部署时各任务只在持有锁的节点运行，jobs 中 holder 为持有节点
+	nodeID, jobs := scheduler.GetLeaderStatus(c.Request.Context())
 	status := gin.H{
 		"hot_drama_scheduler_running":      scheduler.IsHotDramaSchedulerRunning(),
 		"ready_resource_scheduler_running": scheduler.IsReadyResourceRunning(),
 		"google_index_scheduler_running":   scheduler.IsGoogleIndexSchedulerRunning(),
 		"sitemap_scheduler_running":        scheduler.IsSitemapSchedulerRunning(),
+		"node_id":                          nodeID,
+		"leader_election":                  scheduler.IsLeaderElectionEnabled(),
+		"jobs":                             jobs,
 	}
 
 	SuccessResponse(c, status)
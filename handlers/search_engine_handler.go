@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/searchengine"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// searchEngineRunTimeout 后台提交的最长执行时间
+const searchEngineRunTimeout = 30 * time.Minute
+
+// SearchEngineHandler 搜索引擎URL提交处理器
+type SearchEngineHandler struct {
+	service  *services.SearchEngineSubmitService
+	validate *validator.Validate
+	running  atomic.Bool // 后台提交是否在执行
+}
+
+// NewSearchEngineHandler 创建搜索引擎URL提交处理器
+func NewSearchEngineHandler(service *services.SearchEngineSubmitService) *SearchEngineHandler {
+	return &SearchEngineHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// GetConfig 获取搜索引擎URL提交配置
+// @Summary 获取搜索引擎URL提交配置
+// @Tags SearchEngine
+// @Produce json
+// @Success 200 {object} Response{data=services.SearchEngineConfig}
+// @Router /search-engine/config [get]
+func (h *SearchEngineHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, h.service.Config())
+}
+
+// UpdateConfig 更新搜索引擎URL提交配置
+// @Summary 更新搜索引擎URL提交配置
+// @Tags SearchEngine
+// @Accept json
+// @Produce json
+// @Param request body dto.SearchEngineConfigRequest true "配置"
+// @Success 200 {object} Response{data=services.SearchEngineConfig}
+// @Router /search-engine/config [put]
+func (h *SearchEngineHandler) UpdateConfig(c *gin.Context) {
+	var req dto.SearchEngineConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := h.service.Config()
+	cfg.AutoSubmit = req.AutoSubmit
+	cfg.IntervalMinutes = req.IntervalMinutes
+	cfg.MaxAttempts = req.MaxAttempts
+	cfg.IndexNowKey = req.IndexNowKey
+	cfg.IndexNowEndpoint = req.IndexNowEndpoint
+	if req.BaiduToken != nil {
+		cfg.BaiduToken = *req.BaiduToken
+	}
+	for name, setting := range req.Engines {
+		cfg.Engines[name] = services.SearchEngineSetting{Enabled: setting.Enabled, DailyQuota: setting.DailyQuota}
+	}
+
+	saved, err := h.service.SaveConfig(cfg)
+	if err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, saved)
+}
+
+// GetStats 获取各搜索引擎的配额与提交统计
+// @Summary 获取搜索引擎提交统计
+// @Tags SearchEngine
+// @Produce json
+// @Success 200 {object} Response
+// @Router /search-engine/stats [get]
+func (h *SearchEngineHandler) GetStats(c *gin.Context) {
+	stats, err := h.service.Stats()
+	if err != nil {
+		ErrorResponse(c, "获取统计失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"engines": stats, "running": h.running.Load()})
+}
+
+// ListSubmissions 分页查询提交台账
+// @Summary 查询搜索引擎提交台账
+// @Tags SearchEngine
+// @Produce json
+// @Param engine query string false "搜索引擎"
+// @Param status query string false "状态 pending/success/failed"
+// @Param keyword query string false "URL关键字"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /search-engine/submissions [get]
+func (h *SearchEngineHandler) ListSubmissions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	list, total, err := h.service.List(c.Query("engine"), c.Query("status"), strings.TrimSpace(c.Query("keyword")), page, pageSize)
+	if err != nil {
+		ErrorResponse(c, "查询提交记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, list, total, page, pageSize)
+}
+
+// SubmitURLs 手动提交本站URL
+// @Summary 手动提交URL到搜索引擎
+// @Tags SearchEngine
+// @Accept json
+// @Produce json
+// @Param request body dto.SearchEngineSubmitRequest true "URL列表"
+// @Success 200 {object} Response
+// @Router /search-engine/submit [post]
+func (h *SearchEngineHandler) SubmitURLs(c *gin.Context) {
+	var req dto.SearchEngineSubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	queued, err := h.service.EnqueueURLs(req.URLs)
+	if err != nil {
+		ErrorResponse(c, "提交失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, gin.H{"queued": queued, "started": h.runInBackground()})
+}
+
+// RequeueFailed 将失败记录重新排队
+// @Summary 重试失败的提交
+// @Tags SearchEngine
+// @Produce json
+// @Param engine query string false "搜索引擎，为空表示全部"
+// @Success 200 {object} Response
+// @Router /search-engine/requeue-failed [post]
+func (h *SearchEngineHandler) RequeueFailed(c *gin.Context) {
+	count, err := h.service.RequeueFailed(c.Query("engine"))
+	if err != nil {
+		ErrorResponse(c, "重新排队失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"requeued": count})
+}
+
+// Run 立即执行一轮提交
+// @Summary 立即提交待处理的URL
+// @Tags SearchEngine
+// @Produce json
+// @Success 200 {object} Response
+// @Router /search-engine/run [post]
+func (h *SearchEngineHandler) Run(c *gin.Context) {
+	if !h.runInBackground() {
+		ErrorResponse(c, "已有提交任务在执行中", http.StatusConflict)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "任务已在后台执行"})
+}
+
+// runInBackground 后台执行一轮提交，已有任务在执行时返回 false
+func (h *SearchEngineHandler) runInBackground() bool {
+	if !h.running.CompareAndSwap(false, true) {
+		return false
+	}
+	go func() {
+		defer h.running.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), searchEngineRunTimeout)
+		defer cancel()
+
+		results, err := h.service.Process(ctx)
+		if err != nil {
+			utils.Error("手动提交搜索引擎URL失败: %v", err)
+			return
+		}
+		for _, r := range results {
+			utils.Info("手动提交搜索引擎URL完成: %+v", r)
+		}
+	}()
+	return true
+}
+
+// ServeIndexNowKey 在站点根目录提供 IndexNow 密钥文件（/indexnow-{key}.txt）
+func (h *SearchEngineHandler) ServeIndexNowKey(c *gin.Context) {
+	key, err := repoManager.SystemConfigRepository.GetConfigValue(entity.SearchEngineConfigKeyIndexNowKey)
+	if err != nil || key == "" || "indexnow-"+c.Param("file") != searchengine.IndexNowKeyFile(key) {
+		c.String(http.StatusNotFound, "not found")
+		return
+	}
+	c.String(http.StatusOK, key)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// SEOHandler 资源页SEO数据处理器
+type SEOHandler struct {
+	service  *services.SEOService
+	validate *validator.Validate
+}
+
+// NewSEOHandler 创建资源页SEO数据处理器
+func NewSEOHandler(service *services.SEOService) *SEOHandler {
+	return &SEOHandler{
+		service:  service,
+		validate: validator.New(),
+	}
+}
+
+// GetResourceSEO 获取资源页的SEO数据
+// @Summary 获取资源页SEO数据
+// @Description 返回 canonical、meta 标题/描述、JSON-LD、hreflang 与 noindex 决策，供前端渲染资源页
+// @Tags SEO
+// @Produce json
+// @Param key path string true "资源Key"
+// @Success 200 {object} Response{data=seo.Payload}
+// @Router /seo/resources/{key} [get]
+func (h *SEOHandler) GetResourceSEO(c *gin.Context) {
+	key := strings.TrimSpace(c.Param("key"))
+	if key == "" {
+		ErrorResponse(c, "Key参数不能为空", http.StatusBadRequest)
+		return
+	}
+
+	payload, err := h.service.ForKey(key)
+	if err != nil {
+		if errors.Is(err, services.ErrSEOResourceNotFound) {
+			ErrorResponse(c, "资源不存在", http.StatusNotFound)
+			return
+		}
+		utils.Error("生成资源SEO数据失败 key=%s: %v", key, err)
+		ErrorResponse(c, "获取SEO数据失败", http.StatusInternalServerError)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	SuccessResponse(c, payload)
+}
+
+// GetConfig 获取资源页SEO配置
+// @Summary 获取资源页SEO配置
+// @Tags SEO
+// @Produce json
+// @Success 200 {object} Response{data=services.SEOConfig}
+// @Router /seo/config [get]
+func (h *SEOHandler) GetConfig(c *gin.Context) {
+	SuccessResponse(c, h.service.Config())
+}
+
+// UpdateConfig 更新资源页SEO配置
+// @Summary 更新资源页SEO配置
+// @Tags SEO
+// @Accept json
+// @Produce json
+// @Param request body dto.SEOConfigRequest true "配置"
+// @Success 200 {object} Response{data=services.SEOConfig}
+// @Router /seo/config [put]
+func (h *SEOHandler) UpdateConfig(c *gin.Context) {
+	var req dto.SEOConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hreflang := make([]string, 0, len(req.Hreflang))
+	for _, lang := range req.Hreflang {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			hreflang = append(hreflang, lang)
+		}
+	}
+	cfg, err := h.service.SaveConfig(services.SEOConfig{
+		TitleTemplate:       req.TitleTemplate,
+		DescriptionTemplate: req.DescriptionTemplate,
+		Hreflang:            hreflang,
+	})
+	if err != nil {
+		ErrorResponse(c, "保存配置失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, cfg)
+}
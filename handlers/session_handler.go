@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/converter"
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/middleware"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// issueLoginResponse 为用户创建会话并签发访问令牌与刷新令牌
+func issueLoginResponse(c *gin.Context, user *entity.User) (*dto.LoginResponse, error) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		return nil, errors.New("会话服务未初始化")
+	}
+	session, refreshToken, err := svc.CreateSession(user, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		return nil, err
+	}
+	return buildLoginResponse(user, session.SessionID, refreshToken)
+}
+
+func buildLoginResponse(user *entity.User, sessionID, refreshToken string) (*dto.LoginResponse, error) {
+	token, expiresAt, err := middleware.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	response := &dto.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+		User:         converter.ToUserResponse(user),
+	}
+	response.User.Permissions = rolePermissions(user.Role)
+	return response, nil
+}
+
+// revokeUserSessions 吊销用户全部会话，用于改密、禁用、删除用户等场景
+func revokeUserSessions(userID uint, reason string) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		return
+	}
+	count, err := svc.RevokeUserSessions(userID, "", reason)
+	if err != nil {
+		utils.Error("吊销用户会话失败 - 用户ID: %d, 原因: %s, Error: %v", userID, reason, err)
+		return
+	}
+	if count > 0 {
+		utils.Info("已吊销用户会话 - 用户ID: %d, 数量: %d, 原因: %s", userID, count, reason)
+	}
+}
+
+// RefreshToken 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换
+// @Summary 刷新访问令牌
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} Response{data=dto.LoginResponse}
+// @Failure 401 {object} Response
+// @Router /auth/refresh [post]
+func RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+
+	user, session, refreshToken, err := svc.Refresh(req.RefreshToken, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRefreshTokenInvalid), errors.Is(err, services.ErrRefreshTokenReused), errors.Is(err, services.ErrUserDisabled):
+			utils.Warn("RefreshToken - 刷新失败 - IP: %s, Error: %v", c.ClientIP(), err)
+			ErrorResponse(c, err.Error(), http.StatusUnauthorized)
+		default:
+			utils.Error("RefreshToken - 刷新失败 - IP: %s, Error: %v", c.ClientIP(), err)
+			ErrorResponse(c, "刷新令牌失败", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response, err := buildLoginResponse(user, session.SessionID, refreshToken)
+	if err != nil {
+		utils.Error("RefreshToken - 生成令牌失败 - 用户: %s(ID:%d), Error: %v", user.Username, user.ID, err)
+		ErrorResponse(c, "生成令牌失败", http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, response)
+}
+
+// Logout 登出，吊销当前会话
+// @Summary 登出
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+	if err := svc.Logout(c.GetString("session_id")); err != nil {
+		ErrorResponse(c, "登出失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Info("Logout - 用户登出 - 用户: %s, IP: %s", c.GetString("username"), c.ClientIP())
+	SuccessResponse(c, gin.H{"message": "已登出"})
+}
+
+// ListMySessions 获取当前用户的登录会话
+// @Summary 获取我的登录会话
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response{data=[]dto.UserSessionResponse}
+// @Router /auth/sessions [get]
+func ListMySessions(c *gin.Context) {
+	listSessions(c, c.GetUint("user_id"))
+}
+
+// RevokeMySession 吊销当前用户的指定会话
+// @Summary 吊销我的登录会话
+// @Tags Auth
+// @Produce json
+// @Param session_id path string true "会话标识"
+// @Success 200 {object} Response
+// @Failure 404 {object} Response
+// @Router /auth/sessions/{session_id} [delete]
+func RevokeMySession(c *gin.Context) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+	if err := svc.RevokeSession(c.GetUint("user_id"), c.Param("session_id")); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			ErrorResponse(c, err.Error(), http.StatusNotFound)
+			return
+		}
+		ErrorResponse(c, "吊销会话失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "会话已吊销"})
+}
+
+// RevokeMyOtherSessions 吊销当前用户除当前会话外的所有会话
+// @Summary 退出其他设备
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response
+// @Router /auth/sessions [delete]
+func RevokeMyOtherSessions(c *gin.Context) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+	count, err := svc.RevokeUserSessions(c.GetUint("user_id"), c.GetString("session_id"), entity.SessionRevokeManual)
+	if err != nil {
+		ErrorResponse(c, "吊销会话失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "其他会话已吊销", "revoked": count})
+}
+
+// ListUserSessions 获取指定用户的登录会话（管理员）
+// @Summary 获取用户登录会话
+// @Tags User
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} Response{data=[]dto.UserSessionResponse}
+// @Router /users/{id}/sessions [get]
+func ListUserSessions(c *gin.Context) {
+	user, ok := findManageableUser(c)
+	if !ok {
+		return
+	}
+	listSessions(c, user.ID)
+}
+
+// RevokeAllUserSessions 吊销指定用户的全部会话（管理员）
+// @Summary 强制用户下线
+// @Tags User
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} Response
+// @Router /users/{id}/sessions [delete]
+func RevokeAllUserSessions(c *gin.Context) {
+	user, ok := findManageableUser(c)
+	if !ok {
+		return
+	}
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+	count, err := svc.RevokeUserSessions(user.ID, "", entity.SessionRevokeManual)
+	if err != nil {
+		ErrorResponse(c, "吊销会话失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Info("RevokeAllUserSessions - 强制用户下线 - 操作人: %s, 用户: %s(ID:%d), 数量: %d", c.GetString("username"), user.Username, user.ID, count)
+	SuccessResponse(c, gin.H{"message": "用户会话已全部吊销", "revoked": count})
+}
+
+func listSessions(c *gin.Context, userID uint) {
+	svc := services.GetDefaultAuthSessionService()
+	if svc == nil {
+		ErrorResponse(c, "会话服务未初始化", http.StatusInternalServerError)
+		return
+	}
+	sessions, err := svc.ListSessions(userID)
+	if err != nil {
+		ErrorResponse(c, "获取会话列表失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, converter.ToUserSessionResponseList(sessions, c.GetString("session_id")))
+}
+
+func findManageableUser(c *gin.Context) (*entity.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return nil, false
+	}
+	user, err := repoManager.UserRepository.FindByID(uint(id))
+	if err != nil {
+		ErrorResponse(c, "用户不存在", http.StatusNotFound)
+		return nil, false
+	}
+	if !checkManageableUser(c, user) {
+		return nil, false
+	}
+	return user, true
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/sitemap"
 	"github.com/ctwj/urldb/scheduler"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -48,8 +49,17 @@ func SetSitemapDependencies(
 	categoryRepo = categoryRepository
 }
 
+const (
+	SITEMAP_MAX_URLS = sitemap.MaxURLsPerFile // 每个sitemap最多5万个URL
+	SITEMAP_DIR      = "./data/sitemap"       // sitemap文件目录
+)
 
-const SITEMAP_MAX_URLS = 50000 // 每个sitemap最多5万个URL
+// sitemapResourceQuery 可收录到sitemap的资源：有效、公开、未删除
+func sitemapResourceQuery() *gorm.DB {
+	return resourceRepo.GetDB().Model(&entity.Resource{}).
+		Where("is_valid = ? AND is_public = ?", true, true).
+		Where("key <> ''")
+}
 
 // SitemapIndex sitemap索引结构
 type SitemapIndex struct {
@@ -200,7 +210,7 @@ func UpdateSitemapConfig(c *gin.Context) {
 func GenerateSitemap(c *gin.Context) {
 	// 获取资源总数
 	var total int64
-	if err := resourceRepo.GetDB().Model(&entity.Resource{}).Count(&total).Error; err != nil {
+	if err := sitemapResourceQuery().Count(&total).Error; err != nil {
 		ErrorResponse(c, "获取资源总数失败", http.StatusInternalServerError)
 		return
 	}
@@ -235,7 +245,7 @@ func GenerateSitemap(c *gin.Context) {
 		"total_resources": total,
 		"total_pages":     totalPages,
 		"status":          "started",
-		"message":         fmt.Sprintf("开始生成 %d 个sitemap文件", totalPages),
+		"message":         "开始增量生成sitemap，仅重写有变更的分页",
 	}
 
 	SuccessResponse(c, result)
@@ -253,7 +263,7 @@ type SitemapFileInfo struct {
 func GetSitemapStatus(c *gin.Context) {
 	// 获取资源总数
 	var total int64
-	if err := resourceRepo.GetDB().Model(&entity.Resource{}).Count(&total).Error; err != nil {
+	if err := sitemapResourceQuery().Count(&total).Error; err != nil {
 		ErrorResponse(c, "获取资源总数失败", http.StatusInternalServerError)
 		return
 	}
@@ -262,14 +272,14 @@ func GetSitemapStatus(c *gin.Context) {
 	totalPages := int((total + SITEMAP_MAX_URLS - 1) / SITEMAP_MAX_URLS)
 
 	// 获取 data/sitemap 目录下的文件列表
-	sitemapDir := "./data/sitemap"
+	sitemapDir := SITEMAP_DIR
 	var files []SitemapFileInfo
 	var latestModTime time.Time
 
 	entries, err := os.ReadDir(sitemapDir)
 	if err == nil {
 		for _, entry := range entries {
-			if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".xml")) {
+			if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".xml") || strings.HasSuffix(entry.Name(), ".xml.gz")) {
 				info, err := entry.Info()
 				if err == nil {
 					modTime := info.ModTime()
@@ -318,10 +328,17 @@ func GetSitemapStatus(c *gin.Context) {
 		}
 	}
 
+	// 增量生成状态
+	lastFullStr := ""
+	if manifest := sitemap.LoadManifest(sitemapDir); manifest != nil && !manifest.FullAt.IsZero() {
+		lastFullStr = manifest.FullAt.Format("2006-01-02 15:04:05")
+	}
+
 	result := map[string]interface{}{
 		"total_resources": total,
 		"total_pages":     totalPages,
 		"last_generate":   lastGenerateStr,
+		"last_full":       lastFullStr,
 		"status":          "ready",
 		"is_running":      isRunning,
 		"auto_generate":   autoGenerateEnabled,
@@ -355,7 +372,7 @@ func formatFileSize(size int64) string {
 func SitemapIndexHandler(c *gin.Context) {
 	// 获取资源总数
 	var total int64
-	if err := resourceRepo.GetDB().Model(&entity.Resource{}).Count(&total).Error; err != nil {
+	if err := sitemapResourceQuery().Count(&total).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取资源总数失败"})
 		return
 	}
@@ -405,7 +422,7 @@ func SitemapPageHandler(c *gin.Context) {
 	limit := SITEMAP_MAX_URLS
 
 	var resources []entity.Resource
-	if err := resourceRepo.GetDB().Offset(offset).Limit(limit).Find(&resources).Error; err != nil {
+	if err := sitemapResourceQuery().Order("id ASC").Offset(offset).Limit(limit).Find(&resources).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "获取资源数据失败"})
 		return
 	}
@@ -423,12 +440,9 @@ func SitemapPageHandler(c *gin.Context) {
 
 	var urls []Url
 	for _, resource := range resources {
-		// 使用资源的创建时间作为 lastmod，因为资源内容创建后很少改变
-		lastMod := resource.CreatedAt
-
 		urls = append(urls, Url{
 			Loc:        fmt.Sprintf("%s/r/%s", baseURL, resource.Key),
-			LastMod:    lastMod.Format("2006-01-01"), // 只保留日期部分
+			LastMod:    resource.UpdatedAt.Format("2006-01-02"), // 只保留日期部分
 			ChangeFreq: "weekly",
 			Priority:   0.8,
 		})
@@ -447,7 +461,7 @@ func SitemapPageHandler(c *gin.Context) {
 func GenerateFullSitemap(c *gin.Context) {
 	// 获取资源总数
 	var total int64
-	if err := resourceRepo.GetDB().Model(&entity.Resource{}).Count(&total).Error; err != nil {
+	if err := sitemapResourceQuery().Count(&total).Error; err != nil {
 		ErrorResponse(c, "获取资源总数失败", http.StatusInternalServerError)
 		return
 	}
@@ -460,8 +474,8 @@ func GenerateFullSitemap(c *gin.Context) {
 		repoManager.TaskRepository,
 	)
 
-	// 手动触发sitemap生成
-	globalScheduler.TriggerSitemapGeneration()
+	// 手动触发sitemap全量生成
+	globalScheduler.TriggerFullSitemapGeneration()
 
 	// 记录最后生成时间为当前时间
 	lastGenerateStr := time.Now().Format("2006-01-02 15:04:05")
@@ -477,7 +491,7 @@ func GenerateFullSitemap(c *gin.Context) {
 	}
 
 	result := map[string]interface{}{
-		"message":         "Sitemap生成任务已启动",
+		"message":         "Sitemap全量生成任务已启动",
 		"total_resources": total,
 		"status":          "processing",
 		"estimated_time":  fmt.Sprintf("%d秒", total/1000), // 估算时间
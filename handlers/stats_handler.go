@@ -126,34 +126,33 @@ func GetPerformanceStats(c *gin.Context) {
 	})
 }
 
-// GetSystemInfo 获取系统信息
+// GetSystemInfo 获取系统信息，备份服务已初始化时附带备份状态
 func GetSystemInfo(c *gin.Context) {
-	SuccessResponse(c, gin.H{
+	info := gin.H{
 		"uptime":     time.Since(startTime).String(),
 		"start_time": utils.FormatTime(startTime, utils.TimeFormatDateTime),
 		"version":    utils.Version,
 		"environment": gin.H{
 			"gin_mode": gin.Mode(),
 		},
-	})
+	}
+	if svc := services.GetDefaultBackupService(); svc != nil {
+		info["backup"] = svc.Status()
+	}
+	SuccessResponse(c, info)
 }
 
-// GetViewsTrend 获取访问量趋势数据
+// GetViewsTrend 获取访问量趋势数据（近7天，已汇总日期读取日汇总表）
 func GetViewsTrend(c *gin.Context) {
-	// 使用访问记录表获取最近7天的访问量数据
-	results, err := repoManager.ResourceViewRepository.GetViewsTrend(7)
-	if err != nil {
-		utils.Error("获取访问量趋势数据失败: %v", err)
-		// 如果获取失败，返回空数据
-		results = []map[string]interface{}{}
-	}
-
-	// 添加调试日志
-	utils.Info("访问量趋势数据: %+v", results)
-	for i, result := range results {
-		utils.Info("第%d天: 日期=%s, 访问量=%d", i+1, result["date"], result["views"])
+	results := []map[string]interface{}{}
+	if svc := services.GetDefaultStatsService(); svc != nil {
+		trend, err := svc.ViewsTrend(7)
+		if err != nil {
+			utils.Error("获取访问量趋势数据失败: %v", err)
+		} else {
+			results = trend
+		}
 	}
-
 	SuccessResponse(c, results)
 }
 
@@ -167,39 +166,17 @@ func GetInvalidTrend(c *gin.Context) {
 	SuccessResponse(c, results)
 }
 
-// GetSearchesTrend 获取搜索量趋势数据
+// GetSearchesTrend 获取搜索量趋势数据（近7天，已汇总日期读取日汇总表）
 func GetSearchesTrend(c *gin.Context) {
-	// 获取最近7天的搜索量数据
-	var results []gin.H
-
-	// 生成最近7天的日期
-	for i := 6; i >= 0; i-- {
-		date := utils.GetCurrentTime().AddDate(0, 0, -i)
-		dateStr := date.Format(utils.TimeFormatDate)
-
-		// 查询该日期的搜索量（从搜索统计表）
-		var searches int64
-		db.DB.Model(&entity.SearchStat{}).
-			Where("DATE(date) = ?", dateStr).
-			Count(&searches)
-
-		// 如果没有搜索记录，返回0
-		// 移除模拟数据生成逻辑，只返回真实数据
-
-		results = append(results, gin.H{
-			"date":     dateStr,
-			"searches": searches,
-		})
-	}
-
-	// 添加调试日志
-	utils.Info("搜索量趋势数据: %+v", results)
-
-	// 添加更详细的调试信息
-	for i, result := range results {
-		utils.Info("第%d天: 日期=%s, 搜索量=%d", i+1, result["date"], result["searches"])
+	results := []map[string]interface{}{}
+	if svc := services.GetDefaultStatsService(); svc != nil {
+		trend, err := svc.SearchesTrend(7)
+		if err != nil {
+			utils.Error("获取搜索量趋势数据失败: %v", err)
+		} else {
+			results = trend
+		}
 	}
-
 	SuccessResponse(c, results)
 }
 
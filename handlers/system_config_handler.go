@@ -8,6 +8,7 @@ import (
 	"github.com/ctwj/urldb/db/dto"
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/scheduler"
 	"github.com/ctwj/urldb/utils"
 
@@ -289,10 +290,23 @@ func UpdateSystemConfig(c *gin.Context) {
 
 	utils.Info("配置更新完成，当前配置数量: %d", len(updatedConfigs))
 
+	// 审计：记录配置项修改前后的值，密钥类配置由审计服务脱敏
+	middleware.SetAuditAction(c, "system_config.update", "system_config", "")
+	middleware.SetAuditChange(c, systemConfigValues(currentConfigs), systemConfigValues(updatedConfigs))
+
 	configResponse := converter.SystemConfigToResponse(updatedConfigs)
 	SuccessResponse(c, configResponse)
 }
 
+// systemConfigValues 配置项键值表，用于审计差异
+func systemConfigValues(configs []entity.SystemConfig) map[string]string {
+	values := make(map[string]string, len(configs))
+	for _, config := range configs {
+		values[config.Key] = config.Value
+	}
+	return values
+}
+
 // 新增：公开获取系统配置（不含api_token）
 func GetPublicSystemConfig(c *gin.Context) {
 	configs, err := repoManager.SystemConfigRepository.GetOrCreateDefault()
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// telegramExportMaxSize 导出文件上传大小上限
+const telegramExportMaxSize = 50 << 20
+
+// TelegramImportHandler Telegram 采集频道管理处理器
+type TelegramImportHandler struct {
+	channelRepo repo.TelegramImportChannelRepository
+	importer    *services.TelegramChannelImporter
+	validate    *validator.Validate
+}
+
+// NewTelegramImportHandler 创建 Telegram 采集频道管理处理器
+func NewTelegramImportHandler(channelRepo repo.TelegramImportChannelRepository, importer *services.TelegramChannelImporter) *TelegramImportHandler {
+	return &TelegramImportHandler{
+		channelRepo: channelRepo,
+		importer:    importer,
+		validate:    validator.New(),
+	}
+}
+
+// ListImportChannels 获取采集频道列表
+func (h *TelegramImportHandler) ListImportChannels(c *gin.Context) {
+	channels, err := h.channelRepo.FindAll()
+	if err != nil {
+		ErrorResponse(c, "获取采集频道失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ListResponse(c, channels, int64(len(channels)))
+}
+
+// CreateImportChannel 创建采集频道
+func (h *TelegramImportHandler) CreateImportChannel(c *gin.Context) {
+	channel := &entity.TelegramImportChannel{Enabled: true}
+	if !h.bindRequest(c, channel) {
+		return
+	}
+
+	if existing, err := h.channelRepo.FindByChatID(channel.ChatID); err == nil && existing != nil {
+		ErrorResponse(c, "该频道已存在", http.StatusBadRequest)
+		return
+	}
+	if err := h.channelRepo.Create(channel); err != nil {
+		ErrorResponse(c, "创建采集频道失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, channel)
+}
+
+// UpdateImportChannel 更新采集频道
+func (h *TelegramImportHandler) UpdateImportChannel(c *gin.Context) {
+	channel, ok := h.findChannel(c)
+	if !ok {
+		return
+	}
+	if !h.bindRequest(c, channel) {
+		return
+	}
+	if err := h.channelRepo.Update(channel); err != nil {
+		ErrorResponse(c, "更新采集频道失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, channel)
+}
+
+// DeleteImportChannel 删除采集频道
+func (h *TelegramImportHandler) DeleteImportChannel(c *gin.Context) {
+	channel, ok := h.findChannel(c)
+	if !ok {
+		return
+	}
+	if err := h.channelRepo.Delete(channel.ID); err != nil {
+		ErrorResponse(c, "删除采集频道失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, gin.H{"message": "删除成功"})
+}
+
+// ImportChannelExport 上传频道导出的 JSON 历史并导入
+// 支持 multipart 字段 file，或直接以请求体提交 JSON。
+func (h *TelegramImportHandler) ImportChannelExport(c *gin.Context) {
+	channel, ok := h.findChannel(c)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			ErrorResponse(c, "获取上传文件失败", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, telegramExportMaxSize+1))
+	if err != nil {
+		ErrorResponse(c, "读取导出文件失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(body) > telegramExportMaxSize {
+		ErrorResponse(c, "导出文件过大", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.importer.ImportExport(channel, body)
+	if err != nil {
+		ErrorResponse(c, "导入失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	SuccessResponse(c, result)
+}
+
+// findChannel 按路径参数 id 查找采集频道，失败时已写入响应
+func (h *TelegramImportHandler) findChannel(c *gin.Context) (*entity.TelegramImportChannel, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		ErrorResponse(c, "无效的ID", http.StatusBadRequest)
+		return nil, false
+	}
+	channel, err := h.channelRepo.FindByID(uint(id))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ErrorResponse(c, "采集频道不存在", http.StatusNotFound)
+			return nil, false
+		}
+		ErrorResponse(c, "获取采集频道失败: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return channel, true
+}
+
+// bindRequest 绑定并校验请求，写入 channel；失败时已写入响应
+func (h *TelegramImportHandler) bindRequest(c *gin.Context, channel *entity.TelegramImportChannel) bool {
+	var req dto.TelegramImportChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		ErrorResponse(c, "参数验证失败: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	channel.MinPostDate = nil
+	if req.MinPostDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.MinPostDate, time.Local)
+		if err != nil {
+			ErrorResponse(c, "参数错误: min_post_date 格式应为 2006-01-02", http.StatusBadRequest)
+			return false
+		}
+		channel.MinPostDate = &t
+	}
+
+	channel.ChatID = req.ChatID
+	channel.ChatUsername = strings.TrimPrefix(strings.TrimSpace(req.ChatUsername), "@")
+	channel.ChatTitle = req.ChatTitle
+	channel.IncludeKeywords = req.IncludeKeywords
+	channel.ExcludeKeywords = req.ExcludeKeywords
+	channel.Platforms = req.Platforms
+	channel.Category = req.Category
+	channel.Tags = req.Tags
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	return true
+}
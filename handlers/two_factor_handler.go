@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func loginSecurityService(c *gin.Context) *services.LoginSecurityService {
+	svc := services.GetDefaultLoginSecurityService()
+	if svc == nil {
+		ErrorResponse(c, "登录保护服务未初始化", http.StatusInternalServerError)
+	}
+	return svc
+}
+
+// twoFactorErrorResponse 两步验证的业务错误返回 400，其余返回 500
+func twoFactorErrorResponse(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorCodeInvalid),
+		errors.Is(err, services.ErrTwoFactorNotSetup),
+		errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		ErrorResponse(c, err.Error(), http.StatusBadRequest)
+	default:
+		ErrorResponse(c, action+"失败: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// runThrottledTwoFactor 执行需要校验验证码或恢复码的操作，与登录共用失败限流：
+// 被锁定时返回 429，验证码错误计入当前用户名与IP的失败次数，防止借已登录的会话暴力破解恢复码。
+// 返回 false 时已写入错误响应
+func runThrottledTwoFactor(c *gin.Context, svc *services.LoginSecurityService, action string, run func() error) bool {
+	username := c.GetString("username")
+	clientIP := c.ClientIP()
+	if err := svc.CheckThrottle(username, clientIP); err != nil {
+		var throttleErr *services.LoginThrottleError
+		if errors.As(err, &throttleErr) {
+			utils.Warn("%s - 验证失败次数过多已被限流 - 用户: %s, IP: %s", action, username, clientIP)
+			c.Header("Retry-After", strconv.Itoa(throttleErr.RetryAfter))
+			ErrorResponse(c, throttleErr.Error(), http.StatusTooManyRequests)
+			return false
+		}
+		utils.Error("%s - 检查登录限流失败: %v", action, err)
+	}
+	if err := run(); err != nil {
+		if errors.Is(err, services.ErrTwoFactorCodeInvalid) {
+			utils.Warn("%s - 两步验证失败 - 用户: %s, IP: %s", action, username, clientIP)
+			svc.RecordLogin(c.GetUint("user_id"), username, clientIP, c.Request.UserAgent(), false, entity.LoginFailBadTwoFactor, false)
+		}
+		twoFactorErrorResponse(c, action, err)
+		return false
+	}
+	return true
+}
+
+// GetTwoFactorStatus 获取当前用户的两步验证状态
+// @Summary 获取两步验证状态
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response{data=dto.TwoFactorStatusResponse}
+// @Router /auth/2fa [get]
+func GetTwoFactorStatus(c *gin.Context) {
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	enabled, remaining, err := svc.TwoFactorStatus(c.GetUint("user_id"))
+	if err != nil {
+		ErrorResponse(c, "获取两步验证状态失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	SuccessResponse(c, dto.TwoFactorStatusResponse{Enabled: enabled, RecoveryCodesRemaining: remaining})
+}
+
+// SetupTwoFactor 生成两步验证密钥与二维码，需再调用启用接口确认
+// @Summary 初始化两步验证
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response{data=dto.TwoFactorSetupResponse}
+// @Failure 400 {object} Response
+// @Router /auth/2fa/setup [post]
+func SetupTwoFactor(c *gin.Context) {
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	user, err := repoManager.UserRepository.FindByID(c.GetUint("user_id"))
+	if err != nil {
+		ErrorResponse(c, "用户不存在", http.StatusNotFound)
+		return
+	}
+	setup, err := svc.SetupTwoFactor(user)
+	if err != nil {
+		twoFactorErrorResponse(c, "初始化两步验证", err)
+		return
+	}
+	SuccessResponse(c, dto.TwoFactorSetupResponse{Secret: setup.Secret, URI: setup.URI, QRCode: setup.QRCode})
+}
+
+// EnableTwoFactor 校验验证码并启用两步验证，返回恢复码
+// @Summary 启用两步验证
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.TwoFactorCodeRequest true "验证器中的验证码"
+// @Success 200 {object} Response{data=dto.RecoveryCodesResponse}
+// @Failure 400 {object} Response
+// @Router /auth/2fa/enable [post]
+func EnableTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	codes, err := svc.EnableTwoFactor(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		twoFactorErrorResponse(c, "启用两步验证", err)
+		return
+	}
+	utils.Info("EnableTwoFactor - 已启用两步验证 - 用户: %s, IP: %s", c.GetString("username"), c.ClientIP())
+	SuccessResponse(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor 校验验证码或恢复码后停用两步验证
+// @Summary 停用两步验证
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} Response
+// @Failure 400 {object} Response
+// @Router /auth/2fa/disable [post]
+func DisableTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	if !runThrottledTwoFactor(c, svc, "停用两步验证", func() error {
+		return svc.DisableTwoFactor(c.GetUint("user_id"), req.Code)
+	}) {
+		return
+	}
+	utils.Warn("DisableTwoFactor - 已停用两步验证 - 用户: %s, IP: %s", c.GetString("username"), c.ClientIP())
+	SuccessResponse(c, gin.H{"message": "两步验证已停用"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码失效
+// @Summary 重新生成恢复码
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body dto.TwoFactorCodeRequest true "验证码或恢复码"
+// @Success 200 {object} Response{data=dto.RecoveryCodesResponse}
+// @Failure 400 {object} Response
+// @Router /auth/2fa/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	var codes []string
+	if !runThrottledTwoFactor(c, svc, "生成恢复码", func() (err error) {
+		codes, err = svc.RegenerateRecoveryCodes(c.GetUint("user_id"), req.Code)
+		return err
+	}) {
+		return
+	}
+	SuccessResponse(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ResetUserTwoFactor 重置指定用户的两步验证（管理员）
+// @Summary 重置用户两步验证
+// @Tags User
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} Response
+// @Router /users/{id}/2fa [delete]
+func ResetUserTwoFactor(c *gin.Context) {
+	user, ok := findManageableUser(c)
+	if !ok {
+		return
+	}
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	if err := svc.ResetTwoFactor(user.ID); err != nil {
+		ErrorResponse(c, "重置两步验证失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	utils.Warn("ResetUserTwoFactor - 重置用户两步验证 - 操作人: %s, 用户: %s(ID:%d)", c.GetString("username"), user.Username, user.ID)
+	SuccessResponse(c, gin.H{"message": "两步验证已重置"})
+}
+
+// ListMyLoginHistory 获取当前用户的登录记录
+// @Summary 获取我的登录记录
+// @Tags Auth
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} Response
+// @Router /auth/login-history [get]
+func ListMyLoginHistory(c *gin.Context) {
+	listLoginHistory(c, c.GetUint("user_id"))
+}
+
+// ListLoginHistory 查询全部登录记录（管理员）
+// @Summary 获取登录记录
+// @Tags User
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param username query string false "用户名"
+// @Param ip query string false "IP"
+// @Param success query bool false "是否成功"
+// @Success 200 {object} Response
+// @Router /login-history [get]
+func ListLoginHistory(c *gin.Context) {
+	listLoginHistory(c, 0)
+}
+
+func listLoginHistory(c *gin.Context, userID uint) {
+	var req dto.LoginHistoryListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		ErrorResponse(c, "参数错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+	svc := loginSecurityService(c)
+	if svc == nil {
+		return
+	}
+	if userID > 0 {
+		req.Username = ""
+	}
+	histories, total, err := svc.ListHistory(userID, req.Username, req.IP, req.Success, req.Page, req.PageSize)
+	if err != nil {
+		ErrorResponse(c, "获取登录记录失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	PageResponse(c, histories, total, req.Page, req.PageSize)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/services"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeHandlerLoginHistoryRepo struct {
+	repo.LoginHistoryRepository
+	mu      sync.Mutex
+	history []entity.LoginHistory
+}
+
+func (f *fakeHandlerLoginHistoryRepo) Create(h *entity.LoginHistory) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history = append(f.history, *h)
+	return nil
+}
+
+func (f *fakeHandlerLoginHistoryRepo) countFailures(match func(entity.LoginHistory) bool) (int64, *time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	var latest *time.Time
+	for _, h := range f.history {
+		if !h.Success && match(h) {
+			count++
+			at := h.CreatedAt
+			latest = &at
+		}
+	}
+	return count, latest, nil
+}
+
+func (f *fakeHandlerLoginHistoryRepo) CountFailuresByUsername(username string, since time.Time) (int64, *time.Time, error) {
+	return f.countFailures(func(h entity.LoginHistory) bool { return h.Username == username })
+}
+
+func (f *fakeHandlerLoginHistoryRepo) CountFailuresByIP(ip string, since time.Time) (int64, *time.Time, error) {
+	return f.countFailures(func(h entity.LoginHistory) bool { return h.IP == ip })
+}
+
+func (f *fakeHandlerLoginHistoryRepo) LastSuccessAt(username string) (*time.Time, error) {
+	return nil, nil
+}
+
+func (f *fakeHandlerLoginHistoryRepo) DeleteBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeHandlerTwoFactorRepo struct {
+	repo.UserTwoFactorRepository
+	deleted bool
+}
+
+func (f *fakeHandlerTwoFactorRepo) FindByUserID(userID uint) (*entity.UserTwoFactor, error) {
+	return &entity.UserTwoFactor{ID: 1, UserID: userID, Secret: "JBSWY3DPEHPK3PXP", Enabled: true}, nil
+}
+
+func (f *fakeHandlerTwoFactorRepo) DeleteByUserID(userID uint) error {
+	f.deleted = true
+	return nil
+}
+
+func TestTwoFactorActionsShareLoginThrottle(t *testing.T) {
+	history := &fakeHandlerLoginHistoryRepo{}
+	twoFactor := &fakeHandlerTwoFactorRepo{}
+	orig := services.GetDefaultLoginSecurityService()
+	services.SetDefaultLoginSecurityService(services.NewLoginSecurityService(history, twoFactor, nil))
+	defer services.SetDefaultLoginSecurityService(orig)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", uint(7))
+		c.Set("username", "alice")
+		c.Next()
+	})
+	r.POST("/auth/2fa/disable", DisableTwoFactor)
+	r.POST("/auth/2fa/recovery-codes", RegenerateRecoveryCodes)
+	post := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"code":"wrong-recovery-code"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 错误的恢复码计入登录失败次数，两个接口共用同一计数
+	var attempts int
+	for _, path := range []string{"/auth/2fa/disable", "/auth/2fa/recovery-codes"} {
+		for i := 0; i < 3; i++ {
+			w := post(path)
+			attempts++
+			if attempts <= 5 && w.Code != http.StatusBadRequest {
+				t.Fatalf("attempt %d on %s: status %d, want 400", attempts, path, w.Code)
+			}
+			if attempts > 5 && (w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "") {
+				t.Fatalf("attempt %d on %s: status %d, want 429 with Retry-After", attempts, path, w.Code)
+			}
+		}
+	}
+	if len(history.history) != 5 || history.history[0].FailureReason != entity.LoginFailBadTwoFactor || history.history[0].Username != "alice" {
+		t.Fatalf("recorded failures = %+v", history.history)
+	}
+	if twoFactor.deleted {
+		t.Fatal("two-factor must not be disabled with a wrong code")
+	}
+}
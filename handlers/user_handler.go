@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ctwj/urldb/db/converter"
@@ -11,6 +13,7 @@ import (
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
+	"github.com/ctwj/urldb/services"
 	"github.com/ctwj/urldb/utils"
 
 	"github.com/gin-gonic/gin"
@@ -24,33 +27,80 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	clientIP, _ := c.Get("client_ip")
+	clientIP := c.ClientIP()
+	userAgent := c.Request.UserAgent()
 	utils.Info("Login - 尝试登录 - 用户名: %s, IP: %s", req.Username, clientIP)
 
+	security := services.GetDefaultLoginSecurityService()
+	if security != nil {
+		if err := security.CheckThrottle(req.Username, clientIP); err != nil {
+			var throttleErr *services.LoginThrottleError
+			if errors.As(err, &throttleErr) {
+				utils.Warn("Login - 登录已被限流 - 用户名: %s, IP: %s", req.Username, clientIP)
+				c.Header("Retry-After", strconv.Itoa(throttleErr.RetryAfter))
+				ErrorResponse(c, throttleErr.Error(), http.StatusTooManyRequests)
+				return
+			}
+			utils.Error("Login - 检查登录限流失败: %v", err)
+		}
+	}
+	recordFailure := func(userID uint, reason string) {
+		if security != nil {
+			security.RecordLogin(userID, req.Username, clientIP, userAgent, false, reason, false)
+		}
+	}
+
 	user, err := repoManager.UserRepository.FindByUsername(req.Username)
 	if err != nil {
 		utils.Warn("Login - 用户不存在或密码错误 - 用户名: %s, IP: %s", req.Username, clientIP)
+		recordFailure(0, entity.LoginFailUserNotFound)
 		ErrorResponse(c, "用户名或密码错误", http.StatusUnauthorized)
 		return
 	}
 
 	if !user.IsActive {
 		utils.Warn("Login - 账户已被禁用 - 用户名: %s, IP: %s", req.Username, clientIP)
+		recordFailure(user.ID, entity.LoginFailDisabled)
 		ErrorResponse(c, "账户已被禁用", http.StatusUnauthorized)
 		return
 	}
 
 	if !middleware.CheckPassword(req.Password, user.Password) {
 		utils.Warn("Login - 密码错误 - 用户名: %s, IP: %s", req.Username, clientIP)
+		recordFailure(user.ID, entity.LoginFailBadPassword)
 		ErrorResponse(c, "用户名或密码错误", http.StatusUnauthorized)
 		return
 	}
 
+	// 两步验证：未提交验证码时提示前端输入，不计为失败
+	twoFactor := false
+	if security != nil {
+		enabled, err := security.TwoFactorEnabled(user.ID)
+		if err != nil {
+			utils.Error("Login - 查询两步验证状态失败 - 用户名: %s, Error: %v", req.Username, err)
+			ErrorResponse(c, "登录失败", http.StatusInternalServerError)
+			return
+		}
+		if enabled {
+			if strings.TrimSpace(req.TwoFactorCode) == "" {
+				SuccessResponse(c, &dto.LoginResponse{TwoFactorRequired: true})
+				return
+			}
+			if err := security.VerifyTwoFactor(user.ID, req.TwoFactorCode); err != nil {
+				utils.Warn("Login - 两步验证失败 - 用户名: %s, IP: %s, Error: %v", req.Username, clientIP, err)
+				recordFailure(user.ID, entity.LoginFailBadTwoFactor)
+				ErrorResponse(c, services.ErrTwoFactorCodeInvalid.Error(), http.StatusUnauthorized)
+				return
+			}
+			twoFactor = true
+		}
+	}
+
 	// 更新最后登录时间
 	repoManager.UserRepository.UpdateLastLogin(user.ID)
 
-	// 生成JWT令牌
-	token, err := middleware.GenerateToken(user)
+	// 创建会话并签发令牌
+	response, err := issueLoginResponse(c, user)
 	if err != nil {
 		utils.Error("Login - 生成令牌失败 - 用户名: %s, IP: %s, Error: %v", req.Username, clientIP, err)
 		ErrorResponse(c, "生成令牌失败", http.StatusInternalServerError)
@@ -58,20 +108,19 @@ func Login(c *gin.Context) {
 	}
 
 	utils.Info("Login - 登录成功 - 用户名: %s(ID:%d), IP: %s", req.Username, user.ID, clientIP)
+	if security != nil {
+		security.RecordLogin(user.ID, user.Username, clientIP, userAgent, true, "", twoFactor)
+	}
 
-	// 触发用户登录事件
+	// 触发用户登录事件（需在登录记录写入之后，新IP告警依赖登录记录）
 	loginData := map[string]interface{}{
 		"ip":         clientIP,
-		"user_agent": c.GetHeader("User-Agent"),
+		"user_agent": userAgent,
 		"login_time": time.Now(),
+		"two_factor": twoFactor,
 	}
 	plugins.TriggerUserLogin(user, loginData)
 
-	response := dto.LoginResponse{
-		Token: token,
-		User:  converter.ToUserResponse(user),
-	}
-
 	SuccessResponse(c, response)
 }
 
@@ -153,6 +202,10 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
+	if req.Role != "" && !checkAssignableRole(c, req.Role) {
+		return
+	}
+
 	adminUsername, _ := c.Get("username")
 	clientIP, _ := c.Get("client_ip")
 	utils.Info("CreateUser - 管理员创建用户 - 管理员: %s, 新用户名: %s, IP: %s", adminUsername, req.Username, clientIP)
@@ -230,6 +283,13 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	if !checkManageableUser(c, user) || (req.Role != "" && req.Role != user.Role && !checkAssignableRole(c, req.Role)) {
+		return
+	}
+
+	wasActive, oldRole := user.IsActive, user.Role
+	before := *user
+
 	// 记录变更前的信息
 	oldInfo := fmt.Sprintf("用户名:%s,邮箱:%s,角色:%s,状态:%t", user.Username, user.Email, user.Role, user.IsActive)
 	utils.Debug("UpdateUser - 更新前用户信息 - 管理员: %s, 用户ID: %d, 信息: %s", adminUsername, id, oldInfo)
@@ -255,6 +315,14 @@ func UpdateUser(c *gin.Context) {
 	// 记录变更后信息
 	newInfo := fmt.Sprintf("用户名:%s,邮箱:%s,角色:%s,状态:%t", user.Username, user.Email, user.Role, user.IsActive)
 	utils.Info("UpdateUser - 用户更新成功 - 管理员: %s, 用户ID: %d, 更新前: %s, 更新后: %s, IP: %s", adminUsername, id, oldInfo, newInfo, clientIP)
+	middleware.SetAuditChange(c, before, user)
+
+	// 禁用或变更角色后已签发的令牌立即失效
+	if wasActive && !user.IsActive {
+		revokeUserSessions(user.ID, entity.SessionRevokeUserDisabled)
+	} else if oldRole != user.Role {
+		revokeUserSessions(user.ID, entity.SessionRevokeRoleChanged)
+	}
 
 	SuccessResponse(c, gin.H{"message": "用户更新成功"})
 }
@@ -284,6 +352,9 @@ func ChangePassword(c *gin.Context) {
 		ErrorResponse(c, "用户不存在", http.StatusNotFound)
 		return
 	}
+	if !checkManageableUser(c, user) {
+		return
+	}
 
 	// 哈希新密码
 	hashedPassword, err := middleware.HashPassword(req.NewPassword)
@@ -302,6 +373,7 @@ func ChangePassword(c *gin.Context) {
 	}
 
 	utils.Info("ChangePassword - 密码修改成功 - 管理员: %s, 用户名: %s(ID:%d), IP: %s", adminUsername, user.Username, id, clientIP)
+	revokeUserSessions(user.ID, entity.SessionRevokePassword)
 
 	SuccessResponse(c, gin.H{"message": "密码修改成功"})
 }
@@ -326,6 +398,9 @@ func DeleteUser(c *gin.Context) {
 		ErrorResponse(c, "用户不存在", http.StatusNotFound)
 		return
 	}
+	if !checkManageableUser(c, user) {
+		return
+	}
 
 	err = repoManager.UserRepository.Delete(uint(id))
 	if err != nil {
@@ -335,6 +410,12 @@ func DeleteUser(c *gin.Context) {
 	}
 
 	utils.Info("DeleteUser - 用户删除成功 - 管理员: %s, 用户名: %s(ID:%d), IP: %s", adminUsername, user.Username, id, clientIP)
+	revokeUserSessions(user.ID, entity.SessionRevokeUserDeleted)
+	if security := services.GetDefaultLoginSecurityService(); security != nil {
+		if err := security.ResetTwoFactor(user.ID); err != nil {
+			utils.Error("DeleteUser - 清理两步验证失败 - 用户ID: %d, Error: %v", user.ID, err)
+		}
+	}
 
 	SuccessResponse(c, gin.H{"message": "用户删除成功"})
 }
@@ -359,6 +440,7 @@ func GetProfile(c *gin.Context) {
 	}
 
 	response := converter.ToUserResponse(user)
+	response.Permissions = rolePermissions(user.Role)
 	utils.Debug("GetProfile - 成功获取个人资料 - 用户名: %s(ID:%d), IP: %s", username, userID, clientIP)
 	SuccessResponse(c, response)
 }
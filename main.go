@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/ctwj/urldb/cmd/cmdanalytics"
+	"github.com/ctwj/urldb/cmd/cmdbackup"
+	"github.com/ctwj/urldb/cmd/cmdcatalog"
 	"github.com/ctwj/urldb/cmd/cmdplugin"
 	"github.com/ctwj/urldb/config"
 	"github.com/ctwj/urldb/db"
@@ -18,7 +23,13 @@ import (
 	"github.com/ctwj/urldb/handlers"
 	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/monitor"
+	"github.com/ctwj/urldb/pkg/backup"
+	"github.com/ctwj/urldb/pkg/cache"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/pkg/leader"
 	"github.com/ctwj/urldb/plugin-system/manager/plugin"
+	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
 	"github.com/ctwj/urldb/routes"
 	"github.com/ctwj/urldb/scheduler"
 	"github.com/ctwj/urldb/services"
@@ -54,6 +65,36 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "analytics":
+			// 处理统计日汇总命令
+			cmdanalytics.InitAnalyticsCommands()
+			rootCmd := &cobra.Command{Use: "urldb"}
+			rootCmd.AddCommand(cmdanalytics.GetAnalyticsCmd())
+			if err := rootCmd.Execute(); err != nil {
+				utils.Error("统计命令执行失败: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "catalog":
+			// 处理资源目录导出/导入命令
+			cmdcatalog.InitCatalogCommands()
+			rootCmd := &cobra.Command{Use: "urldb"}
+			rootCmd.AddCommand(cmdcatalog.GetCatalogCmd())
+			if err := rootCmd.Execute(); err != nil {
+				utils.Error("资源目录命令执行失败: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "backup":
+			// 处理备份与恢复命令
+			cmdbackup.InitBackupCommands()
+			rootCmd := &cobra.Command{Use: "urldb"}
+			rootCmd.AddCommand(cmdbackup.GetBackupCmd())
+			if err := rootCmd.Execute(); err != nil {
+				utils.Error("备份命令执行失败: %v", err)
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
@@ -99,6 +140,34 @@ func main() {
 		}
 	}
 
+	// 初始化两级缓存，配置 CACHE_REDIS_ADDR 时启用 Redis 共享层并在副本间同步失效
+	cacheConfig := cache.Config{
+		OnError: func(op string, err error) { utils.Warn("缓存Redis层%s失败: %v", op, err) },
+	}
+	if size, err := strconv.Atoi(os.Getenv("CACHE_LOCAL_SIZE")); err == nil && size > 0 {
+		cacheConfig.LocalSize = size
+	}
+	if addr := os.Getenv("CACHE_REDIS_ADDR"); addr != "" {
+		redisDB, _ := strconv.Atoi(os.Getenv("CACHE_REDIS_DB"))
+		remote, err := cache.NewRedisRemote(cache.RedisConfig{
+			Addr:     addr,
+			Password: os.Getenv("CACHE_REDIS_PASSWORD"),
+			DB:       redisDB,
+			Prefix:   os.Getenv("CACHE_REDIS_PREFIX"),
+		})
+		if err != nil {
+			utils.Warn("连接缓存Redis失败，仅使用进程内缓存: %v", err)
+		} else {
+			cacheConfig.Remote = remote
+			// 漏收失效通知的副本最多一分钟后与共享层一致
+			cacheConfig.LocalMaxTTL = time.Minute
+			utils.Info("缓存Redis共享层已启用: %s", addr)
+		}
+	}
+	appCache := cache.New(cacheConfig)
+	cache.SetDefault(appCache)
+	defer appCache.Close()
+
 	// 初始化数据库
 	if err := db.InitDB(); err != nil {
 		utils.Fatal("数据库连接失败: %v", err)
@@ -109,6 +178,19 @@ func main() {
 	// 创建Repository管理器
 	repoManager := repo.NewRepositoryManager(db.DB)
 
+	// 统一的定时任务调度器：调度任务、插件定时任务与Telegram推送都注册在这里，
+	// 执行记录、暂停状态与表达式覆盖保存在数据库中，需在插件系统与调度器之前创建
+	jobs := newJobScheduler(repoManager)
+	cronjob.SetDefault(jobs)
+	defer jobs.Close()
+
+	// 多副本选主：每个调度任务、插件定时任务与Telegram长轮询只在持有锁的节点运行，节点下线后由其他节点接管。
+	// LEADER_ELECTION 可选 postgres（默认，advisory lock）、redis（租约锁）、none（单实例，不选主）
+	if elector := newLeaderElector(); elector != nil {
+		scheduler.SetGlobalElector(elector)
+		defer elector.Close()
+	}
+
 	// 创建配置管理器
 	configManager := config.NewConfigManager(repoManager)
 
@@ -148,6 +230,17 @@ func main() {
 	expansionProcessor := task.NewExpansionProcessor(repoManager)
 	taskManager.RegisterProcessor(expansionProcessor)
 
+	// 注册资源目录导出/导入任务处理器
+	catalogService := services.NewCatalogService(
+		repoManager.ResourceRepository,
+		repoManager.CategoryRepository,
+		repoManager.TagRepository,
+		repoManager.PanRepository,
+		repoManager.ResourceMetadataRepository,
+	)
+	taskManager.RegisterProcessor(task.NewCatalogExportProcessor(catalogService))
+	taskManager.RegisterProcessor(task.NewCatalogImportProcessor(catalogService))
+
 	// 初始化Meilisearch管理器
 	meilisearchManager := services.NewMeilisearchManager(repoManager)
 	if err := meilisearchManager.Initialize(); err != nil {
@@ -199,6 +292,7 @@ func main() {
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
 	r.Use(cors.New(config))
+	r.Use(middleware.AuditTrail()) // 后台写操作审计
 
 	// 将Repository管理器注入到handlers中
 	handlers.SetRepositoryManager(repoManager)
@@ -239,6 +333,115 @@ func main() {
 	// 设置全局调度器的Meilisearch管理器
 	scheduler.SetGlobalMeilisearchManager(meilisearchManager)
 
+	// 黑名单在入库、搜索、机器人与频道推送中统一生效，需在调度器与机器人启动前初始化
+	blocklistService := services.NewBlocklistService(repoManager.BlocklistRuleRepository)
+	services.SetDefaultBlocklistService(blocklistService)
+
+	// 创建内容源采集服务并交给调度器
+	contentSourceService := services.NewContentSourceService(
+		repoManager.ContentSourceRepository,
+		repoManager.ReadyResourceRepository,
+		repoManager.ResourceRepository,
+	)
+	scheduler.SetGlobalContentSourceService(contentSourceService)
+
+	// 创建待处理资源流水线服务并交给调度器
+	readyResourcePipelineService := services.NewReadyResourcePipelineService(
+		repoManager.ReadyResourcePipelineRepository,
+		repoManager.ReadyResourceStageLogRepository,
+	)
+	scheduler.SetGlobalReadyResourcePipelineService(readyResourcePipelineService)
+
+	// 创建自动分类服务并交给调度器
+	classificationService := services.NewClassificationService(
+		repoManager.ClassificationRuleRepository,
+		repoManager.ClassificationReviewRepository,
+		repoManager.ClassifierModelRepository,
+		repoManager.ResourceRepository,
+		repoManager.CategoryRepository,
+		repoManager.TagRepository,
+		repoManager.CksRepository,
+	)
+	scheduler.SetGlobalClassificationService(classificationService)
+
+	// 创建影视元数据服务（豆瓣/TMDB），供调度器、搜索筛选、OG图片与推送使用
+	metadataService := services.NewMetadataService(
+		repoManager.ResourceMetadataRepository,
+		repoManager.ResourceRepository,
+		repoManager.SystemConfigRepository,
+	)
+	scheduler.SetGlobalMetadataService(metadataService)
+	handlers.SetMetadataService(metadataService)
+
+	// 创建搜索引擎URL提交服务（Google/Bing/IndexNow/百度）
+	searchEngineSubmitService := services.NewSearchEngineSubmitService(
+		repoManager.SearchEngineSubmissionRepository,
+		repoManager.SystemConfigRepository,
+	)
+	scheduler.SetGlobalSearchEngineSubmitService(searchEngineSubmitService)
+
+	// 创建Google索引覆盖率服务
+	googleIndexCoverageService := services.NewGoogleIndexCoverageService(
+		repoManager.GoogleIndexStatusRepository,
+		repoManager.SystemConfigRepository,
+	)
+	scheduler.SetGlobalGoogleIndexCoverageService(googleIndexCoverageService)
+
+	// 创建资源页SEO数据服务
+	seoService := services.NewSEOService(
+		repoManager.ResourceRepository,
+		repoManager.HotDramaRepository,
+		repoManager.SystemConfigRepository,
+		metadataService,
+	)
+
+	// 创建OG图片服务（模板、磁盘缓存与预生成）
+	ogUploadDir := os.Getenv("UPLOAD_DIR")
+	if ogUploadDir == "" {
+		ogUploadDir = "./uploads"
+	}
+	ogImageService := services.NewOGImageService(
+		repoManager.ResourceRepository,
+		repoManager.SystemConfigRepository,
+		metadataService,
+		"./data/og-cache",
+		ogUploadDir,
+	)
+	scheduler.SetGlobalOGImageService(ogImageService)
+	handlers.SetOGImageService(ogImageService)
+
+	// 创建统计日汇总服务（趋势与排行读取日汇总表，原始记录按保留天数清理）
+	analyticsService := services.NewAnalyticsService(repoManager.AnalyticsRepository, repoManager.SystemConfigRepository)
+	scheduler.SetGlobalAnalyticsService(analyticsService)
+
+	// 创建备份服务（存储由 BACKUP_STORAGE 等环境变量配置），启动时从存储读取备份状态
+	var backupService *services.BackupService
+	if backupStorage, err := services.NewBackupStorageFromEnv(); err != nil {
+		utils.Error("初始化备份存储失败，备份功能不可用: %v", err)
+	} else {
+		backupService = services.NewBackupService(backupStorage, backup.NewPostgres(db.DSN()), repoManager.SystemConfigRepository, services.DefaultBackupSources())
+		scheduler.SetGlobalBackupService(backupService)
+		services.SetDefaultBackupService(backupService)
+		go func() {
+			if err := backupService.RefreshStatus(context.Background()); err != nil {
+				utils.Warn("读取备份状态失败: %v", err)
+			}
+		}()
+	}
+
+	// 多副本部署时通过 PostgreSQL LISTEN/NOTIFY 转发管理后台实时事件
+	if os.Getenv("EVENTS_PG_BRIDGE") == "true" {
+		channel := os.Getenv("EVENTS_PG_CHANNEL")
+		if channel == "" {
+			channel = "urldb_events"
+		}
+		eventBridge := eventbus.NewPGBridge(db.DSN(), channel, eventbus.Default)
+		eventBridge.OnError = func(err error) { utils.Warn("%v", err) }
+		eventBridge.Start()
+		defer eventBridge.Stop()
+		utils.Info("实时事件跨副本桥接已启用，通道: %s", channel)
+	}
+
 	// 初始化并启动调度器
 	globalScheduler := scheduler.GetGlobalScheduler(
 		repoManager.HotDramaRepository,
@@ -283,6 +486,27 @@ func main() {
 		utils.Info("系统配置禁用Sitemap自动生成功能")
 	}
 
+	// 启动内容源采集调度器（各内容源按自身间隔抓取，禁用的内容源不会被抓取）
+	globalScheduler.StartContentSourceScheduler()
+
+	// 启动影视元数据调度器（未启用时每轮直接跳过）
+	globalScheduler.StartMetadataScheduler()
+
+	// 启动搜索引擎URL提交调度器（未启用自动提交时每轮直接跳过）
+	globalScheduler.StartSearchEngineSubmitScheduler()
+
+	// 启动OG图片预生成调度器（未启用预生成时每轮直接跳过）
+	globalScheduler.StartOGImagePrerenderScheduler()
+
+	// 启动统计日汇总调度器（未启用汇总时每轮直接跳过）
+	globalScheduler.StartAnalyticsRollupScheduler()
+
+	// 启动定时备份调度器（未启用定时备份时每轮直接跳过）
+	globalScheduler.StartBackupScheduler()
+
+	// 启动缓存清理任务（清理本节点的本地缓存层，每个副本各自执行）
+	globalScheduler.StartCacheCleaner()
+
 	// Google索引调度器现在由Sitemap调度器管理，不再独立启动
 	if autoGoogleIndexEnabled {
 		utils.Info("系统配置启用Google索引自动提交功能，将由Sitemap调度器管理")
@@ -295,11 +519,52 @@ func main() {
 	// 设置公开API中间件的Repository管理器
 	middleware.SetRepositoryManager(repoManager)
 
+	// JWT签名密钥与登录会话：访问令牌短期有效，刷新令牌服务端保存并可吊销
+	if err := middleware.InitJWTFromEnv(repoManager.SystemConfigRepository); err != nil {
+		utils.Fatal("初始化JWT密钥失败: %v", err)
+	}
+	authSessionService := services.NewAuthSessionService(repoManager.UserRepository, repoManager.UserSessionRepository)
+	if ttl := os.Getenv("JWT_REFRESH_TTL"); ttl != "" {
+		if d, err := time.ParseDuration(ttl); err == nil && d > 0 {
+			authSessionService.SetRefreshTokenTTL(d)
+		} else {
+			utils.Warn("JWT_REFRESH_TTL 格式错误，使用默认值: %s", ttl)
+		}
+	}
+	services.SetDefaultAuthSessionService(authSessionService)
+	middleware.SetSessionValidator(authSessionService)
+
+	// 登录保护：失败限流、登录记录、两步验证，管理员新IP登录时通过Telegram告警
+	loginSecurityService := services.NewLoginSecurityService(repoManager.LoginHistoryRepository, repoManager.UserTwoFactorRepository, repoManager.SystemConfigRepository)
+	services.SetDefaultLoginSecurityService(loginSecurityService)
+	plugins.OnUserLogin(loginSecurityService.HandleUserLogin)
+
+	// 角色与权限：同步权限定义、创建内置角色并加载权限缓存
+	rbacService := services.NewRBACService(repoManager.RoleRepository, repoManager.PermissionRepository, repoManager.PermissionDenialRepository)
+	if err := rbacService.EnsureDefaults(); err != nil {
+		utils.Error("初始化角色与权限失败，非管理员角色将无法访问管理接口: %v", err)
+	}
+	services.SetDefaultRBACService(rbacService)
+	middleware.SetPermissionChecker(rbacService)
+	roleHandler := handlers.NewRoleHandler(rbacService)
+
+	// 公开API密钥：认证、作用域、限流与配额
+	apiKeyService := services.NewAPIKeyService(repoManager.APIKeyRepository, repoManager.SystemConfigRepository)
+	middleware.SetAPIKeyAuthenticator(apiKeyService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+
+	// 操作审计：记录通过权限校验的后台写操作
+	auditLogService := services.NewAuditLogService(repoManager.AuditLogRepository, repoManager.SystemConfigRepository)
+	services.SetDefaultAuditLogService(auditLogService)
+	middleware.SetAuditRecorder(auditLogService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
+
 	// 创建公开API处理器
 	publicAPIHandler := handlers.NewPublicAPIHandler()
 
 	// 创建任务处理器
 	taskHandler := handlers.NewTaskHandler(repoManager, taskManager)
+	catalogHandler := handlers.NewCatalogHandler(repoManager, taskManager)
 
 	// 创建文件处理器
 	fileHandler := handlers.NewFileHandler(repoManager.FileRepository, repoManager.SystemConfigRepository, repoManager.UserRepository)
@@ -308,12 +573,52 @@ func main() {
 	meilisearchHandler := handlers.NewMeilisearchHandler(meilisearchManager)
 
 	// 创建OG图片处理器
-	ogImageHandler := handlers.NewOGImageHandler()
+	ogImageHandler := handlers.NewOGImageHandler(ogImageService)
+
+	// 创建统计日汇总处理器
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+
+	// 创建备份管理处理器
+	var backupHandler *handlers.BackupHandler
+	if backupService != nil {
+		backupHandler = handlers.NewBackupHandler(backupService)
+	}
+
+	// 创建实时事件处理器
+	eventHandler := handlers.NewEventHandler(eventbus.Default, repoManager.UserRepository)
+
+	// 举报与版权申述处置：下架资源、移出索引、删除转存文件、加入黑名单禁止重新入库，均可撤销
+	moderationService := services.NewModerationService(
+		repoManager.ReportRepository,
+		repoManager.CopyrightClaimRepository,
+		repoManager.ResourceRepository,
+		repoManager.ModerationActionRepository,
+		blocklistService,
+		repoManager.SystemConfigRepository,
+	)
+	moderationService.SetIndex(meilisearchManager)
+	moderationService.SetFileDeleter(services.NewCleanupService(repoManager.ResourceRepository, repoManager.SystemConfigRepository, repoManager.CksRepository, repoManager.PanRepository))
+	if mailer := services.NewSMTPMailerFromEnv(); mailer != nil {
+		moderationService.SetMailer(mailer)
+	}
+	services.SetDefaultModerationService(moderationService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	blocklistHandler := handlers.NewBlocklistHandler(blocklistService)
+	jobHandler := handlers.NewJobHandler(jobs, repoManager.ScheduledJobRepository)
 
 	// 创建举报和版权申述处理器
 	reportHandler := handlers.NewReportHandler(repoManager.ReportRepository, repoManager.ResourceRepository)
 	copyrightClaimHandler := handlers.NewCopyrightClaimHandler(repoManager.CopyrightClaimRepository, repoManager.ResourceRepository)
 
+	// 创建内容源处理器
+	contentSourceHandler := handlers.NewContentSourceHandler(repoManager.ContentSourceRepository, contentSourceService)
+	readyResourcePipelineHandler := handlers.NewReadyResourcePipelineHandler(readyResourcePipelineService, repoManager.ReadyResourceStageLogRepository)
+	classificationHandler := handlers.NewClassificationHandler(classificationService, repoManager.ClassificationRuleRepository, repoManager.ClassificationReviewRepository)
+	metadataHandler := handlers.NewMetadataHandler(metadataService)
+	searchEngineHandler := handlers.NewSearchEngineHandler(searchEngineSubmitService)
+	googleIndexCoverageHandler := handlers.NewGoogleIndexCoverageHandler(googleIndexCoverageService)
+	seoHandler := handlers.NewSEOHandler(seoService)
+
 	// 创建Google索引任务处理器
 	googleIndexProcessor := task.NewGoogleIndexProcessor(repoManager)
 
@@ -339,27 +644,37 @@ func main() {
 	{
 		// 公开API路由（需要API Token认证）
 		publicAPI := api.Group("/public")
-		publicAPI.Use(middleware.PublicAPIAuth())
 		{
 			// 批量添加资源
-			publicAPI.POST("/resources/batch-add", publicAPIHandler.AddBatchResources)
+			publicAPI.POST("/resources/batch-add", middleware.PublicAPIAuth(entity.APIScopeBatchAdd), publicAPIHandler.AddBatchResources)
 			// 资源搜索
-			publicAPI.GET("/resources/search", publicAPIHandler.SearchResources)
+			publicAPI.GET("/resources/search", middleware.PublicAPIAuth(entity.APIScopeSearch), publicAPIHandler.SearchResources)
 			// 热门剧
-			publicAPI.GET("/hot-dramas", publicAPIHandler.GetHotDramas)
+			publicAPI.GET("/hot-dramas", middleware.PublicAPIAuth(entity.APIScopeHotDramas), publicAPIHandler.GetHotDramas)
 		}
 
 		// 认证路由
 		api.POST("/auth/login", handlers.Login)
 		api.POST("/auth/register", handlers.Register)
 		api.GET("/auth/profile", middleware.AuthMiddleware(), handlers.GetProfile)
+		api.POST("/auth/refresh", handlers.RefreshToken)
+		api.POST("/auth/logout", middleware.AuthMiddleware(), handlers.Logout)
+		api.GET("/auth/sessions", middleware.AuthMiddleware(), handlers.ListMySessions)
+		api.DELETE("/auth/sessions", middleware.AuthMiddleware(), handlers.RevokeMyOtherSessions)
+		api.DELETE("/auth/sessions/:session_id", middleware.AuthMiddleware(), handlers.RevokeMySession)
+		api.GET("/auth/2fa", middleware.AuthMiddleware(), handlers.GetTwoFactorStatus)
+		api.POST("/auth/2fa/setup", middleware.AuthMiddleware(), handlers.SetupTwoFactor)
+		api.POST("/auth/2fa/enable", middleware.AuthMiddleware(), handlers.EnableTwoFactor)
+		api.POST("/auth/2fa/disable", middleware.AuthMiddleware(), handlers.DisableTwoFactor)
+		api.POST("/auth/2fa/recovery-codes", middleware.AuthMiddleware(), handlers.RegenerateRecoveryCodes)
+		api.GET("/auth/login-history", middleware.AuthMiddleware(), handlers.ListMyLoginHistory)
 
 		// 资源管理
 		api.GET("/resources", handlers.GetResources)
 		api.GET("/resources/hot", handlers.GetHotResources)
-		api.POST("/resources", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateResource)
-		api.PUT("/resources/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateResource)
-		api.DELETE("/resources/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteResource)
+		api.POST("/resources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionResourceManage), handlers.CreateResource)
+		api.PUT("/resources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionResourceManage), handlers.UpdateResource)
+		api.DELETE("/resources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionResourceManage), handlers.DeleteResource)
 		api.GET("/resources/:id", handlers.GetResourceByID)
 		api.GET("/resources/key/:key", handlers.GetResourcesByKey)
 		api.GET("/resources/check-exists", handlers.CheckResourceExists)
@@ -368,13 +683,13 @@ func main() {
 		api.GET("/resources/:id/link", handlers.GetResourceLink)
 		api.GET("/resources/:id/validity", handlers.CheckResourceValidity)
 		api.POST("/resources/validity/batch", handlers.BatchCheckResourceValidity)
-		api.DELETE("/resources/batch", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.BatchDeleteResources)
+		api.DELETE("/resources/batch", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionResourceManage), handlers.BatchDeleteResources)
 
 		// 分类管理
 		api.GET("/categories", handlers.GetCategories)
-		api.POST("/categories", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateCategory)
-		api.PUT("/categories/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateCategory)
-		api.DELETE("/categories/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteCategory)
+		api.POST("/categories", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.CreateCategory)
+		api.PUT("/categories/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.UpdateCategory)
+		api.DELETE("/categories/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.DeleteCategory)
 
 		// 搜索
 		api.GET("/search", handlers.SearchResources)
@@ -385,54 +700,148 @@ func main() {
 		api.GET("/stats/views-trend", handlers.GetViewsTrend)
 		api.GET("/stats/searches-trend", handlers.GetSearchesTrend)
 		api.GET("/stats/invalid-trend", handlers.GetInvalidTrend)
-		api.GET("/stats/summary", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSummary)
+		api.GET("/stats/summary", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionStatsView), handlers.GetSummary)
+
+		// 统计日汇总：趋势、排行与汇总管理
+		api.GET("/analytics/trend", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionStatsView), analyticsHandler.GetTrend)
+		api.GET("/analytics/leaderboard", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionStatsView), analyticsHandler.GetLeaderboard)
+		api.GET("/analytics/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionStatsView), analyticsHandler.GetStatus)
+		api.PUT("/analytics/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAnalyticsManage), analyticsHandler.UpdateConfig)
+		api.POST("/analytics/rollup", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAnalyticsManage), analyticsHandler.Rollup)
+		api.POST("/analytics/backfill", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAnalyticsManage), analyticsHandler.Backfill)
+
+		// 备份管理（恢复只能通过命令行执行）
+		if backupHandler != nil {
+			api.GET("/backups", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBackupManage), backupHandler.List)
+			api.POST("/backups", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBackupManage), backupHandler.Run)
+			api.GET("/backups/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBackupManage), backupHandler.GetConfig)
+			api.PUT("/backups/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBackupManage), backupHandler.UpdateConfig)
+			api.POST("/backups/:name/verify", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBackupManage), backupHandler.Verify)
+		}
+
+		// 管理后台实时事件（SSE）
+		api.GET("/events/stream", middleware.QueryTokenMiddleware(), middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionEventSubscribe), eventHandler.Stream)
+		api.GET("/events/types", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionEventSubscribe), eventHandler.GetTypes)
+		api.GET("/events/subscriptions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionEventSubscribe), eventHandler.GetSubscriptions)
+		api.PUT("/events/subscriptions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionEventSubscribe), eventHandler.UpdateSubscriptions)
 		api.GET("/system/info", handlers.GetSystemInfo)
 
 		// 平台管理
 		api.GET("/pans", handlers.GetPans)
-		api.POST("/pans", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreatePan)
-		api.PUT("/pans/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdatePan)
-		api.DELETE("/pans/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeletePan)
-		api.GET("/pans/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetPan)
+		api.POST("/pans", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionPanManage), handlers.CreatePan)
+		api.PUT("/pans/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionPanManage), handlers.UpdatePan)
+		api.DELETE("/pans/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionPanManage), handlers.DeletePan)
+		api.GET("/pans/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionPanManage), handlers.GetPan)
 
 		// Cookie管理
 		api.GET("/cks", handlers.GetCks)
-		api.POST("/cks", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateCks)
-		api.PUT("/cks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateCks)
-		api.DELETE("/cks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteCks)
-		api.GET("/cks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetCksByID)
-		api.POST("/cks/:id/refresh-capacity", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.RefreshCapacity)
-		api.POST("/cks/:id/delete-related-resources", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteRelatedResources)
+		api.POST("/cks", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.CreateCks)
+		api.PUT("/cks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.UpdateCks)
+		api.DELETE("/cks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.DeleteCks)
+		api.GET("/cks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.GetCksByID)
+		api.POST("/cks/:id/refresh-capacity", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.RefreshCapacity)
+		api.POST("/cks/:id/delete-related-resources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionCksManage), handlers.DeleteRelatedResources)
 
 		// 标签管理
 		api.GET("/tags", handlers.GetTags)
-		api.POST("/tags", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateTag)
-		api.PUT("/tags/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateTag)
-		api.DELETE("/tags/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteTag)
+		api.POST("/tags", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.CreateTag)
+		api.PUT("/tags/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.UpdateTag)
+		api.DELETE("/tags/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaxonomyManage), handlers.DeleteTag)
 		api.GET("/tags/:id", handlers.GetTagByID)
 		api.GET("/categories/:categoryId/tags", handlers.GetTagsByCategory)
 
 		// 待处理资源管理
-		api.GET("/ready-resources", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetReadyResources)
-		api.POST("/ready-resources/batch", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.BatchCreateReadyResources)
-		api.POST("/ready-resources/text", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateReadyResourcesFromText)
-		api.DELETE("/ready-resources/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteReadyResource)
-		api.DELETE("/ready-resources", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ClearReadyResources)
-		api.GET("/ready-resources/key/:key", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetReadyResourcesByKey)
-		api.DELETE("/ready-resources/key/:key", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteReadyResourcesByKey)
-		api.GET("/ready-resources/errors", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetReadyResourcesWithErrors)
-		api.POST("/ready-resources/:id/clear-error", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ClearErrorMsg)
-		api.POST("/ready-resources/retry-failed", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.RetryFailedResources)
-		api.POST("/ready-resources/batch-restore", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.BatchRestoreToReadyPool)
-		api.POST("/ready-resources/batch-restore-by-query", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.BatchRestoreToReadyPoolByQuery)
-		api.POST("/ready-resources/clear-all-errors-by-query", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ClearAllErrorsByQuery)
-
-		// 用户管理（仅管理员）
-		api.GET("/users", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetUsers)
-		api.POST("/users", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateUser)
-		api.PUT("/users/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateUser)
-		api.PUT("/users/:id/password", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ChangePassword)
-		api.DELETE("/users/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteUser)
+		api.GET("/ready-resources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), handlers.GetReadyResources)
+		api.POST("/ready-resources/batch", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.BatchCreateReadyResources)
+		api.POST("/ready-resources/text", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.CreateReadyResourcesFromText)
+		api.DELETE("/ready-resources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.DeleteReadyResource)
+		api.DELETE("/ready-resources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.ClearReadyResources)
+		api.GET("/ready-resources/key/:key", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), handlers.GetReadyResourcesByKey)
+		api.DELETE("/ready-resources/key/:key", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.DeleteReadyResourcesByKey)
+		api.GET("/ready-resources/errors", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), handlers.GetReadyResourcesWithErrors)
+		api.POST("/ready-resources/:id/clear-error", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.ClearErrorMsg)
+		api.POST("/ready-resources/retry-failed", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.RetryFailedResources)
+		api.POST("/ready-resources/batch-restore", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.BatchRestoreToReadyPool)
+		api.POST("/ready-resources/batch-restore-by-query", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.BatchRestoreToReadyPoolByQuery)
+		api.POST("/ready-resources/clear-all-errors-by-query", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), handlers.ClearAllErrorsByQuery)
+		api.GET("/ready-resources/stage-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), readyResourcePipelineHandler.ListStageLogs)
+		api.GET("/ready-resources/stage-stats", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), readyResourcePipelineHandler.GetStageStats)
+		api.GET("/ready-resources/:id/stage-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), readyResourcePipelineHandler.GetReadyResourceStageLogs)
+
+		// 待处理资源处理流水线
+		api.GET("/ready-resource-pipelines", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), readyResourcePipelineHandler.ListPipelines)
+		api.GET("/ready-resource-pipelines/:source", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceView), readyResourcePipelineHandler.GetPipeline)
+		api.PUT("/ready-resource-pipelines/:source", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), readyResourcePipelineHandler.SavePipeline)
+		api.DELETE("/ready-resource-pipelines/:source", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReadyResourceManage), readyResourcePipelineHandler.DeletePipeline)
+
+		// 自动分类与打标签
+		api.GET("/classification/rules", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.ListRules)
+		api.POST("/classification/rules", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.CreateRule)
+		api.PUT("/classification/rules/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.UpdateRule)
+		api.DELETE("/classification/rules/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.DeleteRule)
+		api.POST("/classification/test", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.TestClassify)
+		api.POST("/classification/resources/:id/classify", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.ClassifyResource)
+		api.POST("/classification/classify-uncategorized", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.ClassifyUncategorized)
+		api.GET("/classification/model", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.GetModel)
+		api.POST("/classification/model/train", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.TrainModel)
+		api.GET("/classification/reviews", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.ListReviews)
+		api.POST("/classification/reviews/:id/approve", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.ApproveReview)
+		api.POST("/classification/reviews/:id/reject", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionClassificationManage), classificationHandler.RejectReview)
+
+		// 影视元数据管理
+		api.GET("/metadata/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.GetConfig)
+		api.PUT("/metadata/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.UpdateConfig)
+		api.GET("/metadata/stats", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.GetStats)
+		api.GET("/metadata/search", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.Search)
+		api.POST("/metadata/run", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.RunBatch)
+		api.GET("/resources/:id/metadata", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.GetResourceMetadata)
+		api.PUT("/resources/:id/metadata", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.BindResourceMetadata)
+		api.DELETE("/resources/:id/metadata", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.UnbindResourceMetadata)
+		api.POST("/resources/:id/metadata/refresh", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionMetadataManage), metadataHandler.RefreshResourceMetadata)
+
+		// 内容源采集管理
+		api.GET("/content-sources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.ListContentSources)
+		api.POST("/content-sources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.CreateContentSource)
+		api.POST("/content-sources/preview", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.PreviewContentSource)
+		api.GET("/content-sources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.GetContentSource)
+		api.PUT("/content-sources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.UpdateContentSource)
+		api.DELETE("/content-sources/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.DeleteContentSource)
+		api.POST("/content-sources/:id/run", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.RunContentSource)
+		api.POST("/content-sources/:id/reset-cursor", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), contentSourceHandler.ResetContentSourceCursor)
+
+		// 用户管理
+		api.GET("/users", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.GetUsers)
+		api.POST("/users", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.CreateUser)
+		api.PUT("/users/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.UpdateUser)
+		api.PUT("/users/:id/password", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.ChangePassword)
+		api.DELETE("/users/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.DeleteUser)
+		api.GET("/users/:id/sessions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.ListUserSessions)
+		api.DELETE("/users/:id/sessions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.RevokeAllUserSessions)
+		api.DELETE("/users/:id/2fa", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.ResetUserTwoFactor)
+		api.GET("/login-history", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionUserManage), handlers.ListLoginHistory)
+
+		// 角色与权限管理
+		api.GET("/roles", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.ListRoles)
+		api.POST("/roles", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.CreateRole)
+		api.PUT("/roles/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.UpdateRole)
+		api.DELETE("/roles/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.DeleteRole)
+		api.GET("/permissions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.ListPermissions)
+		api.GET("/permission-denials", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.ListPermissionDenials)
+		api.DELETE("/permission-denials", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionRoleManage), roleHandler.CleanupPermissionDenials)
+
+		// 公开API密钥管理
+		api.GET("/api-keys", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.List)
+		api.POST("/api-keys", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.Issue)
+		api.PUT("/api-keys/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.Update)
+		api.POST("/api-keys/:id/rotate", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.Rotate)
+		api.POST("/api-keys/:id/revoke", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.Revoke)
+		api.GET("/api-keys/:id/usage", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAPIKeyManage), apiKeyHandler.Usage)
+
+		// 操作审计日志
+		api.GET("/audit-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAuditView), auditLogHandler.List)
+		api.GET("/audit-logs/export", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAuditView), auditLogHandler.Export)
+		api.GET("/audit-logs/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionAuditView), auditLogHandler.GetConfig)
+		api.PUT("/audit-logs/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), auditLogHandler.UpdateConfig)
 
 		// 搜索统计路由
 		api.GET("/search-stats", handlers.GetSearchStats)
@@ -446,44 +855,58 @@ func main() {
 		api.GET("/search-stats/source-distribution", handlers.GetSearchSourceDistribution)
 
 		// API访问日志路由
-		api.GET("/api-access-logs", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetAPIAccessLogs)
-		api.GET("/api-access-logs/summary", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetAPIAccessLogSummary)
-		api.GET("/api-access-logs/stats", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetAPIAccessLogStats)
-		api.DELETE("/api-access-logs", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ClearAPIAccessLogs)
+		api.GET("/api-access-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetAPIAccessLogs)
+		api.GET("/api-access-logs/summary", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetAPIAccessLogSummary)
+		api.GET("/api-access-logs/stats", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetAPIAccessLogStats)
+		api.DELETE("/api-access-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogManage), handlers.ClearAPIAccessLogs)
 
 		// 系统日志路由
-		api.GET("/system-logs", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSystemLogs)
-		api.GET("/system-logs/files", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSystemLogFiles)
-		api.GET("/system-logs/summary", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSystemLogSummary)
-		api.DELETE("/system-logs", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ClearSystemLogs)
+		api.GET("/system-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetSystemLogs)
+		api.GET("/system-logs/files", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetSystemLogFiles)
+		api.GET("/system-logs/summary", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogView), handlers.GetSystemLogSummary)
+		api.DELETE("/system-logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionLogManage), handlers.ClearSystemLogs)
 
 		// 系统配置路由
-		api.GET("/system/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSystemConfig)
-		api.POST("/system/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateSystemConfig)
-		api.GET("/system/config/status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetConfigStatus)
-		api.POST("/system/config/toggle-auto-process", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.ToggleAutoProcess)
+		api.GET("/system/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), handlers.GetSystemConfig)
+		api.POST("/system/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), handlers.UpdateSystemConfig)
+		api.GET("/system/config/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), handlers.GetConfigStatus)
+		api.POST("/system/config/toggle-auto-process", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), handlers.ToggleAutoProcess)
+		api.GET("/scheduler/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSystemConfigManage), handlers.GetSchedulerStatus)
 		api.GET("/public/system-config", handlers.GetPublicSystemConfig)
 		api.GET("/public/site-verification", handlers.GetPublicSiteVerificationCode) // 网站验证代码（公开访问）
 
 		// 热播剧管理路由（查询接口无需认证）
 		api.GET("/hot-dramas", handlers.GetHotDramaList)
 		api.GET("/hot-dramas/:id", handlers.GetHotDramaByID)
-		api.POST("/hot-dramas", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.CreateHotDrama)
-		api.PUT("/hot-dramas/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateHotDrama)
-		api.DELETE("/hot-dramas/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.DeleteHotDrama)
+		api.POST("/hot-dramas", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionHotDramaManage), handlers.CreateHotDrama)
+		api.PUT("/hot-dramas/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionHotDramaManage), handlers.UpdateHotDrama)
+		api.DELETE("/hot-dramas/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionHotDramaManage), handlers.DeleteHotDrama)
 		api.GET("/hot-dramas/poster", handlers.GetPosterImage)
 
 		// 任务管理路由
-		api.POST("/tasks/transfer", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.CreateBatchTransferTask)
-		api.POST("/tasks/expansion", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.CreateExpansionTask)
-		api.GET("/tasks/expansion/accounts", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.GetExpansionAccounts)
-		api.GET("/tasks", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.GetTasks)
-		api.GET("/tasks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.GetTaskStatus)
-		api.POST("/tasks/:id/start", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.StartTask)
-		api.POST("/tasks/:id/stop", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.StopTask)
-		api.POST("/tasks/:id/pause", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.PauseTask)
-		api.DELETE("/tasks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.DeleteTask)
-		api.GET("/tasks/:id/items", middleware.AuthMiddleware(), middleware.AdminMiddleware(), taskHandler.GetTaskItems)
+		api.POST("/tasks/transfer", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.CreateBatchTransferTask)
+		api.POST("/tasks/expansion", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.CreateExpansionTask)
+		api.GET("/tasks/expansion/accounts", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), taskHandler.GetExpansionAccounts)
+		// 导出文件包含未公开的资源，创建与下载还需资源管理权限
+		api.POST("/catalog/export", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), middleware.RequirePermission(entity.PermissionResourceManage), catalogHandler.CreateExport)
+		api.POST("/catalog/import", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), catalogHandler.CreateImport)
+		api.GET("/catalog/tasks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), catalogHandler.GetResult)
+		api.GET("/catalog/tasks/:id/download", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), middleware.RequirePermission(entity.PermissionResourceManage), catalogHandler.Download)
+		api.GET("/tasks", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), taskHandler.GetTasks)
+		api.GET("/tasks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), taskHandler.GetTaskStatus)
+		api.POST("/tasks/:id/start", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.StartTask)
+		api.POST("/tasks/:id/stop", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.StopTask)
+		api.POST("/tasks/:id/pause", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.PauseTask)
+		api.DELETE("/tasks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskManage), taskHandler.DeleteTask)
+		api.GET("/tasks/:id/items", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), taskHandler.GetTaskItems)
+
+		// 定时任务路由
+		api.GET("/jobs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), jobHandler.ListJobs)
+		api.GET("/jobs/:name/runs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionTaskView), jobHandler.ListRuns)
+		api.POST("/jobs/:name/trigger", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionJobManage), jobHandler.TriggerJob)
+		api.POST("/jobs/:name/pause", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionJobManage), jobHandler.PauseJob)
+		api.POST("/jobs/:name/resume", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionJobManage), jobHandler.ResumeJob)
+		api.PUT("/jobs/:name/schedule", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionJobManage), jobHandler.UpdateSchedule)
 
 		// 版本管理路由
 		api.GET("/version", handlers.GetVersion)
@@ -492,23 +915,23 @@ func main() {
 		api.GET("/version/check-update", handlers.CheckUpdate)
 
 		// Meilisearch管理路由
-		api.GET("/meilisearch/status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetStatus)
-		api.GET("/meilisearch/unsynced-count", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetUnsyncedCount)
-		api.GET("/meilisearch/unsynced", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetUnsyncedResources)
-		api.GET("/meilisearch/synced", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetSyncedResources)
-		api.GET("/meilisearch/resources", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetAllResources)
-		api.POST("/meilisearch/sync-all", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.SyncAllResources)
-		api.GET("/meilisearch/sync-progress", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.GetSyncProgress)
-		api.POST("/meilisearch/stop-sync", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.StopSync)
-		api.POST("/meilisearch/clear-index", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.ClearIndex)
-		api.POST("/meilisearch/test-connection", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.TestConnection)
-		api.POST("/meilisearch/update-settings", middleware.AuthMiddleware(), middleware.AdminMiddleware(), meilisearchHandler.UpdateIndexSettings)
-
-		// 文件上传相关路由
-		api.POST("/files/upload", middleware.AuthMiddleware(), fileHandler.UploadFile)
-		api.GET("/files", middleware.AuthMiddleware(), fileHandler.GetFileList)
-		api.DELETE("/files", middleware.AuthMiddleware(), fileHandler.DeleteFiles)
-		api.PUT("/files", middleware.AuthMiddleware(), fileHandler.UpdateFile)
+		api.GET("/meilisearch/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetStatus)
+		api.GET("/meilisearch/unsynced-count", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetUnsyncedCount)
+		api.GET("/meilisearch/unsynced", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetUnsyncedResources)
+		api.GET("/meilisearch/synced", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetSyncedResources)
+		api.GET("/meilisearch/resources", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetAllResources)
+		api.POST("/meilisearch/sync-all", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexManage), meilisearchHandler.SyncAllResources)
+		api.GET("/meilisearch/sync-progress", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexView), meilisearchHandler.GetSyncProgress)
+		api.POST("/meilisearch/stop-sync", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexManage), meilisearchHandler.StopSync)
+		api.POST("/meilisearch/clear-index", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexManage), meilisearchHandler.ClearIndex)
+		api.POST("/meilisearch/test-connection", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexManage), meilisearchHandler.TestConnection)
+		api.POST("/meilisearch/update-settings", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSearchIndexManage), meilisearchHandler.UpdateIndexSettings)
+
+		// 文件管理：需上传权限，只能操作自己的文件；拥有 file:manage 时可操作所有用户的文件
+		api.POST("/files/upload", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionFileUpload), fileHandler.UploadFile)
+		api.GET("/files", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionFileUpload), fileHandler.GetFileList)
+		api.DELETE("/files", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionFileUpload), fileHandler.DeleteFiles)
+		api.PUT("/files", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionFileUpload), fileHandler.UpdateFile)
 		// 微信公众号验证文件上传（无需认证，仅支持TXT文件）
 		api.POST("/wechat/verify-file", fileHandler.UploadWechatVerifyFile)
 
@@ -521,6 +944,12 @@ func main() {
 			linkCheckService,
 			meilisearchManager,
 		)
+		// Telegram 频道采集器（channel_post 与导出历史共用）
+		telegramChannelImporter := services.NewTelegramChannelImporter(
+			repoManager.TelegramImportChannelRepository,
+			repoManager.ReadyResourceRepository,
+			repoManager.ResourceRepository,
+		)
 		telegramBotService := services.NewTelegramBotService(
 			repoManager.SystemConfigRepository,
 			repoManager.TelegramChannelRepository,
@@ -532,12 +961,18 @@ func main() {
 			resourceLinkService,
 			repoManager.SearchStatRepository,
 			repoManager.ResourceViewRepository,
+			telegramChannelImporter,
+			metadataService,
 		)
 
-		// 启动Telegram Bot服务
+		// 启动Telegram Bot服务（长轮询与频道推送参与选主）
+		telegramBotService.SetLeaderElector(scheduler.GetGlobalElector())
+		scheduler.SetGlobalTelegramBotService(telegramBotService)
 		if err := telegramBotService.Start(); err != nil {
 			utils.Error("启动Telegram Bot服务失败: %v", err)
 		}
+		loginSecurityService.SetNotifier(telegramBotService)
+		moderationService.SetNotifier(telegramBotService)
 
 		// 创建微信公众号机器人服务
 		wechatBotService := services.NewWechatBotService(
@@ -558,65 +993,100 @@ func main() {
 			repoManager.SystemConfigRepository,
 			telegramBotService,
 		)
-		api.GET("/telegram/bot-config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.GetBotConfig)
-		api.PUT("/telegram/bot-config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.UpdateBotConfig)
-		api.POST("/telegram/validate-api-key", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.ValidateApiKey)
-		api.GET("/telegram/bot-status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.GetBotStatus)
-		api.POST("/telegram/reload-config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.ReloadBotConfig)
-		api.POST("/telegram/test-message", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.TestBotMessage)
-		api.GET("/telegram/debug-connection", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.DebugBotConnection)
-		api.GET("/telegram/channels", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.GetChannels)
-		api.POST("/telegram/channels", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.CreateChannel)
-		api.PUT("/telegram/channels/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.UpdateChannel)
-		api.DELETE("/telegram/channels/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.DeleteChannel)
-		api.GET("/telegram/logs", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.GetTelegramLogs)
-		api.GET("/telegram/logs/stats", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.GetTelegramLogStats)
-		api.POST("/telegram/logs/clear", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.ClearTelegramLogs)
+		api.GET("/telegram/bot-config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.GetBotConfig)
+		api.PUT("/telegram/bot-config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.UpdateBotConfig)
+		api.POST("/telegram/validate-api-key", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.ValidateApiKey)
+		api.GET("/telegram/bot-status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.GetBotStatus)
+		api.POST("/telegram/reload-config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.ReloadBotConfig)
+		api.POST("/telegram/test-message", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.TestBotMessage)
+		api.GET("/telegram/debug-connection", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.DebugBotConnection)
+		api.GET("/telegram/channels", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.GetChannels)
+		api.POST("/telegram/channels", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.CreateChannel)
+		api.PUT("/telegram/channels/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.UpdateChannel)
+		api.DELETE("/telegram/channels/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.DeleteChannel)
+		api.GET("/telegram/logs", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.GetTelegramLogs)
+		api.GET("/telegram/logs/stats", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.GetTelegramLogStats)
+		api.POST("/telegram/logs/clear", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.ClearTelegramLogs)
 		api.POST("/telegram/webhook", telegramHandler.HandleWebhook)
-		api.POST("/telegram/manual-push/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), telegramHandler.ManualPushToChannel)
+		api.POST("/telegram/manual-push/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), telegramHandler.ManualPushToChannel)
+
+		// Telegram 采集频道路由
+		telegramImportHandler := handlers.NewTelegramImportHandler(repoManager.TelegramImportChannelRepository, telegramChannelImporter)
+		api.GET("/telegram/import-channels", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), telegramImportHandler.ListImportChannels)
+		api.POST("/telegram/import-channels", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), telegramImportHandler.CreateImportChannel)
+		api.PUT("/telegram/import-channels/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), telegramImportHandler.UpdateImportChannel)
+		api.DELETE("/telegram/import-channels/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), telegramImportHandler.DeleteImportChannel)
+		api.POST("/telegram/import-channels/:id/import", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionContentSourceManage), telegramImportHandler.ImportChannelExport)
 
 		// 微信公众号相关路由
 		wechatHandler := handlers.NewWechatHandler(
 			wechatBotService,
 			repoManager.SystemConfigRepository,
 		)
-		api.GET("/wechat/bot-config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), wechatHandler.GetBotConfig)
-		api.PUT("/wechat/bot-config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), wechatHandler.UpdateBotConfig)
-		api.GET("/wechat/bot-status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), wechatHandler.GetBotStatus)
+		api.GET("/wechat/bot-config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), wechatHandler.GetBotConfig)
+		api.PUT("/wechat/bot-config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), wechatHandler.UpdateBotConfig)
+		api.GET("/wechat/bot-status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionBotManage), wechatHandler.GetBotStatus)
 		api.POST("/wechat/callback", wechatHandler.HandleWechatMessage)
 		api.GET("/wechat/callback", wechatHandler.HandleWechatMessage)
 
 		// OG图片生成路由
 		api.GET("/og-image", ogImageHandler.GenerateOGImage)
+		api.GET("/og-image/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.GetConfig)
+		api.PUT("/og-image/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.UpdateConfig)
+		api.PUT("/og-image/templates", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.SaveTemplate)
+		api.DELETE("/og-image/templates/:name", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.DeleteTemplate)
+		api.POST("/og-image/preview", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.Preview)
+		api.DELETE("/og-image/cache", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), ogImageHandler.ClearCache)
+
+		// 资源页SEO数据路由
+		api.GET("/seo/resources/:key", seoHandler.GetResourceSEO)
+		api.GET("/seo/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), seoHandler.GetConfig)
+		api.PUT("/seo/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), seoHandler.UpdateConfig)
 
 		// 举报和版权申述路由
 		api.POST("/reports", reportHandler.CreateReport)
 		api.GET("/reports/:id", reportHandler.GetReport)
-		api.GET("/reports", middleware.AuthMiddleware(), middleware.AdminMiddleware(), reportHandler.ListReports)
-		api.PUT("/reports/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), reportHandler.UpdateReport)
-		api.DELETE("/reports/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), reportHandler.DeleteReport)
+		api.GET("/reports", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), reportHandler.ListReports)
+		api.PUT("/reports/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), reportHandler.UpdateReport)
+		api.DELETE("/reports/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), reportHandler.DeleteReport)
 		api.GET("/reports/resource/:resource_key", reportHandler.GetReportByResource)
 
 		api.POST("/copyright-claims", copyrightClaimHandler.CreateCopyrightClaim)
 		api.GET("/copyright-claims/:id", copyrightClaimHandler.GetCopyrightClaim)
-		api.GET("/copyright-claims", middleware.AuthMiddleware(), middleware.AdminMiddleware(), copyrightClaimHandler.ListCopyrightClaims)
-		api.PUT("/copyright-claims/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), copyrightClaimHandler.UpdateCopyrightClaim)
-		api.DELETE("/copyright-claims/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), copyrightClaimHandler.DeleteCopyrightClaim)
+		api.GET("/copyright-claims", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), copyrightClaimHandler.ListCopyrightClaims)
+		api.PUT("/copyright-claims/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), copyrightClaimHandler.UpdateCopyrightClaim)
+		api.DELETE("/copyright-claims/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), copyrightClaimHandler.DeleteCopyrightClaim)
 		api.GET("/copyright-claims/resource/:resource_key", copyrightClaimHandler.GetCopyrightClaimByResource)
 
+		// 内容处置记录与撤销
+		api.GET("/moderation/actions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), moderationHandler.ListActions)
+		api.POST("/moderation/actions/:id/revert", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), moderationHandler.RevertAction)
+		api.GET("/moderation/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), moderationHandler.GetConfig)
+		api.PUT("/moderation/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), moderationHandler.UpdateConfig)
+
+		// 黑名单规则
+		api.GET("/blocklist", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), blocklistHandler.ListRules)
+		api.POST("/blocklist", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), blocklistHandler.CreateRule)
+		api.PUT("/blocklist/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), blocklistHandler.UpdateRule)
+		api.DELETE("/blocklist/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportManage), blocklistHandler.DeleteRule)
+		api.POST("/blocklist/test", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionReportView), blocklistHandler.TestRules)
+
 		// Sitemap静态文件服务（优先于API路由）
 		// 提供生成的sitemap.xml索引文件
 		r.StaticFile("/sitemap.xml", "./data/sitemap/sitemap.xml")
-		// 提供生成的sitemap分页文件，使用通配符路由
+		// 提供生成的sitemap分页文件（资源分页与分类/标签/热播剧专题，gzip压缩），使用通配符路由
 		r.GET("/sitemap-:page", func(c *gin.Context) {
 			page := c.Param("page")
-			if !strings.HasSuffix(page, ".xml") {
+			if !strings.HasSuffix(page, ".xml") && !strings.HasSuffix(page, ".xml.gz") {
 				c.JSON(http.StatusNotFound, gin.H{"error": "文件不存在"})
 				return
 			}
 			c.File("./data/sitemap/sitemap-" + page)
 		})
 
+		// IndexNow 密钥文件（需位于站点根目录）
+		r.GET("/indexnow-:file", searchEngineHandler.ServeIndexNowKey)
+
 		// Sitemap静态文件API路由（API兼容）
 		api.GET("/sitemap.xml", func(c *gin.Context) {
 			c.File("./data/sitemap/sitemap.xml")
@@ -624,7 +1094,7 @@ func main() {
 		// 提供生成的sitemap分页文件，使用API路径
 		api.GET("/sitemap-:page", func(c *gin.Context) {
 			page := c.Param("page")
-			if !strings.HasSuffix(page, ".xml") {
+			if !strings.HasSuffix(page, ".xml") && !strings.HasSuffix(page, ".xml.gz") {
 				c.JSON(http.StatusNotFound, gin.H{"error": "文件不存在"})
 				return
 			}
@@ -632,35 +1102,50 @@ func main() {
 		})
 
 		// Sitemap管理API（通过管理员接口进行管理）
-		api.GET("/sitemap/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSitemapConfig)
-		api.POST("/sitemap/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.UpdateSitemapConfig)
-		api.POST("/sitemap/generate", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GenerateSitemap)
-		api.GET("/sitemap/status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GetSitemapStatus)
-		api.POST("/sitemap/full-generate", middleware.AuthMiddleware(), middleware.AdminMiddleware(), handlers.GenerateFullSitemap)
+		api.GET("/sitemap/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), handlers.GetSitemapConfig)
+		api.POST("/sitemap/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), handlers.UpdateSitemapConfig)
+		api.POST("/sitemap/generate", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), handlers.GenerateSitemap)
+		api.GET("/sitemap/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), handlers.GetSitemapStatus)
+		api.POST("/sitemap/full-generate", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), handlers.GenerateFullSitemap)
+
+		// 搜索引擎URL提交API
+		api.GET("/search-engine/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.GetConfig)
+		api.PUT("/search-engine/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.UpdateConfig)
+		api.GET("/search-engine/stats", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.GetStats)
+		api.GET("/search-engine/submissions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.ListSubmissions)
+		api.POST("/search-engine/submit", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.SubmitURLs)
+		api.POST("/search-engine/requeue-failed", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.RequeueFailed)
+		api.POST("/search-engine/run", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), searchEngineHandler.Run)
 
 		// Google索引管理API
-		api.GET("/google-index/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetConfig)
-		api.GET("/google-index/config-all", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetAllConfig) // 获取所有配置
-		api.POST("/google-index/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.UpdateConfig)
-		api.POST("/google-index/config/update", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.UpdateGoogleIndexConfig) // 分组配置更新
-		api.GET("/google-index/status", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetStatus)                       // 获取状态
-		api.POST("/google-index/tasks", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.CreateTask)
-		api.GET("/google-index/tasks", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetTasks)
-		api.GET("/google-index/tasks/:id", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetTaskStatus)
-		api.POST("/google-index/tasks/:id/start", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.StartTask)
-		api.GET("/google-index/tasks/:id/items", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.GetTaskItems)
+		api.GET("/google-index/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetConfig)
+		api.GET("/google-index/config-all", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetAllConfig) // 获取所有配置
+		api.POST("/google-index/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.UpdateConfig)
+		api.POST("/google-index/config/update", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.UpdateGoogleIndexConfig) // 分组配置更新
+		api.GET("/google-index/status", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetStatus)                       // 获取状态
+		api.POST("/google-index/tasks", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.CreateTask)
+		api.GET("/google-index/tasks", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetTasks)
+		api.GET("/google-index/tasks/:id", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetTaskStatus)
+		api.POST("/google-index/tasks/:id/start", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.StartTask)
+		api.GET("/google-index/tasks/:id/items", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.GetTaskItems)
 
 		// Google索引凭据上传和验证API
-		api.POST("/google-index/upload-credentials", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.UploadCredentials)
-		api.POST("/google-index/validate-credentials", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.ValidateCredentials)
-		api.POST("/google-index/diagnose-permissions", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.DiagnosePermissions)
-		api.POST("/google-index/urls/submit-to-index", middleware.AuthMiddleware(), middleware.AdminMiddleware(), googleIndexHandler.SubmitURLsToIndex)
+		api.POST("/google-index/upload-credentials", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.UploadCredentials)
+		api.POST("/google-index/validate-credentials", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.ValidateCredentials)
+		api.POST("/google-index/diagnose-permissions", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.DiagnosePermissions)
+		api.POST("/google-index/urls/submit-to-index", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexHandler.SubmitURLsToIndex)
+
+		// Google索引覆盖率API
+		api.GET("/google-index/coverage", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexCoverageHandler.GetCoverage)
+		api.GET("/google-index/coverage/statuses", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexCoverageHandler.ListStatuses)
+		api.POST("/google-index/coverage/reconcile", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexCoverageHandler.Reconcile)
+		api.POST("/google-index/coverage/resubmit", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), googleIndexCoverageHandler.Resubmit)
 
 		// Bing提交API
 		if bingHandler != nil {
 			// Bing配置API
-			api.GET("/bing/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), bingHandler.GetBingIndexConfig)
-			api.POST("/bing/config", middleware.AuthMiddleware(), middleware.AdminMiddleware(), bingHandler.UpdateBingIndexConfig)
+			api.GET("/bing/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), bingHandler.GetBingIndexConfig)
+			api.POST("/bing/config", middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionSEOManage), bingHandler.UpdateBingIndexConfig)
 		}
 
 		// 插件管理API
@@ -717,3 +1202,80 @@ func main() {
 
 	utils.Info("服务器已优雅关闭")
 }
+
+// newJobScheduler 创建定时任务调度器，执行失败时记录告警日志；
+// 配置了选主时手动执行需取得任务的锁，避免与主节点重复执行
+func newJobScheduler(repoManager *repo.RepositoryManager) *cronjob.Scheduler {
+	nodeID := os.Getenv("LEADER_NODE_ID")
+	if nodeID == "" {
+		nodeID = leader.DefaultNodeID()
+	}
+	return cronjob.New(cronjob.Config{
+		Store:  scheduler.NewJobStore(repoManager.ScheduledJobRepository),
+		NodeID: nodeID,
+		Lease: func(ctx context.Context, name string) (func(), error) {
+			if elector := scheduler.GetGlobalElector(); elector != nil {
+				return elector.Acquire(ctx, name)
+			}
+			return func() {}, nil
+		},
+		OnFinish: func(run cronjob.Run) {
+			if run.Status == cronjob.StatusFailed {
+				utils.Warn("定时任务 %s 执行失败（%s）: %s", run.Job, run.Trigger, run.Error)
+			}
+		},
+		OnError: func(op string, err error) {
+			utils.Warn("定时任务存储操作 %s 失败: %v", op, err)
+		},
+	})
+}
+
+// newLeaderElector 按环境变量创建选主器，LEADER_ELECTION=none 时返回 nil
+func newLeaderElector() *leader.Elector {
+	mode := os.Getenv("LEADER_ELECTION")
+	if mode == "none" {
+		return nil
+	}
+	nodeID := os.Getenv("LEADER_NODE_ID")
+	if nodeID == "" {
+		nodeID = leader.DefaultNodeID()
+	}
+
+	var locker leader.Locker
+	if mode == "redis" {
+		addr := os.Getenv("LEADER_REDIS_ADDR")
+		password := os.Getenv("LEADER_REDIS_PASSWORD")
+		redisDB, _ := strconv.Atoi(os.Getenv("LEADER_REDIS_DB"))
+		if addr == "" {
+			addr = os.Getenv("CACHE_REDIS_ADDR")
+			password = os.Getenv("CACHE_REDIS_PASSWORD")
+			redisDB, _ = strconv.Atoi(os.Getenv("CACHE_REDIS_DB"))
+		}
+		redisLocker, err := leader.NewRedisLocker(leader.RedisConfig{Addr: addr, Password: password, DB: redisDB}, nodeID)
+		if err != nil {
+			utils.Warn("连接选主Redis失败，改用PostgreSQL advisory lock: %v", err)
+		} else {
+			locker = redisLocker
+		}
+	}
+	if locker == nil {
+		mode = "postgres"
+		locker = leader.NewPGLocker(db.DSN(), nodeID)
+	}
+
+	utils.Info("调度任务选主已启用，节点: %s，锁: %s", nodeID, mode)
+	return leader.New(leader.Config{
+		NodeID: nodeID,
+		Locker: locker,
+		OnError: func(job string, err error) {
+			utils.Warn("调度任务 %s 选主失败: %v", job, err)
+		},
+		OnChange: func(job string, leading bool) {
+			if leading {
+				utils.Info("本节点成为调度任务 %s 的主节点", job)
+			} else {
+				utils.Info("本节点不再是调度任务 %s 的主节点", job)
+			}
+		},
+	})
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRecorder 审计日志写入，由 services.AuditLogService 实现；before/after 为修改前后的数据，
+// 写入时计算差异并对敏感字段脱敏
+type AuditRecorder interface {
+	RecordAudit(log *entity.AuditLog, before, after interface{}) error
+}
+
+var auditRecorder AuditRecorder
+
+// SetAuditRecorder 设置审计日志写入实现
+func SetAuditRecorder(recorder AuditRecorder) {
+	auditRecorder = recorder
+}
+
+const (
+	auditPermissionKey = "audit_permission"
+	auditContextKey    = "audit_context"
+)
+
+// auditContext 处理函数补充的审计信息
+type auditContext struct {
+	action     string
+	targetType string
+	targetID   string
+	detail     string
+	before     interface{}
+	after      interface{}
+	skip       bool
+}
+
+func getAuditContext(c *gin.Context) *auditContext {
+	if v, ok := c.Get(auditContextKey); ok {
+		if ac, ok := v.(*auditContext); ok {
+			return ac
+		}
+	}
+	ac := &auditContext{}
+	c.Set(auditContextKey, ac)
+	return ac
+}
+
+// SetAuditAction 覆盖默认的操作名称与对象，参数为空时保留默认值
+func SetAuditAction(c *gin.Context, action, targetType, targetID string) {
+	ac := getAuditContext(c)
+	if action != "" {
+		ac.action = action
+	}
+	if targetType != "" {
+		ac.targetType = targetType
+	}
+	if targetID != "" {
+		ac.targetID = targetID
+	}
+}
+
+// SetAuditChange 记录修改前后的数据，新建时 before 为 nil，删除时 after 为 nil
+func SetAuditChange(c *gin.Context, before, after interface{}) {
+	ac := getAuditContext(c)
+	ac.before, ac.after = before, after
+}
+
+// SetAuditDetail 记录补充说明，如批量删除的ID列表
+func SetAuditDetail(c *gin.Context, detail string) {
+	getAuditContext(c).detail = detail
+}
+
+// SkipAudit 本次请求不写审计日志（如只读的测试、预览类接口）
+func SkipAudit(c *gin.Context) {
+	getAuditContext(c).skip = true
+}
+
+// AuditTrail 审计中间件，全局注册；只记录通过 RequirePermission 校验的写操作（POST/PUT/PATCH/DELETE），
+// 在处理函数执行完成后写入，操作名称默认由路由推导，处理函数可通过 SetAuditAction 等补充
+func AuditTrail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if auditRecorder == nil {
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return
+		}
+		permission := c.GetString(auditPermissionKey)
+		if permission == "" {
+			return
+		}
+		ac := getAuditContext(c)
+		if ac.skip {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		action, targetType := auditActionFromRoute(c.Request.Method, route)
+		if ac.action != "" {
+			action = ac.action
+		}
+		if ac.targetType != "" {
+			targetType = ac.targetType
+		}
+		targetID := ac.targetID
+		if targetID == "" && len(c.Params) > 0 {
+			targetID = c.Params[0].Value
+		}
+
+		status := c.Writer.Status()
+		log := &entity.AuditLog{
+			UserID:     c.GetUint("user_id"),
+			Username:   c.GetString("username"),
+			Role:       c.GetString("role"),
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			StatusCode: status,
+			Success:    status < http.StatusBadRequest,
+			IP:         c.ClientIP(),
+			UserAgent:  c.Request.UserAgent(),
+			Detail:     ac.detail,
+		}
+		if err := auditRecorder.RecordAudit(log, ac.before, ac.after); err != nil {
+			utils.Error("写入审计日志失败 - 操作: %s, 用户: %s, Error: %v", action, log.Username, err)
+		}
+	}
+}
+
+// auditActionFromRoute 由路由推导操作名称：对象类型为 /api 后的第一段，
+// 其余静态段依次拼接，再按方法追加 create/update/delete；POST 且带静态动作段时动作段即为操作。
+// 例如 PUT /api/cks/:id -> cks.update，DELETE /api/resources/batch -> resources.batch.delete，
+// POST /api/plugins/:name/enable -> plugins.enable
+func auditActionFromRoute(method, route string) (string, string) {
+	var segments []string
+	for _, seg := range strings.Split(strings.TrimPrefix(route, "/api/"), "/") {
+		if seg == "" || strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			continue
+		}
+		segments = append(segments, strings.ReplaceAll(seg, "-", "_"))
+	}
+	if len(segments) == 0 {
+		return strings.ToLower(method), ""
+	}
+	targetType := segments[0]
+	parts := segments
+	switch method {
+	case http.MethodPost:
+		if len(segments) == 1 {
+			parts = append(parts, "create")
+		}
+	case http.MethodPut, http.MethodPatch:
+		parts = append(parts, "update")
+	case http.MethodDelete:
+		parts = append(parts, "delete")
+	}
+	return strings.Join(parts, "."), targetType
+}
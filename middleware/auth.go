@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -13,16 +14,27 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte("your-secret-key")
-
 // Claims JWT声明
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
+// SessionValidator 校验访问令牌关联的会话仍然有效且用户未被禁用，由 services.AuthSessionService 实现
+type SessionValidator interface {
+	ValidateSession(userID uint, sessionID string) error
+}
+
+var sessionValidator SessionValidator
+
+// SetSessionValidator 设置会话校验实现
+func SetSessionValidator(validator SessionValidator) {
+	sessionValidator = validator
+}
+
 // AuthMiddleware 认证中间件
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -59,6 +71,16 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 会话被吊销（登出、改密、禁用用户等）后令牌立即失效
+		if sessionValidator != nil {
+			if err := sessionValidator.ValidateSession(claims.UserID, claims.SessionID); err != nil {
+				utils.Warn("AuthMiddleware - 会话无效 - 用户: %s(ID:%d), IP: %s, Error: %v", claims.Username, claims.UserID, clientIP, err)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的令牌"})
+				c.Abort()
+				return
+			}
+		}
+
 		// utils.Info("AuthMiddleware - 认证成功 - 用户: %s(ID:%d), 角色: %s, IP: %s",
 		// 	claims.Username, claims.UserID, claims.Role, clientIP)
 
@@ -66,12 +88,26 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 		c.Set("client_ip", clientIP)
 
 		c.Next()
 	}
 }
 
+// QueryTokenMiddleware 允许通过 access_token 查询参数传递令牌，
+// 供无法设置请求头的 EventSource 使用，需放在 AuthMiddleware 之前
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
+
 // AdminMiddleware 管理员中间件
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -97,43 +133,52 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-// GenerateToken 生成JWT令牌
-func GenerateToken(user *entity.User) (string, error) {
+// GenerateToken 为会话签发短期访问令牌，返回令牌与过期时间
+func GenerateToken(user *entity.User, sessionID string) (string, time.Time, error) {
+	kid, key, err := signingKey()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := utils.GetCurrentTime()
+	expiresAt := now.Add(accessTokenTTL)
 	claims := Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(utils.GetCurrentTime().Add(30 * 24 * time.Hour)), // 30天有效期
-			IssuedAt:  jwt.NewNumericDate(utils.GetCurrentTime()),
-			NotBefore: jwt.NewNumericDate(utils.GetCurrentTime()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	return signed, expiresAt, err
 }
 
-// parseToken 解析JWT令牌
+// parseToken 解析JWT令牌，按 kid 选择校验密钥
 func parseToken(tokenString string) (*Claims, error) {
-	// utils.Info("parseToken - 开始解析令牌")
-
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		return verifyKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
-		// utils.Error("parseToken - JWT解析失败: %v", err)
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		// utils.Info("parseToken - 令牌解析成功，用户ID: %d", claims.UserID)
-		return claims, nil
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
 	}
-
-	// utils.Error("parseToken - 令牌无效或签名错误")
-	return nil, jwt.ErrSignatureInvalid
+	if claims.SessionID == "" {
+		return nil, fmt.Errorf("令牌缺少会话标识")
+	}
+	return claims, nil
 }
 
 // HashPassword 哈希密码
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+)
+
+// 访问令牌默认有效期，可通过 JWT_ACCESS_TTL 调整
+const defaultAccessTokenTTL = 15 * time.Minute
+
+// jwtKeyring JWT签名密钥：current 用于签发，verify 按 kid 校验（包含轮换前的旧密钥）
+type jwtKeyring struct {
+	currentID string
+	current   []byte
+	verify    map[string][]byte
+}
+
+var (
+	jwtKeysMu      sync.RWMutex
+	jwtKeys        *jwtKeyring
+	accessTokenTTL = defaultAccessTokenTTL
+)
+
+// SetJWTSecrets 设置签发密钥与仍可校验的旧密钥，kid 由密钥摘要派生
+func SetJWTSecrets(current string, previous ...string) error {
+	if len(current) < 32 {
+		return errors.New("JWT密钥长度至少为32个字符")
+	}
+	keyring := &jwtKeyring{
+		currentID: jwtKeyID(current),
+		current:   []byte(current),
+		verify:    map[string][]byte{jwtKeyID(current): []byte(current)},
+	}
+	for _, secret := range previous {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			keyring.verify[jwtKeyID(secret)] = []byte(secret)
+		}
+	}
+
+	jwtKeysMu.Lock()
+	jwtKeys = keyring
+	jwtKeysMu.Unlock()
+	return nil
+}
+
+// SetAccessTokenTTL 设置访问令牌有效期
+func SetAccessTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		accessTokenTTL = ttl
+	}
+}
+
+// AccessTokenTTL 访问令牌有效期
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
+// InitJWTFromEnv 从环境变量加载JWT配置：
+// JWT_SECRET 为签发密钥，JWT_PREVIOUS_SECRETS 为逗号分隔的旧密钥（轮换期间仍可校验），
+// JWT_ACCESS_TTL 为访问令牌有效期。未设置 JWT_SECRET 时使用系统配置中保存的密钥，首次启动自动生成
+func InitJWTFromEnv(configRepo repo.SystemConfigRepository) error {
+	if ttl := os.Getenv("JWT_ACCESS_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("JWT_ACCESS_TTL 格式错误: %s", ttl)
+		}
+		SetAccessTokenTTL(d)
+	}
+
+	previous := strings.Split(os.Getenv("JWT_PREVIOUS_SECRETS"), ",")
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return SetJWTSecrets(secret, previous...)
+	}
+
+	secret, err := configRepo.GetConfigValue(entity.ConfigKeyJWTSecret)
+	if err != nil || secret == "" {
+		buf := make([]byte, 48)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("生成JWT密钥失败: %v", err)
+		}
+		secret = base64.RawURLEncoding.EncodeToString(buf)
+		if err := configRepo.UpsertConfigs([]entity.SystemConfig{{Key: entity.ConfigKeyJWTSecret, Value: secret, Type: entity.ConfigTypeString}}); err != nil {
+			return fmt.Errorf("保存JWT密钥失败: %v", err)
+		}
+		utils.Warn("未设置 JWT_SECRET 环境变量，已生成随机JWT密钥并保存到系统配置")
+	}
+	return SetJWTSecrets(secret, previous...)
+}
+
+// jwtKeyID 由密钥派生的 kid，不泄露密钥本身
+func jwtKeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+func signingKey() (string, []byte, error) {
+	jwtKeysMu.RLock()
+	defer jwtKeysMu.RUnlock()
+	if jwtKeys == nil {
+		return "", nil, errors.New("JWT密钥未初始化")
+	}
+	return jwtKeys.currentID, jwtKeys.current, nil
+}
+
+func verifyKey(kid string) ([]byte, error) {
+	jwtKeysMu.RLock()
+	defer jwtKeysMu.RUnlock()
+	if jwtKeys == nil {
+		return nil, errors.New("JWT密钥未初始化")
+	}
+	key, ok := jwtKeys.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的密钥标识: %s", kid)
+	}
+	return key, nil
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionChecker 角色权限查询，由 services.RBACService 实现
+type PermissionChecker interface {
+	HasPermission(role, permission string) bool
+	RecordDenial(denial *entity.PermissionDenial) error
+}
+
+var permissionChecker PermissionChecker
+
+// SetPermissionChecker 设置角色权限查询实现
+func SetPermissionChecker(checker PermissionChecker) {
+	permissionChecker = checker
+}
+
+// HasPermission 判断当前请求用户是否拥有权限，需在 AuthMiddleware 之后使用；
+// 未设置权限查询实现时仅管理员拥有权限
+func HasPermission(c *gin.Context, permission string) bool {
+	role, _ := c.Get("role")
+	roleName, _ := role.(string)
+	if roleName == entity.RoleAdmin {
+		return true
+	}
+	if permissionChecker == nil || roleName == "" {
+		return false
+	}
+	return permissionChecker.HasPermission(roleName, permission)
+}
+
+// RequirePermission 权限中间件，需放在 AuthMiddleware 之后；无权限时返回 403 并记录拒绝
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if HasPermission(c, permission) {
+			// 供 AuditTrail 判断是否为需要审计的后台操作
+			c.Set(auditPermissionKey, permission)
+			c.Next()
+			return
+		}
+
+		userID, _ := c.Get("user_id")
+		username, _ := c.Get("username")
+		role, _ := c.Get("role")
+		utils.Warn("RequirePermission - 权限不足 - 用户: %v, 角色: %v, 权限: %s, IP: %s, Path: %s %s",
+			username, role, permission, c.ClientIP(), c.Request.Method, c.Request.URL.Path)
+
+		if permissionChecker != nil {
+			denial := &entity.PermissionDenial{
+				Permission: permission,
+				Method:     c.Request.Method,
+				Path:       c.Request.URL.Path,
+				IP:         c.ClientIP(),
+			}
+			denial.UserID, _ = userID.(uint)
+			denial.Username, _ = username.(string)
+			denial.Role, _ = role.(string)
+			go func() {
+				if err := permissionChecker.RecordDenial(denial); err != nil {
+					utils.Error("记录权限拒绝失败: %v", err)
+				}
+			}()
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "权限不足",
+			"data":    gin.H{"permission": permission},
+			"code":    http.StatusForbidden,
+		})
+		c.Abort()
+	}
+}
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,92 +18,95 @@ func SetRepositoryManager(rm *repo.RepositoryManager) {
 	repoManager = rm
 }
 
-// PublicAPIAuth 公开API认证中间件
-func PublicAPIAuth() gin.HandlerFunc {
+// APIKeyAuthenticator 公开API令牌校验，由 services.APIKeyService 实现。
+// 返回的错误可实现 HTTPStatus() int 与 RetryAfterSeconds() int
+type APIKeyAuthenticator interface {
+	Authenticate(token, ip, scope string, fromQuery bool) (*entity.APIKey, error)
+}
+
+var apiKeyAuthenticator APIKeyAuthenticator
+
+// SetAPIKeyAuthenticator 设置公开API令牌校验实现
+func SetAPIKeyAuthenticator(authenticator APIKeyAuthenticator) {
+	apiKeyAuthenticator = authenticator
+}
+
+// PublicAPIAuth 公开API认证中间件，校验令牌、作用域、IP白名单、限流与每日配额。
+// 令牌只能通过 X-API-Token 请求头传递，仍读取 api_token 查询参数是为了返回明确的拒绝原因
+func PublicAPIAuth(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取API Token
 		apiToken := c.GetHeader("X-API-Token")
+		fromQuery := false
 		if apiToken == "" {
-			// 尝试从查询参数获取
 			apiToken = c.Query("api_token")
+			fromQuery = apiToken != ""
 		}
 
-		if apiToken == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "缺少API Token",
-				"code":    401,
-			})
-			c.Abort()
+		if repoManager == nil || apiKeyAuthenticator == nil {
+			abortPublicAPI(c, http.StatusInternalServerError, "系统未初始化")
 			return
 		}
 
-		// 验证API Token
-		if repoManager == nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "系统未初始化",
-				"code":    500,
-			})
-			c.Abort()
-			return
-		}
-
-		// 验证API Token
-		apiTokenConfig, err := repoManager.SystemConfigRepository.GetConfigValue(entity.ConfigKeyApiToken)
+		key, err := apiKeyAuthenticator.Authenticate(apiToken, c.ClientIP(), scope, fromQuery)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "系统配置获取失败",
-				"code":    500,
-			})
-			c.Abort()
-			return
-		}
-
-		if apiTokenConfig == "" {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"success": false,
-				"message": "API Token未配置",
-				"code":    503,
-			})
-			c.Abort()
-			return
-		}
-
-		if apiTokenConfig != apiToken {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "API Token无效",
-				"code":    401,
-			})
-			c.Abort()
+			status := http.StatusUnauthorized
+			var statusErr interface{ HTTPStatus() int }
+			if errors.As(err, &statusErr) {
+				status = statusErr.HTTPStatus()
+			}
+			var retryErr interface{ RetryAfterSeconds() int }
+			if errors.As(err, &retryErr) && retryErr.RetryAfterSeconds() > 0 {
+				c.Header("Retry-After", strconv.Itoa(retryErr.RetryAfterSeconds()))
+			}
+			if key != nil {
+				recordPublicAPIRejection(c, key.ID, status, err.Error())
+			}
+			abortPublicAPI(c, status, err.Error())
 			return
 		}
-
 		// 检查维护模式
 		maintenanceMode, err := repoManager.SystemConfigRepository.GetConfigBool(entity.ConfigKeyMaintenanceMode)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "系统配置获取失败",
-				"code":    500,
-			})
-			c.Abort()
+			abortPublicAPI(c, http.StatusInternalServerError, "系统配置获取失败")
 			return
 		}
 
 		if maintenanceMode {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"success": false,
-				"message": "系统维护中，请稍后再试",
-				"code":    503,
-			})
-			c.Abort()
+			abortPublicAPI(c, http.StatusServiceUnavailable, "系统维护中，请稍后再试")
 			return
 		}
 
-		// 验证通过，继续处理
+		// 验证通过，记录密钥供访问日志使用
+		c.Set("api_key_id", key.ID)
+		c.Set("api_key_name", key.Name)
 		c.Next()
 	}
 }
+
+func abortPublicAPI(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": message,
+		"code":    status,
+	})
+	c.Abort()
+}
+
+// recordPublicAPIRejection 把密钥被拒绝的请求写入API访问日志
+func recordPublicAPIRejection(c *gin.Context, apiKeyID uint, status int, message string) {
+	logEntry := &entity.APIAccessLog{
+		IP:             c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+		Endpoint:       c.Request.URL.Path,
+		Method:         c.Request.Method,
+		ResponseStatus: status,
+		ErrorMessage:   message,
+		APIKeyID:       apiKeyID,
+	}
+	go func() {
+		if err := repoManager.APIAccessLogRepository.Create(logEntry); err != nil {
+			utils.Error("保存API访问日志失败: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,277 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestName 归档中的清单文件，最后写入，记录其他所有条目的校验和
+const ManifestName = "manifest.json"
+
+// ManifestVersion 当前归档格式版本
+const ManifestVersion = 1
+
+// 归档中各部分的路径前缀
+const (
+	PrefixDatabase = "db/"
+	PrefixConfig   = "config/"
+	PrefixFiles    = "files/"
+)
+
+// Manifest 备份清单
+type Manifest struct {
+	Version    int         `json:"version"`
+	CreatedAt  time.Time   `json:"created_at"`
+	AppVersion string      `json:"app_version"`
+	DBMode     string      `json:"db_mode,omitempty"`
+	Tables     []TableInfo `json:"tables,omitempty"`
+	Sources    []string    `json:"sources,omitempty"`
+	Files      []FileEntry `json:"files"`
+}
+
+// TableInfo 原生导出时每个表的列与行数，恢复时用于按列导入和校验行数
+type TableInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows"`
+}
+
+// FileEntry 归档中的一个条目
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Writer 写出 tar.gz 归档，Close 时写入清单
+type Writer struct {
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	manifest *Manifest
+}
+
+// NewWriter 创建归档写出器，manifest 的 Files 由写出器填充
+func NewWriter(w io.Writer, manifest *Manifest) *Writer {
+	gz := gzip.NewWriter(w)
+	manifest.Version = ManifestVersion
+	manifest.Files = nil
+	return &Writer{gz: gz, tw: tar.NewWriter(gz), manifest: manifest}
+}
+
+// Manifest 当前清单
+func (w *Writer) Manifest() *Manifest {
+	return w.manifest
+}
+
+// AddFile 写入一个条目，size 必须与 r 的长度一致
+func (w *Writer) AddFile(name string, r io.Reader, size int64, modTime time.Time) error {
+	if err := checkEntryName(name); err != nil {
+		return err
+	}
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(w.tw, io.TeeReader(r, h))
+	if err != nil {
+		return fmt.Errorf("写入 %s 失败: %v", name, err)
+	}
+	if n != size {
+		return fmt.Errorf("写入 %s 失败: 长度 %d 与声明的 %d 不一致", name, n, size)
+	}
+	w.manifest.Files = append(w.manifest.Files, FileEntry{Path: name, Size: size, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
+// AddBytes 写入内存中的内容
+func (w *Writer) AddBytes(name string, data []byte) error {
+	return w.AddFile(name, bytes.NewReader(data), int64(len(data)), time.Now())
+}
+
+// AddLocalFile 写入本地文件
+func (w *Writer) AddLocalFile(name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return w.AddFile(name, f, info.Size(), info.ModTime())
+}
+
+// AddDir 递归写入目录下的普通文件，条目名为 prefix + 相对路径；目录不存在时跳过
+func (w *Writer) AddDir(prefix, dir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if err := w.AddLocalFile(prefix+filepath.ToSlash(rel), p); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// Close 写入清单并结束归档，不关闭底层 io.Writer
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := w.tw.WriteHeader(&tar.Header{
+		Name:     ManifestName,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return err
+	}
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// Walk 依次读取归档中的条目（不含清单）
+func Walk(r io.Reader, fn func(name string, size int64, r io.Reader) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("不是有效的备份归档: %v", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("读取备份归档失败: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Name == ManifestName {
+			continue
+		}
+		if err := checkEntryName(hdr.Name); err != nil {
+			return err
+		}
+		if err := fn(hdr.Name, hdr.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// Verify 完整读取归档，校验每个条目的大小和 SHA-256 与清单一致
+func Verify(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的备份归档: %v", err)
+	}
+	defer gz.Close()
+
+	actual := make(map[string]FileEntry)
+	var manifest *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取备份归档失败: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Name == ManifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("解析备份清单失败: %v", err)
+			}
+			continue
+		}
+		h := sha256.New()
+		n, err := io.Copy(h, tr)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %v", hdr.Name, err)
+		}
+		actual[hdr.Name] = FileEntry{Path: hdr.Name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}
+	}
+	// gzip 尾部的 CRC 在读到末尾时校验
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return nil, fmt.Errorf("备份归档已损坏: %v", err)
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("备份归档缺少清单，可能不完整")
+	}
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("备份格式版本 %d 高于当前支持的版本 %d", manifest.Version, ManifestVersion)
+	}
+	for _, want := range manifest.Files {
+		got, ok := actual[want.Path]
+		if !ok {
+			return nil, fmt.Errorf("备份归档缺少 %s", want.Path)
+		}
+		if got.Size != want.Size || got.SHA256 != want.SHA256 {
+			return nil, fmt.Errorf("%s 校验失败", want.Path)
+		}
+		delete(actual, want.Path)
+	}
+	if len(actual) > 0 {
+		extra := make([]string, 0, len(actual))
+		for name := range actual {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("备份归档包含清单外的条目: %s", strings.Join(extra, ", "))
+	}
+	return manifest, nil
+}
+
+// checkEntryName 条目名必须是不含 .. 的相对路径
+func checkEntryName(name string) error {
+	clean := path.Clean(name)
+	if name == "" || clean != name || path.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("非法的归档条目: %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func buildArchive(t *testing.T) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644)
+	os.WriteFile(filepath.Join(dir, "sub", "b.png"), []byte{0, 1, 2, 3}, 0644)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Manifest{AppVersion: "test", Sources: []string{"uploads"}})
+	if err := w.AddBytes(PrefixConfig+"system_config.json", []byte(`[]`)); err != nil {
+		t.Fatal(err)
+	}
+	n, err := w.AddDir(PrefixFiles+"uploads/", dir)
+	if err != nil || n != 2 {
+		t.Fatalf("AddDir = %d, %v", n, err)
+	}
+	if n, err := w.AddDir(PrefixFiles+"plugins/", filepath.Join(dir, "missing")); err != nil || n != 0 {
+		t.Fatalf("AddDir missing = %d, %v", n, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	data := buildArchive(t)
+
+	manifest, err := Verify(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if manifest.Version != ManifestVersion || manifest.AppVersion != "test" || len(manifest.Files) != 3 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+
+	got := map[string]string{}
+	err = Walk(bytes.NewReader(data), func(name string, size int64, r io.Reader) error {
+		b, err := io.ReadAll(r)
+		got[name] = string(b)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"config/system_config.json": "[]",
+		"files/uploads/a.txt":       "hello",
+		"files/uploads/sub/b.png":   "\x00\x01\x02\x03",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("entries = %q", got)
+	}
+}
+
+func TestVerifyDetectsCorruption(t *testing.T) {
+	data := buildArchive(t)
+
+	truncated := data[:len(data)/2]
+	if _, err := Verify(bytes.NewReader(truncated)); err == nil {
+		t.Fatal("truncated archive should fail verification")
+	}
+
+	flipped := append([]byte{}, data...)
+	flipped[len(flipped)/2] ^= 0xff
+	if _, err := Verify(bytes.NewReader(flipped)); err == nil {
+		t.Fatal("corrupted archive should fail verification")
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Manifest{})
+	w.AddBytes("a.txt", []byte("a"))
+	w.manifest.Files[0].SHA256 = strings.Repeat("0", 64)
+	w.Close()
+	if _, err := Verify(&buf); err == nil || !strings.Contains(err.Error(), "a.txt") {
+		t.Fatalf("checksum mismatch err = %v", err)
+	}
+}
+
+func TestCheckEntryName(t *testing.T) {
+	for _, name := range []string{"../etc/passwd", "/abs", "a/../../b", "", "a//b"} {
+		if checkEntryName(name) == nil {
+			t.Errorf("%q should be rejected", name)
+		}
+	}
+	if err := checkEntryName("files/uploads/a.png"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocalStorage(filepath.Join(t.TempDir(), "backups"))
+
+	if objects, err := s.List(ctx); err != nil || len(objects) != 0 {
+		t.Fatalf("List empty = %v, %v", objects, err)
+	}
+	names := []string{
+		ArchiveName(time.Date(2026, 1, 2, 3, 0, 0, 0, time.Local)),
+		ArchiveName(time.Date(2026, 1, 1, 3, 0, 0, 0, time.Local)),
+	}
+	for _, name := range names {
+		if err := s.Put(ctx, name, strings.NewReader(name), int64(len(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put(ctx, "../evil.tar.gz", strings.NewReader("x"), 1); err == nil {
+		t.Fatal("invalid name should be rejected")
+	}
+
+	objects, err := s.List(ctx)
+	if err != nil || len(objects) != 2 || objects[0].Name != names[1] {
+		t.Fatalf("List = %v, %v", objects, err)
+	}
+	r, err := s.Get(ctx, names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(r)
+	r.Close()
+	if string(b) != names[0] {
+		t.Fatalf("Get = %q", b)
+	}
+	if err := s.Delete(ctx, names[0]); err != nil {
+		t.Fatal(err)
+	}
+	if objects, _ := s.List(ctx); len(objects) != 1 {
+		t.Fatalf("after delete = %v", objects)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	base := time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local) // 周一
+	var objects []Object
+	// 14 天，每天 02:00 和 14:00 各一个备份
+	for d := 0; d < 14; d++ {
+		for _, h := range []int{2, 14} {
+			objects = append(objects, Object{Name: ArchiveName(base.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour))})
+		}
+	}
+	objects = append(objects, Object{Name: "other.tar.gz"})
+
+	keep, remove := Prune(objects, RetentionPolicy{KeepLast: 3, KeepDaily: 2, KeepWeekly: 2})
+	var kept []string
+	for _, o := range keep {
+		kept = append(kept, o.Name)
+	}
+	sort.Strings(kept)
+	want := []string{
+		"other.tar.gz",
+		"urldb-20260322-140000.tar.gz", // 第一周最后一个
+		"urldb-20260328-140000.tar.gz", // 每日
+		"urldb-20260329-020000.tar.gz", // 最近 3 个
+		"urldb-20260329-140000.tar.gz",
+	}
+	if !reflect.DeepEqual(kept, want) {
+		t.Fatalf("keep = %v", kept)
+	}
+	if len(keep)+len(remove) != len(objects) {
+		t.Fatalf("keep %d + remove %d != %d", len(keep), len(remove), len(objects))
+	}
+
+	if keep, remove := Prune(objects, RetentionPolicy{}); len(keep) != len(objects) || remove != nil {
+		t.Fatal("empty policy should keep everything")
+	}
+}
+
+func TestSortTables(t *testing.T) {
+	tables := []string{"resource_tags", "resources", "categories", "tags", "a", "b"}
+	deps := map[string][]string{
+		"resources":     {"categories", "resources"}, // 自引用不影响排序
+		"resource_tags": {"resources", "tags"},
+		"a":             {"b"},
+		"b":             {"a"},
+	}
+	got := sortTables(tables, deps)
+	pos := map[string]int{}
+	for i, name := range got {
+		pos[name] = i
+	}
+	if len(got) != len(tables) {
+		t.Fatalf("sortTables = %v", got)
+	}
+	if pos["categories"] > pos["resources"] || pos["resources"] > pos["resource_tags"] || pos["tags"] > pos["resource_tags"] {
+		t.Fatalf("dependency order violated: %v", got)
+	}
+}
+
+// 使用 AWS SigV4 测试套件中的 get-vanilla 用例
+func TestSignV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	signV4(req, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Fatalf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// fakeS3 最小的路径风格 S3 实现，用于代替 MinIO
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+		r.Header.Get("X-Amz-Content-Sha256") == "" {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		prefix := r.URL.Query().Get("prefix")
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		// 每页一个对象，覆盖分页逻辑
+		start := 0
+		if token := r.URL.Query().Get("continuation-token"); token != "" {
+			for i, k := range keys {
+				if k == token {
+					start = i
+				}
+			}
+		}
+		type content struct {
+			Key          string
+			Size         int
+			LastModified time.Time
+		}
+		result := struct {
+			XMLName               xml.Name `xml:"ListBucketResult"`
+			Contents              []content
+			IsTruncated           bool
+			NextContinuationToken string `xml:",omitempty"`
+		}{}
+		if start < len(keys) {
+			result.Contents = []content{{Key: keys[start], Size: len(f.objects[keys[start]]), LastModified: time.Now().UTC()}}
+		}
+		if start+1 < len(keys) {
+			result.IsTruncated = true
+			result.NextContinuationToken = keys[start+1]
+		}
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		f.objects[key] = b
+	case r.Method == http.MethodGet:
+		b, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(b)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusBadRequest)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: map[string][]byte{"other/file.txt": []byte("x")}})
+	defer server.Close()
+
+	s, err := NewS3Storage(S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "bucket",
+		Prefix:    "/urldb/",
+		AccessKey: "key",
+		SecretKey: "secret",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	archive := buildArchive(t)
+	names := []string{
+		ArchiveName(time.Date(2026, 1, 1, 3, 0, 0, 0, time.Local)),
+		ArchiveName(time.Date(2026, 1, 2, 3, 0, 0, 0, time.Local)),
+		ArchiveName(time.Date(2026, 1, 3, 3, 0, 0, 0, time.Local)),
+	}
+	for _, name := range names {
+		if err := s.Put(ctx, name, bytes.NewReader(archive), int64(len(archive))); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	objects, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(objects) != 3 || objects[0].Name != names[0] || objects[2].Size != int64(len(archive)) {
+		t.Fatalf("List = %+v", objects)
+	}
+
+	r, err := s.Get(ctx, names[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Verify(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("Verify downloaded: %v", err)
+	}
+
+	if err := s.Delete(ctx, names[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, names[0]); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("Get deleted err = %v", err)
+	}
+
+	bad, _ := NewS3Storage(S3Config{Endpoint: server.URL, Bucket: "bucket", AccessKey: "wrong", SecretKey: "s", PathStyle: true})
+	if _, err := bad.List(ctx); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("bad credentials err = %v", err)
+	}
+}
@@ -0,0 +1,423 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// 数据库导出方式
+const (
+	// DBModeAuto 优先 pg_dump，不可用或失败时使用原生导出
+	DBModeAuto = "auto"
+	// DBModePgDump 调用 pg_dump 生成 SQL，恢复时调用 psql
+	DBModePgDump = "pg_dump"
+	// DBModeNative 通过 COPY 逐表导出数据，恢复时按外键顺序导入到已有表结构
+	DBModeNative = "native"
+)
+
+// 数据库部分在归档中的路径
+const (
+	dumpEntry   = PrefixDatabase + "dump.sql"
+	tablePrefix = PrefixDatabase + "tables/"
+	tableSuffix = ".copy"
+)
+
+// IsDBMode 是否为支持的导出方式
+func IsDBMode(mode string) bool {
+	return mode == DBModeAuto || mode == DBModePgDump || mode == DBModeNative
+}
+
+// RestoreStats 数据库恢复结果
+type RestoreStats struct {
+	Mode   string `json:"mode"`
+	Tables int    `json:"tables"`
+	Rows   int64  `json:"rows"`
+	// Skipped 备份中存在但当前数据库中没有的表
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Postgres PostgreSQL 逻辑备份
+type Postgres struct {
+	DSN string
+}
+
+// NewPostgres 创建 PostgreSQL 备份，dsn 为 libpq 格式
+func NewPostgres(dsn string) *Postgres {
+	return &Postgres{DSN: dsn}
+}
+
+// Dump 按 mode 导出数据库到归档，并在清单中记录实际使用的导出方式
+func (p *Postgres) Dump(ctx context.Context, w *Writer, mode string) error {
+	switch mode {
+	case DBModePgDump:
+		return p.dumpPgDump(ctx, w)
+	case DBModeNative:
+		return p.dumpNative(ctx, w)
+	case DBModeAuto, "":
+		if _, err := exec.LookPath("pg_dump"); err == nil {
+			// pg_dump 只在成功后才写入归档，失败时（如客户端版本低于服务端）可以安全回退
+			if err := p.dumpPgDump(ctx, w); err == nil {
+				return nil
+			}
+		}
+		return p.dumpNative(ctx, w)
+	default:
+		return fmt.Errorf("不支持的数据库导出方式: %s", mode)
+	}
+}
+
+// Restore 按清单记录的导出方式从归档恢复数据库，r 为整个归档
+func (p *Postgres) Restore(ctx context.Context, r io.Reader, manifest *Manifest) (*RestoreStats, error) {
+	switch manifest.DBMode {
+	case DBModePgDump:
+		return p.restorePgDump(ctx, r)
+	case DBModeNative:
+		return p.restoreNative(ctx, r, manifest)
+	case "":
+		return nil, fmt.Errorf("该备份不包含数据库")
+	default:
+		return nil, fmt.Errorf("不支持的数据库导出方式: %s", manifest.DBMode)
+	}
+}
+
+// dumpPgDump 调用 pg_dump 导出为带 DROP 语句的 SQL
+func (p *Postgres) dumpPgDump(ctx context.Context, w *Writer) error {
+	tmp, err := os.CreateTemp("", "urldb-dump-*.sql")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := runCommand(ctx, "pg_dump", "--dbname="+p.DSN, "--clean", "--if-exists", "--no-owner", "--no-privileges", "--file="+tmp.Name()); err != nil {
+		return err
+	}
+	if err := w.AddLocalFile(dumpEntry, tmp.Name()); err != nil {
+		return err
+	}
+	w.Manifest().DBMode = DBModePgDump
+	return nil
+}
+
+// restorePgDump 在单个事务中用 psql 执行 SQL
+func (p *Postgres) restorePgDump(ctx context.Context, r io.Reader) (*RestoreStats, error) {
+	tmp, err := os.CreateTemp("", "urldb-restore-*.sql")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	found := false
+	err = Walk(r, func(name string, size int64, entry io.Reader) error {
+		if name != dumpEntry {
+			return nil
+		}
+		found = true
+		_, err := io.Copy(tmp, entry)
+		return err
+	})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("备份归档缺少 %s", dumpEntry)
+	}
+
+	if err := runCommand(ctx, "psql", "--dbname="+p.DSN, "--quiet", "-v", "ON_ERROR_STOP=1", "--single-transaction", "--file="+tmp.Name()); err != nil {
+		return nil, err
+	}
+	return &RestoreStats{Mode: DBModePgDump}, nil
+}
+
+// dumpNative 在可重复读的只读事务中逐表 COPY，保证各表数据来自同一快照
+func (p *Postgres) dumpNative(ctx context.Context, w *Writer) error {
+	conn, err := pgx.Connect(ctx, p.DSN)
+	if err != nil {
+		return fmt.Errorf("连接数据库失败: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	var infos []TableInfo
+	for _, table := range tables {
+		columns, err := tableColumns(ctx, tx, table)
+		if err != nil {
+			return err
+		}
+		rows, err := copyTableToArchive(ctx, conn, w, table, columns)
+		if err != nil {
+			return fmt.Errorf("导出表 %s 失败: %v", table, err)
+		}
+		infos = append(infos, TableInfo{Name: table, Columns: columns, Rows: rows})
+	}
+
+	w.Manifest().DBMode = DBModeNative
+	w.Manifest().Tables = infos
+	return nil
+}
+
+// copyTableToArchive COPY 到临时文件（归档条目需要预先知道大小）后写入归档
+func copyTableToArchive(ctx context.Context, conn *pgx.Conn, w *Writer, table string, columns []string) (int64, error) {
+	tmp, err := os.CreateTemp("", "urldb-table-*.copy")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	tag, err := conn.PgConn().CopyTo(ctx, tmp, fmt.Sprintf("COPY %s (%s) TO STDOUT", quoteIdent(table), quoteIdents(columns)))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := w.AddLocalFile(tablePrefix+table+tableSuffix, tmp.Name()); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// restoreNative 在单个事务中清空备份涉及的表并按归档顺序（即外键依赖顺序）导入，最后重置序列
+func (p *Postgres) restoreNative(ctx context.Context, r io.Reader, manifest *Manifest) (*RestoreStats, error) {
+	conn, err := pgx.Connect(ctx, p.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	// 需要超级用户权限，失败时依赖导入顺序满足外键约束
+	_, _ = conn.Exec(ctx, "SET session_replication_role = replica")
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	existing, err := listTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		exists[t] = true
+	}
+
+	stats := &RestoreStats{Mode: DBModeNative}
+	tables := make(map[string]TableInfo, len(manifest.Tables))
+	var targets []string
+	for _, t := range manifest.Tables {
+		if !exists[t.Name] {
+			stats.Skipped = append(stats.Skipped, t.Name)
+			continue
+		}
+		tables[t.Name] = t
+		targets = append(targets, quoteIdent(t.Name))
+	}
+	if len(targets) > 0 {
+		if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(targets, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+			return nil, fmt.Errorf("清空数据表失败: %v", err)
+		}
+	}
+
+	err = Walk(r, func(name string, size int64, entry io.Reader) error {
+		if !strings.HasPrefix(name, tablePrefix) || !strings.HasSuffix(name, tableSuffix) {
+			return nil
+		}
+		info, ok := tables[strings.TrimSuffix(strings.TrimPrefix(name, tablePrefix), tableSuffix)]
+		if !ok {
+			return nil
+		}
+		tag, err := conn.PgConn().CopyFrom(ctx, entry, fmt.Sprintf("COPY %s (%s) FROM STDIN", quoteIdent(info.Name), quoteIdents(info.Columns)))
+		if err != nil {
+			return fmt.Errorf("导入表 %s 失败: %v", info.Name, err)
+		}
+		if tag.RowsAffected() != info.Rows {
+			return fmt.Errorf("导入表 %s 行数 %d 与备份记录的 %d 不一致", info.Name, tag.RowsAffected(), info.Rows)
+		}
+		stats.Tables++
+		stats.Rows += info.Rows
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stats.Tables != len(tables) {
+		return nil, fmt.Errorf("备份归档中的数据表不完整: 已导入 %d / %d", stats.Tables, len(tables))
+	}
+	if err := resetSequences(ctx, tx, tables); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// listTables public 模式下的表，按外键依赖排序（被引用的表在前）
+func listTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, "SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY tablename")
+	if err != nil {
+		return nil, err
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `SELECT src.relname, dst.relname
+		FROM pg_constraint c
+		JOIN pg_class src ON src.oid = c.conrelid
+		JOIN pg_class dst ON dst.oid = c.confrelid
+		JOIN pg_namespace n ON n.oid = src.relnamespace
+		WHERE c.contype = 'f' AND n.nspname = 'public'`)
+	if err != nil {
+		return nil, err
+	}
+	deps := make(map[string][]string)
+	var from, to string
+	_, err = pgx.ForEachRow(rows, []any{&from, &to}, func() error {
+		deps[from] = append(deps[from], to)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortTables(tables, deps), nil
+}
+
+// sortTables 拓扑排序，deps[t] 为 t 引用的表；存在循环引用的表按名称追加在末尾
+func sortTables(tables []string, deps map[string][]string) []string {
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t] = true
+	}
+	sorted := make([]string, 0, len(tables))
+	done := make(map[string]bool, len(tables))
+	for len(sorted) < len(tables) {
+		progressed := false
+		for _, t := range tables {
+			if done[t] {
+				continue
+			}
+			ready := true
+			for _, d := range deps[t] {
+				if d != t && known[d] && !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[t] = true
+				sorted = append(sorted, t)
+				progressed = true
+			}
+		}
+		if !progressed {
+			var rest []string
+			for _, t := range tables {
+				if !done[t] {
+					rest = append(rest, t)
+				}
+			}
+			sort.Strings(rest)
+			return append(sorted, rest...)
+		}
+	}
+	return sorted
+}
+
+// tableColumns 表的列（不含生成列），按定义顺序
+func tableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx, `SELECT a.attname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1
+			AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+		ORDER BY a.attnum`, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// resetSequences 将恢复表上的自增序列设置为当前最大值之后
+func resetSequences(ctx context.Context, tx pgx.Tx, tables map[string]TableInfo) error {
+	rows, err := tx.Query(ctx, `SELECT t.relname, a.attname, s.relname
+		FROM pg_depend d
+		JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		JOIN pg_class t ON t.oid = d.refobjid
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = d.refobjsubid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = 'public' AND d.deptype IN ('a', 'i')`)
+	if err != nil {
+		return err
+	}
+	type sequence struct{ table, column, name string }
+	var sequences []sequence
+	var seq sequence
+	_, err = pgx.ForEachRow(rows, []any{&seq.table, &seq.column, &seq.name}, func() error {
+		if _, ok := tables[seq.table]; ok {
+			sequences = append(sequences, seq)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, s := range sequences {
+		sql := fmt.Sprintf("SELECT setval($1, COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)", quoteIdent(s.column), quoteIdent(s.table))
+		if _, err := tx.Exec(ctx, sql, quoteIdent(s.name)); err != nil {
+			return fmt.Errorf("重置序列 %s 失败: %v", s.name, err)
+		}
+	}
+	return nil
+}
+
+// runCommand 执行外部命令，失败时附带标准错误输出
+func runCommand(ctx context.Context, name string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		return fmt.Errorf("%s 执行失败: %v %s", name, err, msg)
+	}
+	return nil
+}
+
+func quoteIdent(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
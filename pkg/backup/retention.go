@@ -0,0 +1,57 @@
+package backup
+
+import "fmt"
+
+// RetentionPolicy 保留规则，三条规则取并集，全部为 0 时保留所有备份
+type RetentionPolicy struct {
+	// KeepLast 保留最近的 N 个备份
+	KeepLast int `json:"keep_last"`
+	// KeepDaily 最近 N 个有备份的自然日，每天保留最后一个
+	KeepDaily int `json:"keep_daily"`
+	// KeepWeekly 最近 N 个有备份的 ISO 周，每周保留最后一个
+	KeepWeekly int `json:"keep_weekly"`
+}
+
+// Prune 按保留规则划分要保留和删除的备份，名称无法解析时间的对象始终保留
+func Prune(objects []Object, policy RetentionPolicy) (keep, remove []Object) {
+	if policy.KeepLast <= 0 && policy.KeepDaily <= 0 && policy.KeepWeekly <= 0 {
+		return objects, nil
+	}
+
+	sorted := sortObjects(append([]Object{}, objects...))
+	kept := make(map[string]bool)
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	last := 0
+	// 从新到旧遍历，每个周期第一次遇到的即为该周期最后一个备份
+	for i := len(sorted) - 1; i >= 0; i-- {
+		o := sorted[i]
+		t, ok := ArchiveTime(o.Name)
+		if !ok {
+			kept[o.Name] = true
+			continue
+		}
+		if last < policy.KeepLast {
+			kept[o.Name] = true
+			last++
+		}
+		if day := t.Format("2006-01-02"); !days[day] && len(days) < policy.KeepDaily {
+			days[day] = true
+			kept[o.Name] = true
+		}
+		year, week := t.ISOWeek()
+		if key := fmt.Sprintf("%d-%02d", year, week); !weeks[key] && len(weeks) < policy.KeepWeekly {
+			weeks[key] = true
+			kept[o.Name] = true
+		}
+	}
+
+	for _, o := range sorted {
+		if kept[o.Name] {
+			keep = append(keep, o)
+		} else {
+			remove = append(remove, o)
+		}
+	}
+	return keep, remove
+}
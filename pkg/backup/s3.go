@@ -0,0 +1,320 @@
+package backup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload 上传大文件时不对内容做签名摘要（S3 与 MinIO 均支持）
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// emptyPayloadHash 空内容的 SHA-256
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config S3 兼容存储配置
+type S3Config struct {
+	// Endpoint 服务地址，如 https://s3.amazonaws.com 或 http://127.0.0.1:9000（MinIO）
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	// PathStyle 使用 endpoint/bucket/key 形式的地址，MinIO 等自建服务通常需要开启
+	PathStyle bool
+}
+
+// S3Storage S3 兼容对象存储，使用 AWS Signature V4 签名
+type S3Storage struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Storage 创建 S3 兼容存储
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 存储需要配置 endpoint 和 bucket")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3 存储需要配置 access key 和 secret key")
+	}
+	base, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("无效的 S3 endpoint: %s", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	if cfg.Prefix != "" {
+		cfg.Prefix += "/"
+	}
+	return &S3Storage{cfg: cfg, base: base, client: &http.Client{}, now: time.Now}, nil
+}
+
+// Kind 存储类型
+func (s *S3Storage) Kind() string {
+	return "s3"
+}
+
+// Put 单次 PUT 上传（单个对象最大 5GB）
+func (s *S3Storage) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := checkArchiveName(name); err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, s.cfg.Prefix+name, nil, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get 下载备份归档
+func (s *S3Storage) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkArchiveName(name); err != nil {
+		return nil, err
+	}
+	req, err := s.newRequest(ctx, http.MethodGet, s.cfg.Prefix+name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// listBucketResult ListObjectsV2 响应
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List 分页列出前缀下的备份归档
+func (s *S3Storage) List(ctx context.Context) ([]Object, error) {
+	objects := []Object{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req, emptyPayloadHash)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("解析 S3 列表响应失败: %v", err)
+		}
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, s.cfg.Prefix)
+			if _, ok := ArchiveTime(name); ok && !strings.Contains(name, "/") {
+				objects = append(objects, Object{Name: name, Size: c.Size, ModTime: c.LastModified})
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return sortObjects(objects), nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Delete 删除备份归档
+func (s *S3Storage) Delete(ctx context.Context, name string) error {
+	if err := checkArchiveName(name); err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodDelete, s.cfg.Prefix+name, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// newRequest 构造对象（key 为空时为桶）请求地址
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := *s.base
+	escapedKey := ""
+	if key != "" {
+		escapedKey = "/" + uriEncodePath(key)
+	}
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + s.cfg.Bucket + escapedKey
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = strings.TrimRight(u.Path, "/") + escapedKey
+		if u.Path == "" {
+			u.Path = "/"
+		}
+	}
+	u.RawPath = u.Path
+	if query != nil {
+		u.RawQuery = canonicalQuery(query)
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do 签名并发送请求，非 2xx 响应转换为错误
+func (s *S3Storage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signV4(req, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, "s3", s.now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求 S3 失败: %v", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("S3 %s %s 返回 %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// signV4 按 AWS Signature Version 4 为请求添加 Authorization 头。
+// 签名的头部为 host、x-amz-* 以及 content-type；内容摘要取 X-Amz-Content-Sha256，缺省为空内容摘要。
+func signV4(req *http.Request, accessKey, secretKey, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = emptyPayloadHash
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery 按键排序并按 RFC 3986 编码的查询串
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncodePath 逐段编码对象 key，保留 /
+func uriEncodePath(key string) string {
+	return uriEncode(key, false)
+}
+
+// uriEncode AWS 要求的编码：只保留 A-Z a-z 0-9 - . _ ~，encodeSlash 为 false 时保留 /
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,145 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveExt 备份归档扩展名
+const ArchiveExt = ".tar.gz"
+
+// archiveTimeLayout 备份名中的时间格式
+const archiveTimeLayout = "20060102-150405"
+
+// Object 存储中的一个备份
+type Object struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Storage 备份存储：本地目录或 S3 兼容对象存储
+type Storage interface {
+	// Kind 存储类型，用于展示
+	Kind() string
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List 列出所有备份归档，按名称（即时间）升序
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// ArchiveName 按时间生成备份名，如 urldb-20260101-030000.tar.gz
+func ArchiveName(t time.Time) string {
+	return "urldb-" + t.Format(archiveTimeLayout) + ArchiveExt
+}
+
+// ArchiveTime 从备份名解析备份时间（本地时区）
+func ArchiveTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, "urldb-") || !strings.HasSuffix(name, ArchiveExt) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(archiveTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, "urldb-"), ArchiveExt), time.Local)
+	return t, err == nil
+}
+
+// checkArchiveName 备份名只能是 ArchiveName 生成的格式，防止路径穿越
+func checkArchiveName(name string) error {
+	if _, ok := ArchiveTime(name); !ok || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("无效的备份名: %s", name)
+	}
+	return nil
+}
+
+func sortObjects(objects []Object) []Object {
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects
+}
+
+// LocalStorage 本地目录存储
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage 创建本地目录存储
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{dir: dir}
+}
+
+// Kind 存储类型
+func (s *LocalStorage) Kind() string {
+	return "local"
+}
+
+// Dir 备份目录
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Put 先写临时文件再改名，避免留下不完整的归档
+func (s *LocalStorage) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := checkArchiveName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// Get 打开备份归档
+func (s *LocalStorage) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkArchiveName(name); err != nil {
+		return nil, err
+	}
+	return os.Open(filepath.Join(s.dir, name))
+}
+
+// List 列出目录中的备份归档
+func (s *LocalStorage) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []Object{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	objects := []Object{}
+	for _, e := range entries {
+		if _, ok := ArchiveTime(e.Name()); !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return sortObjects(objects), nil
+}
+
+// Delete 删除备份归档
+func (s *LocalStorage) Delete(ctx context.Context, name string) error {
+	if err := checkArchiveName(name); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(s.dir, name))
+}
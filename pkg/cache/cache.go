@@ -0,0 +1,307 @@
+// Package cache 两级缓存：进程内 LRU + 可选的 Redis 共享层。
+// 读取依次查询本地与 Redis，均未命中时通过 singleflight 合并并发回源；
+// 写入时登记标签，仓储层写库后按标签失效，Redis 启用时通过发布订阅通知其他副本清理本地层
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultTTL 未指定 TTL 时的默认过期时间
+const DefaultTTL = 5 * time.Minute
+
+// Remote 共享缓存层（Redis），值为 JSON 编码
+type Remote interface {
+	// Get 读取缓存，不存在时 ok 为 false
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set 写入缓存并登记索引
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, indexes []string) error
+	// Delete 删除缓存键
+	Delete(ctx context.Context, keys ...string) error
+	// Invalidate 删除索引下的缓存项，并通知其他副本
+	Invalidate(ctx context.Context, indexes []string) error
+	// Subscribe 接收其他副本的失效通知，直到 ctx 结束
+	Subscribe(ctx context.Context, fn func(indexes []string))
+	// Close 关闭连接
+	Close() error
+}
+
+// Config 缓存配置
+type Config struct {
+	// LocalSize 本地层最多保存的条目数
+	LocalSize int
+	// LocalMaxTTL 本地层条目的最长存活时间，0 表示与写入 TTL 相同。
+	// 启用 Redis 时建议设置，漏收失效通知的副本最多在该时间后与共享层一致
+	LocalMaxTTL time.Duration
+	// Remote 共享层，为空时仅使用本地层
+	Remote Remote
+	// OnError Redis 层出错时回调（缓存降级为仅本地，不影响请求），为空时忽略
+	OnError func(op string, err error)
+}
+
+// Options 单次读取的选项
+type Options struct {
+	// TTL 过期时间，0 表示 DefaultTTL
+	TTL time.Duration
+	// Tags 缓存项依赖的标签，见 Tag、AllTag
+	Tags []string
+}
+
+// invalidationListener 按标签类型注册的失效回调
+type invalidationListener struct {
+	kind string
+	fn   func()
+}
+
+// Cache 两级缓存，可并发使用
+type Cache struct {
+	cfg    Config
+	local  *localStore
+	remote Remote
+	group  singleflight.Group
+	now    func() time.Time
+
+	// generation 每次失效递增，回源期间发生过失效的结果不写入缓存，避免旧数据回填
+	generation atomic.Uint64
+
+	listenersMu sync.RWMutex
+	listeners   []invalidationListener
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New 创建缓存，配置了 Remote 时开始订阅其他副本的失效通知
+func New(cfg Config) *Cache {
+	if cfg.LocalSize <= 0 {
+		cfg.LocalSize = 10000
+	}
+	c := &Cache{
+		cfg:    cfg,
+		local:  newLocalStore(cfg.LocalSize),
+		remote: cfg.Remote,
+		now:    time.Now,
+	}
+	if c.remote != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.cancel = cancel
+		c.done = make(chan struct{})
+		go func() {
+			defer close(c.done)
+			c.remote.Subscribe(ctx, func(indexes []string) {
+				c.generation.Add(1)
+				c.local.invalidate(indexes)
+				for _, index := range indexes {
+					invalidationsTotal.WithLabelValues(keyGroup(index), "remote").Inc()
+				}
+				c.notifyInvalidated(indexes)
+			})
+		}()
+	}
+	return c
+}
+
+// Close 停止订阅并关闭共享层连接
+func (c *Cache) Close() error {
+	if c.remote == nil {
+		return nil
+	}
+	c.cancel()
+	<-c.done
+	return c.remote.Close()
+}
+
+// GetOrLoad 读取缓存，两级均未命中时调用 load 回源并写入缓存。
+// 同一个键的并发回源只执行一次；load 返回错误时不缓存
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, opts Options, load func(ctx context.Context) (T, error)) (T, error) {
+	group := keyGroup(key)
+	if value, ok := c.local.get(key, c.now()); ok {
+		if typed, ok := value.(T); ok {
+			requestsTotal.WithLabelValues(group, tierLocal, "hit").Inc()
+			return typed, nil
+		}
+	}
+	requestsTotal.WithLabelValues(group, tierLocal, "miss").Inc()
+
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		ttl := opts.TTL
+		if ttl <= 0 {
+			ttl = DefaultTTL
+		}
+		indexes := entryIndexes(opts.Tags)
+
+		if c.remote != nil {
+			raw, ok, err := c.remote.Get(ctx, key)
+			switch {
+			case err != nil:
+				c.remoteError("get", err)
+			case ok:
+				var value T
+				if err := json.Unmarshal(raw, &value); err == nil {
+					requestsTotal.WithLabelValues(group, tierRemote, "hit").Inc()
+					c.local.set(key, value, c.localExpiry(ttl), indexes)
+					return value, nil
+				}
+				c.remoteError("decode", err)
+			}
+			requestsTotal.WithLabelValues(group, tierRemote, "miss").Inc()
+		}
+
+		generation := c.generation.Load()
+		start := time.Now()
+		value, err := load(ctx)
+		loadDuration.WithLabelValues(group).Observe(time.Since(start).Seconds())
+		if err != nil {
+			loadsTotal.WithLabelValues(group, "error").Inc()
+			return value, err
+		}
+		loadsTotal.WithLabelValues(group, "ok").Inc()
+
+		if c.generation.Load() != generation {
+			return value, nil
+		}
+		c.local.set(key, value, c.localExpiry(ttl), indexes)
+		if c.remote != nil {
+			if raw, err := json.Marshal(value); err != nil {
+				c.remoteError("encode", err)
+			} else if err := c.remote.Set(ctx, key, raw, ttl, indexes); err != nil {
+				c.remoteError("set", err)
+			}
+		}
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	typed, ok := result.(T)
+	if !ok {
+		var zero T
+		return zero, errors.New("cache: 同一个键被用于不同类型: " + key)
+	}
+	return typed, nil
+}
+
+// Delete 删除指定缓存键
+func (c *Cache) Delete(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	c.generation.Add(1)
+	c.local.delete(keys...)
+	if c.remote != nil {
+		if err := c.remote.Delete(ctx, keys...); err != nil {
+			c.remoteError("delete", err)
+		}
+	}
+}
+
+// Invalidate 按标签失效缓存，标签规则见 Tag
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) {
+	indexes := invalidationIndexes(tags)
+	if len(indexes) == 0 {
+		return
+	}
+	c.generation.Add(1)
+	c.local.invalidate(indexes)
+	for _, tag := range tags {
+		invalidationsTotal.WithLabelValues(keyGroup(tag), "local").Inc()
+	}
+	c.notifyInvalidated(indexes)
+	if c.remote != nil {
+		if err := c.remote.Invalidate(ctx, indexes); err != nil {
+			c.remoteError("invalidate", err)
+		}
+	}
+}
+
+// OnInvalidate 注册失效回调：本副本按标签失效、清空本地层或收到其他副本的失效通知时，
+// 涉及 kind 类型标签（见 Tag、AllTag）的失效都会同步调用 fn。
+// 用于让缓存之外的进程内快照（如每次请求都读取的权限表）随标签失效
+func (c *Cache) OnInvalidate(kind string, fn func()) {
+	c.listenersMu.Lock()
+	defer c.listenersMu.Unlock()
+	c.listeners = append(c.listeners, invalidationListener{kind: kind + ":", fn: fn})
+}
+
+// notifyInvalidated 调用与失效索引类型匹配的回调
+func (c *Cache) notifyInvalidated(indexes []string) {
+	c.listenersMu.RLock()
+	listeners := c.listeners
+	c.listenersMu.RUnlock()
+	for _, l := range listeners {
+		for _, index := range indexes {
+			if tagGroup(index) == l.kind {
+				l.fn()
+				break
+			}
+		}
+	}
+}
+
+// PurgeExpired 清理本地层已过期的条目，返回清理数量
+func (c *Cache) PurgeExpired() int {
+	return c.local.purgeExpired(c.now())
+}
+
+// Clear 清空本地层，并调用全部失效回调
+func (c *Cache) Clear() {
+	c.generation.Add(1)
+	c.local.clear()
+	c.listenersMu.RLock()
+	listeners := c.listeners
+	c.listenersMu.RUnlock()
+	for _, l := range listeners {
+		l.fn()
+	}
+}
+
+// Len 返回本地层条目数
+func (c *Cache) Len() int {
+	return c.local.len()
+}
+
+// RemoteEnabled 是否启用了共享层
+func (c *Cache) RemoteEnabled() bool {
+	return c.remote != nil
+}
+
+func (c *Cache) localExpiry(ttl time.Duration) time.Time {
+	if c.cfg.LocalMaxTTL > 0 && ttl > c.cfg.LocalMaxTTL {
+		ttl = c.cfg.LocalMaxTTL
+	}
+	return c.now().Add(ttl)
+}
+
+func (c *Cache) remoteError(op string, err error) {
+	remoteErrorsTotal.WithLabelValues(op).Inc()
+	if c.cfg.OnError != nil {
+		c.cfg.OnError(op, err)
+	}
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultCache = New(Config{})
+)
+
+// Default 返回全局缓存，未配置时为仅本地层的缓存
+func Default() *Cache {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultCache
+}
+
+// SetDefault 替换全局缓存
+func SetDefault(c *Cache) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCache = c
+}
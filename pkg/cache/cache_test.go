@@ -0,0 +1,294 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRemote 内存版共享层，多个 Cache 共用同一个实例模拟多副本
+type fakeRemote struct {
+	mu          sync.Mutex
+	values      map[string][]byte
+	indexes     map[string]map[string]bool
+	subscribers []chan []string
+}
+
+func newFakeRemote() *fakeRemote {
+	return &fakeRemote{values: make(map[string][]byte), indexes: make(map[string]map[string]bool)}
+}
+
+func (f *fakeRemote) Get(_ context.Context, key string) ([]byte, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value, ok := f.values[key]
+	return value, ok, nil
+}
+
+func (f *fakeRemote) Set(_ context.Context, key string, value []byte, _ time.Duration, indexes []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = value
+	for _, index := range indexes {
+		if f.indexes[index] == nil {
+			f.indexes[index] = make(map[string]bool)
+		}
+		f.indexes[index][key] = true
+	}
+	return nil
+}
+
+func (f *fakeRemote) Delete(_ context.Context, keys ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		delete(f.values, key)
+	}
+	return nil
+}
+
+func (f *fakeRemote) Invalidate(_ context.Context, indexes []string) error {
+	f.mu.Lock()
+	for _, index := range indexes {
+		for key := range f.indexes[index] {
+			delete(f.values, key)
+		}
+		delete(f.indexes, index)
+	}
+	subscribers := append([]chan []string(nil), f.subscribers...)
+	f.mu.Unlock()
+	for _, ch := range subscribers {
+		ch <- indexes
+	}
+	return nil
+}
+
+func (f *fakeRemote) Subscribe(ctx context.Context, fn func(indexes []string)) {
+	ch := make(chan []string, 16)
+	f.mu.Lock()
+	f.subscribers = append(f.subscribers, ch)
+	f.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case indexes := <-ch:
+			fn(indexes)
+		}
+	}
+}
+
+func (f *fakeRemote) Close() error { return nil }
+
+// waitSubscribers 等待各副本完成订阅
+func (f *fakeRemote) waitSubscribers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		f.mu.Lock()
+		count := len(f.subscribers)
+		f.mu.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("subscribers did not reach %d", n)
+}
+
+func loadCounter(value string, calls *int32) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		atomic.AddInt32(calls, 1)
+		return value, nil
+	}
+}
+
+func TestGetOrLoadLocal(t *testing.T) {
+	c := New(Config{LocalSize: 2})
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	var calls int32
+	for i := 0; i < 3; i++ {
+		v, err := GetOrLoad(ctx, c, "resource:latest", Options{TTL: time.Minute}, loadCounter("a", &calls))
+		if err != nil || v != "a" {
+			t.Fatalf("GetOrLoad = %q, %v", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("loader calls = %d, want 1", calls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, _ = GetOrLoad(ctx, c, "resource:latest", Options{TTL: time.Minute}, loadCounter("a", &calls)); calls != 2 {
+		t.Fatalf("expired entry should reload, calls = %d", calls)
+	}
+
+	// 容量为 2，最久未使用的条目被淘汰
+	_, _ = GetOrLoad(ctx, c, "k:1", Options{}, loadCounter("1", &calls))
+	_, _ = GetOrLoad(ctx, c, "k:2", Options{}, loadCounter("2", &calls))
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", c.Len())
+	}
+	if _, ok := c.local.get("resource:latest", now); ok {
+		t.Fatal("least recently used entry should be evicted")
+	}
+
+	failing := func(context.Context) (string, error) { return "", errors.New("db down") }
+	if _, err := GetOrLoad(ctx, c, "k:err", Options{}, failing); err == nil {
+		t.Fatal("loader error should be returned")
+	}
+	if _, ok := c.local.get("k:err", now); ok {
+		t.Fatal("errors should not be cached")
+	}
+}
+
+func TestGetOrLoadSingleflight(t *testing.T) {
+	c := New(Config{})
+	release := make(chan struct{})
+	var calls int32
+	load := func(context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = GetOrLoad(context.Background(), c, "hot:10", Options{}, load)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("concurrent misses should load once, calls = %d", calls)
+	}
+	for _, v := range results {
+		if v != 42 {
+			t.Fatalf("results = %v", results)
+		}
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	c := New(Config{})
+	ctx := context.Background()
+	var calls int32
+	get := func(key string, tags ...string) {
+		_, _ = GetOrLoad(ctx, c, key, Options{Tags: tags}, loadCounter(key, &calls))
+	}
+	cached := func(key string) bool {
+		_, ok := c.local.get(key, time.Now())
+		return ok
+	}
+
+	get("resource:5", Tag("resource", 5))
+	get("resource:6", Tag("resource", 6))
+	get("resource:latest", AllTag("resource"))
+	get("category:list", AllTag("category"))
+
+	c.Invalidate(ctx, Tag("resource", 5))
+	if cached("resource:5") || cached("resource:latest") || !cached("resource:6") || !cached("category:list") {
+		t.Fatal("resource:5 should drop the resource and resource lists only")
+	}
+
+	get("resource:latest", AllTag("resource"))
+	c.Invalidate(ctx, AllTag("resource"))
+	if cached("resource:6") || cached("resource:latest") || !cached("category:list") {
+		t.Fatal("resource:* should drop every resource entry")
+	}
+}
+
+func TestRemoteTierAndCrossReplicaInvalidation(t *testing.T) {
+	remote := newFakeRemote()
+	a := New(Config{Remote: remote})
+	b := New(Config{Remote: remote})
+	defer a.Close()
+	defer b.Close()
+	remote.waitSubscribers(t, 2)
+	ctx := context.Background()
+
+	type item struct{ Name string }
+	var calls int32
+	load := func(context.Context) ([]item, error) {
+		atomic.AddInt32(&calls, 1)
+		return []item{{Name: "x"}}, nil
+	}
+	opts := Options{Tags: []string{AllTag("config")}}
+
+	if _, err := GetOrLoad(ctx, a, "config:all", opts, load); err != nil {
+		t.Fatal(err)
+	}
+	// 副本 b 从共享层读取，不再回源
+	got, err := GetOrLoad(ctx, b, "config:all", opts, load)
+	if err != nil || len(got) != 1 || got[0].Name != "x" || calls != 1 {
+		t.Fatalf("replica b = %+v, %v, calls = %d", got, err, calls)
+	}
+
+	a.Invalidate(ctx, Tag("config", 3))
+	deadline := time.Now().Add(time.Second)
+	for b.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if b.Len() != 0 {
+		t.Fatal("invalidation should reach other replicas")
+	}
+	if _, _ = GetOrLoad(ctx, b, "config:all", opts, load); calls != 2 {
+		t.Fatalf("invalidated entry should reload, calls = %d", calls)
+	}
+}
+
+func TestOnInvalidate(t *testing.T) {
+	remote := newFakeRemote()
+	a := New(Config{Remote: remote})
+	b := New(Config{Remote: remote})
+	defer a.Close()
+	defer b.Close()
+	remote.waitSubscribers(t, 2)
+	ctx := context.Background()
+
+	var local, remoteCalls, other atomic.Int32
+	a.OnInvalidate("role", func() { local.Add(1) })
+	b.OnInvalidate("role", func() { remoteCalls.Add(1) })
+	b.OnInvalidate("resource", func() { other.Add(1) })
+
+	a.Invalidate(ctx, Tag("role", 3))
+	if local.Load() == 0 {
+		t.Fatal("local invalidation should call the listener synchronously")
+	}
+	deadline := time.Now().Add(time.Second)
+	for remoteCalls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if remoteCalls.Load() == 0 {
+		t.Fatal("invalidation from another replica should call the listener")
+	}
+	if other.Load() != 0 {
+		t.Fatal("listeners of other kinds should not be called")
+	}
+}
+
+func TestLoadRacingInvalidationIsNotCached(t *testing.T) {
+	c := New(Config{})
+	ctx := context.Background()
+	load := func(context.Context) (string, error) {
+		c.Invalidate(ctx, AllTag("resource"))
+		return "stale", nil
+	}
+	if v, _ := GetOrLoad(ctx, c, "resource:latest", Options{Tags: []string{AllTag("resource")}}, load); v != "stale" {
+		t.Fatalf("value = %q", v)
+	}
+	if c.Len() != 0 {
+		t.Fatal("value loaded across an invalidation should not be cached")
+	}
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// localEntry 进程内缓存项，保存解码后的值，命中时无需反序列化
+type localEntry struct {
+	key     string
+	value   any
+	expires time.Time
+	indexes []string
+}
+
+// localStore 带容量上限的进程内 LRU，同时维护标签索引
+type localStore struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	items   map[string]*list.Element
+	indexes map[string]map[string]struct{}
+}
+
+func newLocalStore(size int) *localStore {
+	return &localStore{
+		size:    size,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+		indexes: make(map[string]map[string]struct{}),
+	}
+}
+
+func (s *localStore) get(key string, now time.Time) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*localEntry)
+	if !now.Before(entry.expires) {
+		s.removeElement(el)
+		return nil, false
+	}
+	s.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+func (s *localStore) set(key string, value any, expires time.Time, indexes []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		s.removeElement(el)
+	}
+	el := s.ll.PushFront(&localEntry{key: key, value: value, expires: expires, indexes: indexes})
+	s.items[key] = el
+	for _, index := range indexes {
+		keys := s.indexes[index]
+		if keys == nil {
+			keys = make(map[string]struct{})
+			s.indexes[index] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	for s.size > 0 && s.ll.Len() > s.size {
+		s.removeElement(s.ll.Back())
+	}
+}
+
+func (s *localStore) delete(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := s.items[key]; ok {
+			s.removeElement(el)
+		}
+	}
+}
+
+// invalidate 删除索引下的全部缓存项，返回删除数量
+func (s *localStore) invalidate(indexes []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, index := range indexes {
+		for key := range s.indexes[index] {
+			if el, ok := s.items[key]; ok {
+				s.removeElement(el)
+				removed++
+			}
+		}
+		delete(s.indexes, index)
+	}
+	return removed
+}
+
+// purgeExpired 清理已过期的缓存项
+func (s *localStore) purgeExpired(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for el := s.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if !now.Before(el.Value.(*localEntry).expires) {
+			s.removeElement(el)
+			removed++
+		}
+		el = prev
+	}
+	return removed
+}
+
+func (s *localStore) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ll.Init()
+	s.items = make(map[string]*list.Element)
+	s.indexes = make(map[string]map[string]struct{})
+}
+
+func (s *localStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+func (s *localStore) removeElement(el *list.Element) {
+	entry := s.ll.Remove(el).(*localEntry)
+	delete(s.items, entry.key)
+	for _, index := range entry.indexes {
+		if keys := s.indexes[index]; keys != nil {
+			delete(keys, entry.key)
+			if len(keys) == 0 {
+				delete(s.indexes, index)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// requestsTotal 各层缓存命中/未命中次数，group 为缓存键第一个冒号前的部分
+	requestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "urldb",
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Cache lookups by key group, tier and result",
+		},
+		[]string{"group", "tier", "result"},
+	)
+
+	// loadsTotal 缓存未命中时回源加载次数
+	loadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "urldb",
+			Subsystem: "cache",
+			Name:      "loads_total",
+			Help:      "Cache loader calls by key group and result",
+		},
+		[]string{"group", "result"},
+	)
+
+	// loadDuration 回源加载耗时
+	loadDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "urldb",
+			Subsystem: "cache",
+			Name:      "load_duration_seconds",
+			Help:      "Cache loader latency by key group",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"group"},
+	)
+
+	// invalidationsTotal 标签失效次数
+	invalidationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "urldb",
+			Subsystem: "cache",
+			Name:      "invalidations_total",
+			Help:      "Tag invalidations by tag kind and origin",
+		},
+		[]string{"kind", "origin"},
+	)
+
+	// remoteErrorsTotal Redis 层错误次数
+	remoteErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "urldb",
+			Subsystem: "cache",
+			Name:      "remote_errors_total",
+			Help:      "Remote tier errors by operation",
+		},
+		[]string{"op"},
+	)
+)
+
+const (
+	tierLocal  = "local"
+	tierRemote = "redis"
+)
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// indexTTLFloor 索引集合的最短过期时间，集合随缓存项写入续期
+const indexTTLFloor = time.Hour
+
+// RedisConfig Redis 共享层配置
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix 键前缀，多个应用共用同一个 Redis 时区分
+	Prefix string
+	// Channel 失效通知的发布订阅通道
+	Channel string
+}
+
+// RedisRemote 基于 Redis 的共享层：缓存值为字符串键，标签索引为集合，
+// 失效时删除索引下的键并在 Channel 上发布索引列表
+type RedisRemote struct {
+	client  *redis.Client
+	prefix  string
+	channel string
+}
+
+// NewRedisRemote 连接 Redis，连接失败时返回错误
+func NewRedisRemote(cfg RedisConfig) (*RedisRemote, error) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "urldb:cache:"
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = cfg.Prefix + "invalidate"
+	}
+	client := redis.NewClient(&redis.Options{Addr: cfg.Addr, Password: cfg.Password, DB: cfg.DB})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisRemote{client: client, prefix: cfg.Prefix, channel: cfg.Channel}, nil
+}
+
+func (r *RedisRemote) valueKey(key string) string {
+	return r.prefix + "v:" + key
+}
+
+func (r *RedisRemote) indexKey(index string) string {
+	return r.prefix + "i:" + index
+}
+
+// Get 读取缓存值
+func (r *RedisRemote) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, r.valueKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set 写入缓存值并把键加入各索引集合
+func (r *RedisRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration, indexes []string) error {
+	indexTTL := ttl
+	if indexTTL < indexTTLFloor {
+		indexTTL = indexTTLFloor
+	}
+	valueKey := r.valueKey(key)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, valueKey, value, ttl)
+		for _, index := range indexes {
+			pipe.SAdd(ctx, r.indexKey(index), valueKey)
+			pipe.Expire(ctx, r.indexKey(index), indexTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// Delete 删除缓存键
+func (r *RedisRemote) Delete(ctx context.Context, keys ...string) error {
+	valueKeys := make([]string, len(keys))
+	for i, key := range keys {
+		valueKeys[i] = r.valueKey(key)
+	}
+	return r.client.Del(ctx, valueKeys...).Err()
+}
+
+// Invalidate 删除索引集合中的全部键并发布失效通知
+func (r *RedisRemote) Invalidate(ctx context.Context, indexes []string) error {
+	for _, index := range indexes {
+		indexKey := r.indexKey(index)
+		members, err := r.client.SMembers(ctx, indexKey).Result()
+		if err != nil {
+			return err
+		}
+		if err := r.client.Del(ctx, append(members, indexKey)...).Err(); err != nil {
+			return err
+		}
+	}
+	payload, err := json.Marshal(indexes)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, r.channel, payload).Err()
+}
+
+// Subscribe 订阅失效通知，断线由客户端自动重连
+func (r *RedisRemote) Subscribe(ctx context.Context, fn func(indexes []string)) {
+	sub := r.client.Subscribe(ctx, r.channel)
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var indexes []string
+			if err := json.Unmarshal([]byte(msg.Payload), &indexes); err == nil && len(indexes) > 0 {
+				fn(indexes)
+			}
+		}
+	}
+}
+
+// Close 关闭连接
+func (r *RedisRemote) Close() error {
+	return r.client.Close()
+}
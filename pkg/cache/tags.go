@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 标签格式为 "类型:ID"，如 "resource:12"；"类型:*" 表示依赖整个集合（列表、统计等）。
+//
+// 缓存项的标签与失效的对应关系：
+//   - Invalidate("resource:12") 删除标记为 "resource:12" 与 "resource:*" 的缓存项
+//     （单个资源变化会影响该资源本身以及所有资源列表）
+//   - Invalidate("resource:*") 删除所有带 "resource:" 前缀标签的缓存项
+
+// Tag 返回单个对象的标签
+func Tag(kind string, id any) string {
+	return fmt.Sprintf("%s:%v", kind, id)
+}
+
+// AllTag 返回整个集合的标签
+func AllTag(kind string) string {
+	return kind + ":*"
+}
+
+// tagGroup 返回标签的类型前缀（含冒号），无类型时返回空串
+func tagGroup(tag string) string {
+	if i := strings.IndexByte(tag, ':'); i > 0 {
+		return tag[:i+1]
+	}
+	return ""
+}
+
+// entryIndexes 写入缓存项时登记的索引
+func entryIndexes(tags []string) []string {
+	indexes := make([]string, 0, len(tags)*2)
+	seen := make(map[string]bool, len(tags)*2)
+	add := func(index string) {
+		if index != "" && !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	for _, tag := range tags {
+		add(tag)
+		add(tagGroup(tag))
+	}
+	return indexes
+}
+
+// invalidationIndexes 失效标签时需要清理的索引
+func invalidationIndexes(tags []string) []string {
+	indexes := make([]string, 0, len(tags)*2)
+	seen := make(map[string]bool, len(tags)*2)
+	add := func(index string) {
+		if index != "" && !seen[index] {
+			seen[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	for _, tag := range tags {
+		group := tagGroup(tag)
+		switch {
+		case group == "":
+			add(tag)
+		case strings.HasSuffix(tag, ":*"):
+			add(group)
+		default:
+			add(tag)
+			add(group + "*")
+		}
+	}
+	return indexes
+}
+
+// keyGroup 返回缓存键的分组（第一个冒号之前的部分），用于指标标签
+func keyGroup(key string) string {
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+	return "other"
+}
@@ -0,0 +1,201 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleRecords() []*Record {
+	return []*Record{
+		{
+			Key:         "aB3xY9",
+			Title:       "三体 全集 <4K>",
+			Description: "第一行\n第二行, 含逗号 \"引号\"",
+			URL:         "https://pan.quark.cn/s/abc123",
+			Category:    "电视剧",
+			Tags:        []string{"科幻", "国产"},
+			Pan:         "quark",
+			ViewCount:   42,
+			IsValid:     true,
+			IsPublic:    true,
+			CreatedAt:   time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC),
+			Metadata: &Metadata{
+				Provider:  "douban",
+				SubjectID: "26647087",
+				MediaType: "tv",
+				Title:     "三体",
+				Year:      2023,
+				Genres:    "剧情,科幻",
+				Rating:    8.7,
+				Episodes:  30,
+			},
+		},
+		{
+			Title:    "无元数据资源",
+			URL:      "https://www.alipan.com/s/xyz",
+			IsValid:  false,
+			IsPublic: true,
+		},
+	}
+}
+
+func roundTrip(t *testing.T, format string) []*Record {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range sampleRecords() {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*Record
+	for {
+		r, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("line %d: %v", reader.Line(), err)
+		}
+		got = append(got, r)
+	}
+	return got
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := sampleRecords()
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			got := roundTrip(t, format)
+			if len(got) != len(want) {
+				t.Fatalf("got %d records, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Errorf("record %d:\n got  %+v\n want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCSVReaderMapsColumnsByHeader(t *testing.T) {
+	input := "\ufeffURL,Title,tags,is_public,extra\n" +
+		"https://pan.baidu.com/s/1,资源一,a| b ,false,x\n" +
+		",,,,\n" +
+		"https://pan.baidu.com/s/2,资源二,,,\n"
+	reader, err := NewReader(FormatCSV, strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Title != "资源一" || !reflect.DeepEqual(first.Tags, []string{"a", "b"}) || first.IsPublic || !first.IsValid {
+		t.Errorf("first = %+v", first)
+	}
+	second, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Title != "资源二" || reader.Line() != 4 {
+		t.Errorf("second = %+v, line = %d", second, reader.Line())
+	}
+
+	if _, err := NewReader(FormatCSV, strings.NewReader("title\nx\n")); err == nil {
+		t.Error("header without url should be rejected")
+	}
+}
+
+// TestXLSXReaderSharedStrings 读取 Excel 另存的文件：共享字符串、缺省单元格引用
+func TestXLSXReaderSharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>url</t></si><si><t>title</t></si><si><r><t>富</t></r><r><t>文本</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>https://pan.quark.cn/s/q</t></is></c><c r="C3" t="s"><v>2</v></c></row>
+</sheetData></worksheet>`,
+	}
+	for name, body := range files {
+		f, _ := zw.Create(name)
+		f.Write([]byte(body))
+	}
+	zw.Close()
+
+	reader, err := NewReader(FormatXLSX, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.URL != "https://pan.quark.cn/s/q" || r.Title != "富文本" || reader.Line() != 3 {
+		t.Errorf("record = %+v, line = %d", r, reader.Line())
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	if FormatFromName("export.XLSX") != FormatXLSX || FormatFromName("a.ndjson") != FormatJSONL || FormatFromName("a.txt") != "" {
+		t.Error("unexpected format detection")
+	}
+	if columnName(0) != "A" || columnName(26) != "AA" {
+		t.Error("unexpected column name")
+	}
+	if idx, ok := columnIndex("AB12"); !ok || idx != 27 {
+		t.Errorf("columnIndex(AB12) = %d", idx)
+	}
+}
+
+// TestReaderRecordError 单条记录格式错误时返回 RecordError，后续记录仍可读取
+func TestReaderRecordError(t *testing.T) {
+	input := `{"url":"https://pan.quark.cn/s/1","title":"a"}
+{"url":
+{"url":"https://pan.quark.cn/s/2","title":"b","view_count":"x"}
+{"url":"https://pan.quark.cn/s/3","title":"c"}
+`
+	reader, _ := NewReader(FormatJSONL, strings.NewReader(input))
+	var titles []string
+	var recordErrs []int
+	for {
+		r, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var recErr *RecordError
+		if errors.As(err, &recErr) {
+			recordErrs = append(recordErrs, reader.Line())
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, r.Title)
+	}
+	if !reflect.DeepEqual(titles, []string{"a", "c"}) || !reflect.DeepEqual(recordErrs, []int{2, 3}) {
+		t.Errorf("titles = %v, record errors at lines %v", titles, recordErrs)
+	}
+}
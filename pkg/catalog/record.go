@@ -0,0 +1,309 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导出/导入格式
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+	FormatXLSX  = "xlsx"
+)
+
+// Formats 支持的全部格式
+var Formats = []string{FormatJSONL, FormatCSV, FormatXLSX}
+
+// tagSeparator CSV/XLSX 中多个标签的分隔符
+const tagSeparator = "|"
+
+// Record 目录中的一条资源，分类、标签、网盘均以名称表示，便于跨实例迁移
+type Record struct {
+	Key         string    `json:"key,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url"`
+	SaveURL     string    `json:"save_url,omitempty"`
+	FileSize    string    `json:"file_size,omitempty"`
+	Cover       string    `json:"cover,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Pan         string    `json:"pan,omitempty"`
+	ViewCount   int       `json:"view_count"`
+	IsValid     bool      `json:"is_valid"`
+	IsPublic    bool      `json:"is_public"`
+	CreatedAt   time.Time `json:"created_at"`
+	Metadata    *Metadata `json:"metadata,omitempty"`
+}
+
+// Metadata 影视元数据
+type Metadata struct {
+	Provider      string  `json:"provider"`
+	SubjectID     string  `json:"subject_id"`
+	MediaType     string  `json:"media_type,omitempty"`
+	Title         string  `json:"title,omitempty"`
+	OriginalTitle string  `json:"original_title,omitempty"`
+	Year          int     `json:"year,omitempty"`
+	Regions       string  `json:"regions,omitempty"`
+	Genres        string  `json:"genres,omitempty"`
+	Rating        float64 `json:"rating,omitempty"`
+	RatingCount   int     `json:"rating_count,omitempty"`
+	PosterURL     string  `json:"poster_url,omitempty"`
+	Episodes      int     `json:"episodes,omitempty"`
+	Directors     string  `json:"directors,omitempty"`
+	Actors        string  `json:"actors,omitempty"`
+	Summary       string  `json:"summary,omitempty"`
+	SubjectURL    string  `json:"subject_url,omitempty"`
+}
+
+// Columns CSV/XLSX 的列，元数据字段以 metadata_ 为前缀展开
+var Columns = []string{
+	"key", "title", "description", "url", "save_url", "file_size", "cover", "author",
+	"category", "tags", "pan", "view_count", "is_valid", "is_public", "created_at",
+	"metadata_provider", "metadata_subject_id", "metadata_media_type", "metadata_title",
+	"metadata_original_title", "metadata_year", "metadata_regions", "metadata_genres",
+	"metadata_rating", "metadata_rating_count", "metadata_poster_url", "metadata_episodes",
+	"metadata_directors", "metadata_actors", "metadata_summary", "metadata_subject_url",
+}
+
+// numericColumns 在 XLSX 中写为数字单元格的列
+var numericColumns = map[string]bool{
+	"view_count": true, "metadata_year": true, "metadata_rating": true,
+	"metadata_rating_count": true, "metadata_episodes": true,
+}
+
+// Writer 按格式写出记录，Close 写出结尾部分但不关闭底层 io.Writer
+type Writer interface {
+	Write(r *Record) error
+	Close() error
+}
+
+// Reader 按格式逐条读取记录，读完返回 io.EOF；Line 为当前记录所在的行号
+type Reader interface {
+	Read() (*Record, error)
+	Line() int
+}
+
+// RecordError 单条记录无法解析，读取器可以继续读取下一条；其他错误表示读取无法继续
+type RecordError struct {
+	Err error
+}
+
+func (e *RecordError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// NewWriter 创建指定格式的写出器
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("不支持的格式: %s", format)
+}
+
+// NewReader 创建指定格式的读取器。XLSX 需要随机访问，会先把内容读入内存
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatJSONL:
+		return newJSONLReader(r), nil
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatXLSX:
+		return newXLSXReader(r)
+	}
+	return nil, fmt.Errorf("不支持的格式: %s", format)
+}
+
+// FormatFromName 根据文件扩展名推断格式，无法识别时返回空串
+func FormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	}
+	return ""
+}
+
+// ContentType 格式对应的 MIME 类型
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/x-ndjson"
+}
+
+// toRow 把记录展开为与 Columns 对应的单元格
+func toRow(r *Record) []string {
+	row := []string{
+		r.Key, r.Title, r.Description, r.URL, r.SaveURL, r.FileSize, r.Cover, r.Author,
+		r.Category, strings.Join(r.Tags, tagSeparator), r.Pan, strconv.Itoa(r.ViewCount),
+		strconv.FormatBool(r.IsValid), strconv.FormatBool(r.IsPublic), formatTime(r.CreatedAt),
+	}
+	m := r.Metadata
+	if m == nil {
+		return append(row, make([]string, len(Columns)-len(row))...)
+	}
+	return append(row,
+		m.Provider, m.SubjectID, m.MediaType, m.Title, m.OriginalTitle, formatInt(m.Year),
+		m.Regions, m.Genres, formatFloat(m.Rating), formatInt(m.RatingCount), m.PosterURL,
+		formatInt(m.Episodes), m.Directors, m.Actors, m.Summary, m.SubjectURL,
+	)
+}
+
+// fromRow 按表头把单元格还原为记录，缺失的列取零值，未知列忽略
+func fromRow(header map[string]int, row []string) (*Record, error) {
+	get := func(col string) string {
+		if i, ok := header[col]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	r := &Record{
+		Key:         get("key"),
+		Title:       get("title"),
+		Description: get("description"),
+		URL:         get("url"),
+		SaveURL:     get("save_url"),
+		FileSize:    get("file_size"),
+		Cover:       get("cover"),
+		Author:      get("author"),
+		Category:    get("category"),
+		Pan:         get("pan"),
+		IsValid:     true,
+		IsPublic:    true,
+	}
+	for _, tag := range strings.Split(get("tags"), tagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			r.Tags = append(r.Tags, tag)
+		}
+	}
+
+	var err error
+	if r.ViewCount, err = parseInt(get("view_count")); err != nil {
+		return nil, fmt.Errorf("view_count: %v", err)
+	}
+	if v := get("is_valid"); v != "" {
+		if r.IsValid, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("is_valid: %v", err)
+		}
+	}
+	if v := get("is_public"); v != "" {
+		if r.IsPublic, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("is_public: %v", err)
+		}
+	}
+	if v := get("created_at"); v != "" {
+		if r.CreatedAt, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, fmt.Errorf("created_at: %v", err)
+		}
+	}
+
+	if get("metadata_provider") == "" || get("metadata_subject_id") == "" {
+		return r, nil
+	}
+	m := &Metadata{
+		Provider:      get("metadata_provider"),
+		SubjectID:     get("metadata_subject_id"),
+		MediaType:     get("metadata_media_type"),
+		Title:         get("metadata_title"),
+		OriginalTitle: get("metadata_original_title"),
+		Regions:       get("metadata_regions"),
+		Genres:        get("metadata_genres"),
+		PosterURL:     get("metadata_poster_url"),
+		Directors:     get("metadata_directors"),
+		Actors:        get("metadata_actors"),
+		Summary:       get("metadata_summary"),
+		SubjectURL:    get("metadata_subject_url"),
+	}
+	if m.Year, err = parseInt(get("metadata_year")); err != nil {
+		return nil, fmt.Errorf("metadata_year: %v", err)
+	}
+	if m.RatingCount, err = parseInt(get("metadata_rating_count")); err != nil {
+		return nil, fmt.Errorf("metadata_rating_count: %v", err)
+	}
+	if m.Episodes, err = parseInt(get("metadata_episodes")); err != nil {
+		return nil, fmt.Errorf("metadata_episodes: %v", err)
+	}
+	if v := get("metadata_rating"); v != "" {
+		if m.Rating, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("metadata_rating: %v", err)
+		}
+	}
+	r.Metadata = m
+	return r, nil
+}
+
+// recordFromRow 还原一行记录，字段格式错误作为 RecordError 返回
+func recordFromRow(header map[string]int, row []string) (*Record, error) {
+	r, err := fromRow(header, row)
+	if err != nil {
+		return nil, &RecordError{Err: err}
+	}
+	return r, nil
+}
+
+// headerIndex 表头列名到下标的映射，列名不区分大小写
+func headerIndex(header []string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, col := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))] = i
+	}
+	if _, ok := index["url"]; !ok {
+		return nil, fmt.Errorf("表头缺少 url 列")
+	}
+	return index, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatInt(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func formatFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	// XLSX 中的数字单元格可能带小数部分
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return int(f), nil
+	}
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxJSONLLine 单行 JSONL 的最大长度
+const maxJSONLLine = 4 << 20
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{enc: enc}
+}
+
+func (w *jsonlWriter) Write(r *Record) error {
+	return w.enc.Encode(r)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxJSONLLine)
+	return &jsonlReader{scanner: scanner}
+}
+
+func (r *jsonlReader) Read() (*Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := &Record{IsValid: true, IsPublic: true}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, &RecordError{Err: fmt.Errorf("JSON 解析失败: %v", err)}
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *jsonlReader) Line() int {
+	return r.line
+}
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// 写入 UTF-8 BOM，Excel 直接打开时中文不乱码
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (w *csvWriter) Write(r *Record) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(Columns); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	return w.w.Write(toRow(r))
+}
+
+func (w *csvWriter) Close() error {
+	if !w.wroteHeader {
+		if err := w.w.Write(Columns); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header map[string]int
+	line   int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("文件为空")
+		}
+		return nil, err
+	}
+	index, err := headerIndex(header)
+	if err != nil {
+		return nil, err
+	}
+	return &csvReader{r: reader, header: index, line: 1}, nil
+}
+
+func (r *csvReader) Read() (*Record, error) {
+	for {
+		row, err := r.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.line, _ = r.r.FieldPos(0)
+		if isBlankRow(row) {
+			continue
+		}
+		return recordFromRow(r.header, row)
+	}
+}
+
+func (r *csvReader) Line() int {
+	return r.line
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
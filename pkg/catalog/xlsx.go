@@ -0,0 +1,358 @@
+package catalog
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// xlsxMaxRows Excel 单个工作表的最大行数（含表头）
+	xlsxMaxRows = 1048576
+	// xlsxMaxCellChars Excel 单元格的最大字符数
+	xlsxMaxCellChars = 32767
+	// xlsxMaxSize 导入时 XLSX 文件的最大大小
+	xlsxMaxSize = 512 << 20
+)
+
+// xlsx 最小化的静态部件，只包含一个工作表
+var xlsxStaticParts = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="resources" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// xlsxWriter 流式写出 XLSX：静态部件先写入，工作表逐行写入 zip 条目，字符串使用内联字符串
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &xlsxWriter{zw: zw, sheet: bufio.NewWriterSize(f, 64<<10)}
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err := x.writeRow(Columns, false); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(r *Record) error {
+	if x.rows >= xlsxMaxRows {
+		return fmt.Errorf("超过 XLSX 单表最大行数 %d，请改用 CSV 或 JSONL", xlsxMaxRows)
+	}
+	return x.writeRow(toRow(r), true)
+}
+
+func (x *xlsxWriter) writeRow(cells []string, typed bool) error {
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+	for i, value := range cells {
+		if value == "" {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(x.rows)
+		if typed && numericColumns[Columns[i]] {
+			fmt.Fprintf(x.sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+			continue
+		}
+		if len(value) > xlsxMaxCellChars {
+			value = truncateRunes(value, xlsxMaxCellChars)
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(x.sheet, []byte(value)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+// xlsxReader 读取第一个工作表，支持共享字符串、内联字符串和数字单元格
+type xlsxReader struct {
+	dec     *xml.Decoder
+	sheet   io.Closer
+	shared  []string
+	header  map[string]int
+	line    int
+	rowNext int
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	data, err := io.ReadAll(io.LimitReader(r, xlsxMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > xlsxMaxSize {
+		return nil, fmt.Errorf("XLSX 文件超过 %d MB", xlsxMaxSize>>20)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的 XLSX 文件: %v", err)
+	}
+
+	var sheetFile *zip.File
+	x := &xlsxReader{}
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "xl/sharedStrings.xml":
+			if x.shared, err = readSharedStrings(f); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(f.Name, "xl/worksheets/") && path.Ext(f.Name) == ".xml":
+			if sheetFile == nil || f.Name == "xl/worksheets/sheet1.xml" {
+				sheetFile = f
+			}
+		}
+	}
+	if sheetFile == nil {
+		return nil, fmt.Errorf("XLSX 中没有工作表")
+	}
+
+	sheet, err := sheetFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	x.sheet = sheet
+	x.dec = xml.NewDecoder(sheet)
+
+	header, err := x.nextRow()
+	if err != nil {
+		sheet.Close()
+		if err == io.EOF {
+			return nil, fmt.Errorf("文件为空")
+		}
+		return nil, err
+	}
+	if x.header, err = headerIndex(header); err != nil {
+		sheet.Close()
+		return nil, err
+	}
+	return x, nil
+}
+
+func (x *xlsxReader) Read() (*Record, error) {
+	for {
+		row, err := x.nextRow()
+		if err != nil {
+			if err == io.EOF {
+				x.sheet.Close()
+			}
+			return nil, err
+		}
+		if isBlankRow(row) {
+			continue
+		}
+		return recordFromRow(x.header, row)
+	}
+}
+
+func (x *xlsxReader) Line() int {
+	return x.line
+}
+
+// nextRow 读取下一个 <row>，按单元格引用把值放到对应列
+func (x *xlsxReader) nextRow() ([]string, error) {
+	for {
+		tok, err := x.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		x.rowNext++
+		x.line = x.rowNext
+		if n, err := strconv.Atoi(attr(start, "r")); err == nil {
+			x.line, x.rowNext = n, n
+		}
+
+		var row []string
+		col := 0
+		for {
+			tok, err := x.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if end, ok := tok.(xml.EndElement); ok && end.Name.Local == "row" {
+				return row, nil
+			}
+			cell, ok := tok.(xml.StartElement)
+			if !ok || cell.Name.Local != "c" {
+				continue
+			}
+			if idx, ok := columnIndex(attr(cell, "r")); ok {
+				col = idx
+			}
+			value, err := x.cellValue(cell)
+			if err != nil {
+				return nil, err
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			row[col] = value
+			col++
+		}
+	}
+}
+
+// cellValue 读取单元格内容直到 </c>
+func (x *xlsxReader) cellValue(cell xml.StartElement) (string, error) {
+	var v, inline strings.Builder
+	var inV, inT bool
+	for {
+		tok, err := x.dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inV = t.Name.Local == "v"
+			inT = t.Name.Local == "t"
+		case xml.EndElement:
+			if t.Name.Local == "c" {
+				switch attr(cell, "t") {
+				case "inlineStr":
+					return inline.String(), nil
+				case "s":
+					i, err := strconv.Atoi(strings.TrimSpace(v.String()))
+					if err != nil || i < 0 || i >= len(x.shared) {
+						return "", fmt.Errorf("无效的共享字符串索引: %s", v.String())
+					}
+					return x.shared[i], nil
+				}
+				return v.String(), nil
+			}
+			inV, inT = false, false
+		case xml.CharData:
+			if inV {
+				v.Write(t)
+			} else if inT {
+				inline.Write(t)
+			}
+		}
+	}
+}
+
+// readSharedStrings 读取共享字符串表，富文本按顺序拼接各段文本
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var shared []string
+	var current strings.Builder
+	var inT bool
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "si" {
+				current.Reset()
+			}
+			inT = t.Name.Local == "t"
+		case xml.EndElement:
+			if t.Name.Local == "si" {
+				shared = append(shared, current.String())
+			}
+			inT = false
+		case xml.CharData:
+			if inT {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// columnName 列下标转列名：0 -> A，26 -> AA
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+// columnIndex 从单元格引用（如 AB12）解析列下标
+func columnIndex(ref string) (int, bool) {
+	idx := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		idx = idx*26 + int(ch-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return idx - 1, true
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,116 @@
+package classifier
+
+import (
+	"math"
+	"sort"
+)
+
+// NaiveBayes 多项式朴素贝叶斯分类器（拉普拉斯平滑），可直接 JSON 序列化保存
+type NaiveBayes struct {
+	Labels    map[string]*LabelStats `json:"labels"`
+	TotalDocs int                    `json:"total_docs"`
+	VocabSize int                    `json:"vocab_size"`
+}
+
+// LabelStats 单个类别的统计
+type LabelStats struct {
+	Docs   int            `json:"docs"`
+	Tokens int            `json:"tokens"`
+	Counts map[string]int `json:"counts"`
+}
+
+// Prediction 预测结果
+type Prediction struct {
+	Label       string  `json:"label"`
+	Probability float64 `json:"probability"`
+}
+
+// NewNaiveBayes 创建空模型
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{Labels: make(map[string]*LabelStats)}
+}
+
+// Train 加入一条训练样本
+func (nb *NaiveBayes) Train(label string, tokens []string) {
+	if label == "" || len(tokens) == 0 {
+		return
+	}
+	stats, ok := nb.Labels[label]
+	if !ok {
+		stats = &LabelStats{Counts: make(map[string]int)}
+		nb.Labels[label] = stats
+	}
+	stats.Docs++
+	nb.TotalDocs++
+	for _, t := range tokens {
+		stats.Counts[t]++
+		stats.Tokens++
+	}
+}
+
+// Finalize 训练结束后调用：丢弃出现次数低于 minCount 的特征以控制模型体积，并统计词表大小
+func (nb *NaiveBayes) Finalize(minCount int) {
+	vocab := make(map[string]struct{})
+	for _, stats := range nb.Labels {
+		for token, count := range stats.Counts {
+			if count < minCount {
+				delete(stats.Counts, token)
+				stats.Tokens -= count
+				continue
+			}
+			vocab[token] = struct{}{}
+		}
+	}
+	nb.VocabSize = len(vocab)
+}
+
+// Predict 返回按概率降序的预测结果，概率为各类别后验归一化后的值
+func (nb *NaiveBayes) Predict(tokens []string) []Prediction {
+	if nb == nil || nb.TotalDocs == 0 || len(tokens) == 0 {
+		return nil
+	}
+
+	vocab := float64(nb.VocabSize + 1)
+	scores := make([]Prediction, 0, len(nb.Labels))
+	maxScore := math.Inf(-1)
+	for label, stats := range nb.Labels {
+		score := math.Log(float64(stats.Docs) / float64(nb.TotalDocs))
+		denominator := float64(stats.Tokens) + vocab
+		known := false
+		for _, t := range tokens {
+			count := stats.Counts[t]
+			if count > 0 {
+				known = true
+			}
+			score += math.Log((float64(count) + 1) / denominator)
+		}
+		if !known {
+			// 与该类别完全没有共同特征时不参与竞争，避免仅凭先验给出高概率
+			score = math.Inf(-1)
+		}
+		scores = append(scores, Prediction{Label: label, Probability: score})
+		if score > maxScore {
+			maxScore = score
+		}
+	}
+	if math.IsInf(maxScore, -1) {
+		return nil
+	}
+
+	// softmax 归一化
+	var sum float64
+	for i := range scores {
+		scores[i].Probability = math.Exp(scores[i].Probability - maxScore)
+		sum += scores[i].Probability
+	}
+	for i := range scores {
+		scores[i].Probability /= sum
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Probability == scores[j].Probability {
+			return scores[i].Label < scores[j].Label
+		}
+		return scores[i].Probability > scores[j].Probability
+	})
+	return scores
+}
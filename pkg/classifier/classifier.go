@@ -0,0 +1,181 @@
+// Package classifier 根据标题、描述与文件名为资源推荐分类和标签。
+//
+// 由两部分组成：按分类/标签配置的关键词、正则规则（Engine），
+// 以及基于已分类资源训练的朴素贝叶斯分类器（NaiveBayes）。
+// 两者的结果通过 Combine 合并，置信度取值 0~1。
+package classifier
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 规则作用的字段
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldFiles       = "files"
+)
+
+// 建议来源
+const (
+	SourceRule  = "rule"
+	SourceBayes = "bayes"
+)
+
+// Document 待分类的资源文本
+type Document struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Files       []string `json:"files"` // 网盘文件名列表
+}
+
+// Field 返回指定字段的文本，files 以换行拼接
+func (d Document) Field(field string) string {
+	switch field {
+	case FieldTitle:
+		return d.Title
+	case FieldDescription:
+		return d.Description
+	case FieldFiles:
+		return strings.Join(d.Files, "\n")
+	}
+	return ""
+}
+
+// Text 返回全部字段拼接后的文本
+func (d Document) Text() string {
+	return d.Title + "\n" + d.Description + "\n" + d.Field(FieldFiles)
+}
+
+// Suggestion 一条分类或标签建议
+type Suggestion struct {
+	TargetID   uint     `json:"target_id"`
+	Name       string   `json:"name"`
+	Confidence float64  `json:"confidence"`
+	Sources    []string `json:"sources"`            // rule / bayes
+	RuleIDs    []uint   `json:"rule_ids,omitempty"` // 命中的规则
+	Matched    []string `json:"matched,omitempty"`  // 命中的关键词或正则片段
+}
+
+// Result 分类结果，建议按置信度降序排列
+type Result struct {
+	Categories []Suggestion `json:"categories"`
+	Tags       []Suggestion `json:"tags"`
+}
+
+// TopCategory 返回置信度最高的分类建议
+func (r *Result) TopCategory() *Suggestion {
+	if r == nil || len(r.Categories) == 0 {
+		return nil
+	}
+	return &r.Categories[0]
+}
+
+// Combine 合并同一目标的多条建议：置信度按 1-Π(1-c) 叠加，来源与命中信息合并
+func Combine(groups ...[]Suggestion) []Suggestion {
+	merged := make(map[uint]*Suggestion)
+	var order []uint
+	for _, group := range groups {
+		for _, s := range group {
+			existing, ok := merged[s.TargetID]
+			if !ok {
+				copied := s
+				copied.Sources = append([]string(nil), s.Sources...)
+				copied.RuleIDs = append([]uint(nil), s.RuleIDs...)
+				copied.Matched = append([]string(nil), s.Matched...)
+				merged[s.TargetID] = &copied
+				order = append(order, s.TargetID)
+				continue
+			}
+			existing.Confidence = 1 - (1-existing.Confidence)*(1-s.Confidence)
+			existing.Sources = appendUnique(existing.Sources, s.Sources...)
+			existing.RuleIDs = append(existing.RuleIDs, s.RuleIDs...)
+			existing.Matched = appendUnique(existing.Matched, s.Matched...)
+			if existing.Name == "" {
+				existing.Name = s.Name
+			}
+		}
+	}
+
+	result := make([]Suggestion, 0, len(order))
+	for _, id := range order {
+		result = append(result, *merged[id])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Confidence > result[j].Confidence
+	})
+	return result
+}
+
+// Tokenize 分词：英文与数字按单词切分，中文按字的二元组切分（单字成词时保留单字），
+// 文件名额外保留扩展名作为特征
+func Tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	var han []rune
+
+	flushWord := func() {
+		if len(word) >= 2 {
+			tokens = append(tokens, string(word))
+		}
+		word = word[:0]
+	}
+	flushHan := func() {
+		switch {
+		case len(han) == 1:
+			tokens = append(tokens, string(han))
+		case len(han) > 1:
+			for i := 0; i+1 < len(han); i++ {
+				tokens = append(tokens, string(han[i:i+2]))
+			}
+		}
+		han = han[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+// DocumentTokens 文档的全部特征：正文分词 + 文件扩展名（ext:mkv）
+func DocumentTokens(doc Document) []string {
+	tokens := Tokenize(doc.Text())
+	for _, f := range doc.Files {
+		if ext := strings.TrimPrefix(strings.ToLower(path.Ext(f)), "."); ext != "" && len(ext) <= 5 {
+			tokens = append(tokens, "ext:"+ext)
+		}
+	}
+	return tokens
+}
+
+func appendUnique(dst []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, d := range dst {
+			if d == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
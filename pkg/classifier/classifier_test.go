@@ -0,0 +1,92 @@
+package classifier
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("流浪地球2 4K.HDR 国语")
+	want := []string{"流浪", "浪地", "地球", "4k", "hdr", "国语"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize() = %v, want %v", got, want)
+	}
+
+	tokens := DocumentTokens(Document{Files: []string{"S01E01.mkv", "readme"}})
+	if tokens[len(tokens)-1] != "ext:mkv" {
+		t.Fatalf("DocumentTokens() = %v, want ext:mkv feature", tokens)
+	}
+}
+
+func TestEngineMatch(t *testing.T) {
+	engine, errs := NewEngine([]Rule{
+		{ID: 1, Target: TargetCategory, TargetID: 10, TargetName: "电影", MatchType: MatchKeyword, Pattern: "电影, 1080P", Confidence: 0.6},
+		{ID: 2, Target: TargetCategory, TargetID: 10, TargetName: "电影", MatchType: MatchRegex, Pattern: `\.(mkv|mp4)$`, Fields: []string{FieldFiles}, Confidence: 0.5},
+		{ID: 3, Target: TargetCategory, TargetID: 20, TargetName: "电子书", MatchType: MatchRegex, Pattern: `\.(epub|mobi|azw3)`, Confidence: 0.9},
+		{ID: 4, Target: TargetTag, TargetID: 30, TargetName: "4K", MatchType: MatchRegex, Pattern: `\b(4k|2160p)\b`, Fields: []string{FieldTitle}, Confidence: 0.8},
+		{ID: 5, Target: TargetTag, TargetID: 31, MatchType: MatchRegex, Pattern: `(`, Confidence: 0.8},
+		{ID: 6, Target: "series", TargetID: 32, MatchType: MatchKeyword, Pattern: "x", Confidence: 0.8},
+	})
+	if len(errs) != 2 || engine.Len() != 4 {
+		t.Fatalf("NewEngine() compiled %d rules, errs = %v", engine.Len(), errs)
+	}
+
+	result := engine.Match(Document{
+		Title: "沙丘2 2160P 1080p 双版本",
+		Files: []string{"Dune.Part.Two.2024.mkv"},
+	})
+	top := result.TopCategory()
+	if top == nil || top.TargetID != 10 {
+		t.Fatalf("TopCategory() = %+v", top)
+	}
+	// 两条规则叠加：1-(1-0.6)(1-0.5) = 0.8
+	if top.Confidence < 0.799 || top.Confidence > 0.801 || len(top.RuleIDs) != 2 {
+		t.Fatalf("combined suggestion = %+v", top)
+	}
+	if len(result.Tags) != 1 || result.Tags[0].TargetID != 30 {
+		t.Fatalf("tags = %+v", result.Tags)
+	}
+
+	// files 字段限定的规则不匹配标题
+	result = engine.Match(Document{Title: "示例.mkv"})
+	if len(result.Categories) != 0 {
+		t.Fatalf("field-scoped rule matched title: %+v", result.Categories)
+	}
+}
+
+func TestNaiveBayes(t *testing.T) {
+	nb := NewNaiveBayes()
+	samples := map[string][]string{
+		"movie": {"流浪地球 电影 1080p", "沙丘 电影 4k 蓝光", "奥本海默 电影 国语中字"},
+		"book":  {"三体 全集 epub 电子书", "明朝那些事儿 mobi 电子书", "百年孤独 azw3 电子书"},
+	}
+	for label, texts := range samples {
+		for _, text := range texts {
+			nb.Train(label, Tokenize(text))
+		}
+	}
+	nb.Finalize(1)
+
+	predictions := nb.Predict(Tokenize("活着 余华 epub 电子书"))
+	if len(predictions) == 0 || predictions[0].Label != "book" || predictions[0].Probability < 0.9 {
+		t.Fatalf("Predict() = %+v", predictions)
+	}
+	if got := nb.Predict(Tokenize("完全无关的文本")); got != nil {
+		t.Fatalf("Predict() on unseen tokens = %+v, want nil", got)
+	}
+
+	// 序列化后预测结果不变
+	raw, err := json.Marshal(nb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewNaiveBayes()
+	if err := json.Unmarshal(raw, restored); err != nil {
+		t.Fatal(err)
+	}
+	again := restored.Predict(Tokenize("活着 余华 epub 电子书"))
+	if again[0].Label != predictions[0].Label || again[0].Probability != predictions[0].Probability {
+		t.Fatalf("restored Predict() = %+v, want %+v", again, predictions)
+	}
+}
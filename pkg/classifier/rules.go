@@ -0,0 +1,170 @@
+package classifier
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 规则目标
+const (
+	TargetCategory = "category"
+	TargetTag      = "tag"
+)
+
+// 匹配方式
+const (
+	MatchKeyword = "keyword" // 任一关键词出现即命中，不区分大小写
+	MatchRegex   = "regex"   // 正则匹配，默认不区分大小写
+)
+
+// Rule 分类/标签规则
+type Rule struct {
+	ID         uint
+	Target     string   // category / tag
+	TargetID   uint     // 分类ID或标签ID
+	TargetName string   // 分类名或标签名
+	MatchType  string   // keyword / regex
+	Pattern    string   // 关键词（逗号或换行分隔）或正则表达式
+	Fields     []string // 匹配字段，为空时匹配全部字段
+	Confidence float64  // 命中后的置信度，0~1
+}
+
+type compiledRule struct {
+	Rule
+	keywords []string
+	regex    *regexp.Regexp
+}
+
+// Engine 规则引擎
+type Engine struct {
+	rules []compiledRule
+}
+
+// NewEngine 编译规则，无效规则被跳过并在 errs 中返回
+func NewEngine(rules []Rule) (*Engine, []error) {
+	e := &Engine{}
+	var errs []error
+	for _, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("规则 %d: %v", rule.ID, err))
+			continue
+		}
+		e.rules = append(e.rules, *compiled)
+	}
+	return e, errs
+}
+
+// ValidateRule 校验单条规则
+func ValidateRule(rule Rule) error {
+	_, err := compileRule(rule)
+	return err
+}
+
+// compileRule 校验并编译单条规则
+func compileRule(rule Rule) (*compiledRule, error) {
+	if rule.Target != TargetCategory && rule.Target != TargetTag {
+		return nil, fmt.Errorf("无效的规则目标: %s", rule.Target)
+	}
+	if rule.TargetID == 0 {
+		return nil, fmt.Errorf("规则未指定目标")
+	}
+	if rule.Confidence <= 0 || rule.Confidence > 1 {
+		return nil, fmt.Errorf("置信度应在 (0, 1] 之间")
+	}
+	for _, f := range rule.Fields {
+		if f != FieldTitle && f != FieldDescription && f != FieldFiles {
+			return nil, fmt.Errorf("无效的匹配字段: %s", f)
+		}
+	}
+
+	compiled := &compiledRule{Rule: rule}
+	switch rule.MatchType {
+	case MatchKeyword:
+		for _, line := range strings.Split(rule.Pattern, "\n") {
+			for _, kw := range strings.Split(line, ",") {
+				if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+					compiled.keywords = append(compiled.keywords, kw)
+				}
+			}
+		}
+		if len(compiled.keywords) == 0 {
+			return nil, fmt.Errorf("关键词不能为空")
+		}
+	case MatchRegex:
+		pattern := rule.Pattern
+		if !strings.HasPrefix(pattern, "(?") {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("正则表达式无效: %v", err)
+		}
+		compiled.regex = re
+	default:
+		return nil, fmt.Errorf("无效的匹配方式: %s", rule.MatchType)
+	}
+	return compiled, nil
+}
+
+// match 返回命中的片段，未命中返回 nil
+func (r *compiledRule) match(doc Document) []string {
+	fields := r.Fields
+	if len(fields) == 0 {
+		fields = []string{FieldTitle, FieldDescription, FieldFiles}
+	}
+
+	var matched []string
+	for _, field := range fields {
+		text := doc.Field(field)
+		if text == "" {
+			continue
+		}
+		if r.regex != nil {
+			if m := r.regex.FindString(text); m != "" {
+				matched = appendUnique(matched, m)
+			}
+			continue
+		}
+		lower := strings.ToLower(text)
+		for _, kw := range r.keywords {
+			if strings.Contains(lower, kw) {
+				matched = appendUnique(matched, kw)
+			}
+		}
+	}
+	return matched
+}
+
+// Len 返回已编译的规则数
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Match 对文档执行全部规则，返回按置信度降序的分类与标签建议
+func (e *Engine) Match(doc Document) *Result {
+	var categories, tags []Suggestion
+	for i := range e.rules {
+		rule := &e.rules[i]
+		matched := rule.match(doc)
+		if len(matched) == 0 {
+			continue
+		}
+		s := Suggestion{
+			TargetID:   rule.TargetID,
+			Name:       rule.TargetName,
+			Confidence: rule.Confidence,
+			Sources:    []string{SourceRule},
+			RuleIDs:    []uint{rule.ID},
+			Matched:    matched,
+		}
+		if rule.Target == TargetCategory {
+			categories = append(categories, s)
+		} else {
+			tags = append(tags, s)
+		}
+	}
+
+	return &Result{Categories: Combine(categories), Tags: Combine(tags)}
+}
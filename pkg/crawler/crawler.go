@@ -0,0 +1,260 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 内容源类型，与 entity.SourceRSS 等取值保持一致
+const (
+	TypeRSS            = "rss"
+	TypeTelegramExport = "telegram_export"
+	TypeHTML           = "html"
+	TypeJSONAPI        = "json_api"
+)
+
+// 默认抓取参数
+const (
+	defaultTimeout   = 30 * time.Second
+	defaultUserAgent = "Mozilla/5.0 (compatible; urldb-crawler/1.0)"
+	maxBodySize      = 20 << 20 // 单次抓取最多读取 20MB
+)
+
+// Item 抓取到的一条内容
+type Item struct {
+	GUID        string    `json:"guid"`         // 源内唯一标识，缺省时由链接生成
+	Title       string    `json:"title"`        // 标题
+	Description string    `json:"description"`  // 描述
+	Link        string    `json:"link"`         // 原文链接
+	URLs        []string  `json:"urls"`         // 提取到的网盘链接
+	Cover       string    `json:"cover"`        // 封面
+	Category    string    `json:"category"`     // 分类（源内提供时）
+	Tags        []string  `json:"tags"`         // 标签（源内提供时）
+	PublishedAt time.Time `json:"published_at"` // 发布时间，零值表示未知
+}
+
+// Config 内容源的声明式配置，以 JSON 形式保存在 content_sources.config 中
+type Config struct {
+	// 通用
+	Headers        map[string]string `json:"headers,omitempty"`         // 额外请求头
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // 请求超时（秒）
+
+	// HTML：SelectorType 为 css（默认）或 xpath；字段选择器支持 "selector@attr" 取属性
+	SelectorType        string `json:"selector_type,omitempty"`
+	ItemSelector        string `json:"item_selector,omitempty"`
+	TitleSelector       string `json:"title_selector,omitempty"`
+	LinkSelector        string `json:"link_selector,omitempty"`
+	DescriptionSelector string `json:"description_selector,omitempty"`
+	CoverSelector       string `json:"cover_selector,omitempty"`
+	DateSelector        string `json:"date_selector,omitempty"`
+
+	// JSON API：ItemsPath 为点分路径（如 data.list），Fields 为 字段名 -> 点分路径
+	ItemsPath string            `json:"items_path,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// ParseConfig 解析内容源配置，空字符串返回零值配置
+func ParseConfig(raw string) (*Config, error) {
+	cfg := &Config{}
+	if strings.TrimSpace(raw) == "" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal([]byte(raw), cfg); err != nil {
+		return nil, fmt.Errorf("解析内容源配置失败: %v", err)
+	}
+	return cfg, nil
+}
+
+// Parser 将原始响应解析为内容条目
+type Parser func(body []byte, baseURL string, cfg *Config) ([]Item, error)
+
+var parsers = map[string]Parser{
+	TypeRSS:            ParseFeed,
+	TypeTelegramExport: ParseTelegramExport,
+	TypeHTML:           ParseHTML,
+	TypeJSONAPI:        ParseJSONAPI,
+}
+
+// IsSupportedType 判断内容源类型是否受支持
+func IsSupportedType(sourceType string) bool {
+	_, ok := parsers[sourceType]
+	return ok
+}
+
+// Crawler 内容源抓取器
+type Crawler struct {
+	client    *http.Client
+	importDir string // Telegram 导出文件的导入目录，为空时不支持 file:// 地址
+}
+
+// NewCrawler 创建抓取器，client 为 nil 时使用默认客户端
+func NewCrawler(client *http.Client) *Crawler {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Crawler{client: client}
+}
+
+// SetImportDir 设置导入目录，Telegram 导出类型的 file:// 地址只能读取该目录内的文件
+func (c *Crawler) SetImportDir(dir string) {
+	c.importDir = dir
+}
+
+// Fetch 抓取并解析内容源，返回已补齐 GUID 的条目
+func (c *Crawler) Fetch(ctx context.Context, sourceType, sourceURL string, cfg *Config) ([]Item, error) {
+	parser, ok := parsers[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的内容源类型: %s", sourceType)
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	body, err := c.load(ctx, sourceType, sourceURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := parser(body, sourceURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Title = strings.TrimSpace(items[i].Title)
+		items[i].Description = strings.TrimSpace(items[i].Description)
+		if items[i].GUID == "" {
+			items[i].GUID = ItemHash(items[i])
+		}
+	}
+	return items, nil
+}
+
+// load 读取内容源原始数据，支持 http(s)；Telegram 导出类型另支持 file:// 读取导入目录内的文件
+func (c *Crawler) load(ctx context.Context, sourceType, sourceURL string, cfg *Config) ([]byte, error) {
+	if strings.HasPrefix(sourceURL, "file://") {
+		if sourceType != TypeTelegramExport {
+			return nil, fmt.Errorf("仅 Telegram 导出类型支持 file:// 地址")
+		}
+		return c.loadImportFile(strings.TrimPrefix(sourceURL, "file://"))
+	}
+
+	if cfg.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求内容源失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("内容源返回异常状态码: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+}
+
+// loadImportFile 读取导入目录内的文件，name 可以是相对导入目录的路径；
+// 解析符号链接后仍须位于导入目录内，防止借内容源读取服务器上的任意文件
+func (c *Crawler) loadImportFile(name string) ([]byte, error) {
+	if c.importDir == "" {
+		return nil, fmt.Errorf("未配置导入目录，不支持 file:// 地址")
+	}
+	root, err := filepath.Abs(c.importDir)
+	if err != nil {
+		return nil, fmt.Errorf("解析导入目录失败: %v", err)
+	}
+	path := filepath.Clean(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if !withinDir(root, path) {
+		return nil, fmt.Errorf("文件不在导入目录内: %s", name)
+	}
+
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, fmt.Errorf("解析导入目录失败: %v", err)
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return nil, fmt.Errorf("读取导入文件失败: %v", err)
+	}
+	if !withinDir(root, path) {
+		return nil, fmt.Errorf("文件不在导入目录内: %s", name)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取导入文件失败: %v", err)
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, maxBodySize))
+}
+
+// withinDir 判断 path 是否为 dir 下的文件（不含 dir 本身）
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." {
+		return false
+	}
+	return !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ItemHash 计算条目的去重哈希：优先 GUID，其次原文链接与网盘链接
+func ItemHash(item Item) string {
+	key := item.GUID
+	if key == "" {
+		key = item.Link + "|" + strings.Join(item.URLs, "|")
+		if key == "|" {
+			key = item.Title
+		}
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseTime 尝试按常见格式解析时间字符串，失败返回零值
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	layouts := []string{
+		time.RFC3339,
+		time.RFC3339Nano,
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC822Z,
+		time.RFC822,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
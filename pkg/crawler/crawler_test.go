@@ -0,0 +1,350 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const rssFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+  <title>资源订阅</title>
+  <item>
+    <title>流浪地球2 4K</title>
+    <link>https://blog.example.com/posts/1</link>
+    <guid>post-1</guid>
+    <pubDate>Mon, 02 Jan 2006 15:04:05 +0800</pubDate>
+    <category>电影</category>
+    <category>科幻</category>
+    <description><![CDATA[<p>夸克：<a href="https://pan.quark.cn/s/abc123">https://pan.quark.cn/s/abc123</a></p><img src="https://img.example.com/1.jpg">]]></description>
+  </item>
+  <item>
+    <title>无网盘链接</title>
+    <link>https://blog.example.com/posts/2</link>
+    <description>nothing here</description>
+  </item>
+</channel>
+</rss>`
+
+const atomFixture = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom 源</title>
+  <entry>
+    <id>urn:entry:1</id>
+    <title>三体 全集</title>
+    <link href="https://site.example.com/e/1"/>
+    <updated>2024-05-01T10:00:00Z</updated>
+    <category term="电视剧"/>
+    <summary>百度 https://pan.baidu.com/s/1abcdEFG 提取码: wxyz</summary>
+  </entry>
+</feed>`
+
+const telegramFixture = `{
+  "name": "资源频道",
+  "type": "public_channel",
+  "id": 1001,
+  "messages": [
+    {"id": 1, "type": "service", "date": "2024-01-01T00:00:00", "text": ""},
+    {"id": 2, "type": "message", "date": "2024-01-02T08:00:00", "date_unixtime": "1704153600",
+     "photo": "photos/photo_2.jpg",
+     "text": ["名称：繁花 (2023)\n描述：王家卫首部电视剧\n链接：", {"type": "link", "text": "https://pan.quark.cn/s/fanhua01"}, "\n#电视剧 #国产"]},
+    {"id": 3, "type": "message", "date": "2024-01-03T08:00:00", "text": "只是一条闲聊"},
+    {"id": 4, "type": "message", "date": "2024-01-04T08:00:00",
+     "text": ["狂飙 全39集\n", {"type": "text_link", "text": "点我转存", "href": "https://www.alipan.com/s/kuangbiao"}]}
+  ]
+}`
+
+const htmlFixture = `<html><body>
+<ul id="list">
+  <li class="post hot"><a class="title" href="/detail/1">庆余年2</a><span class="desc">第二季</span>
+      <a href="https://pan.quark.cn/s/qyn2">下载</a><img src="/covers/1.jpg"><time>2024-05-16</time></li>
+  <li class="post"><a class="title" href="/detail/2">长相思</a>
+      <a href="https://pan.xunlei.com/s/cxs">下载</a></li>
+</ul>
+<div class="ad"><a href="https://pan.quark.cn/s/ad">广告</a></div>
+</body></html>`
+
+const jsonFixture = `{
+  "code": 0,
+  "data": {
+    "list": [
+      {"id": 11, "name": "周处除三害", "intro": "台湾电影", "links": ["https://pan.quark.cn/s/zhouchu", "https://example.com/x"],
+       "poster": "https://img.example.com/z.jpg", "type": "电影", "tags": "犯罪,动作", "ts": 1709251200},
+      {"id": 12, "name": "沙丘2 https://pan.baidu.com/s/1dune2", "intro": "", "links": [], "ts": "2024-03-08"}
+    ]
+  }
+}`
+
+func newFixtureServer(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") == "deny" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchRSS(t *testing.T) {
+	srv := newFixtureServer(t, "application/rss+xml", rssFixture)
+	items, err := NewCrawler(nil).Fetch(context.Background(), TypeRSS, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	it := items[0]
+	if it.GUID != "post-1" || it.Title != "流浪地球2 4K" {
+		t.Fatalf("unexpected item: %+v", it)
+	}
+	if len(it.URLs) != 1 || it.URLs[0] != "https://pan.quark.cn/s/abc123" {
+		t.Fatalf("URLs = %v", it.URLs)
+	}
+	if it.Cover != "https://img.example.com/1.jpg" || it.Category != "电影" || len(it.Tags) != 1 || it.Tags[0] != "科幻" {
+		t.Fatalf("cover/category/tags = %q %q %v", it.Cover, it.Category, it.Tags)
+	}
+	if it.PublishedAt.IsZero() {
+		t.Fatalf("PublishedAt not parsed")
+	}
+	if len(items[1].URLs) != 0 || items[1].GUID == "" {
+		t.Fatalf("second item should have no URLs and a generated GUID: %+v", items[1])
+	}
+}
+
+func TestFetchAtom(t *testing.T) {
+	srv := newFixtureServer(t, "application/atom+xml", atomFixture)
+	items, err := NewCrawler(nil).Fetch(context.Background(), TypeRSS, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	it := items[0]
+	if it.GUID != "urn:entry:1" || it.Link != "https://site.example.com/e/1" || it.Category != "电视剧" {
+		t.Fatalf("unexpected item: %+v", it)
+	}
+	if len(it.URLs) != 1 || it.URLs[0] != "https://pan.baidu.com/s/1abcdEFG?pwd=wxyz" {
+		t.Fatalf("URLs = %v", it.URLs)
+	}
+}
+
+func TestFetchTelegramExport(t *testing.T) {
+	srv := newFixtureServer(t, "application/json", telegramFixture)
+	items, err := NewCrawler(nil).Fetch(context.Background(), TypeTelegramExport, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	first := items[0]
+	if first.GUID != "tg:1001:2" || first.Title != "繁花 (2023)" || first.Description != "王家卫首部电视剧" {
+		t.Fatalf("unexpected first item: %+v", first)
+	}
+	if first.Cover != "photos/photo_2.jpg" || len(first.Tags) != 2 || first.PublishedAt.Unix() != 1704153600 {
+		t.Fatalf("cover/tags/time = %q %v %v", first.Cover, first.Tags, first.PublishedAt)
+	}
+
+	second := items[1]
+	if second.Title != "狂飙 全39集" || len(second.URLs) != 1 || second.URLs[0] != "https://www.alipan.com/s/kuangbiao" {
+		t.Fatalf("text_link href should be extracted: %+v", second)
+	}
+}
+
+func TestFetchTelegramExportFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "result.json")
+	if err := os.WriteFile(path, []byte(telegramFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCrawler(nil)
+	c.SetImportDir(dir)
+
+	for _, url := range []string{"file://" + path, "file://result.json"} {
+		items, err := c.Fetch(context.Background(), TypeTelegramExport, url, nil)
+		if err != nil {
+			t.Fatalf("Fetch(%s): %v", url, err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("Fetch(%s) got %d items, want 2", url, len(items))
+		}
+	}
+}
+
+func TestFetchFileRestrictedToImportDir(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "imports")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(base, "secret.json")
+	if err := os.WriteFile(secret, []byte(telegramFixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(dir, "link.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewCrawler(nil).Fetch(context.Background(), TypeTelegramExport, "file://"+secret, nil); err == nil {
+		t.Fatal("file:// without import dir should be rejected")
+	}
+
+	c := NewCrawler(nil)
+	c.SetImportDir(dir)
+	cases := []struct {
+		sourceType string
+		url        string
+	}{
+		{TypeTelegramExport, "file://" + secret},
+		{TypeTelegramExport, "file://../secret.json"},
+		{TypeTelegramExport, "file://" + dir + "/../secret.json"},
+		{TypeTelegramExport, "file://link.json"},
+		{TypeRSS, "file://" + filepath.Join(dir, "feed.xml")},
+	}
+	for _, tc := range cases {
+		if _, err := c.Fetch(context.Background(), tc.sourceType, tc.url, nil); err == nil {
+			t.Errorf("Fetch(%s, %s) should be rejected", tc.sourceType, tc.url)
+		}
+	}
+}
+
+func TestFetchHTML(t *testing.T) {
+	srv := newFixtureServer(t, "text/html", htmlFixture)
+
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"css", &Config{
+			ItemSelector:        "ul#list > li.post",
+			TitleSelector:       "a.title",
+			DescriptionSelector: ".desc",
+			DateSelector:        "time",
+		}},
+		{"xpath", &Config{
+			SelectorType:        SelectorXPath,
+			ItemSelector:        "//ul[@id='list']/li[contains(@class,'post')]",
+			TitleSelector:       "./a[@class='title']/text()",
+			DescriptionSelector: ".//span[@class='desc']",
+			DateSelector:        ".//time",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := NewCrawler(nil).Fetch(context.Background(), TypeHTML, srv.URL+"/list", tt.cfg)
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if len(items) != 2 {
+				t.Fatalf("got %d items, want 2", len(items))
+			}
+			it := items[0]
+			if it.Title != "庆余年2" || it.Description != "第二季" {
+				t.Fatalf("title/desc = %q %q", it.Title, it.Description)
+			}
+			if it.Link != srv.URL+"/detail/1" || it.GUID != it.Link {
+				t.Fatalf("link = %q guid = %q", it.Link, it.GUID)
+			}
+			if it.Cover != srv.URL+"/covers/1.jpg" || it.PublishedAt.IsZero() {
+				t.Fatalf("cover/date = %q %v", it.Cover, it.PublishedAt)
+			}
+			if len(it.URLs) != 1 || it.URLs[0] != "https://pan.quark.cn/s/qyn2" {
+				t.Fatalf("URLs = %v", it.URLs)
+			}
+			if len(items[1].URLs) != 1 || items[1].URLs[0] != "https://pan.xunlei.com/s/cxs" {
+				t.Fatalf("second URLs = %v", items[1].URLs)
+			}
+		})
+	}
+}
+
+func TestFetchJSONAPI(t *testing.T) {
+	srv := newFixtureServer(t, "application/json", jsonFixture)
+	cfg := &Config{
+		ItemsPath: "data.list",
+		Fields: map[string]string{
+			FieldGUID:        "id",
+			FieldTitle:       "name",
+			FieldDescription: "intro",
+			FieldURL:         "links",
+			FieldCover:       "poster",
+			FieldCategory:    "type",
+			FieldTags:        "tags",
+			FieldPublishedAt: "ts",
+		},
+	}
+	items, err := NewCrawler(nil).Fetch(context.Background(), TypeJSONAPI, srv.URL, cfg)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	it := items[0]
+	if it.GUID != "11" || it.Title != "周处除三害" || it.Category != "电影" || len(it.Tags) != 2 {
+		t.Fatalf("unexpected item: %+v", it)
+	}
+	if len(it.URLs) != 1 || it.URLs[0] != "https://pan.quark.cn/s/zhouchu" || it.PublishedAt.Unix() != 1709251200 {
+		t.Fatalf("URLs/time = %v %v", it.URLs, it.PublishedAt)
+	}
+	// url 已映射时不从标题中提取链接
+	if len(items[1].URLs) != 0 {
+		t.Fatalf("second URLs = %v", items[1].URLs)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	srv := newFixtureServer(t, "text/plain", "ok")
+
+	if _, err := NewCrawler(nil).Fetch(context.Background(), "unknown", srv.URL, nil); err == nil {
+		t.Fatalf("expected error for unknown type")
+	}
+	cfg := &Config{ItemSelector: "li", Headers: map[string]string{"X-Token": "deny"}}
+	if _, err := NewCrawler(nil).Fetch(context.Background(), TypeHTML, srv.URL, cfg); err == nil {
+		t.Fatalf("expected error for non-2xx status")
+	}
+	if _, err := NewCrawler(nil).Fetch(context.Background(), TypeHTML, srv.URL, &Config{}); err == nil {
+		t.Fatalf("expected error for missing item_selector")
+	}
+}
+
+func TestExtractPanLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"plain", "夸克 https://pan.quark.cn/s/abc", []string{"https://pan.quark.cn/s/abc"}},
+		{"passcode", "https://pan.baidu.com/s/1xyz 提取码：ab12", []string{"https://pan.baidu.com/s/1xyz?pwd=ab12"}},
+		{"existing pwd kept", "https://pan.baidu.com/s/1xyz?pwd=zz99 密码: ab12", []string{"https://pan.baidu.com/s/1xyz?pwd=zz99"}},
+		{"passcode not shared", "https://pan.quark.cn/s/a https://pan.baidu.com/s/1b 提取码: cd34",
+			[]string{"https://pan.quark.cn/s/a", "https://pan.baidu.com/s/1b?pwd=cd34"}},
+		{"dedupe and filter", "https://pan.quark.cn/s/a，https://example.com/x https://pan.quark.cn/s/a", []string{"https://pan.quark.cn/s/a"}},
+		{"none", "no links", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractPanLinks(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractPanLinks(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ExtractPanLinks(%q) = %v, want %v", tt.text, got, tt.want)
+				}
+			}
+		})
+	}
+}
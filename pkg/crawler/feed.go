@@ -0,0 +1,188 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// rssDocument RSS 2.0 文档
+type rssDocument struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Enclosure   struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Thumbnail struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+// atomDocument Atom 1.0 文档
+type atomDocument struct {
+	XMLName xml.Name    `xml:"feed"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Updated   string `xml:"updated"`
+	Published string `xml:"published"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+var (
+	imgSrcPattern = regexp.MustCompile(`(?i)<img[^>]+src=["']([^"']+)["']`)
+	tagPattern    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseFeed 解析 RSS 2.0 或 Atom 1.0 订阅
+func ParseFeed(body []byte, _ string, _ *Config) ([]Item, error) {
+	trimmed := bytes.TrimSpace(body)
+	if bytes.Contains(trimmed[:min(len(trimmed), 512)], []byte("<feed")) {
+		return parseAtom(trimmed)
+	}
+	return parseRSS(trimmed)
+}
+
+func parseRSS(body []byte) ([]Item, error) {
+	var doc rssDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("解析RSS失败: %v", err)
+	}
+
+	items := make([]Item, 0, len(doc.Channel.Items))
+	for _, it := range doc.Channel.Items {
+		content := it.Description
+		if it.Content != "" {
+			content = it.Content
+		}
+
+		cover := it.Thumbnail.URL
+		if cover == "" && strings.HasPrefix(it.Enclosure.Type, "image/") {
+			cover = it.Enclosure.URL
+		}
+		if cover == "" {
+			cover = firstImage(content)
+		}
+
+		item := Item{
+			GUID:        strings.TrimSpace(it.GUID),
+			Title:       html.UnescapeString(it.Title),
+			Description: stripTags(content),
+			Link:        strings.TrimSpace(it.Link),
+			URLs:        ExtractPanLinks(it.Link + "\n" + html.UnescapeString(content)),
+			Cover:       cover,
+			PublishedAt: parseTime(it.PubDate),
+		}
+		if len(it.Categories) > 0 {
+			item.Category = strings.TrimSpace(it.Categories[0])
+			item.Tags = trimAll(it.Categories[1:])
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseAtom(body []byte) ([]Item, error) {
+	var doc atomDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("解析Atom失败: %v", err)
+	}
+
+	items := make([]Item, 0, len(doc.Entries))
+	for _, e := range doc.Entries {
+		content := e.Summary
+		if e.Content != "" {
+			content = e.Content
+		}
+
+		var link, cover string
+		for _, l := range e.Links {
+			switch {
+			case l.Rel == "enclosure" && strings.HasPrefix(l.Type, "image/"):
+				cover = l.Href
+			case link == "" && (l.Rel == "" || l.Rel == "alternate"):
+				link = l.Href
+			}
+		}
+		if cover == "" {
+			cover = firstImage(content)
+		}
+
+		published := parseTime(e.Published)
+		if published.IsZero() {
+			published = parseTime(e.Updated)
+		}
+
+		item := Item{
+			GUID:        strings.TrimSpace(e.ID),
+			Title:       html.UnescapeString(e.Title),
+			Description: stripTags(content),
+			Link:        link,
+			URLs:        ExtractPanLinks(link + "\n" + html.UnescapeString(content)),
+			Cover:       cover,
+			PublishedAt: published,
+		}
+		for i, c := range e.Categories {
+			if i == 0 {
+				item.Category = c.Term
+			} else {
+				item.Tags = append(item.Tags, c.Term)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// firstImage 取 HTML 片段中第一张图片地址
+func firstImage(fragment string) string {
+	if m := imgSrcPattern.FindStringSubmatch(html.UnescapeString(fragment)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// stripTags 去除 HTML 标签并反转义实体
+func stripTags(fragment string) string {
+	text := html.UnescapeString(fragment)
+	text = tagPattern.ReplaceAllString(text, " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func trimAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
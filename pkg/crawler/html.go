@@ -0,0 +1,199 @@
+package crawler
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 选择器类型
+const (
+	SelectorCSS   = "css"
+	SelectorXPath = "xpath"
+)
+
+// nodeQuery 选取节点
+type nodeQuery func(*html.Node) []*html.Node
+
+// fieldQuery 从节点中取值（文本或属性）
+type fieldQuery func(*html.Node) []string
+
+// ParseHTML 按 item_selector 切分条目，再用各字段选择器取值
+func ParseHTML(body []byte, baseURL string, cfg *Config) ([]Item, error) {
+	if cfg == nil || strings.TrimSpace(cfg.ItemSelector) == "" {
+		return nil, fmt.Errorf("HTML 内容源需要配置 item_selector")
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("解析HTML失败: %v", err)
+	}
+
+	selectItems, err := compileNodeQuery(cfg.SelectorType, cfg.ItemSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	linkSel := cfg.LinkSelector
+	coverSel := cfg.CoverSelector
+	if cfg.SelectorType == SelectorXPath {
+		if linkSel == "" {
+			linkSel = ".//a/@href"
+		}
+		if coverSel == "" {
+			coverSel = ".//img/@src"
+		}
+	} else {
+		if linkSel == "" {
+			linkSel = "a@href"
+		}
+		if coverSel == "" {
+			coverSel = "img@src"
+		}
+	}
+
+	fields := map[string]fieldQuery{}
+	for name, expr := range map[string]string{
+		"title":       cfg.TitleSelector,
+		"link":        linkSel,
+		"description": cfg.DescriptionSelector,
+		"cover":       coverSel,
+		"date":        cfg.DateSelector,
+	} {
+		if expr == "" {
+			continue
+		}
+		q, err := compileFieldQuery(cfg.SelectorType, expr)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 选择器无效: %v", name, err)
+		}
+		fields[name] = q
+	}
+
+	base, _ := url.Parse(baseURL)
+	first := func(name string, n *html.Node) string {
+		if q, ok := fields[name]; ok {
+			for _, v := range q(n) {
+				if v = strings.TrimSpace(v); v != "" {
+					return v
+				}
+			}
+		}
+		return ""
+	}
+
+	var items []Item
+	for _, n := range selectItems(doc) {
+		var links []string
+		for _, href := range fields["link"](n) {
+			if abs := resolveURL(base, href); abs != "" {
+				links = append(links, abs)
+			}
+		}
+
+		item := Item{
+			Title:       first("title", n),
+			Description: first("description", n),
+			Cover:       resolveURL(base, first("cover", n)),
+			PublishedAt: parseTime(first("date", n)),
+		}
+		if item.Title == "" {
+			item.Title = nodeText(n)
+		}
+		item.URLs = ExtractPanLinks(strings.Join(links, "\n") + "\n" + nodeText(n))
+		for _, l := range links {
+			if len(ExtractPanLinks(l)) == 0 {
+				item.Link = l
+				break
+			}
+		}
+		if item.Link == "" && len(links) > 0 {
+			item.Link = links[0]
+		}
+		item.GUID = item.Link
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// compileNodeQuery 编译条目选择器
+func compileNodeQuery(selectorType, expr string) (nodeQuery, error) {
+	if selectorType == SelectorXPath {
+		x, err := compileXPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		return x.selectAll, nil
+	}
+	sel, err := compileCSS(expr)
+	if err != nil {
+		return nil, err
+	}
+	return sel.selectAll, nil
+}
+
+// compileFieldQuery 编译字段选择器。
+// CSS 形如 "a.title"（取文本）或 "a@href"（取属性），"@href" 表示取条目自身属性；
+// XPath 以 /@attr 或 /text() 结尾取值，否则取文本。
+func compileFieldQuery(selectorType, expr string) (fieldQuery, error) {
+	if selectorType == SelectorXPath {
+		x, err := compileXPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		return x.values, nil
+	}
+
+	selExpr, attrName := expr, ""
+	parts := splitOutside(expr, '@')
+	if len(parts) > 1 {
+		attrName = strings.TrimSpace(parts[len(parts)-1])
+		selExpr = strings.Join(parts[:len(parts)-1], "@")
+	}
+	selExpr = strings.TrimSpace(selExpr)
+
+	var sel cssSelector
+	if selExpr != "" {
+		var err error
+		if sel, err = compileCSS(selExpr); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(n *html.Node) []string {
+		nodes := []*html.Node{n}
+		if sel != nil {
+			nodes = sel.selectAll(n)
+		}
+		var out []string
+		for _, m := range nodes {
+			if attrName != "" {
+				if v, ok := attrOK(m, attrName); ok {
+					out = append(out, strings.TrimSpace(v))
+				}
+				continue
+			}
+			out = append(out, nodeText(m))
+		}
+		return out
+	}, nil
+}
+
+// resolveURL 将相对地址转换为绝对地址
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "javascript:") || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base == nil {
+		return u.String()
+	}
+	return base.ResolveReference(u).String()
+}
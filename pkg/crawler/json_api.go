@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSON 字段映射支持的字段名
+const (
+	FieldGUID        = "guid"
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldURL         = "url"
+	FieldLink        = "link"
+	FieldCover       = "cover"
+	FieldCategory    = "category"
+	FieldTags        = "tags"
+	FieldPublishedAt = "published_at"
+)
+
+// ParseJSONAPI 按 items_path 定位条目数组，再按 fields 映射取值。
+// url 字段可指向字符串或字符串数组；未映射 url 时从标题与描述中提取网盘链接。
+func ParseJSONAPI(body []byte, _ string, cfg *Config) ([]Item, error) {
+	if cfg == nil || len(cfg.Fields) == 0 {
+		return nil, fmt.Errorf("JSON 内容源需要配置 fields 字段映射")
+	}
+
+	var root interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, fmt.Errorf("解析JSON失败: %v", err)
+	}
+
+	node, ok := lookupPath(root, cfg.ItemsPath)
+	if !ok {
+		return nil, fmt.Errorf("未找到条目路径: %s", cfg.ItemsPath)
+	}
+	list, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("条目路径 %s 不是数组", cfg.ItemsPath)
+	}
+
+	items := make([]Item, 0, len(list))
+	for _, raw := range list {
+		get := func(field string) interface{} {
+			path, ok := cfg.Fields[field]
+			if !ok || path == "" {
+				return nil
+			}
+			v, _ := lookupPath(raw, path)
+			return v
+		}
+
+		item := Item{
+			GUID:        toString(get(FieldGUID)),
+			Title:       toString(get(FieldTitle)),
+			Description: toString(get(FieldDescription)),
+			Link:        toString(get(FieldLink)),
+			Cover:       toString(get(FieldCover)),
+			Category:    toString(get(FieldCategory)),
+			Tags:        toStrings(get(FieldTags)),
+			PublishedAt: toTime(get(FieldPublishedAt)),
+		}
+
+		text := strings.Join(toStrings(get(FieldURL)), "\n")
+		if _, mapped := cfg.Fields[FieldURL]; !mapped {
+			text = item.Title + "\n" + item.Description
+		}
+		item.URLs = ExtractPanLinks(text)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// lookupPath 按点分路径取值，数字段作为数组下标；空路径返回自身
+func lookupPath(v interface{}, path string) (interface{}, bool) {
+	path = strings.TrimSpace(path)
+	if path == "" || path == "." {
+		return v, true
+	}
+	for _, seg := range strings.Split(path, ".") {
+		switch cur := v.(type) {
+		case map[string]interface{}:
+			next, ok := cur[seg]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(cur) {
+				return nil, false
+			}
+			v = cur[idx]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(val)
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		return strings.Join(toStrings(val), ",")
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+// toStrings 数组逐项转换；字符串按逗号拆分
+func toStrings(v interface{}) []string {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var out []string
+		for _, e := range val {
+			if s := toString(e); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		return trimAll(strings.Split(val, ","))
+	default:
+		if s := toString(val); s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+}
+
+// toTime 支持时间字符串与 Unix 时间戳（秒或毫秒）
+func toTime(v interface{}) time.Time {
+	switch val := v.(type) {
+	case json.Number:
+		n, err := val.Int64()
+		if err != nil || n <= 0 {
+			return time.Time{}
+		}
+		if n > 1e12 {
+			return time.UnixMilli(n)
+		}
+		return time.Unix(n, 0)
+	case string:
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return toTime(json.Number(strconv.FormatInt(n, 10)))
+		}
+		return parseTime(val)
+	default:
+		return time.Time{}
+	}
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+
+	panutils "github.com/ctwj/urldb/common"
+)
+
+var (
+	// urlPattern 匹配文本中的 http(s) 链接，遇到空白、引号、尖括号及中文标点截止
+	urlPattern = regexp.MustCompile(`https?://[^\s"'<>()（）\[\]【】，。；、]+`)
+	// passcodePattern 匹配链接后的提取码，如 "提取码: abcd"、"密码：1234"
+	passcodePattern = regexp.MustCompile(`(?:提取码|密码|访问码|pwd)\s*[:：=]?\s*([A-Za-z0-9]{4,6})`)
+)
+
+// ExtractPanLinks 从文本中提取受支持网盘的分享链接（按出现顺序去重）。
+// 链接本身不带 pwd 参数且其后紧跟提取码时，会补齐为 ?pwd=xxxx。
+func ExtractPanLinks(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	locs := urlPattern.FindAllStringIndex(text, -1)
+	for i, loc := range locs {
+		link := strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?")
+		if panutils.ExtractServiceType(link) == panutils.NotFound {
+			continue
+		}
+
+		if !strings.Contains(strings.ToLower(link), "pwd=") {
+			// 只在当前链接与下一个链接之间查找提取码，避免串用
+			end := len(text)
+			if i+1 < len(locs) {
+				end = locs[i+1][0]
+			}
+			if m := passcodePattern.FindStringSubmatch(text[loc[1]:end]); m != nil {
+				sep := "?"
+				if strings.Contains(link, "?") {
+					sep = "&"
+				}
+				link = link + sep + "pwd=" + m[1]
+			}
+		}
+
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// extractFirstURL 提取文本中的第一个链接（不限网盘），用于封面等字段
+func extractFirstURL(text string) string {
+	return urlPattern.FindString(text)
+}
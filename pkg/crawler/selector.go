@@ -0,0 +1,330 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 支持的 CSS 选择器子集：
+//   - 类型 / 通配：div、*
+//   - 类与 ID：.item、#list、div.item.hot
+//   - 属性：[href]、[href=x]、[href^=x]、[href$=x]、[href*=x]、[class~=x]
+//   - 组合器：后代（空格）、子元素（>）
+//   - 选择器列表：a, b
+
+// cssSelector 逗号分隔的选择器列表
+type cssSelector []cssComplex
+
+// cssComplex 由组合器连接的复合选择器，按从左到右顺序存放
+type cssComplex struct {
+	parts       []cssCompound
+	combinators []byte // combinators[i] 连接 parts[i] 与 parts[i+1]，取值 ' ' 或 '>'
+}
+
+type cssCompound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []cssAttr
+}
+
+type cssAttr struct {
+	name  string
+	op    string // "" 仅判断存在；"=", "^=", "$=", "*=", "~="
+	value string
+}
+
+// compileCSS 解析 CSS 选择器
+func compileCSS(expr string) (cssSelector, error) {
+	var sel cssSelector
+	for _, group := range splitOutside(expr, ',') {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		complexSel, err := parseComplex(group)
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, complexSel)
+	}
+	if len(sel) == 0 {
+		return nil, fmt.Errorf("选择器为空")
+	}
+	return sel, nil
+}
+
+func parseComplex(expr string) (cssComplex, error) {
+	var c cssComplex
+	var buf strings.Builder
+	pending := byte(0)
+	depth := 0
+
+	flush := func() error {
+		token := strings.TrimSpace(buf.String())
+		buf.Reset()
+		if token == "" {
+			return nil
+		}
+		compound, err := parseCompound(token)
+		if err != nil {
+			return err
+		}
+		if len(c.parts) > 0 {
+			if pending == 0 {
+				pending = ' '
+			}
+			c.combinators = append(c.combinators, pending)
+		}
+		c.parts = append(c.parts, compound)
+		pending = 0
+		return nil
+	}
+
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case ch == '[':
+			depth++
+			buf.WriteByte(ch)
+		case ch == ']':
+			depth--
+			buf.WriteByte(ch)
+		case depth == 0 && (ch == ' ' || ch == '\t' || ch == '\n'):
+			if err := flush(); err != nil {
+				return c, err
+			}
+		case depth == 0 && ch == '>':
+			if err := flush(); err != nil {
+				return c, err
+			}
+			pending = '>'
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	if err := flush(); err != nil {
+		return c, err
+	}
+	if len(c.parts) == 0 || pending != 0 {
+		return c, fmt.Errorf("无效的选择器: %s", expr)
+	}
+	return c, nil
+}
+
+func parseCompound(token string) (cssCompound, error) {
+	var cp cssCompound
+	i := 0
+	readName := func() string {
+		start := i
+		for i < len(token) && token[i] != '.' && token[i] != '#' && token[i] != '[' {
+			i++
+		}
+		return token[start:i]
+	}
+
+	if token[0] != '.' && token[0] != '#' && token[0] != '[' {
+		cp.tag = strings.ToLower(readName())
+	}
+	for i < len(token) {
+		switch token[i] {
+		case '.':
+			i++
+			cp.classes = append(cp.classes, readName())
+		case '#':
+			i++
+			cp.id = readName()
+		case '[':
+			end := strings.IndexByte(token[i:], ']')
+			if end < 0 {
+				return cp, fmt.Errorf("属性选择器未闭合: %s", token)
+			}
+			cp.attrs = append(cp.attrs, parseAttr(token[i+1:i+end]))
+			i += end + 1
+		default:
+			return cp, fmt.Errorf("无效的选择器: %s", token)
+		}
+	}
+	return cp, nil
+}
+
+func parseAttr(body string) cssAttr {
+	for _, op := range []string{"^=", "$=", "*=", "~=", "="} {
+		if idx := strings.Index(body, op); idx > 0 {
+			return cssAttr{
+				name:  strings.ToLower(strings.TrimSpace(body[:idx])),
+				op:    op,
+				value: strings.Trim(strings.TrimSpace(body[idx+len(op):]), `"'`),
+			}
+		}
+	}
+	return cssAttr{name: strings.ToLower(strings.TrimSpace(body))}
+}
+
+// selectAll 返回 root 子树中（不含 root 自身）匹配的元素，按文档顺序
+func (s cssSelector) selectAll(root *html.Node) []*html.Node {
+	var out []*html.Node
+	walkElements(root, func(n *html.Node) {
+		if n == root {
+			return
+		}
+		for _, c := range s {
+			if c.matches(n, root) {
+				out = append(out, n)
+				return
+			}
+		}
+	})
+	return out
+}
+
+// matches 从右向左匹配，祖先查找不越过 scope
+func (c cssComplex) matches(n, scope *html.Node) bool {
+	return c.matchAt(len(c.parts)-1, n, scope)
+}
+
+func (c cssComplex) matchAt(idx int, n, scope *html.Node) bool {
+	if !c.parts[idx].matches(n) {
+		return false
+	}
+	if idx == 0 {
+		return true
+	}
+	switch c.combinators[idx-1] {
+	case '>':
+		p := n.Parent
+		return p != nil && p != scope && p.Type == html.ElementNode && c.matchAt(idx-1, p, scope)
+	default:
+		for p := n.Parent; p != nil && p != scope; p = p.Parent {
+			if p.Type == html.ElementNode && c.matchAt(idx-1, p, scope) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (cp cssCompound) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if cp.tag != "" && cp.tag != "*" && cp.tag != n.Data {
+		return false
+	}
+	if cp.id != "" && attr(n, "id") != cp.id {
+		return false
+	}
+	if len(cp.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, want := range cp.classes {
+			if !containsString(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range cp.attrs {
+		val, ok := attrOK(n, a.name)
+		if !ok {
+			return false
+		}
+		switch a.op {
+		case "=":
+			ok = val == a.value
+		case "^=":
+			ok = strings.HasPrefix(val, a.value)
+		case "$=":
+			ok = strings.HasSuffix(val, a.value)
+		case "*=":
+			ok = strings.Contains(val, a.value)
+		case "~=":
+			ok = containsString(strings.Fields(val), a.value)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// walkElements 以文档顺序遍历元素节点（含 root）
+func walkElements(root *html.Node, fn func(*html.Node)) {
+	if root.Type == html.ElementNode || root.Type == html.DocumentNode {
+		fn(root)
+	}
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		walkElements(c, fn)
+	}
+}
+
+func attrOK(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func attr(n *html.Node, name string) string {
+	v, _ := attrOK(n, name)
+	return v
+}
+
+// nodeText 返回节点的可见文本（合并空白）
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.TextNode {
+			b.WriteString(node.Data)
+			b.WriteByte(' ')
+			return
+		}
+		if node.Type == html.ElementNode && (node.Data == "script" || node.Data == "style") {
+			return
+		}
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// splitOutside 按分隔符切分，忽略方括号、圆括号与引号内的分隔符
+func splitOutside(expr string, sep byte) []string {
+	var parts []string
+	depth := 0
+	quote := byte(0)
+	start := 0
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '[' || ch == '(':
+			depth++
+		case ch == ']' || ch == ')':
+			depth--
+		case ch == sep && depth == 0:
+			parts = append(parts, expr[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
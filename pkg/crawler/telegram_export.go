@@ -0,0 +1,173 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TelegramExport Telegram Desktop 导出的频道历史（result.json）
+type TelegramExport struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	ID       int64             `json:"id"`
+	Messages []TelegramMessage `json:"messages"`
+}
+
+// TelegramMessage 导出历史中的单条消息
+type TelegramMessage struct {
+	ID           int64           `json:"id"`
+	Type         string          `json:"type"`
+	Date         string          `json:"date"`
+	DateUnixtime string          `json:"date_unixtime"`
+	Text         json.RawMessage `json:"text"`
+	Photo        string          `json:"photo"`
+}
+
+// telegramTextEntity text 为数组时的富文本片段
+type telegramTextEntity struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	Href string `json:"href"`
+}
+
+// PlainText 拼接消息正文；text_link 类型片段会把 href 一并输出，以免丢失隐藏在文字后的链接
+func (m TelegramMessage) PlainText() string {
+	if len(m.Text) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(m.Text, &s); err == nil {
+		return s
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(m.Text, &parts); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, p := range parts {
+		var str string
+		if err := json.Unmarshal(p, &str); err == nil {
+			b.WriteString(str)
+			continue
+		}
+		var ent telegramTextEntity
+		if err := json.Unmarshal(p, &ent); err != nil {
+			continue
+		}
+		b.WriteString(ent.Text)
+		if ent.Href != "" && ent.Href != ent.Text {
+			b.WriteString(" " + ent.Href + " ")
+		}
+	}
+	return b.String()
+}
+
+// Time 消息时间，优先使用 date_unixtime
+func (m TelegramMessage) Time() time.Time {
+	if sec, err := strconv.ParseInt(m.DateUnixtime, 10, 64); err == nil && sec > 0 {
+		return time.Unix(sec, 0)
+	}
+	return parseTime(m.Date)
+}
+
+// ParseTelegramExport 解析 Telegram 频道导出 JSON，每条含网盘链接的消息生成一个条目
+func ParseTelegramExport(body []byte, _ string, _ *Config) ([]Item, error) {
+	var export TelegramExport
+	if err := json.Unmarshal(body, &export); err != nil {
+		return nil, fmt.Errorf("解析Telegram导出文件失败: %v", err)
+	}
+
+	var items []Item
+	for _, msg := range export.Messages {
+		if msg.Type != "" && msg.Type != "message" {
+			continue
+		}
+		text := msg.PlainText()
+		links := ExtractPanLinks(text)
+		if len(links) == 0 {
+			continue
+		}
+
+		title, desc := SplitPostText(text)
+		items = append(items, Item{
+			GUID:        fmt.Sprintf("tg:%d:%d", export.ID, msg.ID),
+			Title:       title,
+			Description: desc,
+			URLs:        links,
+			Cover:       msg.Photo,
+			Tags:        ExtractHashtags(text),
+			PublishedAt: msg.Time(),
+		})
+	}
+	return items, nil
+}
+
+// titlePrefixes 频道帖子中常见的标题前缀
+var titlePrefixes = []string{"名称：", "名称:", "资源名称：", "资源名称:", "标题：", "标题:"}
+
+// descPrefixes 频道帖子中常见的描述前缀
+var descPrefixes = []string{"描述：", "描述:", "简介：", "简介:"}
+
+// SplitPostText 将频道帖子正文拆分为标题与描述：
+// 优先识别 "名称：" / "描述：" 行，否则取首个非链接行作为标题，其余非链接行作为描述。
+func SplitPostText(text string) (title, description string) {
+	var rest []string
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || urlPattern.MatchString(line) && strings.TrimSpace(urlPattern.ReplaceAllString(line, "")) == "" {
+			continue
+		}
+		if v, ok := cutPrefix(line, titlePrefixes); ok {
+			if title == "" {
+				title = v
+			}
+			continue
+		}
+		if v, ok := cutPrefix(line, descPrefixes); ok {
+			rest = append([]string{v}, rest...)
+			continue
+		}
+		if urlPattern.MatchString(line) || strings.HasPrefix(line, "#") {
+			// 链接行（如 "夸克：https://..."）和纯标签行不计入描述
+			continue
+		}
+		if title == "" {
+			title = line
+			continue
+		}
+		rest = append(rest, line)
+	}
+	return title, strings.Join(rest, "\n")
+}
+
+// ExtractHashtags 提取文本中的 #标签
+func ExtractHashtags(text string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(text) {
+		if !strings.HasPrefix(field, "#") || len(field) < 2 {
+			continue
+		}
+		tag := strings.TrimRight(strings.TrimPrefix(field, "#"), ",.，。")
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func cutPrefix(line string, prefixes []string) (string, bool) {
+	for _, p := range prefixes {
+		if strings.HasPrefix(line, p) {
+			return strings.TrimSpace(strings.TrimPrefix(line, p)), true
+		}
+	}
+	return "", false
+}
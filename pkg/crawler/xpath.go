@@ -0,0 +1,232 @@
+package crawler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 支持的 XPath 子集：
+//   - 绝对 / 相对路径：/html/body、//div、./a、.//a、a/span
+//   - 节点测试：元素名、*
+//   - 谓词：[@attr]、[@attr='v']、[contains(@attr,'v')]、[n]（从 1 开始）
+//   - 末尾取值：/@attr、/text()
+
+type xpathExpr struct {
+	absolute bool
+	steps    []xpathStep
+	attr     string // 末尾 /@attr
+	text     bool   // 末尾 /text()
+}
+
+type xpathStep struct {
+	descendant bool
+	name       string
+	preds      []xpathPred
+}
+
+type xpathPred struct {
+	kind  string // exists / eq / contains / index
+	attr  string
+	value string
+	index int
+}
+
+// compileXPath 解析 XPath 表达式
+func compileXPath(expr string) (*xpathExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("XPath 为空")
+	}
+
+	x := &xpathExpr{}
+	segments := splitOutside(expr, '/')
+	descendant := false
+	for i, seg := range segments {
+		seg = strings.TrimSpace(seg)
+		switch {
+		case seg == "":
+			if i == 0 {
+				x.absolute = true
+			} else {
+				descendant = true
+			}
+			continue
+		case seg == ".":
+			continue
+		case strings.HasPrefix(seg, "@"):
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("属性步骤只能出现在末尾: %s", expr)
+			}
+			x.attr = seg[1:]
+			continue
+		case seg == "text()":
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("text() 只能出现在末尾: %s", expr)
+			}
+			x.text = true
+			continue
+		}
+
+		step, err := parseXPathStep(seg)
+		if err != nil {
+			return nil, err
+		}
+		step.descendant = descendant
+		descendant = false
+		x.steps = append(x.steps, step)
+	}
+	return x, nil
+}
+
+func parseXPathStep(seg string) (xpathStep, error) {
+	step := xpathStep{}
+	bracket := strings.IndexByte(seg, '[')
+	if bracket < 0 {
+		step.name = strings.ToLower(seg)
+		return step, nil
+	}
+	step.name = strings.ToLower(seg[:bracket])
+
+	rest := seg[bracket:]
+	for len(rest) > 0 {
+		if rest[0] != '[' {
+			return step, fmt.Errorf("无效的 XPath 步骤: %s", seg)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return step, fmt.Errorf("谓词未闭合: %s", seg)
+		}
+		pred, err := parseXPathPred(strings.TrimSpace(rest[1:end]))
+		if err != nil {
+			return step, err
+		}
+		step.preds = append(step.preds, pred)
+		rest = rest[end+1:]
+	}
+	return step, nil
+}
+
+func parseXPathPred(body string) (xpathPred, error) {
+	if n, err := strconv.Atoi(body); err == nil {
+		return xpathPred{kind: "index", index: n}, nil
+	}
+	if strings.HasPrefix(body, "contains(") && strings.HasSuffix(body, ")") {
+		args := splitOutside(body[len("contains("):len(body)-1], ',')
+		if len(args) != 2 || !strings.HasPrefix(strings.TrimSpace(args[0]), "@") {
+			return xpathPred{}, fmt.Errorf("不支持的谓词: %s", body)
+		}
+		return xpathPred{
+			kind:  "contains",
+			attr:  strings.TrimPrefix(strings.TrimSpace(args[0]), "@"),
+			value: strings.Trim(strings.TrimSpace(args[1]), `"'`),
+		}, nil
+	}
+	if strings.HasPrefix(body, "@") {
+		if idx := strings.IndexByte(body, '='); idx > 0 {
+			return xpathPred{
+				kind:  "eq",
+				attr:  strings.TrimSpace(body[1:idx]),
+				value: strings.Trim(strings.TrimSpace(body[idx+1:]), `"'`),
+			}, nil
+		}
+		return xpathPred{kind: "exists", attr: body[1:]}, nil
+	}
+	return xpathPred{}, fmt.Errorf("不支持的谓词: %s", body)
+}
+
+// selectAll 在 ctx 上求值，返回匹配的元素节点
+func (x *xpathExpr) selectAll(ctx *html.Node) []*html.Node {
+	start := ctx
+	if x.absolute {
+		for start.Parent != nil {
+			start = start.Parent
+		}
+	}
+
+	current := []*html.Node{start}
+	for _, step := range x.steps {
+		var next []*html.Node
+		seen := make(map[*html.Node]bool)
+		for _, n := range current {
+			var candidates []*html.Node
+			if step.descendant {
+				walkElements(n, func(d *html.Node) {
+					if d != n && step.matches(d) {
+						candidates = append(candidates, d)
+					}
+				})
+			} else {
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if step.matches(c) {
+						candidates = append(candidates, c)
+					}
+				}
+			}
+			for _, c := range step.applyIndex(candidates) {
+				if !seen[c] {
+					seen[c] = true
+					next = append(next, c)
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// values 求值并按末尾步骤取属性或文本
+func (x *xpathExpr) values(ctx *html.Node) []string {
+	var out []string
+	for _, n := range x.selectAll(ctx) {
+		if x.attr != "" {
+			if v, ok := attrOK(n, x.attr); ok {
+				out = append(out, strings.TrimSpace(v))
+			}
+			continue
+		}
+		out = append(out, nodeText(n))
+	}
+	return out
+}
+
+func (s xpathStep) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if s.name != "*" && s.name != n.Data {
+		return false
+	}
+	for _, p := range s.preds {
+		switch p.kind {
+		case "exists":
+			if _, ok := attrOK(n, p.attr); !ok {
+				return false
+			}
+		case "eq":
+			if attr(n, p.attr) != p.value {
+				return false
+			}
+		case "contains":
+			if !strings.Contains(attr(n, p.attr), p.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s xpathStep) applyIndex(nodes []*html.Node) []*html.Node {
+	for _, p := range s.preds {
+		if p.kind != "index" {
+			continue
+		}
+		if p.index < 1 || p.index > len(nodes) {
+			return nil
+		}
+		nodes = nodes[p.index-1 : p.index]
+	}
+	return nodes
+}
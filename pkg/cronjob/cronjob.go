@@ -0,0 +1,691 @@
+// Package cronjob 统一的定时任务调度：任务按 cron 表达式（标准 5 段或 @every 等描述符）执行，
+// 支持随机抖动、错过执行后的补跑、手动触发与暂停，执行记录与暂停/表达式覆盖通过 Store 持久化
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// 执行来源
+const (
+	TriggerSchedule = "schedule"
+	TriggerCatchUp  = "catch_up"
+	TriggerManual   = "manual"
+)
+
+// 执行结果
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// 任务来源
+const (
+	SourceSystem = "system"
+	SourcePlugin = "plugin"
+)
+
+var (
+	// ErrSkipped 任务本轮无事可做（未启用、未到期等）时返回，定时执行的跳过不写入执行记录
+	ErrSkipped = errors.New("cronjob: skipped")
+	// ErrNotFound 任务未注册
+	ErrNotFound = errors.New("cronjob: job not found")
+	// ErrRunning 任务正在执行
+	ErrRunning = errors.New("cronjob: job is running")
+)
+
+// Job 任务定义
+type Job struct {
+	Name        string
+	Description string
+	// Source 任务来源，见 SourceSystem、SourcePlugin
+	Source string
+	// Spec 默认 cron 表达式，可被 Store 中保存的覆盖表达式替换
+	Spec string
+	// Jitter 每次执行在计划时间后随机延迟 [0, Jitter)，避免大量任务在同一时刻执行
+	Jitter time.Duration
+	// CatchUp 启用时若上次执行后已错过计划时间（或从未执行过）则立即补跑一次
+	CatchUp bool
+	// Run 执行任务，返回结果摘要；无事可做时返回 ErrSkipped
+	Run func(ctx context.Context) (string, error)
+}
+
+// Run 一次执行记录
+type Run struct {
+	ID         uint       `json:"id"`
+	Job        string     `json:"job"`
+	Trigger    string     `json:"trigger"`
+	Node       string     `json:"node"`
+	Status     string     `json:"status"`
+	Summary    string     `json:"summary"`
+	Error      string     `json:"error"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+}
+
+// State 任务的持久化状态
+type State struct {
+	// Spec 覆盖默认表达式，为空表示使用 Job.Spec
+	Spec   string
+	Paused bool
+}
+
+// Store 执行记录与任务状态的持久化
+type Store interface {
+	LoadState(ctx context.Context, job string) (State, error)
+	SaveState(ctx context.Context, job string, state State) error
+	// LastRun 返回最近一次执行记录，没有时返回 nil
+	LastRun(ctx context.Context, job string) (*Run, error)
+	SaveRun(ctx context.Context, run *Run) error
+	// PruneRuns 删除 before 之前开始的执行记录
+	PruneRuns(ctx context.Context, before time.Time) error
+}
+
+// Config 调度器配置
+type Config struct {
+	// Store 持久化，为空时执行记录与状态只保存在内存
+	Store Store
+	// NodeID 写入执行记录的节点标识
+	NodeID string
+	// StateRefresh 从 Store 重新读取暂停与表达式的间隔，使其他节点的修改生效；0 表示一分钟
+	StateRefresh time.Duration
+	// HistoryRetention 执行记录保留时间，0 表示 30 天
+	HistoryRetention time.Duration
+	// Lease 手动执行前取得任务的执行权（如多副本选主的锁），返回的释放函数在执行结束后调用；
+	// 为空时手动执行总在本节点进行
+	Lease func(ctx context.Context, name string) (release func(), err error)
+	// OnFinish 每次执行结束时回调（包括被跳过的定时执行），为空时忽略
+	OnFinish func(run Run)
+	// OnError Store 读写失败时回调，为空时忽略
+	OnError func(op string, err error)
+}
+
+// JobInfo 任务的当前状态
+type JobInfo struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Source       string     `json:"source"`
+	Spec         string     `json:"spec"`
+	DefaultSpec  string     `json:"default_spec"`
+	Frequency    *Frequency `json:"frequency"`
+	Active       bool       `json:"active"`
+	Paused       bool       `json:"paused"`
+	Running      bool       `json:"running"`
+	RunningSince *time.Time `json:"running_since,omitempty"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+	LastRun      *Run       `json:"last_run,omitempty"`
+}
+
+type entry struct {
+	job          Job
+	defaultSched cron.Schedule
+	override     string
+	sched        cron.Schedule
+	paused       bool
+
+	active bool
+	cancel context.CancelFunc
+	done   chan struct{}
+	ctx    context.Context
+	wake   chan struct{}
+
+	running      bool
+	runningSince time.Time
+	next         time.Time
+	last         *Run
+}
+
+// Scheduler 任务调度器，可并发使用
+type Scheduler struct {
+	cfg   Config
+	store Store
+	parse func(spec string) (cron.Schedule, error)
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建调度器，配置了 Store 时每天清理一次过期的执行记录
+func New(cfg Config) *Scheduler {
+	if cfg.StateRefresh <= 0 {
+		cfg.StateRefresh = time.Minute
+	}
+	if cfg.HistoryRetention <= 0 {
+		cfg.HistoryRetention = 30 * 24 * time.Hour
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		cfg:     cfg,
+		store:   cfg.Store,
+		parse:   cron.ParseStandard,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	if s.store != nil {
+		s.wg.Add(1)
+		go s.pruneLoop()
+	}
+	return s
+}
+
+// Validate 检查 cron 表达式是否合法
+func Validate(spec string) error {
+	_, err := cron.ParseStandard(spec)
+	return err
+}
+
+// Register 注册或更新任务定义，不会启用任务；已启用的任务按新的默认表达式重新计算下次执行时间
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("cronjob: name and run are required")
+	}
+	if job.Source == "" {
+		job.Source = SourceSystem
+	}
+	sched, err := s.parse(job.Spec)
+	if err != nil {
+		return fmt.Errorf("cronjob: 任务 %s 的表达式 %q 无效: %v", job.Name, job.Spec, err)
+	}
+
+	s.mu.Lock()
+	e, ok := s.entries[job.Name]
+	if ok {
+		e.job = job
+		e.defaultSched = sched
+		if e.override == "" {
+			e.sched = sched
+		}
+		s.mu.Unlock()
+		e.notify()
+		return nil
+	}
+	e = &entry{job: job, defaultSched: sched, sched: sched, wake: make(chan struct{}, 1)}
+	s.entries[job.Name] = e
+	s.mu.Unlock()
+
+	s.loadState(s.ctx, e)
+	if s.store != nil {
+		last, err := s.store.LastRun(s.ctx, job.Name)
+		if err != nil {
+			s.reportError("last_run", err)
+		} else if last != nil {
+			s.mu.Lock()
+			if e.last == nil {
+				e.last = last
+			}
+			s.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// Unregister 停用并移除任务
+func (s *Scheduler) Unregister(name string) {
+	s.Deactivate(name)
+	s.mu.Lock()
+	delete(s.entries, name)
+	s.mu.Unlock()
+}
+
+// Activate 启用任务，按计划执行；开启 CatchUp 时视情况立即补跑一次
+func (s *Scheduler) Activate(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.active {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	e.active = true
+	e.ctx = ctx
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go s.loop(ctx, e, e.done)
+	return nil
+}
+
+// Deactivate 停用任务，正在执行的任务通过 ctx 取消
+func (s *Scheduler) Deactivate(name string) {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	if !ok || !e.active {
+		s.mu.Unlock()
+		return
+	}
+	e.active = false
+	e.next = time.Time{}
+	cancel, done := e.cancel, e.done
+	s.mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+// IsActive 任务是否已启用
+func (s *Scheduler) IsActive(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	return ok && e.active
+}
+
+// Trigger 立即执行一次任务（暂停或未启用时同样执行），正在执行时返回 ErrRunning；
+// 配置了 Lease 时先取得执行权，取不到时返回 Lease 的错误
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+	if !ok {
+		return ErrNotFound
+	}
+	release := func() {}
+	if s.cfg.Lease != nil {
+		var err error
+		if release, err = s.cfg.Lease(ctx, name); err != nil {
+			return err
+		}
+	}
+	if !s.dispatch(e, TriggerManual, release) {
+		release()
+		return ErrRunning
+	}
+	return nil
+}
+
+// Pause 暂停任务的定时执行
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	return s.updateState(ctx, name, func(e *entry) error {
+		e.paused = true
+		return nil
+	})
+}
+
+// Resume 恢复任务的定时执行
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	return s.updateState(ctx, name, func(e *entry) error {
+		e.paused = false
+		return nil
+	})
+}
+
+// SetSpec 覆盖任务的 cron 表达式，spec 为空时恢复默认表达式
+func (s *Scheduler) SetSpec(ctx context.Context, name, spec string) error {
+	var sched cron.Schedule
+	if spec != "" {
+		parsed, err := s.parse(spec)
+		if err != nil {
+			return err
+		}
+		sched = parsed
+	}
+	return s.updateState(ctx, name, func(e *entry) error {
+		e.override = spec
+		if sched != nil {
+			e.sched = sched
+		} else {
+			e.sched = e.defaultSched
+		}
+		return nil
+	})
+}
+
+// Jobs 返回全部任务的状态，按名称排序
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	infos := make([]JobInfo, 0, len(s.entries))
+	for _, e := range s.entries {
+		infos = append(infos, e.info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Job 返回单个任务的状态
+func (s *Scheduler) Job(name string) (JobInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok {
+		return JobInfo{}, false
+	}
+	return e.info(), true
+}
+
+// Close 停用全部任务并停止清理
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.entries))
+	for name := range s.entries {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	for _, name := range names {
+		s.Deactivate(name)
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (e *entry) info() JobInfo {
+	spec := e.job.Spec
+	if e.override != "" {
+		spec = e.override
+	}
+	info := JobInfo{
+		Name:        e.job.Name,
+		Description: e.job.Description,
+		Source:      e.job.Source,
+		Spec:        spec,
+		DefaultSpec: e.job.Spec,
+		Frequency:   Describe(spec),
+		Active:      e.active,
+		Paused:      e.paused,
+		Running:     e.running,
+	}
+	if e.running {
+		since := e.runningSince
+		info.RunningSince = &since
+	}
+	if e.active && !e.next.IsZero() {
+		next := e.next
+		info.NextRun = &next
+	}
+	if e.last != nil {
+		last := *e.last
+		info.LastRun = &last
+	}
+	return info
+}
+
+func (e *entry) notify() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) updateState(ctx context.Context, name string, apply func(e *entry) error) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	if err := apply(e); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	state := State{Spec: e.override, Paused: e.paused}
+	s.mu.Unlock()
+
+	e.notify()
+	if s.store != nil {
+		return s.store.SaveState(ctx, name, state)
+	}
+	return nil
+}
+
+// loadState 从 Store 读取暂停状态与覆盖表达式，返回调度表达式是否变化
+func (s *Scheduler) loadState(ctx context.Context, e *entry) bool {
+	if s.store == nil {
+		return false
+	}
+	state, err := s.store.LoadState(ctx, e.job.Name)
+	if err != nil {
+		s.reportError("load_state", err)
+		return false
+	}
+
+	var sched cron.Schedule
+	if state.Spec != "" {
+		parsed, err := s.parse(state.Spec)
+		if err != nil {
+			s.reportError("load_state", fmt.Errorf("任务 %s 保存的表达式 %q 无效: %v", e.job.Name, state.Spec, err))
+			state.Spec = ""
+		} else {
+			sched = parsed
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.paused = state.Paused
+	if state.Spec == e.override {
+		return false
+	}
+	e.override = state.Spec
+	if sched != nil {
+		e.sched = sched
+	} else {
+		e.sched = e.defaultSched
+	}
+	return true
+}
+
+// nextFire 计算下次执行时间（含抖动）
+func (s *Scheduler) nextFire(e *entry) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := e.sched.Next(s.now())
+	if e.job.Jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(e.job.Jitter))))
+	}
+	e.next = next
+	return next
+}
+
+// shouldCatchUp 从未执行过，或上次执行后的下一个计划时间已过去
+func (s *Scheduler) shouldCatchUp(e *entry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !e.job.CatchUp {
+		return false
+	}
+	if e.last == nil {
+		return true
+	}
+	return !e.sched.Next(e.last.StartedAt).After(s.now())
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry, done chan struct{}) {
+	defer close(done)
+
+	s.loadState(ctx, e)
+	if s.shouldCatchUp(e) && !e.isPaused(&s.mu) {
+		s.dispatch(e, TriggerCatchUp, nil)
+	}
+
+	var fire time.Time
+	for {
+		if fire.IsZero() {
+			fire = s.nextFire(e)
+		}
+		wait := fire.Sub(s.now())
+		refresh := false
+		if s.store != nil && wait > s.cfg.StateRefresh {
+			wait = s.cfg.StateRefresh
+			refresh = true
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-e.wake:
+			timer.Stop()
+			fire = time.Time{}
+			continue
+		case <-timer.C:
+		}
+
+		// 重新读取其他节点修改的暂停状态与表达式
+		if s.loadState(ctx, e) {
+			fire = time.Time{}
+			continue
+		}
+		if refresh {
+			continue
+		}
+		fire = time.Time{}
+		if e.isPaused(&s.mu) {
+			continue
+		}
+		s.dispatch(e, TriggerSchedule, nil)
+	}
+}
+
+func (e *entry) isPaused(mu *sync.Mutex) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return e.paused
+}
+
+// dispatch 异步执行一次，执行结束后调用 release（可为空）；上一次还未结束时跳过并返回 false
+func (s *Scheduler) dispatch(e *entry, trigger string, release func()) bool {
+	s.mu.Lock()
+	if e.running {
+		s.mu.Unlock()
+		if trigger != TriggerManual {
+			s.finish(Run{Job: e.job.Name, Trigger: trigger, Node: s.cfg.NodeID, Status: StatusSkipped, Summary: "上一次执行尚未结束", StartedAt: s.now()})
+		}
+		return false
+	}
+	e.running = true
+	e.runningSince = s.now()
+	ctx := s.ctx
+	if e.active && e.ctx != nil {
+		ctx = e.ctx
+	}
+	job := e.job
+	s.mu.Unlock()
+
+	go s.execute(ctx, e, job, trigger, release)
+	return true
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, job Job, trigger string, release func()) {
+	if release != nil {
+		defer release()
+	}
+	run := Run{Job: job.Name, Trigger: trigger, Node: s.cfg.NodeID, StartedAt: s.now()}
+	summary, err := safeRun(context.WithValue(ctx, triggerKey{}, trigger), job.Run)
+	finished := s.now()
+	run.FinishedAt = &finished
+	run.DurationMs = finished.Sub(run.StartedAt).Milliseconds()
+	run.Summary = summary
+	switch {
+	case errors.Is(err, ErrSkipped):
+		run.Status = StatusSkipped
+	case err != nil:
+		run.Status = StatusFailed
+		run.Error = err.Error()
+	default:
+		run.Status = StatusSuccess
+	}
+
+	// 定时执行被跳过时不写入记录，避免高频检查类任务刷屏
+	persist := run.Status != StatusSkipped || trigger == TriggerManual
+	if persist && s.store != nil {
+		if err := s.store.SaveRun(context.Background(), &run); err != nil {
+			s.reportError("save_run", err)
+		}
+	}
+
+	s.mu.Lock()
+	e.running = false
+	e.runningSince = time.Time{}
+	if persist {
+		last := run
+		e.last = &last
+	}
+	s.mu.Unlock()
+
+	s.finish(run)
+}
+
+func (s *Scheduler) finish(run Run) {
+	if s.cfg.OnFinish != nil {
+		s.cfg.OnFinish(run)
+	}
+}
+
+type triggerKey struct{}
+
+// IsManual 本次执行是否为手动触发，任务可据此跳过"未到期"之类的检查
+func IsManual(ctx context.Context) bool {
+	trigger, _ := ctx.Value(triggerKey{}).(string)
+	return trigger == TriggerManual
+}
+
+// safeRun 执行任务并把 panic 转换为错误
+func safeRun(ctx context.Context, run func(ctx context.Context) (string, error)) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+func (s *Scheduler) pruneLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+	for {
+		if err := s.store.PruneRuns(s.ctx, s.now().Add(-s.cfg.HistoryRetention)); err != nil && s.ctx.Err() == nil {
+			s.reportError("prune_runs", err)
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) reportError(op string, err error) {
+	if s.cfg.OnError != nil {
+		s.cfg.OnError(op, err)
+	}
+}
+
+var (
+	defaultMu        sync.RWMutex
+	defaultScheduler = New(Config{})
+)
+
+// Default 返回全局调度器，未配置时执行记录只保存在内存
+func Default() *Scheduler {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultScheduler
+}
+
+// SetDefault 替换全局调度器，需在注册任务之前调用
+func SetDefault(s *Scheduler) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultScheduler = s
+}
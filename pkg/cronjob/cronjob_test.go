@@ -0,0 +1,390 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// memStore 内存中的 Store
+type memStore struct {
+	mu     sync.Mutex
+	states map[string]State
+	runs   []Run
+}
+
+func newMemStore() *memStore {
+	return &memStore{states: make(map[string]State)}
+}
+
+func (m *memStore) LoadState(_ context.Context, job string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[job], nil
+}
+
+func (m *memStore) SaveState(_ context.Context, job string, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[job] = state
+	return nil
+}
+
+func (m *memStore) LastRun(_ context.Context, job string) (*Run, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.runs) - 1; i >= 0; i-- {
+		if m.runs[i].Job == job {
+			run := m.runs[i]
+			return &run, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memStore) SaveRun(_ context.Context, run *Run) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	run.ID = uint(len(m.runs) + 1)
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *memStore) PruneRuns(_ context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.runs[:0]
+	for _, run := range m.runs {
+		if !run.StartedAt.Before(before) {
+			kept = append(kept, run)
+		}
+	}
+	m.runs = kept
+	return nil
+}
+
+func (m *memStore) list(job string) []Run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var runs []Run
+	for _, run := range m.runs {
+		if run.Job == job {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+// fastSchedule 亚秒级间隔，robfig 的 @every 最小为一秒
+type fastSchedule time.Duration
+
+func (f fastSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(f))
+}
+
+// newTestScheduler "fast" 表达式解析为 10ms 间隔，其他表达式按标准语法解析
+func newTestScheduler(store Store) *Scheduler {
+	s := New(Config{Store: store, NodeID: "node-1", StateRefresh: 20 * time.Millisecond})
+	s.parse = func(spec string) (cron.Schedule, error) {
+		if spec == "fast" {
+			return fastSchedule(10 * time.Millisecond), nil
+		}
+		return cron.ParseStandard(spec)
+	}
+	return s
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestScheduledRunsAreRecorded(t *testing.T) {
+	store := newMemStore()
+	s := newTestScheduler(store)
+	defer s.Close()
+
+	var calls atomic.Int32
+	err := s.Register(Job{Name: "sitemap", Spec: "fast", Run: func(context.Context) (string, error) {
+		calls.Add(1)
+		return "ok", nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.IsActive("sitemap") {
+		t.Fatal("registered job should be inactive")
+	}
+	if err := s.Activate("sitemap"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "two runs", func() bool { return len(store.list("sitemap")) >= 2 })
+	s.Deactivate("sitemap")
+
+	run := store.list("sitemap")[0]
+	if run.Status != StatusSuccess || run.Trigger != TriggerSchedule || run.Node != "node-1" || run.Summary != "ok" || run.FinishedAt == nil {
+		t.Fatalf("run = %+v", run)
+	}
+
+	// 停用后不再执行
+	n := calls.Load()
+	time.Sleep(40 * time.Millisecond)
+	if calls.Load() != n {
+		t.Fatal("deactivated job kept running")
+	}
+	info, ok := s.Job("sitemap")
+	if !ok || info.Active || info.LastRun == nil || info.Source != SourceSystem {
+		t.Fatalf("info = %+v", info)
+	}
+}
+
+func TestCatchUpAfterMissedRun(t *testing.T) {
+	store := newMemStore()
+	store.runs = []Run{
+		{Job: "stale", Status: StatusSuccess, StartedAt: time.Now().Add(-2 * time.Hour)},
+		{Job: "fresh", Status: StatusSuccess, StartedAt: time.Now().Add(-time.Minute)},
+	}
+	s := newTestScheduler(store)
+	defer s.Close()
+
+	var stale, fresh atomic.Int32
+	s.Register(Job{Name: "stale", Spec: "@every 1h", CatchUp: true, Run: func(context.Context) (string, error) {
+		stale.Add(1)
+		return "", nil
+	}})
+	s.Register(Job{Name: "fresh", Spec: "@every 1h", CatchUp: true, Run: func(context.Context) (string, error) {
+		fresh.Add(1)
+		return "", nil
+	}})
+	s.Activate("stale")
+	s.Activate("fresh")
+
+	waitFor(t, "catch-up run", func() bool { return len(store.list("stale")) == 2 })
+	if runs := store.list("stale"); runs[1].Trigger != TriggerCatchUp {
+		t.Fatalf("trigger = %s, want %s", runs[1].Trigger, TriggerCatchUp)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if fresh.Load() != 0 {
+		t.Fatal("job within its interval should not catch up")
+	}
+
+	info, _ := s.Job("fresh")
+	if info.NextRun == nil || info.NextRun.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("next run = %v", info.NextRun)
+	}
+}
+
+func TestPauseAndManualTrigger(t *testing.T) {
+	store := newMemStore()
+	s := newTestScheduler(store)
+	defer s.Close()
+
+	var calls atomic.Int32
+	var manual atomic.Bool
+	s.Register(Job{Name: "cleanup", Spec: "fast", Run: func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		if IsManual(ctx) {
+			manual.Store(true)
+		}
+		return "", nil
+	}})
+	if err := s.Pause(context.Background(), "cleanup"); err != nil {
+		t.Fatal(err)
+	}
+	if !store.states["cleanup"].Paused {
+		t.Fatal("pause should be persisted")
+	}
+	s.Activate("cleanup")
+	time.Sleep(40 * time.Millisecond)
+	if calls.Load() != 0 {
+		t.Fatal("paused job should not run on schedule")
+	}
+
+	// 暂停时仍可手动执行
+	if err := s.Trigger(context.Background(), "cleanup"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "manual run", func() bool { return len(store.list("cleanup")) == 1 })
+	if run := store.list("cleanup")[0]; run.Trigger != TriggerManual || !manual.Load() {
+		t.Fatalf("trigger = %s, manual = %v", run.Trigger, manual.Load())
+	}
+
+	// 其他节点恢复任务后，本节点在下一次刷新时生效
+	store.SaveState(context.Background(), "cleanup", State{})
+	waitFor(t, "resumed run", func() bool { return calls.Load() >= 3 })
+
+	if err := s.Trigger(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOverlappingTriggerIsRejected(t *testing.T) {
+	s := newTestScheduler(nil)
+	defer s.Close()
+
+	release := make(chan struct{})
+	s.Register(Job{Name: "backup", Spec: "@daily", Run: func(ctx context.Context) (string, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return "", nil
+	}})
+	if err := s.Trigger(context.Background(), "backup"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "job running", func() bool { info, _ := s.Job("backup"); return info.Running })
+	if err := s.Trigger(context.Background(), "backup"); !errors.Is(err, ErrRunning) {
+		t.Fatalf("err = %v, want ErrRunning", err)
+	}
+	close(release)
+	waitFor(t, "job finished", func() bool { info, _ := s.Job("backup"); return !info.Running })
+}
+
+func TestManualTriggerHoldsLease(t *testing.T) {
+	s := newTestScheduler(nil)
+	defer s.Close()
+
+	errHeld := errors.New("held by node-2")
+	var leased, released atomic.Int32
+	var held atomic.Bool
+	s.cfg.Lease = func(context.Context, string) (func(), error) {
+		if held.Load() {
+			return nil, errHeld
+		}
+		leased.Add(1)
+		return func() { released.Add(1) }, nil
+	}
+
+	finish := make(chan struct{})
+	s.Register(Job{Name: "sitemap", Spec: "@daily", Run: func(ctx context.Context) (string, error) {
+		<-finish
+		return "", nil
+	}})
+	if err := s.Trigger(context.Background(), "sitemap"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "job running", func() bool { info, _ := s.Job("sitemap"); return info.Running })
+	if released.Load() != 0 {
+		t.Fatal("lease should be held while the job runs")
+	}
+
+	// 本节点已在执行：租约立即归还
+	if err := s.Trigger(context.Background(), "sitemap"); !errors.Is(err, ErrRunning) {
+		t.Fatalf("err = %v, want ErrRunning", err)
+	}
+	if leased.Load() != 2 || released.Load() != 1 {
+		t.Fatalf("leased = %d, released = %d", leased.Load(), released.Load())
+	}
+	close(finish)
+	waitFor(t, "lease released", func() bool { return released.Load() == 2 })
+
+	// 执行权在其他节点：不执行
+	held.Store(true)
+	if err := s.Trigger(context.Background(), "sitemap"); !errors.Is(err, errHeld) {
+		t.Fatalf("err = %v, want lease error", err)
+	}
+	if info, _ := s.Job("sitemap"); info.Running || leased.Load() != 2 {
+		t.Fatal("job should not run without the lease")
+	}
+}
+
+func TestSkippedAndFailedRuns(t *testing.T) {
+	store := newMemStore()
+	var finished []Run
+	var mu sync.Mutex
+	s := newTestScheduler(store)
+	s.cfg.OnFinish = func(run Run) {
+		mu.Lock()
+		finished = append(finished, run)
+		mu.Unlock()
+	}
+	defer s.Close()
+
+	s.Register(Job{Name: "idle", Spec: "fast", Run: func(context.Context) (string, error) {
+		return "", ErrSkipped
+	}})
+	s.Register(Job{Name: "broken", Spec: "@daily", Run: func(context.Context) (string, error) {
+		panic("boom")
+	}})
+
+	s.Activate("idle")
+	waitFor(t, "skipped runs", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(finished) >= 2
+	})
+	s.Deactivate("idle")
+	if runs := store.list("idle"); len(runs) != 0 {
+		t.Fatalf("scheduled skips should not be recorded, got %d", len(runs))
+	}
+	s.Trigger(context.Background(), "idle")
+	waitFor(t, "manual skip", func() bool { return len(store.list("idle")) == 1 })
+	if run := store.list("idle")[0]; run.Status != StatusSkipped {
+		t.Fatalf("status = %s", run.Status)
+	}
+
+	s.Trigger(context.Background(), "broken")
+	waitFor(t, "failed run", func() bool { return len(store.list("broken")) == 1 })
+	if run := store.list("broken")[0]; run.Status != StatusFailed || run.Error != "panic: boom" {
+		t.Fatalf("run = %+v", run)
+	}
+}
+
+func TestSpecOverride(t *testing.T) {
+	store := newMemStore()
+	store.states["metadata"] = State{Spec: "0 3 * * *"}
+	s := newTestScheduler(store)
+	defer s.Close()
+
+	run := func(context.Context) (string, error) { return "", nil }
+	if err := s.Register(Job{Name: "metadata", Spec: "@every 1h", Run: run}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := s.Job("metadata")
+	if info.Spec != "0 3 * * *" || info.DefaultSpec != "@every 1h" {
+		t.Fatalf("info = %+v", info)
+	}
+
+	if err := s.SetSpec(context.Background(), "metadata", "bad spec"); err == nil {
+		t.Fatal("invalid spec should be rejected")
+	}
+	if err := s.SetSpec(context.Background(), "metadata", ""); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := s.Job("metadata"); info.Spec != "@every 1h" || store.states["metadata"].Spec != "" {
+		t.Fatalf("reset spec = %s", info.Spec)
+	}
+	if err := s.Register(Job{Name: "invalid", Spec: "* *", Run: run}); err == nil {
+		t.Fatal("register should validate spec")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	cases := map[string]string{
+		"@every 3m":   "3分钟",
+		"@every 12h":  "12小时",
+		"@every 24h":  "1天",
+		"@daily":      "天",
+		"*/5 * * * *": "5分钟",
+		"0 * * * *":   "小时",
+		"@every nope": "未知",
+	}
+	for spec, want := range cases {
+		if got := Describe(spec).Interval; got != want {
+			t.Errorf("Describe(%q).Interval = %q, want %q", spec, got, want)
+		}
+	}
+}
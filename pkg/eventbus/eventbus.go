@@ -0,0 +1,333 @@
+package eventbus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 事件类型
+const (
+	TypeTaskProgress       = "task.progress"       // 任务项处理进度
+	TypeTaskStatus         = "task.status"         // 任务状态变更
+	TypeSchedulerRun       = "scheduler.run"       // 定时任务开始/结束
+	TypeMeilisearchSync    = "meilisearch.sync"    // Meilisearch 全量同步进度
+	TypeReportCreated      = "report.created"      // 新举报
+	TypeReportEscalated    = "report.escalated"    // 同一资源举报过多，已自动升级
+	TypeModerationResolved = "moderation.resolved" // 举报或版权申述处理完成
+	TypeAccountFailed      = "account.failed"      // 网盘账号失效/保活失败
+)
+
+// Types 全部事件类型，供订阅设置页面展示
+var Types = []string{
+	TypeTaskProgress,
+	TypeTaskStatus,
+	TypeSchedulerRun,
+	TypeMeilisearchSync,
+	TypeReportCreated,
+	TypeReportEscalated,
+	TypeModerationResolved,
+	TypeAccountFailed,
+}
+
+// Event 事件。ID 由接收事件的进程内总线分配，单调递增，用于 SSE 断线续传
+type Event struct {
+	ID     uint64          `json:"id"`
+	Type   string          `json:"type"`
+	Time   time.Time       `json:"time"`
+	Origin string          `json:"origin,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// Bridge 跨进程转发通道，Send 把本进程发布的事件广播给其他副本
+type Bridge interface {
+	Send(ev Event) error
+}
+
+// Stats 总线运行统计
+type Stats struct {
+	Origin      string `json:"origin"`
+	Subscribers int    `json:"subscribers"`
+	LastID      uint64 `json:"last_id"`
+	Published   uint64 `json:"published"`
+	Remote      uint64 `json:"remote"`
+	Dropped     uint64 `json:"dropped"`
+	BridgeSent  uint64 `json:"bridge_sent"`
+	BridgeErred uint64 `json:"bridge_erred"`
+}
+
+// Bus 进程内发布/订阅总线。投递不阻塞发布方，订阅方缓冲区满时丢弃事件并计数
+type Bus struct {
+	origin string
+
+	mu          sync.RWMutex
+	seq         uint64
+	history     []Event
+	historySize int
+	subs        map[*Subscription]struct{}
+
+	bridgeMu sync.Mutex
+	bridge   Bridge
+	outbox   chan Event
+
+	published   atomic.Uint64
+	remote      atomic.Uint64
+	dropped     atomic.Uint64
+	bridgeSent  atomic.Uint64
+	bridgeErred atomic.Uint64
+}
+
+// New 创建总线，historySize 为保留用于断线续传的最近事件数
+func New(historySize int) *Bus {
+	if historySize < 0 {
+		historySize = 0
+	}
+	return &Bus{
+		origin:      newOrigin(),
+		historySize: historySize,
+		subs:        make(map[*Subscription]struct{}),
+	}
+}
+
+// Origin 本进程标识，用于跳过桥接回来的自身事件
+func (b *Bus) Origin() string {
+	return b.origin
+}
+
+// Publish 发布事件，data 序列化为 JSON。同时经桥接转发给其他副本
+func (b *Bus) Publish(eventType string, data interface{}) {
+	var raw json.RawMessage
+	if data != nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return
+		}
+		raw = encoded
+	}
+
+	ev := b.dispatch(Event{Type: eventType, Time: time.Now(), Origin: b.origin, Data: raw})
+	b.published.Add(1)
+
+	// 非阻塞写入，持锁避免与 SetBridge 关闭队列并发
+	b.bridgeMu.Lock()
+	defer b.bridgeMu.Unlock()
+	if b.outbox != nil {
+		select {
+		case b.outbox <- ev:
+		default:
+			b.bridgeErred.Add(1)
+		}
+	}
+}
+
+// Deliver 投递其他副本转发来的事件，自身发出的事件会被忽略
+func (b *Bus) Deliver(ev Event) {
+	if ev.Type == "" || ev.Origin == b.origin {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	b.dispatch(ev)
+	b.remote.Add(1)
+}
+
+// dispatch 分配本地 ID、写入历史并投递给匹配的订阅者
+func (b *Bus) dispatch(ev Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	ev.ID = b.seq
+	if b.historySize > 0 {
+		if len(b.history) >= b.historySize {
+			copy(b.history, b.history[1:])
+			b.history = b.history[:len(b.history)-1]
+		}
+		b.history = append(b.history, ev)
+	}
+
+	for sub := range b.subs {
+		if !sub.filter.Match(ev.Type) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.dropped.Add(1)
+			b.dropped.Add(1)
+		}
+	}
+	return ev
+}
+
+// Subscribe 订阅匹配 filter 的事件。lastID > 0 时返回历史中 ID 更大的匹配事件用于补发，
+// 注册与取历史在同一把锁内完成，补发事件与后续实时事件之间不会遗漏
+func (b *Bus) Subscribe(filter Filter, buffer int, lastID uint64) (*Subscription, []Event) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	sub := &Subscription{bus: b, filter: filter, ch: make(chan Event, buffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastID > 0 {
+		for _, ev := range b.history {
+			if ev.ID > lastID && filter.Match(ev.Type) {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	b.subs[sub] = struct{}{}
+	return sub, replay
+}
+
+// SetBridge 设置跨进程桥接，发布的事件经后台队列异步发送，发送失败只计数不重试
+func (b *Bus) SetBridge(bridge Bridge) {
+	b.bridgeMu.Lock()
+	defer b.bridgeMu.Unlock()
+
+	if b.outbox != nil {
+		close(b.outbox)
+		b.outbox = nil
+	}
+	b.bridge = bridge
+	if bridge == nil {
+		return
+	}
+
+	outbox := make(chan Event, 256)
+	b.outbox = outbox
+	go func() {
+		for ev := range outbox {
+			if err := bridge.Send(ev); err != nil {
+				b.bridgeErred.Add(1)
+				continue
+			}
+			b.bridgeSent.Add(1)
+		}
+	}()
+}
+
+// Stats 获取运行统计
+func (b *Bus) Stats() Stats {
+	b.mu.RLock()
+	subscribers := len(b.subs)
+	lastID := b.seq
+	b.mu.RUnlock()
+
+	return Stats{
+		Origin:      b.origin,
+		Subscribers: subscribers,
+		LastID:      lastID,
+		Published:   b.published.Load(),
+		Remote:      b.remote.Load(),
+		Dropped:     b.dropped.Load(),
+		BridgeSent:  b.bridgeSent.Load(),
+		BridgeErred: b.bridgeErred.Load(),
+	}
+}
+
+// Subscription 订阅句柄
+type Subscription struct {
+	bus     *Bus
+	filter  Filter
+	ch      chan Event
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// C 事件通道，Close 后关闭
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Dropped 因缓冲区满被丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.ch)
+		s.bus.mu.Unlock()
+	})
+}
+
+// Filter 事件类型过滤器。支持精确类型、"task.*" 前缀通配和 "*"，为空时匹配全部
+type Filter []string
+
+// ParseFilter 解析逗号分隔的过滤表达式
+func ParseFilter(s string) Filter {
+	var f Filter
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			f = append(f, part)
+		}
+	}
+	return f
+}
+
+// Match 判断事件类型是否匹配
+func (f Filter) Match(eventType string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, pattern := range f {
+		switch {
+		case pattern == "*" || pattern == eventType:
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 检查每个表达式是否能匹配到已知事件类型，返回第一个无效表达式
+func (f Filter) Validate() error {
+	for _, pattern := range f {
+		matched := false
+		for _, t := range Types {
+			if (Filter{pattern}).Match(t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("未知的事件类型: %s", pattern)
+		}
+	}
+	return nil
+}
+
+// String 还原为逗号分隔形式
+func (f Filter) String() string {
+	return strings.Join(f, ",")
+}
+
+func newOrigin() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Default 全局总线，业务代码通过 Publish 发布事件
+var Default = New(512)
+
+// Publish 向全局总线发布事件
+func Publish(eventType string, data interface{}) {
+	Default.Publish(eventType, data)
+}
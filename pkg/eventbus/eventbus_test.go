@@ -0,0 +1,142 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterMatch(t *testing.T) {
+	cases := []struct {
+		filter string
+		typ    string
+		want   bool
+	}{
+		{"", TypeTaskProgress, true},
+		{"*", TypeAccountFailed, true},
+		{"task.*", TypeTaskProgress, true},
+		{"task.*", TypeTaskStatus, true},
+		{"task.*", "taskx.progress", false},
+		{" report.created , account.failed ", TypeAccountFailed, true},
+		{"report.created", TypeSchedulerRun, false},
+	}
+	for _, c := range cases {
+		if got := ParseFilter(c.filter).Match(c.typ); got != c.want {
+			t.Errorf("ParseFilter(%q).Match(%q) = %v, want %v", c.filter, c.typ, got, c.want)
+		}
+	}
+
+	if err := ParseFilter("task.*,report.created,*").Validate(); err != nil {
+		t.Errorf("valid filter rejected: %v", err)
+	}
+	if err := ParseFilter("task.*,tasks.*").Validate(); err == nil {
+		t.Error("unknown pattern should be rejected")
+	}
+}
+
+func TestBusFanOutAndReplay(t *testing.T) {
+	bus := New(3)
+	tasks, _ := bus.Subscribe(ParseFilter("task.*"), 8, 0)
+	all, _ := bus.Subscribe(nil, 8, 0)
+
+	bus.Publish(TypeTaskProgress, map[string]int{"processed": 1})
+	bus.Publish(TypeReportCreated, nil)
+	bus.Publish(TypeTaskStatus, map[string]string{"status": "completed"})
+
+	if got := len(tasks.C()); got != 2 {
+		t.Errorf("task subscriber got %d events, want 2", got)
+	}
+	if got := len(all.C()); got != 3 {
+		t.Errorf("unfiltered subscriber got %d events, want 3", got)
+	}
+	ev := <-tasks.C()
+	if ev.ID != 1 || ev.Type != TypeTaskProgress || string(ev.Data) != `{"processed":1}` {
+		t.Errorf("first event = %+v", ev)
+	}
+
+	// 历史只保留最近 3 条，第 4 条发布后 ID 1 被淘汰
+	bus.Publish(TypeTaskProgress, nil)
+	_, replay := bus.Subscribe(ParseFilter("task.*"), 8, 1)
+	if len(replay) != 2 || replay[0].ID != 3 || replay[1].ID != 4 {
+		t.Errorf("replay = %+v", replay)
+	}
+
+	tasks.Close()
+	tasks.Close()
+	if _, ok := <-tasks.C(); !ok {
+		t.Error("closed subscription should still drain buffered events")
+	}
+	if stats := bus.Stats(); stats.Subscribers != 2 || stats.Published != 4 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBusDropsWhenSubscriberFull(t *testing.T) {
+	bus := New(0)
+	slow, _ := bus.Subscribe(nil, 1, 0)
+	for i := 0; i < 5; i++ {
+		bus.Publish(TypeSchedulerRun, nil)
+	}
+	if slow.Dropped() != 4 || bus.Stats().Dropped != 4 {
+		t.Errorf("dropped = %d, bus dropped = %d", slow.Dropped(), bus.Stats().Dropped)
+	}
+}
+
+// loopback 把事件直接投递给另一条总线，模拟 LISTEN/NOTIFY 的多副本广播
+type loopback struct {
+	peers []*Bus
+}
+
+func (l *loopback) Send(ev Event) error {
+	for _, peer := range l.peers {
+		peer.Deliver(ev)
+	}
+	return nil
+}
+
+func TestBusBridgeBetweenReplicas(t *testing.T) {
+	a, b := New(10), New(10)
+	// 广播会回到发送方自身，Deliver 需要跳过
+	link := &loopback{peers: []*Bus{a, b}}
+	a.SetBridge(link)
+	defer a.SetBridge(nil)
+
+	subA, _ := a.Subscribe(nil, 8, 0)
+	subB, _ := b.Subscribe(nil, 8, 0)
+	b.Publish(TypeTaskStatus, nil) // b 未设置桥接，不会外发
+	a.Publish(TypeAccountFailed, map[string]string{"account": "alice"})
+
+	select {
+	case ev := <-subB.C():
+		if ev.Type != TypeTaskStatus {
+			t.Fatalf("unexpected local event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("missing local event")
+	}
+	select {
+	case ev := <-subB.C():
+		if ev.Type != TypeAccountFailed || ev.ID != 2 || ev.Origin != a.Origin() {
+			t.Errorf("bridged event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bridged event not delivered")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if got := len(subA.C()); got != 1 {
+		t.Errorf("publisher received %d events, want 1 (no echo)", got)
+	}
+}
+
+func TestEncodeNotifyPayloadTruncates(t *testing.T) {
+	big, _ := json.Marshal(map[string]string{"message": strings.Repeat("x", 9000)})
+	payload, err := encodeNotifyPayload(Event{ID: 1, Type: TypeMeilisearchSync, Data: big})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) > maxNotifyPayload || !strings.Contains(payload, `"truncated":true`) {
+		t.Errorf("payload not truncated: %d bytes", len(payload))
+	}
+}
@@ -0,0 +1,164 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxNotifyPayload pg_notify 负载上限为 8000 字节，留出余量
+const maxNotifyPayload = 7900
+
+// PGBridge 基于 PostgreSQL LISTEN/NOTIFY 的跨副本桥接。
+// 监听使用独立长连接，断开后指数退避重连；发送使用另一条连接，失败时下次重建
+type PGBridge struct {
+	dsn     string
+	channel string
+	bus     *Bus
+
+	// OnError 连接或发送异常时回调，为空时忽略
+	OnError func(err error)
+
+	sendMu   sync.Mutex
+	sendConn *pgx.Conn
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPGBridge 创建桥接，channel 为 LISTEN/NOTIFY 的通道名
+func NewPGBridge(dsn, channel string, bus *Bus) *PGBridge {
+	return &PGBridge{dsn: dsn, channel: channel, bus: bus}
+}
+
+// Start 开始监听其他副本的事件并注册为总线的桥接
+func (p *PGBridge) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	go p.run(ctx)
+	p.bus.SetBridge(p)
+}
+
+// Stop 停止监听并关闭连接
+func (p *PGBridge) Stop() {
+	p.bus.SetBridge(nil)
+	if p.cancel != nil {
+		p.cancel()
+		<-p.done
+	}
+
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+	if p.sendConn != nil {
+		p.sendConn.Close(context.Background())
+		p.sendConn = nil
+	}
+}
+
+// Send 通过 pg_notify 广播事件，负载过大时去掉 Data 并标记 truncated
+func (p *PGBridge) Send(ev Event) error {
+	payload, err := encodeNotifyPayload(ev)
+	if err != nil {
+		return err
+	}
+
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if p.sendConn == nil || p.sendConn.IsClosed() {
+		conn, err := pgx.Connect(ctx, p.dsn)
+		if err != nil {
+			p.reportError(fmt.Errorf("事件桥接连接失败: %v", err))
+			return err
+		}
+		p.sendConn = conn
+	}
+
+	if _, err := p.sendConn.Exec(ctx, "SELECT pg_notify($1, $2)", p.channel, payload); err != nil {
+		p.sendConn.Close(context.Background())
+		p.sendConn = nil
+		p.reportError(fmt.Errorf("事件桥接发送失败: %v", err))
+		return err
+	}
+	return nil
+}
+
+// run 监听循环，连接断开后按 1s、2s … 30s 退避重连
+func (p *PGBridge) run(ctx context.Context) {
+	defer close(p.done)
+
+	backoff := time.Second
+	for {
+		listened, err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if listened {
+			backoff = time.Second
+		}
+		p.reportError(fmt.Errorf("事件桥接监听中断，%v 后重连: %v", backoff, err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > 30*time.Second {
+			backoff = 30 * time.Second
+		}
+	}
+}
+
+// listen 建立监听连接并持续接收通知，listened 表示 LISTEN 是否已成功执行
+func (p *PGBridge) listen(ctx context.Context) (listened bool, err error) {
+	conn, err := pgx.Connect(ctx, p.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{p.channel}.Sanitize()); err != nil {
+		return false, err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(notification.Payload), &ev); err != nil {
+			continue
+		}
+		p.bus.Deliver(ev)
+	}
+}
+
+func (p *PGBridge) reportError(err error) {
+	if p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// encodeNotifyPayload 序列化事件，超过 NOTIFY 负载上限时用 {"truncated":true} 替换 Data
+func encodeNotifyPayload(ev Event) (string, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) > maxNotifyPayload {
+		ev.Data = json.RawMessage(`{"truncated":true}`)
+		if payload, err = json.Marshal(ev); err != nil {
+			return "", err
+		}
+	}
+	return string(payload), nil
+}
@@ -41,9 +41,13 @@ type URLInspectionRequest struct {
 // URLInspectionResult URL检查结果
 type URLInspectionResult struct {
 	IndexStatusResult struct {
-		IndexingState string `json:"indexingState"`
-		LastCrawled   string `json:"lastCrawled"`
-		CrawlErrors   []struct {
+		IndexingState   string `json:"indexingState"`
+		LastCrawled     string `json:"lastCrawled"`
+		Verdict         string `json:"verdict"`         // PASS 表示已被索引
+		CoverageState   string `json:"coverageState"`   // 如 "Crawled - currently not indexed"
+		PageFetchState  string `json:"pageFetchState"`  // 页面抓取状态
+		GoogleCanonical string `json:"googleCanonical"` // Google 选定的规范网址
+		CrawlErrors     []struct {
 			ErrorCode string `json:"errorCode"`
 		} `json:"crawlErrors"`
 	} `json:"indexStatusResult"`
@@ -268,6 +272,10 @@ func (c *Client) InspectURL(url string) (*URLInspectionResult, error) {
 
 		if response.InspectionResult.IndexStatusResult != nil {
 			result.IndexStatusResult.IndexingState = string(response.InspectionResult.IndexStatusResult.IndexingState)
+			result.IndexStatusResult.Verdict = response.InspectionResult.IndexStatusResult.Verdict
+			result.IndexStatusResult.CoverageState = response.InspectionResult.IndexStatusResult.CoverageState
+			result.IndexStatusResult.PageFetchState = response.InspectionResult.IndexStatusResult.PageFetchState
+			result.IndexStatusResult.GoogleCanonical = response.InspectionResult.IndexStatusResult.GoogleCanonical
 			if response.InspectionResult.IndexStatusResult.LastCrawlTime != "" {
 				result.IndexStatusResult.LastCrawled = response.InspectionResult.IndexStatusResult.LastCrawlTime
 				fmt.Printf("[GOOGLE-CLIENT] 最后抓取时间: %s\n", result.IndexStatusResult.LastCrawled)
@@ -0,0 +1,459 @@
+// Package leader 多副本部署时的任务选主：每个任务按名称竞争一把分布式锁，
+// 持有锁的节点运行任务，锁丢失（连接断开、续期失败）时停止，其他节点在下一轮竞选中接管
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultInterval 默认的竞选/续期间隔
+const DefaultInterval = 5 * time.Second
+
+// ErrHeldElsewhere 任务的锁由其他节点持有，或本节点在竞选中但不是主节点
+var ErrHeldElsewhere = errors.New("leader: job is held by another node")
+
+// Locker 分布式锁
+type Locker interface {
+	// TryLock 尝试获取 name 的锁，本节点已持有时确认锁仍有效（续期）；返回是否持有
+	TryLock(ctx context.Context, name string) (bool, error)
+	// Unlock 释放本节点持有的锁
+	Unlock(ctx context.Context, name string) error
+	// Holder 返回当前持有锁的节点 ID，无人持有时为空
+	Holder(ctx context.Context, name string) (string, error)
+	// Close 释放全部锁并关闭连接
+	Close() error
+}
+
+// Config 选主配置
+type Config struct {
+	// NodeID 本节点标识，为空时使用 DefaultNodeID
+	NodeID string
+	// Locker 分布式锁，为空时使用 LocalLocker（单实例部署）
+	Locker Locker
+	// Interval 竞选与续期的间隔，0 表示 DefaultInterval
+	Interval time.Duration
+	// OnError 锁操作出错时回调，为空时忽略
+	OnError func(job string, err error)
+	// OnChange 本节点成为或不再是任务的主节点时回调，为空时忽略
+	OnChange func(job string, leading bool)
+}
+
+// JobStatus 任务的选主状态
+type JobStatus struct {
+	Name string `json:"name"`
+	// Enabled 本节点是否在参与该任务的竞选
+	Enabled bool `json:"enabled"`
+	// Leader 本节点是否为该任务的主节点
+	Leader bool `json:"leader"`
+	// Holder 当前持有该任务的节点，无人持有时为空
+	Holder string `json:"holder"`
+	// Since 本节点成为主节点的时间
+	Since *time.Time `json:"since,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+type job struct {
+	name  string
+	start func()
+	stop  func()
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// 以下字段由 Elector.mu 保护
+	leading bool
+	since   time.Time
+}
+
+// Elector 按任务名竞选，可并发使用
+type Elector struct {
+	cfg    Config
+	locker Locker
+
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	// leaseMu 串行化 Acquire 与释放，保护 leases
+	leaseMu sync.Mutex
+	leases  map[string]*lease
+}
+
+// lease 未参与竞选时为一次性执行临时持有的锁，refs 为尚未释放的次数
+type lease struct {
+	refs   int
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New 创建选主器
+func New(cfg Config) *Elector {
+	if cfg.NodeID == "" {
+		cfg.NodeID = DefaultNodeID()
+	}
+	if cfg.Locker == nil {
+		cfg.Locker = NewLocalLocker(cfg.NodeID)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Elector{cfg: cfg, locker: cfg.Locker, jobs: make(map[string]*job), leases: make(map[string]*lease)}
+}
+
+// DefaultNodeID 主机名加进程号
+func DefaultNodeID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "node"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// NodeID 返回本节点标识
+func (e *Elector) NodeID() string {
+	return e.cfg.NodeID
+}
+
+// Run 开始竞选任务 name：获得锁后调用 start，失去锁后调用 stop。
+// 已在竞选中时忽略；start/stop 在竞选协程中串行调用
+func (e *Elector) Run(name string, start, stop func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.jobs[name]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{name: name, start: start, stop: stop, cancel: cancel, done: make(chan struct{})}
+	e.jobs[name] = j
+	go e.campaign(ctx, j)
+}
+
+// Resign 退出任务 name 的竞选：本节点为主节点时先调用 stop 再释放锁
+func (e *Elector) Resign(name string) {
+	e.mu.Lock()
+	j, ok := e.jobs[name]
+	delete(e.jobs, name)
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	j.cancel()
+	<-j.done
+	e.mu.Lock()
+	leading := j.leading
+	j.leading = false
+	e.mu.Unlock()
+	if leading {
+		j.stop()
+		e.changed(name, false)
+	}
+
+	// 竞选协程可能在退出前刚拿到锁，未持有时 Unlock 为空操作
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Interval)
+	defer cancel()
+	if err := e.locker.Unlock(ctx, name); err != nil {
+		e.reportError(name, err)
+	}
+}
+
+// Active 本节点是否在参与任务 name 的竞选
+func (e *Elector) Active(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.jobs[name]
+	return ok
+}
+
+// Leading 本节点是否为任务 name 的主节点
+func (e *Elector) Leading(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	j, ok := e.jobs[name]
+	return ok && j.leading
+}
+
+// Acquire 为一次性执行（如手动触发）取得任务 name 的执行权，执行结束后调用返回的释放函数。
+// 本节点为主节点时直接返回；本节点未参与竞选时临时获取锁并按竞选间隔续期，释放后其他节点才能获得；
+// 锁由其他节点持有，或本节点在竞选中但尚未成为主节点时返回 ErrHeldElsewhere
+func (e *Elector) Acquire(ctx context.Context, name string) (func(), error) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	e.mu.Lock()
+	j, campaigning := e.jobs[name]
+	leading := campaigning && j.leading
+	e.mu.Unlock()
+	if leading {
+		return func() {}, nil
+	}
+	if campaigning {
+		return nil, e.heldElsewhere(ctx, name)
+	}
+
+	l, ok := e.leases[name]
+	if !ok {
+		lockCtx, cancel := context.WithTimeout(ctx, e.cfg.Interval)
+		held, err := e.locker.TryLock(lockCtx, name)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			return nil, e.heldElsewhere(ctx, name)
+		}
+		renewCtx, cancelRenew := context.WithCancel(context.Background())
+		l = &lease{cancel: cancelRenew, done: make(chan struct{})}
+		e.leases[name] = l
+		go e.renew(renewCtx, name, l)
+	}
+	l.refs++
+
+	var once sync.Once
+	return func() { once.Do(func() { e.release(name, l) }) }, nil
+}
+
+// release 释放一次 Acquire 取得的租约，全部释放后停止续期并解锁；
+// 期间本节点已开始竞选时锁交由竞选协程管理，不再解锁
+func (e *Elector) release(name string, l *lease) {
+	e.leaseMu.Lock()
+	defer e.leaseMu.Unlock()
+
+	l.refs--
+	if l.refs > 0 {
+		return
+	}
+	delete(e.leases, name)
+	l.cancel()
+	<-l.done
+	if e.Active(name) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Interval)
+	defer cancel()
+	if err := e.locker.Unlock(ctx, name); err != nil {
+		e.reportError(name, err)
+	}
+}
+
+// renew 按竞选间隔续期租约，锁丢失时只上报错误，正在进行的执行不受影响
+func (e *Elector) renew(ctx context.Context, name string, l *lease) {
+	defer close(l.done)
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		lockCtx, cancel := context.WithTimeout(ctx, e.cfg.Interval)
+		held, err := e.locker.TryLock(lockCtx, name)
+		cancel()
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			e.reportError(name, err)
+		case !held:
+			e.reportError(name, fmt.Errorf("任务 %s 的临时锁已被其他节点获取", name))
+		}
+	}
+}
+
+// heldElsewhere 返回带持有者的 ErrHeldElsewhere
+func (e *Elector) heldElsewhere(ctx context.Context, name string) error {
+	holder, err := e.locker.Holder(ctx, name)
+	if err != nil || holder == "" {
+		return ErrHeldElsewhere
+	}
+	return fmt.Errorf("%w: %s", ErrHeldElsewhere, holder)
+}
+
+// Status 返回各任务的选主状态，names 为空时只返回本节点参与竞选的任务
+func (e *Elector) Status(ctx context.Context, names ...string) []JobStatus {
+	e.mu.Lock()
+	if len(names) == 0 {
+		for name := range e.jobs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	statuses := make([]JobStatus, len(names))
+	for i, name := range names {
+		statuses[i] = JobStatus{Name: name}
+		if j, ok := e.jobs[name]; ok {
+			statuses[i].Enabled = true
+			statuses[i].Leader = j.leading
+			if j.leading {
+				since := j.since
+				statuses[i].Since = &since
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	for i := range statuses {
+		holder, err := e.locker.Holder(ctx, statuses[i].Name)
+		if err != nil {
+			statuses[i].Error = err.Error()
+			if statuses[i].Leader {
+				statuses[i].Holder = e.cfg.NodeID
+			}
+			continue
+		}
+		statuses[i].Holder = holder
+	}
+	return statuses
+}
+
+// Close 退出全部竞选并关闭锁
+func (e *Elector) Close() error {
+	e.mu.Lock()
+	names := make([]string, 0, len(e.jobs))
+	for name := range e.jobs {
+		names = append(names, name)
+	}
+	e.mu.Unlock()
+	for _, name := range names {
+		e.Resign(name)
+	}
+	return e.locker.Close()
+}
+
+func (e *Elector) campaign(ctx context.Context, j *job) {
+	defer close(j.done)
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		e.tick(ctx, j)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 一轮竞选：获得锁时启动任务，锁丢失或出错时停止任务
+func (e *Elector) tick(ctx context.Context, j *job) {
+	lockCtx, cancel := context.WithTimeout(ctx, e.cfg.Interval)
+	held, err := e.locker.TryLock(lockCtx, j.name)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		e.reportError(j.name, err)
+		held = false
+	}
+
+	e.mu.Lock()
+	leading := j.leading
+	if held != leading {
+		j.leading = held
+		if held {
+			j.since = time.Now()
+		}
+	}
+	e.mu.Unlock()
+
+	switch {
+	case held && !leading:
+		j.start()
+		e.changed(j.name, true)
+	case !held && leading:
+		j.stop()
+		e.changed(j.name, false)
+	}
+}
+
+func (e *Elector) changed(name string, leading bool) {
+	leaderGauge.WithLabelValues(name).Set(boolGauge(leading))
+	if leading {
+		transitionsTotal.WithLabelValues(name).Inc()
+	}
+	if e.cfg.OnChange != nil {
+		e.cfg.OnChange(name, leading)
+	}
+}
+
+func (e *Elector) reportError(name string, err error) {
+	if e.cfg.OnError != nil {
+		e.cfg.OnError(name, err)
+	}
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultElector *Elector
+)
+
+// Default 返回全局选主器，未启用选主（单实例部署）时为 nil
+func Default() *Elector {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultElector
+}
+
+// SetDefault 设置全局选主器，需在注册调度任务与加载插件之前调用
+func SetDefault(e *Elector) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultElector = e
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// LocalLocker 进程内锁，单实例部署时使用，所有任务都由本节点持有
+type LocalLocker struct {
+	nodeID string
+	mu     sync.Mutex
+	held   map[string]bool
+}
+
+// NewLocalLocker 创建进程内锁
+func NewLocalLocker(nodeID string) *LocalLocker {
+	return &LocalLocker{nodeID: nodeID, held: make(map[string]bool)}
+}
+
+// TryLock 总是成功
+func (l *LocalLocker) TryLock(_ context.Context, name string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held[name] = true
+	return true, nil
+}
+
+// Unlock 释放锁
+func (l *LocalLocker) Unlock(_ context.Context, name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.held, name)
+	return nil
+}
+
+// Holder 返回本节点或空
+func (l *LocalLocker) Holder(_ context.Context, name string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[name] {
+		return l.nodeID, nil
+	}
+	return "", nil
+}
+
+// Close 无操作
+func (l *LocalLocker) Close() error {
+	return nil
+}
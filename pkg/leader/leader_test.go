@@ -0,0 +1,247 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sharedLocks 模拟数据库中的锁表，多个 fakeLocker 共用以模拟多副本
+type sharedLocks struct {
+	mu      sync.Mutex
+	holders map[string]string
+}
+
+// fakeLocker 单个节点的锁连接，down 时模拟连接断开（锁随之释放）
+type fakeLocker struct {
+	shared *sharedLocks
+	nodeID string
+	down   atomic.Bool
+}
+
+func newShared() *sharedLocks {
+	return &sharedLocks{holders: make(map[string]string)}
+}
+
+func (s *sharedLocks) locker(nodeID string) *fakeLocker {
+	return &fakeLocker{shared: s, nodeID: nodeID}
+}
+
+func (f *fakeLocker) TryLock(_ context.Context, name string) (bool, error) {
+	if f.down.Load() {
+		return false, errors.New("connection lost")
+	}
+	f.shared.mu.Lock()
+	defer f.shared.mu.Unlock()
+	holder, ok := f.shared.holders[name]
+	if !ok {
+		f.shared.holders[name] = f.nodeID
+		return true, nil
+	}
+	return holder == f.nodeID, nil
+}
+
+func (f *fakeLocker) Unlock(_ context.Context, name string) error {
+	f.shared.mu.Lock()
+	defer f.shared.mu.Unlock()
+	if f.shared.holders[name] == f.nodeID {
+		delete(f.shared.holders, name)
+	}
+	return nil
+}
+
+func (f *fakeLocker) Holder(_ context.Context, name string) (string, error) {
+	f.shared.mu.Lock()
+	defer f.shared.mu.Unlock()
+	return f.shared.holders[name], nil
+}
+
+func (f *fakeLocker) Close() error { return nil }
+
+// disconnect 模拟连接断开：数据库释放该节点的全部锁
+func (f *fakeLocker) disconnect() {
+	f.down.Store(true)
+	f.shared.mu.Lock()
+	defer f.shared.mu.Unlock()
+	for name, holder := range f.shared.holders {
+		if holder == f.nodeID {
+			delete(f.shared.holders, name)
+		}
+	}
+}
+
+// runningJob 记录任务在本节点的运行状态
+type runningJob struct {
+	running atomic.Bool
+	starts  atomic.Int32
+}
+
+func (r *runningJob) start() {
+	r.running.Store(true)
+	r.starts.Add(1)
+}
+
+func (r *runningJob) stop() {
+	r.running.Store(false)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestSingleLeaderAndFailover(t *testing.T) {
+	shared := newShared()
+	lockerA, lockerB := shared.locker("a"), shared.locker("b")
+	a := New(Config{NodeID: "a", Locker: lockerA, Interval: 10 * time.Millisecond})
+	b := New(Config{NodeID: "b", Locker: lockerB, Interval: 10 * time.Millisecond})
+	defer a.Close()
+	defer b.Close()
+
+	var jobA, jobB runningJob
+	a.Run("cleanup", jobA.start, jobA.stop)
+	waitFor(t, "a to lead", jobA.running.Load)
+	b.Run("cleanup", jobB.start, jobB.stop)
+
+	time.Sleep(50 * time.Millisecond)
+	if jobB.running.Load() || !b.Active("cleanup") || b.Leading("cleanup") {
+		t.Fatal("follower should campaign without running the job")
+	}
+
+	statuses := b.Status(context.Background(), "cleanup", "sitemap")
+	if len(statuses) != 2 || statuses[0].Holder != "a" || !statuses[0].Enabled || statuses[0].Leader {
+		t.Fatalf("status = %+v", statuses)
+	}
+	if statuses[1].Enabled || statuses[1].Holder != "" {
+		t.Fatalf("unknown job status = %+v", statuses[1])
+	}
+
+	// 主节点连接断开：本地停止任务，从节点接管
+	lockerA.disconnect()
+	waitFor(t, "a to stop", func() bool { return !jobA.running.Load() })
+	waitFor(t, "b to take over", jobB.running.Load)
+	if holder, _ := lockerB.Holder(context.Background(), "cleanup"); holder != "b" {
+		t.Fatalf("holder = %q, want b", holder)
+	}
+}
+
+func TestResignHandsOver(t *testing.T) {
+	shared := newShared()
+	a := New(Config{NodeID: "a", Locker: shared.locker("a"), Interval: 10 * time.Millisecond})
+	b := New(Config{NodeID: "b", Locker: shared.locker("b"), Interval: 10 * time.Millisecond})
+	defer b.Close()
+
+	var jobA, jobB runningJob
+	a.Run("sitemap", jobA.start, jobA.stop)
+	waitFor(t, "a to lead", jobA.running.Load)
+	b.Run("sitemap", jobB.start, jobB.stop)
+
+	// 重复 Run 不会重复启动
+	a.Run("sitemap", jobA.start, jobA.stop)
+	time.Sleep(30 * time.Millisecond)
+	if jobA.starts.Load() != 1 {
+		t.Fatalf("starts = %d, want 1", jobA.starts.Load())
+	}
+
+	a.Resign("sitemap")
+	if jobA.running.Load() || a.Active("sitemap") {
+		t.Fatal("resign should stop the job and leave the campaign")
+	}
+	waitFor(t, "b to take over", jobB.running.Load)
+
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireForOneOffRun(t *testing.T) {
+	shared := newShared()
+	lockerA, lockerB := shared.locker("a"), shared.locker("b")
+	a := New(Config{NodeID: "a", Locker: lockerA, Interval: 10 * time.Millisecond})
+	b := New(Config{NodeID: "b", Locker: lockerB, Interval: 10 * time.Millisecond})
+	defer a.Close()
+	defer b.Close()
+	ctx := context.Background()
+
+	var job runningJob
+	a.Run("backup", job.start, job.stop)
+	waitFor(t, "a to lead", job.running.Load)
+
+	// 主节点直接执行，释放不影响竞选持有的锁
+	release, err := a.Acquire(ctx, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if holder, _ := lockerB.Holder(ctx, "backup"); holder != "a" {
+		t.Fatalf("holder = %q, want a", holder)
+	}
+
+	// 其他节点持有锁：拒绝并带上持有者
+	if _, err := b.Acquire(ctx, "backup"); !errors.Is(err, ErrHeldElsewhere) || err.Error() != ErrHeldElsewhere.Error()+": a" {
+		t.Fatalf("err = %v, want ErrHeldElsewhere from a", err)
+	}
+
+	// 无人竞选的任务：临时持有锁并续期，全部释放后才解锁
+	first, err := b.Acquire(ctx, "sitemap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.Acquire(ctx, "sitemap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Acquire(ctx, "sitemap"); !errors.Is(err, ErrHeldElsewhere) {
+		t.Fatalf("err = %v, want ErrHeldElsewhere", err)
+	}
+	first()
+	first()
+	time.Sleep(30 * time.Millisecond)
+	if holder, _ := lockerA.Holder(ctx, "sitemap"); holder != "b" {
+		t.Fatalf("holder = %q, want b while a lease is outstanding", holder)
+	}
+	second()
+	if holder, _ := lockerA.Holder(ctx, "sitemap"); holder != "" {
+		t.Fatalf("holder = %q, want lock released", holder)
+	}
+
+	// 从节点在竞选中但未持有锁时同样拒绝，避免临时锁干扰竞选
+	var follower runningJob
+	b.Run("backup", follower.start, follower.stop)
+	if _, err := b.Acquire(ctx, "backup"); !errors.Is(err, ErrHeldElsewhere) {
+		t.Fatalf("err = %v, want ErrHeldElsewhere", err)
+	}
+}
+
+func TestLocalLockerAlwaysLeads(t *testing.T) {
+	e := New(Config{NodeID: "solo", Interval: 10 * time.Millisecond})
+	defer e.Close()
+
+	var job runningJob
+	e.Run("backup", job.start, job.stop)
+	waitFor(t, "job to start", job.running.Load)
+
+	statuses := e.Status(context.Background())
+	if len(statuses) != 1 || statuses[0].Holder != "solo" || !statuses[0].Leader || statuses[0].Since == nil {
+		t.Fatalf("status = %+v", statuses)
+	}
+}
+
+func TestLockKeyStable(t *testing.T) {
+	if lockKey("hot_drama") != lockKey("hot_drama") || lockKey("hot_drama") == lockKey("sitemap") {
+		t.Fatal("lock keys should be stable and distinct")
+	}
+	if lockKey("telegram_polling") < 0 {
+		t.Fatal("lock keys should be non-negative")
+	}
+}
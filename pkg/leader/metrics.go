@@ -0,0 +1,30 @@
+package leader
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// leaderGauge 本节点是否为任务的主节点
+	leaderGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "urldb",
+			Subsystem: "leader",
+			Name:      "is_leader",
+			Help:      "Whether this node currently leads the job (1) or not (0)",
+		},
+		[]string{"job"},
+	)
+
+	// transitionsTotal 本节点成为主节点的次数
+	transitionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "urldb",
+			Subsystem: "leader",
+			Name:      "acquired_total",
+			Help:      "Times this node acquired leadership of the job",
+		},
+		[]string{"job"},
+	)
+)
@@ -0,0 +1,158 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// advisoryNamespace 双参数 advisory lock 的第一个键，区分本应用与其他使用 advisory lock 的程序
+const advisoryNamespace int32 = 0x75726c64 // "urld"
+
+// applicationNamePrefix 锁连接的 application_name 前缀，Holder 通过 pg_stat_activity 读取持有者
+const applicationNamePrefix = "urldb-leader:"
+
+// PGLocker 基于 PostgreSQL 会话级 advisory lock 的锁：全部锁共用一条独立连接，
+// 进程退出或连接断开时数据库自动释放锁，其他节点在下一轮竞选中接管
+type PGLocker struct {
+	dsn    string
+	nodeID string
+
+	mu   sync.Mutex
+	conn *pgx.Conn
+	held map[string]bool
+}
+
+// NewPGLocker 创建锁，连接在首次使用时建立，断开后下一次调用时重连
+func NewPGLocker(dsn, nodeID string) *PGLocker {
+	return &PGLocker{dsn: dsn, nodeID: nodeID, held: make(map[string]bool)}
+}
+
+// lockKey 任务名映射到 advisory lock 的第二个键
+func lockKey(name string) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int32(h.Sum32() & 0x7fffffff)
+}
+
+// connect 确保连接可用，重连时此前持有的锁已随旧连接释放
+func (p *PGLocker) connect(ctx context.Context) error {
+	if p.conn != nil && !p.conn.IsClosed() {
+		return nil
+	}
+	p.held = make(map[string]bool)
+
+	cfg, err := pgx.ParseConfig(p.dsn)
+	if err != nil {
+		return err
+	}
+	appName := applicationNamePrefix + p.nodeID
+	if len(appName) > 63 {
+		appName = appName[:63]
+	}
+	cfg.RuntimeParams["application_name"] = appName
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	return nil
+}
+
+// reset 连接异常时关闭连接，数据库端的锁随之释放
+func (p *PGLocker) reset() {
+	if p.conn != nil {
+		p.conn.Close(context.Background())
+		p.conn = nil
+	}
+	p.held = make(map[string]bool)
+}
+
+// TryLock 获取锁；已持有时检查连接是否存活
+func (p *PGLocker) TryLock(ctx context.Context, name string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.held[name] && p.conn != nil && !p.conn.IsClosed() {
+		if err := p.conn.Ping(ctx); err != nil {
+			p.reset()
+			return false, err
+		}
+		return true, nil
+	}
+
+	if err := p.connect(ctx); err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := p.conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1, $2)", advisoryNamespace, lockKey(name)).Scan(&ok); err != nil {
+		p.reset()
+		return false, err
+	}
+	if ok {
+		p.held[name] = true
+	}
+	return ok, nil
+}
+
+// Unlock 释放锁
+func (p *PGLocker) Unlock(ctx context.Context, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.held[name] {
+		return nil
+	}
+	delete(p.held, name)
+	if p.conn == nil || p.conn.IsClosed() {
+		return nil
+	}
+	if _, err := p.conn.Exec(ctx, "SELECT pg_advisory_unlock($1, $2)", advisoryNamespace, lockKey(name)); err != nil {
+		p.reset()
+		return err
+	}
+	return nil
+}
+
+// Holder 通过 pg_locks 与 pg_stat_activity 查询持有锁的会话，返回其 application_name 中的节点 ID
+func (p *PGLocker) Holder(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.connect(ctx); err != nil {
+		return "", err
+	}
+	var appName string
+	err := p.conn.QueryRow(ctx, `
+		SELECT COALESCE(a.application_name, '')
+		FROM pg_locks l
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted
+		  AND l.classid::int8 = $1 AND l.objid::int8 = $2 AND l.objsubid = 2
+		LIMIT 1`, int64(advisoryNamespace), int64(lockKey(name))).Scan(&appName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		p.reset()
+		return "", err
+	}
+	if appName == "" {
+		// 无权查看其他会话时 application_name 为空，锁确实被持有
+		return "unknown", nil
+	}
+	return strings.TrimPrefix(appName, applicationNamePrefix), nil
+}
+
+// Close 关闭连接，释放全部锁
+func (p *PGLocker) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reset()
+	return nil
+}
@@ -0,0 +1,100 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DefaultRedisTTL 默认的锁租期，应明显大于竞选间隔
+const DefaultRedisTTL = 15 * time.Second
+
+// RedisConfig Redis 锁配置
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// Prefix 键前缀
+	Prefix string
+	// TTL 锁租期，持有者每轮竞选续期；持有者宕机后最多 TTL 后由其他节点接管
+	TTL time.Duration
+}
+
+// acquireScript 本节点已持有时续期，无人持有时获取
+var acquireScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if v == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if not v then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// releaseScript 只删除本节点持有的锁
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker 基于 Redis 租约的锁，值为持有者节点 ID
+type RedisLocker struct {
+	client *redis.Client
+	prefix string
+	nodeID string
+	ttl    time.Duration
+}
+
+// NewRedisLocker 连接 Redis，连接失败时返回错误
+func NewRedisLocker(cfg RedisConfig, nodeID string) (*RedisLocker, error) {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "urldb:leader:"
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultRedisTTL
+	}
+	client := redis.NewClient(&redis.Options{Addr: cfg.Addr, Password: cfg.Password, DB: cfg.DB})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisLocker{client: client, prefix: cfg.Prefix, nodeID: nodeID, ttl: cfg.TTL}, nil
+}
+
+// TryLock 获取或续期锁
+func (r *RedisLocker) TryLock(ctx context.Context, name string) (bool, error) {
+	n, err := acquireScript.Run(ctx, r.client, []string{r.prefix + name}, r.nodeID, r.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Unlock 释放本节点持有的锁
+func (r *RedisLocker) Unlock(ctx context.Context, name string) error {
+	return releaseScript.Run(ctx, r.client, []string{r.prefix + name}, r.nodeID).Err()
+}
+
+// Holder 返回锁的持有者
+func (r *RedisLocker) Holder(ctx context.Context, name string) (string, error) {
+	holder, err := r.client.Get(ctx, r.prefix+name).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return holder, err
+}
+
+// Close 关闭连接，未释放的锁在租期结束后失效
+func (r *RedisLocker) Close() error {
+	return r.client.Close()
+}
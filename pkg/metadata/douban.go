@@ -0,0 +1,214 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDoubanBaseURL = "https://m.douban.com/rexxar/api/v2"
+	defaultTimeout       = 20 * time.Second
+	maxBodySize          = 5 << 20
+)
+
+// DoubanProvider 豆瓣移动端接口（与热播剧使用同一套 rexxar 接口）
+type DoubanProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewDoubanProvider 创建豆瓣数据源
+func NewDoubanProvider() *DoubanProvider {
+	return &DoubanProvider{
+		BaseURL: defaultDoubanBaseURL,
+		Client:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Name 提供方标识
+func (p *DoubanProvider) Name() string {
+	return ProviderDouban
+}
+
+type doubanRating struct {
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
+type doubanPerson struct {
+	Name string `json:"name"`
+}
+
+type doubanSubject struct {
+	ID            string         `json:"id"`
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	OriginalTitle string         `json:"original_title"`
+	Year          string         `json:"year"`
+	CardSubtitle  string         `json:"card_subtitle"`
+	Rating        *doubanRating  `json:"rating"`
+	CoverURL      string         `json:"cover_url"`
+	Pic           *doubanPic     `json:"pic"`
+	Genres        []string       `json:"genres"`
+	Countries     []string       `json:"countries"`
+	EpisodesCount int            `json:"episodes_count"`
+	IsTV          bool           `json:"is_tv"`
+	Intro         string         `json:"intro"`
+	Directors     []doubanPerson `json:"directors"`
+	Actors        []doubanPerson `json:"actors"`
+	URL           string         `json:"url"`
+	SharingURL    string         `json:"sharing_url"`
+}
+
+// doubanPic 豆瓣图片地址
+type doubanPic struct {
+	Large  string `json:"large"`
+	Normal string `json:"normal"`
+}
+
+// Search 搜索影视条目
+func (p *DoubanProvider) Search(ctx context.Context, q Query) ([]Subject, error) {
+	if strings.TrimSpace(q.Name) == "" {
+		return nil, nil
+	}
+	params := url.Values{}
+	params.Set("q", q.Name)
+	params.Set("start", "0")
+	params.Set("count", "10")
+
+	var resp struct {
+		Items []struct {
+			TargetType string        `json:"target_type"`
+			Target     doubanSubject `json:"target"`
+		} `json:"items"`
+	}
+	if err := p.get(ctx, "/search/movie?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	var subjects []Subject
+	for _, item := range resp.Items {
+		if item.TargetType != MediaMovie && item.TargetType != MediaTV {
+			continue
+		}
+		target := item.Target
+		target.Type = item.TargetType
+		subjects = append(subjects, target.toSubject())
+	}
+	return subjects, nil
+}
+
+// Detail 获取条目详情
+func (p *DoubanProvider) Detail(ctx context.Context, mediaType, id string) (*Subject, error) {
+	if mediaType != MediaTV {
+		mediaType = MediaMovie
+	}
+	var raw doubanSubject
+	if err := p.get(ctx, "/"+mediaType+"/"+url.PathEscape(id), &raw); err != nil {
+		return nil, err
+	}
+	if raw.ID == "" {
+		return nil, fmt.Errorf("豆瓣条目不存在: %s", id)
+	}
+	if raw.Type == "" {
+		raw.Type = mediaType
+	}
+	subject := raw.toSubject()
+	return &subject, nil
+}
+
+func (p *DoubanProvider) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.BaseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1")
+	req.Header.Set("Referer", "https://m.douban.com/")
+	req.Header.Set("Accept", "application/json, text/plain, */*")
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("豆瓣接口返回状态码 %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析豆瓣响应失败: %v", err)
+	}
+	return nil
+}
+
+func (d doubanSubject) toSubject() Subject {
+	s := Subject{
+		Provider:      ProviderDouban,
+		ID:            d.ID,
+		MediaType:     d.Type,
+		Title:         d.Title,
+		OriginalTitle: d.OriginalTitle,
+		Genres:        d.Genres,
+		Regions:       d.Countries,
+		Episodes:      d.EpisodesCount,
+		Summary:       d.Intro,
+		URL:           d.URL,
+	}
+	if s.MediaType == "" {
+		s.MediaType = MediaMovie
+		if d.IsTV {
+			s.MediaType = MediaTV
+		}
+	}
+	if s.URL == "" {
+		s.URL = d.SharingURL
+	}
+	s.Year, _ = strconv.Atoi(strings.TrimSpace(d.Year))
+	if d.Rating != nil {
+		s.Rating = d.Rating.Value
+		s.RatingCount = d.Rating.Count
+	}
+	if d.Pic != nil && d.Pic.Large != "" {
+		s.Poster = d.Pic.Large
+	} else if d.Pic != nil && d.Pic.Normal != "" {
+		s.Poster = d.Pic.Normal
+	} else {
+		s.Poster = d.CoverURL
+	}
+	for _, person := range d.Directors {
+		s.Directors = append(s.Directors, person.Name)
+	}
+	for _, person := range d.Actors {
+		s.Actors = append(s.Actors, person.Name)
+	}
+
+	// 搜索结果只有 card_subtitle："2023 / 中国大陆 / 剧情 爱情 / 导演 / 演员"
+	if d.CardSubtitle != "" {
+		parts := strings.Split(d.CardSubtitle, " / ")
+		if s.Year == 0 && len(parts) > 0 {
+			s.Year, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+		}
+		if len(s.Regions) == 0 && len(parts) > 1 {
+			s.Regions = strings.Fields(parts[1])
+		}
+		if len(s.Genres) == 0 && len(parts) > 2 {
+			s.Genres = strings.Fields(parts[2])
+		}
+	}
+	return s
+}
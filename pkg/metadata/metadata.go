@@ -0,0 +1,334 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 影视类型
+const (
+	MediaMovie = "movie"
+	MediaTV    = "tv"
+)
+
+// 数据提供方
+const (
+	ProviderDouban = "douban"
+	ProviderTMDB   = "tmdb"
+)
+
+// Subject 影视条目（豆瓣条目或 TMDB 作品）
+type Subject struct {
+	Provider      string   `json:"provider"`
+	ID            string   `json:"id"`
+	MediaType     string   `json:"media_type"`
+	Title         string   `json:"title"`
+	OriginalTitle string   `json:"original_title,omitempty"`
+	Year          int      `json:"year,omitempty"`
+	Regions       []string `json:"regions,omitempty"`
+	Genres        []string `json:"genres,omitempty"`
+	Rating        float64  `json:"rating"`
+	RatingCount   int      `json:"rating_count"`
+	Poster        string   `json:"poster,omitempty"`
+	Episodes      int      `json:"episodes,omitempty"`
+	Directors     []string `json:"directors,omitempty"`
+	Actors        []string `json:"actors,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	URL           string   `json:"url,omitempty"`
+}
+
+// Provider 影视元数据来源
+type Provider interface {
+	// Name 提供方标识
+	Name() string
+	// Search 按规范化后的标题搜索候选条目（可仅包含基础字段）
+	Search(ctx context.Context, q Query) ([]Subject, error)
+	// Detail 获取条目完整信息
+	Detail(ctx context.Context, mediaType, id string) (*Subject, error)
+}
+
+// Query 从资源标题中解析出的检索条件
+type Query struct {
+	Raw    string `json:"raw"`
+	Name   string `json:"name"`             // 规范化后的片名
+	Year   int    `json:"year,omitempty"`   // 标题中的年份，0 表示未知
+	Season int    `json:"season,omitempty"` // 季数，0 表示未知
+	IsTV   bool   `json:"is_tv"`            // 标题中含有剧集特征（第x季、全x集、S01E01 等）
+}
+
+var (
+	bracketJunkRe = regexp.MustCompile(`[【\[(（][^】\])）]*(?:4K|1080|2160|720|高清|蓝光|中字|字幕|国语|粤语|双语|完结|更新|全集|合集|网盘|夸克|阿里|百度|迅雷|无水印|HDR|杜比)[^】\])）]*[】\])）]`)
+	yearRe        = regexp.MustCompile(`(?:^|[^\d])((?:19|20)\d{2})(?:[^\d]|$)`)
+	seasonRe      = regexp.MustCompile(`(?i)第\s*([0-9一二三四五六七八九十]+)\s*季|\bS(\d{1,2})(?:E\d{1,3})?\b|\bSeason\s*(\d{1,2})\b`)
+	tvHintRe      = regexp.MustCompile(`(?i)全\s*\d+\s*集|更新至|第\s*\d+\s*集|\bS\d{1,2}E\d{1,3}\b|\bEP?\d{1,3}\b|连续剧|电视剧|剧集`)
+	junkWordRe    = regexp.MustCompile(`(?i)(4K|8K|2160[Pp]?|1080[PpIi]?|720[Pp]?|HDR10\+?|HDR|杜比视界|杜比|DoVi|WEB-?DL|WEB-?Rip|BluRay|BDRip|Blu-?ray|REMUX|HEVC|[HhXx]\.?26[45]|AAC|DTS|FLAC|60帧|高码率?|蓝光原盘|蓝光|超清|高清|国粤双语|国英双语|国语|粤语|英语|双语|中英字幕|中英双字|中字|内封字幕|内嵌字幕|简繁字幕|字幕|完结|全集|合集|无删减|未删减|无水印|夸克网盘|百度网盘|阿里云盘|迅雷云盘|网盘|持续更新|更新至\S*|全\s*\d+\s*集|第\s*\d+\s*集|S\d{1,2}E\d{1,3}|\bEP?\d{1,3}\b|连续剧|电视剧|电影|剧集|纪录片|动漫|番剧)`)
+	splitRe       = regexp.MustCompile(`[\s._\-|/·:：,，、+~～]+`)
+	bracketCharRe = regexp.MustCompile(`[【】\[\]()（）《》<>「」『』"“”']`)
+)
+
+var chineseNumbers = map[rune]int{'一': 1, '二': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9, '十': 10}
+
+// ParseTitle 从资源标题解析片名、年份与季数。
+// 去掉画质、音轨、字幕、网盘等修饰词，只保留第一个修饰词之前的片名部分。
+func ParseTitle(title string) Query {
+	q := Query{Raw: title}
+	s := strings.TrimSpace(title)
+	if s == "" {
+		return q
+	}
+
+	// 《片名》优先
+	if start := strings.Index(s, "《"); start >= 0 {
+		if end := strings.Index(s[start:], "》"); end > 0 {
+			inner := s[start+len("《") : start+end]
+			if strings.TrimSpace(inner) != "" {
+				rest := s[:start] + " " + s[start+end+len("》"):]
+				q = parseRest(q, rest)
+				q.Name = cleanName(inner)
+				return q
+			}
+		}
+	}
+
+	s = bracketJunkRe.ReplaceAllString(s, " ")
+	q = parseRest(q, s)
+
+	// 截掉年份、季数与修饰词及其之后的内容；位于开头的年份视为片名的一部分（如《1917》）
+	cut := len(s)
+	if m := yearRe.FindStringSubmatchIndex(s); m != nil && m[2] > 0 {
+		cut = m[2]
+	}
+	for _, re := range []*regexp.Regexp{seasonRe, junkWordRe} {
+		if loc := re.FindStringIndex(s); loc != nil && loc[0] < cut {
+			cut = loc[0]
+		}
+	}
+	name := s[:cut]
+	if cleanName(name) == "" {
+		// 标题以年份/修饰词开头时，退化为去掉全部修饰词
+		name = seasonRe.ReplaceAllString(junkWordRe.ReplaceAllString(yearRe.ReplaceAllString(s, " "), " "), " ")
+	}
+	q.Name = cleanName(name)
+	return q
+}
+
+// parseRest 解析年份、季数与剧集特征
+func parseRest(q Query, s string) Query {
+	if m := yearRe.FindStringSubmatch(s); m != nil {
+		q.Year, _ = strconv.Atoi(m[1])
+	}
+	if m := seasonRe.FindStringSubmatch(s); m != nil {
+		for _, g := range m[1:] {
+			if g != "" {
+				q.Season = parseNumber(g)
+				break
+			}
+		}
+		q.IsTV = true
+	}
+	if tvHintRe.MatchString(s) {
+		q.IsTV = true
+	}
+	return q
+}
+
+func cleanName(s string) string {
+	s = bracketCharRe.ReplaceAllString(s, " ")
+	parts := splitRe.Split(s, -1)
+	var kept []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+func parseNumber(s string) int {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	// 仅处理一到十九、二十等常见写法
+	total, current := 0, 0
+	for _, r := range s {
+		v := chineseNumbers[r]
+		if v == 10 {
+			if current == 0 {
+				current = 1
+			}
+			total += current * 10
+			current = 0
+		} else {
+			current = v
+		}
+	}
+	return total + current
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// normalizeForCompare 统一大小写并去掉空白与标点，用于标题比较
+func normalizeForCompare(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// bigrams 按字符切分二元组
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	grams := make(map[string]int)
+	if len(runes) == 1 {
+		grams[s]++
+		return grams
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])]++
+	}
+	return grams
+}
+
+// titleSimilarity 标题相似度（0-1）：完全一致为 1，包含关系按长度比例折算，其余使用二元组 Dice 系数
+func titleSimilarity(a, b string) float64 {
+	a, b = normalizeForCompare(a), normalizeForCompare(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	la, lb := len([]rune(a)), len([]rune(b))
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		short, long := la, lb
+		if short > long {
+			short, long = long, short
+		}
+		return 0.6 + 0.35*float64(short)/float64(long)
+	}
+
+	ga, gb := bigrams(a), bigrams(b)
+	overlap, total := 0, 0
+	for g, n := range ga {
+		total += n
+		if m, ok := gb[g]; ok {
+			overlap += minInt(n, m)
+		}
+	}
+	for _, n := range gb {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(overlap) / float64(total)
+}
+
+// Score 计算候选条目与检索条件的匹配度（0-1）
+func Score(q Query, s Subject) float64 {
+	score := titleSimilarity(q.Name, s.Title)
+	if s.OriginalTitle != "" {
+		if alt := titleSimilarity(q.Name, s.OriginalTitle); alt > score {
+			score = alt
+		}
+	}
+	if score == 0 {
+		return 0
+	}
+
+	if q.Year > 0 && s.Year > 0 {
+		switch diff := q.Year - s.Year; {
+		case diff == 0:
+			score += 0.1
+		case diff == 1 || diff == -1:
+			// 上映年份与资源年份常有一年误差，不加不减
+		default:
+			score -= 0.3
+		}
+	}
+	if q.IsTV && s.MediaType == MediaMovie {
+		score -= 0.15
+	}
+	if score > 1 {
+		score = 1
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// Match 候选条目及其匹配度
+type Match struct {
+	Subject Subject `json:"subject"`
+	Score   float64 `json:"score"`
+}
+
+// Rank 按匹配度从高到低排序候选条目；同分时评分人数多者优先
+func Rank(q Query, candidates []Subject) []Match {
+	matches := make([]Match, 0, len(candidates))
+	for _, c := range candidates {
+		matches = append(matches, Match{Subject: c, Score: Score(q, c)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Subject.RatingCount > matches[j].Subject.RatingCount
+	})
+	return matches
+}
+
+// BestMatch 返回匹配度不低于 minScore 的最佳条目
+func BestMatch(q Query, candidates []Subject, minScore float64) (*Match, bool) {
+	ranked := Rank(q, candidates)
+	if len(ranked) == 0 || ranked[0].Score < minScore {
+		return nil, false
+	}
+	return &ranked[0], true
+}
+
+// Summary 生成一行摘要，如 "2023 · 中国大陆 · 剧情/爱情 · 豆瓣 8.3 · 全30集"
+func Summary(s Subject) string {
+	var parts []string
+	if s.Year > 0 {
+		parts = append(parts, strconv.Itoa(s.Year))
+	}
+	if len(s.Regions) > 0 {
+		parts = append(parts, s.Regions[0])
+	}
+	if len(s.Genres) > 0 {
+		genres := s.Genres
+		if len(genres) > 3 {
+			genres = genres[:3]
+		}
+		parts = append(parts, strings.Join(genres, "/"))
+	}
+	if s.Rating > 0 {
+		label := "评分"
+		switch s.Provider {
+		case ProviderDouban:
+			label = "豆瓣"
+		case ProviderTMDB:
+			label = "TMDB"
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f", label, s.Rating))
+	}
+	if s.Episodes > 1 {
+		parts = append(parts, fmt.Sprintf("全%d集", s.Episodes))
+	}
+	return strings.Join(parts, " · ")
+}
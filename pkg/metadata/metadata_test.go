@@ -0,0 +1,150 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseTitle(t *testing.T) {
+	tests := []struct {
+		title  string
+		name   string
+		year   int
+		season int
+		isTV   bool
+	}{
+		{title: "流浪地球2 (2023) 4K 国语中字", name: "流浪地球2", year: 2023},
+		{title: "【4K高码】奥本海默 2023 IMAX", name: "奥本海默", year: 2023},
+		{title: "【繁花】全30集 4K", name: "繁花", isTV: true},
+		{title: "庆余年第二季 2024 更新至10集", name: "庆余年", year: 2024, season: 2, isTV: true},
+		{title: "The.Last.of.Us.S01.2160p.WEB-DL", name: "The Last of Us", season: 1, isTV: true},
+		{title: "电影《长安三万里》1080P", name: "长安三万里"},
+		{title: "1917 蓝光原盘", name: "1917", year: 1917},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			q := ParseTitle(tt.title)
+			if q.Name != tt.name || q.Year != tt.year || q.Season != tt.season || q.IsTV != tt.isTV {
+				t.Fatalf("ParseTitle(%q) = %+v, want name=%q year=%d season=%d tv=%v", tt.title, q, tt.name, tt.year, tt.season, tt.isTV)
+			}
+		})
+	}
+}
+
+func TestBestMatch(t *testing.T) {
+	candidates := []Subject{
+		{ID: "1", MediaType: MediaMovie, Title: "流浪地球", Year: 2019, RatingCount: 100},
+		{ID: "2", MediaType: MediaMovie, Title: "流浪地球2", Year: 2023, RatingCount: 50},
+		{ID: "3", MediaType: MediaTV, Title: "流浪地球：飞跃2020特别版", Year: 2021},
+	}
+
+	m, ok := BestMatch(ParseTitle("流浪地球2 2023 4K"), candidates, 0.6)
+	if !ok || m.Subject.ID != "2" {
+		t.Fatalf("BestMatch = %+v, %v; want subject 2", m, ok)
+	}
+
+	// 年份不符时降分，仍可按标题匹配
+	m, ok = BestMatch(ParseTitle("流浪地球 2019"), candidates, 0.6)
+	if !ok || m.Subject.ID != "1" {
+		t.Fatalf("BestMatch = %+v, %v; want subject 1", m, ok)
+	}
+
+	if _, ok := BestMatch(ParseTitle("完全无关的片名"), candidates, 0.6); ok {
+		t.Fatal("expected no match for unrelated title")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s := Subject{Provider: ProviderDouban, Year: 2023, Regions: []string{"中国大陆"}, Genres: []string{"剧情", "爱情", "年代", "都市"}, Rating: 8.3, Episodes: 30}
+	want := "2023 · 中国大陆 · 剧情/爱情/年代 · 豆瓣 8.3 · 全30集"
+	if got := Summary(s); got != want {
+		t.Fatalf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestDoubanProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/search/movie":
+			if r.URL.Query().Get("q") != "繁花" {
+				t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+			}
+			w.Write([]byte(`{"items":[
+				{"target_type":"tv","target":{"id":"35","title":"繁花","year":"2023","card_subtitle":"2023 / 中国大陆 / 剧情 爱情 / 王家卫 / 胡歌","rating":{"value":8.7,"count":500},"cover_url":"http://img/s.jpg"}},
+				{"target_type":"person","target":{"id":"9","title":"胡歌"}}
+			]}`))
+		case r.URL.Path == "/tv/35":
+			w.Write([]byte(`{"id":"35","title":"繁花","year":"2023","is_tv":true,"genres":["剧情","爱情"],"countries":["中国大陆"],"episodes_count":30,"rating":{"value":8.7,"count":520},"pic":{"large":"http://img/l.jpg"},"intro":"简介","directors":[{"name":"王家卫"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := &DoubanProvider{BaseURL: srv.URL, Client: srv.Client()}
+	subjects, err := p.Search(context.Background(), Query{Name: "繁花"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subjects) != 1 || subjects[0].MediaType != MediaTV || subjects[0].Year != 2023 || subjects[0].Regions[0] != "中国大陆" || subjects[0].Poster != "http://img/s.jpg" {
+		t.Fatalf("unexpected search result: %+v", subjects)
+	}
+
+	detail, err := p.Detail(context.Background(), MediaTV, "35")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Episodes != 30 || detail.Poster != "http://img/l.jpg" || detail.RatingCount != 520 || strings.Join(detail.Directors, ",") != "王家卫" {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+
+	if _, err := p.Detail(context.Background(), MediaMovie, "404"); err == nil {
+		t.Fatal("expected error for missing subject")
+	}
+}
+
+func TestTMDBProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "k" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/search/multi":
+			w.Write([]byte(`{"results":[
+				{"id":1,"media_type":"movie","title":"奥本海默","original_title":"Oppenheimer","release_date":"2023-07-19","vote_average":8.1,"vote_count":900,"poster_path":"/p.jpg"},
+				{"id":2,"media_type":"person","name":"诺兰"}
+			]}`))
+		case "/movie/1":
+			w.Write([]byte(`{"id":1,"title":"奥本海默","release_date":"2023-07-19","genres":[{"name":"剧情"},{"name":"历史"}],"production_countries":[{"name":"美国"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewTMDBProvider("k")
+	p.BaseURL, p.ImageURL, p.Client = srv.URL, "http://img", srv.Client()
+
+	subjects, err := p.Search(context.Background(), Query{Name: "Oppenheimer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subjects) != 1 || subjects[0].OriginalTitle != "Oppenheimer" || subjects[0].Year != 2023 || subjects[0].Poster != "http://img/p.jpg" {
+		t.Fatalf("unexpected search result: %+v", subjects)
+	}
+	detail, err := p.Detail(context.Background(), MediaMovie, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(detail.Genres, ",") != "剧情,历史" || detail.Regions[0] != "美国" {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+
+	if _, err := NewTMDBProvider("").Search(context.Background(), Query{Name: "x"}); err == nil {
+		t.Fatal("expected error without api key")
+	}
+}
@@ -0,0 +1,187 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTMDBBaseURL  = "https://api.themoviedb.org/3"
+	defaultTMDBImageURL = "https://image.tmdb.org/t/p/w500"
+)
+
+// TMDBProvider TMDB 数据源，豆瓣不可用时作为替代
+type TMDBProvider struct {
+	BaseURL  string
+	ImageURL string
+	APIKey   string // v3 API Key 或 v4 读访问令牌
+	Language string
+	Client   *http.Client
+}
+
+// NewTMDBProvider 创建 TMDB 数据源
+func NewTMDBProvider(apiKey string) *TMDBProvider {
+	return &TMDBProvider{
+		BaseURL:  defaultTMDBBaseURL,
+		ImageURL: defaultTMDBImageURL,
+		APIKey:   apiKey,
+		Language: "zh-CN",
+		Client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Name 提供方标识
+func (p *TMDBProvider) Name() string {
+	return ProviderTMDB
+}
+
+type tmdbNamed struct {
+	Name string `json:"name"`
+}
+
+type tmdbItem struct {
+	ID               int64       `json:"id"`
+	MediaType        string      `json:"media_type"`
+	Title            string      `json:"title"`
+	Name             string      `json:"name"`
+	OriginalTitle    string      `json:"original_title"`
+	OriginalName     string      `json:"original_name"`
+	ReleaseDate      string      `json:"release_date"`
+	FirstAirDate     string      `json:"first_air_date"`
+	VoteAverage      float64     `json:"vote_average"`
+	VoteCount        int         `json:"vote_count"`
+	PosterPath       string      `json:"poster_path"`
+	Overview         string      `json:"overview"`
+	Genres           []tmdbNamed `json:"genres"`
+	OriginCountry    []string    `json:"origin_country"`
+	Countries        []tmdbNamed `json:"production_countries"`
+	NumberOfEpisodes int         `json:"number_of_episodes"`
+}
+
+// Search 搜索电影与剧集
+func (p *TMDBProvider) Search(ctx context.Context, q Query) ([]Subject, error) {
+	if strings.TrimSpace(q.Name) == "" {
+		return nil, nil
+	}
+	params := url.Values{}
+	params.Set("query", q.Name)
+	params.Set("include_adult", "false")
+
+	var resp struct {
+		Results []tmdbItem `json:"results"`
+	}
+	if err := p.get(ctx, "/search/multi", params, &resp); err != nil {
+		return nil, err
+	}
+
+	var subjects []Subject
+	for _, item := range resp.Results {
+		if item.MediaType != MediaMovie && item.MediaType != MediaTV {
+			continue
+		}
+		subjects = append(subjects, p.toSubject(item, item.MediaType))
+	}
+	return subjects, nil
+}
+
+// Detail 获取作品详情
+func (p *TMDBProvider) Detail(ctx context.Context, mediaType, id string) (*Subject, error) {
+	if mediaType != MediaTV {
+		mediaType = MediaMovie
+	}
+	var item tmdbItem
+	if err := p.get(ctx, "/"+mediaType+"/"+url.PathEscape(id), url.Values{}, &item); err != nil {
+		return nil, err
+	}
+	if item.ID == 0 {
+		return nil, fmt.Errorf("TMDB 作品不存在: %s", id)
+	}
+	subject := p.toSubject(item, mediaType)
+	return &subject, nil
+}
+
+func (p *TMDBProvider) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	if p.APIKey == "" {
+		return fmt.Errorf("未配置 TMDB API Key")
+	}
+	if p.Language != "" {
+		params.Set("language", p.Language)
+	}
+	// v4 读访问令牌为 JWT，使用 Bearer 认证；否则作为 v3 api_key 参数
+	bearer := strings.Count(p.APIKey, ".") == 2
+	if !bearer {
+		params.Set("api_key", p.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.BaseURL, "/")+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("TMDB 接口返回状态码 %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("解析 TMDB 响应失败: %v", err)
+	}
+	return nil
+}
+
+func (p *TMDBProvider) toSubject(item tmdbItem, mediaType string) Subject {
+	s := Subject{
+		Provider:      ProviderTMDB,
+		ID:            strconv.FormatInt(item.ID, 10),
+		MediaType:     mediaType,
+		Title:         item.Title,
+		OriginalTitle: item.OriginalTitle,
+		Rating:        item.VoteAverage,
+		RatingCount:   item.VoteCount,
+		Episodes:      item.NumberOfEpisodes,
+		Summary:       item.Overview,
+		URL:           fmt.Sprintf("https://www.themoviedb.org/%s/%d", mediaType, item.ID),
+	}
+	date := item.ReleaseDate
+	if mediaType == MediaTV {
+		s.Title, s.OriginalTitle, date = item.Name, item.OriginalName, item.FirstAirDate
+	}
+	if len(date) >= 4 {
+		s.Year, _ = strconv.Atoi(date[:4])
+	}
+	if item.PosterPath != "" {
+		s.Poster = strings.TrimRight(p.ImageURL, "/") + item.PosterPath
+	}
+	for _, g := range item.Genres {
+		s.Genres = append(s.Genres, g.Name)
+	}
+	for _, c := range item.Countries {
+		s.Regions = append(s.Regions, c.Name)
+	}
+	if len(s.Regions) == 0 {
+		s.Regions = item.OriginCountry
+	}
+	return s
+}
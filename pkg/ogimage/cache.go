@@ -0,0 +1,257 @@
+package ogimage
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRemoteImageSize 远程封面/Logo 的最大下载大小
+const maxRemoteImageSize = 10 << 20
+
+var safeKeyRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// CacheStats 磁盘缓存统计
+type CacheStats struct {
+	Keys  int   `json:"keys"`
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// Cache OG图片磁盘缓存，文件位于 <dir>/<key>/<fingerprint>.<format>。
+// fingerprint 由模板版本与绘制内容计算，资源更新或模板修改后自然失效，写入新文件时清理同一 Key 下的旧文件。
+type Cache struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewCache 创建磁盘缓存
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir 缓存目录
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get 读取缓存
+func (c *Cache) Get(key, fingerprint, format string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key, fingerprint, format))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Put 写入缓存，并删除同一 Key 下其他 fingerprint 的旧文件
+func (c *Cache) Put(key, fingerprint, format string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dir := filepath.Join(c.dir, safeKey(key))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	target := c.path(key, fingerprint, format)
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, fingerprint+".") && !strings.HasPrefix(name, ".tmp-") {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+	return nil
+}
+
+// Has 判断缓存是否存在
+func (c *Cache) Has(key, fingerprint, format string) bool {
+	_, err := os.Stat(c.path(key, fingerprint, format))
+	return err == nil
+}
+
+// Invalidate 删除某个 Key 的全部缓存
+func (c *Cache) Invalidate(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return os.RemoveAll(filepath.Join(c.dir, safeKey(key)))
+}
+
+// Clear 清空缓存目录（保留远程图片缓存）
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == assetsDir {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stats 统计缓存的 Key 数、文件数与占用空间
+func (c *Cache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == assetsDir {
+			continue
+		}
+		stats.Keys++
+		files, err := os.ReadDir(filepath.Join(c.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if info, err := f.Info(); err == nil && !strings.HasPrefix(f.Name(), ".tmp-") {
+				stats.Files++
+				stats.Bytes += info.Size()
+			}
+		}
+	}
+	return stats, nil
+}
+
+func (c *Cache) path(key, fingerprint, format string) string {
+	return filepath.Join(c.dir, safeKey(key), fingerprint+"."+format)
+}
+
+// safeKey 资源 Key 一般只含字母数字，其他情况使用哈希作为目录名
+func safeKey(key string) string {
+	if safeKeyRe.MatchString(key) && key != assetsDir {
+		return key
+	}
+	return "h-" + hashString(key)
+}
+
+// assetsDir 远程图片缓存所在的子目录
+const assetsDir = "_assets"
+
+// ImageLoader 加载封面与 Logo：/uploads/ 下的文件直接读本地，远程图片下载后缓存到磁盘，避免每次绘制都重新下载
+type ImageLoader struct {
+	dir       string
+	uploadDir string
+	client    *http.Client
+}
+
+// NewImageLoader 创建图片加载器，远程图片缓存在 cacheDir/_assets 下
+func NewImageLoader(cacheDir, uploadDir string) *ImageLoader {
+	return &ImageLoader{
+		dir:       filepath.Join(cacheDir, assetsDir),
+		uploadDir: uploadDir,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Load 加载并解码图片
+func (l *ImageLoader) Load(src string) (image.Image, error) {
+	data, err := l.read(src)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+	return img, nil
+}
+
+func (l *ImageLoader) read(src string) ([]byte, error) {
+	src = strings.TrimSpace(src)
+	if rel, ok := strings.CutPrefix(src, "/uploads/"); ok {
+		clean := filepath.Clean("/" + rel)
+		return os.ReadFile(filepath.Join(l.uploadDir, clean))
+	}
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return nil, fmt.Errorf("不支持的图片地址: %s", src)
+	}
+
+	cached := filepath.Join(l.dir, hashString(src))
+	if data, err := os.ReadFile(cached); err == nil {
+		return data, nil
+	}
+
+	resp, err := l.client.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载图片失败: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRemoteImageSize {
+		return nil, fmt.Errorf("图片超过 %d MB", maxRemoteImageSize>>20)
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+	if err := os.MkdirAll(l.dir, 0755); err == nil {
+		os.WriteFile(cached, data, 0644)
+	}
+	return data, nil
+}
+
+// Fingerprint 缓存指纹：模板版本 + 绘制内容（标题、描述、封面地址等）的哈希
+func Fingerprint(tpl Template, parts ...string) string {
+	return tpl.Version() + "-" + hashString(strings.Join(parts, "\x00"))[:12]
+}
+
+func hashString(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
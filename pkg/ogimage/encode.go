@@ -0,0 +1,54 @@
+package ogimage
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// 输出格式
+const (
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// Negotiate 确定输出格式：显式指定的 format 优先，其次看 Accept 是否接受 image/webp，默认 PNG
+func Negotiate(format, accept string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatWebP:
+		return FormatWebP
+	case FormatPNG:
+		return FormatPNG
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), "image/webp") && !strings.Contains(strings.ReplaceAll(params, " ", ""), "q=0") {
+			return FormatWebP
+		}
+	}
+	return FormatPNG
+}
+
+// ContentType 格式对应的 Content-Type
+func ContentType(format string) string {
+	if format == FormatWebP {
+		return "image/webp"
+	}
+	return "image/png"
+}
+
+// Encode 按格式编码图片，WebP 为无损编码
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatPNG:
+		return png.Encode(w, img)
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("不支持的图片格式: %s", format)
+	}
+}
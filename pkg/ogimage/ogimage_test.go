@@ -0,0 +1,162 @@
+package ogimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateNormalizeAndValidate(t *testing.T) {
+	tpl := Template{Name: " poster ", Layout: LayoutCoverBackground}.Normalize()
+	if err := tpl.Validate(); err != nil {
+		t.Fatalf("normalized template should be valid: %v", err)
+	}
+	if tpl.Name != "poster" || tpl.Width != 1200 || tpl.Colors.Title != "#ffffff" {
+		t.Errorf("tpl = %+v", tpl)
+	}
+
+	bad := tpl
+	bad.Colors.Title = "white"
+	if bad.Validate() == nil {
+		t.Error("expected invalid color to be rejected")
+	}
+	bad = tpl
+	bad.Logo.URL = "/etc/logo.png"
+	if bad.Validate() == nil {
+		t.Error("expected local logo path outside uploads to be rejected")
+	}
+	bad = tpl
+	bad.Logo.URL = "https://example.com/logo.png"
+	bad.QRCode.Enabled = true
+	bad.QRCode.Position = bad.Logo.Position
+	if bad.Validate() == nil {
+		t.Error("expected logo and qr code at the same position to be rejected")
+	}
+
+	changed := tpl
+	changed.Colors.Title = "#fff"
+	if changed.Version() == tpl.Version() {
+		t.Error("version should change with template")
+	}
+	if ParseColor("#fff") != (color.NRGBA{255, 255, 255, 255}) || ParseColor("#00000050").A != 0x50 {
+		t.Error("ParseColor mismatch")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct{ format, accept, want string }{
+		{"", "image/avif,image/webp,*/*", FormatWebP},
+		{"", "image/png,*/*;q=0.8", FormatPNG},
+		{"", "image/webp;q=0", FormatPNG},
+		{"png", "image/webp", FormatPNG},
+		{"WEBP", "", FormatWebP},
+	}
+	for _, c := range cases {
+		if got := Negotiate(c.format, c.accept); got != c.want {
+			t.Errorf("Negotiate(%q, %q) = %q, want %q", c.format, c.accept, got, c.want)
+		}
+	}
+}
+
+func TestRenderAndEncode(t *testing.T) {
+	cover := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for _, layout := range Layouts {
+		tpl := DefaultTemplate()
+		tpl.Layout = layout
+		tpl.Width, tpl.Height = 400, 210
+		tpl.QRCode.Enabled = true
+		tpl.QRCode.Size = 80
+		tpl.Logo.Size = 32
+		img, err := Render(tpl, Data{Title: "三体 全30集", Subtitle: "2023 · 豆瓣 8.7", Description: "根据刘慈欣同名小说改编", SiteName: "老九网盘", URL: "https://pan.example.com/r/abc", Cover: cover})
+		if err != nil {
+			t.Fatalf("%s: %v", layout, err)
+		}
+		if b := img.Bounds(); b.Dx() != 400 || b.Dy() != 210 {
+			t.Errorf("%s: bounds = %v", layout, b)
+		}
+	}
+
+	img, _ := Render(DefaultTemplate(), Data{Title: "t"})
+	var buf bytes.Buffer
+	if err := Encode(&buf, img, FormatWebP); err != nil {
+		t.Fatal(err)
+	}
+	if data := buf.Bytes(); len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		t.Error("not a webp file")
+	}
+	if Encode(&buf, img, "gif") == nil {
+		t.Error("expected unsupported format error")
+	}
+}
+
+func TestCacheReplacesStaleFingerprints(t *testing.T) {
+	c := NewCache(t.TempDir())
+	if err := c.Put("abc", "v1", FormatPNG, []byte("old")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("abc", "v2", FormatPNG, []byte("png")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("abc", "v2", FormatWebP, []byte("webp")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("abc", "v1", FormatPNG); ok {
+		t.Error("stale fingerprint should be removed")
+	}
+	if data, ok := c.Get("abc", "v2", FormatPNG); !ok || string(data) != "png" {
+		t.Error("png entry missing")
+	}
+	c.Put("../x", "v1", FormatPNG, []byte("x"))
+	if stats, _ := c.Stats(); stats.Keys != 2 || stats.Files != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if err := c.Invalidate("abc"); err != nil || c.Has("abc", "v2", FormatWebP) {
+		t.Errorf("invalidate failed: %v", err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := c.Stats(); stats.Keys != 0 {
+		t.Errorf("stats after clear = %+v", stats)
+	}
+}
+
+func TestImageLoaderCachesRemoteImages(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	uploads := t.TempDir()
+	os.WriteFile(filepath.Join(uploads, "logo.png"), buf.Bytes(), 0644)
+	l := NewImageLoader(t.TempDir(), uploads)
+
+	for i := 0; i < 2; i++ {
+		if _, err := l.Load(srv.URL + "/cover.png"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("remote hits = %d, want 1", hits)
+	}
+	if _, err := l.Load("/uploads/logo.png"); err != nil {
+		t.Error(err)
+	}
+	if _, err := l.Load("/uploads/../../etc/passwd"); err == nil {
+		t.Error("expected traversal outside uploads to fail")
+	}
+	if _, err := l.Load("file:///etc/passwd"); err == nil {
+		t.Error("expected unsupported scheme to fail")
+	}
+}
@@ -0,0 +1,302 @@
+package ogimage
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/skip2/go-qrcode"
+)
+
+// padding 文案与封面区域的内边距
+const padding = 60
+
+// fallbackFonts 模板字体加载失败时依次尝试的系统字体
+var fallbackFonts = []string{
+	"font/SourceHanSansSC-Regular.otf",
+	"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+	"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+	"C:/Windows/Fonts/msyh.ttc",   // 微软雅黑
+	"C:/Windows/Fonts/simhei.ttf", // 黑体
+	"C:/Windows/Fonts/simsun.ttc", // 宋体
+}
+
+// fallbackBoldFonts 粗体回退字体，全部失败时再使用常规字体
+var fallbackBoldFonts = []string{
+	"font/SourceHanSansSC-Bold.otf",
+	"/usr/share/fonts/opentype/noto/NotoSansCJK-Bold.ttc",
+	"C:/Windows/Fonts/msyhbd.ttc", // 微软雅黑粗体
+	"C:/Windows/Fonts/simhei.ttf", // 黑体
+}
+
+// Data 绘制用的内容
+type Data struct {
+	Title       string
+	Subtitle    string // 影视元数据摘要，如「2023 · 中国大陆 · 剧情 · 豆瓣 8.3」
+	Description string
+	SiteName    string
+	URL         string      // 资源页地址，用于底部链接与二维码
+	Cover       image.Image // 可为 nil
+	Logo        image.Image // 可为 nil
+}
+
+// rect 矩形区域
+type rect struct{ x, y, w, h float64 }
+
+// renderer 一次绘制的上下文
+type renderer struct {
+	dc    *gg.Context
+	tpl   Template
+	fonts map[bool]string // 已成功加载的字体路径，避免每次重新尝试全部候选
+}
+
+// Render 按模板绘制OG图片，模板需已通过 Normalize 与 Validate
+func Render(tpl Template, data Data) (image.Image, error) {
+	r := &renderer{dc: gg.NewContext(tpl.Width, tpl.Height), tpl: tpl, fonts: make(map[bool]string)}
+	w, h := float64(tpl.Width), float64(tpl.Height)
+
+	r.drawBackground()
+
+	// 没有封面时文案占满整个宽度
+	text := rect{0, 0, w, h}
+	if data.Cover != nil {
+		switch tpl.Layout {
+		case LayoutCoverLeft:
+			r.drawCoverContain(data.Cover, rect{0, 0, w / 3, h})
+			text = rect{w / 3, 0, w * 2 / 3, h}
+		case LayoutCoverRight:
+			r.drawCoverContain(data.Cover, rect{w * 2 / 3, 0, w / 3, h})
+			text = rect{0, 0, w * 2 / 3, h}
+		case LayoutCoverBackground:
+			r.drawCoverFill(data.Cover)
+		}
+	}
+
+	r.drawText(data, text)
+	if tpl.Decorations {
+		r.drawDecorations()
+	}
+	if tpl.ShowURL && data.URL != "" {
+		r.setFont(false, tpl.Sizes.URL)
+		r.dc.SetColor(ParseColor(tpl.Colors.URL))
+		r.dc.DrawStringAnchored(data.URL, w/2, h-40, 0.5, 0.5)
+	}
+	if tpl.QRCode.Enabled && data.URL != "" {
+		if err := r.drawQRCode(data.URL); err != nil {
+			return nil, err
+		}
+	}
+	if data.Logo != nil && tpl.Logo.URL != "" {
+		size := tpl.Logo.Size
+		x, y := r.corner(tpl.Logo.Position, size)
+		r.drawScaled(data.Logo, rect{x, y, float64(size), float64(size)})
+	}
+	return r.dc.Image(), nil
+}
+
+// drawBackground 圆角背景与渐变
+func (r *renderer) drawBackground() {
+	w, h := float64(r.tpl.Width), float64(r.tpl.Height)
+	r.dc.DrawRoundedRectangle(0, 0, w, h, r.tpl.CornerRadius)
+	r.dc.SetColor(ParseColor(r.tpl.Colors.Background))
+	r.dc.FillPreserve()
+
+	gradient := gg.NewLinearGradient(0, 0, w, h)
+	gradient.AddColorStop(0, ParseColor(r.tpl.Colors.GradientStart))
+	gradient.AddColorStop(1, ParseColor(r.tpl.Colors.GradientEnd))
+	r.dc.SetFillStyle(gradient)
+	r.dc.Fill()
+}
+
+// drawCoverContain 在区域内按比例完整绘制封面：竖图贴左、横图居中
+func (r *renderer) drawCoverContain(img image.Image, area rect) {
+	const margin = 40
+	bounds := img.Bounds()
+	aspect := float64(bounds.Dx()) / float64(bounds.Dy())
+	maxW, maxH := area.w-margin*2, area.h-margin*2
+
+	drawW, drawH := maxW, maxW/aspect
+	if drawH > maxH {
+		drawH = maxH
+		drawW = maxH * aspect
+	}
+	x := area.x + (area.w-drawW)/2
+	if aspect < 1 {
+		x = area.x + margin
+	}
+	y := area.y + (area.h-drawH)/2
+
+	dst := rect{x, y, drawW, drawH}
+	r.drawScaled(img, dst)
+	r.dc.SetColor(ParseColor(r.tpl.Colors.CoverMask))
+	r.dc.DrawRectangle(dst.x, dst.y, dst.w, dst.h)
+	r.dc.Fill()
+}
+
+// drawCoverFill 封面铺满画布（居中裁剪）并叠加遮罩
+func (r *renderer) drawCoverFill(img image.Image) {
+	w, h := float64(r.tpl.Width), float64(r.tpl.Height)
+	bounds := img.Bounds()
+	scale := math.Max(w/float64(bounds.Dx()), h/float64(bounds.Dy()))
+	drawW, drawH := float64(bounds.Dx())*scale, float64(bounds.Dy())*scale
+
+	r.dc.Push()
+	r.dc.DrawRoundedRectangle(0, 0, w, h, r.tpl.CornerRadius)
+	r.dc.Clip()
+	r.drawScaled(img, rect{(w - drawW) / 2, (h - drawH) / 2, drawW, drawH})
+	r.dc.SetColor(ParseColor(r.tpl.Colors.CoverMask))
+	r.dc.DrawRectangle(0, 0, w, h)
+	r.dc.Fill()
+	r.dc.ResetClip()
+	r.dc.Pop()
+}
+
+// drawScaled 将图片缩放绘制到目标区域
+func (r *renderer) drawScaled(img image.Image, dst rect) {
+	bounds := img.Bounds()
+	r.dc.Push()
+	r.dc.Translate(dst.x, dst.y)
+	r.dc.Scale(dst.w/float64(bounds.Dx()), dst.h/float64(bounds.Dy()))
+	r.dc.DrawImage(img, -bounds.Min.X, -bounds.Min.Y)
+	r.dc.Pop()
+}
+
+// drawText 站点名、标题、摘要与描述
+func (r *renderer) drawText(data Data, area rect) {
+	tpl := r.tpl
+	x := area.x + padding
+	maxWidth := area.w - padding*2
+
+	if data.SiteName != "" {
+		r.setFont(false, tpl.Sizes.SiteName)
+		r.dc.SetColor(ParseColor(tpl.Colors.SiteName))
+		r.dc.DrawStringAnchored(data.SiteName, x, 50, 0, 0.5)
+	}
+
+	// 标题逐步缩小字号直到单行放下，最小仍放不下时折行
+	size := tpl.Sizes.Title
+	for ; size > 24; size -= 4 {
+		r.setFont(true, size)
+		if w, _ := r.dc.MeasureString(data.Title); w <= maxWidth {
+			break
+		}
+	}
+	r.setFont(true, size)
+	r.dc.SetColor(ParseColor(tpl.Colors.Title))
+	y := float64(tpl.Height)/2 - 80
+	titleLines := wrapText(r.dc, data.Title, maxWidth, 2)
+	for i, line := range titleLines {
+		r.dc.DrawString(line, x, y+float64(i)*size*1.2)
+	}
+	y += float64(len(titleLines)-1)*size*1.2 + tpl.Sizes.Subtitle*2
+
+	if data.Subtitle != "" {
+		r.setFont(false, tpl.Sizes.Subtitle)
+		r.dc.SetColor(ParseColor(tpl.Colors.Subtitle))
+		r.dc.DrawString(data.Subtitle, x, y)
+		y += tpl.Sizes.Subtitle * 1.6
+	} else {
+		y += tpl.Sizes.Description * 0.4
+	}
+
+	if data.Description != "" {
+		r.setFont(false, tpl.Sizes.Description)
+		r.dc.SetColor(ParseColor(tpl.Colors.Description))
+		for i, line := range wrapText(r.dc, data.Description, maxWidth, 3) {
+			r.dc.DrawString(line, x, y+float64(i)*tpl.Sizes.Description*1.1)
+		}
+	}
+}
+
+// drawDecorations 装饰性圆点与底部分隔线
+func (r *renderer) drawDecorations() {
+	w, h := float64(r.tpl.Width), float64(r.tpl.Height)
+	r.dc.SetColor(ParseColor(r.tpl.Colors.Decoration))
+	r.dc.SetLineWidth(2)
+	for i := 0; i < 5; i++ {
+		r.dc.DrawCircle(float64(100+i*150), float64(100+(i%2)*200), 8)
+		r.dc.Stroke()
+	}
+	r.dc.DrawLine(60, h-80, w-60, h-80)
+	r.dc.Stroke()
+}
+
+// drawQRCode 在角标位置绘制指向资源页的二维码
+func (r *renderer) drawQRCode(url string) error {
+	q, err := qrcode.New(url, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	q.ForegroundColor = ParseColor(r.tpl.QRCode.Foreground)
+	q.BackgroundColor = ParseColor(r.tpl.QRCode.Background)
+	size := r.tpl.QRCode.Size
+	x, y := r.corner(r.tpl.QRCode.Position, size)
+	r.dc.DrawImage(q.Image(size), int(x), int(y))
+	return nil
+}
+
+// corner 角标左上角坐标，距画布边缘 40 像素
+func (r *renderer) corner(position string, size int) (float64, float64) {
+	const margin = 40
+	w, h, s := float64(r.tpl.Width), float64(r.tpl.Height), float64(size)
+	switch position {
+	case PositionTopLeft:
+		return margin, margin
+	case PositionBottomLeft:
+		return margin, h - margin - s
+	case PositionBottomRight:
+		return w - margin - s, h - margin - s
+	default:
+		return w - margin - s, margin
+	}
+}
+
+// setFont 依次尝试模板字体与回退字体，全部失败时保留 gg 的内置字体
+func (r *renderer) setFont(bold bool, size float64) bool {
+	candidates := []string{r.fonts[bold]}
+	if bold {
+		candidates = append(candidates, r.tpl.Fonts.Bold)
+		candidates = append(candidates, fallbackBoldFonts...)
+	}
+	candidates = append(candidates, r.tpl.Fonts.Regular)
+	candidates = append(candidates, fallbackFonts...)
+	for _, path := range candidates {
+		if path == "" {
+			continue
+		}
+		if err := r.dc.LoadFontFace(path, size); err == nil {
+			r.fonts[bold] = path
+			return true
+		}
+	}
+	return false
+}
+
+// wrapText 按宽度逐字折行，超过 maxLines 行时截断并在末尾加省略号
+func wrapText(dc *gg.Context, text string, maxWidth float64, maxLines int) []string {
+	text = strings.Join(strings.Fields(text), " ")
+	var lines []string
+	var current []rune
+	for _, ch := range text {
+		next := append(current, ch)
+		if w, _ := dc.MeasureString(string(next)); w > maxWidth && len(current) > 0 {
+			lines = append(lines, string(current))
+			current = []rune{ch}
+			continue
+		}
+		current = next
+	}
+	if len(current) > 0 {
+		lines = append(lines, string(current))
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := []rune(lines[maxLines-1])
+		if len(last) > 1 {
+			last = last[:len(last)-1]
+		}
+		lines[maxLines-1] = string(last) + "…"
+	}
+	return lines
+}
@@ -0,0 +1,286 @@
+package ogimage
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strings"
+)
+
+// 布局
+const (
+	LayoutCoverLeft       = "cover_left"       // 封面在左侧1/3，文案在右侧
+	LayoutCoverRight      = "cover_right"      // 封面在右侧1/3，文案在左侧
+	LayoutCoverBackground = "cover_background" // 封面铺满背景并加遮罩
+	LayoutTextOnly        = "text_only"        // 不绘制封面
+)
+
+// 角标位置（Logo、二维码）
+const (
+	PositionTopLeft     = "top_left"
+	PositionTopRight    = "top_right"
+	PositionBottomLeft  = "bottom_left"
+	PositionBottomRight = "bottom_right"
+)
+
+// Layouts 支持的布局
+var Layouts = []string{LayoutCoverLeft, LayoutCoverRight, LayoutCoverBackground, LayoutTextOnly}
+
+// Positions 支持的角标位置
+var Positions = []string{PositionTopLeft, PositionTopRight, PositionBottomLeft, PositionBottomRight}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// Fonts 字体文件路径，加载失败时依次回退到内置的候选字体
+type Fonts struct {
+	Regular string `json:"regular"`
+	Bold    string `json:"bold"`
+}
+
+// Sizes 各文字的字号
+type Sizes struct {
+	SiteName    float64 `json:"site_name"`
+	Title       float64 `json:"title"`
+	Subtitle    float64 `json:"subtitle"`
+	Description float64 `json:"description"`
+	URL         float64 `json:"url"`
+}
+
+// Colors 颜色，均为 #RGB / #RRGGBB / #RRGGBBAA
+type Colors struct {
+	Background    string `json:"background"`
+	GradientStart string `json:"gradient_start"`
+	GradientEnd   string `json:"gradient_end"`
+	SiteName      string `json:"site_name"`
+	Title         string `json:"title"`
+	Subtitle      string `json:"subtitle"`
+	Description   string `json:"description"`
+	URL           string `json:"url"`
+	Decoration    string `json:"decoration"`
+	CoverMask     string `json:"cover_mask"`
+}
+
+// Logo 站点 Logo，URL 为空时不绘制
+type Logo struct {
+	URL      string `json:"url"`
+	Position string `json:"position"`
+	Size     int    `json:"size"`
+}
+
+// QRCode 指向资源页的二维码
+type QRCode struct {
+	Enabled    bool   `json:"enabled"`
+	Position   string `json:"position"`
+	Size       int    `json:"size"`
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+}
+
+// Template OG图片模板
+type Template struct {
+	Name         string  `json:"name"`
+	Layout       string  `json:"layout"`
+	Width        int     `json:"width"`
+	Height       int     `json:"height"`
+	CornerRadius float64 `json:"corner_radius"`
+	Fonts        Fonts   `json:"fonts"`
+	Sizes        Sizes   `json:"sizes"`
+	Colors       Colors  `json:"colors"`
+	Logo         Logo    `json:"logo"`
+	QRCode       QRCode  `json:"qr_code"`
+	ShowURL      bool    `json:"show_url"`
+	Decorations  bool    `json:"decorations"`
+}
+
+// DefaultTemplate 默认模板，与原先硬编码的样式一致
+func DefaultTemplate() Template {
+	return Template{
+		Name:         "default",
+		Layout:       LayoutCoverLeft,
+		Width:        1200,
+		Height:       630,
+		CornerRadius: 20,
+		Fonts: Fonts{
+			Regular: "font/SourceHanSansSC-Regular.otf",
+			Bold:    "font/SourceHanSansSC-Bold.otf",
+		},
+		Sizes: Sizes{SiteName: 24, Title: 48, Subtitle: 26, Description: 28, URL: 20},
+		Colors: Colors{
+			Background:    "#374151",
+			GradientStart: "#1f2937",
+			GradientEnd:   "#4b5563",
+			SiteName:      "#ffffff",
+			Title:         "#ffffff",
+			Subtitle:      "#fbbf24",
+			Description:   "#e5e7eb",
+			URL:           "#d1d5db",
+			Decoration:    "#ffffff",
+			CoverMask:     "#00000050",
+		},
+		Logo:        Logo{Position: PositionTopRight, Size: 64},
+		QRCode:      QRCode{Position: PositionBottomRight, Size: 140, Foreground: "#111827", Background: "#ffffff"},
+		ShowURL:     true,
+		Decorations: true,
+	}
+}
+
+// BuiltinTemplates 内置主题，对应旧接口的 theme 参数
+func BuiltinTemplates() []Template {
+	themes := []struct{ name, bg, start, end string }{
+		{"default", "#374151", "#1f2937", "#4b5563"},
+		{"dark", "#1f2937", "#0f172a", "#374151"},
+		{"blue", "#1d4ed8", "#1e3a8a", "#3b82f6"},
+		{"green", "#065f46", "#064e3b", "#10b981"},
+		{"purple", "#6d28d9", "#5b21b6", "#8b5cf6"},
+	}
+	list := make([]Template, 0, len(themes))
+	for _, th := range themes {
+		t := DefaultTemplate()
+		t.Name = th.name
+		t.Colors.Background = th.bg
+		t.Colors.GradientStart = th.start
+		t.Colors.GradientEnd = th.end
+		list = append(list, t)
+	}
+	return list
+}
+
+// Normalize 用默认模板补齐未填写的字段
+func (t Template) Normalize() Template {
+	d := DefaultTemplate()
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Layout == "" {
+		t.Layout = d.Layout
+	}
+	if t.Width == 0 {
+		t.Width = d.Width
+	}
+	if t.Height == 0 {
+		t.Height = d.Height
+	}
+	if t.Fonts.Regular == "" {
+		t.Fonts.Regular = d.Fonts.Regular
+	}
+	if t.Fonts.Bold == "" {
+		t.Fonts.Bold = d.Fonts.Bold
+	}
+	fillFloat(&t.Sizes.SiteName, d.Sizes.SiteName)
+	fillFloat(&t.Sizes.Title, d.Sizes.Title)
+	fillFloat(&t.Sizes.Subtitle, d.Sizes.Subtitle)
+	fillFloat(&t.Sizes.Description, d.Sizes.Description)
+	fillFloat(&t.Sizes.URL, d.Sizes.URL)
+	fillString(&t.Colors.Background, d.Colors.Background)
+	fillString(&t.Colors.GradientStart, d.Colors.GradientStart)
+	fillString(&t.Colors.GradientEnd, d.Colors.GradientEnd)
+	fillString(&t.Colors.SiteName, d.Colors.SiteName)
+	fillString(&t.Colors.Title, d.Colors.Title)
+	fillString(&t.Colors.Subtitle, d.Colors.Subtitle)
+	fillString(&t.Colors.Description, d.Colors.Description)
+	fillString(&t.Colors.URL, d.Colors.URL)
+	fillString(&t.Colors.Decoration, d.Colors.Decoration)
+	fillString(&t.Colors.CoverMask, d.Colors.CoverMask)
+	t.Logo.URL = strings.TrimSpace(t.Logo.URL)
+	fillString(&t.Logo.Position, d.Logo.Position)
+	if t.Logo.Size == 0 {
+		t.Logo.Size = d.Logo.Size
+	}
+	fillString(&t.QRCode.Position, d.QRCode.Position)
+	if t.QRCode.Size == 0 {
+		t.QRCode.Size = d.QRCode.Size
+	}
+	fillString(&t.QRCode.Foreground, d.QRCode.Foreground)
+	fillString(&t.QRCode.Background, d.QRCode.Background)
+	return t
+}
+
+// Validate 校验模板
+func (t Template) Validate() error {
+	if t.Name == "" || len([]rune(t.Name)) > 50 {
+		return fmt.Errorf("模板名称不能为空且不超过50个字符")
+	}
+	if !contains(Layouts, t.Layout) {
+		return fmt.Errorf("不支持的布局: %s", t.Layout)
+	}
+	if t.Width < 200 || t.Width > 2000 || t.Height < 200 || t.Height > 2000 {
+		return fmt.Errorf("图片尺寸需在 200-2000 像素之间")
+	}
+	if t.CornerRadius < 0 || t.CornerRadius > float64(min(t.Width, t.Height))/2 {
+		return fmt.Errorf("圆角半径无效")
+	}
+	for _, size := range []float64{t.Sizes.SiteName, t.Sizes.Title, t.Sizes.Subtitle, t.Sizes.Description, t.Sizes.URL} {
+		if size < 8 || size > 200 {
+			return fmt.Errorf("字号需在 8-200 之间")
+		}
+	}
+	for _, c := range []string{t.Colors.Background, t.Colors.GradientStart, t.Colors.GradientEnd, t.Colors.SiteName, t.Colors.Title,
+		t.Colors.Subtitle, t.Colors.Description, t.Colors.URL, t.Colors.Decoration, t.Colors.CoverMask,
+		t.QRCode.Foreground, t.QRCode.Background} {
+		if !hexColorRe.MatchString(c) {
+			return fmt.Errorf("无效的颜色: %s", c)
+		}
+	}
+	if !contains(Positions, t.Logo.Position) || !contains(Positions, t.QRCode.Position) {
+		return fmt.Errorf("无效的角标位置")
+	}
+	limit := min(t.Width, t.Height) / 2
+	if t.Logo.Size < 16 || t.Logo.Size > limit || t.QRCode.Size < 64 || t.QRCode.Size > limit {
+		return fmt.Errorf("Logo 或二维码尺寸无效")
+	}
+	if t.Logo.URL != "" && !strings.HasPrefix(t.Logo.URL, "http://") && !strings.HasPrefix(t.Logo.URL, "https://") && !strings.HasPrefix(t.Logo.URL, "/uploads/") {
+		return fmt.Errorf("Logo 地址需为 http(s) 链接或 /uploads/ 下的文件")
+	}
+	if t.Logo.URL != "" && t.QRCode.Enabled && t.Logo.Position == t.QRCode.Position {
+		return fmt.Errorf("Logo 与二维码不能放在同一位置")
+	}
+	return nil
+}
+
+// Version 模板版本，模板任一字段变化都会改变，用作缓存键的一部分
+func (t Template) Version() string {
+	data, _ := json.Marshal(t)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ParseColor 解析 #RGB / #RRGGBB / #RRGGBBAA 颜色，格式错误时返回不透明黑色
+func ParseColor(s string) color.NRGBA {
+	c := color.NRGBA{A: 255}
+	if !hexColorRe.MatchString(s) {
+		return c
+	}
+	s = s[1:]
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	var b [4]byte
+	n, _ := hex.Decode(b[:], []byte(s))
+	c.R, c.G, c.B = b[0], b[1], b[2]
+	if n == 4 {
+		c.A = b[3]
+	}
+	return c
+}
+
+func fillString(v *string, def string) {
+	if *v = strings.TrimSpace(*v); *v == "" {
+		*v = def
+	}
+}
+
+func fillFloat(v *float64, def float64) {
+	if *v == 0 {
+		*v = def
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
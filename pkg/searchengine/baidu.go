@@ -0,0 +1,121 @@
+package searchengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaiduEndpoint 百度资源平台链接提交接口
+const DefaultBaiduEndpoint = "http://data.zz.baidu.com/urls"
+
+// BaiduSubmitter 百度普通收录 / 快速收录提交器
+type BaiduSubmitter struct {
+	Endpoint string
+	SiteURL  string
+	Token    string
+	Daily    bool // true 为快速收录（type=daily），配额单独计算
+	Client   *http.Client
+}
+
+// NewBaiduSubmitter 创建百度提交器
+func NewBaiduSubmitter(siteURL, token string, daily bool) *BaiduSubmitter {
+	return &BaiduSubmitter{
+		Endpoint: DefaultBaiduEndpoint,
+		SiteURL:  strings.TrimRight(siteURL, "/"),
+		Token:    strings.TrimSpace(token),
+		Daily:    daily,
+		Client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Name 搜索引擎标识
+func (s *BaiduSubmitter) Name() string {
+	if s.Daily {
+		return EngineBaiduDaily
+	}
+	return EngineBaidu
+}
+
+// MaxBatch 百度单次最多 2000 条
+func (s *BaiduSubmitter) MaxBatch() int { return 2000 }
+
+type baiduResponse struct {
+	Success      int      `json:"success"`
+	Remain       int      `json:"remain"`
+	SuccessDaily int      `json:"success_daily"`
+	RemainDaily  int      `json:"remain_daily"`
+	NotSameSite  []string `json:"not_same_site"`
+	NotValid     []string `json:"not_valid"`
+	Error        int      `json:"error"`
+	Message      string   `json:"message"`
+}
+
+// Submit 提交 URL 列表（每行一个）
+func (s *BaiduSubmitter) Submit(ctx context.Context, urls []string) ([]Result, error) {
+	if s.Token == "" {
+		return nil, fmt.Errorf("未配置百度推送 token")
+	}
+	site, err := url.Parse(s.SiteURL)
+	if err != nil || site.Host == "" {
+		return nil, fmt.Errorf("网站地址无效: %s", s.SiteURL)
+	}
+
+	params := url.Values{}
+	// 百度要求 site 与资源平台中验证的站点一致，https 站点需带协议
+	if site.Scheme == "https" {
+		params.Set("site", site.Scheme+"://"+site.Host)
+	} else {
+		params.Set("site", site.Host)
+	}
+	params.Set("token", s.Token)
+	if s.Daily {
+		params.Set("type", "daily")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"?"+params.Encode(), strings.NewReader(strings.Join(urls, "\n")))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	status, body, err := doRequest(s.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp baiduResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析百度响应失败 (状态码 %d): %s", status, bodySnippet(body))
+	}
+	if status != http.StatusOK || resp.Error != 0 {
+		// 配额用尽时百度返回 400 over quota
+		if strings.Contains(strings.ToLower(resp.Message), "over quota") {
+			return nil, &QuotaError{Engine: s.Name(), Message: resp.Message}
+		}
+		return nil, fmt.Errorf("百度返回错误 %d: %s", resp.Error, resp.Message)
+	}
+
+	rejected := make(map[string]string)
+	for _, u := range resp.NotSameSite {
+		rejected[u] = "不是本站 URL"
+	}
+	for _, u := range resp.NotValid {
+		rejected[u] = "URL 不合法"
+	}
+	remain := resp.Remain
+	if s.Daily {
+		remain = resp.RemainDaily
+	}
+	results := make([]Result, len(urls))
+	for i, u := range urls {
+		if msg, ok := rejected[u]; ok {
+			results[i] = Result{URL: u, Message: msg}
+			continue
+		}
+		results[i] = Result{URL: u, Success: true, Message: fmt.Sprintf("提交成功，今日剩余 %d", remain)}
+	}
+	return results, nil
+}
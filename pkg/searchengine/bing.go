@@ -0,0 +1,80 @@
+package searchengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBingEndpoint Bing Webmaster 批量 URL 提交接口
+const DefaultBingEndpoint = "https://ssl.bing.com/webmaster/api.svc/json/SubmitUrlbatch"
+
+// BingSubmitter Bing Webmaster URL 提交器（与 pkg/bing 的站点地图提交共用 API 密钥）
+type BingSubmitter struct {
+	Endpoint string
+	SiteURL  string
+	APIKey   string
+	Client   *http.Client
+}
+
+// NewBingSubmitter 创建 Bing 提交器
+func NewBingSubmitter(siteURL, apiKey string) *BingSubmitter {
+	return &BingSubmitter{
+		Endpoint: DefaultBingEndpoint,
+		SiteURL:  strings.TrimRight(siteURL, "/"),
+		APIKey:   strings.TrimSpace(apiKey),
+		Client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Name 搜索引擎标识
+func (s *BingSubmitter) Name() string { return EngineBing }
+
+// MaxBatch Bing 单次最多 500 个 URL
+func (s *BingSubmitter) MaxBatch() int { return 500 }
+
+// Submit 提交 URL 列表
+func (s *BingSubmitter) Submit(ctx context.Context, urls []string) ([]Result, error) {
+	if s.APIKey == "" {
+		return nil, fmt.Errorf("未配置 Bing Webmaster API 密钥")
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"siteUrl": s.SiteURL,
+		"urlList": urls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint+"?apikey="+url.QueryEscape(s.APIKey), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	status, body, err := doRequest(s.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusOK {
+		return allResults(urls, true, "提交成功"), nil
+	}
+
+	var apiErr struct {
+		ErrorCode int    `json:"ErrorCode"`
+		Message   string `json:"Message"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+	message := apiErr.Message
+	if message == "" {
+		message = bodySnippet(body)
+	}
+	// 配额用尽时返回 "Quota remaining for today: 0"
+	if status == http.StatusTooManyRequests || strings.Contains(strings.ToLower(message), "quota") {
+		return nil, &QuotaError{Engine: EngineBing, Message: message}
+	}
+	return nil, fmt.Errorf("Bing 返回状态码 %d: %s", status, message)
+}
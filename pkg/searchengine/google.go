@@ -0,0 +1,47 @@
+package searchengine
+
+import (
+	"context"
+	"strings"
+)
+
+// URLPublisher Google Indexing API 的单 URL 通知接口，由 pkg/google.Client 实现
+type URLPublisher interface {
+	PublishURL(url string, urlType string) error
+}
+
+// GoogleSubmitter 通过 Indexing API 逐个提交 URL_UPDATED 通知
+type GoogleSubmitter struct {
+	Publisher URLPublisher
+}
+
+// NewGoogleSubmitter 创建 Google 提交器
+func NewGoogleSubmitter(publisher URLPublisher) *GoogleSubmitter {
+	return &GoogleSubmitter{Publisher: publisher}
+}
+
+// Name 搜索引擎标识
+func (s *GoogleSubmitter) Name() string { return EngineGoogle }
+
+// MaxBatch Indexing API 没有批量接口，这里只限制单轮处理数量
+func (s *GoogleSubmitter) MaxBatch() int { return 100 }
+
+// Submit 逐个提交；遇到速率限制时停止并返回已处理部分的结果
+func (s *GoogleSubmitter) Submit(ctx context.Context, urls []string) ([]Result, error) {
+	results := make([]Result, 0, len(urls))
+	for _, u := range urls {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		err := s.Publisher.PublishURL(u, "URL_UPDATED")
+		if err != nil {
+			if strings.Contains(err.Error(), "过于频繁") || strings.Contains(err.Error(), "429") {
+				return results, &QuotaError{Engine: EngineGoogle, Message: err.Error()}
+			}
+			results = append(results, Result{URL: u, Message: err.Error()})
+			continue
+		}
+		results = append(results, Result{URL: u, Success: true, Message: "提交成功"})
+	}
+	return results, nil
+}
@@ -0,0 +1,90 @@
+package searchengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultIndexNowEndpoint IndexNow 公共入口，会同步给 Bing、Yandex、Seznam 等参与方
+const DefaultIndexNowEndpoint = "https://api.indexnow.org/indexnow"
+
+// IndexNowSubmitter IndexNow 协议提交器
+type IndexNowSubmitter struct {
+	Endpoint string
+	SiteURL  string // 站点根地址，用于 host 与 keyLocation
+	Key      string
+	Client   *http.Client
+}
+
+// NewIndexNowSubmitter 创建 IndexNow 提交器，endpoint 为空时使用公共入口
+func NewIndexNowSubmitter(endpoint, siteURL, key string) *IndexNowSubmitter {
+	if strings.TrimSpace(endpoint) == "" {
+		endpoint = DefaultIndexNowEndpoint
+	}
+	return &IndexNowSubmitter{
+		Endpoint: endpoint,
+		SiteURL:  strings.TrimRight(siteURL, "/"),
+		Key:      strings.TrimSpace(key),
+		Client:   &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// IndexNowKeyFile 密钥文件名，需放在站点根目录下才能覆盖全站 URL
+func IndexNowKeyFile(key string) string {
+	return "indexnow-" + key + ".txt"
+}
+
+// Name 搜索引擎标识
+func (s *IndexNowSubmitter) Name() string { return EngineIndexNow }
+
+// MaxBatch IndexNow 单次最多 10000 个 URL
+func (s *IndexNowSubmitter) MaxBatch() int { return 10000 }
+
+// Submit 提交 URL 列表
+func (s *IndexNowSubmitter) Submit(ctx context.Context, urls []string) ([]Result, error) {
+	if s.Key == "" {
+		return nil, fmt.Errorf("未配置 IndexNow 密钥")
+	}
+	site, err := url.Parse(s.SiteURL)
+	if err != nil || site.Host == "" {
+		return nil, fmt.Errorf("网站地址无效: %s", s.SiteURL)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"host":        site.Host,
+		"key":         s.Key,
+		"keyLocation": s.SiteURL + "/" + IndexNowKeyFile(s.Key),
+		"urlList":     urls,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	status, body, err := doRequest(s.Client, req)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK, http.StatusAccepted:
+		// 202 表示已接收但密钥尚在验证中
+		return allResults(urls, true, fmt.Sprintf("已接收 (%d)", status)), nil
+	case http.StatusTooManyRequests:
+		return nil, &QuotaError{Engine: EngineIndexNow, Message: "请求过于频繁"}
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("密钥无效或密钥文件无法访问 (403)")
+	case http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("URL 不属于该站点或与密钥不匹配 (422)")
+	default:
+		return nil, fmt.Errorf("IndexNow 返回状态码 %d: %s", status, bodySnippet(body))
+	}
+}
@@ -0,0 +1,92 @@
+// Package searchengine 将页面 URL 主动推送给搜索引擎（Google Indexing API、Bing Webmaster、IndexNow、百度普通/快速收录）。
+package searchengine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 搜索引擎标识
+const (
+	EngineGoogle     = "google"
+	EngineBing       = "bing"
+	EngineIndexNow   = "indexnow"
+	EngineBaidu      = "baidu"       // 百度普通收录
+	EngineBaiduDaily = "baidu_daily" // 百度快速收录
+)
+
+// Engines 所有支持的搜索引擎，按提交顺序排列
+var Engines = []string{EngineGoogle, EngineBing, EngineIndexNow, EngineBaidu, EngineBaiduDaily}
+
+const (
+	defaultTimeout = 30 * time.Second
+	maxBodySize    = 1 << 20
+)
+
+// Result 单个 URL 的提交结果
+type Result struct {
+	URL     string `json:"url"`
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// Submitter 搜索引擎 URL 提交器
+type Submitter interface {
+	// Name 搜索引擎标识
+	Name() string
+	// MaxBatch 单次请求最多提交的 URL 数量
+	MaxBatch() int
+	// Submit 提交一批 URL，返回各 URL 的结果；整个请求失败时返回 error。
+	// 中途遇到配额限制时可同时返回已处理部分的结果和 *QuotaError
+	Submit(ctx context.Context, urls []string) ([]Result, error)
+}
+
+// QuotaError 搜索引擎返回配额耗尽，当天不应再提交
+type QuotaError struct {
+	Engine  string
+	Message string
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s 配额已用尽: %s", e.Engine, e.Message)
+}
+
+// allResults 为整批 URL 生成相同的结果
+func allResults(urls []string, success bool, message string) []Result {
+	results := make([]Result, len(urls))
+	for i, u := range urls {
+		results[i] = Result{URL: u, Success: success, Message: message}
+	}
+	return results
+}
+
+// doRequest 发送请求并读取响应体（限制大小）
+func doRequest(client *http.Client, req *http.Request) (int, []byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+// bodySnippet 截取响应体用于错误信息
+func bodySnippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if r := []rune(s); len(r) > 200 {
+		return string(r[:200]) + "..."
+	}
+	return s
+}
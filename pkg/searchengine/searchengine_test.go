@@ -0,0 +1,150 @@
+package searchengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIndexNowSubmitter(t *testing.T) {
+	var got map[string]interface{}
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := NewIndexNowSubmitter(srv.URL, "https://pan.example.com/", "abcdef123456")
+	results, err := s.Submit(context.Background(), []string{"https://pan.example.com/r/a", "https://pan.example.com/r/b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || !results[0].Success || !results[1].Success {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got["host"] != "pan.example.com" || got["key"] != "abcdef123456" ||
+		got["keyLocation"] != "https://pan.example.com/indexnow-abcdef123456.txt" {
+		t.Errorf("unexpected payload: %v", got)
+	}
+
+	status = http.StatusTooManyRequests
+	_, err = s.Submit(context.Background(), []string{"https://pan.example.com/r/a"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Errorf("expected QuotaError, got %v", err)
+	}
+
+	status = http.StatusUnprocessableEntity
+	if _, err = s.Submit(context.Background(), []string{"https://other.example.com/r/a"}); err == nil || errors.As(err, &quotaErr) {
+		t.Errorf("expected plain error, got %v", err)
+	}
+}
+
+func TestBaiduSubmitter(t *testing.T) {
+	var query, body string
+	response := `{"success":1,"remain":99,"not_valid":["https://pan.example.com/bad"]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		if strings.Contains(response, `"error"`) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	s := NewBaiduSubmitter("https://pan.example.com", "tok", true)
+	s.Endpoint = srv.URL
+	if s.Name() != EngineBaiduDaily {
+		t.Errorf("name = %s", s.Name())
+	}
+	results, err := s.Submit(context.Background(), []string{"https://pan.example.com/r/a", "https://pan.example.com/bad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(query, "type=daily") || !strings.Contains(query, "site=https%3A%2F%2Fpan.example.com") {
+		t.Errorf("query = %s", query)
+	}
+	if body != "https://pan.example.com/r/a\nhttps://pan.example.com/bad" {
+		t.Errorf("body = %q", body)
+	}
+	if !results[0].Success || results[1].Success || results[1].Message != "URL 不合法" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	response = `{"error":400,"message":"over quota"}`
+	_, err = s.Submit(context.Background(), []string{"https://pan.example.com/r/a"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Errorf("expected QuotaError, got %v", err)
+	}
+}
+
+func TestBingSubmitter(t *testing.T) {
+	var apiKey string
+	var payload struct {
+		SiteURL string   `json:"siteUrl"`
+		URLList []string `json:"urlList"`
+	}
+	status, response := http.StatusOK, `{"d":null}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.URL.Query().Get("apikey")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	s := NewBingSubmitter("https://pan.example.com/", "k1")
+	s.Endpoint = srv.URL
+	results, err := s.Submit(context.Background(), []string{"https://pan.example.com/r/a"})
+	if err != nil || len(results) != 1 || !results[0].Success {
+		t.Fatalf("results = %+v, err = %v", results, err)
+	}
+	if apiKey != "k1" || payload.SiteURL != "https://pan.example.com" || len(payload.URLList) != 1 {
+		t.Errorf("unexpected request: key=%s payload=%+v", apiKey, payload)
+	}
+
+	status, response = http.StatusBadRequest, `{"ErrorCode":2,"Message":"ERROR!!! Quota remaining for today: 0"}`
+	_, err = s.Submit(context.Background(), []string{"https://pan.example.com/r/a"})
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Errorf("expected QuotaError, got %v", err)
+	}
+}
+
+type fakePublisher struct {
+	errs map[string]error
+	sent []string
+}
+
+func (f *fakePublisher) PublishURL(url, urlType string) error {
+	f.sent = append(f.sent, url)
+	return f.errs[url]
+}
+
+func TestGoogleSubmitterStopsOnRateLimit(t *testing.T) {
+	p := &fakePublisher{errs: map[string]error{
+		"https://a/2": errors.New("URL格式错误或无法访问"),
+		"https://a/3": errors.New("URL索引提交请求过于频繁，Indexing API有严格的速率限制"),
+	}}
+	results, err := NewGoogleSubmitter(p).Submit(context.Background(), []string{"https://a/1", "https://a/2", "https://a/3", "https://a/4"})
+
+	var quotaErr *QuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected QuotaError, got %v", err)
+	}
+	if len(results) != 2 || !results[0].Success || results[1].Success {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(p.sent) != 3 {
+		t.Errorf("sent = %v, want stop after rate limit", p.sent)
+	}
+}
@@ -0,0 +1,397 @@
+// Package seo 生成资源详情页的 SEO 数据：canonical、meta 标题/描述、JSON-LD 与 hreflang/noindex 决策
+package seo
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 页面状态
+const (
+	StatusOK      = "ok"      // 正常，可收录
+	StatusInvalid = "invalid" // 资源已失效
+	StatusPrivate = "private" // 资源未公开
+)
+
+// 模板中可用的占位符
+const (
+	PlaceholderTitle       = "{title}"
+	PlaceholderSiteTitle   = "{site_title}"
+	PlaceholderCategory    = "{category}"
+	PlaceholderTags        = "{tags}"
+	PlaceholderPan         = "{pan}"
+	PlaceholderYear        = "{year}"
+	PlaceholderSummary     = "{summary}"
+	PlaceholderDescription = "{description}"
+)
+
+// Placeholders 模板支持的全部占位符，供配置页展示
+var Placeholders = []string{
+	PlaceholderTitle, PlaceholderSiteTitle, PlaceholderCategory, PlaceholderTags,
+	PlaceholderPan, PlaceholderYear, PlaceholderSummary, PlaceholderDescription,
+}
+
+// 标题与描述的最大长度（字符）
+const (
+	MaxTitleLength       = 70
+	MaxDescriptionLength = 160
+)
+
+// Site 站点信息
+type Site struct {
+	URL         string   // 网站地址，如 https://pan.example.com
+	Title       string   // 站点标题
+	Description string   // 站点描述，资源没有描述时使用
+	Languages   []string // hreflang 语言列表，如 zh-CN
+}
+
+// Media 影视信息，来自影视元数据或热播剧
+type Media struct {
+	Type        string // movie / tv
+	Title       string
+	Year        int
+	Genres      []string
+	Regions     []string
+	Directors   []string
+	Actors      []string
+	Episodes    int
+	Rating      float64
+	RatingCount int
+	Poster      string
+	Summary     string // 一行摘要，如 "2023 · 中国大陆 · 剧情 · 豆瓣 8.3"
+	Plot        string // 剧情简介，资源没有描述时使用
+	SameAs      string // 豆瓣/TMDB 条目地址
+}
+
+// Resource 同一 Key 下资源的汇总信息
+type Resource struct {
+	Key         string
+	Title       string
+	Description string
+	Cover       string
+	Category    string
+	Tags        []string
+	Pans        []string
+	Valid       bool // 至少有一个有效资源
+	Public      bool // 至少有一个公开资源
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Media       *Media
+}
+
+// Templates meta 标题与描述模板
+type Templates struct {
+	Title       string
+	Description string
+}
+
+// Alternate hreflang 备用链接
+type Alternate struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
+
+// Payload 前端渲染资源页所需的 SEO 数据
+type Payload struct {
+	Key         string                   `json:"key"`
+	Status      string                   `json:"status"`
+	Canonical   string                   `json:"canonical"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Keywords    []string                 `json:"keywords"`
+	Robots      string                   `json:"robots"`
+	NoIndex     bool                     `json:"noindex"`
+	Alternates  []Alternate              `json:"alternates"`
+	OGType      string                   `json:"og_type"`
+	OGImage     string                   `json:"og_image"`
+	Image       string                   `json:"image,omitempty"`
+	JSONLD      []map[string]interface{} `json:"json_ld"`
+}
+
+var placeholderRe = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// Render 替换模板中的占位符；未知占位符替换为空，并清理替换后多余的分隔符
+func Render(tpl string, vars map[string]string) string {
+	out := placeholderRe.ReplaceAllStringFunc(tpl, func(p string) string {
+		return vars[p]
+	})
+	return tidy(out)
+}
+
+var (
+	repeatedSepRe = regexp.MustCompile(`(\s*[-|_·,，]\s*){2,}`)
+	spaceRe       = regexp.MustCompile(`\s+`)
+)
+
+// tidy 合并连续的分隔符与空白，并去掉首尾的分隔符
+func tidy(s string) string {
+	s = spaceRe.ReplaceAllString(s, " ")
+	s = repeatedSepRe.ReplaceAllStringFunc(s, func(m string) string {
+		for _, r := range m {
+			if !strings.ContainsRune(" \t", r) {
+				if r == '-' || r == '|' || r == '_' {
+					return " " + string(r) + " "
+				}
+				return string(r)
+			}
+		}
+		return " "
+	})
+	return strings.Trim(s, " -|_·,，")
+}
+
+// Build 生成 SEO 数据；失效或未公开的资源标记为 noindex，且不输出 hreflang 与 JSON-LD 作品信息
+func Build(site Site, res Resource, tpl Templates) Payload {
+	siteURL := strings.TrimRight(site.URL, "/")
+	canonical := siteURL + "/r/" + res.Key
+	vars := templateVars(site, res)
+
+	payload := Payload{
+		Key:         res.Key,
+		Status:      StatusOK,
+		Canonical:   canonical,
+		Title:       truncate(Render(tpl.Title, vars), MaxTitleLength),
+		Description: truncate(Render(tpl.Description, vars), MaxDescriptionLength),
+		Keywords:    keywords(res),
+		Robots:      "index, follow, max-image-preview:large",
+		OGType:      "website",
+		OGImage:     siteURL + "/api/og-image?key=" + res.Key,
+		Image:       image(siteURL, res),
+	}
+	if payload.Title == "" {
+		payload.Title = truncate(res.Title, MaxTitleLength)
+	}
+	if payload.Description == "" {
+		payload.Description = truncate(site.Description, MaxDescriptionLength)
+	}
+
+	switch {
+	case !res.Valid:
+		payload.Status = StatusInvalid
+	case !res.Public:
+		payload.Status = StatusPrivate
+	}
+	if payload.Status != StatusOK {
+		payload.NoIndex = true
+		payload.Robots = "noindex, follow"
+		payload.JSONLD = []map[string]interface{}{breadcrumb(siteURL, site.Title, canonical, res)}
+		return payload
+	}
+
+	for _, lang := range site.Languages {
+		payload.Alternates = append(payload.Alternates, Alternate{Hreflang: lang, Href: canonical})
+	}
+	if len(payload.Alternates) > 0 {
+		payload.Alternates = append(payload.Alternates, Alternate{Hreflang: "x-default", Href: canonical})
+	}
+
+	work := creativeWork(site, res, canonical, payload.Description, payload.Image)
+	if t, _ := work["@type"].(string); t == "Movie" {
+		payload.OGType = "video.movie"
+	} else if t == "TVSeries" {
+		payload.OGType = "video.tv_show"
+	}
+	payload.JSONLD = []map[string]interface{}{work, breadcrumb(siteURL, site.Title, canonical, res)}
+	return payload
+}
+
+func templateVars(site Site, res Resource) map[string]string {
+	vars := map[string]string{
+		PlaceholderTitle:       res.Title,
+		PlaceholderSiteTitle:   site.Title,
+		PlaceholderCategory:    res.Category,
+		PlaceholderTags:        strings.Join(res.Tags, ","),
+		PlaceholderPan:         strings.Join(res.Pans, ","),
+		PlaceholderDescription: plainText(res.Description),
+	}
+	if res.Media != nil {
+		if res.Media.Year > 0 {
+			vars[PlaceholderYear] = strconv.Itoa(res.Media.Year)
+		}
+		vars[PlaceholderSummary] = res.Media.Summary
+		if vars[PlaceholderDescription] == "" {
+			vars[PlaceholderDescription] = plainText(res.Media.Plot)
+		}
+	}
+	return vars
+}
+
+// creativeWork 作品的 JSON-LD：匹配到电影/剧集时使用 Movie/TVSeries，否则为 CreativeWork
+func creativeWork(site Site, res Resource, canonical, description, img string) map[string]interface{} {
+	work := map[string]interface{}{
+		"@context":    "https://schema.org",
+		"@type":       "CreativeWork",
+		"@id":         canonical + "#work",
+		"name":        res.Title,
+		"url":         canonical,
+		"description": description,
+	}
+	if len(site.Languages) > 0 {
+		work["inLanguage"] = site.Languages[0]
+	}
+	if img != "" {
+		work["image"] = img
+	}
+	if !res.CreatedAt.IsZero() {
+		work["datePublished"] = res.CreatedAt.Format(time.RFC3339)
+	}
+	if !res.UpdatedAt.IsZero() {
+		work["dateModified"] = res.UpdatedAt.Format(time.RFC3339)
+	}
+	if res.Category != "" {
+		work["genre"] = res.Category
+	}
+	if len(res.Tags) > 0 {
+		work["keywords"] = strings.Join(res.Tags, ",")
+	}
+	if site.Title != "" {
+		work["publisher"] = map[string]interface{}{"@type": "Organization", "name": site.Title, "url": strings.TrimRight(site.URL, "/") + "/"}
+	}
+
+	m := res.Media
+	if m == nil {
+		return work
+	}
+	switch m.Type {
+	case "movie":
+		work["@type"] = "Movie"
+	case "tv":
+		work["@type"] = "TVSeries"
+		if m.Episodes > 0 {
+			work["numberOfEpisodes"] = m.Episodes
+		}
+	}
+	if m.Title != "" && m.Title != res.Title {
+		work["alternateName"] = m.Title
+	}
+	if len(m.Genres) > 0 {
+		work["genre"] = m.Genres
+	}
+	if m.Year > 0 {
+		work["dateCreated"] = strconv.Itoa(m.Year)
+	}
+	if len(m.Regions) > 0 {
+		countries := make([]map[string]interface{}, 0, len(m.Regions))
+		for _, r := range m.Regions {
+			countries = append(countries, map[string]interface{}{"@type": "Country", "name": r})
+		}
+		work["countryOfOrigin"] = countries
+	}
+	if people := persons(m.Directors, 5); len(people) > 0 {
+		work["director"] = people
+	}
+	if people := persons(m.Actors, 10); len(people) > 0 {
+		work["actor"] = people
+	}
+	if m.Rating > 0 && m.RatingCount > 0 {
+		work["aggregateRating"] = map[string]interface{}{
+			"@type":       "AggregateRating",
+			"ratingValue": m.Rating,
+			"ratingCount": m.RatingCount,
+			"bestRating":  10,
+			"worstRating": 0,
+		}
+	}
+	if m.SameAs != "" {
+		work["sameAs"] = m.SameAs
+	}
+	return work
+}
+
+// breadcrumb 首页 > 分类 > 资源
+func breadcrumb(siteURL, siteTitle, canonical string, res Resource) map[string]interface{} {
+	home := siteTitle
+	if home == "" {
+		home = "首页"
+	}
+	items := []map[string]interface{}{
+		{"@type": "ListItem", "position": 1, "name": home, "item": siteURL + "/"},
+	}
+	if res.Category != "" {
+		items = append(items, map[string]interface{}{
+			"@type": "ListItem", "position": 2, "name": res.Category, "item": siteURL + "/?search=" + url.QueryEscape(res.Category),
+		})
+	}
+	items = append(items, map[string]interface{}{
+		"@type": "ListItem", "position": len(items) + 1, "name": res.Title, "item": canonical,
+	})
+	return map[string]interface{}{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+}
+
+func persons(names []string, max int) []map[string]interface{} {
+	var people []map[string]interface{}
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+		people = append(people, map[string]interface{}{"@type": "Person", "name": n})
+		if len(people) >= max {
+			break
+		}
+	}
+	return people
+}
+
+// keywords 标题、分类、标签与影视类型去重后的关键词
+func keywords(res Resource) []string {
+	var list []string
+	seen := make(map[string]bool)
+	add := func(values ...string) {
+		for _, v := range values {
+			v = strings.TrimSpace(v)
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	add(res.Title, res.Category)
+	add(res.Tags...)
+	if res.Media != nil {
+		add(res.Media.Genres...)
+	}
+	return list
+}
+
+// image 资源封面，没有时使用影视海报；相对路径补全为绝对地址
+func image(siteURL string, res Resource) string {
+	img := res.Cover
+	if img == "" && res.Media != nil {
+		img = res.Media.Poster
+	}
+	if img == "" {
+		return ""
+	}
+	if strings.HasPrefix(img, "http://") || strings.HasPrefix(img, "https://") {
+		return img
+	}
+	if strings.HasPrefix(img, "//") {
+		return "https:" + img
+	}
+	return siteURL + "/" + strings.TrimLeft(img, "/")
+}
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// plainText 去掉 HTML 标签并压缩空白
+func plainText(s string) string {
+	return strings.TrimSpace(spaceRe.ReplaceAllString(htmlTagRe.ReplaceAllString(s, " "), " "))
+}
+
+// truncate 按字符截断，超长时以省略号结尾
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
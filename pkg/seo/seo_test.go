@@ -0,0 +1,132 @@
+package seo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSite = Site{
+	URL:         "https://pan.example.com/",
+	Title:       "老九网盘",
+	Description: "网盘资源搜索",
+	Languages:   []string{"zh-CN"},
+}
+
+var testTemplates = Templates{
+	Title:       "{title} - {category} - {site_title}",
+	Description: "{title}，{summary}，{description}",
+}
+
+func TestRenderCollapsesEmptyPlaceholders(t *testing.T) {
+	cases := []struct {
+		tpl  string
+		vars map[string]string
+		want string
+	}{
+		{"{title} - {category} - {site_title}", map[string]string{"{title}": "三体", "{site_title}": "老九"}, "三体 - 老九"},
+		{"{title}，{summary}，{description}", map[string]string{"{title}": "三体", "{description}": "科幻剧"}, "三体，科幻剧"},
+		{"{unknown}{title} | {site_title}", map[string]string{"{title}": "三体"}, "三体"},
+	}
+	for _, c := range cases {
+		if got := Render(c.tpl, c.vars); got != c.want {
+			t.Errorf("Render(%q) = %q, want %q", c.tpl, got, c.want)
+		}
+	}
+}
+
+func TestBuildTVSeries(t *testing.T) {
+	created := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	res := Resource{
+		Key:         "abc",
+		Title:       "三体 全30集",
+		Description: "<p>根据刘慈欣同名小说改编</p>",
+		Cover:       "/uploads/cover.jpg",
+		Category:    "电视剧",
+		Tags:        []string{"科幻", "国产"},
+		Pans:        []string{"夸克网盘"},
+		Valid:       true,
+		Public:      true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+		Media: &Media{
+			Type:        "tv",
+			Title:       "三体",
+			Year:        2023,
+			Genres:      []string{"科幻", "剧情"},
+			Directors:   []string{"杨磊"},
+			Actors:      []string{"张鲁一", "于和伟"},
+			Episodes:    30,
+			Rating:      8.7,
+			RatingCount: 100000,
+			Summary:     "2023 · 中国大陆 · 科幻 · 豆瓣 8.7",
+			SameAs:      "https://movie.douban.com/subject/1/",
+		},
+	}
+
+	p := Build(testSite, res, testTemplates)
+	if p.Canonical != "https://pan.example.com/r/abc" || p.NoIndex || p.Status != StatusOK {
+		t.Fatalf("payload = %+v", p)
+	}
+	if p.Title != "三体 全30集 - 电视剧 - 老九网盘" {
+		t.Errorf("title = %q", p.Title)
+	}
+	if p.Description != "三体 全30集，2023 · 中国大陆 · 科幻 · 豆瓣 8.7，根据刘慈欣同名小说改编" {
+		t.Errorf("description = %q", p.Description)
+	}
+	if p.Image != "https://pan.example.com/uploads/cover.jpg" || p.OGImage != "https://pan.example.com/api/og-image?key=abc" {
+		t.Errorf("image = %q og = %q", p.Image, p.OGImage)
+	}
+	if len(p.Alternates) != 2 || p.Alternates[1].Hreflang != "x-default" {
+		t.Errorf("alternates = %+v", p.Alternates)
+	}
+	if p.OGType != "video.tv_show" || len(p.JSONLD) != 2 {
+		t.Fatalf("og_type = %q json_ld = %d", p.OGType, len(p.JSONLD))
+	}
+
+	work := p.JSONLD[0]
+	if work["@type"] != "TVSeries" || work["numberOfEpisodes"] != 30 || work["sameAs"] != "https://movie.douban.com/subject/1/" {
+		t.Errorf("work = %+v", work)
+	}
+	if _, ok := work["aggregateRating"]; !ok {
+		t.Error("aggregateRating missing")
+	}
+	data, err := json.Marshal(p.JSONLD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"BreadcrumbList"`) || !strings.Contains(string(data), `"item":"https://pan.example.com/?search=%E7%94%B5%E8%A7%86%E5%89%A7"`) {
+		t.Errorf("json-ld = %s", data)
+	}
+}
+
+func TestBuildInvalidResourceIsNoIndex(t *testing.T) {
+	p := Build(testSite, Resource{Key: "gone", Title: "旧资源", Valid: false, Public: true}, testTemplates)
+	if !p.NoIndex || p.Status != StatusInvalid || p.Robots != "noindex, follow" {
+		t.Errorf("payload = %+v", p)
+	}
+	if len(p.Alternates) != 0 || len(p.JSONLD) != 1 || p.JSONLD[0]["@type"] != "BreadcrumbList" {
+		t.Errorf("invalid page should only carry breadcrumb: %+v", p)
+	}
+	// 没有描述时回退到站点描述
+	if p.Description != "旧资源" {
+		t.Errorf("description = %q", p.Description)
+	}
+
+	p = Build(testSite, Resource{Key: "hidden", Title: "私有", Valid: true}, Templates{})
+	if !p.NoIndex || p.Status != StatusPrivate || p.Title != "私有" || p.Description != "网盘资源搜索" {
+		t.Errorf("private payload = %+v", p)
+	}
+}
+
+func TestBuildTruncatesLongDescription(t *testing.T) {
+	res := Resource{Key: "k", Title: "标题", Description: strings.Repeat("很长的描述", 60), Valid: true, Public: true}
+	p := Build(testSite, res, Templates{Description: "{description}"})
+	if n := len([]rune(p.Description)); n != MaxDescriptionLength || !strings.HasSuffix(p.Description, "…") {
+		t.Errorf("description length = %d", n)
+	}
+	if p.JSONLD[0]["@type"] != "CreativeWork" {
+		t.Errorf("type = %v", p.JSONLD[0]["@type"])
+	}
+}
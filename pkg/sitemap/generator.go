@@ -0,0 +1,377 @@
+package sitemap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 专题 sitemap 文件名
+const (
+	CategoriesFile = "sitemap-categories.xml.gz"
+	TagsFile       = "sitemap-tags.xml.gz"
+	HotDramasFile  = "sitemap-hot-dramas.xml.gz"
+
+	resourceFilePrefix = "sitemap-resources-"
+	resourceFileSuffix = ".xml.gz"
+
+	// defaultFullInterval 距上次全量生成超过该时间时重新全量生成
+	defaultFullInterval = 24 * time.Hour
+	// changeSkew 增量起点向前回退的时间，覆盖生成期间提交的事务与时钟误差
+	changeSkew = time.Minute
+)
+
+var (
+	resourceFileRe = regexp.MustCompile(`^sitemap-resources-(\d+)\.xml\.gz$`)
+	legacyFileRe   = regexp.MustCompile(`^sitemap-\d+\.xml$`)
+)
+
+// Resource 资源详情页数据
+type Resource struct {
+	Key       string
+	Cover     string
+	UpdatedAt time.Time
+}
+
+// Page 专题页数据（分类、标签、热播剧），Path 为站内路径
+type Page struct {
+	Path      string
+	Image     string
+	UpdatedAt time.Time
+}
+
+// Source sitemap 数据源。资源按 ID 区间分页：第 n 页包含 ID 在 (n*size, (n+1)*size] 内的资源，
+// 分页边界不随资源的增删变化，因此只需重写有变更的分页。
+type Source interface {
+	// MaxResourceID 资源（含已删除）的最大 ID
+	MaxResourceID(ctx context.Context) (uint, error)
+	// ChangedResourcePages since 之后有新增、更新或删除的资源所在的分页
+	ChangedResourcePages(ctx context.Context, since time.Time, pageSize int) ([]int, error)
+	// Resources ID 在 [minID, maxID] 内的有效、公开、未删除的资源
+	Resources(ctx context.Context, minID, maxID uint) ([]Resource, error)
+	Categories(ctx context.Context) ([]Page, error)
+	Tags(ctx context.Context) ([]Page, error)
+	HotDramas(ctx context.Context) ([]Page, error)
+}
+
+// Generator sitemap 生成器，分页文件以 gzip 压缩，索引文件为 sitemap.xml
+type Generator struct {
+	Dir          string
+	BaseURL      string
+	Source       Source
+	PageSize     int
+	FullInterval time.Duration
+	Now          func() time.Time
+}
+
+// Result 一次生成的结果
+type Result struct {
+	Full          bool     `json:"full"`
+	ResourcePages int      `json:"resource_pages"`
+	Regenerated   []string `json:"regenerated"`
+	Removed       []string `json:"removed"`
+	URLs          int      `json:"urls"` // 本次重写的文件包含的 URL 数
+}
+
+// NewGenerator 创建 sitemap 生成器
+func NewGenerator(dir, baseURL string, source Source) *Generator {
+	return &Generator{
+		Dir:          dir,
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		Source:       source,
+		PageSize:     MaxURLsPerFile,
+		FullInterval: defaultFullInterval,
+		Now:          time.Now,
+	}
+}
+
+// ResourceFile 第 page 个资源分页的文件名
+func ResourceFile(page int) string {
+	return resourceFilePrefix + strconv.Itoa(page) + resourceFileSuffix
+}
+
+// Generate 生成 sitemap。首次生成、站点地址变化、超过全量间隔或 full 为 true 时全量生成，
+// 否则只重写有变更或缺失的资源分页；专题 sitemap 数据量小，每次都重新生成。
+func (g *Generator) Generate(ctx context.Context, full bool) (*Result, error) {
+	if g.PageSize <= 0 || g.PageSize > MaxURLsPerFile {
+		g.PageSize = MaxURLsPerFile
+	}
+	if err := os.MkdirAll(g.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建sitemap目录失败: %w", err)
+	}
+
+	started := g.Now()
+	manifest := LoadManifest(g.Dir)
+	if manifest == nil || manifest.BaseURL != g.BaseURL ||
+		(g.FullInterval > 0 && started.Sub(manifest.FullAt) >= g.FullInterval) {
+		full = true
+	}
+	var previous map[string]FileState
+	if full {
+		if manifest != nil {
+			previous = manifest.Files
+		}
+		manifest = &Manifest{Files: map[string]FileState{}}
+	}
+	result := &Result{Full: full}
+
+	maxID, err := g.Source.MaxResourceID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取资源最大ID失败: %w", err)
+	}
+	totalPages := 0
+	if maxID > 0 {
+		totalPages = int((maxID-1)/uint(g.PageSize)) + 1
+	}
+	result.ResourcePages = totalPages
+
+	pages, err := g.pagesToRegenerate(ctx, manifest, full, totalPages)
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := g.writeResourcePage(ctx, manifest, page, result); err != nil {
+			return result, fmt.Errorf("生成第 %d 个资源sitemap失败: %w", page, err)
+		}
+	}
+
+	// 清理超出范围的分页
+	for name := range manifest.Files {
+		if m := resourceFileRe.FindStringSubmatch(name); m != nil {
+			if page, _ := strconv.Atoi(m[1]); page >= totalPages {
+				g.removeFile(manifest, name, result)
+			}
+		}
+	}
+
+	specials := []struct {
+		name  string
+		fetch func(context.Context) ([]Page, error)
+	}{
+		{CategoriesFile, g.Source.Categories},
+		{TagsFile, g.Source.Tags},
+		{HotDramasFile, g.Source.HotDramas},
+	}
+	for _, sp := range specials {
+		list, err := sp.fetch(ctx)
+		if err != nil {
+			return result, fmt.Errorf("获取 %s 数据失败: %w", sp.name, err)
+		}
+		if err := g.writePages(manifest, sp.name, list, result); err != nil {
+			return result, err
+		}
+	}
+
+	if full {
+		// 全量生成后不再需要的文件
+		for name := range previous {
+			if _, ok := manifest.Files[name]; !ok {
+				g.removeFile(manifest, name, result)
+			}
+		}
+		g.removeLegacyFiles()
+	}
+	if err := WriteIndex(filepath.Join(g.Dir, IndexFile), g.indexEntries(manifest, totalPages)); err != nil {
+		return result, fmt.Errorf("生成sitemap索引失败: %w", err)
+	}
+
+	manifest.BaseURL = g.BaseURL
+	manifest.GeneratedAt = started
+	if full {
+		manifest.FullAt = started
+	}
+	return result, manifest.Save(g.Dir)
+}
+
+// pagesToRegenerate 需要重写的资源分页：全量时为全部分页，否则为有变更的分页和文件缺失的分页
+func (g *Generator) pagesToRegenerate(ctx context.Context, manifest *Manifest, full bool, totalPages int) ([]int, error) {
+	if full {
+		pages := make([]int, totalPages)
+		for i := range pages {
+			pages[i] = i
+		}
+		return pages, nil
+	}
+
+	changed, err := g.Source.ChangedResourcePages(ctx, manifest.GeneratedAt.Add(-changeSkew), g.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("获取变更资源失败: %w", err)
+	}
+	set := make(map[int]bool, len(changed))
+	for _, p := range changed {
+		if p >= 0 && p < totalPages {
+			set[p] = true
+		}
+	}
+	for name := range manifest.Files {
+		if _, err := os.Stat(filepath.Join(g.Dir, name)); err == nil {
+			continue
+		}
+		if m := resourceFileRe.FindStringSubmatch(name); m != nil {
+			if page, _ := strconv.Atoi(m[1]); page < totalPages {
+				set[page] = true
+			}
+		}
+	}
+
+	pages := make([]int, 0, len(set))
+	for p := range set {
+		pages = append(pages, p)
+	}
+	sort.Ints(pages)
+	return pages, nil
+}
+
+// writeResourcePage 重写单个资源分页，分页内没有可收录的资源时删除文件
+func (g *Generator) writeResourcePage(ctx context.Context, manifest *Manifest, page int, result *Result) error {
+	minID := uint(page*g.PageSize) + 1
+	maxID := uint((page + 1) * g.PageSize)
+	resources, err := g.Source.Resources(ctx, minID, maxID)
+	if err != nil {
+		return err
+	}
+
+	// 同一 Key 的资源共用一个详情页，取最近的更新时间
+	byKey := make(map[string]int, len(resources))
+	var urls []URL
+	var updated []time.Time
+	for _, r := range resources {
+		if r.Key == "" {
+			continue
+		}
+		if i, ok := byKey[r.Key]; ok {
+			if r.UpdatedAt.After(updated[i]) {
+				updated[i] = r.UpdatedAt
+				urls[i].LastMod = FormatDate(r.UpdatedAt)
+			}
+			if len(urls[i].Images) == 0 {
+				urls[i].Images = g.images(r.Cover)
+			}
+			continue
+		}
+		byKey[r.Key] = len(urls)
+		updated = append(updated, r.UpdatedAt)
+		urls = append(urls, URL{
+			Loc:        g.BaseURL + "/r/" + r.Key,
+			LastMod:    FormatDate(r.UpdatedAt),
+			ChangeFreq: "weekly",
+			Priority:   0.8,
+			Images:     g.images(r.Cover),
+		})
+	}
+
+	name := ResourceFile(page)
+	if len(urls) == 0 {
+		g.removeFile(manifest, name, result)
+		return nil
+	}
+	if err := WriteURLSet(filepath.Join(g.Dir, name), urls); err != nil {
+		return err
+	}
+	manifest.Files[name] = FileState{URLs: len(urls), LastMod: latest(updated)}
+	result.Regenerated = append(result.Regenerated, name)
+	result.URLs += len(urls)
+	return nil
+}
+
+// writePages 重写专题 sitemap
+func (g *Generator) writePages(manifest *Manifest, name string, list []Page, result *Result) error {
+	if len(list) > MaxURLsPerFile {
+		list = list[:MaxURLsPerFile]
+	}
+	urls := make([]URL, 0, len(list))
+	updated := make([]time.Time, 0, len(list))
+	for _, p := range list {
+		urls = append(urls, URL{
+			Loc:        g.BaseURL + p.Path,
+			LastMod:    FormatDate(p.UpdatedAt),
+			ChangeFreq: "daily",
+			Priority:   0.6,
+			Images:     g.images(p.Image),
+		})
+		updated = append(updated, p.UpdatedAt)
+	}
+	if len(urls) == 0 {
+		g.removeFile(manifest, name, result)
+		return nil
+	}
+	if err := WriteURLSet(filepath.Join(g.Dir, name), urls); err != nil {
+		return fmt.Errorf("生成 %s 失败: %w", name, err)
+	}
+	manifest.Files[name] = FileState{URLs: len(urls), LastMod: latest(updated)}
+	result.Regenerated = append(result.Regenerated, name)
+	result.URLs += len(urls)
+	return nil
+}
+
+// images 封面地址转为图片条目：站内相对路径补全为绝对地址，其他非 http(s) 地址忽略
+func (g *Generator) images(src string) []Image {
+	src = strings.TrimSpace(src)
+	switch {
+	case src == "":
+		return nil
+	case strings.HasPrefix(src, "http://"), strings.HasPrefix(src, "https://"):
+		return []Image{{Loc: src}}
+	case strings.HasPrefix(src, "/") && !strings.HasPrefix(src, "//"):
+		return []Image{{Loc: g.BaseURL + src}}
+	default:
+		return nil
+	}
+}
+
+func (g *Generator) removeFile(manifest *Manifest, name string, result *Result) {
+	_, tracked := manifest.Files[name]
+	delete(manifest.Files, name)
+	if err := os.Remove(filepath.Join(g.Dir, name)); err == nil || tracked {
+		result.Removed = append(result.Removed, name)
+	}
+}
+
+// removeLegacyFiles 删除旧版生成的未压缩分页（sitemap-N.xml）
+func (g *Generator) removeLegacyFiles() {
+	entries, err := os.ReadDir(g.Dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !e.IsDir() && legacyFileRe.MatchString(e.Name()) {
+			os.Remove(filepath.Join(g.Dir, e.Name()))
+		}
+	}
+}
+
+// indexEntries 索引条目：资源分页按页码排序，随后是专题 sitemap
+func (g *Generator) indexEntries(manifest *Manifest, totalPages int) []IndexEntry {
+	var entries []IndexEntry
+	add := func(name string) {
+		if state, ok := manifest.Files[name]; ok {
+			entries = append(entries, IndexEntry{Loc: g.BaseURL + "/" + name, LastMod: FormatDate(state.LastMod)})
+		}
+	}
+	for page := 0; page < totalPages; page++ {
+		add(ResourceFile(page))
+	}
+	add(CategoriesFile)
+	add(TagsFile)
+	add(HotDramasFile)
+	return entries
+}
+
+func latest(times []time.Time) time.Time {
+	var max time.Time
+	for _, t := range times {
+		if t.After(max) {
+			max = t
+		}
+	}
+	return max
+}
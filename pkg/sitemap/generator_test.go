@@ -0,0 +1,230 @@
+package sitemap
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeResource struct {
+	ID        uint
+	Key       string
+	Cover     string
+	UpdatedAt time.Time
+	Visible   bool // 有效、公开且未删除
+}
+
+type fakeSource struct {
+	resources []fakeResource
+	tags      []Page
+	queried   [][2]uint
+}
+
+func (f *fakeSource) MaxResourceID(context.Context) (uint, error) {
+	var max uint
+	for _, r := range f.resources {
+		if r.ID > max {
+			max = r.ID
+		}
+	}
+	return max, nil
+}
+
+func (f *fakeSource) ChangedResourcePages(_ context.Context, since time.Time, pageSize int) ([]int, error) {
+	var pages []int
+	for _, r := range f.resources {
+		if r.UpdatedAt.After(since) {
+			pages = append(pages, int(r.ID-1)/pageSize)
+		}
+	}
+	return pages, nil
+}
+
+func (f *fakeSource) Resources(_ context.Context, minID, maxID uint) ([]Resource, error) {
+	f.queried = append(f.queried, [2]uint{minID, maxID})
+	var list []Resource
+	for _, r := range f.resources {
+		if r.Visible && r.ID >= minID && r.ID <= maxID {
+			list = append(list, Resource{Key: r.Key, Cover: r.Cover, UpdatedAt: r.UpdatedAt})
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSource) Categories(context.Context) ([]Page, error) { return nil, nil }
+func (f *fakeSource) Tags(context.Context) ([]Page, error)       { return f.tags, nil }
+func (f *fakeSource) HotDramas(context.Context) ([]Page, error) {
+	return []Page{{Path: "/hot-dramas"}}, nil
+}
+
+// readURLSet 解压并解析 sitemap 文件，同时返回原始 XML（图片扩展使用命名空间前缀，直接按文本断言）
+func readURLSet(t *testing.T, path string) (URLSet, string) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var set URLSet
+	if err := xml.Unmarshal(data, &set); err != nil {
+		t.Fatal(err)
+	}
+	return set, string(data)
+}
+
+func readIndex(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index Index
+	if err := xml.Unmarshal(data, &index); err != nil {
+		t.Fatal(err)
+	}
+	var locs []string
+	for _, s := range index.Sitemaps {
+		locs = append(locs, s.Loc)
+	}
+	return locs
+}
+
+func TestGenerateFullAndIncremental(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	source := &fakeSource{
+		resources: []fakeResource{
+			{ID: 1, Key: "a", Cover: "/uploads/a.jpg", UpdatedAt: day1, Visible: true},
+			{ID: 2, Key: "a", UpdatedAt: day1.Add(time.Hour), Visible: true},
+			{ID: 3, Key: "hidden", UpdatedAt: day1, Visible: false},
+			{ID: 4, Key: "b", Cover: "https://img.example.com/b.jpg", UpdatedAt: day1, Visible: true},
+			{ID: 5, Key: "c", UpdatedAt: day1, Visible: true},
+		},
+		tags: []Page{{Path: "/?search=tag", UpdatedAt: day1}},
+	}
+	now := day1.Add(2 * time.Hour)
+	// 旧版生成的文件应在全量生成时清理
+	if err := os.WriteFile(filepath.Join(dir, "sitemap-0.xml"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGenerator(dir, "https://pan.example.com/", source)
+	g.PageSize = 2
+	g.Now = func() time.Time { return now }
+
+	result, err := g.Generate(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Full || result.ResourcePages != 3 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sitemap-0.xml")); !os.IsNotExist(err) {
+		t.Error("legacy sitemap should be removed")
+	}
+
+	page0, raw0 := readURLSet(t, filepath.Join(dir, ResourceFile(0)))
+	if len(page0.URLs) != 1 || page0.URLs[0].Loc != "https://pan.example.com/r/a" {
+		t.Fatalf("page0 = %+v", page0.URLs)
+	}
+	if page0.URLs[0].LastMod != "2026-03-01" || !strings.Contains(raw0, "<image:loc>https://pan.example.com/uploads/a.jpg</image:loc>") {
+		t.Errorf("page0 = %s", raw0)
+	}
+	page1, raw1 := readURLSet(t, filepath.Join(dir, ResourceFile(1)))
+	if len(page1.URLs) != 1 || page1.URLs[0].Loc != "https://pan.example.com/r/b" {
+		t.Errorf("hidden resource should be excluded: %+v", page1.URLs)
+	}
+	if !strings.Contains(raw1, "<image:loc>https://img.example.com/b.jpg</image:loc>") {
+		t.Errorf("page1 = %s", raw1)
+	}
+
+	wantIndex := []string{
+		"https://pan.example.com/" + ResourceFile(0),
+		"https://pan.example.com/" + ResourceFile(1),
+		"https://pan.example.com/" + ResourceFile(2),
+		"https://pan.example.com/" + TagsFile,
+		"https://pan.example.com/" + HotDramasFile,
+	}
+	if got := readIndex(t, dir); strings.Join(got, ",") != strings.Join(wantIndex, ",") {
+		t.Errorf("index = %v, want %v", got, wantIndex)
+	}
+
+	// 增量：只有 ID 5 所在的分页有变更，且该资源被隐藏后分页变空
+	now = now.Add(time.Hour)
+	source.resources[4].Visible = false
+	source.resources[4].UpdatedAt = now.Add(-time.Minute)
+	source.queried = nil
+	result, err = g.Generate(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Full || len(source.queried) != 1 || source.queried[0] != [2]uint{5, 6} {
+		t.Fatalf("expected only page 2 to be regenerated, result=%+v queried=%v", result, source.queried)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ResourceFile(2))); !os.IsNotExist(err) {
+		t.Error("empty page should be removed")
+	}
+	if got := readIndex(t, dir); len(got) != 4 {
+		t.Errorf("index after removal = %v", got)
+	}
+
+	// 文件缺失时即使没有变更也会重写
+	if err := os.Remove(filepath.Join(dir, ResourceFile(0))); err != nil {
+		t.Fatal(err)
+	}
+	source.queried = nil
+	now = now.Add(time.Hour)
+	if _, err := g.Generate(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if len(source.queried) != 1 || source.queried[0] != [2]uint{1, 2} {
+		t.Errorf("missing page should be regenerated, queried=%v", source.queried)
+	}
+}
+
+func TestGenerateFullRemovesStalePages(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	source := &fakeSource{resources: []fakeResource{
+		{ID: 1, Key: "a", UpdatedAt: now, Visible: true},
+		{ID: 3, Key: "b", UpdatedAt: now, Visible: true},
+	}}
+	g := NewGenerator(dir, "https://pan.example.com", source)
+	g.PageSize = 2
+	g.Now = func() time.Time { return now }
+	if _, err := g.Generate(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	source.resources = source.resources[:1]
+	now = now.Add(25 * time.Hour)
+	result, err := g.Generate(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Full {
+		t.Error("expected full regeneration after FullInterval")
+	}
+	sort.Strings(result.Removed)
+	if len(result.Removed) != 1 || result.Removed[0] != ResourceFile(1) {
+		t.Errorf("removed = %v", result.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ResourceFile(1))); !os.IsNotExist(err) {
+		t.Error("stale page should be removed")
+	}
+}
@@ -0,0 +1,171 @@
+package sitemap
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxURLsPerFile 单个 sitemap 文件最多包含的 URL 数（协议上限）
+	MaxURLsPerFile = 50000
+
+	// IndexFile sitemap 索引文件名
+	IndexFile = "sitemap.xml"
+	// ManifestFile 记录各分页状态的清单文件名，用于增量生成
+	ManifestFile = "sitemap-manifest.json"
+
+	sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	imageXMLNS   = "http://www.google.com/schemas/sitemap-image/1.1"
+	dateLayout   = "2006-01-02"
+)
+
+// Image 图片 sitemap 扩展条目
+type Image struct {
+	Loc string `xml:"image:loc"`
+}
+
+// URL 单个 URL 条目
+type URL struct {
+	Loc        string  `xml:"loc"`
+	LastMod    string  `xml:"lastmod,omitempty"`
+	ChangeFreq string  `xml:"changefreq,omitempty"`
+	Priority   float64 `xml:"priority,omitempty"`
+	Images     []Image `xml:"image:image,omitempty"`
+}
+
+// URLSet sitemap 文件内容
+type URLSet struct {
+	XMLName    xml.Name `xml:"urlset"`
+	XMLNS      string   `xml:"xmlns,attr"`
+	XMLNSImage string   `xml:"xmlns:image,attr,omitempty"`
+	URLs       []URL    `xml:"url"`
+}
+
+// IndexEntry sitemap 索引中的单个文件
+type IndexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Index sitemap 索引文件内容
+type Index struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []IndexEntry `xml:"sitemap"`
+}
+
+// FormatDate 格式化 lastmod，零值返回空字符串
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+// WriteURLSet 写入 sitemap 文件，文件名以 .gz 结尾时以 gzip 压缩
+func WriteURLSet(path string, urls []URL) error {
+	set := URLSet{XMLNS: sitemapXMLNS, URLs: urls}
+	for _, u := range urls {
+		if len(u.Images) > 0 {
+			set.XMLNSImage = imageXMLNS
+			break
+		}
+	}
+	return writeXML(path, set)
+}
+
+// WriteIndex 写入 sitemap 索引文件
+func WriteIndex(path string, entries []IndexEntry) error {
+	return writeXML(path, Index{XMLNS: sitemapXMLNS, Sitemaps: entries})
+}
+
+// writeXML 先写临时文件再重命名，避免读取方拿到写了一半的文件
+func writeXML(path string, v interface{}) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sitemap-*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	var w io.Writer = tmp
+	var gz *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(tmp)
+		w = gz
+	}
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err = encoder.Encode(v); err != nil {
+		return fmt.Errorf("写入XML失败: %w", err)
+	}
+	if gz != nil {
+		if err = gz.Close(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// FileState 清单中单个 sitemap 文件的状态
+type FileState struct {
+	URLs    int       `json:"urls"`
+	LastMod time.Time `json:"lastmod"`
+}
+
+// Manifest 上次生成的状态
+type Manifest struct {
+	BaseURL     string               `json:"base_url"`
+	GeneratedAt time.Time            `json:"generated_at"` // 上次生成开始的时间，作为下次增量的起点
+	FullAt      time.Time            `json:"full_at"`      // 上次全量生成的时间
+	Files       map[string]FileState `json:"files"`
+}
+
+// LoadManifest 读取清单，文件不存在或损坏时返回 nil
+func LoadManifest(dir string) *Manifest {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	if m.Files == nil {
+		m.Files = map[string]FileState{}
+	}
+	return &m
+}
+
+// Save 保存清单
+func (m *Manifest) Save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ManifestFile))
+}
@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量（取 8 位结果的后 6 位）
+func TestCodeRFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tc := range cases {
+		got, err := Code(secret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Code(T=%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	code, _ := Code(secret, Step(now))
+
+	if step, ok := Validate(secret, code, now, 1); !ok || step != Step(now) {
+		t.Fatalf("Validate current = %d, %v", step, ok)
+	}
+	// 允许一个步长的时钟偏差
+	if _, ok := Validate(secret, code, now.Add(Period*time.Second), 1); !ok {
+		t.Fatal("previous step should be accepted with skew 1")
+	}
+	if _, ok := Validate(secret, code, now.Add(2*Period*time.Second), 1); ok {
+		t.Fatal("code two steps old must be rejected")
+	}
+	if _, ok := Validate(secret, " "+code+" ", now, 0); !ok {
+		t.Fatal("surrounding spaces should be ignored")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Fatal("short code must be rejected")
+	}
+	if _, ok := Validate("not base32!", code, now, 1); ok {
+		t.Fatal("invalid secret must be rejected")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("老九网盘", "admin", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "period=30") {
+		t.Fatalf("URI = %s", uri)
+	}
+}
@@ -0,0 +1,58 @@
+package wordfilter
+
+import (
+	"slices"
+	"sync"
+)
+
+// Cache 按违禁词列表缓存构建好的匹配器，列表不变时直接复用，变化后重新构建；
+// 保留最近使用的若干个，兼顾系统配置与各处附加词表
+type Cache struct {
+	opts    Options
+	size    int
+	mu      sync.Mutex
+	entries []cacheEntry // 最近使用的在前
+}
+
+type cacheEntry struct {
+	src     []string // 调用方传入的切片，用于按地址快速命中
+	words   []string // 词表副本，内容相同的其他切片按此比较
+	matcher *Matcher
+}
+
+// NewCache 创建匹配器缓存，size 为最多保留的匹配器数量
+func NewCache(opts Options, size int) *Cache {
+	if size <= 0 {
+		size = 1
+	}
+	return &Cache{opts: opts, size: size}
+}
+
+// Get 返回词表对应的匹配器，命中时不产生内存分配。
+// 调用方通常反复传入同一份解析结果（如 utils.GetForbiddenWordsFromConfig 的返回值），
+// 按切片地址直接命中；其他切片逐词比较。传入的切片之后不应被原地修改
+func (c *Cache) Get(words []string) *Matcher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, entry := range c.entries {
+		if sameSlice(entry.src, words) || slices.Equal(entry.words, words) {
+			entry.src = words
+			copy(c.entries[1:i+1], c.entries[:i])
+			c.entries[0] = entry
+			return entry.matcher
+		}
+	}
+
+	entry := cacheEntry{src: words, words: slices.Clone(words), matcher: New(words, c.opts)}
+	if len(c.entries) < c.size {
+		c.entries = append(c.entries, cacheEntry{})
+	}
+	copy(c.entries[1:], c.entries[:len(c.entries)-1])
+	c.entries[0] = entry
+	return entry.matcher
+}
+
+// sameSlice 判断两个切片是否指向同一段底层数组且长度相同
+func sameSlice(a, b []string) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
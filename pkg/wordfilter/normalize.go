@@ -0,0 +1,89 @@
+package wordfilter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// traditionalToSimplified 繁体到简体的单字映射
+	traditionalToSimplified = buildPairs(traditionalPairs)
+	// pinyinOf 汉字到拼音的映射
+	pinyinOf = buildPinyin(pinyinTable)
+)
+
+func buildPairs(table string) map[rune]rune {
+	pairs := make(map[rune]rune)
+	for _, item := range strings.Fields(table) {
+		from, size := utf8.DecodeRuneInString(item)
+		to, _ := utf8.DecodeRuneInString(item[size:])
+		pairs[from] = to
+	}
+	return pairs
+}
+
+func buildPinyin(table string) map[rune]string {
+	pinyin := make(map[rune]string)
+	for _, item := range strings.Fields(table) {
+		syllable, chars, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		for _, r := range chars {
+			pinyin[r] = syllable
+		}
+	}
+	return pinyin
+}
+
+// foldWidth 全角字符转半角（全角空格与 U+FF01~U+FF5E 的 ASCII 对应字符）
+func foldWidth(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// normalizeRune 按选项归一化单个字符，始终一对一映射，便于把匹配位置还原到原文
+func (o Options) normalizeRune(r rune) rune {
+	if o.FoldWidth {
+		r = foldWidth(r)
+	}
+	if o.IgnoreCase {
+		r = unicode.ToLower(r)
+	}
+	if o.Simplify {
+		if s, ok := traditionalToSimplified[r]; ok {
+			r = s
+		}
+	}
+	return r
+}
+
+// Normalize 按选项归一化文本（全角转半角、大小写、繁转简）
+func (o Options) Normalize(s string) string {
+	return strings.Map(o.normalizeRune, s)
+}
+
+// Pinyin 返回汉字词的全拼（不带声调、不分隔），含非汉字或未收录的字时返回空串
+func Pinyin(word string) string {
+	var b strings.Builder
+	for _, r := range word {
+		syllable, ok := pinyinOf[r]
+		if !ok {
+			return ""
+		}
+		b.WriteString(syllable)
+	}
+	return b.String()
+}
+
+// isWordRune 判断是否为参与单词边界判断的字符（ASCII 字母）
+// 数字不视为单词字符，因此 "av" 仍能命中 "av123"
+func isWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+}
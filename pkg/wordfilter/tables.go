@@ -0,0 +1,60 @@
+package wordfilter
+
+// traditionalPairs 常用繁体字与对应简体字，每项为“繁简”两个字符
+// 仅覆盖违禁词中常见的字，未收录的字按原样参与匹配
+const traditionalPairs = "" +
+	"賭赌 賬账 黃黄 槍枪 彈弹 藥药 殺杀 義义 國国 黨党 軍军 變变 態态 獨独 亂乱 動动 盪荡 蕩荡 穢秽 " +
+	"買买 賣卖 錢钱 貨货 幣币 銀银 號号 碼码 網网 絡络 點点 擊击 廣广 車车 馬马 門门 開开 關关 機机 " +
+	"電电 腦脑 話话 語语 說说 讀读 書书 學学 習习 實实 際际 視视 頻频 圖图 畫画 聲声 樂乐 劇剧 場场 " +
+	"戲戏 遊游 戶户 頭头 發发 髮发 東东 業业 產产 歲岁 時时 間间 問问 題题 應应 該该 從从 來来 這这 " +
+	"裡里 們们 個个 為为 與与 會会 後后 對对 長长 見见 現现 還还 進进 過过 邊边 當当 樣样 經经 紅红 " +
+	"綠绿 藍蓝 線线 級级 紙纸 給给 結结 統统 絕绝 編编 練练 約约 總总 萬万 億亿 兩两 幾几 無无 愛爱 " +
+	"親亲 夢梦 覺觉 鬥斗 爭争 戰战 衛卫 護护 報报 導导 譯译 計计 記记 設设 許许 請请 讓让 論论 證证 " +
+	"識识 議议 調调 談谈 講讲 誰谁 課课 試试 詩诗 詞词 認认 誤误 謊谎 騙骗 詐诈 竊窃 盜盗 搶抢 綁绑 " +
+	"贓赃 販贩 賄贿 賂赂 貪贪 偽伪 傳传 揚扬 專专 屬属 歷历 曆历 歸归 鄉乡 濟济 擁拥 選选 舉举 權权 " +
+	"憲宪 務务 員员 區区 縣县 廳厅 處处 華华 漢汉 蘭兰 臺台 灣湾 島岛 瀏浏 覽览 獎奖 盤盘 資资 載载 " +
+	"貼贴 鏈链 連连 隱隐 檔档 節节 創创 劍剑 龍龙 鳥鸟 魚鱼 雞鸡 鴨鸭 豬猪 貓猫 媽妈 爺爷 孫孙 婦妇 " +
+	"兒儿 嬰婴 陰阴 陽阳 體体 膚肤 臉脸 腳脚 齒齿 氣气 麼么 麵面 飯饭 飲饮 餓饿 館馆 樓楼 層层 滿满 " +
+	"濕湿 溫温 熱热 燈灯 燒烧 煙烟 煉炼 爾尔 獄狱 猶犹 環环 瑪玛 畢毕 異异 療疗 癮瘾 盡尽 監监 確确 " +
+	"禮礼 禍祸 種种 稱称 穩稳 窮穷 競竞 筆笔 簡简 籤签 簽签 糧粮 緊紧 純纯 細细 組组 終终 維维 緣缘 " +
+	"縮缩 織织 繼继 續续 罰罚 羅罗 聯联 聽听 職职 膽胆 臟脏 興兴 舊旧 艦舰 藝艺 術术 衝冲 補补 製制 " +
+	"複复 襲袭 規规 觀观 觸触 訂订 訊讯 託托 訴诉 診诊 詳详 誘诱 誌志 謀谋 謝谢 謎谜 豐丰 貝贝 負负 " +
+	"財财 責责 貫贯 費费 貿贸 賀贺 賓宾 賞赏 賠赔 賴赖 贏赢 贊赞 趙赵 趕赶 躍跃 軟软 較较 輕轻 輸输 " +
+	"轉转 辦办 農农 遠远 適适 遲迟 遺遗 邏逻 郵邮 鄭郑 醫医 釋释 針针 釣钓 鈔钞 鋼钢 錄录 錯错 鍵键 " +
+	"鎖锁 鏡镜 閃闪 閉闭 閱阅 闊阔 隊队 階阶 陸陆 險险 雖虽 雙双 雜杂 離离 難难 雲云 靈灵 韓韩 頁页 " +
+	"項项 順顺 須须 預预 領领 顏颜 額额 風风 飛飞 養养 驗验 驚惊 髒脏 鬧闹 魯鲁 鮮鲜 麗丽 齊齐 龜龟 " +
+	"測测 濾滤 違违 審审 標标 優优 惡恶 極极 壞坏 嚴严 團团 圍围 園园 塊块 壓压 墮堕 奪夺 寫写 寶宝 " +
+	"將将 尋寻 屍尸 歡欢 殘残 毆殴 決决 況况 淨净 準准 涼凉 減减 湯汤 滅灭 漲涨 潔洁 澤泽 濃浓 災灾 " +
+	"爐炉 牽牵 犧牺 狀状 獲获 獻献 傷伤 價价 劃划 勞劳 勢势 協协 單单 嘗尝 嚇吓 囑嘱 塵尘 壯壮 " +
+	"壺壶 夥伙 奮奋 孌娈 寢寝 屆届 岡冈 幹干 幫帮 廢废 彎弯 徑径 憂忧 懷怀 懸悬 戀恋 攝摄 擔担 據据 " +
+	"擠挤 擴扩 擺摆 攤摊 敵敌 數数 斷断 暫暂 曉晓 朧胧 條条 棄弃 樁桩 橋桥 歐欧 殼壳 氾泛 湧涌 漁渔"
+
+// pinyinTable 常用汉字的拼音（不带声调），多音字取最常见读音
+// 格式为“拼音:汉字”，以空白分隔
+const pinyinTable = "" +
+	"a:阿啊 ai:爱艾哀 an:安按暗案 ba:八把爸吧霸 bai:白百摆败 ban:办版半班板 bang:帮棒 bao:包报保宝暴爆 " +
+	"bei:被北备背杯 ben:本 bi:比必币笔逼 bian:变边便编 biao:表标 bie:别 bing:病兵冰 bo:博播波伯 bu:不部步布怖 " +
+	"cai:才彩菜财裁 can:参残 cao:草操 ce:测策 cha:查茶差 chan:产 chang:长场常唱娼 chao:超炒 che:车 chen:陈 " +
+	"cheng:成城程 chi:吃 chong:冲虫 chu:出处初 chuan:传穿 chuang:床 chun:春 ci:次此 cong:从 cu:促 cuo:错 " +
+	"da:大打达 dai:代带袋贷 dan:单弹蛋 dang:党当 dao:到道刀导岛盗 de:的得德 deng:等灯 di:地第低帝 " +
+	"dian:点电店 diao:调 die:跌 ding:定 dong:东动洞 dou:都 du:赌读独毒度督肚渡杜堵 duan:短断 dui:对队 " +
+	"e:恶 er:二儿 fa:发法罚 fan:反翻饭犯贩 fang:方放房 fei:非飞费 fen:分 feng:风封 fu:服夫父复富府 " +
+	"gai:改 gan:干感 gang:港刚 gao:高搞告 ge:个歌哥 gei:给 gong:共功工公攻 gou:狗够 gu:古股 gua:挂 " +
+	"guan:关官管 guang:广光 gui:鬼规 guo:国过 hai:还海孩害 han:汉韩 hao:好号 he:和合河 hei:黑 hong:红 " +
+	"hou:后 hu:湖护户 hua:花华话画化 huai:坏 huan:欢 huang:黄皇 hui:会回 huo:火货活 ji:机鸡极记级集激基几妓 " +
+	"jia:家假价 jian:见间简监 jiang:江讲将奖 jiao:交教叫 jie:接解姐 jin:进金禁近 jing:经警京精境 jiu:就九酒旧 " +
+	"ju:局举剧 jun:军 kai:开 kan:看 kao:考 ke:可客课 kong:空恐控 kou:口 ku:哭 kuai:快块 la:拉 lai:来 lan:蓝 " +
+	"lang:狼 lao:老 le:乐了 lei:类 li:里理力利李历 lian:连脸练 liang:两量 liao:聊 lin:林 ling:领灵 liu:六流刘 " +
+	"long:龙 lu:路录陆 luan:乱 lun:轮论 luo:裸罗落 lv:绿律 ma:妈马吗麻 mai:买卖 man:满 mao:毛猫 mei:美没妹 " +
+	"men:门们 mi:密迷米 mian:面免 min:民 ming:明名 mo:魔 mu:母目 na:那拿 nan:男南难 nao:脑 nei:内 ni:你 " +
+	"nian:年 niao:鸟 nv:女 pai:派牌拍 pan:盘 pao:炮跑 pei:陪 pian:片骗 piao:票嫖 pin:品 ping:平 po:破 " +
+	"qi:气期其起七骑 qian:钱前千签 qiang:枪强抢墙 qin:亲 qing:情青清请 qiu:求球 qu:区去取 quan:全权 " +
+	"ren:人认 ri:日 rou:肉 ru:入 ruan:软 san:三 se:色 sha:杀 shan:山 shang:上商 shao:少 she:社射 " +
+	"shen:身神 sheng:生声 shi:是时事十世市实视 shou:手受 shu:书数 shuang:双 shui:水 shuo:说 si:死四私司 " +
+	"song:送 su:速 suo:所锁 ta:他她它 tai:台太 tan:谈 tang:堂 tao:套 te:特 ti:体题 tian:天 tiao:条 " +
+	"tong:统同 tou:头偷 tu:图 tui:退 tun:吞 wai:外 wan:玩完万丸 wang:网王 wei:为位未伪 wen:文问 wo:我 " +
+	"wu:无五武 xi:西习戏袭 xia:下 xian:先现县 xiang:想 xiao:小 xie:写邪 xin:新心信 xing:性行 xiong:胸 " +
+	"xiu:修 xu:需 xuan:选 xue:学血 ya:压 yan:言眼烟 yang:阳 yao:要药摇 ye:也夜爷业 yi:一以义医艺 " +
+	"yin:音银淫阴 ying:影应 you:有游油 yu:与鱼语 yuan:员源援 yue:约月 yun:云运孕 za:杂 zai:在 zang:脏 " +
+	"zao:造 ze:责 zha:炸诈 zhan:战 zhang:张 zhao:找 zhe:这 zhen:真 zheng:政证争正 zhi:之制支指 " +
+	"zhong:中种 zhu:主猪 zhuan:专转 zi:自资 zong:总 zou:走 zu:组 zui:最罪 zuo:做作"
@@ -0,0 +1,315 @@
+// Package wordfilter 基于 Aho-Corasick 自动机的多模式违禁词匹配，
+// 一次扫描即可找出文本中的全部违禁词，支持全角/半角、大小写、繁简归一化，
+// 英文单词边界以及拼音变体
+package wordfilter
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options 匹配选项
+type Options struct {
+	// FoldWidth 全角字符按半角匹配
+	FoldWidth bool
+	// IgnoreCase 忽略大小写
+	IgnoreCase bool
+	// Simplify 繁体字按简体匹配
+	Simplify bool
+	// WordBoundary 以英文字母开头/结尾的违禁词需在单词边界上才算命中，避免 "ass" 命中 "class"
+	WordBoundary bool
+	// Pinyin 为两个字及以上的汉字违禁词生成全拼变体（如 "赌博" 同时匹配 "dubo"），
+	// 拼音变体总是按单词边界匹配
+	Pinyin bool
+}
+
+// DefaultOptions 默认开启全部归一化与拼音变体
+func DefaultOptions() Options {
+	return Options{FoldWidth: true, IgnoreCase: true, Simplify: true, WordBoundary: true, Pinyin: true}
+}
+
+// HighlightTags 搜索高亮标记，ReplaceHighlighted 匹配时跳过这些标记
+var HighlightTags = []string{"<mark>", "</mark>"}
+
+// Match 一次命中
+type Match struct {
+	// Word 命中的原始违禁词（拼音变体命中时为对应的汉字词）
+	Word string
+	// Start、End 命中内容在原文中的字节区间 [Start, End)
+	Start, End int
+}
+
+type pattern struct {
+	word          int // 对应 words 下标
+	length        int // 归一化后的字符数
+	boundaryStart bool
+	boundaryEnd   bool
+}
+
+type node struct {
+	next map[rune]int32
+	fail int32
+	// out 以该节点结尾的模式下标，构建时已合并 fail 链上的输出
+	out []int32
+}
+
+// Matcher 违禁词匹配器，构建后只读，可并发使用
+type Matcher struct {
+	opts     Options
+	words    []string
+	patterns []pattern
+	nodes    []node
+}
+
+// New 构建匹配器，空白词被忽略，重复词只保留一个
+func New(words []string, opts Options) *Matcher {
+	m := &Matcher{opts: opts, nodes: []node{{}}}
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		idx := len(m.words)
+		m.words = append(m.words, word)
+
+		m.add(idx, opts.Normalize(word), opts.WordBoundary)
+		if opts.Pinyin && utf8.RuneCountInString(word) >= 2 {
+			if variant := Pinyin(opts.Normalize(word)); variant != "" {
+				m.add(idx, variant, true)
+			}
+		}
+	}
+	m.build()
+	return m
+}
+
+// add 把归一化后的模式插入字典树
+func (m *Matcher) add(word int, normalized string, boundary bool) {
+	p := pattern{word: word}
+	state := int32(0)
+	var first, last rune
+	for _, r := range normalized {
+		if p.length == 0 {
+			first = r
+		}
+		last = r
+		p.length++
+		next, ok := m.nodes[state].next[r]
+		if !ok {
+			if m.nodes[state].next == nil {
+				m.nodes[state].next = make(map[rune]int32)
+			}
+			next = int32(len(m.nodes))
+			m.nodes[state].next[r] = next
+			m.nodes = append(m.nodes, node{})
+		}
+		state = next
+	}
+	if p.length == 0 {
+		return
+	}
+	p.boundaryStart = boundary && isWordRune(first)
+	p.boundaryEnd = boundary && isWordRune(last)
+	m.nodes[state].out = append(m.nodes[state].out, int32(len(m.patterns)))
+	m.patterns = append(m.patterns, p)
+}
+
+// build 按层序计算失配指针并合并输出
+func (m *Matcher) build() {
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for {
+				if next, ok := m.nodes[fail].next[r]; ok {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if out := m.nodes[m.nodes[child].fail].out; len(out) > 0 {
+				m.nodes[child].out = append(m.nodes[child].out, out...)
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Len 返回违禁词数量
+func (m *Matcher) Len() int {
+	return len(m.words)
+}
+
+// Words 返回去重后的违禁词
+func (m *Matcher) Words() []string {
+	return append([]string(nil), m.words...)
+}
+
+// text 归一化后的待匹配文本，保留每个字符在原文中的位置
+type text struct {
+	runes  []rune
+	starts []int
+	sizes  []int
+}
+
+func (m *Matcher) prepare(s string, skip []string) text {
+	t := text{
+		runes:  make([]rune, 0, len(s)),
+		starts: make([]int, 0, len(s)),
+		sizes:  make([]int, 0, len(s)),
+	}
+	for i := 0; i < len(s); {
+		if s[i] == '<' && len(skip) > 0 {
+			if n := skipTag(s[i:], skip); n > 0 {
+				i += n
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		t.runes = append(t.runes, m.opts.normalizeRune(r))
+		t.starts = append(t.starts, i)
+		t.sizes = append(t.sizes, size)
+		i += size
+	}
+	return t
+}
+
+func skipTag(s string, tags []string) int {
+	for _, tag := range tags {
+		if strings.HasPrefix(s, tag) {
+			return len(tag)
+		}
+	}
+	return 0
+}
+
+// scan 扫描文本，对每个满足边界条件的命中回调 fn(模式, 起始字符下标, 结束字符下标)，fn 返回 false 时停止
+func (m *Matcher) scan(t text, fn func(p pattern, start, end int) bool) {
+	if len(m.patterns) == 0 {
+		return
+	}
+	state := int32(0)
+	for i, r := range t.runes {
+		for {
+			if next, ok := m.nodes[state].next[r]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.nodes[state].fail
+		}
+		for _, pi := range m.nodes[state].out {
+			p := m.patterns[pi]
+			start := i - p.length + 1
+			if p.boundaryStart && start > 0 && isWordRune(t.runes[start-1]) {
+				continue
+			}
+			if p.boundaryEnd && i+1 < len(t.runes) && isWordRune(t.runes[i+1]) {
+				continue
+			}
+			if !fn(p, start, i) {
+				return
+			}
+		}
+	}
+}
+
+// Contains 判断文本是否包含违禁词
+func (m *Matcher) Contains(s string) bool {
+	found := false
+	m.scan(m.prepare(s, nil), func(pattern, int, int) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// MatchedWords 返回文本中命中的违禁词，按配置顺序去重
+func (m *Matcher) MatchedWords(s string) []string {
+	var indexes []int
+	m.scan(m.prepare(s, nil), func(p pattern, _, _ int) bool {
+		indexes = append(indexes, p.word)
+		return true
+	})
+	if len(indexes) == 0 {
+		return nil
+	}
+	sort.Ints(indexes)
+	words := make([]string, 0, len(indexes))
+	for i, idx := range indexes {
+		if i == 0 || idx != indexes[i-1] {
+			words = append(words, m.words[idx])
+		}
+	}
+	return words
+}
+
+// FindAll 返回全部命中（包括重叠的命中），按结束位置排序
+func (m *Matcher) FindAll(s string) []Match {
+	t := m.prepare(s, nil)
+	var matches []Match
+	m.scan(t, func(p pattern, start, end int) bool {
+		matches = append(matches, Match{
+			Word:  m.words[p.word],
+			Start: t.starts[start],
+			End:   t.starts[end] + t.sizes[end],
+		})
+		return true
+	})
+	return matches
+}
+
+// Replace 把命中的字符替换为 *，每个字符替换为一个 *
+func (m *Matcher) Replace(s string) string {
+	return m.replace(s, nil)
+}
+
+// ReplaceHighlighted 与 Replace 相同，但匹配时忽略 <mark> 高亮标记并保留标记本身，
+// 违禁词被高亮拆开时也能命中
+func (m *Matcher) ReplaceHighlighted(s string) string {
+	return m.replace(s, HighlightTags)
+}
+
+func (m *Matcher) replace(s string, skip []string) string {
+	t := m.prepare(s, skip)
+	var covered []bool
+	m.scan(t, func(_ pattern, start, end int) bool {
+		if covered == nil {
+			covered = make([]bool, len(t.runes))
+		}
+		for i := start; i <= end; i++ {
+			covered[i] = true
+		}
+		return true
+	})
+	if covered == nil {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for i, hit := range covered {
+		if !hit {
+			continue
+		}
+		b.WriteString(s[last:t.starts[i]])
+		b.WriteByte('*')
+		last = t.starts[i] + t.sizes[i]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package wordfilter
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMatcherNormalization(t *testing.T) {
+	m := New([]string{"赌博", "Porn", "AV", " ", "赌博"}, DefaultOptions())
+	if m.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", m.Len())
+	}
+
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"网上賭博平台", []string{"赌博"}},               // 繁体
+		{"ＰＯＲＮ合集", []string{"Porn"}},             // 全角 + 大小写
+		{"free porn!", []string{"Porn"}},         // 单词边界
+		{"pornography", nil},                     // 不在单词边界
+		{"经典av123", []string{"AV"}},              // 数字不算单词字符
+		{"java教程", nil},                          // 不在单词边界
+		{"在线dubo网站", []string{"赌博"}},             // 拼音变体
+		{"Dubois 传记", nil},                       // 拼音变体也按单词边界
+		{"赌博 porn dubo", []string{"赌博", "Porn"}}, // 按配置顺序去重
+		{"正常标题", nil},
+	}
+	for _, tc := range cases {
+		got := m.MatchedWords(tc.text)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("MatchedWords(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+		if m.Contains(tc.text) != (len(tc.want) > 0) {
+			t.Errorf("Contains(%q) = %v", tc.text, !m.Contains(tc.text))
+		}
+	}
+
+	plain := New([]string{"赌博", "ass"}, Options{})
+	if plain.Contains("賭博") || plain.Contains("dubo") || !plain.Contains("class") {
+		t.Fatal("options disabled should match raw text only")
+	}
+}
+
+func TestMatcherReplace(t *testing.T) {
+	m := New([]string{"赌博", "赌博网站", "博彩", "sex"}, DefaultOptions())
+
+	cases := []struct{ in, want string }{
+		{"最新賭博網站大全", "最新****大全"},
+		{"赌博彩票", "***票"}, // 重叠命中合并
+		{"ＳＥＸ video", "*** video"},
+		{"sexy", "sexy"},
+		{"正常标题", "正常标题"},
+	}
+	for _, tc := range cases {
+		if got := m.Replace(tc.in); got != tc.want {
+			t.Errorf("Replace(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	got := m.ReplaceHighlighted("<mark>赌</mark>博合集")
+	if got != "<mark>*</mark>*合集" {
+		t.Fatalf("ReplaceHighlighted = %q", got)
+	}
+
+	matches := m.FindAll("看sex片")
+	if len(matches) != 1 || matches[0].Word != "sex" || "看sex片"[matches[0].Start:matches[0].End] != "sex" {
+		t.Fatalf("FindAll = %+v", matches)
+	}
+}
+
+func TestCacheRebuildsOnChange(t *testing.T) {
+	c := NewCache(DefaultOptions(), 2)
+	a := c.Get([]string{"a1", "a2"})
+	if c.Get([]string{"a1", "a2"}) != a {
+		t.Fatal("same words should reuse matcher")
+	}
+	b := c.Get([]string{"b1"})
+	if b == a || !b.Contains("b1") || b.Contains("a1") {
+		t.Fatal("changed words should rebuild matcher")
+	}
+	if c.Get([]string{"a1", "a2"}) != a {
+		t.Fatal("recently used matcher should stay cached")
+	}
+	c.Get([]string{"c1"}) // 淘汰最久未使用的 b
+	if c.Get([]string{"b1"}) == b {
+		t.Fatal("least recently used matcher should be evicted")
+	}
+
+	// 内容相同的不同切片同样命中
+	d := c.Get([]string{"d1", "d2"})
+	if c.Get([]string{"d1", "d2"}) != d || c.Get([]string{"d1", "d3"}) == d {
+		t.Fatal("equal word lists should share a matcher")
+	}
+}
+
+func TestCacheHitDoesNotAllocate(t *testing.T) {
+	c := NewCache(DefaultOptions(), 2)
+	words := benchWords(5000)
+	c.Get(words)
+	if allocs := testing.AllocsPerRun(100, func() { c.Get(words) }); allocs != 0 {
+		t.Fatalf("Get allocated %.0f times per hit", allocs)
+	}
+	other := slices.Clone(words)
+	if allocs := testing.AllocsPerRun(100, func() { c.Get(words); c.Get(other) }); allocs != 0 {
+		t.Fatalf("Get allocated %.0f times per equal-content hit", allocs)
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	if got := Pinyin("法轮"); got != "falun" {
+		t.Fatalf("Pinyin = %q", got)
+	}
+	if got := Pinyin("赌x"); got != "" {
+		t.Fatalf("Pinyin with non-han = %q", got)
+	}
+}
+
+// benchWords 生成由常用汉字与英文组成的违禁词表
+func benchWords(n int) []string {
+	r := rand.New(rand.NewSource(1))
+	chars := []rune(strings.Join(strings.Fields(strings.Map(func(c rune) rune {
+		if c < 0x80 && c != ' ' {
+			return ' '
+		}
+		return c
+	}, pinyinTable)), ""))
+	words := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if i%5 == 0 {
+			words = append(words, fmt.Sprintf("spam%d", i))
+			continue
+		}
+		word := make([]rune, 2+r.Intn(3))
+		for j := range word {
+			word[j] = chars[r.Intn(len(chars))]
+		}
+		words = append(words, string(word))
+	}
+	return words
+}
+
+var benchTitles = []string{
+	"【高清】某某电影 2024 1080P 中英双字 阿里云盘",
+	"Python 从入门到精通 全套视频教程 附源码",
+	"经典老歌合集 无损音乐 FLAC 百度网盘分享",
+	"考研英语历年真题解析 PDF 电子版",
+	"The Complete Guide to Go Programming (2nd Edition)",
+}
+
+// naiveReplace 逐词正则替换（优化前的实现）
+func naiveReplace(text string, words []string) string {
+	for _, word := range words {
+		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(word))
+		text = re.ReplaceAllString(text, strings.Repeat("*", len([]rune(word))))
+	}
+	return text
+}
+
+// naiveContains 逐词 strings.Contains（优化前的实现）
+func naiveContains(text string, words []string) bool {
+	lower := strings.ToLower(text)
+	for _, word := range words {
+		if strings.Contains(lower, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func BenchmarkReplace(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		words := benchWords(n)
+		b.Run(fmt.Sprintf("naive/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveReplace(benchTitles[i%len(benchTitles)], words)
+			}
+		})
+		m := New(words, DefaultOptions())
+		b.Run(fmt.Sprintf("automaton/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m.Replace(benchTitles[i%len(benchTitles)])
+			}
+		})
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	for _, n := range []int{100, 1000, 5000} {
+		words := benchWords(n)
+		b.Run(fmt.Sprintf("naive/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				naiveContains(benchTitles[i%len(benchTitles)], words)
+			}
+		})
+		m := New(words, DefaultOptions())
+		b.Run(fmt.Sprintf("automaton/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m.Contains(benchTitles[i%len(benchTitles)])
+			}
+		})
+		// 业务代码的调用方式：每条标题都经缓存取匹配器
+		c := NewCache(DefaultOptions(), 8)
+		b.Run(fmt.Sprintf("cached/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				c.Get(words).Contains(benchTitles[i%len(benchTitles)])
+			}
+		})
+	}
+}
+
+func BenchmarkBuild(b *testing.B) {
+	words := benchWords(5000)
+	for i := 0; i < b.N; i++ {
+		New(words, DefaultOptions())
+	}
+}
@@ -1,6 +1,7 @@
 package jsvm
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha256"
@@ -17,34 +18,20 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/dop251/goja"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/robfig/cron/v3"
 	"github.com/ctwj/urldb/plugin-system/core"
 	"github.com/ctwj/urldb/db"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/leader"
 	"github.com/ctwj/urldb/utils"
 )
 
-// 全局cron调度器管理
-type cronManager struct {
-	scheduler *cron.Cron
-	jobs      map[string]cron.EntryID
-	jobsMux   sync.RWMutex
-}
-
-var globalCronManager = &cronManager{
-	scheduler: cron.New(),
-	jobs:      make(map[string]cron.EntryID),
-}
-
-// 初始化cron调度器
-func init() {
-	globalCronManager.scheduler.Start()
-}
+// pluginCronJobPrefix 插件定时任务在任务调度器中的名称前缀，避免与系统任务重名
+const pluginCronJobPrefix = "plugin."
 
 // baseBinds 基础API绑定
 func baseBinds(vm *goja.Runtime) {
@@ -1065,135 +1052,94 @@ func hooksBinds(app core.App, vm *goja.Runtime, executors *vmsPool) {
 
 // cronBinds 定时任务绑定
 func cronBinds(app core.App, vm *goja.Runtime, executors *vmsPool, repoManager *repo.RepositoryManager) {
+	add := func(name, schedule string, handler goja.Value) error {
+		return addPluginCronJob(name, schedule, handler, executors, repoManager)
+	}
 	vm.Set("cron", map[string]interface{}{
-		"add": func(name, schedule string, handler goja.Value) error {
-			if fn, ok := goja.AssertFunction(handler); ok {
-				// 实际添加到cron调度器
-				globalCronManager.jobsMux.Lock()
-				defer globalCronManager.jobsMux.Unlock()
-
-				// 如果同名任务已存在，先移除
-				if entryID, exists := globalCronManager.jobs[name]; exists {
-					globalCronManager.scheduler.Remove(entryID)
-					delete(globalCronManager.jobs, name)
-					utils.Info("Removed existing cron job: %s", name)
-				}
-
-				// 创建包装函数，从池中获取VM实例
-				wrappedFunc := func() {
-					executor := executors.Get()
-					defer executors.Put(executor)
-
-					// 设置当前插件上下文
-					pluginName := extractPluginNameFromCronJob(name)
-					if pluginName != "" {
-						executor.Set("_currentPluginName", pluginName)
-					} else {
-						executor.Set("_currentPluginName", "cron_job")
-					}
-					executor.Set("_repoManager", repoManager)
-
-					_, err := fn(goja.Undefined())
-					if err != nil {
-						utils.Error("Cron job '%s' execution error: %v", name, err)
-					} else {
-						utils.Info("Cron job '%s' executed successfully", name)
-					}
-				}
-
-				// 添加到调度器
-				entryID, err := globalCronManager.scheduler.AddFunc(schedule, wrappedFunc)
-				if err != nil {
-					utils.Error("Failed to add cron job '%s': %v", name, err)
-					return err
-				}
-
-				// 保存任务ID
-				globalCronManager.jobs[name] = entryID
-				utils.Info("Cron job registered and started: %s (%s)", name, schedule)
-			}
-			return nil
-		},
+		"add": add,
 	})
 
 	// 为了兼容性，直接注册 cronAdd 函数
-	vm.Set("cronAdd", func(name, schedule string, handler goja.Value) error {
-		if fn, ok := goja.AssertFunction(handler); ok {
-			// 实际添加到cron调度器
-			globalCronManager.jobsMux.Lock()
-			defer globalCronManager.jobsMux.Unlock()
-
-			// 如果同名任务已存在，先移除
-			if entryID, exists := globalCronManager.jobs[name]; exists {
-				globalCronManager.scheduler.Remove(entryID)
-				delete(globalCronManager.jobs, name)
-				utils.Info("Removed existing cron job: %s", name)
-			}
-
-			// 创建包装函数，从池中获取VM实例
-			wrappedFunc := func() {
-				// 添加panic恢复机制，防止整个程序崩溃
-				defer func() {
-					if r := recover(); r != nil {
-						utils.Error("Cron job '%s' panicked: %v", name, r)
-						utils.Error("Stack trace: %v", r)
-						// 不要重新panic，只是记录错误
-					}
-				}()
-
-				// 检查插件是否启用
-				pluginName := extractPluginNameFromCronJob(name)
-				if repoManager != nil && pluginName != "" {
-					if config, err := repoManager.PluginConfigRepository.GetConfig(pluginName); err == nil && config != nil && !config.Enabled {
-						utils.Debug("Cron job '%s' skipped: plugin '%s' is disabled", name, pluginName)
-						return
-					}
-				}
+	vm.Set("cronAdd", add)
+}
 
-				executor := executors.Get()
-				defer executors.Put(executor)
+// addPluginCronJob 把插件的定时任务注册到统一的任务调度器并启用，同名任务会被替换
+func addPluginCronJob(name, schedule string, handler goja.Value, executors *vmsPool, repoManager *repo.RepositoryManager) error {
+	fn, ok := goja.AssertFunction(handler)
+	if !ok {
+		return nil
+	}
+	pluginName := extractPluginNameFromCronJob(name)
+
+	run := func(context.Context) (string, error) {
+		// 检查插件是否启用
+		if repoManager != nil && pluginName != "" {
+			if config, err := repoManager.PluginConfigRepository.GetConfig(pluginName); err == nil && config != nil && !config.Enabled {
+				utils.Debug("Cron job '%s' skipped: plugin '%s' is disabled", name, pluginName)
+				return fmt.Sprintf("插件 %s 已禁用", pluginName), cronjob.ErrSkipped
+			}
+		}
 
-				// 设置当前插件上下文
-				if pluginName != "" {
-					executor.Set("_currentPluginName", pluginName)
-					utils.Info("CRON: Set _currentPluginName to %s for VM %p", pluginName, executor)
-				} else {
-					executor.Set("_currentPluginName", "cron_job")
-					utils.Info("CRON: Set _currentPluginName to cron_job for VM %p", executor)
-				}
-				executor.Set("_repoManager", repoManager)
-				utils.Info("CRON: Set _repoManager for VM %p", executor)
+		executor := executors.Get()
+		defer executors.Put(executor)
 
-				// 再次保护，防止VM调用时出错
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							utils.Error("Cron job '%s' VM execution panicked: %v", name, r)
-						}
-					}()
+		// 设置当前插件上下文
+		if pluginName != "" {
+			executor.Set("_currentPluginName", pluginName)
+		} else {
+			executor.Set("_currentPluginName", "cron_job")
+		}
+		executor.Set("_repoManager", repoManager)
 
-					_, err := fn(goja.Undefined())
-					if err != nil {
-						utils.Error("Cron job '%s' execution error: %v", name, err)
-					} else {
-						utils.Info("Cron job '%s' executed successfully", name)
-					}
-				}()
-			}
+		// VM 执行中的 panic 由任务调度器恢复并记为失败
+		result, err := fn(goja.Undefined())
+		if err != nil {
+			utils.Error("Cron job '%s' execution error: %v", name, err)
+			return "", err
+		}
+		utils.Debug("Cron job '%s' executed successfully", name)
+		if result == nil || goja.IsUndefined(result) || goja.IsNull(result) {
+			return "", nil
+		}
+		return result.String(), nil
+	}
 
-			// 添加到调度器
-			entryID, err := globalCronManager.scheduler.AddFunc(schedule, wrappedFunc)
-			if err != nil {
-				utils.Error("Failed to add cron job '%s': %v", name, err)
-				return err
-			}
+	jobs := cronjob.Default()
+	jobName := pluginCronJobPrefix + name
+	err := jobs.Register(cronjob.Job{
+		Name:        jobName,
+		Description: fmt.Sprintf("插件 %s 的定时任务 %s", pluginName, name),
+		Source:      cronjob.SourcePlugin,
+		Spec:        schedule,
+		Run:         run,
+	})
+	if err == nil {
+		err = activatePluginCronJob(jobName)
+	}
+	if err != nil {
+		utils.Error("Failed to add cron job '%s': %v", name, err)
+		return err
+	}
+	utils.Info("Cron job registered and started: %s (%s)", name, schedule)
+	return nil
+}
 
-			// 保存任务ID
-			globalCronManager.jobs[name] = entryID
-			utils.Info("Cron job registered and started: %s (%s)", name, schedule)
+// activatePluginCronJob 启用插件定时任务：配置了选主时与系统任务一样参与竞选，
+// 只在持有锁的节点启用，避免每个副本各执行一次
+func activatePluginCronJob(jobName string) error {
+	jobs := cronjob.Default()
+	elector := leader.Default()
+	if elector == nil {
+		return jobs.Activate(jobName)
+	}
+	elector.Run(jobName, func() {
+		if err := jobs.Activate(jobName); err != nil {
+			utils.Error("Failed to activate cron job '%s': %v", jobName, err)
 		}
-		return nil
+	}, func() {
+		jobs.Deactivate(jobName)
 	})
+	return nil
 }
 
 // configBinds 配置相关绑定
@@ -1,6 +1,8 @@
 package plugins
 
 import (
+	"sync"
+
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/utils"
 )
@@ -39,8 +41,30 @@ func TriggerURLAdd(url *entity.Resource, data map[string]interface{}) {
 	}
 }
 
+// UserLoginListener 进程内的用户登录事件监听器，需自行处理耗时操作
+type UserLoginListener func(user *entity.User, data map[string]interface{})
+
+var (
+	loginListenersMu sync.RWMutex
+	loginListeners   []UserLoginListener
+)
+
+// OnUserLogin 注册用户登录事件监听器，与插件一起在 TriggerUserLogin 时调用
+func OnUserLogin(listener UserLoginListener) {
+	loginListenersMu.Lock()
+	defer loginListenersMu.Unlock()
+	loginListeners = append(loginListeners, listener)
+}
+
 // TriggerUserLogin 触发用户登录事件
 func TriggerUserLogin(user *entity.User, data map[string]interface{}) {
+	loginListenersMu.RLock()
+	listeners := loginListeners
+	loginListenersMu.RUnlock()
+	for _, listener := range listeners {
+		listener(user, data)
+	}
+
 	if pluginApp != nil {
 		if err := pluginApp.TriggerUserLogin(user, data); err != nil {
 			utils.Error("Failed to trigger user login event: %v", err)
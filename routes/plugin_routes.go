@@ -1,8 +1,10 @@
 package routes
 
 import (
+	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
 	"github.com/ctwj/urldb/handlers"
+	"github.com/ctwj/urldb/middleware"
 	"github.com/ctwj/urldb/plugin-system/manager/plugin"
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +15,7 @@ func SetupPluginRoutes(router *gin.Engine, repoManager *repo.RepositoryManager,
 
 	// 插件管理路由组
 	pluginGroup := router.Group("/api/plugins")
+	pluginGroup.Use(middleware.AuthMiddleware(), middleware.RequirePermission(entity.PermissionPluginManage))
 	{
 		// 插件列表和详情
 		pluginGroup.GET("", pluginHandler.GetPlugins)                    // 获取插件列表（钩子插件）
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	// analyticsRollupInterval 检查周期，跨天后的第一次检查执行汇总
+	analyticsRollupInterval = 30 * time.Minute
+	// analyticsRollupTimeout 单次汇总与清理的最长执行时间
+	analyticsRollupTimeout = 2 * time.Hour
+)
+
+// AnalyticsRollupScheduler 统计日汇总调度器
+// 每天汇总前一天（以及此前遗漏）的访问、搜索与API访问记录，并按保留天数清理原始记录。
+type AnalyticsRollupScheduler struct {
+	*BaseScheduler
+	lastRunDay string // 最近一次成功执行的日期
+}
+
+// NewAnalyticsRollupScheduler 创建统计日汇总调度器
+func NewAnalyticsRollupScheduler(base *BaseScheduler) *AnalyticsRollupScheduler {
+	return &AnalyticsRollupScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob 统计日汇总任务定义，启用时立即检查一次
+func (s *AnalyticsRollupScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobAnalyticsRollup,
+		Description: "汇总前一天的访问与搜索统计并清理原始记录",
+		Spec:        everySpec(analyticsRollupInterval),
+		CatchUp:     true,
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动统计日汇总定时任务
+func (s *AnalyticsRollupScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止统计日汇总定时任务，正在进行的汇总会被取消
+func (s *AnalyticsRollupScheduler) Stop() {
+	stopCronJob(JobAnalyticsRollup)
+}
+
+// IsRunning 检查统计日汇总任务是否在运行
+func (s *AnalyticsRollupScheduler) IsRunning() bool {
+	return cronJobActive(JobAnalyticsRollup)
+}
+
+// runOnce 每天只成功执行一次（手动触发除外）；失败时下个周期重试
+func (s *AnalyticsRollupScheduler) runOnce(ctx context.Context) (string, error) {
+	today := utils.GetCurrentTime().Format(utils.TimeFormatDate)
+	if s.lastRunDay == today && !cronjob.IsManual(ctx) {
+		return "今日已汇总", cronjob.ErrSkipped
+	}
+	svc := GetGlobalAnalyticsService()
+	if svc == nil {
+		utils.Debug("[AnalyticsRollupScheduler] 统计服务未初始化，跳过本轮执行")
+		return "统计服务未初始化", cronjob.ErrSkipped
+	}
+	if !svc.Config().RollupEnabled {
+		return "未启用日汇总", cronjob.ErrSkipped
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, analyticsRollupTimeout)
+	defer cancel()
+	finish := publishRun("analytics_rollup")
+	result, err := svc.RunNightly(ctx)
+	if err != nil {
+		utils.Error("[AnalyticsRollupScheduler] 统计日汇总失败: %v", err)
+		finish("", err)
+		return "", err
+	}
+	s.lastRunDay = today
+	summary := fmt.Sprintf("汇总 %d 天, 失败 %d 天, 清理原始记录 %v", len(result.Backfill.Rolled), len(result.Backfill.Failed), result.Purged)
+	utils.Info("[AnalyticsRollupScheduler] 统计日汇总完成: %s", summary)
+	finish(summary, nil)
+	return summary, nil
+}
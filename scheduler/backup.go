@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	// backupCheckInterval 检查周期，距上次备份超过配置的间隔时执行
+	backupCheckInterval = 10 * time.Minute
+	// backupTimeout 单次备份的最长执行时间
+	backupTimeout = 6 * time.Hour
+)
+
+// BackupScheduler 定时备份调度器
+// 按配置的间隔备份数据库、系统配置、上传与插件目录，并按保留规则清理旧备份。
+type BackupScheduler struct {
+	*BaseScheduler
+}
+
+// NewBackupScheduler 创建定时备份调度器
+func NewBackupScheduler(base *BaseScheduler) *BackupScheduler {
+	return &BackupScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob 定时备份任务定义，启用时立即检查一次
+func (s *BackupScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobBackup,
+		Description: "按配置的间隔备份数据库与文件并清理旧备份",
+		Spec:        everySpec(backupCheckInterval),
+		CatchUp:     true,
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动定时备份任务
+func (s *BackupScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止定时备份任务，正在进行的备份会被取消
+func (s *BackupScheduler) Stop() {
+	stopCronJob(JobBackup)
+}
+
+// IsRunning 检查定时备份任务是否在运行
+func (s *BackupScheduler) IsRunning() bool {
+	return cronJobActive(JobBackup)
+}
+
+// runOnce 到期时执行一次备份；失败时下个周期重试
+func (s *BackupScheduler) runOnce(ctx context.Context) (string, error) {
+	svc := GetGlobalBackupService()
+	if svc == nil {
+		utils.Debug("[BackupScheduler] 备份服务未初始化，跳过本轮执行")
+		return "备份服务未初始化", cronjob.ErrSkipped
+	}
+	// 手动触发时不检查是否到期
+	if !cronjob.IsManual(ctx) && !svc.Due() {
+		return "未到备份时间", cronjob.ErrSkipped
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backupTimeout)
+	defer cancel()
+	finish := publishRun("backup")
+	result, err := svc.Run(ctx)
+	if err != nil {
+		utils.Error("[BackupScheduler] 定时备份失败: %v", err)
+		finish("", err)
+		return "", err
+	}
+	summary := fmt.Sprintf("%s, 大小 %d 字节, 文件 %d 个, 清理旧备份 %d 个", result.Name, result.Size, result.Files, len(result.Pruned))
+	utils.Info("[BackupScheduler] 定时备份完成: %s", summary)
+	finish(summary, nil)
+	return summary, nil
+}
@@ -1,10 +1,14 @@
 package scheduler
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/utils"
 )
 
 // BaseScheduler 基础调度器结构
@@ -19,10 +23,6 @@ type BaseScheduler struct {
 	tagRepo           repo.TagRepository
 	categoryRepo      repo.CategoryRepository
 
-	// 控制字段
-	stopChan  chan bool
-	isRunning bool
-
 	// 平台映射缓存
 	panCache     map[string]*uint // serviceType -> panID
 	panCacheOnce sync.Once
@@ -48,41 +48,78 @@ func NewBaseScheduler(
 		cksRepo:           cksRepo,
 		tagRepo:           tagRepo,
 		categoryRepo:      categoryRepo,
-		stopChan:          make(chan bool),
-		isRunning:         false,
 		panCache:          make(map[string]*uint),
 	}
 }
 
-// Stop 停止调度器
-func (b *BaseScheduler) Stop() {
-	if b.isRunning {
-		b.stopChan <- true
-		b.isRunning = false
+// publishRun 推送定时任务开始事件，返回的函数在本轮结束时推送结果摘要
+func publishRun(name string) func(summary string, err error) {
+	startedAt := utils.GetCurrentTime()
+	eventbus.Publish(eventbus.TypeSchedulerRun, map[string]interface{}{
+		"scheduler":  name,
+		"phase":      "start",
+		"started_at": startedAt,
+	})
+	return func(summary string, err error) {
+		data := map[string]interface{}{
+			"scheduler":   name,
+			"phase":       "finish",
+			"started_at":  startedAt,
+			"duration_ms": time.Since(startedAt).Milliseconds(),
+			"success":     err == nil,
+			"summary":     summary,
+		}
+		if err != nil {
+			data["error"] = err.Error()
+		}
+		eventbus.Publish(eventbus.TypeSchedulerRun, data)
 	}
 }
 
-// IsRunning 检查是否正在运行
-func (b *BaseScheduler) IsRunning() bool {
-	return b.isRunning
+// startCronJob 注册并启用任务，由统一的任务调度器按 cron 表达式执行
+func startCronJob(job cronjob.Job) {
+	jobs := cronjob.Default()
+	if err := jobs.Register(job); err != nil {
+		utils.Error("注册定时任务 %s 失败: %v", job.Name, err)
+		return
+	}
+	if err := jobs.Activate(job.Name); err != nil {
+		utils.Error("启用定时任务 %s 失败: %v", job.Name, err)
+		return
+	}
+	utils.Info("定时任务 %s 已启用，执行计划: %s", job.Name, job.Spec)
 }
 
-// SetRunning 设置运行状态
-func (b *BaseScheduler) SetRunning(running bool) {
-	b.isRunning = running
+// stopCronJob 停用任务，正在执行的一轮通过 ctx 取消
+func stopCronJob(name string) {
+	if !cronjob.Default().IsActive(name) {
+		utils.Debug("定时任务 %s 未在运行", name)
+		return
+	}
+	cronjob.Default().Deactivate(name)
+	utils.Info("定时任务 %s 已停用", name)
 }
 
-// GetStopChan 获取停止通道
-func (b *BaseScheduler) GetStopChan() chan bool {
-	return b.stopChan
+// cronJobActive 任务是否已启用
+func cronJobActive(name string) bool {
+	return cronjob.Default().IsActive(name)
+}
+
+// registerCronJob 只注册任务不启用，使未启用的任务也出现在任务列表中并可手动执行
+func registerCronJob(job cronjob.Job) {
+	if err := cronjob.Default().Register(job); err != nil {
+		utils.Error("注册定时任务 %s 失败: %v", job.Name, err)
+	}
 }
 
-// SleepWithStopCheck 带停止检查的睡眠
-func (b *BaseScheduler) SleepWithStopCheck(duration time.Duration) bool {
-	select {
-	case <-time.After(duration):
-		return false
-	case <-b.stopChan:
-		return true
+// everySpec 把固定间隔转换为 @every 表达式，如 3m、12h
+func everySpec(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("@every %dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("@every %dm", d/time.Minute)
+	default:
+		return "@every " + d.String()
 	}
 }
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+// contentSourceCheckInterval 检查到期内容源的周期；各内容源的抓取间隔由其 interval_minutes 决定
+const contentSourceCheckInterval = time.Minute
+
+// ContentSourceScheduler 内容源采集调度器
+// 每分钟检查一次到期的启用内容源，依次抓取并写入待处理资源。
+type ContentSourceScheduler struct {
+	*BaseScheduler
+}
+
+// NewContentSourceScheduler 创建内容源采集调度器
+func NewContentSourceScheduler(base *BaseScheduler) *ContentSourceScheduler {
+	return &ContentSourceScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob 内容源采集任务定义
+func (s *ContentSourceScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobContentSource,
+		Description: "抓取到期的内容源并写入待处理资源",
+		Spec:        everySpec(contentSourceCheckInterval),
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动内容源采集定时任务
+func (s *ContentSourceScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止内容源采集定时任务，正在进行的抓取会被取消
+func (s *ContentSourceScheduler) Stop() {
+	stopCronJob(JobContentSource)
+}
+
+// IsRunning 检查内容源采集任务是否在运行
+func (s *ContentSourceScheduler) IsRunning() bool {
+	return cronJobActive(JobContentSource)
+}
+
+// runOnce 抓取所有到期的内容源
+func (s *ContentSourceScheduler) runOnce(ctx context.Context) (string, error) {
+	svc := GetGlobalContentSourceService()
+	if svc == nil {
+		utils.Debug("[ContentSourceScheduler] 内容源服务未初始化，跳过本轮执行")
+		return "内容源服务未初始化", cronjob.ErrSkipped
+	}
+
+	results := svc.RunDue(ctx)
+	if len(results) == 0 {
+		return "没有到期的内容源", cronjob.ErrSkipped
+	}
+
+	created, failed := 0, 0
+	for _, r := range results {
+		created += r.Created
+		if r.Error != "" {
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("内容源=%d, 失败=%d, 新增待处理资源=%d", len(results), failed, created)
+	utils.Info("[ContentSourceScheduler] 本轮采集结束: %s", summary)
+	return summary, nil
+}
@@ -1,9 +1,11 @@
 package scheduler
 
 import (
+	"context"
 	"sync"
 
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/leader"
 	"github.com/ctwj/urldb/services"
 	"github.com/ctwj/urldb/utils"
 )
@@ -21,6 +23,26 @@ var (
 	globalMeilisearchManager *services.MeilisearchManager
 	// 全局链接检测服务
 	globalLinkCheckService services.LinkCheckService
+	// 全局内容源采集服务
+	globalContentSourceService *services.ContentSourceService
+	// 全局待处理资源流水线服务
+	globalReadyResourcePipelineService *services.ReadyResourcePipelineService
+	// 全局自动分类服务
+	globalClassificationService *services.ClassificationService
+	// 全局影视元数据服务
+	globalMetadataService *services.MetadataService
+	// 全局搜索引擎URL提交服务
+	globalSearchEngineSubmitService *services.SearchEngineSubmitService
+	// 全局Google索引覆盖率服务
+	globalGoogleIndexCoverageService *services.GoogleIndexCoverageService
+	// 全局OG图片服务
+	globalOGImageService *services.OGImageService
+	// 全局统计日汇总服务
+	globalAnalyticsService *services.AnalyticsService
+	// 全局备份服务
+	globalBackupService *services.BackupService
+	// 全局Telegram机器人服务（状态接口展示长轮询的持有节点）
+	globalTelegramBotService services.TelegramBotService
 )
 
 // SetGlobalMeilisearchManager 设置全局Meilisearch管理器
@@ -43,6 +65,116 @@ func GetGlobalLinkCheckService() services.LinkCheckService {
 	return globalLinkCheckService
 }
 
+// SetGlobalContentSourceService 设置全局内容源采集服务
+func SetGlobalContentSourceService(svc *services.ContentSourceService) {
+	globalContentSourceService = svc
+}
+
+// GetGlobalContentSourceService 获取全局内容源采集服务
+func GetGlobalContentSourceService() *services.ContentSourceService {
+	return globalContentSourceService
+}
+
+// SetGlobalReadyResourcePipelineService 设置全局待处理资源流水线服务
+func SetGlobalReadyResourcePipelineService(svc *services.ReadyResourcePipelineService) {
+	globalReadyResourcePipelineService = svc
+}
+
+// GetGlobalReadyResourcePipelineService 获取全局待处理资源流水线服务
+func GetGlobalReadyResourcePipelineService() *services.ReadyResourcePipelineService {
+	return globalReadyResourcePipelineService
+}
+
+// SetGlobalClassificationService 设置全局自动分类服务
+func SetGlobalClassificationService(svc *services.ClassificationService) {
+	globalClassificationService = svc
+}
+
+// GetGlobalClassificationService 获取全局自动分类服务
+func GetGlobalClassificationService() *services.ClassificationService {
+	return globalClassificationService
+}
+
+// SetGlobalMetadataService 设置全局影视元数据服务
+func SetGlobalMetadataService(svc *services.MetadataService) {
+	globalMetadataService = svc
+}
+
+// GetGlobalMetadataService 获取全局影视元数据服务
+func GetGlobalMetadataService() *services.MetadataService {
+	return globalMetadataService
+}
+
+// SetGlobalSearchEngineSubmitService 设置全局搜索引擎URL提交服务
+func SetGlobalSearchEngineSubmitService(svc *services.SearchEngineSubmitService) {
+	globalSearchEngineSubmitService = svc
+}
+
+// GetGlobalSearchEngineSubmitService 获取全局搜索引擎URL提交服务
+func GetGlobalSearchEngineSubmitService() *services.SearchEngineSubmitService {
+	return globalSearchEngineSubmitService
+}
+
+// SetGlobalGoogleIndexCoverageService 设置全局Google索引覆盖率服务
+func SetGlobalGoogleIndexCoverageService(svc *services.GoogleIndexCoverageService) {
+	globalGoogleIndexCoverageService = svc
+}
+
+// GetGlobalGoogleIndexCoverageService 获取全局Google索引覆盖率服务
+func GetGlobalGoogleIndexCoverageService() *services.GoogleIndexCoverageService {
+	return globalGoogleIndexCoverageService
+}
+
+// SetGlobalOGImageService 设置全局OG图片服务
+func SetGlobalOGImageService(svc *services.OGImageService) {
+	globalOGImageService = svc
+}
+
+// GetGlobalOGImageService 获取全局OG图片服务
+func GetGlobalOGImageService() *services.OGImageService {
+	return globalOGImageService
+}
+
+// SetGlobalAnalyticsService 设置全局统计日汇总服务
+func SetGlobalAnalyticsService(svc *services.AnalyticsService) {
+	globalAnalyticsService = svc
+}
+
+// GetGlobalAnalyticsService 获取全局统计日汇总服务
+func GetGlobalAnalyticsService() *services.AnalyticsService {
+	return globalAnalyticsService
+}
+
+// SetGlobalBackupService 设置全局备份服务
+func SetGlobalBackupService(svc *services.BackupService) {
+	globalBackupService = svc
+}
+
+// GetGlobalBackupService 获取全局备份服务
+func GetGlobalBackupService() *services.BackupService {
+	return globalBackupService
+}
+
+// SetGlobalElector 设置全局选主器，设置后各调度任务只在持有锁的节点运行；需在 GetGlobalScheduler 之前设置
+func SetGlobalElector(elector *leader.Elector) {
+	leader.SetDefault(elector)
+}
+
+// GetGlobalElector 获取全局选主器
+func GetGlobalElector() *leader.Elector {
+	return leader.Default()
+}
+
+// SetGlobalTelegramBotService 设置全局Telegram机器人服务
+func SetGlobalTelegramBotService(svc services.TelegramBotService) {
+	globalTelegramBotService = svc
+}
+
+// GetGlobalTelegramBotService 获取全局Telegram机器人服务
+func GetGlobalTelegramBotService() services.TelegramBotService {
+	return globalTelegramBotService
+}
+
 // GetGlobalScheduler 获取全局调度器实例（单例模式）
 func GetGlobalScheduler(hotDramaRepo repo.HotDramaRepository, readyResourceRepo repo.ReadyResourceRepository, resourceRepo repo.ResourceRepository, systemConfigRepo repo.SystemConfigRepository, panRepo repo.PanRepository, cksRepo repo.CksRepository, tagRepo repo.TagRepository, categoryRepo repo.CategoryRepository, taskItemRepo repo.TaskItemRepository, taskRepo repo.TaskRepository) *GlobalScheduler {
 	once.Do(func() {
@@ -206,7 +338,7 @@ func (gs *GlobalScheduler) GetSitemapConfig() (bool, error) {
 	return gs.manager.GetSitemapConfig()
 }
 
-// TriggerSitemapGeneration 手动触发sitemap生成
+// TriggerSitemapGeneration 手动触发sitemap增量生成
 func (gs *GlobalScheduler) TriggerSitemapGeneration() {
 	gs.mutex.Lock()
 	defer gs.mutex.Unlock()
@@ -214,6 +346,14 @@ func (gs *GlobalScheduler) TriggerSitemapGeneration() {
 	gs.manager.TriggerSitemapGeneration()
 }
 
+// TriggerFullSitemapGeneration 手动触发sitemap全量生成
+func (gs *GlobalScheduler) TriggerFullSitemapGeneration() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	gs.manager.TriggerFullSitemapGeneration()
+}
+
 // StartGoogleIndexScheduler 启动Google索引调度任务
 func (gs *GlobalScheduler) StartGoogleIndexScheduler() {
 	gs.mutex.Lock()
@@ -303,3 +443,260 @@ func (gs *GlobalScheduler) UpdateSchedulerStatusWithCleanup(enabled bool) {
 		}
 	}
 }
+
+// StartContentSourceScheduler 启动内容源采集定时任务
+func (gs *GlobalScheduler) StartContentSourceScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsContentSourceRunning() {
+		utils.Debug("内容源采集任务已在运行中")
+		return
+	}
+
+	gs.manager.StartContentSourceScheduler()
+	utils.Info("全局调度器已启动内容源采集任务")
+}
+
+// StopContentSourceScheduler 停止内容源采集定时任务
+func (gs *GlobalScheduler) StopContentSourceScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsContentSourceRunning() {
+		utils.Debug("内容源采集任务未在运行")
+		return
+	}
+
+	gs.manager.StopContentSourceScheduler()
+	utils.Info("全局调度器已停止内容源采集任务")
+}
+
+// IsContentSourceSchedulerRunning 检查内容源采集任务是否在运行
+func (gs *GlobalScheduler) IsContentSourceSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsContentSourceRunning()
+}
+
+// StartMetadataScheduler 启动影视元数据定时任务
+func (gs *GlobalScheduler) StartMetadataScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsMetadataRunning() {
+		utils.Debug("影视元数据任务已在运行中")
+		return
+	}
+
+	gs.manager.StartMetadataScheduler()
+	utils.Info("全局调度器已启动影视元数据任务")
+}
+
+// StopMetadataScheduler 停止影视元数据定时任务
+func (gs *GlobalScheduler) StopMetadataScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsMetadataRunning() {
+		utils.Debug("影视元数据任务未在运行")
+		return
+	}
+
+	gs.manager.StopMetadataScheduler()
+	utils.Info("全局调度器已停止影视元数据任务")
+}
+
+// IsMetadataSchedulerRunning 检查影视元数据任务是否在运行
+func (gs *GlobalScheduler) IsMetadataSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsMetadataRunning()
+}
+
+// StartSearchEngineSubmitScheduler 启动搜索引擎URL提交定时任务
+func (gs *GlobalScheduler) StartSearchEngineSubmitScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsSearchEngineSubmitRunning() {
+		utils.Debug("搜索引擎URL提交任务已在运行中")
+		return
+	}
+
+	gs.manager.StartSearchEngineSubmitScheduler()
+	utils.Info("全局调度器已启动搜索引擎URL提交任务")
+}
+
+// StopSearchEngineSubmitScheduler 停止搜索引擎URL提交定时任务
+func (gs *GlobalScheduler) StopSearchEngineSubmitScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsSearchEngineSubmitRunning() {
+		utils.Debug("搜索引擎URL提交任务未在运行")
+		return
+	}
+
+	gs.manager.StopSearchEngineSubmitScheduler()
+	utils.Info("全局调度器已停止搜索引擎URL提交任务")
+}
+
+// IsSearchEngineSubmitSchedulerRunning 检查搜索引擎URL提交任务是否在运行
+func (gs *GlobalScheduler) IsSearchEngineSubmitSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsSearchEngineSubmitRunning()
+}
+
+// StartOGImagePrerenderScheduler 启动OG图片预生成定时任务
+func (gs *GlobalScheduler) StartOGImagePrerenderScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsOGImagePrerenderRunning() {
+		utils.Debug("OG图片预生成任务已在运行中")
+		return
+	}
+
+	gs.manager.StartOGImagePrerenderScheduler()
+	utils.Info("全局调度器已启动OG图片预生成任务")
+}
+
+// StopOGImagePrerenderScheduler 停止OG图片预生成定时任务
+func (gs *GlobalScheduler) StopOGImagePrerenderScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsOGImagePrerenderRunning() {
+		utils.Debug("OG图片预生成任务未在运行")
+		return
+	}
+
+	gs.manager.StopOGImagePrerenderScheduler()
+	utils.Info("全局调度器已停止OG图片预生成任务")
+}
+
+// IsOGImagePrerenderSchedulerRunning 检查OG图片预生成任务是否在运行
+func (gs *GlobalScheduler) IsOGImagePrerenderSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsOGImagePrerenderRunning()
+}
+
+// StartAnalyticsRollupScheduler 启动统计日汇总定时任务
+func (gs *GlobalScheduler) StartAnalyticsRollupScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsAnalyticsRollupRunning() {
+		utils.Debug("统计日汇总任务已在运行中")
+		return
+	}
+
+	gs.manager.StartAnalyticsRollupScheduler()
+	utils.Info("全局调度器已启动统计日汇总任务")
+}
+
+// StopAnalyticsRollupScheduler 停止统计日汇总定时任务
+func (gs *GlobalScheduler) StopAnalyticsRollupScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsAnalyticsRollupRunning() {
+		utils.Debug("统计日汇总任务未在运行")
+		return
+	}
+
+	gs.manager.StopAnalyticsRollupScheduler()
+	utils.Info("全局调度器已停止统计日汇总任务")
+}
+
+// IsAnalyticsRollupSchedulerRunning 检查统计日汇总任务是否在运行
+func (gs *GlobalScheduler) IsAnalyticsRollupSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsAnalyticsRollupRunning()
+}
+
+// StartBackupScheduler 启动定时备份任务
+func (gs *GlobalScheduler) StartBackupScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsBackupRunning() {
+		utils.Debug("定时备份任务已在运行中")
+		return
+	}
+
+	gs.manager.StartBackupScheduler()
+	utils.Info("全局调度器已启动定时备份任务")
+}
+
+// StopBackupScheduler 停止定时备份任务
+func (gs *GlobalScheduler) StopBackupScheduler() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsBackupRunning() {
+		utils.Debug("定时备份任务未在运行")
+		return
+	}
+
+	gs.manager.StopBackupScheduler()
+	utils.Info("全局调度器已停止定时备份任务")
+}
+
+// IsBackupSchedulerRunning 检查定时备份任务是否在运行
+func (gs *GlobalScheduler) IsBackupSchedulerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsBackupRunning()
+}
+
+// StartCacheCleaner 启动缓存清理任务
+func (gs *GlobalScheduler) StartCacheCleaner() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if gs.manager.IsCacheCleanerRunning() {
+		utils.Debug("缓存清理任务已在运行中")
+		return
+	}
+
+	gs.manager.StartCacheCleaner()
+	utils.Info("全局调度器已启动缓存清理任务")
+}
+
+// StopCacheCleaner 停止缓存清理任务
+func (gs *GlobalScheduler) StopCacheCleaner() {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+
+	if !gs.manager.IsCacheCleanerRunning() {
+		utils.Debug("缓存清理任务未在运行")
+		return
+	}
+
+	gs.manager.StopCacheCleaner()
+	utils.Info("全局调度器已停止缓存清理任务")
+}
+
+// IsCacheCleanerRunning 检查缓存清理任务是否在运行
+func (gs *GlobalScheduler) IsCacheCleanerRunning() bool {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.IsCacheCleanerRunning()
+}
+
+// GetLeaderStatus 获取各调度任务的持有节点，返回本节点 ID 与任务状态
+func (gs *GlobalScheduler) GetLeaderStatus(ctx context.Context) (string, []JobStatus) {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.manager.GetLeaderStatus(ctx)
+}
+
+// IsLeaderElectionEnabled 是否启用了多副本选主
+func (gs *GlobalScheduler) IsLeaderElectionEnabled() bool {
+	return gs.manager.elector != nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
 	"github.com/ctwj/urldb/pkg/google"
 	"github.com/ctwj/urldb/utils"
 )
@@ -18,8 +19,6 @@ import (
 type GoogleIndexScheduler struct {
 	*BaseScheduler
 	config        entity.SystemConfig
-	stopChan      chan bool
-	isRunning     bool
 	enabled       bool
 	checkInterval time.Duration
 	googleClient  *google.Client
@@ -37,15 +36,28 @@ func NewGoogleIndexScheduler(baseScheduler *BaseScheduler, taskItemRepo repo.Tas
 		BaseScheduler:     baseScheduler,
 		taskItemRepo:      taskItemRepo,
 		taskRepo:          taskRepo,
-		stopChan:          make(chan bool),
-		isRunning:         false,
 		pendingURLResults: make([]*repo.URLStatusResult, 0),
 	}
 }
 
+// cronJob Google索引任务定义，间隔取自配置的检查间隔（默认60分钟），启用时立即执行一次
+func (s *GoogleIndexScheduler) cronJob() cronjob.Job {
+	interval := s.checkInterval
+	if interval <= 0 {
+		interval = 60 * time.Minute
+	}
+	return cronjob.Job{
+		Name:        JobGoogleIndex,
+		Description: "提交sitemap并检查URL的Google索引状态",
+		Spec:        everySpec(interval),
+		CatchUp:     true,
+		Run:         s.performScheduledTasks,
+	}
+}
+
 // Start 启动Google索引调度任务
 func (s *GoogleIndexScheduler) Start() {
-	if s.isRunning {
+	if cronJobActive(JobGoogleIndex) {
 		utils.Debug("Google索引调度任务已在运行中")
 		return
 	}
@@ -61,45 +73,18 @@ func (s *GoogleIndexScheduler) Start() {
 		return
 	}
 
-	s.isRunning = true
 	utils.Info("开始启动Google索引调度任务，检查间隔: %v", s.checkInterval)
-
-	go s.run()
+	startCronJob(s.cronJob())
 }
 
 // Stop 停止Google索引调度任务
 func (s *GoogleIndexScheduler) Stop() {
-	if !s.isRunning {
-		return
-	}
-
-	utils.Info("正在停止Google索引调度任务...")
-	s.stopChan <- true
-	s.isRunning = false
+	stopCronJob(JobGoogleIndex)
 }
 
 // IsRunning 检查调度器是否正在运行
 func (s *GoogleIndexScheduler) IsRunning() bool {
-	return s.isRunning
-}
-
-// run 运行调度器主循环
-func (s *GoogleIndexScheduler) run() {
-	ticker := time.NewTicker(s.checkInterval)
-	defer ticker.Stop()
-
-	// 启动时立即执行一次
-	s.performScheduledTasks()
-
-	for {
-		select {
-		case <-s.stopChan:
-			utils.Info("Google索引调度任务已停止")
-			return
-		case <-ticker.C:
-			s.performScheduledTasks()
-		}
-	}
+	return cronJobActive(JobGoogleIndex)
 }
 
 // loadConfig 加载配置
@@ -165,25 +150,28 @@ func (s *GoogleIndexScheduler) initGoogleClient() error {
 }
 
 // performScheduledTasks 执行调度任务
-func (s *GoogleIndexScheduler) performScheduledTasks() {
+func (s *GoogleIndexScheduler) performScheduledTasks(ctx context.Context) (string, error) {
 	if !s.enabled {
-		return
+		return "Google索引功能未启用", cronjob.ErrSkipped
 	}
 
-	ctx := context.Background()
 	now := time.Now()
+	var done, failed []string
 
 	// 任务0: 清理旧记录
 	if err := s.taskItemRepo.CleanupOldRecords(); err != nil {
 		utils.Error("清理旧记录失败: %v", err)
+		failed = append(failed, fmt.Sprintf("清理旧记录: %v", err))
 	}
 
 	// 任务1: 智能sitemap提交策略
 	if s.shouldSubmitSitemap(now) {
 		if err := s.submitSitemapToGoogle(ctx); err != nil {
 			utils.Error("提交sitemap失败: %v", err)
+			failed = append(failed, fmt.Sprintf("提交sitemap: %v", err))
 		} else {
 			s.updateLastSitemapSubmitTime()
+			done = append(done, "提交sitemap")
 		}
 	}
 
@@ -191,6 +179,9 @@ func (s *GoogleIndexScheduler) performScheduledTasks() {
 	if s.shouldCheckURLStatus(now) {
 		if err := s.checkNewURLsStatus(ctx); err != nil {
 			utils.Error("检查新URL状态失败: %v", err)
+			failed = append(failed, fmt.Sprintf("检查URL状态: %v", err))
+		} else {
+			done = append(done, "检查URL状态")
 		}
 	}
 
@@ -198,6 +189,11 @@ func (s *GoogleIndexScheduler) performScheduledTasks() {
 	s.flushURLResults()
 
 	utils.Debug("Google索引调度任务执行完成")
+	summary := strings.Join(done, ", ")
+	if len(failed) > 0 {
+		return summary, fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return summary, nil
 }
 
 // submitSitemapToGoogle 提交sitemap给Google
@@ -547,11 +543,6 @@ func (s *GoogleIndexScheduler) getErrorMessage(result *google.URLInspectionResul
 	return ""
 }
 
-// SetRunning 设置运行状态
-func (s *GoogleIndexScheduler) SetRunning(running bool) {
-	s.isRunning = running
-}
-
 // shouldSubmitSitemap 判断是否应该提交sitemap
 func (s *GoogleIndexScheduler) shouldSubmitSitemap(now time.Time) bool {
 	// 获取上次提交时间
@@ -634,12 +625,21 @@ func (s *GoogleIndexScheduler) updateLastURLCheckTime() {
 func (s *GoogleIndexScheduler) checkNewURLsStatus(ctx context.Context) error {
 	utils.Info("开始检查新URL状态...")
 
-	// 暂时跳过新URL检查，因为GetRecentResources方法不存在
-	// TODO: 后续可以添加获取最近资源的逻辑
-	utils.Info("新URL检查功能暂时跳过")
-
-	// 仍然更新检查时间，避免重复尝试
-	s.updateLastURLCheckTime()
+	// 无论成功与否都更新检查时间，避免重复尝试
+	defer s.updateLastURLCheckTime()
 
+	// 优先检查从未检查和已过期的URL，并自动重新提交已更新的“已抓取未索引”页面
+	coverageService := GetGlobalGoogleIndexCoverageService()
+	if coverageService == nil {
+		utils.Info("Google索引覆盖率服务未初始化，跳过新URL检查")
+		return nil
+	}
+	result, err := coverageService.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if result.RateLimited {
+		utils.Warn("URL检查触发Google速率限制，已提前结束: %s", result.Error)
+	}
 	return nil
 }
\ No newline at end of file
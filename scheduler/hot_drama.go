@@ -1,12 +1,13 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/cronjob"
 	"github.com/ctwj/urldb/utils"
 )
 
@@ -14,7 +15,6 @@ import (
 type HotDramaScheduler struct {
 	*BaseScheduler
 	doubanService *utils.DoubanService
-	hotDramaMutex sync.Mutex // 防止热播剧任务重叠执行
 }
 
 // NewHotDramaScheduler 创建热播剧调度器
@@ -22,69 +22,51 @@ func NewHotDramaScheduler(base *BaseScheduler) *HotDramaScheduler {
 	return &HotDramaScheduler{
 		BaseScheduler: base,
 		doubanService: utils.NewDoubanService(),
-		hotDramaMutex: sync.Mutex{},
 	}
 }
 
-// Start 启动热播剧定时任务
-func (h *HotDramaScheduler) Start() {
-	if h.IsRunning() {
-		utils.Info("热播剧定时任务已在运行中")
-		return
+// hotDramaInterval 热播剧数据刷新周期
+const hotDramaInterval = 12 * time.Hour
+
+// cronJob 热播剧任务定义，启用时立即补跑一次
+func (h *HotDramaScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobHotDrama,
+		Description: "抓取豆瓣热播剧与榜单数据",
+		Spec:        everySpec(hotDramaInterval),
+		Jitter:      time.Minute,
+		CatchUp:     true,
+		Run: func(context.Context) (string, error) {
+			return h.fetchHotDramaData()
+		},
 	}
+}
 
-	h.SetRunning(true)
-	utils.Info("启动热播剧定时任务")
-
-	go func() {
-		ticker := time.NewTicker(12 * time.Hour) // 每12小时执行一次
-		defer ticker.Stop()
-
-		// 立即执行一次
-		h.fetchHotDramaData()
-
-		for {
-			select {
-			case <-ticker.C:
-				// 使用TryLock防止任务重叠执行
-				if h.hotDramaMutex.TryLock() {
-					go func() {
-						defer h.hotDramaMutex.Unlock()
-						h.fetchHotDramaData()
-					}()
-				} else {
-					utils.Info("上一次热播剧任务还在执行中，跳过本次执行")
-				}
-			case <-h.GetStopChan():
-				utils.Info("停止热播剧定时任务")
-				return
-			}
-		}
-	}()
+// Start 启动热播剧定时任务
+func (h *HotDramaScheduler) Start() {
+	startCronJob(h.cronJob())
 }
 
 // Stop 停止热播剧定时任务
 func (h *HotDramaScheduler) Stop() {
-	if !h.IsRunning() {
-		utils.Info("热播剧定时任务未在运行")
-		return
-	}
+	stopCronJob(JobHotDrama)
+}
 
-	h.GetStopChan() <- true
-	h.SetRunning(false)
-	utils.Info("已发送停止信号给热播剧定时任务")
+// IsRunning 检查热播剧定时任务是否在运行
+func (h *HotDramaScheduler) IsRunning() bool {
+	return cronJobActive(JobHotDrama)
 }
 
 // fetchHotDramaData 获取热播剧数据
-func (h *HotDramaScheduler) fetchHotDramaData() {
+func (h *HotDramaScheduler) fetchHotDramaData() (string, error) {
 	utils.Info("开始获取热播剧数据...")
 
 	// 直接处理电影和电视剧数据，不再需要FetchHotDramaNames
-	h.processHotDramaNames([]string{})
+	return h.processHotDramaNames([]string{})
 }
 
 // processHotDramaNames 处理热播剧名称
-func (h *HotDramaScheduler) processHotDramaNames(dramaNames []string) {
+func (h *HotDramaScheduler) processHotDramaNames(dramaNames []string) (string, error) {
 	utils.Info("开始处理热播剧数据，共 %d 个", len(dramaNames))
 
 	// 收集所有数据
@@ -119,7 +101,7 @@ func (h *HotDramaScheduler) processHotDramaNames(dramaNames []string) {
 	utils.Info("准备清空数据库，当前共有 %d 条数据", len(allDramas))
 	if err := h.hotDramaRepo.DeleteAll(); err != nil {
 		utils.Error(fmt.Sprintf("清空数据库失败: %v", err))
-		return
+		return "", fmt.Errorf("清空热播剧数据失败: %v", err)
 	}
 	utils.Info("数据库清空完成")
 
@@ -128,6 +110,7 @@ func (h *HotDramaScheduler) processHotDramaNames(dramaNames []string) {
 		utils.Info("开始批量插入 %d 条数据", len(allDramas))
 		if err := h.hotDramaRepo.BatchCreate(allDramas); err != nil {
 			utils.Error(fmt.Sprintf("批量插入数据失败: %v", err))
+			return "", fmt.Errorf("写入热播剧数据失败: %v", err)
 		} else {
 			utils.Info("成功批量插入 %d 条数据", len(allDramas))
 		}
@@ -136,6 +119,7 @@ func (h *HotDramaScheduler) processHotDramaNames(dramaNames []string) {
 	}
 
 	utils.Info("热播剧数据处理完成")
+	return fmt.Sprintf("热播剧=%d", len(allDramas)), nil
 }
 
 // processRecentMovies 处理最近热门电影数据
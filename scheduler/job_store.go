@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
+)
+
+// jobStore 把任务调度器的执行记录与状态保存到数据库
+type jobStore struct {
+	repo repo.ScheduledJobRepository
+}
+
+// NewJobStore 创建基于数据库的任务存储
+func NewJobStore(r repo.ScheduledJobRepository) cronjob.Store {
+	return &jobStore{repo: r}
+}
+
+// LoadState 读取任务状态，没有记录时返回零值
+func (s *jobStore) LoadState(_ context.Context, job string) (cronjob.State, error) {
+	state, err := s.repo.GetState(job)
+	if err != nil || state == nil {
+		return cronjob.State{}, err
+	}
+	return cronjob.State{Spec: state.Spec, Paused: state.Paused}, nil
+}
+
+// SaveState 保存任务状态
+func (s *jobStore) SaveState(_ context.Context, job string, state cronjob.State) error {
+	return s.repo.SaveState(&entity.ScheduledJobState{
+		Name:      job,
+		Spec:      state.Spec,
+		Paused:    state.Paused,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// LastRun 读取最近一次执行记录
+func (s *jobStore) LastRun(_ context.Context, job string) (*cronjob.Run, error) {
+	run, err := s.repo.LastRun(job)
+	if err != nil || run == nil {
+		return nil, err
+	}
+	result := RunFromEntity(*run)
+	return &result, nil
+}
+
+// SaveRun 写入执行记录
+func (s *jobStore) SaveRun(_ context.Context, run *cronjob.Run) error {
+	record := entity.ScheduledJobRun{
+		Job:        run.Job,
+		Trigger:    run.Trigger,
+		Node:       run.Node,
+		Status:     run.Status,
+		Summary:    run.Summary,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.DurationMs,
+	}
+	if err := s.repo.Create(&record); err != nil {
+		return err
+	}
+	run.ID = record.ID
+	return nil
+}
+
+// PruneRuns 删除过期的执行记录
+func (s *jobStore) PruneRuns(_ context.Context, before time.Time) error {
+	_, err := s.repo.DeleteRunsBefore(before)
+	return err
+}
+
+// RunFromEntity 数据库记录转换为执行记录
+func RunFromEntity(run entity.ScheduledJobRun) cronjob.Run {
+	return cronjob.Run{
+		ID:         run.ID,
+		Job:        run.Job,
+		Trigger:    run.Trigger,
+		Node:       run.Node,
+		Status:     run.Status,
+		Summary:    run.Summary,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		DurationMs: run.DurationMs,
+	}
+}
@@ -1,7 +1,11 @@
 package scheduler
 
 import (
+	"context"
+
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/leader"
 	"github.com/ctwj/urldb/services"
 	"github.com/ctwj/urldb/utils"
 )
@@ -10,13 +14,63 @@ var _ = services.NewCleanupService // 保留 services 导入（用于内部创
 
 // Manager 调度器管理器
 type Manager struct {
-	baseScheduler          *BaseScheduler
-	hotDramaScheduler      *HotDramaScheduler
-	readyResourceScheduler *ReadyResourceScheduler
-	sitemapScheduler       *SitemapScheduler
-	googleIndexScheduler   *GoogleIndexScheduler
-	cleanupScheduler       *CleanupScheduler
-	xunleiKeepaliveScheduler *XunleiKeepaliveScheduler
+	baseScheduler               *BaseScheduler
+	hotDramaScheduler           *HotDramaScheduler
+	readyResourceScheduler      *ReadyResourceScheduler
+	sitemapScheduler            *SitemapScheduler
+	googleIndexScheduler        *GoogleIndexScheduler
+	cleanupScheduler            *CleanupScheduler
+	xunleiKeepaliveScheduler    *XunleiKeepaliveScheduler
+	contentSourceScheduler      *ContentSourceScheduler
+	metadataScheduler           *MetadataScheduler
+	searchEngineSubmitScheduler *SearchEngineSubmitScheduler
+	ogImagePrerenderScheduler   *OGImagePrerenderScheduler
+	analyticsRollupScheduler    *AnalyticsRollupScheduler
+	backupScheduler             *BackupScheduler
+	cacheCleaner                *CacheCleaner
+
+	// elector 多副本选主，为空时调度任务直接在本节点启停
+	elector *leader.Elector
+}
+
+// 调度任务名，同时用作选主的锁名、任务调度器中的任务名与状态接口的键
+const (
+	JobHotDrama           = "hot_drama"
+	JobReadyResource      = "ready_resource"
+	JobSitemap            = "sitemap"
+	JobGoogleIndex        = "google_index"
+	JobCleanup            = "cleanup"
+	JobXunleiKeepalive    = "xunlei_keepalive"
+	JobContentSource      = "content_source"
+	JobMetadata           = "metadata"
+	JobSearchEngineSubmit = "search_engine_submit"
+	JobOGImagePrerender   = "og_image_prerender"
+	JobAnalyticsRollup    = "analytics_rollup"
+	JobBackup             = "backup"
+	JobCacheCleanup       = "cache_cleanup" // 每个节点各自执行，不参与选主
+)
+
+// jobNames 全部调度任务，状态接口按此顺序输出
+var jobNames = []string{
+	JobHotDrama,
+	JobReadyResource,
+	JobSitemap,
+	JobGoogleIndex,
+	JobCleanup,
+	JobXunleiKeepalive,
+	JobContentSource,
+	JobMetadata,
+	JobSearchEngineSubmit,
+	JobOGImagePrerender,
+	JobAnalyticsRollup,
+	JobBackup,
+	JobCacheCleanup,
+}
+
+// job 可启停的调度任务
+type job interface {
+	Start()
+	Stop()
 }
 
 // NewManager 创建调度器管理器
@@ -54,16 +108,77 @@ func NewManager(
 	googleIndexScheduler := NewGoogleIndexScheduler(baseScheduler, taskItemRepo, taskRepo)
 	cleanupScheduler := NewCleanupScheduler(baseScheduler, cleanupService)
 	xunleiKeepaliveScheduler := NewXunleiKeepaliveScheduler(baseScheduler)
+	contentSourceScheduler := NewContentSourceScheduler(baseScheduler)
+	metadataScheduler := NewMetadataScheduler(baseScheduler)
+	searchEngineSubmitScheduler := NewSearchEngineSubmitScheduler(baseScheduler)
+	ogImagePrerenderScheduler := NewOGImagePrerenderScheduler(baseScheduler)
+	analyticsRollupScheduler := NewAnalyticsRollupScheduler(baseScheduler)
+	backupScheduler := NewBackupScheduler(baseScheduler)
+	cacheCleaner := NewCacheCleaner(baseScheduler)
+
+	// 先注册全部任务（未启用），任务列表中可见并可手动执行，启用由各 Start 方法负责
+	for _, j := range []cronjob.Job{
+		hotDramaScheduler.cronJob(),
+		readyResourceScheduler.cronJob(),
+		sitemapScheduler.cronJob(),
+		googleIndexScheduler.cronJob(),
+		cleanupScheduler.cronJob(),
+		xunleiKeepaliveScheduler.cronJob(),
+		contentSourceScheduler.cronJob(),
+		metadataScheduler.cronJob(),
+		searchEngineSubmitScheduler.cronJob(),
+		ogImagePrerenderScheduler.cronJob(),
+		analyticsRollupScheduler.cronJob(),
+		backupScheduler.cronJob(),
+		cacheCleaner.cronJob(),
+	} {
+		registerCronJob(j)
+	}
 
 	return &Manager{
-		baseScheduler:            baseScheduler,
-		hotDramaScheduler:        hotDramaScheduler,
-		readyResourceScheduler:   readyResourceScheduler,
-		sitemapScheduler:         sitemapScheduler,
-		googleIndexScheduler:     googleIndexScheduler,
-		cleanupScheduler:         cleanupScheduler,
-		xunleiKeepaliveScheduler: xunleiKeepaliveScheduler,
+		baseScheduler:               baseScheduler,
+		hotDramaScheduler:           hotDramaScheduler,
+		readyResourceScheduler:      readyResourceScheduler,
+		sitemapScheduler:            sitemapScheduler,
+		googleIndexScheduler:        googleIndexScheduler,
+		cleanupScheduler:            cleanupScheduler,
+		xunleiKeepaliveScheduler:    xunleiKeepaliveScheduler,
+		contentSourceScheduler:      contentSourceScheduler,
+		metadataScheduler:           metadataScheduler,
+		searchEngineSubmitScheduler: searchEngineSubmitScheduler,
+		ogImagePrerenderScheduler:   ogImagePrerenderScheduler,
+		analyticsRollupScheduler:    analyticsRollupScheduler,
+		backupScheduler:             backupScheduler,
+		cacheCleaner:                cacheCleaner,
+		elector:                     GetGlobalElector(),
+	}
+}
+
+// startJob 启用调度任务：配置了选主时参与竞选，成为主节点后才在本节点启动
+func (m *Manager) startJob(name string, j job) {
+	if m.elector == nil {
+		j.Start()
+		return
 	}
+	m.elector.Run(name, j.Start, j.Stop)
+}
+
+// stopJob 停用调度任务：退出竞选，本节点为主节点时停止任务并释放锁
+func (m *Manager) stopJob(name string, j job) {
+	if m.elector == nil {
+		j.Stop()
+		return
+	}
+	m.elector.Resign(name)
+}
+
+// jobEnabled 调度任务是否在本节点启用。配置了选主时以是否参与竞选为准，
+// 从节点上任务未实际运行但仍视为启用，避免按配置重复启动或漏掉停用
+func (m *Manager) jobEnabled(name string, running func() bool) bool {
+	if m.elector == nil {
+		return running()
+	}
+	return m.elector.Active(name)
 }
 
 // StartAll 启动所有调度任务
@@ -74,13 +189,31 @@ func (m *Manager) StartAll() {
 	m.StartHotDramaScheduler()
 
 	// 启动待处理资源调度任务
-	m.readyResourceScheduler.Start()
+	m.StartReadyResourceScheduler()
 
 	// 启动Google索引调度任务
-	m.googleIndexScheduler.Start()
+	m.StartGoogleIndexScheduler()
 
 	// 启动迅雷 token 保活任务
-	m.xunleiKeepaliveScheduler.Start()
+	m.StartXunleiKeepaliveScheduler()
+
+	// 启动内容源采集任务
+	m.StartContentSourceScheduler()
+
+	// 启动影视元数据任务
+	m.StartMetadataScheduler()
+
+	// 启动搜索引擎URL提交任务
+	m.StartSearchEngineSubmitScheduler()
+
+	// 启动OG图片预生成任务
+	m.StartOGImagePrerenderScheduler()
+
+	// 启动统计日汇总任务
+	m.StartAnalyticsRollupScheduler()
+
+	// 启动缓存清理任务
+	m.StartCacheCleaner()
 
 	utils.Debug("所有调度任务已启动")
 }
@@ -93,45 +226,81 @@ func (m *Manager) StopAll() {
 	m.StopHotDramaScheduler()
 
 	// 停止待处理资源调度任务
-	m.readyResourceScheduler.Stop()
+	m.StopReadyResourceScheduler()
 
 	// 停止Google索引调度任务
-	m.googleIndexScheduler.Stop()
+	m.StopGoogleIndexScheduler()
 
 	// 停止迅雷 token 保活任务
-	m.xunleiKeepaliveScheduler.Stop()
+	m.StopXunleiKeepaliveScheduler()
+
+	// 停止内容源采集任务
+	m.StopContentSourceScheduler()
+
+	// 停止影视元数据任务
+	m.StopMetadataScheduler()
+
+	// 停止搜索引擎URL提交任务
+	m.StopSearchEngineSubmitScheduler()
+
+	// 停止OG图片预生成任务
+	m.StopOGImagePrerenderScheduler()
+
+	// 停止统计日汇总任务
+	m.StopAnalyticsRollupScheduler()
+
+	// 停止定时备份任务
+	m.StopBackupScheduler()
+
+	// 停止缓存清理任务
+	m.StopCacheCleaner()
 
 	utils.Debug("所有调度任务已停止")
 }
 
 // StartHotDramaScheduler 启动热播剧调度任务
 func (m *Manager) StartHotDramaScheduler() {
-	m.hotDramaScheduler.Start()
+	m.startJob(JobHotDrama, m.hotDramaScheduler)
 }
 
 // StopHotDramaScheduler 停止热播剧调度任务
 func (m *Manager) StopHotDramaScheduler() {
-	m.hotDramaScheduler.Stop()
+	m.stopJob(JobHotDrama, m.hotDramaScheduler)
 }
 
 // IsHotDramaRunning 检查热播剧调度任务是否正在运行
 func (m *Manager) IsHotDramaRunning() bool {
-	return m.hotDramaScheduler.IsRunning()
+	return m.jobEnabled(JobHotDrama, m.hotDramaScheduler.IsRunning)
+}
+
+// StartXunleiKeepaliveScheduler 启动迅雷 token 保活任务
+func (m *Manager) StartXunleiKeepaliveScheduler() {
+	m.startJob(JobXunleiKeepalive, m.xunleiKeepaliveScheduler)
+}
+
+// StopXunleiKeepaliveScheduler 停止迅雷 token 保活任务
+func (m *Manager) StopXunleiKeepaliveScheduler() {
+	m.stopJob(JobXunleiKeepalive, m.xunleiKeepaliveScheduler)
+}
+
+// IsXunleiKeepaliveRunning 检查迅雷 token 保活任务是否在运行
+func (m *Manager) IsXunleiKeepaliveRunning() bool {
+	return m.jobEnabled(JobXunleiKeepalive, m.xunleiKeepaliveScheduler.IsRunning)
 }
 
 // StartReadyResourceScheduler 启动待处理资源调度任务
 func (m *Manager) StartReadyResourceScheduler() {
-	m.readyResourceScheduler.Start()
+	m.startJob(JobReadyResource, m.readyResourceScheduler)
 }
 
 // StopReadyResourceScheduler 停止待处理资源调度任务
 func (m *Manager) StopReadyResourceScheduler() {
-	m.readyResourceScheduler.Stop()
+	m.stopJob(JobReadyResource, m.readyResourceScheduler)
 }
 
 // IsReadyResourceRunning 检查待处理资源调度任务是否正在运行
 func (m *Manager) IsReadyResourceRunning() bool {
-	return m.readyResourceScheduler.IsReadyResourceRunning()
+	return m.jobEnabled(JobReadyResource, m.readyResourceScheduler.IsReadyResourceRunning)
 }
 
 // GetHotDramaNames 获取热播剧名称列表
@@ -141,17 +310,17 @@ func (m *Manager) GetHotDramaNames() ([]string, error) {
 
 // StartSitemapScheduler 启动Sitemap调度任务
 func (m *Manager) StartSitemapScheduler() {
-	m.sitemapScheduler.Start()
+	m.startJob(JobSitemap, m.sitemapScheduler)
 }
 
 // StopSitemapScheduler 停止Sitemap调度任务
 func (m *Manager) StopSitemapScheduler() {
-	m.sitemapScheduler.Stop()
+	m.stopJob(JobSitemap, m.sitemapScheduler)
 }
 
 // IsSitemapRunning 检查Sitemap调度任务是否在运行
 func (m *Manager) IsSitemapRunning() bool {
-	return m.sitemapScheduler.IsRunning()
+	return m.jobEnabled(JobSitemap, m.sitemapScheduler.IsRunning)
 }
 
 // GetSitemapConfig 获取Sitemap配置
@@ -164,49 +333,215 @@ func (m *Manager) UpdateSitemapConfig(enabled bool) error {
 	return m.sitemapScheduler.UpdateSitemapConfig(enabled)
 }
 
-// TriggerSitemapGeneration 手动触发sitemap生成
+// TriggerSitemapGeneration 手动触发sitemap增量生成
 func (m *Manager) TriggerSitemapGeneration() {
-	go m.sitemapScheduler.generateSitemap()
+	go m.sitemapScheduler.generateSitemap(context.Background(), false)
+}
+
+// TriggerFullSitemapGeneration 手动触发sitemap全量生成
+func (m *Manager) TriggerFullSitemapGeneration() {
+	go m.sitemapScheduler.generateSitemap(context.Background(), true)
 }
 
 // StartGoogleIndexScheduler 启动Google索引调度任务
 func (m *Manager) StartGoogleIndexScheduler() {
-	m.googleIndexScheduler.Start()
+	m.startJob(JobGoogleIndex, m.googleIndexScheduler)
 }
 
 // StopGoogleIndexScheduler 停止Google索引调度任务
 func (m *Manager) StopGoogleIndexScheduler() {
-	m.googleIndexScheduler.Stop()
+	m.stopJob(JobGoogleIndex, m.googleIndexScheduler)
 }
 
 // IsGoogleIndexRunning 检查Google索引调度任务是否在运行
 func (m *Manager) IsGoogleIndexRunning() bool {
-	return m.googleIndexScheduler.IsRunning()
+	return m.jobEnabled(JobGoogleIndex, m.googleIndexScheduler.IsRunning)
 }
 
 // StartCleanupScheduler 启动转存文件自动清理调度任务
 func (m *Manager) StartCleanupScheduler() {
-	m.cleanupScheduler.Start()
+	m.startJob(JobCleanup, m.cleanupScheduler)
 }
 
 // StopCleanupScheduler 停止转存文件自动清理调度任务
 func (m *Manager) StopCleanupScheduler() {
-	m.cleanupScheduler.Stop()
+	m.stopJob(JobCleanup, m.cleanupScheduler)
 }
 
 // IsCleanupRunning 检查转存文件自动清理调度任务是否在运行
 func (m *Manager) IsCleanupRunning() bool {
-	return m.cleanupScheduler.IsCleanupRunning()
+	return m.jobEnabled(JobCleanup, m.cleanupScheduler.IsCleanupRunning)
+}
+
+// StartContentSourceScheduler 启动内容源采集调度任务
+func (m *Manager) StartContentSourceScheduler() {
+	m.startJob(JobContentSource, m.contentSourceScheduler)
+}
+
+// StopContentSourceScheduler 停止内容源采集调度任务
+func (m *Manager) StopContentSourceScheduler() {
+	m.stopJob(JobContentSource, m.contentSourceScheduler)
+}
+
+// IsContentSourceRunning 检查内容源采集调度任务是否在运行
+func (m *Manager) IsContentSourceRunning() bool {
+	return m.jobEnabled(JobContentSource, m.contentSourceScheduler.IsRunning)
+}
+
+// StartMetadataScheduler 启动影视元数据调度任务
+func (m *Manager) StartMetadataScheduler() {
+	m.startJob(JobMetadata, m.metadataScheduler)
+}
+
+// StopMetadataScheduler 停止影视元数据调度任务
+func (m *Manager) StopMetadataScheduler() {
+	m.stopJob(JobMetadata, m.metadataScheduler)
+}
+
+// IsMetadataRunning 检查影视元数据调度任务是否在运行
+func (m *Manager) IsMetadataRunning() bool {
+	return m.jobEnabled(JobMetadata, m.metadataScheduler.IsRunning)
+}
+
+// StartSearchEngineSubmitScheduler 启动搜索引擎URL提交调度任务
+func (m *Manager) StartSearchEngineSubmitScheduler() {
+	m.startJob(JobSearchEngineSubmit, m.searchEngineSubmitScheduler)
+}
+
+// StopSearchEngineSubmitScheduler 停止搜索引擎URL提交调度任务
+func (m *Manager) StopSearchEngineSubmitScheduler() {
+	m.stopJob(JobSearchEngineSubmit, m.searchEngineSubmitScheduler)
+}
+
+// IsSearchEngineSubmitRunning 检查搜索引擎URL提交调度任务是否在运行
+func (m *Manager) IsSearchEngineSubmitRunning() bool {
+	return m.jobEnabled(JobSearchEngineSubmit, m.searchEngineSubmitScheduler.IsRunning)
+}
+
+// StartOGImagePrerenderScheduler 启动OG图片预生成调度任务
+func (m *Manager) StartOGImagePrerenderScheduler() {
+	m.startJob(JobOGImagePrerender, m.ogImagePrerenderScheduler)
+}
+
+// StopOGImagePrerenderScheduler 停止OG图片预生成调度任务
+func (m *Manager) StopOGImagePrerenderScheduler() {
+	m.stopJob(JobOGImagePrerender, m.ogImagePrerenderScheduler)
+}
+
+// IsOGImagePrerenderRunning 检查OG图片预生成调度任务是否在运行
+func (m *Manager) IsOGImagePrerenderRunning() bool {
+	return m.jobEnabled(JobOGImagePrerender, m.ogImagePrerenderScheduler.IsRunning)
+}
+
+// StartAnalyticsRollupScheduler 启动统计日汇总调度任务
+func (m *Manager) StartAnalyticsRollupScheduler() {
+	m.startJob(JobAnalyticsRollup, m.analyticsRollupScheduler)
+}
+
+// StopAnalyticsRollupScheduler 停止统计日汇总调度任务
+func (m *Manager) StopAnalyticsRollupScheduler() {
+	m.stopJob(JobAnalyticsRollup, m.analyticsRollupScheduler)
+}
+
+// IsAnalyticsRollupRunning 检查统计日汇总调度任务是否在运行
+func (m *Manager) IsAnalyticsRollupRunning() bool {
+	return m.jobEnabled(JobAnalyticsRollup, m.analyticsRollupScheduler.IsRunning)
+}
+
+// StartBackupScheduler 启动定时备份调度任务
+func (m *Manager) StartBackupScheduler() {
+	m.startJob(JobBackup, m.backupScheduler)
+}
+
+// StopBackupScheduler 停止定时备份调度任务
+func (m *Manager) StopBackupScheduler() {
+	m.stopJob(JobBackup, m.backupScheduler)
+}
+
+// IsBackupRunning 检查定时备份调度任务是否在运行
+func (m *Manager) IsBackupRunning() bool {
+	return m.jobEnabled(JobBackup, m.backupScheduler.IsRunning)
+}
+
+// StartCacheCleaner 启动缓存清理任务。清理的是本进程的本地缓存层，每个节点各自执行，不参与选主
+func (m *Manager) StartCacheCleaner() {
+	m.cacheCleaner.Start()
+}
+
+// StopCacheCleaner 停止缓存清理任务
+func (m *Manager) StopCacheCleaner() {
+	m.cacheCleaner.Stop()
+}
+
+// IsCacheCleanerRunning 检查缓存清理任务是否在运行
+func (m *Manager) IsCacheCleanerRunning() bool {
+	return m.cacheCleaner.IsRunning()
 }
 
 // GetStatus 获取所有调度任务的状态
 func (m *Manager) GetStatus() map[string]bool {
 	return map[string]bool{
-		"hot_drama":        m.IsHotDramaRunning(),
-		"ready_resource":   m.IsReadyResourceRunning(),
-		"sitemap":          m.IsSitemapRunning(),
-		"google_index":     m.IsGoogleIndexRunning(),
-		"cleanup":          m.IsCleanupRunning(),
-		"xunlei_keepalive": m.xunleiKeepaliveScheduler.IsRunning(),
+		JobHotDrama:           m.IsHotDramaRunning(),
+		JobReadyResource:      m.IsReadyResourceRunning(),
+		JobSitemap:            m.IsSitemapRunning(),
+		JobGoogleIndex:        m.IsGoogleIndexRunning(),
+		JobCleanup:            m.IsCleanupRunning(),
+		JobXunleiKeepalive:    m.IsXunleiKeepaliveRunning(),
+		JobContentSource:      m.IsContentSourceRunning(),
+		JobMetadata:           m.IsMetadataRunning(),
+		JobSearchEngineSubmit: m.IsSearchEngineSubmitRunning(),
+		JobOGImagePrerender:   m.IsOGImagePrerenderRunning(),
+		JobAnalyticsRollup:    m.IsAnalyticsRollupRunning(),
+		JobBackup:             m.IsBackupRunning(),
+		JobCacheCleanup:       m.IsCacheCleanerRunning(),
+	}
+}
+
+// JobStatus 调度任务在集群中的状态
+type JobStatus struct {
+	leader.JobStatus
+	// Running 任务是否在本节点实际运行
+	Running bool `json:"running"`
+}
+
+// GetLeaderStatus 获取各调度任务的持有节点与本节点运行情况；未配置选主时持有者均为本节点
+func (m *Manager) GetLeaderStatus(ctx context.Context) (string, []JobStatus) {
+	running := map[string]bool{
+		JobHotDrama:           m.hotDramaScheduler.IsRunning(),
+		JobReadyResource:      m.readyResourceScheduler.IsReadyResourceRunning(),
+		JobSitemap:            m.sitemapScheduler.IsRunning(),
+		JobGoogleIndex:        m.googleIndexScheduler.IsRunning(),
+		JobCleanup:            m.cleanupScheduler.IsCleanupRunning(),
+		JobXunleiKeepalive:    m.xunleiKeepaliveScheduler.IsRunning(),
+		JobContentSource:      m.contentSourceScheduler.IsRunning(),
+		JobMetadata:           m.metadataScheduler.IsRunning(),
+		JobSearchEngineSubmit: m.searchEngineSubmitScheduler.IsRunning(),
+		JobOGImagePrerender:   m.ogImagePrerenderScheduler.IsRunning(),
+		JobAnalyticsRollup:    m.analyticsRollupScheduler.IsRunning(),
+		JobBackup:             m.backupScheduler.IsRunning(),
+		JobCacheCleanup:       m.cacheCleaner.IsRunning(),
+	}
+	names := jobNames
+	if tg := GetGlobalTelegramBotService(); tg != nil {
+		names = append(names[:len(names):len(names)], services.TelegramPollingJob)
+		running[services.TelegramPollingJob] = tg.IsPolling()
+	}
+
+	statuses := make([]JobStatus, 0, len(names))
+	if m.elector == nil {
+		nodeID := leader.DefaultNodeID()
+		for _, name := range names {
+			status := JobStatus{JobStatus: leader.JobStatus{Name: name, Enabled: running[name], Leader: running[name]}, Running: running[name]}
+			if running[name] {
+				status.Holder = nodeID
+			}
+			statuses = append(statuses, status)
+		}
+		return nodeID, statuses
+	}
+
+	for _, status := range m.elector.Status(ctx, names...) {
+		statuses = append(statuses, JobStatus{JobStatus: status, Running: running[status.Name]})
 	}
+	return m.elector.NodeID(), statuses
 }
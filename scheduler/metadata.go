@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+// metadataCheckInterval 影视元数据补全与刷新的执行周期
+const metadataCheckInterval = time.Hour
+
+// MetadataScheduler 影视元数据调度器
+// 启用后每小时为未匹配的资源补全元数据，并刷新到期的元数据（评分、集数等会变化）。
+type MetadataScheduler struct {
+	*BaseScheduler
+}
+
+// NewMetadataScheduler 创建影视元数据调度器
+func NewMetadataScheduler(base *BaseScheduler) *MetadataScheduler {
+	return &MetadataScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob 影视元数据任务定义，重启后错过的一轮会补跑
+func (s *MetadataScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobMetadata,
+		Description: "补全并刷新影视元数据",
+		Spec:        everySpec(metadataCheckInterval),
+		Jitter:      time.Minute,
+		CatchUp:     true,
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动影视元数据定时任务
+func (s *MetadataScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止影视元数据定时任务，正在进行的请求会被取消
+func (s *MetadataScheduler) Stop() {
+	stopCronJob(JobMetadata)
+}
+
+// IsRunning 检查影视元数据任务是否在运行
+func (s *MetadataScheduler) IsRunning() bool {
+	return cronJobActive(JobMetadata)
+}
+
+// runOnce 刷新到期的元数据，再为未匹配的资源补全
+func (s *MetadataScheduler) runOnce(ctx context.Context) (string, error) {
+	svc := GetGlobalMetadataService()
+	if svc == nil {
+		utils.Debug("[MetadataScheduler] 影视元数据服务未初始化，跳过本轮执行")
+		return "影视元数据服务未初始化", cronjob.ErrSkipped
+	}
+	cfg := svc.Config()
+	if !cfg.Enabled {
+		return "影视元数据功能未启用", cronjob.ErrSkipped
+	}
+
+	var errs []string
+	refreshed, err := svc.RefreshDue(ctx, cfg.BatchSize)
+	if err != nil {
+		utils.Error("[MetadataScheduler] 刷新影视元数据失败: %v", err)
+		errs = append(errs, fmt.Sprintf("刷新: %v", err))
+	} else if refreshed.Total > 0 {
+		utils.Info("[MetadataScheduler] 刷新完成: 总数=%d, 匹配=%d, 未找到=%d, 失败=%d", refreshed.Total, refreshed.Matched, refreshed.NotFound, refreshed.Failed)
+	}
+
+	enriched, err := svc.EnrichMissing(ctx, cfg.BatchSize)
+	if err != nil {
+		utils.Error("[MetadataScheduler] 补全影视元数据失败: %v", err)
+		errs = append(errs, fmt.Sprintf("补全: %v", err))
+	} else if enriched.Total > 0 {
+		utils.Info("[MetadataScheduler] 补全完成: 总数=%d, 匹配=%d, 未找到=%d, 失败=%d", enriched.Total, enriched.Matched, enriched.NotFound, enriched.Failed)
+	}
+
+	summary := fmt.Sprintf("刷新=%d, 补全=%d", refreshed.Total, enriched.Total)
+	if len(errs) > 0 {
+		return summary, errors.New(strings.Join(errs, "; "))
+	}
+	return summary, nil
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	// ogImagePrerenderInterval 预生成检查周期
+	ogImagePrerenderInterval = 5 * time.Minute
+	// ogImagePrerenderBatch 每轮最多处理的资源数
+	ogImagePrerenderBatch = 200
+)
+
+// OGImagePrerenderScheduler OG图片预生成调度器
+// 启用预生成后，定期为新增或更新的资源生成默认模板的 PNG/WebP 图片，分享链接首次被抓取时即可直接命中缓存。
+type OGImagePrerenderScheduler struct {
+	*BaseScheduler
+}
+
+// NewOGImagePrerenderScheduler 创建OG图片预生成调度器
+func NewOGImagePrerenderScheduler(base *BaseScheduler) *OGImagePrerenderScheduler {
+	return &OGImagePrerenderScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob OG图片预生成任务定义
+func (s *OGImagePrerenderScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobOGImagePrerender,
+		Description: "为新增或更新的资源预生成OG图片",
+		Spec:        everySpec(ogImagePrerenderInterval),
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动OG图片预生成定时任务
+func (s *OGImagePrerenderScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止OG图片预生成定时任务，正在进行的生成会被取消
+func (s *OGImagePrerenderScheduler) Stop() {
+	stopCronJob(JobOGImagePrerender)
+}
+
+// IsRunning 检查OG图片预生成任务是否在运行
+func (s *OGImagePrerenderScheduler) IsRunning() bool {
+	return cronJobActive(JobOGImagePrerender)
+}
+
+// runOnce 为游标之后新增/更新的资源预生成图片
+func (s *OGImagePrerenderScheduler) runOnce(ctx context.Context) (string, error) {
+	svc := GetGlobalOGImageService()
+	if svc == nil {
+		utils.Debug("[OGImagePrerenderScheduler] OG图片服务未初始化，跳过本轮执行")
+		return "OG图片服务未初始化", cronjob.ErrSkipped
+	}
+	if !svc.Config().PrerenderEnabled {
+		return "未启用预生成", cronjob.ErrSkipped
+	}
+
+	rendered, err := svc.Prerender(ctx, ogImagePrerenderBatch)
+	if err != nil {
+		utils.Error("[OGImagePrerenderScheduler] 预生成失败: %v", err)
+		return "", err
+	}
+	if rendered == 0 {
+		return "没有需要预生成的资源", cronjob.ErrSkipped
+	}
+	utils.Info("[OGImagePrerenderScheduler] 已预生成 %d 个资源的OG图片", rendered)
+	return fmt.Sprintf("预生成=%d", rendered), nil
+}
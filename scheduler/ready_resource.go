@@ -4,106 +4,73 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	panutils "github.com/ctwj/urldb/common"
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/services"
 	"github.com/ctwj/urldb/utils"
 )
 
 // ReadyResourceScheduler 待处理资源调度器
 type ReadyResourceScheduler struct {
 	*BaseScheduler
-	readyResourceRunning bool
-	processingMutex      sync.Mutex // 防止ready_resource任务重叠执行
 }
 
 // NewReadyResourceScheduler 创建待处理资源调度器
 func NewReadyResourceScheduler(base *BaseScheduler) *ReadyResourceScheduler {
 	return &ReadyResourceScheduler{
-		BaseScheduler:        base,
-		readyResourceRunning: false,
-		processingMutex:      sync.Mutex{},
+		BaseScheduler: base,
 	}
 }
 
-// Start 启动待处理资源定时任务
-func (r *ReadyResourceScheduler) Start() {
-	if r.readyResourceRunning {
-		utils.Debug("待处理资源自动处理任务已在运行中")
-		return
+// cronJob 待处理资源任务定义，间隔取自系统配置（默认3分钟），启用时立即执行一次
+func (r *ReadyResourceScheduler) cronJob() cronjob.Job {
+	interval := 3 * time.Minute // 默认3分钟
+	if autoProcessInterval, err := r.systemConfigRepo.GetConfigInt(entity.ConfigKeyAutoProcessInterval); err == nil && autoProcessInterval > 0 {
+		interval = time.Duration(autoProcessInterval) * time.Minute
+	}
+	return cronjob.Job{
+		Name:        JobReadyResource,
+		Description: "自动处理待处理资源",
+		Spec:        everySpec(interval),
+		CatchUp:     true,
+		Run: func(context.Context) (string, error) {
+			return r.processReadyResources()
+		},
 	}
+}
 
-	r.readyResourceRunning = true
-	utils.Info("启动待处理资源自动处理任务")
-
-	go func() {
-		// 获取系统配置中的间隔时间
-		interval := 3 * time.Minute // 默认3分钟
-		if autoProcessInterval, err := r.systemConfigRepo.GetConfigInt(entity.ConfigKeyAutoProcessInterval); err == nil && autoProcessInterval > 0 {
-			interval = time.Duration(autoProcessInterval) * time.Minute
-		}
-
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		utils.Info(fmt.Sprintf("待处理资源自动处理任务已启动，间隔时间: %v", interval))
-
-		// 立即执行一次
-		r.processReadyResources()
-
-		for {
-			select {
-			case <-ticker.C:
-				// 使用TryLock防止任务重叠执行
-				if r.processingMutex.TryLock() {
-					go func() {
-						defer r.processingMutex.Unlock()
-						r.processReadyResources()
-					}()
-				} else {
-					utils.Debug("上一次待处理资源任务还在执行中，跳过本次执行")
-				}
-			case <-r.GetStopChan():
-				utils.Info("停止待处理资源自动处理任务")
-				return
-			}
-		}
-	}()
+// Start 启动待处理资源定时任务
+func (r *ReadyResourceScheduler) Start() {
+	startCronJob(r.cronJob())
 }
 
 // Stop 停止待处理资源定时任务
 func (r *ReadyResourceScheduler) Stop() {
-	if !r.readyResourceRunning {
-		utils.Debug("待处理资源自动处理任务未在运行")
-		return
-	}
-
-	r.GetStopChan() <- true
-	r.readyResourceRunning = false
-	utils.Info("已发送停止信号给待处理资源自动处理任务")
+	stopCronJob(JobReadyResource)
 }
 
 // IsReadyResourceRunning 检查待处理资源任务是否正在运行
 func (r *ReadyResourceScheduler) IsReadyResourceRunning() bool {
-	return r.readyResourceRunning
+	return cronJobActive(JobReadyResource)
 }
 
 // processReadyResources 处理待处理资源
-func (r *ReadyResourceScheduler) processReadyResources() {
+func (r *ReadyResourceScheduler) processReadyResources() (string, error) {
 	utils.Debug("开始处理待处理资源...")
 
 	// 检查系统配置，确认是否启用自动处理
 	autoProcess, err := r.systemConfigRepo.GetConfigBool(entity.ConfigKeyAutoProcessReadyResources)
 	if err != nil {
 		utils.Error(fmt.Sprintf("获取系统配置失败: %v", err))
-		return
+		return "", err
 	}
 
 	if !autoProcess {
 		utils.Debug("自动处理待处理资源功能已禁用")
-		return
+		return "自动处理已禁用", cronjob.ErrSkipped
 	}
 
 	// 获取所有没有错误的待处理资源
@@ -111,33 +78,34 @@ func (r *ReadyResourceScheduler) processReadyResources() {
 	// readyResources, err := r.readyResourceRepo.FindWithoutErrors()
 	if err != nil {
 		utils.Error(fmt.Sprintf("获取待处理资源失败: %v", err))
-		return
+		return "", err
 	}
 
 	if len(readyResources) == 0 {
 		utils.Debug("没有待处理的资源")
-		return
+		return "没有待处理的资源", cronjob.ErrSkipped
 	}
 
 	utils.Debug(fmt.Sprintf("找到 %d 个待处理资源，开始处理...", len(readyResources)))
+	finish := publishRun("ready_resource")
 
 	processedCount := 0
 	factory := panutils.GetInstance() // 使用单例模式
+	funcs := r.stageFuncs()
 	for _, readyResource := range readyResources {
-
-		//readyResource.URL 是 查重
-		exits, err := r.resourceRepo.FindExists(readyResource.URL)
-		if err != nil {
-			utils.Error(fmt.Sprintf("查重失败: %v", err))
-			continue
+		pc := &pipelineContext{ready: readyResource, factory: factory}
+		result := runPipeline(pc, r.pipelineStages(readyResource.Source), funcs)
+		if globalReadyResourcePipelineService != nil {
+			globalReadyResourcePipelineService.RecordStageLogs(result.logs)
 		}
-		if exits {
-			utils.Debug(fmt.Sprintf("资源已存在: %s", readyResource.URL))
+
+		if result.skipped {
+			utils.Debug(fmt.Sprintf("资源已跳过: %s", readyResource.URL))
 			r.readyResourceRepo.Delete(readyResource.ID)
 			continue
 		}
 
-		if err := r.convertReadyResourceToResource(readyResource, factory); err != nil {
+		if err := result.err; err != nil {
 			utils.Error(fmt.Sprintf("处理资源失败 (ID: %d): %v", readyResource.ID, err))
 
 			// 保存完整的错误信息
@@ -162,145 +130,17 @@ func (r *ReadyResourceScheduler) processReadyResources() {
 	if processedCount > 0 {
 		utils.Info(fmt.Sprintf("待处理资源处理完成，共处理 %d 个资源", processedCount))
 	}
+	summary := fmt.Sprintf("待处理=%d, 成功=%d", len(readyResources), processedCount)
+	finish(summary, nil)
+	return summary, nil
 }
 
-// convertReadyResourceToResource 将待处理资源转换为正式资源
-func (r *ReadyResourceScheduler) convertReadyResourceToResource(readyResource entity.ReadyResource, factory *panutils.PanFactory) error {
-	utils.Debug(fmt.Sprintf("开始处理资源: %s", readyResource.URL))
-
-	// 提取分享ID和服务类型
-	shareID, serviceType := panutils.ExtractShareId(readyResource.URL)
-	if serviceType == panutils.NotFound {
-		utils.Warn(fmt.Sprintf("不支持的链接地址: %s", readyResource.URL))
-		return fmt.Errorf("不支持的链接地址: %s", readyResource.URL)
-	}
-
-	utils.Debug(fmt.Sprintf("检测到服务类型: %s, 分享ID: %s", serviceType.String(), shareID))
-
-	resource := &entity.Resource{
-		Title:       derefString(readyResource.Title),
-		Description: readyResource.Description,
-		URL:         readyResource.URL,
-		Cover:       readyResource.Img,
-		IsValid:     true,
-		IsPublic:    true,
-		Key:         readyResource.Key,
-		PanID:       r.getPanIDByServiceType(serviceType),
-	}
-
-	// 检查违禁词
-	// forbiddenWords, err := r.systemConfigRepo.GetConfigValue(entity.ConfigKeyForbiddenWords)
-	// if err == nil && forbiddenWords != "" {
-	// 	words := strings.Split(forbiddenWords, ",")
-	// 	var matchedWords []string
-	// 	title := strings.ToLower(resource.Title)
-	// 	description := strings.ToLower(resource.Description)
-
-	// 	for _, word := range words {
-	// 		word = strings.TrimSpace(word)
-	// 		if word != "" {
-	// 			wordLower := strings.ToLower(word)
-	// 			if strings.Contains(title, wordLower) || strings.Contains(description, wordLower) {
-	// 				matchedWords = append(matchedWords, word)
-	// 			}
-	// 		}
-	// 	}
-
-	// 	if len(matchedWords) > 0 {
-	// 		utils.Warn(fmt.Sprintf("资源包含违禁词: %s, 违禁词: %s", resource.Title, strings.Join(matchedWords, ", ")))
-	// 		return fmt.Errorf("存在违禁词: %s", strings.Join(matchedWords, ", "))
-	// 	}
-	// }
-
-	// PanCheck 有效性校验（全平台，含夸克）
-	// - 失效：拒绝，不进入转存/创建流程
-	// - 未启用检测 / 未得出结论：放行（保持 is_valid 原值，不阻断），对齐 FR-004
-	if globalLinkCheckService != nil {
-		lcResult := globalLinkCheckService.CheckURL(context.Background(), readyResource.URL, false)
-		utils.Info(fmt.Sprintf("[PanCheck] 检测结果: URL=%s, status=%s, method=%s, reason=%s", readyResource.URL, lcResult.Status, lcResult.DetectionMethod, lcResult.FailReason))
-		if lcResult.Status == "invalid" {
-			utils.Warn(fmt.Sprintf("PanCheck 判定链接失效: %s, 原因: %s", readyResource.URL, lcResult.FailReason))
-			return fmt.Errorf("链接无效: %s", readyResource.URL)
-		}
-	} else {
-		utils.Warn("[PanCheck] globalLinkCheckService 未注入（nil），跳过 PanCheck 检测，资源直接放行")
+// pipelineStages 获取数据来源对应的流水线阶段配置
+func (r *ReadyResourceScheduler) pipelineStages(source string) []services.PipelineStageConfig {
+	if globalReadyResourcePipelineService == nil {
+		return services.DefaultPipelineStageConfigs()
 	}
-
-	// 夸克/百度：校验通过后通过转存服务获取标题（IsType=1，仅校验+取标题，不真转存）
-	if serviceType == panutils.Quark || serviceType == panutils.BaiduPan {
-		if err := r.fetchPanMeta(serviceType, shareID, readyResource.URL, resource, factory); err != nil {
-			return err
-		}
-	}
-
-	// 处理分类
-	if readyResource.Category != "" {
-		categoryID, err := r.resolveCategory(readyResource.Category, nil)
-		if err != nil {
-			utils.Error(fmt.Sprintf("解析分类失败: %v", err))
-		} else {
-			resource.CategoryID = categoryID
-		}
-	}
-
-	// 处理标签
-	if readyResource.Tags != "" {
-		tagIDs, err := r.handleTags(readyResource.Tags)
-		if err != nil {
-			utils.Error(fmt.Sprintf("处理标签失败: %v", err))
-		} else {
-			// 保存资源
-			err = r.resourceRepo.Create(resource)
-			if err != nil {
-				return fmt.Errorf("创建资源失败: %v", err)
-			}
-
-			// 创建资源标签关联
-			for _, tagID := range tagIDs {
-				resourceTag := &entity.ResourceTag{
-					ResourceID: resource.ID,
-					TagID:      tagID,
-				}
-				err = r.resourceRepo.CreateResourceTag(resourceTag)
-				if err != nil {
-					utils.Error(fmt.Sprintf("创建资源标签关联失败: %v", err))
-				}
-			}
-		}
-	} else {
-		// 保存资源
-		err := r.resourceRepo.Create(resource)
-		if err != nil {
-			return fmt.Errorf("创建资源失败: %v", err)
-		}
-	}
-
-	// 同步到Meilisearch
-	utils.Debug(fmt.Sprintf("准备同步资源到Meilisearch - 资源ID: %d, URL: %s", resource.ID, resource.URL))
-	utils.Debug(fmt.Sprintf("globalMeilisearchManager: %v", globalMeilisearchManager != nil))
-
-	if globalMeilisearchManager != nil {
-		utils.Debug(fmt.Sprintf("Meilisearch管理器已初始化，检查启用状态"))
-		isEnabled := globalMeilisearchManager.IsEnabled()
-		utils.Debug(fmt.Sprintf("Meilisearch启用状态: %v", isEnabled))
-
-		if isEnabled {
-			utils.Debug(fmt.Sprintf("Meilisearch已启用，开始同步资源"))
-			go func() {
-				if err := globalMeilisearchManager.SyncResourceToMeilisearch(resource); err != nil {
-					utils.Error("同步资源到Meilisearch失败: %v", err)
-				} else {
-					utils.Info(fmt.Sprintf("资源已同步到Meilisearch: %s", resource.URL))
-				}
-			}()
-		} else {
-			utils.Debug("Meilisearch未启用，跳过同步")
-		}
-	} else {
-		utils.Debug("Meilisearch管理器未初始化，跳过同步")
-	}
-
-	return nil
+	return globalReadyResourcePipelineService.Resolve(source)
 }
 
 // fetchPanMeta 通过转存服务获取资源标题（IsType=1，仅校验+取标题，不真转存），
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+)
+
+// pipelineSkip 阶段判定资源无需处理（如已存在），终止流水线但不视为失败
+type pipelineSkip struct {
+	reason string
+}
+
+func (s *pipelineSkip) Error() string {
+	return s.reason
+}
+
+// pipelineContext 单个待处理资源在流水线中的处理上下文
+type pipelineContext struct {
+	ready       entity.ReadyResource
+	resource    *entity.Resource // normalize 阶段创建
+	shareID     string
+	serviceType panutils.ServiceType
+	tagIDs      []uint
+	factory     *panutils.PanFactory
+	review      *services.ClassificationDecision // 需入库后加入审核队列的分类建议
+	metadata    *entity.ResourceMetadata         // 需入库后保存的影视元数据
+}
+
+// pipelineStageFunc 阶段实现
+type pipelineStageFunc func(pc *pipelineContext, cfg services.PipelineStageConfig) error
+
+// pipelineResult 流水线执行结果
+type pipelineResult struct {
+	logs    []entity.ReadyResourceStageLog
+	skipped bool  // 资源被跳过，不视为失败
+	err     error // 资源被拒绝的原因，已带 [阶段] 前缀
+}
+
+// runPipeline 按配置顺序执行已启用的阶段，记录每个阶段的耗时与结果
+func runPipeline(pc *pipelineContext, stages []services.PipelineStageConfig, funcs map[string]pipelineStageFunc) pipelineResult {
+	var result pipelineResult
+	for _, cfg := range stages {
+		if !cfg.Enabled {
+			continue
+		}
+		fn, ok := funcs[cfg.Name]
+		if !ok {
+			utils.Warn("[Pipeline] 未实现的阶段: %s，已跳过", cfg.Name)
+			continue
+		}
+
+		start := time.Now()
+		err := fn(pc, cfg)
+		log := entity.ReadyResourceStageLog{
+			ReadyResourceID: pc.ready.ID,
+			Key:             pc.ready.Key,
+			URL:             pc.ready.URL,
+			Source:          pc.ready.Source,
+			Stage:           cfg.Name,
+			Seq:             len(result.logs) + 1,
+			Status:          entity.StageStatusSuccess,
+			DurationMs:      time.Since(start).Milliseconds(),
+		}
+
+		if err != nil {
+			log.Error = err.Error()
+			if skip, ok := err.(*pipelineSkip); ok {
+				log.Status = entity.StageStatusSkipped
+				log.Error = skip.reason
+				result.skipped = true
+			} else if cfg.ShouldContinueOnError() {
+				log.Status = entity.StageStatusWarning
+				utils.Warn("[Pipeline] 阶段 %s 失败，继续执行 (URL: %s): %v", cfg.Name, pc.ready.URL, err)
+			} else {
+				log.Status = entity.StageStatusFailed
+				result.err = fmt.Errorf("[%s] %v", strings.ToUpper(cfg.Name), err)
+			}
+		}
+		result.logs = append(result.logs, log)
+
+		if result.skipped || result.err != nil {
+			break
+		}
+	}
+
+	if pc.resource != nil && pc.resource.ID != 0 {
+		resourceID := pc.resource.ID
+		for i := range result.logs {
+			result.logs[i].ResourceID = &resourceID
+		}
+	}
+	return result
+}
@@ -0,0 +1,358 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/classifier"
+	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+)
+
+// 违禁词阶段的处理方式
+const (
+	forbiddenActionReject = "reject" // 拒绝入库
+	forbiddenActionHide   = "hide"   // 入库但不公开
+	forbiddenActionMask   = "mask"   // 违禁词替换为 *
+)
+
+// metadataStageTimeout 单个资源匹配影视元数据的超时时间
+const metadataStageTimeout = 30 * time.Second
+
+// stageFuncs 各阶段实现
+func (r *ReadyResourceScheduler) stageFuncs() map[string]pipelineStageFunc {
+	return map[string]pipelineStageFunc{
+		services.PipelineStageNormalize:      r.stageNormalize,
+		services.PipelineStageDedupe:         r.stageDedupe,
+		services.PipelineStageValidity:       r.stageValidity,
+		services.PipelineStageMetadata:       r.stageMetadata,
+		services.PipelineStageForbiddenWords: r.stageForbiddenWords,
+		services.PipelineStageCategorize:     r.stageCategorize,
+		services.PipelineStageTag:            r.stageTag,
+		services.PipelineStageClassify:       r.stageClassify,
+		services.PipelineStageEnrich:         r.stageEnrich,
+		services.PipelineStageCover:          r.stageCover,
+		services.PipelineStagePersist:        r.stagePersist,
+		services.PipelineStageIndex:          r.stageIndex,
+		services.PipelineStageNotify:         r.stageNotify,
+	}
+}
+
+// stageNormalize 清理链接与文本，识别网盘类型并创建待入库的资源
+func (r *ReadyResourceScheduler) stageNormalize(pc *pipelineContext, _ services.PipelineStageConfig) error {
+	url := strings.TrimRight(strings.TrimSpace(pc.ready.URL), "，。；、,;")
+
+	shareID, serviceType := panutils.ExtractShareId(url)
+	if serviceType == panutils.NotFound {
+		return fmt.Errorf("不支持的链接地址: %s", url)
+	}
+	utils.Debug("检测到服务类型: %s, 分享ID: %s", serviceType.String(), shareID)
+
+	pc.shareID = shareID
+	pc.serviceType = serviceType
+	pc.resource = &entity.Resource{
+		Title:       strings.TrimSpace(derefString(pc.ready.Title)),
+		Description: strings.TrimSpace(pc.ready.Description),
+		URL:         url,
+		Cover:       strings.TrimSpace(pc.ready.Img),
+		IsValid:     true,
+		IsPublic:    true,
+		Key:         pc.ready.Key,
+		PanID:       r.getPanIDByServiceType(serviceType),
+	}
+	return nil
+}
+
+// stageDedupe 链接已存在时跳过，命中黑名单拒绝规则时提前拒绝，避免后续检测与取元数据
+func (r *ReadyResourceScheduler) stageDedupe(pc *pipelineContext, _ services.PipelineStageConfig) error {
+	exists, err := r.resourceRepo.FindExists(pc.resource.URL)
+	if err != nil {
+		return fmt.Errorf("查重失败: %v", err)
+	}
+	if exists {
+		return &pipelineSkip{reason: "资源已存在"}
+	}
+
+	if blocklist := services.GetDefaultBlocklistService(); blocklist != nil {
+		target := services.BlocklistTarget{URL: pc.resource.URL, Title: pc.resource.Title, Author: pc.resource.Author}
+		if reason := blocklist.CheckReject(target); reason != "" {
+			return fmt.Errorf("拒绝入库: %s", reason)
+		}
+	}
+	return nil
+}
+
+// stageValidity PanCheck 有效性校验（全平台，含夸克）
+// - 失效：拒绝，不进入转存/创建流程
+// - 未启用检测 / 未得出结论：放行（保持 is_valid 原值，不阻断），对齐 FR-004
+func (r *ReadyResourceScheduler) stageValidity(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	if globalLinkCheckService == nil {
+		utils.Warn("[PanCheck] globalLinkCheckService 未注入（nil），跳过 PanCheck 检测，资源直接放行")
+		return nil
+	}
+
+	url := pc.resource.URL
+	lcResult := globalLinkCheckService.CheckURL(context.Background(), url, cfg.OptionBool("ignore_cache", false))
+	utils.Info("[PanCheck] 检测结果: URL=%s, status=%s, method=%s, reason=%s", url, lcResult.Status, lcResult.DetectionMethod, lcResult.FailReason)
+	if lcResult.Status == "invalid" {
+		utils.Warn("PanCheck 判定链接失效: %s, 原因: %s", url, lcResult.FailReason)
+		return fmt.Errorf("链接无效: %s", url)
+	}
+	return nil
+}
+
+// stageMetadata 通过转存服务获取标题（IsType=1，仅校验+取标题，不真转存），默认仅夸克/百度
+func (r *ReadyResourceScheduler) stageMetadata(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	platforms := cfg.OptionStrings("platforms", []string{panutils.Quark.String(), panutils.BaiduPan.String()})
+	for _, platform := range platforms {
+		if strings.EqualFold(platform, pc.serviceType.String()) {
+			return r.fetchPanMeta(pc.serviceType, pc.shareID, pc.resource.URL, pc.resource, pc.factory)
+		}
+	}
+	return nil
+}
+
+// stageForbiddenWordCache 系统违禁词与阶段违禁词合并后的列表。
+// 两者都未变化时返回同一切片，违禁词匹配器按切片复用，不必对每条资源重建
+var stageForbiddenWordCache struct {
+	mu     sync.Mutex
+	config []string
+	extra  []string
+	words  []string
+}
+
+// stageForbiddenWordList 合并系统违禁词（GetForbiddenWordsFromConfig 在配置未变时返回同一切片）与阶段配置的违禁词
+func stageForbiddenWordList(config, extra []string) []string {
+	if len(extra) == 0 {
+		return config
+	}
+	c := &stageForbiddenWordCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sameConfig := len(c.config) == len(config) && (len(config) == 0 || &c.config[0] == &config[0])
+	if c.words == nil || !sameConfig || !slices.Equal(c.extra, extra) {
+		c.config = config
+		c.extra = extra
+		c.words = slices.Concat(config, extra)
+	}
+	return c.words
+}
+
+// stageForbiddenWords 检查标题与描述中的违禁词（系统配置 + 阶段配置 words）
+func (r *ReadyResourceScheduler) stageForbiddenWords(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	// 读取配置失败时只使用阶段配置的违禁词
+	configWords, _ := utils.GetForbiddenWordsFromConfig(func() (string, error) {
+		return r.systemConfigRepo.GetConfigValue(entity.ConfigKeyForbiddenWords)
+	})
+	words := stageForbiddenWordList(configWords, cfg.OptionStrings("words", nil))
+	if len(words) == 0 {
+		return nil
+	}
+
+	matched, matchedWords := utils.CheckContainsForbiddenWords(pc.resource.Title+"\n"+pc.resource.Description, words)
+	if !matched {
+		return nil
+	}
+
+	switch cfg.OptionString("action", forbiddenActionReject) {
+	case forbiddenActionHide:
+		pc.resource.IsPublic = false
+		utils.Warn("资源包含违禁词，已设为不公开: %s, 违禁词: %s", pc.resource.Title, strings.Join(matchedWords, ", "))
+		return nil
+	case forbiddenActionMask:
+		pc.resource.Title = utils.ReplaceForbiddenWords(pc.resource.Title, words)
+		pc.resource.Description = utils.ReplaceForbiddenWords(pc.resource.Description, words)
+		return nil
+	default:
+		utils.Warn("资源包含违禁词: %s, 违禁词: %s", pc.resource.Title, strings.Join(matchedWords, ", "))
+		return fmt.Errorf("存在违禁词: %s", strings.Join(matchedWords, ", "))
+	}
+}
+
+// stageCategorize 按分类名匹配或创建分类，未指定时使用 default_category
+func (r *ReadyResourceScheduler) stageCategorize(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	name := strings.TrimSpace(pc.ready.Category)
+	if name == "" {
+		name = cfg.OptionString("default_category", "")
+	}
+	if name == "" {
+		return nil
+	}
+
+	categoryID, err := r.resolveCategory(name, nil)
+	if err != nil {
+		return fmt.Errorf("解析分类失败: %v", err)
+	}
+	pc.resource.CategoryID = categoryID
+	return nil
+}
+
+// stageTag 按标签名匹配或创建标签，extra_tags 会追加到每个资源
+func (r *ReadyResourceScheduler) stageTag(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	tags := pc.ready.Tags
+	if extra := cfg.OptionStrings("extra_tags", nil); len(extra) > 0 {
+		tags = strings.Trim(tags+","+strings.Join(extra, ","), ",")
+	}
+	if tags == "" {
+		return nil
+	}
+
+	tagIDs, err := r.handleTags(tags)
+	if err != nil {
+		return fmt.Errorf("处理标签失败: %v", err)
+	}
+	pc.tagIDs = tagIDs
+	return nil
+}
+
+// stageClassify 按分类规则与本地模型推荐分类和标签：高置信度直接采纳，低置信度入库后进入审核队列
+func (r *ReadyResourceScheduler) stageClassify(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	if globalClassificationService == nil {
+		return nil
+	}
+
+	opts := services.ClassifyOptions{
+		AssignThreshold: cfg.OptionFloat("assign_threshold", services.DefaultClassifyAssignThreshold),
+		ReviewThreshold: cfg.OptionFloat("review_threshold", services.DefaultClassifyReviewThreshold),
+		UseBayes:        cfg.OptionBool("use_bayes", true),
+		Overwrite:       cfg.OptionBool("overwrite", false),
+	}
+	doc := classifier.Document{Title: pc.resource.Title, Description: pc.resource.Description}
+	result := globalClassificationService.Classify(doc, opts.UseBayes)
+	decision := globalClassificationService.Decide(result, opts, pc.resource.CategoryID != nil, pc.tagIDs)
+
+	if decision.CategoryID != nil {
+		pc.resource.CategoryID = decision.CategoryID
+	}
+	pc.tagIDs = append(pc.tagIDs, decision.TagIDs...)
+	globalClassificationService.RecordRuleHits(decision)
+	if decision.NeedsReview() {
+		pc.review = decision
+	}
+	return nil
+}
+
+// stageEnrich 匹配影视元数据，入库后保存；缺少封面时默认使用条目海报
+func (r *ReadyResourceScheduler) stageEnrich(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	if globalMetadataService == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metadataStageTimeout)
+	defer cancel()
+	m, err := globalMetadataService.Resolve(ctx, pc.resource.Title, cfg.OptionFloat("min_score", 0))
+	if err != nil {
+		// 请求失败不记录，由定时补全任务稍后重试
+		return fmt.Errorf("匹配影视元数据失败: %v", err)
+	}
+
+	pc.metadata = m
+	if m.HasSubject() && pc.resource.Cover == "" && cfg.OptionBool("set_cover", true) {
+		pc.resource.Cover = m.PosterURL
+	}
+	return nil
+}
+
+// stageCover 缺少封面时使用同名热播剧海报，仍没有则使用 default_cover
+func (r *ReadyResourceScheduler) stageCover(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	if pc.resource.Cover != "" {
+		return nil
+	}
+	if pc.resource.Title != "" {
+		if drama, err := r.hotDramaRepo.FindByTitle(pc.resource.Title); err == nil && drama.PosterURL != "" {
+			pc.resource.Cover = drama.PosterURL
+			return nil
+		}
+	}
+	pc.resource.Cover = cfg.OptionString("default_cover", "")
+	return nil
+}
+
+// stagePersist 按最终的标题与链接应用黑名单后保存资源及标签关联。
+// 黑名单在必需的入库阶段执行，不受流水线配置影响
+func (r *ReadyResourceScheduler) stagePersist(pc *pipelineContext, _ services.PipelineStageConfig) error {
+	if blocklist := services.GetDefaultBlocklistService(); blocklist != nil {
+		if reason := blocklist.ApplyIngest(pc.resource); reason != "" {
+			return fmt.Errorf("拒绝入库: %s", reason)
+		}
+	}
+
+	if err := r.resourceRepo.Create(pc.resource); err != nil {
+		return fmt.Errorf("创建资源失败: %v", err)
+	}
+
+	for _, tagID := range pc.tagIDs {
+		resourceTag := &entity.ResourceTag{
+			ResourceID: pc.resource.ID,
+			TagID:      tagID,
+		}
+		if err := r.resourceRepo.CreateResourceTag(resourceTag); err != nil {
+			utils.Error("创建资源标签关联失败: %v", err)
+		}
+	}
+
+	if pc.review != nil && globalClassificationService != nil {
+		if err := globalClassificationService.EnqueueReview(pc.resource, pc.review, services.ClassificationOriginPipeline); err != nil {
+			utils.Error("加入分类审核队列失败: %v", err)
+		}
+	}
+
+	if pc.metadata != nil && globalMetadataService != nil {
+		if err := globalMetadataService.Save(pc.resource.ID, pc.metadata); err != nil {
+			utils.Error("保存影视元数据失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// stageIndex 同步到Meilisearch，未启用时跳过。
+// 最多等待 timeout_seconds 秒（默认 5 秒），超时后同步转入后台继续，
+// 避免搜索服务缓慢或不可用时拖住整批待处理资源
+func (r *ReadyResourceScheduler) stageIndex(pc *pipelineContext, cfg services.PipelineStageConfig) error {
+	if globalMeilisearchManager == nil || !globalMeilisearchManager.IsEnabled() {
+		utils.Debug("Meilisearch未启用，跳过同步")
+		return nil
+	}
+
+	resource := pc.resource
+	done := make(chan error, 1)
+	go func() {
+		done <- globalMeilisearchManager.SyncResourceToMeilisearch(resource)
+	}()
+
+	timeout := time.Duration(cfg.OptionFloat("timeout_seconds", 5) * float64(time.Second))
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("同步资源到Meilisearch失败: %v", err)
+		}
+		utils.Debug("资源已同步到Meilisearch: %s", resource.URL)
+		return nil
+	case <-time.After(timeout):
+		go func() {
+			if err := <-done; err != nil {
+				utils.Error("后台同步资源到Meilisearch失败 (URL: %s): %v", resource.URL, err)
+			}
+		}()
+		return fmt.Errorf("同步资源到Meilisearch超过 %s，已转入后台继续", timeout)
+	}
+}
+
+// stageNotify 触发插件系统 URL 添加事件
+func (r *ReadyResourceScheduler) stageNotify(pc *pipelineContext, _ services.PipelineStageConfig) error {
+	plugins.TriggerURLAdd(pc.resource, map[string]interface{}{
+		"source":            pc.ready.Source,
+		"ready_resource_id": pc.ready.ID,
+	})
+	return nil
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/utils"
+)
+
+// searchEngineSubmitCheckInterval 检查是否到达自动提交间隔的周期
+const searchEngineSubmitCheckInterval = time.Minute
+
+// SearchEngineSubmitScheduler 搜索引擎URL提交调度器
+// 启用自动提交后，按配置的间隔把新增或更新的资源页面加入台账，并在每日配额内提交给各搜索引擎。
+type SearchEngineSubmitScheduler struct {
+	*BaseScheduler
+	lastRun time.Time
+}
+
+// NewSearchEngineSubmitScheduler 创建搜索引擎URL提交调度器
+func NewSearchEngineSubmitScheduler(base *BaseScheduler) *SearchEngineSubmitScheduler {
+	return &SearchEngineSubmitScheduler{
+		BaseScheduler: base,
+	}
+}
+
+// cronJob 搜索引擎URL提交任务定义，每分钟检查是否到达配置的提交间隔
+func (s *SearchEngineSubmitScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobSearchEngineSubmit,
+		Description: "向搜索引擎提交新增或更新的资源页面",
+		Spec:        everySpec(searchEngineSubmitCheckInterval),
+		Run:         s.runOnce,
+	}
+}
+
+// Start 启动搜索引擎URL提交定时任务
+func (s *SearchEngineSubmitScheduler) Start() {
+	startCronJob(s.cronJob())
+}
+
+// Stop 停止搜索引擎URL提交定时任务，正在进行的提交会被取消
+func (s *SearchEngineSubmitScheduler) Stop() {
+	stopCronJob(JobSearchEngineSubmit)
+}
+
+// IsRunning 检查搜索引擎URL提交任务是否在运行
+func (s *SearchEngineSubmitScheduler) IsRunning() bool {
+	return cronJobActive(JobSearchEngineSubmit)
+}
+
+// runOnce 到达配置的间隔后收集新增/更新的资源页面并提交
+func (s *SearchEngineSubmitScheduler) runOnce(ctx context.Context) (string, error) {
+	svc := GetGlobalSearchEngineSubmitService()
+	if svc == nil {
+		utils.Debug("[SearchEngineSubmitScheduler] 搜索引擎提交服务未初始化，跳过本轮执行")
+		return "搜索引擎提交服务未初始化", cronjob.ErrSkipped
+	}
+	cfg := svc.Config()
+	if !cfg.AutoSubmit || len(cfg.EnabledEngines()) == 0 {
+		return "未启用自动提交", cronjob.ErrSkipped
+	}
+	if !cronjob.IsManual(ctx) && time.Since(s.lastRun) < time.Duration(cfg.IntervalMinutes)*time.Minute {
+		return "未到提交间隔", cronjob.ErrSkipped
+	}
+	s.lastRun = time.Now()
+
+	finish := publishRun("search_engine_submit")
+	results, err := svc.RunOnce(ctx)
+	if err != nil {
+		utils.Error("[SearchEngineSubmitScheduler] 提交失败: %v", err)
+		finish("", err)
+		return "", err
+	}
+	submitted := 0
+	for _, r := range results {
+		submitted += r.Submitted
+		if r.Submitted > 0 || r.Error != "" {
+			utils.Info("[SearchEngineSubmitScheduler] %s: 提交=%d, 成功=%d, 失败=%d, 配额用尽=%v %s", r.Engine, r.Submitted, r.Succeeded, r.Failed, r.QuotaExhausted, r.Error)
+		}
+	}
+	summary := fmt.Sprintf("引擎=%d, 提交=%d", len(results), submitted)
+	finish(summary, nil)
+	return summary, nil
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/sitemap"
+	"gorm.io/gorm"
+)
+
+// visibleResourceCond 可收录资源的条件：有效、公开、未删除
+const visibleResourceCond = "r.is_valid = true AND r.is_public = true AND r.deleted_at IS NULL"
+
+// sitemapSource 基于数据库的 sitemap 数据源
+type sitemapSource struct {
+	db *gorm.DB
+}
+
+// MaxResourceID 资源（含已删除）的最大 ID
+func (s *sitemapSource) MaxResourceID(ctx context.Context) (uint, error) {
+	var maxID uint
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.Resource{}).
+		Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error
+	return maxID, err
+}
+
+// ChangedResourcePages since 之后新增、更新或软删除的资源所在的分页
+func (s *sitemapSource) ChangedResourcePages(ctx context.Context, since time.Time, pageSize int) ([]int, error) {
+	var pages []int
+	err := s.db.WithContext(ctx).Unscoped().Model(&entity.Resource{}).
+		Where("updated_at > ? OR deleted_at > ?", since, since).
+		Distinct().
+		Pluck("(id - 1) / "+strconv.Itoa(pageSize), &pages).Error
+	return pages, err
+}
+
+// Resources ID 区间内可收录的资源
+func (s *sitemapSource) Resources(ctx context.Context, minID, maxID uint) ([]sitemap.Resource, error) {
+	var rows []entity.Resource
+	err := s.db.WithContext(ctx).Model(&entity.Resource{}).
+		Select("id, key, cover, updated_at").
+		Where("id BETWEEN ? AND ?", minID, maxID).
+		Where("is_valid = ? AND is_public = ?", true, true).
+		Where("key <> ''").
+		Order("id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	list := make([]sitemap.Resource, 0, len(rows))
+	for _, r := range rows {
+		list = append(list, sitemap.Resource{Key: r.Key, Cover: r.Cover, UpdatedAt: r.UpdatedAt})
+	}
+	return list, nil
+}
+
+// Categories 有可收录资源的分类，链接到首页按分类名搜索的结果页
+func (s *sitemapSource) Categories(ctx context.Context) ([]sitemap.Page, error) {
+	var rows []entity.Category
+	err := s.db.WithContext(ctx).Model(&entity.Category{}).
+		Where("EXISTS (SELECT 1 FROM resources r WHERE r.category_id = categories.id AND " + visibleResourceCond + ")").
+		Order("id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]sitemap.Page, 0, len(rows))
+	for _, c := range rows {
+		pages = append(pages, sitemap.Page{Path: searchPath(c.Name), UpdatedAt: c.UpdatedAt})
+	}
+	return pages, nil
+}
+
+// Tags 有可收录资源的标签
+func (s *sitemapSource) Tags(ctx context.Context) ([]sitemap.Page, error) {
+	var rows []entity.Tag
+	err := s.db.WithContext(ctx).Model(&entity.Tag{}).
+		Where("EXISTS (SELECT 1 FROM resource_tags rt JOIN resources r ON r.id = rt.resource_id WHERE rt.tag_id = tags.id AND " + visibleResourceCond + ")").
+		Order("id ASC").
+		Limit(sitemap.MaxURLsPerFile).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]sitemap.Page, 0, len(rows))
+	for _, t := range rows {
+		pages = append(pages, sitemap.Page{Path: searchPath(t.Name), UpdatedAt: t.UpdatedAt})
+	}
+	return pages, nil
+}
+
+// HotDramas 热播剧列表页及各剧的搜索结果页
+func (s *sitemapSource) HotDramas(ctx context.Context) ([]sitemap.Page, error) {
+	var rows []entity.HotDrama
+	if err := s.db.WithContext(ctx).Order("rank ASC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	pages := make([]sitemap.Page, 0, len(rows)+1)
+	var latest time.Time
+	seen := make(map[string]bool, len(rows))
+	for _, d := range rows {
+		if d.UpdatedAt.After(latest) {
+			latest = d.UpdatedAt
+		}
+		if d.Title == "" || seen[d.Title] {
+			continue
+		}
+		seen[d.Title] = true
+		pages = append(pages, sitemap.Page{Path: searchPath(d.Title), Image: d.PosterURL, UpdatedAt: d.UpdatedAt})
+	}
+	return append([]sitemap.Page{{Path: "/hot-dramas", UpdatedAt: latest}}, pages...), nil
+}
+
+// searchPath 首页搜索结果页路径
+func searchPath(keyword string) string {
+	return "/?search=" + url.QueryEscape(keyword)
+}
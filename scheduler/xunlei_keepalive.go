@@ -1,11 +1,13 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/eventbus"
 	"github.com/ctwj/urldb/utils"
 )
 
@@ -17,8 +19,6 @@ import (
 // 避免闲置账号失效。
 type XunleiKeepaliveScheduler struct {
 	*BaseScheduler
-	running bool
-	mutex   sync.Mutex // 防止保活任务重叠执行
 }
 
 // NewXunleiKeepaliveScheduler 创建迅雷 token 保活调度器
@@ -26,69 +26,45 @@ func NewXunleiKeepaliveScheduler(base *BaseScheduler) *XunleiKeepaliveScheduler
 	return &XunleiKeepaliveScheduler{BaseScheduler: base}
 }
 
-// Start 启动迅雷 token 保活定时任务
-func (s *XunleiKeepaliveScheduler) Start() {
-	if s.running {
-		utils.Debug("迅雷 token 保活任务已在运行中")
-		return
+// cronJob 迅雷 token 保活任务定义：refresh_token 有效期约 30 天，每天刷新一次足够续命；
+// 错过计划时启用后立即补跑（续命 + 尽早暴露失效账号）
+func (s *XunleiKeepaliveScheduler) cronJob() cronjob.Job {
+	return cronjob.Job{
+		Name:        JobXunleiKeepalive,
+		Description: "刷新迅雷账号 token 续命",
+		Spec:        everySpec(24 * time.Hour),
+		Jitter:      10 * time.Minute,
+		CatchUp:     true,
+		Run: func(context.Context) (string, error) {
+			return s.keepalive()
+		},
 	}
-	s.running = true
-	utils.Info("启动迅雷 token 保活定时任务")
-
-	go func() {
-		interval := 24 * time.Hour // refresh_token 有效期约 30 天，每天刷新一次足够续命
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		utils.Info(fmt.Sprintf("迅雷 token 保活任务已启动，间隔: %v", interval))
-
-		// 启动后立即执行一次（续命 + 尽早暴露失效账号）
-		s.keepalive()
+}
 
-		for {
-			select {
-			case <-ticker.C:
-				if s.mutex.TryLock() {
-					go func() {
-						defer s.mutex.Unlock()
-						s.keepalive()
-					}()
-				} else {
-					utils.Debug("上一次迅雷 token 保活任务还在执行中，跳过本次")
-				}
-			case <-s.GetStopChan():
-				utils.Info("停止迅雷 token 保活定时任务")
-				return
-			}
-		}
-	}()
+// Start 启动迅雷 token 保活定时任务
+func (s *XunleiKeepaliveScheduler) Start() {
+	startCronJob(s.cronJob())
 }
 
 // Stop 停止迅雷 token 保活定时任务
 func (s *XunleiKeepaliveScheduler) Stop() {
-	if !s.running {
-		utils.Debug("迅雷 token 保活任务未在运行")
-		return
-	}
-	s.GetStopChan() <- true
-	s.running = false
-	utils.Info("已发送停止信号给迅雷 token 保活任务")
+	stopCronJob(JobXunleiKeepalive)
 }
 
 // IsRunning 检查迅雷 token 保活任务是否正在运行
 func (s *XunleiKeepaliveScheduler) IsRunning() bool {
-	return s.running
+	return cronJobActive(JobXunleiKeepalive)
 }
 
 // keepalive 遍历所有有效迅雷账号，刷新 token 续命
-func (s *XunleiKeepaliveScheduler) keepalive() {
+func (s *XunleiKeepaliveScheduler) keepalive() (string, error) {
 	utils.Debug("[迅雷保活] 开始刷新迅雷账号 token...")
 
 	// 定位 xunlei 平台 ID
 	pans, err := s.panRepo.FindAll()
 	if err != nil {
 		utils.Error(fmt.Sprintf("[迅雷保活] 获取平台列表失败: %v", err))
-		return
+		return "", err
 	}
 	var xunleiPanID uint
 	found := false
@@ -101,17 +77,17 @@ func (s *XunleiKeepaliveScheduler) keepalive() {
 	}
 	if !found {
 		utils.Debug("[迅雷保活] 未找到 xunlei 平台，跳过")
-		return
+		return "未找到 xunlei 平台", cronjob.ErrSkipped
 	}
 
 	accounts, err := s.cksRepo.FindByPanID(xunleiPanID)
 	if err != nil {
 		utils.Error(fmt.Sprintf("[迅雷保活] 获取迅雷账号失败: %v", err))
-		return
+		return "", err
 	}
 	if len(accounts) == 0 {
 		utils.Debug("[迅雷保活] 没有迅雷账号，跳过")
-		return
+		return "没有迅雷账号", cronjob.ErrSkipped
 	}
 
 	factory := panutils.GetInstance()
@@ -134,6 +110,13 @@ func (s *XunleiKeepaliveScheduler) keepalive() {
 		xunlei.SetCKSRepository(s.cksRepo, acc)
 		if err := xunlei.Keepalive(); err != nil {
 			utils.Error(fmt.Sprintf("[迅雷保活] 账号 %d (%s) 刷新失败: %v", acc.ID, acc.Username, err))
+			eventbus.Publish(eventbus.TypeAccountFailed, map[string]interface{}{
+				"account_id": acc.ID,
+				"platform":   "xunlei",
+				"username":   acc.Username,
+				"reason":     err.Error(),
+				"source":     "keepalive",
+			})
 			failCnt++
 			continue
 		}
@@ -141,5 +124,7 @@ func (s *XunleiKeepaliveScheduler) keepalive() {
 		utils.Debug(fmt.Sprintf("[迅雷保活] 账号 %d (%s) 刷新成功", acc.ID, acc.Username))
 	}
 
-	utils.Info(fmt.Sprintf("[迅雷保活] 完成：成功 %d，失败 %d，跳过(无效) %d", successCnt, failCnt, skipCnt))
+	summary := fmt.Sprintf("成功 %d，失败 %d，跳过(无效) %d", successCnt, failCnt, skipCnt)
+	utils.Info("[迅雷保活] 完成：%s", summary)
+	return summary, nil
 }
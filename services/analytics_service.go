@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+)
+
+// AnalyticsConfig 统计日汇总配置，保留天数为 0 表示不清理对应的原始记录
+type AnalyticsConfig struct {
+	RollupEnabled       bool `json:"rollup_enabled"`
+	ViewRetentionDays   int  `json:"view_retention_days"`
+	SearchRetentionDays int  `json:"search_retention_days"`
+	APILogRetentionDays int  `json:"api_log_retention_days"`
+}
+
+// Validate 校验配置。保留天数至少 2 天，保证昨日数据汇总前不会被清理
+func (c AnalyticsConfig) Validate() error {
+	for _, days := range []int{c.ViewRetentionDays, c.SearchRetentionDays, c.APILogRetentionDays} {
+		if days != 0 && (days < 2 || days > 3650) {
+			return fmt.Errorf("保留天数需为 0（不清理）或 2-3650 之间")
+		}
+	}
+	return nil
+}
+
+// AnalyticsBackfillResult 批量汇总结果
+type AnalyticsBackfillResult struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Rolled  []string `json:"rolled"`
+	Skipped int      `json:"skipped"`
+	Failed  []string `json:"failed,omitempty"`
+}
+
+// AnalyticsNightlyResult 每日任务结果：补齐未汇总的日期并清理过期原始记录
+type AnalyticsNightlyResult struct {
+	Backfill AnalyticsBackfillResult `json:"backfill"`
+	Purged   map[string]int64        `json:"purged"`
+}
+
+// AnalyticsTrend 趋势数据
+type AnalyticsTrend struct {
+	Metric  string                        `json:"metric"`
+	GroupBy string                        `json:"group_by,omitempty"`
+	From    string                        `json:"from"`
+	To      string                        `json:"to"`
+	Points  []entity.AnalyticsSeriesPoint `json:"points"`
+}
+
+// AnalyticsStatus 汇总状态
+type AnalyticsStatus struct {
+	Config        AnalyticsConfig             `json:"config"`
+	LastRolledDay string                      `json:"last_rolled_day"`
+	RolledDays    int                         `json:"rolled_days"`
+	Recent        []entity.AnalyticsRollupDay `json:"recent"`
+}
+
+// AnalyticsService 统计数据仓库：每日把 resource_views / search_stats / api_access_logs
+// 汇总到按日分表的汇总表，趋势与排行优先读取汇总表，当天及尚未汇总的日期实时统计原始表，
+// 原始记录按保留天数清理后历史统计不受影响。
+type AnalyticsService struct {
+	repo       repo.AnalyticsRepository
+	configRepo repo.SystemConfigRepository
+
+	// 便于测试替换
+	now func() time.Time
+
+	runMutex sync.Mutex
+}
+
+// NewAnalyticsService 创建统计数据仓库服务
+func NewAnalyticsService(analyticsRepo repo.AnalyticsRepository, configRepo repo.SystemConfigRepository) *AnalyticsService {
+	return &AnalyticsService{
+		repo:       analyticsRepo,
+		configRepo: configRepo,
+		now:        utils.GetCurrentTime,
+	}
+}
+
+// Config 读取配置，未设置时使用默认值
+func (s *AnalyticsService) Config() AnalyticsConfig {
+	cfg := AnalyticsConfig{
+		RollupEnabled:       entity.AnalyticsConfigDefaultRollupEnabled,
+		ViewRetentionDays:   entity.AnalyticsConfigDefaultViewRetentionDays,
+		SearchRetentionDays: entity.AnalyticsConfigDefaultSearchRetentionDays,
+		APILogRetentionDays: entity.AnalyticsConfigDefaultAPILogRetentionDays,
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.AnalyticsConfigKeyRollupEnabled); err == nil && v != "" {
+		cfg.RollupEnabled = v == "true"
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.AnalyticsConfigKeyViewRetentionDays); err == nil && v >= 0 {
+		cfg.ViewRetentionDays = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.AnalyticsConfigKeySearchRetentionDays); err == nil && v >= 0 {
+		cfg.SearchRetentionDays = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.AnalyticsConfigKeyAPILogRetentionDays); err == nil && v >= 0 {
+		cfg.APILogRetentionDays = v
+	}
+	return cfg
+}
+
+// SaveConfig 校验并保存配置
+func (s *AnalyticsService) SaveConfig(cfg AnalyticsConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.AnalyticsConfigKeyRollupEnabled, Value: strconv.FormatBool(cfg.RollupEnabled), Type: entity.ConfigTypeBool},
+		{Key: entity.AnalyticsConfigKeyViewRetentionDays, Value: strconv.Itoa(cfg.ViewRetentionDays), Type: entity.ConfigTypeInt},
+		{Key: entity.AnalyticsConfigKeySearchRetentionDays, Value: strconv.Itoa(cfg.SearchRetentionDays), Type: entity.ConfigTypeInt},
+		{Key: entity.AnalyticsConfigKeyAPILogRetentionDays, Value: strconv.Itoa(cfg.APILogRetentionDays), Type: entity.ConfigTypeInt},
+	})
+}
+
+// RollupDay 重新汇总指定日期（YYYY-MM-DD），只允许已结束的日期
+func (s *AnalyticsService) RollupDay(day string) (*entity.AnalyticsRollupDay, error) {
+	d, err := time.ParseInLocation(utils.TimeFormatDate, day, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("日期格式错误: %s", day)
+	}
+	if !d.Before(startOfDay(s.now())) {
+		return nil, fmt.Errorf("只能汇总今天之前的日期")
+	}
+
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	return s.repo.RollupDay(day)
+}
+
+// Backfill 汇总 [from, to] 内的日期。from 为空时从原始记录最早的日期开始，to 为空或晚于昨天时取昨天；
+// force 为 false 时跳过已汇总的日期，为 true 时全部重新汇总（结果与首次汇总一致）。
+func (s *AnalyticsService) Backfill(ctx context.Context, from, to string, force bool) (AnalyticsBackfillResult, error) {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	return s.backfill(ctx, from, to, force)
+}
+
+func (s *AnalyticsService) backfill(ctx context.Context, from, to string, force bool) (AnalyticsBackfillResult, error) {
+	yesterday := startOfDay(s.now()).AddDate(0, 0, -1)
+	result := AnalyticsBackfillResult{Rolled: []string{}}
+
+	end := yesterday
+	if to != "" {
+		d, err := time.ParseInLocation(utils.TimeFormatDate, to, time.Local)
+		if err != nil {
+			return result, fmt.Errorf("结束日期格式错误: %s", to)
+		}
+		if d.Before(end) {
+			end = d
+		}
+	}
+	if from == "" {
+		earliest, err := s.repo.EarliestRawDate()
+		if err != nil {
+			return result, fmt.Errorf("查询最早记录日期失败: %v", err)
+		}
+		if earliest == "" {
+			return result, nil
+		}
+		from = earliest
+	}
+	start, err := time.ParseInLocation(utils.TimeFormatDate, from, time.Local)
+	if err != nil {
+		return result, fmt.Errorf("开始日期格式错误: %s", from)
+	}
+	result.From = start.Format(utils.TimeFormatDate)
+	result.To = end.Format(utils.TimeFormatDate)
+	if start.After(end) {
+		return result, nil
+	}
+
+	rolled := map[string]bool{}
+	if !force {
+		days, err := s.repo.FindRollupDays(result.From, result.To)
+		if err != nil {
+			return result, fmt.Errorf("查询已汇总日期失败: %v", err)
+		}
+		for _, d := range days {
+			rolled[d.Date.Format(utils.TimeFormatDate)] = true
+		}
+	}
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		day := d.Format(utils.TimeFormatDate)
+		if rolled[day] {
+			result.Skipped++
+			continue
+		}
+		if _, err := s.repo.RollupDay(day); err != nil {
+			utils.Error("汇总统计数据失败 %s: %v", day, err)
+			result.Failed = append(result.Failed, day)
+			continue
+		}
+		result.Rolled = append(result.Rolled, day)
+	}
+	return result, nil
+}
+
+// RunNightly 每日任务：补齐原始记录中所有未汇总的日期（通常只有昨天），再按保留天数清理原始记录
+func (s *AnalyticsService) RunNightly(ctx context.Context) (AnalyticsNightlyResult, error) {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+
+	result := AnalyticsNightlyResult{Purged: map[string]int64{}}
+	cfg := s.Config()
+	if !cfg.RollupEnabled {
+		return result, nil
+	}
+
+	backfill, err := s.backfill(ctx, "", "", false)
+	result.Backfill = backfill
+	if err != nil {
+		return result, err
+	}
+
+	today := startOfDay(s.now())
+	for table, days := range map[string]int{
+		entity.AnalyticsRawViews:    cfg.ViewRetentionDays,
+		entity.AnalyticsRawSearches: cfg.SearchRetentionDays,
+		entity.AnalyticsRawAPILogs:  cfg.APILogRetentionDays,
+	} {
+		if days <= 0 {
+			continue
+		}
+		before := today.AddDate(0, 0, -days).Format(utils.TimeFormatDate)
+		n, err := s.repo.PurgeRaw(table, before, 5000)
+		result.Purged[table] = n
+		if err != nil {
+			return result, fmt.Errorf("清理 %s 失败: %v", table, err)
+		}
+	}
+	return result, nil
+}
+
+// Trend 最近 days 天（含今天）的每日趋势。groupBy 为空时每天一个点，缺失的日期补 0
+func (s *AnalyticsService) Trend(metric, groupBy string, days int) (*AnalyticsTrend, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if days > 366 {
+		return nil, fmt.Errorf("统计天数不能超过366天")
+	}
+	today := startOfDay(s.now())
+	from := today.AddDate(0, 0, -(days - 1)).Format(utils.TimeFormatDate)
+	to := today.Format(utils.TimeFormatDate)
+
+	liveFrom, err := s.liveFrom(from)
+	if err != nil {
+		return nil, err
+	}
+	points, err := s.repo.Series(metric, groupBy, from, liveFrom, to)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := &AnalyticsTrend{Metric: metric, GroupBy: groupBy, From: from, To: to, Points: points}
+	if groupBy == "" {
+		values := make(map[string]int64, len(points))
+		for _, p := range points {
+			values[p.Date] = p.Value
+		}
+		trend.Points = make([]entity.AnalyticsSeriesPoint, 0, days)
+		for i := days - 1; i >= 0; i-- {
+			date := today.AddDate(0, 0, -i).Format(utils.TimeFormatDate)
+			trend.Points = append(trend.Points, entity.AnalyticsSeriesPoint{Date: date, Value: values[date]})
+		}
+	}
+	if trend.Points == nil {
+		trend.Points = []entity.AnalyticsSeriesPoint{}
+	}
+	return trend, nil
+}
+
+// Leaderboard 最近 days 天（含今天）的排行，days 为 0 表示全部历史
+func (s *AnalyticsService) Leaderboard(dimension string, days, limit int) ([]entity.AnalyticsLeaderboardItem, error) {
+	if days < 0 || days > 3650 {
+		return nil, fmt.Errorf("统计天数无效")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	today := startOfDay(s.now())
+	from := ""
+	if days > 0 {
+		from = today.AddDate(0, 0, -(days - 1)).Format(utils.TimeFormatDate)
+	}
+
+	liveFrom, err := s.liveFrom(from)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.Leaderboard(dimension, from, liveFrom, today.Format(utils.TimeFormatDate), limit)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []entity.AnalyticsLeaderboardItem{}
+	}
+	if dimension == repo.AnalyticsDimensionSource {
+		for i := range items {
+			items[i].Name = entity.SourceDisplayName(items[i].Key)
+		}
+	}
+	return items, nil
+}
+
+// TotalViews 全部历史的访问总次数（汇总表 + 未汇总日期的原始记录）
+func (s *AnalyticsService) TotalViews() (int64, error) {
+	liveFrom, err := s.liveFrom("")
+	if err != nil {
+		return 0, err
+	}
+	points, err := s.repo.Series(repo.AnalyticsMetricViews, "", "", liveFrom, startOfDay(s.now()).Format(utils.TimeFormatDate))
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, p := range points {
+		total += p.Value
+	}
+	return total, nil
+}
+
+// Status 汇总状态与最近30个已汇总日期
+func (s *AnalyticsService) Status() (*AnalyticsStatus, error) {
+	days, err := s.repo.FindRollupDays("", "")
+	if err != nil {
+		return nil, err
+	}
+	status := &AnalyticsStatus{Config: s.Config(), RolledDays: len(days), Recent: []entity.AnalyticsRollupDay{}}
+	if len(days) > 0 {
+		status.LastRolledDay = days[len(days)-1].Date.Format(utils.TimeFormatDate)
+	}
+	for i := len(days) - 1; i >= 0 && len(status.Recent) < 30; i-- {
+		status.Recent = append(status.Recent, days[i])
+	}
+	return status, nil
+}
+
+// liveFrom 从 from（为空时取第一个已汇总日期）起第一个未汇总的日期，该日及之后改为统计原始记录；
+// 中间的日期全部已汇总时返回今天。
+func (s *AnalyticsService) liveFrom(from string) (string, error) {
+	today := startOfDay(s.now())
+	days, err := s.repo.FindRollupDays(from, today.AddDate(0, 0, -1).Format(utils.TimeFormatDate))
+	if err != nil {
+		return "", fmt.Errorf("查询已汇总日期失败: %v", err)
+	}
+	if len(days) == 0 {
+		if from == "" {
+			return "1970-01-01", nil
+		}
+		return from, nil
+	}
+
+	rolled := make(map[string]bool, len(days))
+	for _, d := range days {
+		rolled[d.Date.Format(utils.TimeFormatDate)] = true
+	}
+	if from == "" {
+		from = days[0].Date.Format(utils.TimeFormatDate)
+	}
+	start, _ := time.ParseInLocation(utils.TimeFormatDate, from, time.Local)
+	for d := start; d.Before(today); d = d.AddDate(0, 0, 1) {
+		if day := d.Format(utils.TimeFormatDate); !rolled[day] {
+			return day, nil
+		}
+	}
+	return today.Format(utils.TimeFormatDate), nil
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"gorm.io/gorm"
+)
+
+// analyticsTestTx 在本地 Postgres 的事务中准备汇总相关表，测试结束后回滚；无数据库时跳过。
+// 测试数据使用 2001 年的日期，不会与真实记录的汇总日期重叠。
+func analyticsTestTx(t *testing.T) *gorm.DB {
+	t.Helper()
+	if !ensureTestDB(t) {
+		t.Skip("跳过：无可用数据库连接")
+	}
+	tx := db.DB.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	if err := tx.AutoMigrate(&entity.ViewDailyStat{}, &entity.SearchDailyStat{}, &entity.APIAccessDailyStat{}, &entity.AnalyticsRollupDay{}); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// analyticsTestLocation 数据库会话时区，原始记录按该时区的自然日汇总
+func analyticsTestLocation(t *testing.T, tx *gorm.DB) *time.Location {
+	t.Helper()
+	var name string
+	if err := tx.Raw("SELECT current_setting('TimeZone')").Row().Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("跳过：无法加载数据库时区 %s: %v", name, err)
+	}
+	return loc
+}
+
+// newDBAnalyticsService 基于事务创建服务，当前时间固定为 2001-03-10 15:00
+func newDBAnalyticsService(tx *gorm.DB) *AnalyticsService {
+	configRepo := &fakeSearchEngineConfigRepo{values: map[string]string{
+		entity.AnalyticsConfigKeyRollupEnabled:       "true",
+		entity.AnalyticsConfigKeyViewRetentionDays:   "3",
+		entity.AnalyticsConfigKeySearchRetentionDays: "0",
+		entity.AnalyticsConfigKeyAPILogRetentionDays: "2",
+	}}
+	s := NewAnalyticsService(repo.NewAnalyticsRepository(tx), configRepo)
+	s.now = func() time.Time { return time.Date(2001, 3, 10, 15, 0, 0, 0, time.Local) }
+	return s
+}
+
+func createAnalyticsTestResource(t *testing.T, tx *gorm.DB) uint {
+	t.Helper()
+	resource := entity.Resource{Title: "analytics-test", URL: fmt.Sprintf("https://example.com/analytics-%d", time.Now().UnixNano())}
+	if err := tx.Create(&resource).Error; err != nil {
+		t.Fatal(err)
+	}
+	return resource.ID
+}
+
+func createAnalyticsTestViews(t *testing.T, tx *gorm.DB, resourceID uint, times ...time.Time) {
+	t.Helper()
+	for i, at := range times {
+		view := entity.ResourceView{ResourceID: resourceID, IPAddress: fmt.Sprintf("10.0.1.%d", i+1), Source: "web", CreatedAt: at}
+		if err := tx.Create(&view).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// rolledViews 读取汇总表中每日的访问次数
+func rolledViews(t *testing.T, s *AnalyticsService, from, to string) map[string]int64 {
+	t.Helper()
+	days, err := s.repo.FindRollupDays(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	views := make(map[string]int64, len(days))
+	for _, d := range days {
+		views[d.Date.Format("2006-01-02")] = d.Views
+	}
+	return views
+}
+
+func TestAnalyticsConfigValidate(t *testing.T) {
+	if err := (AnalyticsConfig{ViewRetentionDays: 1}).Validate(); err == nil {
+		t.Error("retention shorter than 2 days should be rejected")
+	}
+	if err := (AnalyticsConfig{ViewRetentionDays: 0, APILogRetentionDays: 2}).Validate(); err != nil {
+		t.Errorf("retention 0 keeps raw records and should be allowed: %v", err)
+	}
+}
+
+// TestAnalyticsServiceBackfill 验证按数据库时区的自然日分桶、跳过已汇总日期，以及强制重算时覆盖而不是重复写入
+func TestAnalyticsServiceBackfill(t *testing.T) {
+	tx := analyticsTestTx(t)
+	loc := analyticsTestLocation(t, tx)
+	s := newDBAnalyticsService(tx)
+	resourceID := createAnalyticsTestResource(t, tx)
+
+	createAnalyticsTestViews(t, tx, resourceID,
+		time.Date(2001, 3, 5, 10, 0, 0, 0, loc),
+		time.Date(2001, 3, 5, 23, 59, 59, 0, loc),
+		time.Date(2001, 3, 6, 0, 0, 0, 0, loc),
+		time.Date(2001, 3, 9, 12, 0, 0, 0, loc),
+		time.Date(2001, 3, 10, 9, 0, 0, 0, loc),
+	)
+	if _, err := s.RollupDay("2001-03-06"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.Backfill(context.Background(), "2001-03-05", "2001-03-20", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 结束日期最晚为昨天
+	if result.From != "2001-03-05" || result.To != "2001-03-09" || result.Skipped != 1 || len(result.Rolled) != 4 || len(result.Failed) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	want := map[string]int64{"2001-03-05": 2, "2001-03-06": 1, "2001-03-07": 0, "2001-03-08": 0, "2001-03-09": 1}
+	if got := rolledViews(t, s, "2001-03-01", "2001-03-10"); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rolled views = %v, want %v", got, want)
+	}
+	if _, err := s.RollupDay("2001-03-10"); err == nil {
+		t.Error("today should not be rolled up")
+	}
+
+	// 补录一条访问后强制重算：汇总结果更新，明细仍是每个资源、来源一行
+	createAnalyticsTestViews(t, tx, resourceID, time.Date(2001, 3, 6, 20, 0, 0, 0, loc))
+	result, err = s.Backfill(context.Background(), "2001-03-06", "2001-03-06", true)
+	if err != nil || len(result.Rolled) != 1 || result.Skipped != 0 {
+		t.Fatalf("force result = %+v, err = %v", result, err)
+	}
+	if got := rolledViews(t, s, "2001-03-06", "2001-03-06")["2001-03-06"]; got != 2 {
+		t.Errorf("re-rolled views = %d, want 2", got)
+	}
+	var rows int64
+	tx.Model(&entity.ViewDailyStat{}).Where("date = ?", "2001-03-06").Count(&rows)
+	if rows != 1 {
+		t.Errorf("view_daily_stats rows = %d, want 1", rows)
+	}
+}
+
+// TestAnalyticsServiceRunNightly 验证每日任务补齐未汇总日期并按保留天数清理原始记录，清理后历史统计不变
+func TestAnalyticsServiceRunNightly(t *testing.T) {
+	tx := analyticsTestTx(t)
+	loc := analyticsTestLocation(t, tx)
+	s := newDBAnalyticsService(tx)
+	resourceID := createAnalyticsTestResource(t, tx)
+
+	for day := 5; day <= 9; day++ {
+		createAnalyticsTestViews(t, tx, resourceID, time.Date(2001, 3, day, 12, 0, 0, 0, loc))
+	}
+	if err := tx.Create(&entity.SearchStat{Keyword: "三体", Count: 1, Date: time.Date(2001, 3, 5, 12, 0, 0, 0, loc), IP: "10.0.0.3", Source: "web"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, day := range []int{7, 8} {
+		log := entity.APIAccessLog{IP: "10.0.0.4", Endpoint: "/api/public/resources", Method: "GET", ResponseStatus: 200, ProcessingTime: 10, CreatedAt: time.Date(2001, 3, day, 12, 0, 0, 0, loc)}
+		if err := tx.Create(&log).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Backfill(context.Background(), "2001-03-05", "2001-03-07", false); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := s.RunNightly(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(result.Backfill.Rolled) != "[2001-03-08 2001-03-09]" {
+		t.Errorf("rolled = %v", result.Backfill.Rolled)
+	}
+	// 访问保留 3 天清理 03-07 之前，API 日志保留 2 天清理 03-08 之前，搜索保留 0 天不清理
+	if result.Purged[entity.AnalyticsRawViews] != 2 || result.Purged[entity.AnalyticsRawAPILogs] != 1 || result.Purged[entity.AnalyticsRawSearches] != 0 {
+		t.Errorf("purged = %v", result.Purged)
+	}
+	var searches int64
+	tx.Model(&entity.SearchStat{}).Where("keyword = ? AND date < ?", "三体", "2001-03-06").Count(&searches)
+	if searches != 1 {
+		t.Error("retention 0 should keep raw records")
+	}
+
+	if total, err := s.TotalViews(); err != nil || total != 5 {
+		t.Errorf("total = %d, err = %v; purged days should still be counted", total, err)
+	}
+}
+
+// TestAnalyticsServiceTrendUsesLiveDataAfterGap 验证趋势在已汇总日期读汇总表，从第一个未汇总日期起统计原始记录
+func TestAnalyticsServiceTrendUsesLiveDataAfterGap(t *testing.T) {
+	tx := analyticsTestTx(t)
+	loc := analyticsTestLocation(t, tx)
+	s := newDBAnalyticsService(tx)
+	resourceID := createAnalyticsTestResource(t, tx)
+
+	at := func(day, hour int) time.Time { return time.Date(2001, 3, day, hour, 0, 0, 0, loc) }
+	createAnalyticsTestViews(t, tx, resourceID, at(4, 10), at(7, 10), at(7, 11), at(8, 9), at(8, 10), at(10, 8), at(10, 9))
+	for _, day := range []string{"2001-03-04", "2001-03-05", "2001-03-06", "2001-03-08"} {
+		if _, err := s.RollupDay(day); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 03-04 的原始记录清理后仍应从汇总表读到
+	if _, err := s.repo.PurgeRaw(entity.AnalyticsRawViews, "2001-03-05", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	trend, err := s.Trend(repo.AnalyticsMetricViews, "", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend.From != "2001-03-04" || trend.To != "2001-03-10" || len(trend.Points) != 7 {
+		t.Fatalf("trend = %+v", trend)
+	}
+	want := []int64{1, 0, 0, 2, 2, 0, 2}
+	for i, p := range trend.Points {
+		if p.Value != want[i] {
+			t.Errorf("points = %+v, want values %v", trend.Points, want)
+			break
+		}
+	}
+
+	// 补齐缺口后全部历史来自汇总表加今天的原始记录
+	for _, day := range []string{"2001-03-07", "2001-03-09"} {
+		if _, err := s.RollupDay(day); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if total, err := s.TotalViews(); err != nil || total != 7 {
+		t.Errorf("total = %d, err = %v", total, err)
+	}
+}
+
+// TestAnalyticsRollupMatchesLive 在本地 Postgres 上验证汇总结果与实时统计一致、可重复执行，
+// 且原始记录清理后重新汇总不会丢失历史数据。所有写入在事务中进行并回滚。
+func TestAnalyticsRollupMatchesLive(t *testing.T) {
+	tx := analyticsTestTx(t)
+
+	// 选用历史日期，避免与真实数据混在一起
+	const day = "2001-03-04"
+	at := time.Date(2001, 3, 4, 12, 0, 0, 0, time.Local)
+	resource := entity.Resource{Title: "analytics-test", URL: fmt.Sprintf("https://example.com/analytics-%d", time.Now().UnixNano())}
+	if err := tx.Create(&resource).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, ip := range []string{"10.0.0.1", "10.0.0.1", "10.0.0.2"} {
+		source := "web"
+		if i == 2 {
+			source = "wechat"
+		}
+		if err := tx.Create(&entity.ResourceView{ResourceID: resource.ID, IPAddress: ip, Source: source, CreatedAt: at}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, kw := range []string{"三体", "三体"} {
+		if err := tx.Create(&entity.SearchStat{Keyword: kw, Count: 1, Date: at, IP: "10.0.0.3", Source: "web"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, status := range []int{200, 500} {
+		if err := tx.Create(&entity.APIAccessLog{IP: "10.0.0.4", Endpoint: "/api/public/resources", Method: "GET", ResponseStatus: status, ProcessingTime: 10, CreatedAt: at}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := repo.NewAnalyticsRepository(tx)
+	for i := 0; i < 2; i++ {
+		rolled, err := r.RollupDay(day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rolled.Views != 3 || rolled.UniqueVisitors != 2 || rolled.Searches != 2 || rolled.APIRequests != 2 || rolled.APIErrors != 1 {
+			t.Errorf("run %d: rolled = %+v", i, rolled)
+		}
+	}
+	var rows int64
+	tx.Model(&entity.ViewDailyStat{}).Where("date = ?", day).Count(&rows)
+	if rows != 2 {
+		t.Errorf("view_daily_stats rows = %d, want 2 (one per source)", rows)
+	}
+
+	for _, metric := range []string{repo.AnalyticsMetricViews, repo.AnalyticsMetricSearches, repo.AnalyticsMetricAPIRequests, repo.AnalyticsMetricVisitors} {
+		fromRollup, err := r.Series(metric, "", day, "2001-03-05", day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		live, err := r.Series(metric, "", day, day, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fromRollup) != 1 || len(live) != 1 || fromRollup[0] != live[0] {
+			t.Errorf("%s: rollup = %+v, live = %+v", metric, fromRollup, live)
+		}
+	}
+	board, err := r.Leaderboard(repo.AnalyticsDimensionSource, day, "2001-03-05", day, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board) != 2 || board[0].Key != "web" || board[0].Value != 2 || board[0].Unique != 1 {
+		t.Errorf("board = %+v", board)
+	}
+
+	purged, err := r.PurgeRaw(entity.AnalyticsRawViews, "2001-03-05", 2)
+	if err != nil || purged != 3 {
+		t.Fatalf("purged = %d, err = %v", purged, err)
+	}
+	rolled, err := r.RollupDay(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rolled.Views != 3 || rolled.ViewsPurgedAt == nil {
+		t.Errorf("re-rollup after purge should keep views, rolled = %+v", rolled)
+	}
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyTokenPrefix 密钥明文前缀，用于区分旧版全局 Token
+	apiKeyTokenPrefix = "uk_"
+	// apiKeyIDLength 密钥前缀中随机标识的长度，前缀明文保存用于查找
+	apiKeyIDLength = 8
+	// apiKeySecretLength 密钥秘密部分长度
+	apiKeySecretLength = 32
+
+	apiKeyAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API Key 不存在")
+	ErrAPIKeyRevoked  = errors.New("API Key 已吊销")
+)
+
+// APIKeyError 公开API认证失败，携带HTTP状态码，限流时带重试等待秒数
+type APIKeyError struct {
+	Status     int
+	Message    string
+	RetryAfter int
+}
+
+func (e *APIKeyError) Error() string { return e.Message }
+
+// HTTPStatus 返回HTTP状态码
+func (e *APIKeyError) HTTPStatus() int { return e.Status }
+
+// RetryAfterSeconds 返回建议的重试等待秒数，0 表示无
+func (e *APIKeyError) RetryAfterSeconds() int { return e.RetryAfter }
+
+// apiKeyBucket 每分钟限流令牌桶
+type apiKeyBucket struct {
+	limit  int
+	tokens float64
+	last   time.Time
+}
+
+// APIKeyService 公开API密钥服务：签发、轮换、吊销，以及请求时的认证、作用域、IP白名单、限流与每日配额
+type APIKeyService struct {
+	repo       repo.APIKeyRepository
+	configRepo repo.SystemConfigRepository
+
+	// legacyEnabled 是否仍接受旧版全局 Token，由环境变量 API_LEGACY_TOKEN_ENABLED 开启，默认关闭
+	legacyEnabled bool
+
+	mu      sync.Mutex
+	buckets map[uint]*apiKeyBucket
+	now     func() time.Time
+}
+
+// NewAPIKeyService 创建公开API密钥服务
+func NewAPIKeyService(apiKeyRepo repo.APIKeyRepository, configRepo repo.SystemConfigRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:          apiKeyRepo,
+		configRepo:    configRepo,
+		legacyEnabled: os.Getenv("API_LEGACY_TOKEN_ENABLED") == "true",
+		buckets:       make(map[uint]*apiKeyBucket),
+		now:           utils.GetCurrentTime,
+	}
+}
+
+// Authenticate 校验请求携带的令牌是否可以访问 scope。
+// 令牌只能通过请求头传递。uk_ 开头的为 API Key；其余按旧版全局 Token 校验，
+// 旧版 Token 拥有全部作用域且不限流，仅在显式开启 API_LEGACY_TOKEN_ENABLED 时可用。
+// 返回的错误为 *APIKeyError
+func (s *APIKeyService) Authenticate(token, ip, scope string, fromQuery bool) (*entity.APIKey, error) {
+	if token == "" {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "缺少API Token"}
+	}
+	if fromQuery {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Token 只能通过 X-API-Token 请求头传递"}
+	}
+	if !strings.HasPrefix(token, apiKeyTokenPrefix) {
+		return s.authenticateLegacy(token)
+	}
+
+	prefix, ok := apiKeyPrefixOf(token)
+	if !ok {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Token无效"}
+	}
+	key, err := s.repo.FindByPrefix(prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Token无效"}
+	}
+	if err != nil {
+		utils.Error("查询API Key失败: %v", err)
+		return nil, &APIKeyError{Status: http.StatusInternalServerError, Message: "系统繁忙，请稍后再试"}
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(token)), []byte(key.KeyHash)) != 1 {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Token无效"}
+	}
+
+	now := s.now()
+	switch key.Status(now) {
+	case "revoked":
+		return key, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Key 已吊销"}
+	case "expired":
+		return key, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Key 已过期"}
+	}
+	if !key.IPAllowed(ip) {
+		return key, s.reject(key, &APIKeyError{Status: http.StatusForbidden, Message: "IP 不在该 API Key 的白名单内"})
+	}
+	if !key.HasScope(scope) {
+		return key, s.reject(key, &APIKeyError{Status: http.StatusForbidden, Message: "该 API Key 无权访问此接口"})
+	}
+	if ok, wait := s.allow(key, now); !ok {
+		return key, s.reject(key, &APIKeyError{Status: http.StatusTooManyRequests, Message: "请求过于频繁，请稍后再试", RetryAfter: wait})
+	}
+
+	ok, err = s.repo.IncrementUsage(key.ID, now.Format(utils.TimeFormatDate), key.DailyQuota)
+	if err != nil {
+		utils.Error("累计API Key用量失败: %v", err)
+		return key, &APIKeyError{Status: http.StatusInternalServerError, Message: "系统繁忙，请稍后再试"}
+	}
+	if !ok {
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		return key, s.reject(key, &APIKeyError{
+			Status:     http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("今日请求次数已达上限 %d", key.DailyQuota),
+			RetryAfter: int(math.Ceil(midnight.Sub(now).Seconds())),
+		})
+	}
+
+	go func(id uint) {
+		if err := s.repo.TouchUsage(id, ip, now); err != nil {
+			utils.Error("更新API Key使用记录失败: %v", err)
+		}
+	}(key.ID)
+	return key, nil
+}
+
+// authenticateLegacy 校验旧版全局 Token
+func (s *APIKeyService) authenticateLegacy(token string) (*entity.APIKey, error) {
+	if !s.legacyEnabled {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "旧版全局 Token 已停用，请在后台创建 API Key"}
+	}
+	legacy, err := s.configRepo.GetConfigValue(entity.ConfigKeyApiToken)
+	if err != nil {
+		return nil, &APIKeyError{Status: http.StatusInternalServerError, Message: "系统配置获取失败"}
+	}
+	if legacy == "" || subtle.ConstantTimeCompare([]byte(token), []byte(legacy)) != 1 {
+		return nil, &APIKeyError{Status: http.StatusUnauthorized, Message: "API Token无效"}
+	}
+	return &entity.APIKey{Name: "legacy", Scopes: strings.Join(entity.APIScopes, ",")}, nil
+}
+
+// reject 累计被拒绝请求数并返回错误
+func (s *APIKeyService) reject(key *entity.APIKey, apiErr *APIKeyError) error {
+	if err := s.repo.IncrementRejected(key.ID, s.now().Format(utils.TimeFormatDate)); err != nil {
+		utils.Error("累计API Key拒绝次数失败: %v", err)
+	}
+	return apiErr
+}
+
+// allow 按每分钟上限做令牌桶限流，不通过时返回需要等待的秒数
+func (s *APIKeyService) allow(key *entity.APIKey, now time.Time) (bool, int) {
+	if key.RateLimit <= 0 {
+		return true, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := float64(key.RateLimit)
+	b := s.buckets[key.ID]
+	if b == nil || b.limit != key.RateLimit {
+		b = &apiKeyBucket{limit: key.RateLimit, tokens: limit, last: now}
+		s.buckets[key.ID] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(limit, b.tokens+elapsed*limit/60)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, int(math.Ceil((1 - b.tokens) * 60 / limit))
+}
+
+// Issue 签发新密钥，返回的明文只在此时可见
+func (s *APIKeyService) Issue(req dto.APIKeyRequest, createdBy string) (*entity.APIKey, string, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, "", err
+	}
+	token, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key := &entity.APIKey{Prefix: prefix, KeyHash: hashAPIKey(token), CreatedBy: createdBy}
+	applyAPIKeyRequest(key, req)
+	if err := s.repo.Create(key); err != nil {
+		return nil, "", err
+	}
+	return key, token, nil
+}
+
+// Update 更新密钥名称、作用域、限流、配额、白名单与过期时间，不改变密钥本身
+func (s *APIKeyService) Update(id uint, req dto.APIKeyRequest) (*entity.APIKey, error) {
+	key, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+	applyAPIKeyRequest(key, req)
+	if err := s.repo.Update(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate 为密钥生成新的明文，旧明文立即失效，其余设置与用量保持不变
+func (s *APIKeyService) Rotate(id uint) (*entity.APIKey, string, error) {
+	key, err := s.find(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.RevokedAt != nil {
+		return nil, "", ErrAPIKeyRevoked
+	}
+	token, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	key.Prefix = prefix
+	key.KeyHash = hashAPIKey(token)
+	if err := s.repo.Update(key); err != nil {
+		return nil, "", err
+	}
+	return key, token, nil
+}
+
+// Revoke 吊销密钥，吊销后不可恢复
+func (s *APIKeyService) Revoke(id uint) (*entity.APIKey, error) {
+	key, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := s.now()
+		key.RevokedAt = &now
+		if err := s.repo.Update(key); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	delete(s.buckets, key.ID)
+	s.mu.Unlock()
+	return key, nil
+}
+
+// List 获取全部密钥
+func (s *APIKeyService) List() ([]entity.APIKey, error) {
+	return s.repo.FindAllOrdered()
+}
+
+// Usage 获取密钥最近 days 天的每日用量
+func (s *APIKeyService) Usage(id uint, days int) (*dto.APIKeyUsageResponse, error) {
+	if _, err := s.find(id); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	from := now.AddDate(0, 0, -(days - 1)).Format(utils.TimeFormatDate)
+	usages, err := s.repo.FindDailyUsage(id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	today := now.Format(utils.TimeFormatDate)
+	result := &dto.APIKeyUsageResponse{Days: days, Daily: make([]dto.APIKeyDailyUsageDTO, 0, len(usages))}
+	for _, u := range usages {
+		date := u.Date.Format(utils.TimeFormatDate)
+		result.Daily = append(result.Daily, dto.APIKeyDailyUsageDTO{Date: date, Requests: u.Requests, Rejected: u.Rejected})
+		result.TotalRequests += u.Requests
+		result.TotalRejected += u.Rejected
+		if date == today {
+			result.TodayRequests = u.Requests
+		}
+	}
+	return result, nil
+}
+
+func (s *APIKeyService) find(id uint) (*entity.APIKey, error) {
+	key, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+func (s *APIKeyService) validateRequest(req dto.APIKeyRequest) error {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		return fmt.Errorf("过期时间必须晚于当前时间")
+	}
+	return nil
+}
+
+func applyAPIKeyRequest(key *entity.APIKey, req dto.APIKeyRequest) {
+	key.Name = strings.TrimSpace(req.Name)
+	key.Scopes = strings.Join(uniqueStrings(req.Scopes), ",")
+	key.RateLimit = req.RateLimit
+	key.DailyQuota = req.DailyQuota
+	key.AllowedIPs = strings.Join(uniqueStrings(req.AllowedIPs), ",")
+	key.ExpiresAt = req.ExpiresAt
+	key.Note = req.Note
+}
+
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" && !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// generateAPIKey 生成密钥明文 uk_<8位标识>_<32位秘密>，返回明文与用于查找的前缀
+func generateAPIKey() (string, string, error) {
+	id, err := randomAPIKeyString(apiKeyIDLength)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := randomAPIKeyString(apiKeySecretLength)
+	if err != nil {
+		return "", "", err
+	}
+	prefix := apiKeyTokenPrefix + id
+	return prefix + "_" + secret, prefix, nil
+}
+
+// apiKeyPrefixOf 从明文中取出前缀
+func apiKeyPrefixOf(token string) (string, bool) {
+	n := len(apiKeyTokenPrefix) + apiKeyIDLength
+	if len(token) != n+1+apiKeySecretLength || token[n] != '_' {
+		return "", false
+	}
+	return token[:n], true
+}
+
+func randomAPIKeyString(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(apiKeyAlphabet)))
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = apiKeyAlphabet[v.Int64()]
+	}
+	return string(b), nil
+}
+
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
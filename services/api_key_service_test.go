@@ -0,0 +1,241 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+
+	"gorm.io/gorm"
+)
+
+type fakeAPIKeyRepo struct {
+	repo.APIKeyRepository
+	keys     []*entity.APIKey
+	requests map[string]int64
+	rejected map[string]int64
+	touched  chan uint
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{
+		requests: map[string]int64{},
+		rejected: map[string]int64{},
+		touched:  make(chan uint, 100),
+	}
+}
+
+func (f *fakeAPIKeyRepo) Create(key *entity.APIKey) error {
+	key.ID = uint(len(f.keys) + 1)
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) Update(key *entity.APIKey) error { return nil }
+
+func (f *fakeAPIKeyRepo) FindByID(id uint) (*entity.APIKey, error) {
+	for _, k := range f.keys {
+		if k.ID == id {
+			return k, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAPIKeyRepo) FindByPrefix(prefix string) (*entity.APIKey, error) {
+	for _, k := range f.keys {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAPIKeyRepo) TouchUsage(id uint, ip string, at time.Time) error {
+	f.touched <- id
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) IncrementUsage(id uint, day string, quota int) (bool, error) {
+	k := day
+	if quota > 0 && f.requests[k] >= int64(quota) {
+		return false, nil
+	}
+	f.requests[k]++
+	return true, nil
+}
+
+func (f *fakeAPIKeyRepo) IncrementRejected(id uint, day string) error {
+	f.rejected[day]++
+	return nil
+}
+
+func newTestAPIKeyService(t *testing.T) (*APIKeyService, *fakeAPIKeyRepo, *time.Time) {
+	t.Helper()
+	keys := newFakeAPIKeyRepo()
+	config := &fakeBackupConfigRepo{values: map[string]string{entity.ConfigKeyApiToken: "legacy-token"}}
+	svc := NewAPIKeyService(keys, config)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.Local)
+	svc.now = func() time.Time { return now }
+	return svc, keys, &now
+}
+
+func apiKeyStatus(err error) int {
+	var apiErr *APIKeyError
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	return 0
+}
+
+func TestAPIKeyIssueAndAuthenticate(t *testing.T) {
+	svc, keys, now := newTestAPIKeyService(t)
+	expires := now.Add(48 * time.Hour)
+	key, token, err := svc.Issue(dto.APIKeyRequest{
+		Name:       "partner",
+		Scopes:     []string{entity.APIScopeSearch, entity.APIScopeSearch},
+		AllowedIPs: []string{"10.0.0.0/8", "192.168.1.5"},
+		ExpiresAt:  &expires,
+	}, "admin")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if !strings.HasPrefix(token, key.Prefix+"_") || key.Scopes != entity.APIScopeSearch {
+		t.Fatalf("token = %q, key = %+v", token, key)
+	}
+	if key.KeyHash == "" || strings.Contains(key.KeyHash, token) {
+		t.Fatal("only the hash of the token should be stored")
+	}
+
+	if got, err := svc.Authenticate(token, "10.1.2.3", entity.APIScopeSearch, false); err != nil || got.ID != key.ID {
+		t.Fatalf("Authenticate = %+v, %v", got, err)
+	}
+	if id := <-keys.touched; id != key.ID {
+		t.Fatalf("touched %d", id)
+	}
+
+	cases := []struct {
+		name      string
+		token, ip string
+		scope     string
+		fromQuery bool
+		status    int
+	}{
+		{"missing", "", "10.1.2.3", entity.APIScopeSearch, false, http.StatusUnauthorized},
+		{"wrong secret", key.Prefix + "_" + strings.Repeat("x", apiKeySecretLength), "10.1.2.3", entity.APIScopeSearch, false, http.StatusUnauthorized},
+		{"malformed", "uk_short", "10.1.2.3", entity.APIScopeSearch, false, http.StatusUnauthorized},
+		{"query string", token, "10.1.2.3", entity.APIScopeSearch, true, http.StatusUnauthorized},
+		{"ip not allowed", token, "172.16.0.1", entity.APIScopeSearch, false, http.StatusForbidden},
+		{"scope", token, "192.168.1.5", entity.APIScopeBatchAdd, false, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Authenticate(tc.token, tc.ip, tc.scope, tc.fromQuery); apiKeyStatus(err) != tc.status {
+			t.Errorf("%s: err = %v, want status %d", tc.name, err, tc.status)
+		}
+	}
+	if keys.rejected["2026-05-01"] != 2 {
+		t.Errorf("rejected = %d, want 2 (ip + scope)", keys.rejected["2026-05-01"])
+	}
+
+	*now = expires
+	if _, err := svc.Authenticate(token, "10.1.2.3", entity.APIScopeSearch, false); err == nil || !strings.Contains(err.Error(), "过期") {
+		t.Fatalf("expired key err = %v", err)
+	}
+}
+
+func TestAPIKeyLegacyToken(t *testing.T) {
+	svc, _, _ := newTestAPIKeyService(t)
+	if _, err := svc.Authenticate("legacy-token", "1.2.3.4", entity.APIScopeSearch, false); apiKeyStatus(err) != http.StatusUnauthorized || !strings.Contains(err.Error(), "停用") {
+		t.Fatalf("legacy disabled by default err = %v", err)
+	}
+
+	t.Setenv("API_LEGACY_TOKEN_ENABLED", "true")
+	svc, _, _ = newTestAPIKeyService(t)
+	key, err := svc.Authenticate("legacy-token", "1.2.3.4", entity.APIScopeBatchAdd, false)
+	if err != nil || key.ID != 0 || !key.HasScope(entity.APIScopeHotDramas) {
+		t.Fatalf("legacy = %+v, %v", key, err)
+	}
+	if _, err := svc.Authenticate("other", "1.2.3.4", entity.APIScopeSearch, false); apiKeyStatus(err) != http.StatusUnauthorized {
+		t.Fatalf("wrong legacy err = %v", err)
+	}
+}
+
+func TestAPIKeyLegacyTokenRejectedFromQuery(t *testing.T) {
+	t.Setenv("API_LEGACY_TOKEN_ENABLED", "true")
+	svc, _, _ := newTestAPIKeyService(t)
+	if _, err := svc.Authenticate("legacy-token", "1.2.3.4", entity.APIScopeSearch, true); apiKeyStatus(err) != http.StatusUnauthorized || !strings.Contains(err.Error(), "请求头") {
+		t.Fatalf("legacy token from query err = %v", err)
+	}
+}
+
+func TestAPIKeyRotateAndRevoke(t *testing.T) {
+	svc, _, _ := newTestAPIKeyService(t)
+	key, oldToken, _ := svc.Issue(dto.APIKeyRequest{Name: "bot", Scopes: []string{entity.APIScopeHotDramas}}, "admin")
+
+	_, newToken, err := svc.Rotate(key.ID)
+	if err != nil || newToken == oldToken {
+		t.Fatalf("Rotate = %q, %v", newToken, err)
+	}
+	if _, err := svc.Authenticate(oldToken, "1.2.3.4", entity.APIScopeHotDramas, false); err == nil {
+		t.Fatal("old token must stop working after rotation")
+	}
+	if _, err := svc.Authenticate(newToken, "1.2.3.4", entity.APIScopeHotDramas, false); err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+
+	if _, err := svc.Revoke(key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Authenticate(newToken, "1.2.3.4", entity.APIScopeHotDramas, false); err == nil || !strings.Contains(err.Error(), "吊销") {
+		t.Fatalf("revoked key err = %v", err)
+	}
+	if _, _, err := svc.Rotate(key.ID); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Fatalf("rotate revoked err = %v", err)
+	}
+	if _, err := svc.Revoke(99); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("revoke missing err = %v", err)
+	}
+}
+
+func TestAPIKeyRateLimitAndQuota(t *testing.T) {
+	svc, keys, now := newTestAPIKeyService(t)
+	_, token, _ := svc.Issue(dto.APIKeyRequest{Name: "limited", Scopes: []string{entity.APIScopeSearch}, RateLimit: 2, DailyQuota: 3}, "admin")
+
+	auth := func() error {
+		_, err := svc.Authenticate(token, "1.2.3.4", entity.APIScopeSearch, false)
+		return err
+	}
+	if auth() != nil || auth() != nil {
+		t.Fatal("first two requests should pass")
+	}
+	err := auth()
+	var apiErr *APIKeyError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests || apiErr.RetryAfter != 30 {
+		t.Fatalf("rate limited err = %+v", err)
+	}
+
+	// 30 秒补充一个令牌
+	*now = now.Add(30 * time.Second)
+	if err := auth(); err != nil {
+		t.Fatalf("after refill: %v", err)
+	}
+	*now = now.Add(time.Minute)
+	err = auth()
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests || !strings.Contains(apiErr.Message, "今日") {
+		t.Fatalf("quota err = %+v", err)
+	}
+	if keys.requests["2026-05-01"] != 3 || keys.rejected["2026-05-01"] != 2 {
+		t.Fatalf("usage = %v, rejected = %v", keys.requests, keys.rejected)
+	}
+
+	// 第二天配额重置
+	*now = time.Date(2026, 5, 2, 0, 0, 1, 0, time.Local)
+	if err := auth(); err != nil {
+		t.Fatalf("next day: %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	// 导出单次最多条数
+	auditExportLimit = 50000
+	// 保留天数上限
+	auditMaxRetentionDays = 3650
+	// 差异中单个字符串值的最大长度，避免批量操作写入过大的记录
+	auditMaxValueRunes = 500
+	// 脱敏后的占位值
+	auditMaskedValue = "******"
+)
+
+// sensitiveFieldPatterns 字段名（不区分大小写）包含这些片段时脱敏
+var sensitiveFieldPatterns = []string{"password", "secret", "token", "cookie", "api_key", "apikey", "master_key", "access_key", "private_key"}
+
+// sensitiveFieldNames 需要脱敏的完整字段名：网盘账号的 Cookie（ck）及保存令牌的 extra
+var sensitiveFieldNames = map[string]bool{"ck": true, "extra": true}
+
+// auditIgnoredFields 不参与差异比较的字段
+var auditIgnoredFields = map[string]bool{"updated_at": true}
+
+// AuditFieldChange 单个字段修改前后的值
+type AuditFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditLogService 后台操作审计：写入（计算差异并脱敏）、查询、CSV导出与过期清理
+type AuditLogService struct {
+	repo       repo.AuditLogRepository
+	configRepo repo.SystemConfigRepository
+	now        func() time.Time
+
+	mu          sync.Mutex
+	lastPruneAt time.Time
+}
+
+// NewAuditLogService 创建审计日志服务
+func NewAuditLogService(auditRepo repo.AuditLogRepository, configRepo repo.SystemConfigRepository) *AuditLogService {
+	return &AuditLogService{
+		repo:       auditRepo,
+		configRepo: configRepo,
+		now:        utils.GetCurrentTime,
+	}
+}
+
+// RecordAudit 写入一条审计日志，before/after 为修改前后的数据（结构体或 map），
+// 只保存有变化的字段，敏感字段的值替换为 ******
+func (s *AuditLogService) RecordAudit(log *entity.AuditLog, before, after interface{}) error {
+	if before != nil || after != nil {
+		changes, err := AuditDiff(before, after)
+		if err != nil {
+			utils.Warn("AuditLog - 计算修改差异失败 - 操作: %s, Error: %v", log.Action, err)
+		} else if len(changes) > 0 {
+			data, err := json.Marshal(changes)
+			if err != nil {
+				return err
+			}
+			log.Changes = string(data)
+		}
+	}
+
+	log.Username = truncateRunes(log.Username, 50)
+	log.Action = truncateRunes(log.Action, 100)
+	log.TargetID = truncateRunes(log.TargetID, 100)
+	log.Path = truncateRunes(log.Path, 255)
+	log.UserAgent = truncateRunes(log.UserAgent, 255)
+	log.Detail = truncateRunes(log.Detail, 2000)
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = s.now()
+	}
+	if err := s.repo.Create(log); err != nil {
+		return err
+	}
+	s.prune()
+	return nil
+}
+
+// List 分页查询审计日志
+func (s *AuditLogService) List(filter entity.AuditLogFilter, page, pageSize int) ([]entity.AuditLog, int64, error) {
+	return s.repo.Search(filter, page, pageSize)
+}
+
+// ExportCSV 按条件导出审计日志为 CSV（带 UTF-8 BOM 以便 Excel 正确识别中文），返回导出条数
+func (s *AuditLogService) ExportCSV(w io.Writer, filter entity.AuditLogFilter) (int, error) {
+	logs, err := s.repo.FindForExport(filter, auditExportLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return 0, err
+	}
+	writer := csv.NewWriter(w)
+	header := []string{"时间", "操作人ID", "操作人", "角色", "操作", "对象类型", "对象ID", "方法", "路径", "状态码", "结果", "IP", "修改内容", "说明"}
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+	for _, log := range logs {
+		result := "成功"
+		if !log.Success {
+			result = "失败"
+		}
+		record := []string{
+			log.CreatedAt.Format(utils.TimeFormatDateTime),
+			strconv.FormatUint(uint64(log.UserID), 10),
+			log.Username,
+			log.Role,
+			log.Action,
+			log.TargetType,
+			log.TargetID,
+			log.Method,
+			log.Path,
+			strconv.Itoa(log.StatusCode),
+			result,
+			log.IP,
+			log.Changes,
+			log.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	writer.Flush()
+	return len(logs), writer.Error()
+}
+
+// RetentionDays 审计日志保留天数，0 表示永久保留
+func (s *AuditLogService) RetentionDays() int {
+	if s.configRepo != nil {
+		if v, err := s.configRepo.GetConfigInt(entity.AuditLogConfigKeyRetentionDays); err == nil && v >= 0 {
+			return v
+		}
+	}
+	return entity.AuditLogConfigDefaultRetentionDays
+}
+
+// SaveRetentionDays 保存审计日志保留天数
+func (s *AuditLogService) SaveRetentionDays(days int) error {
+	if days < 0 || days > auditMaxRetentionDays {
+		return fmt.Errorf("保留天数必须在0-%d之间", auditMaxRetentionDays)
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.AuditLogConfigKeyRetentionDays, Value: strconv.Itoa(days), Type: entity.ConfigTypeInt},
+	})
+}
+
+// prune 每天最多一次清理超过保留期的审计日志
+func (s *AuditLogService) prune() {
+	now := s.now()
+	s.mu.Lock()
+	due := now.Sub(s.lastPruneAt) >= 24*time.Hour
+	if due {
+		s.lastPruneAt = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	days := s.RetentionDays()
+	if days == 0 {
+		return
+	}
+	go func() {
+		deleted, err := s.repo.DeleteBefore(now.AddDate(0, 0, -days))
+		if err != nil {
+			utils.Error("AuditLog - 清理过期审计日志失败: %v", err)
+		} else if deleted > 0 {
+			utils.Info("AuditLog - 已清理 %d 条超过 %d 天的审计日志", deleted, days)
+		}
+	}()
+}
+
+// AuditDiff 比较修改前后的数据（按 JSON 字段名），返回有变化的字段；
+// before 为 nil 表示新建，after 为 nil 表示删除。非对象的值以 value 为字段名比较
+func AuditDiff(before, after interface{}) (map[string]AuditFieldChange, error) {
+	oldMap, err := toAuditMap(before)
+	if err != nil {
+		return nil, err
+	}
+	newMap, err := toAuditMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(oldMap)+len(newMap))
+	for k := range oldMap {
+		keys[k] = true
+	}
+	for k := range newMap {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := make(map[string]AuditFieldChange)
+	for _, k := range sorted {
+		if auditIgnoredFields[k] {
+			continue
+		}
+		oldValue, newValue := oldMap[k], newMap[k]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if isSensitiveField(k) {
+			changes[k] = AuditFieldChange{Old: maskValue(oldValue), New: maskValue(newValue)}
+			continue
+		}
+		changes[k] = AuditFieldChange{Old: sanitizeAuditValue(oldValue), New: sanitizeAuditValue(newValue)}
+	}
+	return changes, nil
+}
+
+func toAuditMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	if decoded == nil {
+		return nil, nil
+	}
+	if m, ok := decoded.(map[string]interface{}); ok {
+		return m, nil
+	}
+	return map[string]interface{}{"value": decoded}, nil
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	if sensitiveFieldNames[name] {
+		return true
+	}
+	for _, pattern := range sensitiveFieldPatterns {
+		if strings.Contains(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue 非空值替换为占位值，仍可看出是否修改过
+func maskValue(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return auditMaskedValue
+}
+
+// sanitizeAuditValue 递归脱敏嵌套对象中的敏感字段并截断过长的字符串
+func sanitizeAuditValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if isSensitiveField(k) {
+				result[k] = maskValue(item)
+			} else {
+				result[k] = sanitizeAuditValue(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = sanitizeAuditValue(item)
+		}
+		return result
+	case string:
+		return truncateRunes(value, auditMaxValueRunes)
+	default:
+		return v
+	}
+}
+
+// 默认实例：在 main.go 中通过 SetDefaultAuditLogService 注入
+var defaultAuditLogService *AuditLogService
+
+// SetDefaultAuditLogService 设置默认审计日志服务实例
+func SetDefaultAuditLogService(s *AuditLogService) {
+	defaultAuditLogService = s
+}
+
+// GetDefaultAuditLogService 获取默认审计日志服务实例，未初始化时为 nil
+func GetDefaultAuditLogService() *AuditLogService {
+	return defaultAuditLogService
+}
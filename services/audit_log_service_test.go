@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+)
+
+type fakeAuditLogRepo struct {
+	repo.AuditLogRepository
+	logs []entity.AuditLog
+}
+
+func (f *fakeAuditLogRepo) Create(log *entity.AuditLog) error {
+	log.ID = uint(len(f.logs) + 1)
+	f.logs = append(f.logs, *log)
+	return nil
+}
+
+func (f *fakeAuditLogRepo) FindForExport(filter entity.AuditLogFilter, limit int) ([]entity.AuditLog, error) {
+	var result []entity.AuditLog
+	for _, log := range f.logs {
+		if filter.Username == "" || log.Username == filter.Username {
+			result = append(result, log)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAuditLogRepo) DeleteBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestAuditLogService() (*AuditLogService, *fakeAuditLogRepo) {
+	auditRepo := &fakeAuditLogRepo{}
+	svc := NewAuditLogService(auditRepo, nil)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	svc.lastPruneAt = now
+	return svc, auditRepo
+}
+
+func TestAuditDiffMasksSensitiveFields(t *testing.T) {
+	before := entity.Cks{ID: 3, Ck: "old-cookie", Remark: "主账号", Extra: `{"token":"abc"}`, UpdatedAt: time.Unix(1, 0)}
+	after := before
+	after.Ck = "new-cookie"
+	after.Remark = "备用账号"
+	after.UpdatedAt = time.Unix(2, 0)
+
+	changes, err := AuditDiff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected ck and remark changes, got %+v", changes)
+	}
+	if changes["ck"].Old != auditMaskedValue || changes["ck"].New != auditMaskedValue {
+		t.Fatalf("ck must be masked: %+v", changes["ck"])
+	}
+	if changes["remark"].Old != "主账号" || changes["remark"].New != "备用账号" {
+		t.Fatalf("unexpected remark change: %+v", changes["remark"])
+	}
+
+	// 配置键值表：按键名脱敏，嵌套对象中的敏感字段同样脱敏
+	changes, _ = AuditDiff(
+		map[string]interface{}{"site_title": "A", "meilisearch_master_key": "k1", "plugin": map[string]interface{}{"api_token": "t1", "limit": 1}},
+		map[string]interface{}{"site_title": "B", "meilisearch_master_key": "k2", "plugin": map[string]interface{}{"api_token": "t2", "limit": 2}},
+	)
+	if changes["meilisearch_master_key"].New != auditMaskedValue {
+		t.Fatalf("master key must be masked: %+v", changes)
+	}
+	nested := changes["plugin"].New.(map[string]interface{})
+	if nested["api_token"] != auditMaskedValue || nested["limit"] != float64(2) {
+		t.Fatalf("unexpected nested change: %+v", nested)
+	}
+
+	// 删除：after 为 nil 时记录全部旧值
+	changes, _ = AuditDiff(map[string]string{"title": "资源"}, nil)
+	if changes["title"].Old != "资源" || changes["title"].New != nil {
+		t.Fatalf("unexpected delete diff: %+v", changes)
+	}
+}
+
+func TestRecordAuditStoresDiffAndExportsCSV(t *testing.T) {
+	svc, auditRepo := newTestAuditLogService()
+
+	log := &entity.AuditLog{UserID: 1, Username: "admin", Action: "system_config.update", TargetType: "system_config", StatusCode: 200, Success: true, IP: "1.2.3.4"}
+	before := map[string]string{"site_title": "旧标题", "api_token": "secret-1"}
+	after := map[string]string{"site_title": "新标题", "api_token": "secret-2"}
+	if err := svc.RecordAudit(log, before, after); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RecordAudit(&entity.AuditLog{Username: "editor", Action: "resources.batch.delete", StatusCode: 500}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := auditRepo.logs[0]
+	if strings.Contains(stored.Changes, "secret-") {
+		t.Fatalf("secret leaked into changes: %s", stored.Changes)
+	}
+	var changes map[string]AuditFieldChange
+	if err := json.Unmarshal([]byte(stored.Changes), &changes); err != nil {
+		t.Fatal(err)
+	}
+	if changes["site_title"].New != "新标题" || changes["api_token"].New != auditMaskedValue {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	if stored.CreatedAt.IsZero() || auditRepo.logs[1].Changes != "" {
+		t.Fatalf("unexpected stored logs: %+v", auditRepo.logs)
+	}
+
+	var buf bytes.Buffer
+	count, err := svc.ExportCSV(&buf, entity.AuditLogFilter{Username: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if count != 1 || len(lines) != 2 {
+		t.Fatalf("count=%d lines=%d: %s", count, len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "\xef\xbb\xbf时间,") || !strings.Contains(lines[1], "system_config.update") || !strings.Contains(lines[1], "成功") {
+		t.Fatalf("unexpected csv: %s", buf.String())
+	}
+}
+
+func TestAuditRetentionDaysValidation(t *testing.T) {
+	svc, _ := newTestAuditLogService()
+	if got := svc.RetentionDays(); got != entity.AuditLogConfigDefaultRetentionDays {
+		t.Fatalf("RetentionDays = %d", got)
+	}
+	if err := svc.SaveRetentionDays(-1); err == nil {
+		t.Fatal("negative retention must be rejected")
+	}
+	if err := svc.SaveRetentionDays(auditMaxRetentionDays + 1); err == nil {
+		t.Fatal("retention above limit must be rejected")
+	}
+}
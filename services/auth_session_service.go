@@ -0,0 +1,351 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// 刷新令牌默认有效期，每次刷新顺延，可通过 JWT_REFRESH_TTL 调整
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	// 会话校验结果缓存时间，多副本部署时其他副本上的吊销最迟在该时间后生效
+	sessionCacheTTL = 30 * time.Second
+	// 会话校验结果缓存的最大条目数，超出时先清理过期项，仍然超出则丢弃任意项
+	sessionCacheMaxEntries = 10000
+	// 刷新令牌轮换后的宽限期，期间使用上一个令牌（多标签页并发刷新）只拒绝不吊销
+	refreshReuseGrace = 30 * time.Second
+	// 过期或吊销的会话保留时间
+	sessionRetention = 7 * 24 * time.Hour
+)
+
+var (
+	ErrSessionNotFound     = errors.New("会话不存在")
+	ErrSessionInvalid      = errors.New("会话已失效")
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用，会话已吊销")
+	ErrUserDisabled        = errors.New("账户已被禁用")
+)
+
+// AuthSessionService 登录会话服务：签发与轮换刷新令牌、吊销会话、校验访问令牌对应的会话
+type AuthSessionService struct {
+	userRepo    repo.UserRepository
+	sessionRepo repo.UserSessionRepository
+	refreshTTL  time.Duration
+	now         func() time.Time
+
+	mu          sync.Mutex
+	cache       map[string]sessionCacheEntry
+	cacheSwept  time.Time
+	lastPruneAt time.Time
+}
+
+type sessionCacheEntry struct {
+	userID    uint
+	err       error
+	checkedAt time.Time
+}
+
+// NewAuthSessionService 创建登录会话服务
+func NewAuthSessionService(userRepo repo.UserRepository, sessionRepo repo.UserSessionRepository) *AuthSessionService {
+	return &AuthSessionService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		refreshTTL:  defaultRefreshTokenTTL,
+		now:         utils.GetCurrentTime,
+		cache:       make(map[string]sessionCacheEntry),
+	}
+}
+
+// SetRefreshTokenTTL 设置刷新令牌有效期
+func (s *AuthSessionService) SetRefreshTokenTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.refreshTTL = ttl
+	}
+}
+
+// 默认实例：在 main.go 中通过 SetDefaultAuthSessionService 注入
+var defaultAuthSessionService *AuthSessionService
+
+// SetDefaultAuthSessionService 设置默认登录会话服务实例
+func SetDefaultAuthSessionService(s *AuthSessionService) {
+	defaultAuthSessionService = s
+}
+
+// GetDefaultAuthSessionService 获取默认登录会话服务实例，未初始化时为 nil
+func GetDefaultAuthSessionService() *AuthSessionService {
+	return defaultAuthSessionService
+}
+
+// CreateSession 登录成功后创建会话，返回会话与刷新令牌明文
+func (s *AuthSessionService) CreateSession(user *entity.User, ip, userAgent string) (*entity.UserSession, string, error) {
+	sessionID, err := randomSessionID()
+	if err != nil {
+		return nil, "", err
+	}
+	refreshToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	session := &entity.UserSession{
+		SessionID:        sessionID,
+		UserID:           user.ID,
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		IP:               ip,
+		UserAgent:        truncateRunes(userAgent, 255),
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, "", err
+	}
+
+	s.pruneExpired(now)
+	return session, refreshToken, nil
+}
+
+// Refresh 使用刷新令牌换取新令牌：刷新令牌一次性使用并轮换，重复使用旧令牌视为泄露并吊销整个会话
+func (s *AuthSessionService) Refresh(refreshToken, ip string) (*entity.User, *entity.UserSession, string, error) {
+	sessionID, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" {
+		return nil, nil, "", ErrRefreshTokenInvalid
+	}
+	session, err := s.sessionRepo.FindBySessionID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, "", ErrRefreshTokenInvalid
+		}
+		return nil, nil, "", err
+	}
+
+	now := s.now()
+	if !session.IsActive(now) {
+		return nil, nil, "", ErrRefreshTokenInvalid
+	}
+
+	hash := hashRefreshToken(refreshToken)
+	if session.PreviousTokenHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(session.PreviousTokenHash)) == 1 {
+		if session.RotatedAt != nil && now.Sub(*session.RotatedAt) < refreshReuseGrace {
+			return nil, nil, "", ErrRefreshTokenInvalid
+		}
+		utils.Warn("AuthSession - 检测到刷新令牌重放，吊销会话 - 用户ID: %d, 会话: %s, IP: %s", session.UserID, session.SessionID, ip)
+		if err := s.revoke(session.SessionID, entity.SessionRevokeRefreshReuse); err != nil {
+			return nil, nil, "", err
+		}
+		return nil, nil, "", ErrRefreshTokenReused
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(session.RefreshTokenHash)) != 1 {
+		return nil, nil, "", ErrRefreshTokenInvalid
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil || !user.IsActive {
+		if err := s.revoke(session.SessionID, entity.SessionRevokeUserDisabled); err != nil {
+			return nil, nil, "", err
+		}
+		return nil, nil, "", ErrUserDisabled
+	}
+
+	newToken, err := newRefreshToken(session.SessionID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	newHash := hashRefreshToken(newToken)
+	expiresAt := now.Add(s.refreshTTL)
+	rotated, err := s.sessionRepo.RotateRefreshToken(session.SessionID, session.RefreshTokenHash, newHash, ip, now, expiresAt)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !rotated {
+		// 并发刷新中落后的一方
+		return nil, nil, "", ErrRefreshTokenInvalid
+	}
+
+	session.PreviousTokenHash = session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.RotatedAt = &now
+	session.LastUsedAt = now
+	session.ExpiresAt = expiresAt
+	session.IP = ip
+	return user, session, newToken, nil
+}
+
+// ValidateSession 校验访问令牌关联的会话未吊销、未过期且用户仍处于启用状态，结果短暂缓存
+func (s *AuthSessionService) ValidateSession(userID uint, sessionID string) error {
+	now := s.now()
+	s.mu.Lock()
+	entry, ok := s.cache[sessionID]
+	s.mu.Unlock()
+	if ok && now.Sub(entry.checkedAt) < sessionCacheTTL {
+		if entry.err == nil && entry.userID != userID {
+			return ErrSessionInvalid
+		}
+		return entry.err
+	}
+
+	err := s.checkSession(userID, sessionID, now)
+	if err != nil && !errors.Is(err, ErrSessionInvalid) && !errors.Is(err, ErrUserDisabled) {
+		// 数据库错误不缓存
+		return err
+	}
+	s.mu.Lock()
+	s.storeLocked(sessionID, sessionCacheEntry{userID: userID, err: err, checkedAt: now})
+	s.mu.Unlock()
+	return err
+}
+
+// storeLocked 写入缓存项。每个缓存周期最多扫描一次过期项，条目数达到上限时再强制扫描并淘汰，
+// 保证缓存大小不随访问过的会话数无限增长。调用方需持有 s.mu
+func (s *AuthSessionService) storeLocked(sessionID string, entry sessionCacheEntry) {
+	if _, exists := s.cache[sessionID]; !exists {
+		if len(s.cache) >= sessionCacheMaxEntries || entry.checkedAt.Sub(s.cacheSwept) >= sessionCacheTTL {
+			s.sweepLocked(entry.checkedAt)
+		}
+		for id := range s.cache {
+			if len(s.cache) < sessionCacheMaxEntries {
+				break
+			}
+			delete(s.cache, id)
+		}
+	}
+	s.cache[sessionID] = entry
+}
+
+// sweepLocked 清理过期缓存项，调用方需持有 s.mu
+func (s *AuthSessionService) sweepLocked(now time.Time) {
+	s.cacheSwept = now
+	for id, entry := range s.cache {
+		if now.Sub(entry.checkedAt) >= sessionCacheTTL {
+			delete(s.cache, id)
+		}
+	}
+}
+
+func (s *AuthSessionService) checkSession(userID uint, sessionID string, now time.Time) error {
+	session, err := s.sessionRepo.FindBySessionID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionInvalid
+		}
+		return err
+	}
+	if session.UserID != userID || !session.IsActive(now) {
+		return ErrSessionInvalid
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionInvalid
+		}
+		return err
+	}
+	if !user.IsActive {
+		return ErrUserDisabled
+	}
+	return nil
+}
+
+// Logout 登出当前会话
+func (s *AuthSessionService) Logout(sessionID string) error {
+	return s.revoke(sessionID, entity.SessionRevokeLogout)
+}
+
+// ListSessions 获取用户的有效会话
+func (s *AuthSessionService) ListSessions(userID uint) ([]entity.UserSession, error) {
+	return s.sessionRepo.FindActiveByUser(userID, s.now())
+}
+
+// RevokeSession 吊销用户的指定会话
+func (s *AuthSessionService) RevokeSession(userID uint, sessionID string) error {
+	session, err := s.sessionRepo.FindBySessionID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID || !session.IsActive(s.now()) {
+		return ErrSessionNotFound
+	}
+	return s.revoke(sessionID, entity.SessionRevokeManual)
+}
+
+// RevokeUserSessions 吊销用户的全部会话（exceptSessionID 除外），返回吊销数量
+func (s *AuthSessionService) RevokeUserSessions(userID uint, exceptSessionID, reason string) (int, error) {
+	sessionIDs, err := s.sessionRepo.RevokeByUser(userID, exceptSessionID, reason, s.now())
+	s.invalidate(sessionIDs...)
+	return len(sessionIDs), err
+}
+
+func (s *AuthSessionService) revoke(sessionID, reason string) error {
+	err := s.sessionRepo.Revoke(sessionID, reason, s.now())
+	s.invalidate(sessionID)
+	return err
+}
+
+func (s *AuthSessionService) invalidate(sessionIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range sessionIDs {
+		delete(s.cache, id)
+	}
+	s.sweepLocked(s.now())
+}
+
+// pruneExpired 每天最多一次清理过期或吊销超过保留期的会话
+func (s *AuthSessionService) pruneExpired(now time.Time) {
+	s.mu.Lock()
+	due := now.Sub(s.lastPruneAt) >= 24*time.Hour
+	if due {
+		s.lastPruneAt = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		deleted, err := s.sessionRepo.DeleteExpiredBefore(now.Add(-sessionRetention))
+		if err != nil {
+			utils.Error("AuthSession - 清理过期会话失败: %v", err)
+		} else if deleted > 0 {
+			utils.Info("AuthSession - 已清理 %d 个过期会话", deleted)
+		}
+	}()
+}
+
+// newRefreshToken 刷新令牌格式为 <会话标识>.<随机串>
+func newRefreshToken(sessionID string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return sessionID + "." + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func randomSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
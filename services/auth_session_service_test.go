@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+
+	"gorm.io/gorm"
+)
+
+type fakeSessionRepo struct {
+	repo.UserSessionRepository
+	mu       sync.Mutex
+	sessions map[string]*entity.UserSession
+	lookups  int
+}
+
+func (f *fakeSessionRepo) Create(session *entity.UserSession) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *session
+	f.sessions[session.SessionID] = &copied
+	return nil
+}
+
+func (f *fakeSessionRepo) FindBySessionID(sessionID string) (*entity.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups++
+	session, ok := f.sessions[sessionID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (f *fakeSessionRepo) FindActiveByUser(userID uint, now time.Time) ([]entity.UserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []entity.UserSession
+	for _, s := range f.sessions {
+		if s.UserID == userID && s.IsActive(now) {
+			result = append(result, *s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSessionRepo) RotateRefreshToken(sessionID, oldHash, newHash, ip string, at, expiresAt time.Time) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.sessions[sessionID]
+	if !ok || s.RefreshTokenHash != oldHash || s.RevokedAt != nil {
+		return false, nil
+	}
+	s.PreviousTokenHash, s.RefreshTokenHash = oldHash, newHash
+	s.IP, s.RotatedAt, s.LastUsedAt, s.ExpiresAt = ip, &at, at, expiresAt
+	return true, nil
+}
+
+func (f *fakeSessionRepo) Revoke(sessionID, reason string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.sessions[sessionID]; ok && s.RevokedAt == nil {
+		s.RevokedAt, s.RevokeReason = &at, reason
+	}
+	return nil
+}
+
+func (f *fakeSessionRepo) RevokeByUser(userID uint, exceptSessionID, reason string, at time.Time) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for id, s := range f.sessions {
+		if s.UserID == userID && id != exceptSessionID && s.RevokedAt == nil {
+			s.RevokedAt, s.RevokeReason = &at, reason
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeSessionRepo) DeleteExpiredBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeSessionUserRepo struct {
+	repo.UserRepository
+	users map[uint]*entity.User
+}
+
+func (f *fakeSessionUserRepo) FindByID(id uint) (*entity.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func newTestAuthSessionService() (*AuthSessionService, *fakeSessionRepo, *entity.User, *time.Time) {
+	user := &entity.User{ID: 1, Username: "alice", Role: entity.RoleEditor, IsActive: true}
+	sessions := &fakeSessionRepo{sessions: map[string]*entity.UserSession{}}
+	svc := NewAuthSessionService(&fakeSessionUserRepo{users: map[uint]*entity.User{1: user}}, sessions)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.Local)
+	svc.now = func() time.Time { return now }
+	return svc, sessions, user, &now
+}
+
+func TestAuthSessionRefreshRotation(t *testing.T) {
+	svc, sessions, user, now := newTestAuthSessionService()
+	session, token, err := svc.CreateSession(user, "1.1.1.1", "test-agent")
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	if !strings.HasPrefix(token, session.SessionID+".") || sessions.sessions[session.SessionID].RefreshTokenHash == token {
+		t.Fatalf("token = %q, stored = %+v", token, sessions.sessions[session.SessionID])
+	}
+
+	*now = now.Add(time.Hour)
+	gotUser, _, rotated, err := svc.Refresh(token, "2.2.2.2")
+	if err != nil || gotUser.ID != user.ID || rotated == token {
+		t.Fatalf("Refresh = %v, %q, %v", gotUser, rotated, err)
+	}
+	if sessions.sessions[session.SessionID].IP != "2.2.2.2" {
+		t.Fatal("refresh should record the latest IP")
+	}
+
+	// 宽限期内重复使用旧令牌只拒绝，会话保持有效
+	if _, _, _, err := svc.Refresh(token, "2.2.2.2"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("reuse within grace err = %v", err)
+	}
+	if err := svc.ValidateSession(user.ID, session.SessionID); err != nil {
+		t.Fatalf("session should survive a concurrent refresh: %v", err)
+	}
+
+	// 超过宽限期后重放旧令牌视为泄露，吊销整个会话
+	*now = now.Add(time.Minute)
+	if _, _, _, err := svc.Refresh(token, "3.3.3.3"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replay err = %v", err)
+	}
+	if _, _, _, err := svc.Refresh(rotated, "2.2.2.2"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("current token after reuse revoke err = %v", err)
+	}
+	if err := svc.ValidateSession(user.ID, session.SessionID); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("validate revoked err = %v", err)
+	}
+	if reason := sessions.sessions[session.SessionID].RevokeReason; reason != entity.SessionRevokeRefreshReuse {
+		t.Fatalf("revoke reason = %q", reason)
+	}
+
+	if _, _, _, err := svc.Refresh("garbage", "1.1.1.1"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("garbage err = %v", err)
+	}
+}
+
+func TestAuthSessionExpiry(t *testing.T) {
+	svc, _, user, now := newTestAuthSessionService()
+	svc.SetRefreshTokenTTL(24 * time.Hour)
+	session, token, _ := svc.CreateSession(user, "1.1.1.1", "")
+
+	*now = now.Add(25 * time.Hour)
+	if _, _, _, err := svc.Refresh(token, "1.1.1.1"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Fatalf("expired refresh err = %v", err)
+	}
+	if err := svc.ValidateSession(user.ID, session.SessionID); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("expired session err = %v", err)
+	}
+}
+
+func TestAuthSessionValidateAndRevoke(t *testing.T) {
+	svc, sessions, user, now := newTestAuthSessionService()
+	first, _, _ := svc.CreateSession(user, "1.1.1.1", "a")
+	second, secondToken, _ := svc.CreateSession(user, "1.1.1.1", "b")
+
+	if err := svc.ValidateSession(user.ID, first.SessionID); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := svc.ValidateSession(2, first.SessionID); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("token of another user err = %v", err)
+	}
+	lookups := sessions.lookups
+	if err := svc.ValidateSession(user.ID, first.SessionID); err != nil || sessions.lookups != lookups {
+		t.Fatalf("cached validate err = %v, lookups %d -> %d", err, lookups, sessions.lookups)
+	}
+
+	// 吊销立即生效，不等待缓存过期
+	if err := svc.Logout(first.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ValidateSession(user.ID, first.SessionID); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("after logout err = %v", err)
+	}
+	if err := svc.RevokeSession(2, second.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("revoke other user's session err = %v", err)
+	}
+	if list, _ := svc.ListSessions(user.ID); len(list) != 1 || list[0].SessionID != second.SessionID {
+		t.Fatalf("ListSessions = %+v", list)
+	}
+
+	// 禁用用户：缓存过期后访问令牌失效，刷新令牌立即失效
+	if err := svc.ValidateSession(user.ID, second.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	user.IsActive = false
+	if _, _, _, err := svc.Refresh(secondToken, "1.1.1.1"); !errors.Is(err, ErrUserDisabled) {
+		t.Fatalf("refresh disabled user err = %v", err)
+	}
+	*now = now.Add(sessionCacheTTL)
+	if err := svc.ValidateSession(user.ID, second.SessionID); err == nil {
+		t.Fatal("disabled user's session must be rejected")
+	}
+}
+
+func TestAuthSessionCacheBounded(t *testing.T) {
+	svc, _, user, now := newTestAuthSessionService()
+	// 伪造的会话 ID 同样会缓存校验结果，过期项在后续写入时被清理
+	for i := 0; i < 100; i++ {
+		svc.ValidateSession(user.ID, "forged-"+strconv.Itoa(i))
+	}
+	*now = now.Add(sessionCacheTTL)
+	svc.ValidateSession(user.ID, "forged-next")
+	if n := len(svc.cache); n != 1 {
+		t.Fatalf("expired entries kept after insert: %d", n)
+	}
+
+	for i := 0; i < sessionCacheMaxEntries+100; i++ {
+		svc.ValidateSession(user.ID, "burst-"+strconv.Itoa(i))
+	}
+	if n := len(svc.cache); n > sessionCacheMaxEntries {
+		t.Fatalf("cache size %d exceeds %d", n, sessionCacheMaxEntries)
+	}
+}
+
+func TestAuthSessionRevokeUserSessions(t *testing.T) {
+	svc, _, user, _ := newTestAuthSessionService()
+	current, _, _ := svc.CreateSession(user, "1.1.1.1", "")
+	other, _, _ := svc.CreateSession(user, "1.1.1.1", "")
+	_ = svc.ValidateSession(user.ID, other.SessionID)
+
+	count, err := svc.RevokeUserSessions(user.ID, current.SessionID, entity.SessionRevokeManual)
+	if err != nil || count != 1 {
+		t.Fatalf("RevokeUserSessions = %d, %v", count, err)
+	}
+	if err := svc.ValidateSession(user.ID, other.SessionID); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("revoked session err = %v", err)
+	}
+	if err := svc.ValidateSession(user.ID, current.SessionID); err != nil {
+		t.Fatalf("current session err = %v", err)
+	}
+}
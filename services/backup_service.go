@@ -0,0 +1,678 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/backup"
+	"github.com/ctwj/urldb/utils"
+)
+
+// backupConfigEntry 系统配置在归档中的路径
+const backupConfigEntry = backup.PrefixConfig + "system_config.json"
+
+// BackupDatabase 数据库的导出与恢复，由 backup.Postgres 实现
+type BackupDatabase interface {
+	Dump(ctx context.Context, w *backup.Writer, mode string) error
+	Restore(ctx context.Context, r io.Reader, manifest *backup.Manifest) (*backup.RestoreStats, error)
+}
+
+// BackupSource 需要备份的目录，归档中位于 files/<Name>/ 下
+type BackupSource struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+	// Plugin 是否属于插件目录，受 include_plugins 控制；否则受 include_uploads 控制
+	Plugin bool `json:"plugin"`
+}
+
+// BackupConfig 备份配置
+type BackupConfig struct {
+	Enabled        bool   `json:"enabled"`
+	IntervalHours  int    `json:"interval_hours"`
+	KeepLast       int    `json:"keep_last"`
+	KeepDaily      int    `json:"keep_daily"`
+	KeepWeekly     int    `json:"keep_weekly"`
+	IncludeUploads bool   `json:"include_uploads"`
+	IncludePlugins bool   `json:"include_plugins"`
+	DBMode         string `json:"db_mode"`
+}
+
+// Validate 校验配置
+func (c BackupConfig) Validate() error {
+	if c.IntervalHours < 1 || c.IntervalHours > 24*30 {
+		return fmt.Errorf("备份间隔需在 1-720 小时之间")
+	}
+	for _, n := range []int{c.KeepLast, c.KeepDaily, c.KeepWeekly} {
+		if n < 0 || n > 1000 {
+			return fmt.Errorf("保留数量需在 0-1000 之间")
+		}
+	}
+	if c.KeepLast == 0 && c.KeepDaily == 0 && c.KeepWeekly == 0 {
+		return fmt.Errorf("至少需要保留一个备份")
+	}
+	if !backup.IsDBMode(c.DBMode) {
+		return fmt.Errorf("数据库导出方式只能是 auto、pg_dump 或 native")
+	}
+	return nil
+}
+
+// Retention 保留规则
+func (c BackupConfig) Retention() backup.RetentionPolicy {
+	return backup.RetentionPolicy{KeepLast: c.KeepLast, KeepDaily: c.KeepDaily, KeepWeekly: c.KeepWeekly}
+}
+
+// BackupRunResult 一次备份的结果
+type BackupRunResult struct {
+	Name     string   `json:"name"`
+	Size     int64    `json:"size"`
+	DBMode   string   `json:"db_mode"`
+	Files    int      `json:"files"`
+	Sources  []string `json:"sources"`
+	Pruned   []string `json:"pruned,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+// BackupRestoreOptions 恢复哪些部分
+type BackupRestoreOptions struct {
+	Database bool `json:"database"`
+	Files    bool `json:"files"`
+	Config   bool `json:"config"`
+}
+
+// BackupRestoreResult 恢复结果
+type BackupRestoreResult struct {
+	Name     string               `json:"name"`
+	Manifest *backup.Manifest     `json:"manifest"`
+	Database *backup.RestoreStats `json:"database,omitempty"`
+	Files    int                  `json:"files"`
+	Configs  int                  `json:"configs"`
+}
+
+// BackupStatus 备份状态，用于系统信息与管理接口
+type BackupStatus struct {
+	Enabled     bool           `json:"enabled"`
+	Storage     string         `json:"storage"`
+	Running     bool           `json:"running"`
+	Count       int            `json:"count"`
+	TotalSize   int64          `json:"total_size"`
+	Last        *backup.Object `json:"last,omitempty"`
+	LastError   string         `json:"last_error,omitempty"`
+	LastErrorAt *time.Time     `json:"last_error_at,omitempty"`
+	NextRunAt   *time.Time     `json:"next_run_at,omitempty"`
+}
+
+// BackupService 实例备份：数据库、系统配置、上传目录与插件目录打包为带清单校验的归档，
+// 存放到本地目录或 S3 兼容存储，按保留规则清理旧备份，恢复前完整校验归档。
+type BackupService struct {
+	storage    backup.Storage
+	database   BackupDatabase
+	configRepo repo.SystemConfigRepository
+	sources    []BackupSource
+
+	// 便于测试替换
+	now func() time.Time
+
+	runMutex sync.Mutex
+
+	statusMutex sync.RWMutex
+	status      BackupStatus
+}
+
+// NewBackupService 创建备份服务
+func NewBackupService(storage backup.Storage, database BackupDatabase, configRepo repo.SystemConfigRepository, sources []BackupSource) *BackupService {
+	return &BackupService{
+		storage:    storage,
+		database:   database,
+		configRepo: configRepo,
+		sources:    sources,
+		now:        utils.GetCurrentTime,
+		status:     BackupStatus{Storage: storage.Kind()},
+	}
+}
+
+// 默认实例：在 main.go 中通过 SetDefaultBackupService 注入，供系统信息接口读取备份状态
+var defaultBackupService *BackupService
+
+// SetDefaultBackupService 设置默认备份服务实例（由 main.go 在初始化阶段调用）
+func SetDefaultBackupService(s *BackupService) {
+	defaultBackupService = s
+}
+
+// GetDefaultBackupService 获取默认备份服务实例，未初始化时为 nil
+func GetDefaultBackupService() *BackupService {
+	return defaultBackupService
+}
+
+// DefaultBackupDir 本地备份的默认目录，不能位于对外提供的静态目录中
+const DefaultBackupDir = "./backups"
+
+// PublicStaticDirs main.go 中无需登录即可访问的静态目录，备份、导出等含敏感数据的文件不能写在其中
+var PublicStaticDirs = []string{"./data", "./uploads"}
+
+// InPublicStaticDir path 是否位于（或就是）某个对外提供的静态目录
+func InPublicStaticDir(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range PublicStaticDirs {
+		root, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBackupStorageFromEnv 按环境变量创建存储：BACKUP_STORAGE=local（默认，目录 BACKUP_DIR）或 s3。
+// 归档包含数据库与系统配置（含 jwt_secret），BACKUP_DIR 位于对外静态目录时拒绝创建
+func NewBackupStorageFromEnv() (backup.Storage, error) {
+	switch kind := strings.ToLower(os.Getenv("BACKUP_STORAGE")); kind {
+	case "", "local":
+		dir := os.Getenv("BACKUP_DIR")
+		if dir == "" {
+			dir = DefaultBackupDir
+		}
+		if InPublicStaticDir(dir) {
+			return nil, fmt.Errorf("备份目录 %s 位于无需登录即可访问的静态目录中，请将 BACKUP_DIR 改为其他目录（默认 %s）并移走已有备份", dir, DefaultBackupDir)
+		}
+		return backup.NewLocalStorage(dir), nil
+	case "s3":
+		endpoint := os.Getenv("BACKUP_S3_ENDPOINT")
+		if endpoint != "" && !strings.Contains(endpoint, "://") {
+			scheme := "https://"
+			if os.Getenv("BACKUP_S3_USE_SSL") == "false" {
+				scheme = "http://"
+			}
+			endpoint = scheme + endpoint
+		}
+		return backup.NewS3Storage(backup.S3Config{
+			Endpoint:  endpoint,
+			Region:    os.Getenv("BACKUP_S3_REGION"),
+			Bucket:    os.Getenv("BACKUP_S3_BUCKET"),
+			Prefix:    os.Getenv("BACKUP_S3_PREFIX"),
+			AccessKey: os.Getenv("BACKUP_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("BACKUP_S3_SECRET_KEY"),
+			PathStyle: os.Getenv("BACKUP_S3_PATH_STYLE") != "false",
+		})
+	default:
+		return nil, fmt.Errorf("不支持的备份存储类型: %s", kind)
+	}
+}
+
+// DefaultBackupSources 上传目录（UPLOAD_DIR）、插件目录与插件钩子目录（PLUGIN_HOOKS_DIR）
+func DefaultBackupSources() []BackupSource {
+	uploadDir := os.Getenv("UPLOAD_DIR")
+	if uploadDir == "" {
+		uploadDir = "./uploads"
+	}
+	hooksDir := os.Getenv("PLUGIN_HOOKS_DIR")
+	if hooksDir == "" {
+		hooksDir = "./plugin-system/hooks"
+	}
+	return []BackupSource{
+		{Name: "uploads", Dir: uploadDir},
+		{Name: "plugins", Dir: "./plugins", Plugin: true},
+		{Name: "plugin-hooks", Dir: hooksDir, Plugin: true},
+	}
+}
+
+// Config 读取配置，未设置时使用默认值
+func (s *BackupService) Config() BackupConfig {
+	cfg := BackupConfig{
+		Enabled:        entity.BackupConfigDefaultEnabled,
+		IntervalHours:  entity.BackupConfigDefaultIntervalHours,
+		KeepLast:       entity.BackupConfigDefaultKeepLast,
+		KeepDaily:      entity.BackupConfigDefaultKeepDaily,
+		KeepWeekly:     entity.BackupConfigDefaultKeepWeekly,
+		IncludeUploads: entity.BackupConfigDefaultIncludeUploads,
+		IncludePlugins: entity.BackupConfigDefaultIncludePlugins,
+		DBMode:         entity.BackupConfigDefaultDBMode,
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.BackupConfigKeyEnabled); err == nil && v != "" {
+		cfg.Enabled = v == "true"
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.BackupConfigKeyIntervalHours); err == nil && v > 0 {
+		cfg.IntervalHours = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.BackupConfigKeyKeepLast); err == nil && v >= 0 {
+		cfg.KeepLast = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.BackupConfigKeyKeepDaily); err == nil && v >= 0 {
+		cfg.KeepDaily = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.BackupConfigKeyKeepWeekly); err == nil && v >= 0 {
+		cfg.KeepWeekly = v
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.BackupConfigKeyIncludeUploads); err == nil && v != "" {
+		cfg.IncludeUploads = v == "true"
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.BackupConfigKeyIncludePlugins); err == nil && v != "" {
+		cfg.IncludePlugins = v == "true"
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.BackupConfigKeyDBMode); err == nil && backup.IsDBMode(v) {
+		cfg.DBMode = v
+	}
+	return cfg
+}
+
+// SaveConfig 校验并保存配置
+func (s *BackupService) SaveConfig(cfg BackupConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.BackupConfigKeyEnabled, Value: strconv.FormatBool(cfg.Enabled), Type: entity.ConfigTypeBool},
+		{Key: entity.BackupConfigKeyIntervalHours, Value: strconv.Itoa(cfg.IntervalHours), Type: entity.ConfigTypeInt},
+		{Key: entity.BackupConfigKeyKeepLast, Value: strconv.Itoa(cfg.KeepLast), Type: entity.ConfigTypeInt},
+		{Key: entity.BackupConfigKeyKeepDaily, Value: strconv.Itoa(cfg.KeepDaily), Type: entity.ConfigTypeInt},
+		{Key: entity.BackupConfigKeyKeepWeekly, Value: strconv.Itoa(cfg.KeepWeekly), Type: entity.ConfigTypeInt},
+		{Key: entity.BackupConfigKeyIncludeUploads, Value: strconv.FormatBool(cfg.IncludeUploads), Type: entity.ConfigTypeBool},
+		{Key: entity.BackupConfigKeyIncludePlugins, Value: strconv.FormatBool(cfg.IncludePlugins), Type: entity.ConfigTypeBool},
+		{Key: entity.BackupConfigKeyDBMode, Value: cfg.DBMode, Type: entity.ConfigTypeString},
+	})
+}
+
+// Due 定时备份是否到期：已启用且距上次成功备份超过间隔
+func (s *BackupService) Due() bool {
+	cfg := s.Config()
+	if !cfg.Enabled {
+		return false
+	}
+	s.statusMutex.RLock()
+	last := s.status.Last
+	s.statusMutex.RUnlock()
+	if last == nil {
+		return true
+	}
+	lastAt, ok := backup.ArchiveTime(last.Name)
+	return !ok || !s.now().Before(lastAt.Add(time.Duration(cfg.IntervalHours)*time.Hour))
+}
+
+// Run 立即备份并按保留规则清理旧备份
+func (s *BackupService) Run(ctx context.Context) (*BackupRunResult, error) {
+	if !s.runMutex.TryLock() {
+		return nil, fmt.Errorf("备份正在进行中")
+	}
+	defer s.runMutex.Unlock()
+
+	s.setRunning(true)
+	result, err := s.run(ctx)
+	s.setRunning(false)
+	if err != nil {
+		s.recordError(err)
+		return nil, err
+	}
+	if err := s.RefreshStatus(ctx); err != nil {
+		utils.Warn("刷新备份状态失败: %v", err)
+	}
+	return result, nil
+}
+
+func (s *BackupService) run(ctx context.Context) (*BackupRunResult, error) {
+	start := time.Now()
+	cfg := s.Config()
+	createdAt := s.now()
+	name := backup.ArchiveName(createdAt)
+
+	tmp, err := os.CreateTemp("", "urldb-backup-*"+backup.ArchiveExt)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	w := backup.NewWriter(tmp, &backup.Manifest{CreatedAt: createdAt, AppVersion: utils.Version})
+	if err := s.database.Dump(ctx, w, cfg.DBMode); err != nil {
+		return nil, fmt.Errorf("导出数据库失败: %v", err)
+	}
+	if err := s.writeConfig(w); err != nil {
+		return nil, fmt.Errorf("导出系统配置失败: %v", err)
+	}
+	files := 0
+	for _, src := range s.sources {
+		if (src.Plugin && !cfg.IncludePlugins) || (!src.Plugin && !cfg.IncludeUploads) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n, err := w.AddDir(backup.PrefixFiles+src.Name+"/", src.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("备份目录 %s 失败: %v", src.Dir, err)
+		}
+		files += n
+		w.Manifest().Sources = append(w.Manifest().Sources, src.Name)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, name, tmp, info.Size()); err != nil {
+		return nil, fmt.Errorf("上传备份失败: %v", err)
+	}
+
+	result := &BackupRunResult{
+		Name:    name,
+		Size:    info.Size(),
+		DBMode:  w.Manifest().DBMode,
+		Files:   files,
+		Sources: w.Manifest().Sources,
+	}
+	if result.Pruned, err = s.ApplyRetention(ctx); err != nil {
+		utils.Warn("清理旧备份失败: %v", err)
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	utils.Info("备份完成: %s, 大小 %d 字节, 文件 %d 个, 清理 %d 个旧备份", name, result.Size, files, len(result.Pruned))
+	return result, nil
+}
+
+// writeConfig 导出系统配置
+func (s *BackupService) writeConfig(w *backup.Writer) error {
+	configs, err := s.configRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	type configItem struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		Type  string `json:"type"`
+	}
+	items := make([]configItem, 0, len(configs))
+	for _, c := range configs {
+		items = append(items, configItem{Key: c.Key, Value: c.Value, Type: c.Type})
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return w.AddBytes(backupConfigEntry, data)
+}
+
+// ApplyRetention 按保留规则删除旧备份，返回删除的备份名
+func (s *BackupService) ApplyRetention(ctx context.Context) ([]string, error) {
+	objects, err := s.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, remove := backup.Prune(objects, s.Config().Retention())
+	var pruned []string
+	for _, o := range remove {
+		if err := s.storage.Delete(ctx, o.Name); err != nil {
+			return pruned, fmt.Errorf("删除备份 %s 失败: %v", o.Name, err)
+		}
+		pruned = append(pruned, o.Name)
+	}
+	return pruned, nil
+}
+
+// List 列出所有备份，按时间升序
+func (s *BackupService) List(ctx context.Context) ([]backup.Object, error) {
+	return s.storage.List(ctx)
+}
+
+// FindAt 时间点恢复：返回不晚于 at 的最近一个备份
+func (s *BackupService) FindAt(ctx context.Context, at time.Time) (string, error) {
+	objects, err := s.storage.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	for i := len(objects) - 1; i >= 0; i-- {
+		if t, ok := backup.ArchiveTime(objects[i].Name); ok && !t.After(at) {
+			return objects[i].Name, nil
+		}
+	}
+	return "", fmt.Errorf("没有早于 %s 的备份", at.Format(utils.TimeFormatDateTime))
+}
+
+// Verify 下载并完整校验备份
+func (s *BackupService) Verify(ctx context.Context, name string) (*backup.Manifest, error) {
+	r, err := s.storage.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return backup.Verify(r)
+}
+
+// Restore 下载备份到临时文件并完整校验后，依次恢复数据库、系统配置和目录。
+// 目录恢复只覆盖归档中的文件，不删除当前目录中多出的文件。
+func (s *BackupService) Restore(ctx context.Context, name string, opts BackupRestoreOptions) (*BackupRestoreResult, error) {
+	if !opts.Database && !opts.Files && !opts.Config {
+		return nil, fmt.Errorf("未选择要恢复的内容")
+	}
+	if !s.runMutex.TryLock() {
+		return nil, fmt.Errorf("备份正在进行中")
+	}
+	defer s.runMutex.Unlock()
+
+	tmp, err := os.CreateTemp("", "urldb-restore-*"+backup.ArchiveExt)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+	r, err := s.storage.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(tmp, r)
+	r.Close()
+	if err != nil {
+		return nil, fmt.Errorf("下载备份失败: %v", err)
+	}
+
+	rewind := func() (io.Reader, error) {
+		_, err := tmp.Seek(0, io.SeekStart)
+		return tmp, err
+	}
+	archive, err := rewind()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := backup.Verify(archive)
+	if err != nil {
+		return nil, fmt.Errorf("备份校验失败: %v", err)
+	}
+	result := &BackupRestoreResult{Name: name, Manifest: manifest}
+
+	if opts.Database {
+		if manifest.DBMode == "" {
+			return nil, fmt.Errorf("该备份不包含数据库")
+		}
+		if archive, err = rewind(); err != nil {
+			return nil, err
+		}
+		if result.Database, err = s.database.Restore(ctx, archive, manifest); err != nil {
+			return nil, fmt.Errorf("恢复数据库失败: %v", err)
+		}
+	}
+	if opts.Config {
+		if archive, err = rewind(); err != nil {
+			return nil, err
+		}
+		if result.Configs, err = s.restoreConfig(archive); err != nil {
+			return nil, fmt.Errorf("恢复系统配置失败: %v", err)
+		}
+	}
+	if opts.Files {
+		if archive, err = rewind(); err != nil {
+			return nil, err
+		}
+		if result.Files, err = s.restoreFiles(archive); err != nil {
+			return nil, fmt.Errorf("恢复文件失败: %v", err)
+		}
+	}
+	utils.Info("已从备份 %s 恢复: 数据库 %v, 配置 %d 项, 文件 %d 个", name, opts.Database, result.Configs, result.Files)
+	return result, nil
+}
+
+// restoreConfig 覆盖写入备份中的系统配置
+func (s *BackupService) restoreConfig(archive io.Reader) (int, error) {
+	var configs []entity.SystemConfig
+	found := false
+	err := backup.Walk(archive, func(name string, size int64, r io.Reader) error {
+		if name != backupConfigEntry {
+			return nil
+		}
+		found = true
+		return json.NewDecoder(r).Decode(&configs)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("备份中没有系统配置")
+	}
+	if len(configs) == 0 {
+		return 0, nil
+	}
+	if err := s.configRepo.UpsertConfigs(configs); err != nil {
+		return 0, err
+	}
+	if err := s.configRepo.SafeRefreshConfigCache(); err != nil {
+		utils.Warn("刷新配置缓存失败: %v", err)
+	}
+	return len(configs), nil
+}
+
+// restoreFiles 将 files/<来源>/ 下的文件写回对应目录，当前未配置的来源跳过
+func (s *BackupService) restoreFiles(archive io.Reader) (int, error) {
+	dirs := make(map[string]string, len(s.sources))
+	for _, src := range s.sources {
+		dirs[src.Name] = src.Dir
+	}
+	count := 0
+	err := backup.Walk(archive, func(name string, size int64, r io.Reader) error {
+		rest, ok := strings.CutPrefix(name, backup.PrefixFiles)
+		if !ok {
+			return nil
+		}
+		source, rel, ok := strings.Cut(rest, "/")
+		dir, known := dirs[source]
+		if !ok || !known || rel == "" {
+			return nil
+		}
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := writeFileAtomic(target, r); err != nil {
+			return fmt.Errorf("写入 %s 失败: %v", target, err)
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// writeFileAtomic 先写同目录临时文件再改名
+func writeFileAtomic(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// RefreshStatus 从存储刷新备份数量、大小与最近一次备份
+func (s *BackupService) RefreshStatus(ctx context.Context) error {
+	objects, err := s.storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, o := range objects {
+		total += o.Size
+	}
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	s.status.Count = len(objects)
+	s.status.TotalSize = total
+	s.status.Last = nil
+	if len(objects) > 0 {
+		last := objects[len(objects)-1]
+		s.status.Last = &last
+	}
+	return nil
+}
+
+// Status 当前备份状态
+func (s *BackupService) Status() BackupStatus {
+	cfg := s.Config()
+	s.statusMutex.RLock()
+	status := s.status
+	s.statusMutex.RUnlock()
+
+	status.Enabled = cfg.Enabled
+	if cfg.Enabled {
+		next := s.now()
+		if status.Last != nil {
+			if lastAt, ok := backup.ArchiveTime(status.Last.Name); ok {
+				if due := lastAt.Add(time.Duration(cfg.IntervalHours) * time.Hour); due.After(next) {
+					next = due
+				}
+			}
+		}
+		status.NextRunAt = &next
+	}
+	return status
+}
+
+func (s *BackupService) setRunning(running bool) {
+	s.statusMutex.Lock()
+	s.status.Running = running
+	if running {
+		s.status.LastError = ""
+		s.status.LastErrorAt = nil
+	}
+	s.statusMutex.Unlock()
+}
+
+func (s *BackupService) recordError(err error) {
+	now := s.now()
+	s.statusMutex.Lock()
+	s.status.LastError = err.Error()
+	s.status.LastErrorAt = &now
+	s.statusMutex.Unlock()
+	utils.Error("备份失败: %v", err)
+}
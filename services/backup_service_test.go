@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/backup"
+)
+
+type fakeBackupConfigRepo struct {
+	repo.SystemConfigRepository
+	values map[string]string
+}
+
+func (f *fakeBackupConfigRepo) GetConfigValue(key string) (string, error) {
+	return f.values[key], nil
+}
+
+func (f *fakeBackupConfigRepo) GetConfigInt(key string) (int, error) {
+	v, ok := f.values[key]
+	if !ok {
+		return 0, fmt.Errorf("not found")
+	}
+	return strconv.Atoi(v)
+}
+
+func (f *fakeBackupConfigRepo) FindAll() ([]entity.SystemConfig, error) {
+	var configs []entity.SystemConfig
+	for k, v := range f.values {
+		configs = append(configs, entity.SystemConfig{Key: k, Value: v, Type: entity.ConfigTypeString})
+	}
+	return configs, nil
+}
+
+func (f *fakeBackupConfigRepo) UpsertConfigs(configs []entity.SystemConfig) error {
+	for _, c := range configs {
+		f.values[c.Key] = c.Value
+	}
+	return nil
+}
+
+func (f *fakeBackupConfigRepo) SafeRefreshConfigCache() error { return nil }
+
+// fakeBackupDatabase 把一段文本作为数据库内容写入归档
+type fakeBackupDatabase struct {
+	data     string
+	restored string
+}
+
+func (f *fakeBackupDatabase) Dump(_ context.Context, w *backup.Writer, mode string) error {
+	w.Manifest().DBMode = backup.DBModeNative
+	return w.AddBytes(backup.PrefixDatabase+"tables/resources.copy", []byte(f.data))
+}
+
+func (f *fakeBackupDatabase) Restore(_ context.Context, r io.Reader, _ *backup.Manifest) (*backup.RestoreStats, error) {
+	err := backup.Walk(r, func(name string, size int64, entry io.Reader) error {
+		b, err := io.ReadAll(entry)
+		if strings.HasPrefix(name, backup.PrefixDatabase) {
+			f.restored = string(b)
+		}
+		return err
+	})
+	return &backup.RestoreStats{Mode: backup.DBModeNative, Tables: 1}, err
+}
+
+func newTestBackupService(t *testing.T) (*BackupService, *fakeBackupDatabase, *fakeBackupConfigRepo, string) {
+	t.Helper()
+	root := t.TempDir()
+	uploads := filepath.Join(root, "uploads")
+	os.MkdirAll(filepath.Join(uploads, "images"), 0755)
+	os.WriteFile(filepath.Join(uploads, "images", "a.png"), []byte("png"), 0644)
+
+	db := &fakeBackupDatabase{data: "1\tfirst\n"}
+	configRepo := &fakeBackupConfigRepo{values: map[string]string{
+		entity.BackupConfigKeyEnabled:       "true",
+		entity.BackupConfigKeyIntervalHours: "24",
+		entity.BackupConfigKeyKeepLast:      "2",
+		entity.BackupConfigKeyKeepDaily:     "0",
+		entity.BackupConfigKeyKeepWeekly:    "0",
+		"site_title":                        "urldb",
+	}}
+	svc := NewBackupService(backup.NewLocalStorage(filepath.Join(root, "backups")), db, configRepo, []BackupSource{
+		{Name: "uploads", Dir: uploads},
+		{Name: "plugins", Dir: filepath.Join(root, "plugins"), Plugin: true},
+	})
+	return svc, db, configRepo, uploads
+}
+
+func TestBackupRunRestore(t *testing.T) {
+	svc, db, configRepo, uploads := newTestBackupService(t)
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.Local)
+	svc.now = func() time.Time { return now }
+
+	if !svc.Due() {
+		t.Fatal("backup should be due without previous backups")
+	}
+	result, err := svc.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Name != "urldb-20260501-030000.tar.gz" || result.Files != 1 || result.DBMode != backup.DBModeNative {
+		t.Fatalf("result = %+v", result)
+	}
+	if svc.Due() {
+		t.Fatal("backup should not be due right after a run")
+	}
+	status := svc.Status()
+	if status.Count != 1 || status.Last == nil || status.Last.Name != result.Name || status.LastError != "" {
+		t.Fatalf("status = %+v", status)
+	}
+	if manifest, err := svc.Verify(ctx, result.Name); err != nil || len(manifest.Sources) != 2 {
+		t.Fatalf("Verify = %+v, %v", manifest, err)
+	}
+
+	// 修改现场后恢复
+	os.WriteFile(filepath.Join(uploads, "images", "a.png"), []byte("changed"), 0644)
+	configRepo.values["site_title"] = "changed"
+	restored, err := svc.Restore(ctx, result.Name, BackupRestoreOptions{Database: true, Files: true, Config: true})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if db.restored != db.data || restored.Files != 1 || restored.Configs == 0 {
+		t.Fatalf("restored = %+v, db = %q", restored, db.restored)
+	}
+	if b, _ := os.ReadFile(filepath.Join(uploads, "images", "a.png")); string(b) != "png" {
+		t.Fatalf("restored file = %q", b)
+	}
+	if configRepo.values["site_title"] != "urldb" {
+		t.Fatalf("restored config = %q", configRepo.values["site_title"])
+	}
+}
+
+func TestBackupRetentionAndFindAt(t *testing.T) {
+	svc, _, _, _ := newTestBackupService(t)
+	ctx := context.Background()
+	base := time.Date(2026, 5, 1, 3, 0, 0, 0, time.Local)
+	for i := 0; i < 4; i++ {
+		at := base.Add(time.Duration(i) * 24 * time.Hour)
+		svc.now = func() time.Time { return at }
+		if _, err := svc.Run(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, _ := svc.List(ctx)
+	if len(objects) != 2 || objects[0].Name != "urldb-20260503-030000.tar.gz" {
+		t.Fatalf("after retention = %+v", objects)
+	}
+
+	name, err := svc.FindAt(ctx, time.Date(2026, 5, 3, 12, 0, 0, 0, time.Local))
+	if err != nil || name != "urldb-20260503-030000.tar.gz" {
+		t.Fatalf("FindAt = %q, %v", name, err)
+	}
+	if _, err := svc.FindAt(ctx, base); err == nil {
+		t.Fatal("FindAt before the oldest kept backup should fail")
+	}
+}
+
+func TestBackupRestoreRejectsCorruptArchive(t *testing.T) {
+	svc, db, _, _ := newTestBackupService(t)
+	ctx := context.Background()
+	result, err := svc.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(svc.storage.(*backup.LocalStorage).Dir(), result.Name)
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, data[:len(data)-40], 0644)
+
+	if _, err := svc.Restore(ctx, result.Name, BackupRestoreOptions{Database: true}); err == nil || !strings.Contains(err.Error(), "校验失败") {
+		t.Fatalf("Restore corrupt err = %v", err)
+	}
+	if db.restored != "" {
+		t.Fatal("database must not be touched when verification fails")
+	}
+}
+
+func TestBackupStorageRejectsPublicDir(t *testing.T) {
+	t.Setenv("BACKUP_STORAGE", "local")
+	for _, dir := range []string{"./data/backups", "data", "./uploads/backups", "./data/../data/x"} {
+		t.Setenv("BACKUP_DIR", dir)
+		if _, err := NewBackupStorageFromEnv(); err == nil {
+			t.Fatalf("BACKUP_DIR=%s should be rejected", dir)
+		}
+	}
+	for _, dir := range []string{"", "./backups", "./database", filepath.Join(t.TempDir(), "backups")} {
+		t.Setenv("BACKUP_DIR", dir)
+		if _, err := NewBackupStorageFromEnv(); err != nil {
+			t.Fatalf("BACKUP_DIR=%q: %v", dir, err)
+		}
+	}
+	if InPublicStaticDir(DefaultBackupDir) {
+		t.Fatal("default backup dir must not be publicly served")
+	}
+}
@@ -19,7 +19,7 @@ func SetMeilisearchManager(manager *MeilisearchManager) {
 	meilisearchManager = manager
 }
 
-// UnifiedSearchResources 执行统一搜索（优先使用Meilisearch，否则使用数据库搜索）并处理违禁词
+// UnifiedSearchResources 执行统一搜索（优先使用Meilisearch，否则使用数据库搜索），按黑名单过滤并处理违禁词
 func UnifiedSearchResources(keyword string, limit int, systemConfigRepo repo.SystemConfigRepository, resourceRepo repo.ResourceRepository) ([]entity.Resource, error) {
 	var resources []entity.Resource
 	var total int64
@@ -46,12 +46,14 @@ func UnifiedSearchResources(keyword string, limit int, systemConfigRepo repo.Sys
 						FileSize:    doc.FileSize,
 						Key:         doc.Key,
 						PanID:       doc.PanID,
+						Author:      doc.Author,
 						CreatedAt:   doc.CreatedAt,
 						UpdatedAt:   doc.UpdatedAt,
 					}
 					resources = append(resources, resource)
 				}
 				total = docTotal
+				resources = filterBlockedResources(resources)
 
 				// 获取违禁词配置并处理违禁词
 				cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
@@ -91,7 +93,7 @@ func UnifiedSearchResources(keyword string, limit int, systemConfigRepo repo.Sys
 			filtered = append(filtered, r)
 		}
 	}
-	resources = filtered
+	resources = filterBlockedResources(filtered)
 
 	// 获取违禁词配置并处理违禁词
 	cleanWords, err := utils.GetForbiddenWordsFromConfig(func() (string, error) {
@@ -109,3 +111,24 @@ func UnifiedSearchResources(keyword string, limit int, systemConfigRepo repo.Sys
 
 	return resources, nil
 }
+
+// filterBlockedResources 按黑名单过滤搜索结果，黑名单服务未初始化时原样返回
+func filterBlockedResources(resources []entity.Resource) []entity.Resource {
+	if defaultBlocklistService == nil {
+		return resources
+	}
+	return defaultBlocklistService.FilterResources(resources)
+}
+
+// filterBlockedDocuments 按黑名单过滤 Meilisearch 搜索结果
+func filterBlockedDocuments(docs []MeilisearchDocument) []MeilisearchDocument {
+	if defaultBlocklistService == nil {
+		return docs
+	}
+	return defaultBlocklistService.FilterDocuments(docs)
+}
+
+// resourceAllowed 单个资源是否允许通过机器人对外展示
+func resourceAllowed(res *entity.Resource) bool {
+	return defaultBlocklistService == nil || defaultBlocklistService.Allows(res)
+}
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+)
+
+// blocklistRefreshInterval 规则缓存的最长有效期，多实例部署时其他实例的修改最迟在此间隔后生效
+const blocklistRefreshInterval = time.Minute
+
+// blocklistActionRank 处理方式优先级，多条规则命中时取最高者
+var blocklistActionRank = map[string]int{
+	entity.BlocklistActionMask:   1,
+	entity.BlocklistActionHide:   2,
+	entity.BlocklistActionReject: 3,
+}
+
+// BlocklistTarget 参与黑名单匹配的资源字段
+type BlocklistTarget struct {
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+// BlocklistMatch 黑名单匹配结果
+type BlocklistMatch struct {
+	Action string                 `json:"action"` // 命中规则中优先级最高的处理方式
+	Rules  []entity.BlocklistRule `json:"rules"`
+	masks  []*regexp.Regexp
+}
+
+// Excludes 是否应从搜索结果、机器人回复与频道推送中排除
+func (m *BlocklistMatch) Excludes() bool {
+	return m != nil && (m.Action == entity.BlocklistActionReject || m.Action == entity.BlocklistActionHide)
+}
+
+// Reason 面向管理员的命中原因
+func (m *BlocklistMatch) Reason() string {
+	for _, rule := range m.Rules {
+		if rule.Action != m.Action {
+			continue
+		}
+		if rule.Source == entity.BlocklistSourceModeration {
+			return fmt.Sprintf("已被下架处理（处置 #%d）", rule.SourceID)
+		}
+		return fmt.Sprintf("命中黑名单规则 #%d（%s: %s）", rule.ID, rule.Kind, rule.Pattern)
+	}
+	return ""
+}
+
+// MaskTitle 将标题中命中打码规则的部分替换为 *
+func (m *BlocklistMatch) MaskTitle(title string) string {
+	if m == nil {
+		return title
+	}
+	for _, re := range m.masks {
+		title = re.ReplaceAllStringFunc(title, func(s string) string {
+			return strings.Repeat("*", utf8.RuneCountInString(s))
+		})
+	}
+	return title
+}
+
+// blocklistTitleRule 编译后的标题规则
+type blocklistTitleRule struct {
+	rule *entity.BlocklistRule
+	re   *regexp.Regexp
+}
+
+// blocklistIndex 启用规则按类型建立的索引
+type blocklistIndex struct {
+	urls     map[string][]*entity.BlocklistRule
+	shareIDs map[string][]*entity.BlocklistRule
+	authors  map[string][]*entity.BlocklistRule
+	domains  map[string][]*entity.BlocklistRule
+	titles   []blocklistTitleRule
+}
+
+func newBlocklistIndex(rules []entity.BlocklistRule) *blocklistIndex {
+	idx := &blocklistIndex{
+		urls:     make(map[string][]*entity.BlocklistRule),
+		shareIDs: make(map[string][]*entity.BlocklistRule),
+		authors:  make(map[string][]*entity.BlocklistRule),
+		domains:  make(map[string][]*entity.BlocklistRule),
+	}
+	for i := range rules {
+		rule := &rules[i]
+		switch rule.Kind {
+		case entity.BlocklistKindURL:
+			key := normalizeBlocklistURL(rule.Pattern)
+			idx.urls[key] = append(idx.urls[key], rule)
+		case entity.BlocklistKindShareID:
+			idx.shareIDs[rule.Pattern] = append(idx.shareIDs[rule.Pattern], rule)
+		case entity.BlocklistKindAuthor:
+			key := strings.ToLower(rule.Pattern)
+			idx.authors[key] = append(idx.authors[key], rule)
+		case entity.BlocklistKindDomain:
+			key := normalizeBlocklistDomain(rule.Pattern)
+			idx.domains[key] = append(idx.domains[key], rule)
+		case entity.BlocklistKindTitle:
+			re, err := compileBlocklistTitle(rule.Pattern)
+			if err != nil {
+				utils.Warn("Blocklist - 标题规则无效，已跳过 - ID: %d, Error: %v", rule.ID, err)
+				continue
+			}
+			idx.titles = append(idx.titles, blocklistTitleRule{rule: rule, re: re})
+		}
+	}
+	return idx
+}
+
+// match 返回全部命中的规则，未命中时为 nil
+func (idx *blocklistIndex) match(t BlocklistTarget) *BlocklistMatch {
+	var m BlocklistMatch
+	add := func(rules []*entity.BlocklistRule) {
+		for _, rule := range rules {
+			m.Rules = append(m.Rules, *rule)
+			if blocklistActionRank[rule.Action] > blocklistActionRank[m.Action] {
+				m.Action = rule.Action
+			}
+		}
+	}
+
+	if link := strings.TrimSpace(t.URL); link != "" {
+		add(idx.urls[normalizeBlocklistURL(link)])
+		if shareID, serviceType := panutils.ExtractShareId(link); serviceType != panutils.NotFound && shareID != "" {
+			add(idx.shareIDs[shareID])
+		}
+		if host := blocklistHost(link); host != "" {
+			// 逐级检查上级域名，规则 example.com 同时命中 pan.example.com
+			for {
+				add(idx.domains[host])
+				dot := strings.Index(host, ".")
+				if dot < 0 {
+					break
+				}
+				host = host[dot+1:]
+			}
+		}
+	}
+	if author := strings.ToLower(strings.TrimSpace(t.Author)); author != "" {
+		add(idx.authors[author])
+	}
+	if t.Title != "" {
+		for _, tr := range idx.titles {
+			if !tr.re.MatchString(t.Title) {
+				continue
+			}
+			add([]*entity.BlocklistRule{tr.rule})
+			if tr.rule.Action == entity.BlocklistActionMask {
+				m.masks = append(m.masks, tr.re)
+			}
+		}
+	}
+
+	if len(m.Rules) == 0 {
+		return nil
+	}
+	return &m
+}
+
+// BlocklistService 黑名单：按分享链接、分享ID、标题正则、作者与域名拦截资源，
+// 在入库、搜索、机器人与频道推送中统一生效，并由举报/版权申述处置自动补充规则
+type BlocklistService struct {
+	ruleRepo repo.BlocklistRuleRepository
+	now      func() time.Time
+
+	mu       sync.Mutex
+	index    *blocklistIndex
+	loadedAt time.Time
+}
+
+// NewBlocklistService 创建黑名单服务
+func NewBlocklistService(ruleRepo repo.BlocklistRuleRepository) *BlocklistService {
+	return &BlocklistService{
+		ruleRepo: ruleRepo,
+		now:      utils.GetCurrentTime,
+	}
+}
+
+// snapshot 获取规则索引，缓存过期时重新加载；加载失败时继续使用旧索引
+func (s *BlocklistService) snapshot() *blocklistIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.index != nil && now.Sub(s.loadedAt) < blocklistRefreshInterval {
+		return s.index
+	}
+	s.loadedAt = now
+	rules, err := s.ruleRepo.FindEnabled()
+	if err != nil {
+		utils.Error("Blocklist - 加载规则失败: %v", err)
+		if s.index == nil {
+			s.index = newBlocklistIndex(nil)
+		}
+		return s.index
+	}
+	s.index = newBlocklistIndex(rules)
+	return s.index
+}
+
+// invalidate 规则变化后清空缓存
+func (s *BlocklistService) invalidate() {
+	s.mu.Lock()
+	s.index = nil
+	s.mu.Unlock()
+}
+
+// Match 匹配资源，未命中时返回 nil
+func (s *BlocklistService) Match(t BlocklistTarget) *BlocklistMatch {
+	return s.snapshot().match(t)
+}
+
+// CheckReject 入库前检查是否命中拒绝规则，命中时记录命中次数并返回原因
+func (s *BlocklistService) CheckReject(t BlocklistTarget) string {
+	m := s.Match(t)
+	if m == nil || m.Action != entity.BlocklistActionReject {
+		return ""
+	}
+	s.recordHits(m)
+	return m.Reason()
+}
+
+// ApplyIngest 入库时应用黑名单：命中拒绝规则时返回原因；命中隐藏规则时设为不公开；
+// 命中打码规则时对标题打码
+func (s *BlocklistService) ApplyIngest(res *entity.Resource) string {
+	m := s.Match(BlocklistTarget{URL: res.URL, Title: res.Title, Author: res.Author})
+	if m == nil {
+		return ""
+	}
+	s.recordHits(m)
+
+	switch m.Action {
+	case entity.BlocklistActionReject:
+		return m.Reason()
+	case entity.BlocklistActionHide:
+		res.IsPublic = false
+		utils.Warn("Blocklist - 资源已设为不公开 - 标题: %s, 原因: %s", res.Title, m.Reason())
+	}
+	res.Title = m.MaskTitle(res.Title)
+	return ""
+}
+
+func (s *BlocklistService) recordHits(m *BlocklistMatch) {
+	ids := make([]uint, 0, len(m.Rules))
+	for _, rule := range m.Rules {
+		ids = append(ids, rule.ID)
+	}
+	if err := s.ruleRepo.RecordHits(ids, s.now()); err != nil {
+		utils.Warn("Blocklist - 记录命中次数失败: %v", err)
+	}
+}
+
+// FilterResources 过滤搜索结果：排除命中拒绝或隐藏规则的资源，对命中打码规则的标题打码
+func (s *BlocklistService) FilterResources(resources []entity.Resource) []entity.Resource {
+	idx := s.snapshot()
+	filtered := resources[:0]
+	for _, res := range resources {
+		m := idx.match(BlocklistTarget{URL: res.URL, Title: res.Title, Author: res.Author})
+		if m.Excludes() {
+			continue
+		}
+		res.Title = m.MaskTitle(res.Title)
+		filtered = append(filtered, res)
+	}
+	return filtered
+}
+
+// FilterDocuments 过滤 Meilisearch 搜索结果，规则与 FilterResources 一致
+func (s *BlocklistService) FilterDocuments(docs []MeilisearchDocument) []MeilisearchDocument {
+	idx := s.snapshot()
+	filtered := docs[:0]
+	for _, doc := range docs {
+		m := idx.match(BlocklistTarget{URL: doc.URL, Title: doc.Title, Author: doc.Author})
+		if m.Excludes() {
+			continue
+		}
+		if masked := m.MaskTitle(doc.Title); masked != doc.Title {
+			doc.Title = masked
+			doc.TitleHighlight = masked
+		}
+		filtered = append(filtered, doc)
+	}
+	return filtered
+}
+
+// Allows 单个资源是否允许对外展示（机器人取链等场景）
+func (s *BlocklistService) Allows(res *entity.Resource) bool {
+	return !s.Match(BlocklistTarget{URL: res.URL, Title: res.Title, Author: res.Author}).Excludes()
+}
+
+// List 分页查询规则
+func (s *BlocklistService) List(kind, action, source, keyword string, page, pageSize int) ([]entity.BlocklistRule, int64, error) {
+	return s.ruleRepo.Search(kind, action, source, keyword, page, pageSize)
+}
+
+// Get 获取规则
+func (s *BlocklistService) Get(id uint) (*entity.BlocklistRule, error) {
+	return s.ruleRepo.FindByID(id)
+}
+
+// Create 创建规则
+func (s *BlocklistService) Create(rule *entity.BlocklistRule) error {
+	if err := ValidateBlocklistRule(rule); err != nil {
+		return err
+	}
+	if rule.Source == "" {
+		rule.Source = entity.BlocklistSourceManual
+	}
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Update 保存规则
+func (s *BlocklistService) Update(rule *entity.BlocklistRule) error {
+	if err := ValidateBlocklistRule(rule); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Save(rule); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Delete 删除规则
+func (s *BlocklistService) Delete(id uint) error {
+	if err := s.ruleRepo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// AddModerationRules 处置生效期间拒绝资源重新入库：链接与分享ID始终加入，标题可选
+func (s *BlocklistService) AddModerationRules(actionID uint, resources []entity.Resource, blockTitle bool, operatorID uint) error {
+	seen := make(map[string]bool)
+	var rules []entity.BlocklistRule
+	add := func(kind, pattern string) {
+		if pattern == "" || seen[kind+"\x00"+pattern] {
+			return
+		}
+		seen[kind+"\x00"+pattern] = true
+		rules = append(rules, entity.BlocklistRule{
+			Kind:      kind,
+			Pattern:   pattern,
+			Action:    entity.BlocklistActionReject,
+			Source:    entity.BlocklistSourceModeration,
+			SourceID:  actionID,
+			Enabled:   true,
+			Note:      fmt.Sprintf("处置 #%d 自动添加", actionID),
+			CreatedBy: operatorID,
+		})
+	}
+	for _, res := range resources {
+		link := strings.TrimSpace(res.URL)
+		add(entity.BlocklistKindURL, normalizeBlocklistURL(link))
+		if shareID, serviceType := panutils.ExtractShareId(link); serviceType != panutils.NotFound {
+			add(entity.BlocklistKindShareID, shareID)
+		}
+		if blockTitle {
+			add(entity.BlocklistKindTitle, exactTitlePattern(res.Title))
+		}
+	}
+	if err := s.ruleRepo.CreateBatch(rules); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// RemoveModerationRules 撤销处置时删除其生成的规则
+func (s *BlocklistService) RemoveModerationRules(actionID uint) error {
+	if err := s.ruleRepo.DeleteBySource(entity.BlocklistSourceModeration, actionID); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ValidateBlocklistRule 校验并规范化规则，保存前由 Create/Update 调用
+func ValidateBlocklistRule(rule *entity.BlocklistRule) error {
+	rule.Pattern = strings.TrimSpace(rule.Pattern)
+	if rule.Pattern == "" {
+		return errors.New("匹配内容不能为空")
+	}
+	if blocklistActionRank[rule.Action] == 0 {
+		return fmt.Errorf("不支持的处理方式: %s", rule.Action)
+	}
+	if rule.Action == entity.BlocklistActionMask && rule.Kind != entity.BlocklistKindTitle {
+		return errors.New("打码仅适用于标题规则")
+	}
+
+	switch rule.Kind {
+	case entity.BlocklistKindURL:
+		rule.Pattern = normalizeBlocklistURL(rule.Pattern)
+	case entity.BlocklistKindShareID, entity.BlocklistKindAuthor:
+	case entity.BlocklistKindDomain:
+		rule.Pattern = normalizeBlocklistDomain(rule.Pattern)
+		if rule.Pattern == "" {
+			return errors.New("域名格式错误")
+		}
+	case entity.BlocklistKindTitle:
+		if _, err := compileBlocklistTitle(rule.Pattern); err != nil {
+			return fmt.Errorf("标题正则无效: %v", err)
+		}
+	default:
+		return fmt.Errorf("不支持的匹配类型: %s", rule.Kind)
+	}
+	if len(rule.Pattern) > 500 {
+		return errors.New("匹配内容过长")
+	}
+	return nil
+}
+
+func compileBlocklistTitle(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// exactTitlePattern 与标题完全相同（忽略大小写与多余空白）的正则
+func exactTitlePattern(title string) string {
+	fields := strings.Fields(title)
+	if len(fields) == 0 {
+		return ""
+	}
+	for i, field := range fields {
+		fields[i] = regexp.QuoteMeta(field)
+	}
+	return `^\s*` + strings.Join(fields, `\s+`) + `\s*$`
+}
+
+// normalizeBlocklistURL 链接比较前的规范化：去空白与末尾斜杠，协议与域名转小写
+func normalizeBlocklistURL(raw string) string {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return strings.TrimRight(u.String(), "/")
+}
+
+// normalizeBlocklistDomain 域名规则规范化，兼容填写完整链接或 *.example.com
+func normalizeBlocklistDomain(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if strings.Contains(raw, "://") {
+		raw = blocklistHost(raw)
+	}
+	raw = strings.TrimPrefix(raw, "*.")
+	return strings.Trim(raw, ".")
+}
+
+func blocklistHost(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+var defaultBlocklistService *BlocklistService
+
+// SetDefaultBlocklistService 设置默认黑名单服务实例
+func SetDefaultBlocklistService(s *BlocklistService) {
+	defaultBlocklistService = s
+}
+
+// GetDefaultBlocklistService 获取默认黑名单服务实例，未初始化时为 nil
+func GetDefaultBlocklistService() *BlocklistService {
+	return defaultBlocklistService
+}
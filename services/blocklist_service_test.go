@@ -0,0 +1,210 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+)
+
+type fakeBlocklistRuleRepo struct {
+	repo.BlocklistRuleRepository
+	rules  []entity.BlocklistRule
+	nextID uint
+	loads  int
+	hits   map[uint]int64
+}
+
+func (f *fakeBlocklistRuleRepo) Create(rule *entity.BlocklistRule) error {
+	f.nextID++
+	rule.ID = f.nextID
+	f.rules = append(f.rules, *rule)
+	return nil
+}
+
+func (f *fakeBlocklistRuleRepo) CreateBatch(rules []entity.BlocklistRule) error {
+	for i := range rules {
+		_ = f.Create(&rules[i])
+	}
+	return nil
+}
+
+func (f *fakeBlocklistRuleRepo) Save(rule *entity.BlocklistRule) error {
+	for i := range f.rules {
+		if f.rules[i].ID == rule.ID {
+			f.rules[i] = *rule
+		}
+	}
+	return nil
+}
+
+func (f *fakeBlocklistRuleRepo) FindEnabled() ([]entity.BlocklistRule, error) {
+	f.loads++
+	var rules []entity.BlocklistRule
+	for _, rule := range f.rules {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeBlocklistRuleRepo) DeleteBySource(source string, sourceID uint) error {
+	kept := f.rules[:0]
+	for _, rule := range f.rules {
+		if rule.Source != source || rule.SourceID != sourceID {
+			kept = append(kept, rule)
+		}
+	}
+	f.rules = kept
+	return nil
+}
+
+func (f *fakeBlocklistRuleRepo) RecordHits(ids []uint, hitAt time.Time) error {
+	if f.hits == nil {
+		f.hits = make(map[uint]int64)
+	}
+	for _, id := range ids {
+		f.hits[id]++
+	}
+	return nil
+}
+
+func newTestBlocklist(t *testing.T, rules ...entity.BlocklistRule) (*BlocklistService, *fakeBlocklistRuleRepo) {
+	t.Helper()
+	ruleRepo := &fakeBlocklistRuleRepo{}
+	svc := NewBlocklistService(ruleRepo)
+	for i := range rules {
+		rules[i].Enabled = true
+		if err := svc.Create(&rules[i]); err != nil {
+			t.Fatalf("create rule %+v: %v", rules[i], err)
+		}
+	}
+	return svc, ruleRepo
+}
+
+func TestBlocklistMatchKinds(t *testing.T) {
+	svc, _ := newTestBlocklist(t,
+		entity.BlocklistRule{Kind: entity.BlocklistKindURL, Pattern: "HTTPS://Pan.Quark.cn/s/abc/", Action: entity.BlocklistActionReject},
+		entity.BlocklistRule{Kind: entity.BlocklistKindShareID, Pattern: "1xyz", Action: entity.BlocklistActionHide},
+		entity.BlocklistRule{Kind: entity.BlocklistKindDomain, Pattern: "*.spam.example", Action: entity.BlocklistActionReject},
+		entity.BlocklistRule{Kind: entity.BlocklistKindAuthor, Pattern: "BadUploader", Action: entity.BlocklistActionHide},
+		entity.BlocklistRule{Kind: entity.BlocklistKindTitle, Pattern: `盗版|枪版`, Action: entity.BlocklistActionMask},
+	)
+
+	cases := []struct {
+		name   string
+		target BlocklistTarget
+		action string
+	}{
+		{"url normalized", BlocklistTarget{URL: "https://pan.quark.cn/s/abc"}, entity.BlocklistActionReject},
+		{"share id with password", BlocklistTarget{URL: "https://pan.baidu.com/s/1xyz?pwd=8888"}, entity.BlocklistActionHide},
+		{"sub domain", BlocklistTarget{URL: "https://dl.spam.example/s/q"}, entity.BlocklistActionReject},
+		{"author case insensitive", BlocklistTarget{Author: " baduploader "}, entity.BlocklistActionHide},
+		{"title regex", BlocklistTarget{Title: "某电影 枪版 1080P"}, entity.BlocklistActionMask},
+		{"highest action wins", BlocklistTarget{URL: "https://pan.quark.cn/s/abc", Title: "盗版"}, entity.BlocklistActionReject},
+		{"no match", BlocklistTarget{URL: "https://notspam.example/s/q", Title: "正版"}, ""},
+	}
+	for _, tc := range cases {
+		m := svc.Match(tc.target)
+		got := ""
+		if m != nil {
+			got = m.Action
+		}
+		if got != tc.action {
+			t.Errorf("%s: action = %q, want %q", tc.name, got, tc.action)
+		}
+	}
+
+	if masked := svc.Match(BlocklistTarget{Title: "盗版合集"}).MaskTitle("盗版合集"); masked != "**合集" {
+		t.Fatalf("masked title = %q", masked)
+	}
+}
+
+func TestBlocklistApplyIngestAndFilter(t *testing.T) {
+	svc, ruleRepo := newTestBlocklist(t,
+		entity.BlocklistRule{Kind: entity.BlocklistKindShareID, Pattern: "bad", Action: entity.BlocklistActionReject},
+		entity.BlocklistRule{Kind: entity.BlocklistKindAuthor, Pattern: "spammer", Action: entity.BlocklistActionHide},
+		entity.BlocklistRule{Kind: entity.BlocklistKindTitle, Pattern: `枪版`, Action: entity.BlocklistActionMask},
+	)
+
+	rejected := &entity.Resource{URL: "https://pan.quark.cn/s/bad", Title: "资源", IsPublic: true}
+	if reason := svc.ApplyIngest(rejected); !strings.Contains(reason, "#1") {
+		t.Fatalf("reject reason = %q", reason)
+	}
+	hidden := &entity.Resource{URL: "https://pan.quark.cn/s/ok", Title: "枪版资源", Author: "Spammer", IsPublic: true}
+	if reason := svc.ApplyIngest(hidden); reason != "" || hidden.IsPublic || hidden.Title != "**资源" {
+		t.Fatalf("hide+mask: reason=%q resource=%+v", reason, hidden)
+	}
+	if ruleRepo.hits[1] != 1 || ruleRepo.hits[2] != 1 || ruleRepo.hits[3] != 1 {
+		t.Fatalf("hits = %v", ruleRepo.hits)
+	}
+
+	results := svc.FilterResources([]entity.Resource{
+		{ID: 1, URL: "https://pan.quark.cn/s/bad", Title: "a"},
+		{ID: 2, URL: "https://pan.quark.cn/s/x", Title: "b", Author: "spammer"},
+		{ID: 3, URL: "https://pan.quark.cn/s/y", Title: "枪版c"},
+	})
+	if len(results) != 1 || results[0].ID != 3 || results[0].Title != "**c" {
+		t.Fatalf("filtered = %+v", results)
+	}
+	docs := svc.FilterDocuments([]MeilisearchDocument{
+		{ID: 1, URL: "https://pan.quark.cn/s/bad"},
+		{ID: 3, URL: "https://pan.quark.cn/s/y", Title: "枪版c", TitleHighlight: "<mark>枪版</mark>c"},
+	})
+	if len(docs) != 1 || docs[0].TitleHighlight != "**c" {
+		t.Fatalf("filtered docs = %+v", docs)
+	}
+}
+
+func TestBlocklistCacheAndValidation(t *testing.T) {
+	svc, ruleRepo := newTestBlocklist(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	if svc.Match(BlocklistTarget{Title: "x"}) != nil || svc.Match(BlocklistTarget{Title: "y"}) != nil {
+		t.Fatal("empty blocklist should match nothing")
+	}
+	if ruleRepo.loads != 1 {
+		t.Fatalf("rules should be cached, loads = %d", ruleRepo.loads)
+	}
+
+	rule := &entity.BlocklistRule{Kind: entity.BlocklistKindTitle, Pattern: "^x$", Action: entity.BlocklistActionReject, Enabled: true}
+	if err := svc.Create(rule); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Match(BlocklistTarget{Title: "X"}) == nil {
+		t.Fatal("new rule should take effect immediately")
+	}
+	rule.Enabled = false
+	if err := svc.Update(rule); err != nil {
+		t.Fatal(err)
+	}
+	if svc.Match(BlocklistTarget{Title: "X"}) != nil {
+		t.Fatal("disabled rule should not match")
+	}
+
+	// 其他实例的修改在缓存过期后生效
+	ruleRepo.rules = append(ruleRepo.rules, entity.BlocklistRule{ID: 99, Kind: entity.BlocklistKindAuthor, Pattern: "a", Action: entity.BlocklistActionHide, Enabled: true})
+	if svc.Match(BlocklistTarget{Author: "a"}) != nil {
+		t.Fatal("cache should still be valid")
+	}
+	now = now.Add(blocklistRefreshInterval)
+	if svc.Match(BlocklistTarget{Author: "a"}) == nil {
+		t.Fatal("cache should refresh after the interval")
+	}
+
+	invalid := []entity.BlocklistRule{
+		{Kind: entity.BlocklistKindURL, Pattern: "https://a/s/1", Action: entity.BlocklistActionMask},
+		{Kind: entity.BlocklistKindTitle, Pattern: "(", Action: entity.BlocklistActionReject},
+		{Kind: "keyword", Pattern: "x", Action: entity.BlocklistActionReject},
+		{Kind: entity.BlocklistKindAuthor, Pattern: "  ", Action: entity.BlocklistActionHide},
+	}
+	for _, r := range invalid {
+		if err := svc.Create(&r); err == nil {
+			t.Errorf("rule %+v should be rejected", r)
+		}
+	}
+}
@@ -0,0 +1,687 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/catalog"
+	"github.com/ctwj/urldb/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// catalogPageSize 导出时每页查询的资源数
+	catalogPageSize = 500
+	// catalogImportBatch 导入时每批去重、写入的记录数
+	catalogImportBatch = 200
+	// catalogMaxErrors 导入报告中保留的错误明细上限
+	catalogMaxErrors = 100
+)
+
+// CatalogFilter 导出筛选条件，字段与 SearchWithFilters 的参数一一对应
+type CatalogFilter struct {
+	Search     string  `json:"search,omitempty"`
+	CategoryID uint    `json:"category_id,omitempty"`
+	Category   string  `json:"category,omitempty"`
+	Tag        string  `json:"tag,omitempty"`
+	TagIDs     string  `json:"tag_ids,omitempty"`
+	PanID      uint    `json:"pan_id,omitempty"`
+	PanName    string  `json:"pan_name,omitempty"`
+	IsValid    *bool   `json:"is_valid,omitempty"`
+	IsPublic   *bool   `json:"is_public,omitempty"`
+	HasSaveURL *bool   `json:"has_save_url,omitempty"`
+	Year       int     `json:"year,omitempty"`
+	Region     string  `json:"region,omitempty"`
+	Genre      string  `json:"genre,omitempty"`
+	MediaType  string  `json:"media_type,omitempty"`
+	MinRating  float64 `json:"min_rating,omitempty"`
+}
+
+// Params 转换为 SearchWithFilters 的参数
+func (f CatalogFilter) Params() map[string]interface{} {
+	params := map[string]interface{}{}
+	setString := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			params[key] = value
+		}
+	}
+	setString("search", f.Search)
+	setString("category", f.Category)
+	setString("tag", f.Tag)
+	setString("tag_ids", f.TagIDs)
+	setString("pan_name", f.PanName)
+	setString("region", f.Region)
+	setString("genre", f.Genre)
+	setString("media_type", f.MediaType)
+	if f.CategoryID > 0 {
+		params["category_id"] = f.CategoryID
+	}
+	if f.PanID > 0 {
+		params["pan_id"] = f.PanID
+	}
+	if f.IsValid != nil {
+		params["is_valid"] = *f.IsValid
+	}
+	if f.IsPublic != nil {
+		params["is_public"] = *f.IsPublic
+	}
+	if f.HasSaveURL != nil {
+		params["has_save_url"] = *f.HasSaveURL
+	}
+	if f.Year > 0 {
+		params["year"] = f.Year
+	}
+	if f.MinRating > 0 {
+		params["min_rating"] = f.MinRating
+	}
+	return params
+}
+
+// CatalogImportOptions 导入选项
+type CatalogImportOptions struct {
+	Format string `json:"format"`
+	// CategoryMap 源分类名到本实例分类名的映射，映射为空串表示丢弃该分类
+	CategoryMap map[string]string `json:"category_map,omitempty"`
+	// TagMap 源标签名到本实例标签名的映射，映射为空串表示丢弃该标签
+	TagMap map[string]string `json:"tag_map,omitempty"`
+	// CreateMissing 本实例不存在的分类、标签自动创建；否则资源不带该分类、标签导入
+	CreateMissing bool `json:"create_missing"`
+	// DryRun 只统计不写入
+	DryRun bool `json:"dry_run"`
+}
+
+// CatalogImportError 导入失败的单条记录
+type CatalogImportError struct {
+	Line  int    `json:"line"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error"`
+}
+
+// CatalogImportReport 导入结果报告，DryRun 时各计数表示实际导入时的预期结果
+type CatalogImportReport struct {
+	DryRun             bool                 `json:"dry_run"`
+	Total              int                  `json:"total"`
+	Created            int                  `json:"created"`
+	Duplicates         int                  `json:"duplicates"`
+	Invalid            int                  `json:"invalid"`
+	Failed             int                  `json:"failed"`
+	KeysRemapped       int                  `json:"keys_remapped"`
+	CreatedCategories  []string             `json:"created_categories"`
+	CreatedTags        []string             `json:"created_tags"`
+	UnmappedCategories []string             `json:"unmapped_categories"`
+	UnmappedTags       []string             `json:"unmapped_tags"`
+	Errors             []CatalogImportError `json:"errors"`
+	ErrorsTruncated    bool                 `json:"errors_truncated"`
+}
+
+// CatalogProgress 进度回调，total 未知时为 0
+type CatalogProgress func(processed, total int)
+
+// CatalogService 资源目录导出/导入服务，用于在实例之间迁移资源
+type CatalogService struct {
+	resourceRepo repo.ResourceRepository
+	categoryRepo repo.CategoryRepository
+	tagRepo      repo.TagRepository
+	panRepo      repo.PanRepository
+	metadataRepo repo.ResourceMetadataRepository
+	now          func() time.Time
+}
+
+// NewCatalogService 创建资源目录服务
+func NewCatalogService(resourceRepo repo.ResourceRepository, categoryRepo repo.CategoryRepository, tagRepo repo.TagRepository, panRepo repo.PanRepository, metadataRepo repo.ResourceMetadataRepository) *CatalogService {
+	return &CatalogService{
+		resourceRepo: resourceRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		panRepo:      panRepo,
+		metadataRepo: metadataRepo,
+		now:          utils.GetCurrentTime,
+	}
+}
+
+// Export 按筛选条件流式导出资源，返回导出条数
+func (s *CatalogService) Export(ctx context.Context, w io.Writer, format string, filter CatalogFilter, progress CatalogProgress) (int, error) {
+	writer, err := catalog.NewWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	var lastID uint
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+
+		params := filter.Params()
+		params["page"] = page
+		params["page_size"] = catalogPageSize
+		params["order_by"] = "id"
+		params["order_dir"] = "ASC"
+		resources, total, err := s.resourceRepo.SearchWithFilters(params)
+		if err != nil {
+			return exported, fmt.Errorf("查询资源失败: %v", err)
+		}
+
+		// 按标签筛选时会连接中间表，同一资源可能出现多次；按 ID 升序跳过重复
+		ids := make([]uint, 0, len(resources))
+		for _, r := range resources {
+			ids = append(ids, r.ID)
+		}
+		metadata, err := s.metadataByResource(ids)
+		if err != nil {
+			return exported, err
+		}
+		for i := range resources {
+			r := &resources[i]
+			if r.ID <= lastID {
+				continue
+			}
+			lastID = r.ID
+			if err := writer.Write(toCatalogRecord(r, metadata[r.ID])); err != nil {
+				return exported, fmt.Errorf("写出资源 %d 失败: %v", r.ID, err)
+			}
+			exported++
+		}
+		if progress != nil {
+			progress(exported, int(total))
+		}
+		if len(resources) < catalogPageSize {
+			break
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return exported, err
+	}
+	return exported, nil
+}
+
+// metadataByResource 批量加载已匹配的元数据
+func (s *CatalogService) metadataByResource(ids []uint) (map[uint]*entity.ResourceMetadata, error) {
+	result := make(map[uint]*entity.ResourceMetadata)
+	if s.metadataRepo == nil || len(ids) == 0 {
+		return result, nil
+	}
+	list, err := s.metadataRepo.FindByResourceIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("查询元数据失败: %v", err)
+	}
+	for i := range list {
+		m := &list[i]
+		if m.SubjectID != "" && (m.Status == entity.ResourceMetadataMatched || m.Status == entity.ResourceMetadataManual) {
+			result[m.ResourceID] = m
+		}
+	}
+	return result, nil
+}
+
+func toCatalogRecord(r *entity.Resource, m *entity.ResourceMetadata) *catalog.Record {
+	record := &catalog.Record{
+		Key:         r.Key,
+		Title:       r.Title,
+		Description: r.Description,
+		URL:         r.URL,
+		SaveURL:     r.SaveURL,
+		FileSize:    r.FileSize,
+		Cover:       r.Cover,
+		Author:      r.Author,
+		Category:    r.Category.Name,
+		Pan:         r.Pan.Name,
+		ViewCount:   r.ViewCount,
+		IsValid:     r.IsValid,
+		IsPublic:    r.IsPublic,
+		CreatedAt:   r.CreatedAt,
+	}
+	for _, tag := range r.Tags {
+		record.Tags = append(record.Tags, tag.Name)
+	}
+	if m != nil {
+		record.Metadata = &catalog.Metadata{
+			Provider:      m.Provider,
+			SubjectID:     m.SubjectID,
+			MediaType:     m.MediaType,
+			Title:         m.Title,
+			OriginalTitle: m.OriginalTitle,
+			Year:          m.Year,
+			Regions:       m.Regions,
+			Genres:        m.Genres,
+			Rating:        m.Rating,
+			RatingCount:   m.RatingCount,
+			PosterURL:     m.PosterURL,
+			Episodes:      m.Episodes,
+			Directors:     m.Directors,
+			Actors:        m.Actors,
+			Summary:       m.Summary,
+			SubjectURL:    m.SubjectURL,
+		}
+	}
+	return record
+}
+
+// catalogImport 一次导入过程中的状态
+type catalogImport struct {
+	s      *CatalogService
+	opts   CatalogImportOptions
+	report *CatalogImportReport
+
+	seen       map[string]bool   // 文件内已出现的规范化URL
+	keys       map[string]string // 源key到本实例key的映射
+	categories map[string]*uint  // 分类名到ID的缓存，nil 表示不设置分类
+	tags       map[string]*uint  // 标签名到ID的缓存，nil 表示丢弃
+	pans       map[string]uint   // 网盘名（小写）到ID
+	dryRunID   uint              // DryRun 时为待创建的分类、标签分配的占位ID
+}
+
+type catalogPending struct {
+	line       int
+	record     *catalog.Record
+	normalized string
+}
+
+// Import 从 reader 读取目录并导入，按规范化URL去重（文件内与库内）
+func (s *CatalogService) Import(ctx context.Context, r io.Reader, opts CatalogImportOptions, progress CatalogProgress) (*CatalogImportReport, error) {
+	reader, err := catalog.NewReader(opts.Format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &catalogImport{
+		s:          s,
+		opts:       opts,
+		report:     &CatalogImportReport{DryRun: opts.DryRun},
+		seen:       make(map[string]bool),
+		keys:       make(map[string]string),
+		categories: make(map[string]*uint),
+		tags:       make(map[string]*uint),
+		pans:       make(map[string]uint),
+	}
+	pans, err := s.panRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("查询网盘列表失败: %v", err)
+	}
+	for _, p := range pans {
+		imp.pans[strings.ToLower(p.Name)] = p.ID
+	}
+
+	batch := make([]catalogPending, 0, catalogImportBatch)
+	for {
+		if err := ctx.Err(); err != nil {
+			return imp.finish(), err
+		}
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 单条记录解析失败时跳过，读取器本身出错则终止
+			var recErr *catalog.RecordError
+			if !errors.As(err, &recErr) {
+				return imp.finish(), fmt.Errorf("读取第 %d 行失败: %v", reader.Line(), err)
+			}
+			imp.report.Total++
+			imp.report.Invalid++
+			imp.addError(reader.Line(), "", err.Error())
+			continue
+		}
+		imp.report.Total++
+
+		record.URL = strings.TrimSpace(record.URL)
+		record.Title = strings.TrimSpace(record.Title)
+		normalized := NormalizeURL(record.URL)
+		switch {
+		case normalized == "":
+			imp.report.Invalid++
+			imp.addError(reader.Line(), "", "缺少 url")
+			continue
+		case record.Title == "":
+			imp.report.Invalid++
+			imp.addError(reader.Line(), record.URL, "缺少 title")
+			continue
+		}
+
+		batch = append(batch, catalogPending{line: reader.Line(), record: record, normalized: normalized})
+		if len(batch) >= catalogImportBatch {
+			if err := imp.flush(batch); err != nil {
+				return imp.finish(), err
+			}
+			batch = batch[:0]
+			if progress != nil {
+				progress(imp.report.Total, 0)
+			}
+		}
+	}
+	if err := imp.flush(batch); err != nil {
+		return imp.finish(), err
+	}
+	if progress != nil {
+		progress(imp.report.Total, imp.report.Total)
+	}
+	return imp.finish(), nil
+}
+
+// flush 处理一批记录：先用库内已存在的资源确定key分组映射，再创建新资源
+func (imp *catalogImport) flush(batch []catalogPending) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	lookup := make([]string, 0, len(batch)*3)
+	for _, p := range batch {
+		lookup = append(lookup, p.record.URL, p.normalized, p.normalized+"/")
+	}
+	existing, err := imp.s.resourceRepo.BatchFindByURLs(lookup)
+	if err != nil {
+		return fmt.Errorf("查询已存在资源失败: %v", err)
+	}
+	existingByURL := make(map[string]*entity.Resource, len(existing))
+	for i := range existing {
+		existingByURL[NormalizeURL(existing[i].URL)] = &existing[i]
+	}
+
+	// 库内已存在的资源：同组其他记录沿用已有资源的key
+	fresh := make([]catalogPending, 0, len(batch))
+	for _, p := range batch {
+		if imp.seen[p.normalized] {
+			imp.report.Duplicates++
+			continue
+		}
+		imp.seen[p.normalized] = true
+		if res, ok := existingByURL[p.normalized]; ok {
+			imp.report.Duplicates++
+			if p.record.Key != "" && res.Key != "" {
+				if _, mapped := imp.keys[p.record.Key]; !mapped {
+					imp.keys[p.record.Key] = res.Key
+				}
+			}
+			continue
+		}
+		fresh = append(fresh, p)
+	}
+
+	for _, p := range fresh {
+		if err := imp.create(p.record); err != nil {
+			imp.report.Failed++
+			imp.addError(p.line, p.record.URL, err.Error())
+			continue
+		}
+		imp.report.Created++
+	}
+	return nil
+}
+
+// create 创建单个资源及其标签、元数据
+func (imp *catalogImport) create(record *catalog.Record) error {
+	key, err := imp.resolveKey(record.Key)
+	if err != nil {
+		return err
+	}
+	categoryID, err := imp.resolveCategory(record.Category)
+	if err != nil {
+		return err
+	}
+	var tagIDs []uint
+	for _, name := range record.Tags {
+		id, err := imp.resolveTag(name)
+		if err != nil {
+			return err
+		}
+		if id != nil {
+			tagIDs = append(tagIDs, *id)
+		}
+	}
+	if imp.opts.DryRun {
+		return nil
+	}
+
+	resource := &entity.Resource{
+		Title:       record.Title,
+		Description: record.Description,
+		URL:         record.URL,
+		PanID:       imp.resolvePan(record),
+		SaveURL:     record.SaveURL,
+		FileSize:    record.FileSize,
+		CategoryID:  categoryID,
+		ViewCount:   record.ViewCount,
+		IsValid:     record.IsValid,
+		IsPublic:    record.IsPublic,
+		Cover:       record.Cover,
+		Author:      record.Author,
+		Key:         key,
+		CreatedAt:   record.CreatedAt,
+	}
+	if err := imp.s.resourceRepo.Create(resource); err != nil {
+		return fmt.Errorf("创建资源失败: %v", err)
+	}
+	// IsValid/IsPublic 带 default:true，false 值需要单独写入
+	if !record.IsValid || !record.IsPublic {
+		if err := imp.s.resourceRepo.UpdateFields(resource.ID, map[string]interface{}{
+			"is_valid":  record.IsValid,
+			"is_public": record.IsPublic,
+		}); err != nil {
+			return fmt.Errorf("更新资源状态失败: %v", err)
+		}
+	}
+	for _, tagID := range uniqueUints(tagIDs) {
+		if err := imp.s.resourceRepo.CreateResourceTag(&entity.ResourceTag{ResourceID: resource.ID, TagID: tagID}); err != nil {
+			return fmt.Errorf("关联标签失败: %v", err)
+		}
+	}
+	if m := record.Metadata; m != nil && imp.s.metadataRepo != nil {
+		now := imp.s.now()
+		// 导入的元数据已在源实例确定条目，按手动绑定处理，刷新时不重新匹配
+		if err := imp.s.metadataRepo.Upsert(&entity.ResourceMetadata{
+			ResourceID:    resource.ID,
+			Provider:      m.Provider,
+			SubjectID:     m.SubjectID,
+			MediaType:     m.MediaType,
+			Title:         m.Title,
+			OriginalTitle: m.OriginalTitle,
+			Year:          m.Year,
+			Regions:       m.Regions,
+			Genres:        m.Genres,
+			Rating:        m.Rating,
+			RatingCount:   m.RatingCount,
+			PosterURL:     m.PosterURL,
+			Episodes:      m.Episodes,
+			Directors:     m.Directors,
+			Actors:        m.Actors,
+			Summary:       m.Summary,
+			SubjectURL:    m.SubjectURL,
+			Status:        entity.ResourceMetadataManual,
+			FetchedAt:     &now,
+		}); err != nil {
+			return fmt.Errorf("保存元数据失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// resolveKey 确定资源组key：同组沿用已分配的key，源key未被占用时保留，否则重新生成
+func (imp *catalogImport) resolveKey(sourceKey string) (string, error) {
+	if sourceKey != "" {
+		if key, ok := imp.keys[sourceKey]; ok {
+			return key, nil
+		}
+		exists, err := imp.s.resourceRepo.KeyExists(sourceKey)
+		if err != nil {
+			return "", fmt.Errorf("检查资源Key失败: %v", err)
+		}
+		if !exists {
+			imp.keys[sourceKey] = sourceKey
+			return sourceKey, nil
+		}
+		imp.report.KeysRemapped++
+	}
+
+	if imp.opts.DryRun {
+		if sourceKey != "" {
+			imp.keys[sourceKey] = ""
+		}
+		return "", nil
+	}
+	key, err := imp.s.resourceRepo.GenerateUniqueKey()
+	if err != nil {
+		return "", fmt.Errorf("生成资源Key失败: %v", err)
+	}
+	if sourceKey != "" {
+		imp.keys[sourceKey] = key
+	}
+	return key, nil
+}
+
+// resolveCategory 按映射和名称查找分类，不存在时按选项创建
+func (imp *catalogImport) resolveCategory(name string) (*uint, error) {
+	name = mapCatalogName(imp.opts.CategoryMap, name)
+	if name == "" {
+		return nil, nil
+	}
+	if id, ok := imp.categories[name]; ok {
+		return id, nil
+	}
+
+	var id *uint
+	if category, err := imp.s.categoryRepo.FindByName(name); err == nil {
+		id = &category.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询分类失败: %v", err)
+	} else if !imp.opts.CreateMissing {
+		imp.report.UnmappedCategories = append(imp.report.UnmappedCategories, name)
+	} else {
+		created, err := imp.createCategory(name)
+		if err != nil {
+			return nil, err
+		}
+		id = &created
+		imp.report.CreatedCategories = append(imp.report.CreatedCategories, name)
+	}
+	imp.categories[name] = id
+	return id, nil
+}
+
+func (imp *catalogImport) createCategory(name string) (uint, error) {
+	if imp.opts.DryRun {
+		imp.dryRunID++
+		return imp.dryRunID, nil
+	}
+	// 同名分类被软删除时恢复，避免唯一索引冲突
+	if deleted, err := imp.s.categoryRepo.FindByNameIncludingDeleted(name); err == nil {
+		if err := imp.s.categoryRepo.RestoreDeletedCategory(deleted.ID); err != nil {
+			return 0, fmt.Errorf("恢复分类失败: %v", err)
+		}
+		return deleted.ID, nil
+	}
+	category := &entity.Category{Name: name}
+	if err := imp.s.categoryRepo.Create(category); err != nil {
+		return 0, fmt.Errorf("创建分类失败: %v", err)
+	}
+	return category.ID, nil
+}
+
+// resolveTag 按映射和名称查找标签，不存在时按选项创建
+func (imp *catalogImport) resolveTag(name string) (*uint, error) {
+	name = mapCatalogName(imp.opts.TagMap, name)
+	if name == "" {
+		return nil, nil
+	}
+	if id, ok := imp.tags[name]; ok {
+		return id, nil
+	}
+
+	var id *uint
+	if tag, err := imp.s.tagRepo.FindByName(name); err == nil {
+		id = &tag.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询标签失败: %v", err)
+	} else if !imp.opts.CreateMissing {
+		imp.report.UnmappedTags = append(imp.report.UnmappedTags, name)
+	} else {
+		created, err := imp.createTag(name)
+		if err != nil {
+			return nil, err
+		}
+		id = &created
+		imp.report.CreatedTags = append(imp.report.CreatedTags, name)
+	}
+	imp.tags[name] = id
+	return id, nil
+}
+
+func (imp *catalogImport) createTag(name string) (uint, error) {
+	if imp.opts.DryRun {
+		imp.dryRunID++
+		return imp.dryRunID, nil
+	}
+	if deleted, err := imp.s.tagRepo.FindByNameIncludingDeleted(name); err == nil {
+		if err := imp.s.tagRepo.RestoreDeletedTag(deleted.ID); err != nil {
+			return 0, fmt.Errorf("恢复标签失败: %v", err)
+		}
+		return deleted.ID, nil
+	}
+	tag := &entity.Tag{Name: name}
+	if err := imp.s.tagRepo.Create(tag); err != nil {
+		return 0, fmt.Errorf("创建标签失败: %v", err)
+	}
+	return tag.ID, nil
+}
+
+// resolvePan 按网盘名称匹配，名称缺失或未知时根据链接识别
+func (imp *catalogImport) resolvePan(record *catalog.Record) *uint {
+	if id, ok := imp.pans[strings.ToLower(strings.TrimSpace(record.Pan))]; ok {
+		return &id
+	}
+	if id, ok := imp.pans[panutils.ExtractServiceType(record.URL).String()]; ok {
+		return &id
+	}
+	return nil
+}
+
+func (imp *catalogImport) addError(line int, url, msg string) {
+	if len(imp.report.Errors) >= catalogMaxErrors {
+		imp.report.ErrorsTruncated = true
+		return
+	}
+	imp.report.Errors = append(imp.report.Errors, CatalogImportError{Line: line, URL: url, Error: msg})
+}
+
+// finish 整理报告，列表字段保证非 nil 并排序
+func (imp *catalogImport) finish() *CatalogImportReport {
+	r := imp.report
+	for _, list := range []*[]string{&r.CreatedCategories, &r.CreatedTags, &r.UnmappedCategories, &r.UnmappedTags} {
+		*list = append([]string{}, *list...)
+		sort.Strings(*list)
+	}
+	if r.Errors == nil {
+		r.Errors = []CatalogImportError{}
+	}
+	return r
+}
+
+// mapCatalogName 应用名称映射，映射中存在但目标为空表示丢弃
+func mapCatalogName(mapping map[string]string, name string) string {
+	name = strings.TrimSpace(name)
+	if target, ok := mapping[name]; ok {
+		return strings.TrimSpace(target)
+	}
+	return name
+}
+
+func uniqueUints(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/catalog"
+	"gorm.io/gorm"
+)
+
+type fakeCatalogResourceRepo struct {
+	repo.ResourceRepository
+	existing   []entity.Resource
+	takenKeys  map[string]bool
+	created    []*entity.Resource
+	updated    map[uint]map[string]interface{}
+	tags       map[uint][]uint
+	lastParams map[string]interface{}
+}
+
+func (f *fakeCatalogResourceRepo) SearchWithFilters(params map[string]interface{}) ([]entity.Resource, int64, error) {
+	f.lastParams = params
+	if params["page"].(int) > 1 {
+		return nil, int64(len(f.existing)), nil
+	}
+	return f.existing, int64(len(f.existing)), nil
+}
+
+func (f *fakeCatalogResourceRepo) BatchFindByURLs(urls []string) ([]entity.Resource, error) {
+	var found []entity.Resource
+	for _, r := range f.existing {
+		for _, u := range urls {
+			if r.URL == u {
+				found = append(found, r)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeCatalogResourceRepo) KeyExists(key string) (bool, error) {
+	return f.takenKeys[key], nil
+}
+
+func (f *fakeCatalogResourceRepo) GenerateUniqueKey() (string, error) {
+	return "GEN00" + string(rune('1'+len(f.created))), nil
+}
+
+func (f *fakeCatalogResourceRepo) Create(r *entity.Resource) error {
+	r.ID = uint(100 + len(f.created))
+	f.created = append(f.created, r)
+	return nil
+}
+
+func (f *fakeCatalogResourceRepo) UpdateFields(id uint, fields map[string]interface{}) error {
+	if f.updated == nil {
+		f.updated = map[uint]map[string]interface{}{}
+	}
+	f.updated[id] = fields
+	return nil
+}
+
+func (f *fakeCatalogResourceRepo) CreateResourceTag(rt *entity.ResourceTag) error {
+	if f.tags == nil {
+		f.tags = map[uint][]uint{}
+	}
+	f.tags[rt.ResourceID] = append(f.tags[rt.ResourceID], rt.TagID)
+	return nil
+}
+
+type fakeCatalogCategoryRepo struct {
+	repo.CategoryRepository
+	byName map[string]uint
+}
+
+func (f *fakeCatalogCategoryRepo) FindByName(name string) (*entity.Category, error) {
+	if id, ok := f.byName[name]; ok {
+		return &entity.Category{ID: id, Name: name}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeCatalogTagRepo struct {
+	repo.TagRepository
+	byName  map[string]uint
+	created []string
+}
+
+func (f *fakeCatalogTagRepo) FindByName(name string) (*entity.Tag, error) {
+	if id, ok := f.byName[name]; ok {
+		return &entity.Tag{ID: id, Name: name}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCatalogTagRepo) FindByNameIncludingDeleted(name string) (*entity.Tag, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCatalogTagRepo) Create(tag *entity.Tag) error {
+	tag.ID = uint(50 + len(f.created))
+	f.created = append(f.created, tag.Name)
+	f.byName[tag.Name] = tag.ID
+	return nil
+}
+
+type fakeCatalogPanRepo struct {
+	repo.PanRepository
+}
+
+func (f *fakeCatalogPanRepo) FindAll() ([]entity.Pan, error) {
+	return []entity.Pan{{ID: 1, Name: "quark"}, {ID: 2, Name: "baidu"}}, nil
+}
+
+type fakeCatalogMetadataRepo struct {
+	repo.ResourceMetadataRepository
+	list     []entity.ResourceMetadata
+	upserted []*entity.ResourceMetadata
+}
+
+func (f *fakeCatalogMetadataRepo) FindByResourceIDs(ids []uint) ([]entity.ResourceMetadata, error) {
+	return f.list, nil
+}
+
+func (f *fakeCatalogMetadataRepo) Upsert(m *entity.ResourceMetadata) error {
+	f.upserted = append(f.upserted, m)
+	return nil
+}
+
+func newTestCatalogService() (*CatalogService, *fakeCatalogResourceRepo, *fakeCatalogTagRepo, *fakeCatalogMetadataRepo) {
+	resources := &fakeCatalogResourceRepo{
+		existing:  []entity.Resource{{ID: 1, URL: "https://pan.quark.cn/s/exist", Key: "EXIST1"}},
+		takenKeys: map[string]bool{"EXIST1": true, "taken": true},
+	}
+	tags := &fakeCatalogTagRepo{byName: map[string]uint{}}
+	metadata := &fakeCatalogMetadataRepo{}
+	s := NewCatalogService(resources, &fakeCatalogCategoryRepo{byName: map[string]uint{"影视": 5}}, tags, &fakeCatalogPanRepo{}, metadata)
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC) }
+	return s, resources, tags, metadata
+}
+
+const catalogImportInput = `{"key":"g1","url":"https://PAN.quark.cn/s/exist/","title":"已存在"}
+{"key":"g1","url":"https://pan.quark.cn/s/new1","title":"新资源","category":"电影","tags":["科幻","广告"],"metadata":{"provider":"douban","subject_id":"1","year":2020}}
+{"key":"g2","url":"https://pan.quark.cn/s/new1#frag","title":"文件内重复"}
+{"key":"taken","url":"https://pan.baidu.com/s/x","title":"x","is_valid":false,"category":"未知分类"}
+{"url":"","title":"无链接"}
+{"url":
+`
+
+func TestCatalogImport(t *testing.T) {
+	s, resources, tags, metadata := newTestCatalogService()
+	report, err := s.Import(context.Background(), strings.NewReader(catalogImportInput), CatalogImportOptions{
+		Format:        catalog.FormatJSONL,
+		CategoryMap:   map[string]string{"电影": "影视"},
+		TagMap:        map[string]string{"广告": ""},
+		CreateMissing: false,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Total != 6 || report.Created != 2 || report.Duplicates != 2 || report.Invalid != 2 || report.Failed != 0 || report.KeysRemapped != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if !reflect.DeepEqual(report.UnmappedCategories, []string{"未知分类"}) || !reflect.DeepEqual(report.UnmappedTags, []string{"科幻"}) {
+		t.Errorf("unmapped = %v / %v", report.UnmappedCategories, report.UnmappedTags)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Line != 5 || report.Errors[1].Line != 6 {
+		t.Errorf("errors = %+v", report.Errors)
+	}
+
+	if len(resources.created) != 2 {
+		t.Fatalf("created %d resources", len(resources.created))
+	}
+	first, second := resources.created[0], resources.created[1]
+	// 同组的第一条已存在，新资源并入已有的组
+	if first.Key != "EXIST1" || *first.CategoryID != 5 || *first.PanID != 1 {
+		t.Errorf("first = %+v", first)
+	}
+	// 源 key 已被占用时重新生成，网盘按链接识别
+	if second.Key != "GEN002" || second.CategoryID != nil || *second.PanID != 2 {
+		t.Errorf("second = %+v", second)
+	}
+	if fields := resources.updated[second.ID]; fields["is_valid"] != false {
+		t.Errorf("is_valid=false not persisted: %v", resources.updated)
+	}
+	if len(resources.tags) != 0 || len(tags.created) != 0 {
+		t.Errorf("unexpected tags: %v, created %v", resources.tags, tags.created)
+	}
+	if len(metadata.upserted) != 1 || metadata.upserted[0].ResourceID != first.ID || metadata.upserted[0].Status != entity.ResourceMetadataManual {
+		t.Errorf("metadata = %+v", metadata.upserted)
+	}
+}
+
+func TestCatalogImportDryRunCreateMissing(t *testing.T) {
+	s, resources, tags, metadata := newTestCatalogService()
+	report, err := s.Import(context.Background(), strings.NewReader(catalogImportInput), CatalogImportOptions{
+		Format:        catalog.FormatJSONL,
+		CreateMissing: true,
+		DryRun:        true,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || report.Created != 2 || report.Duplicates != 2 || report.KeysRemapped != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if !reflect.DeepEqual(report.CreatedCategories, []string{"未知分类", "电影"}) || !reflect.DeepEqual(report.CreatedTags, []string{"广告", "科幻"}) {
+		t.Errorf("created = %v / %v", report.CreatedCategories, report.CreatedTags)
+	}
+	if len(resources.created) != 0 || len(tags.created) != 0 || len(metadata.upserted) != 0 {
+		t.Error("dry run must not write")
+	}
+}
+
+func TestCatalogExport(t *testing.T) {
+	s, resources, _, metadata := newTestCatalogService()
+	panID := uint(1)
+	resources.existing = []entity.Resource{
+		{ID: 3, Key: "k1", Title: "资源", URL: "https://pan.quark.cn/s/a", PanID: &panID, Pan: entity.Pan{Name: "quark"},
+			Category: entity.Category{Name: "电影"}, Tags: []entity.Tag{{Name: "科幻"}}, IsValid: true, IsPublic: true},
+		// 按标签连接中间表产生的重复行
+		{ID: 3, Key: "k1", Title: "资源", URL: "https://pan.quark.cn/s/a"},
+		{ID: 4, Title: "无元数据", URL: "https://pan.quark.cn/s/b", IsValid: true},
+	}
+	metadata.list = []entity.ResourceMetadata{
+		{ResourceID: 3, Provider: "douban", SubjectID: "1", Status: entity.ResourceMetadataMatched, Year: 2020},
+		{ResourceID: 4, Provider: "douban", Status: entity.ResourceMetadataNotFound},
+	}
+
+	var buf bytes.Buffer
+	valid := true
+	n, err := s.Export(context.Background(), &buf, catalog.FormatJSONL, CatalogFilter{CategoryID: 2, IsValid: &valid}, nil)
+	if err != nil || n != 2 {
+		t.Fatalf("n = %d, err = %v", n, err)
+	}
+	if p := resources.lastParams; p["category_id"] != uint(2) || p["is_valid"] != true || p["order_by"] != "id" || p["page_size"] != catalogPageSize {
+		t.Errorf("params = %v", p)
+	}
+
+	reader, _ := catalog.NewReader(catalog.FormatJSONL, &buf)
+	var records []*catalog.Record
+	for {
+		r, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if records[0].Category != "电影" || records[0].Pan != "quark" || !reflect.DeepEqual(records[0].Tags, []string{"科幻"}) ||
+		records[0].Metadata == nil || records[0].Metadata.Year != 2020 {
+		t.Errorf("record 0 = %+v", records[0])
+	}
+	if records[1].Metadata != nil {
+		t.Errorf("unmatched metadata exported: %+v", records[1].Metadata)
+	}
+}
@@ -0,0 +1,624 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pan "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/classifier"
+	"github.com/ctwj/urldb/utils"
+	"gorm.io/gorm"
+)
+
+// 分类置信度默认阈值：不低于 assign 自动采纳，介于 review 与 assign 之间进入审核队列
+const (
+	DefaultClassifyAssignThreshold = 0.8
+	DefaultClassifyReviewThreshold = 0.3
+)
+
+// 审核记录的产生方式
+const (
+	ClassificationOriginPipeline = "pipeline"
+	ClassificationOriginManual   = "manual"
+	ClassificationOriginBatch    = "batch"
+)
+
+const (
+	classifierModelCategory = "category" // 分类模型名称
+	classifierTrainingLimit = 50000      // 训练样本上限
+	classifierMinSamples    = 10         // 训练所需最少样本数
+	classifierBayesWeight   = 0.9        // 贝叶斯概率折算为置信度的系数，避免单独依赖模型自动采纳
+	classifierBayesMinProb  = 0.5        // 贝叶斯预测概率下限
+	classifierMaxFiles      = 200        // 参与分类的文件名上限
+)
+
+// ClassifyOptions 分类选项
+type ClassifyOptions struct {
+	AssignThreshold float64 `json:"assign_threshold"`
+	ReviewThreshold float64 `json:"review_threshold"`
+	UseBayes        bool    `json:"use_bayes"`
+	Overwrite       bool    `json:"overwrite"`   // 已有分类时是否覆盖
+	FetchFiles      bool    `json:"fetch_files"` // 是否通过网盘账号获取文件列表
+}
+
+// DefaultClassifyOptions 默认分类选项
+func DefaultClassifyOptions() ClassifyOptions {
+	return ClassifyOptions{
+		AssignThreshold: DefaultClassifyAssignThreshold,
+		ReviewThreshold: DefaultClassifyReviewThreshold,
+		UseBayes:        true,
+		FetchFiles:      true,
+	}
+}
+
+// ClassificationDecision 对一条分类结果的处理决定
+type ClassificationDecision struct {
+	Result         *classifier.Result      `json:"result"`
+	Files          []string                `json:"files,omitempty"`
+	CategoryID     *uint                   `json:"category_id"`      // 自动采纳的分类
+	TagIDs         []uint                  `json:"tag_ids"`          // 自动采纳的标签
+	ReviewCategory *classifier.Suggestion  `json:"review_category"`  // 待审核的分类建议
+	ReviewTags     []classifier.Suggestion `json:"review_tags"`      // 待审核的标签建议
+	AppliedRuleIDs []uint                  `json:"applied_rule_ids"` // 被采纳建议涉及的规则
+}
+
+// NeedsReview 是否存在需人工审核的建议
+func (d *ClassificationDecision) NeedsReview() bool {
+	return d != nil && (d.ReviewCategory != nil || len(d.ReviewTags) > 0)
+}
+
+// ClassificationBatchResult 批量分类统计
+type ClassificationBatchResult struct {
+	Processed int `json:"processed"`
+	Assigned  int `json:"assigned"`
+	Queued    int `json:"queued"`
+	Failed    int `json:"failed"`
+}
+
+// ClassificationService 自动分类与打标签服务
+type ClassificationService struct {
+	ruleRepo     repo.ClassificationRuleRepository
+	reviewRepo   repo.ClassificationReviewRepository
+	modelRepo    repo.ClassifierModelRepository
+	resourceRepo repo.ResourceRepository
+	categoryRepo repo.CategoryRepository
+	tagRepo      repo.TagRepository
+	cksRepo      repo.CksRepository
+
+	mu          sync.RWMutex
+	engine      *classifier.Engine
+	bayes       *classifier.NaiveBayes
+	bayesLoaded bool
+}
+
+// NewClassificationService 创建自动分类服务
+func NewClassificationService(
+	ruleRepo repo.ClassificationRuleRepository,
+	reviewRepo repo.ClassificationReviewRepository,
+	modelRepo repo.ClassifierModelRepository,
+	resourceRepo repo.ResourceRepository,
+	categoryRepo repo.CategoryRepository,
+	tagRepo repo.TagRepository,
+	cksRepo repo.CksRepository,
+) *ClassificationService {
+	return &ClassificationService{
+		ruleRepo:     ruleRepo,
+		reviewRepo:   reviewRepo,
+		modelRepo:    modelRepo,
+		resourceRepo: resourceRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		cksRepo:      cksRepo,
+	}
+}
+
+// ruleEngine 获取已编译的规则引擎（规则变更后重建）
+func (s *ClassificationService) ruleEngine() *classifier.Engine {
+	s.mu.RLock()
+	engine := s.engine
+	s.mu.RUnlock()
+	if engine != nil {
+		return engine
+	}
+
+	rules, err := s.ruleRepo.FindEnabled()
+	if err != nil {
+		utils.Error("[Classification] 加载分类规则失败: %v", err)
+		return &classifier.Engine{}
+	}
+	engine, errs := classifier.NewEngine(toClassifierRules(rules))
+	for _, e := range errs {
+		utils.Warn("[Classification] 跳过无效规则: %v", e)
+	}
+
+	s.mu.Lock()
+	s.engine = engine
+	s.mu.Unlock()
+	return engine
+}
+
+// InvalidateRules 规则变更后清空已编译的规则引擎
+func (s *ClassificationService) InvalidateRules() {
+	s.mu.Lock()
+	s.engine = nil
+	s.mu.Unlock()
+}
+
+// model 获取贝叶斯模型，未训练时返回 nil
+func (s *ClassificationService) model() *classifier.NaiveBayes {
+	s.mu.RLock()
+	nb, loaded := s.bayes, s.bayesLoaded
+	s.mu.RUnlock()
+	if loaded {
+		return nb
+	}
+
+	nb = nil
+	if stored, err := s.modelRepo.FindByName(classifierModelCategory); err == nil {
+		nb = classifier.NewNaiveBayes()
+		if err := json.Unmarshal([]byte(stored.Model), nb); err != nil {
+			utils.Error("[Classification] 解析分类模型失败: %v", err)
+			nb = nil
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		utils.Error("[Classification] 读取分类模型失败: %v", err)
+	}
+
+	s.mu.Lock()
+	s.bayes, s.bayesLoaded = nb, true
+	s.mu.Unlock()
+	return nb
+}
+
+// Classify 对文档执行规则匹配，可选叠加贝叶斯预测
+func (s *ClassificationService) Classify(doc classifier.Document, useBayes bool) *classifier.Result {
+	result := s.ruleEngine().Match(doc)
+	if !useBayes {
+		return result
+	}
+
+	predictions := s.model().Predict(classifier.DocumentTokens(doc))
+	if len(predictions) == 0 || predictions[0].Probability < classifierBayesMinProb {
+		return result
+	}
+	categoryID, err := strconv.ParseUint(predictions[0].Label, 10, 64)
+	if err != nil {
+		return result
+	}
+
+	suggestion := classifier.Suggestion{
+		TargetID:   uint(categoryID),
+		Confidence: predictions[0].Probability * classifierBayesWeight,
+		Sources:    []string{classifier.SourceBayes},
+	}
+	if category, err := s.categoryRepo.FindByID(uint(categoryID)); err == nil {
+		suggestion.Name = category.Name
+	} else {
+		// 模型中的分类已被删除
+		return result
+	}
+	result.Categories = classifier.Combine(result.Categories, []classifier.Suggestion{suggestion})
+	return result
+}
+
+// Decide 按阈值决定自动采纳、进入审核或忽略
+func (s *ClassificationService) Decide(result *classifier.Result, opts ClassifyOptions, hasCategory bool, existingTagIDs []uint) *ClassificationDecision {
+	decision := &ClassificationDecision{Result: result}
+
+	if top := result.TopCategory(); top != nil && (!hasCategory || opts.Overwrite) {
+		switch {
+		case top.Confidence >= opts.AssignThreshold:
+			id := top.TargetID
+			decision.CategoryID = &id
+			decision.AppliedRuleIDs = append(decision.AppliedRuleIDs, top.RuleIDs...)
+		case top.Confidence >= opts.ReviewThreshold:
+			copied := *top
+			decision.ReviewCategory = &copied
+		}
+	}
+
+	existing := make(map[uint]bool, len(existingTagIDs))
+	for _, id := range existingTagIDs {
+		existing[id] = true
+	}
+	for _, tag := range result.Tags {
+		if existing[tag.TargetID] {
+			continue
+		}
+		switch {
+		case tag.Confidence >= opts.AssignThreshold:
+			decision.TagIDs = append(decision.TagIDs, tag.TargetID)
+			decision.AppliedRuleIDs = append(decision.AppliedRuleIDs, tag.RuleIDs...)
+		case tag.Confidence >= opts.ReviewThreshold:
+			decision.ReviewTags = append(decision.ReviewTags, tag)
+		}
+	}
+	return decision
+}
+
+// RecordRuleHits 累加被采纳建议涉及的规则命中次数
+func (s *ClassificationService) RecordRuleHits(decision *ClassificationDecision) {
+	if decision == nil || len(decision.AppliedRuleIDs) == 0 {
+		return
+	}
+	if err := s.ruleRepo.IncrementHitCount(decision.AppliedRuleIDs); err != nil {
+		utils.Warn("[Classification] 更新规则命中次数失败: %v", err)
+	}
+}
+
+// EnqueueReview 将低置信度建议加入审核队列；同一资源已有待审核记录时覆盖
+func (s *ClassificationService) EnqueueReview(resource *entity.Resource, decision *ClassificationDecision, origin string) error {
+	if !decision.NeedsReview() {
+		return nil
+	}
+
+	review, err := s.reviewRepo.FindPendingByResourceID(resource.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if review == nil {
+		review = &entity.ClassificationReview{ResourceID: resource.ID, Status: entity.ClassificationReviewPending}
+	}
+
+	review.ResourceTitle = truncateRunes(resource.Title, 255)
+	review.Origin = origin
+	review.CategoryID, review.CategoryName, review.CategoryConfidence = nil, "", 0
+	if c := decision.ReviewCategory; c != nil {
+		id := c.TargetID
+		review.CategoryID = &id
+		review.CategoryName = c.Name
+		review.CategoryConfidence = c.Confidence
+	}
+
+	ids := make([]string, 0, len(decision.ReviewTags))
+	names := make([]string, 0, len(decision.ReviewTags))
+	for _, tag := range decision.ReviewTags {
+		ids = append(ids, strconv.FormatUint(uint64(tag.TargetID), 10))
+		names = append(names, tag.Name)
+	}
+	review.TagIDs = truncateRunes(strings.Join(ids, ","), 500)
+	review.TagNames = truncateRunes(strings.Join(names, ","), 500)
+
+	raw, _ := json.Marshal(decision.Result)
+	review.Suggestions = string(raw)
+
+	if review.ID == 0 {
+		return s.reviewRepo.Create(review)
+	}
+	return s.reviewRepo.Update(review)
+}
+
+// ClassifyResource 对已入库资源分类；apply 为 true 时采纳高置信度建议并将低置信度建议加入审核队列
+func (s *ClassificationService) ClassifyResource(resourceID uint, opts ClassifyOptions, apply bool, origin string) (*ClassificationDecision, error) {
+	resource, err := s.resourceRepo.FindByID(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := classifier.Document{Title: resource.Title, Description: resource.Description}
+	if opts.FetchFiles {
+		doc.Files = s.fetchFileNames(resource)
+	}
+
+	var existingTagIDs []uint
+	if tags, err := s.tagRepo.FindByResourceID(resource.ID); err == nil {
+		for _, tag := range tags {
+			existingTagIDs = append(existingTagIDs, tag.ID)
+		}
+	}
+
+	decision := s.Decide(s.Classify(doc, opts.UseBayes), opts, resource.CategoryID != nil, existingTagIDs)
+	decision.Files = doc.Files
+	if !apply {
+		return decision, nil
+	}
+
+	if err := s.applyToResource(resource.ID, decision.CategoryID, decision.TagIDs); err != nil {
+		return nil, err
+	}
+	s.RecordRuleHits(decision)
+	if err := s.EnqueueReview(resource, decision, origin); err != nil {
+		utils.Error("[Classification] 加入审核队列失败 (资源ID: %d): %v", resource.ID, err)
+	}
+	return decision, nil
+}
+
+// ClassifyUncategorized 批量处理未分类资源
+func (s *ClassificationService) ClassifyUncategorized(limit int, opts ClassifyOptions) (*ClassificationBatchResult, error) {
+	resources, err := s.resourceRepo.FindUncategorized(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ClassificationBatchResult{}
+	for _, resource := range resources {
+		result.Processed++
+		decision, err := s.ClassifyResource(resource.ID, opts, true, ClassificationOriginBatch)
+		if err != nil {
+			result.Failed++
+			utils.Warn("[Classification] 资源分类失败 (ID: %d): %v", resource.ID, err)
+			continue
+		}
+		if decision.CategoryID != nil || len(decision.TagIDs) > 0 {
+			result.Assigned++
+		}
+		if decision.NeedsReview() {
+			result.Queued++
+		}
+	}
+	utils.Info("[Classification] 批量分类完成: 处理=%d 采纳=%d 待审核=%d 失败=%d",
+		result.Processed, result.Assigned, result.Queued, result.Failed)
+	return result, nil
+}
+
+// applyToResource 设置资源分类并追加标签（已有的标签关联保持不变）
+func (s *ClassificationService) applyToResource(resourceID uint, categoryID *uint, tagIDs []uint) error {
+	if categoryID != nil {
+		if err := s.resourceRepo.UpdateFields(resourceID, map[string]interface{}{"category_id": *categoryID}); err != nil {
+			return fmt.Errorf("更新资源分类失败: %v", err)
+		}
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	existing := make(map[uint]bool)
+	if tags, err := s.tagRepo.FindByResourceID(resourceID); err == nil {
+		for _, tag := range tags {
+			existing[tag.ID] = true
+		}
+	}
+	for _, tagID := range tagIDs {
+		if existing[tagID] {
+			continue
+		}
+		existing[tagID] = true
+		if err := s.resourceRepo.CreateResourceTag(&entity.ResourceTag{ResourceID: resourceID, TagID: tagID}); err != nil {
+			return fmt.Errorf("创建资源标签关联失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// ApproveReview 采纳审核建议；categoryID/tagIDs 不为空时以人工指定为准
+func (s *ClassificationService) ApproveReview(id uint, categoryID *uint, tagIDs []uint) (*entity.ClassificationReview, error) {
+	review, err := s.pendingReview(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if categoryID == nil {
+		categoryID = review.CategoryID
+	}
+	if tagIDs == nil {
+		tagIDs = parseUintList(review.TagIDs)
+	}
+	if err := s.applyToResource(review.ResourceID, categoryID, tagIDs); err != nil {
+		return nil, err
+	}
+	return s.finishReview(review, entity.ClassificationReviewApproved)
+}
+
+// RejectReview 驳回审核建议
+func (s *ClassificationService) RejectReview(id uint) (*entity.ClassificationReview, error) {
+	review, err := s.pendingReview(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.finishReview(review, entity.ClassificationReviewRejected)
+}
+
+func (s *ClassificationService) pendingReview(id uint) (*entity.ClassificationReview, error) {
+	review, err := s.reviewRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != entity.ClassificationReviewPending {
+		return nil, fmt.Errorf("该记录已审核")
+	}
+	return review, nil
+}
+
+func (s *ClassificationService) finishReview(review *entity.ClassificationReview, status string) (*entity.ClassificationReview, error) {
+	now := time.Now()
+	review.Status = status
+	review.ReviewedAt = &now
+	if err := s.reviewRepo.Update(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Train 使用已分类资源训练贝叶斯模型并保存
+func (s *ClassificationService) Train() (*entity.ClassifierModel, error) {
+	resources, err := s.resourceRepo.FindForClassifierTraining(classifierTrainingLimit)
+	if err != nil {
+		return nil, fmt.Errorf("获取训练样本失败: %v", err)
+	}
+	if len(resources) < classifierMinSamples {
+		return nil, fmt.Errorf("已分类资源不足 %d 条，无法训练", classifierMinSamples)
+	}
+
+	nb := classifier.NewNaiveBayes()
+	for _, resource := range resources {
+		if resource.CategoryID == nil {
+			continue
+		}
+		doc := classifier.Document{Title: resource.Title, Description: resource.Description}
+		nb.Train(strconv.FormatUint(uint64(*resource.CategoryID), 10), classifier.DocumentTokens(doc))
+	}
+	if len(nb.Labels) < 2 {
+		return nil, fmt.Errorf("至少需要 2 个分类的样本才能训练")
+	}
+	// 样本较多时丢弃只出现一次的特征，控制模型体积
+	minCount := 1
+	if nb.TotalDocs > 1000 {
+		minCount = 2
+	}
+	nb.Finalize(minCount)
+
+	raw, err := json.Marshal(nb)
+	if err != nil {
+		return nil, err
+	}
+	model := &entity.ClassifierModel{
+		Name:      classifierModelCategory,
+		Model:     string(raw),
+		Samples:   nb.TotalDocs,
+		Labels:    len(nb.Labels),
+		VocabSize: nb.VocabSize,
+		TrainedAt: time.Now(),
+	}
+	if err := s.modelRepo.Save(model); err != nil {
+		return nil, fmt.Errorf("保存分类模型失败: %v", err)
+	}
+
+	s.mu.Lock()
+	s.bayes, s.bayesLoaded = nb, true
+	s.mu.Unlock()
+	utils.Info("[Classification] 分类模型训练完成: 样本=%d 分类=%d 特征=%d", model.Samples, model.Labels, model.VocabSize)
+	return model, nil
+}
+
+// ModelInfo 获取当前模型信息，未训练时返回 nil
+func (s *ClassificationService) ModelInfo() (*entity.ClassifierModel, error) {
+	model, err := s.modelRepo.FindByName(classifierModelCategory)
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return model, err
+}
+
+// ValidateRule 校验规则并补全目标名称
+func (s *ClassificationService) ValidateRule(rule *entity.ClassificationRule) error {
+	switch rule.Target {
+	case classifier.TargetCategory:
+		if rule.CategoryID == nil {
+			return fmt.Errorf("分类规则必须指定分类")
+		}
+		if _, err := s.categoryRepo.FindByID(*rule.CategoryID); err != nil {
+			return fmt.Errorf("分类不存在")
+		}
+		rule.TagID = nil
+	case classifier.TargetTag:
+		if rule.TagID == nil {
+			return fmt.Errorf("标签规则必须指定标签")
+		}
+		if _, err := s.tagRepo.FindByID(*rule.TagID); err != nil {
+			return fmt.Errorf("标签不存在")
+		}
+		rule.CategoryID = nil
+	}
+
+	rules := toClassifierRules([]entity.ClassificationRule{*rule})
+	if len(rules) == 0 {
+		return fmt.Errorf("无效的规则目标: %s", rule.Target)
+	}
+	return classifier.ValidateRule(rules[0])
+}
+
+// fetchFileNames 通过转存账号获取资源目录下的文件名，未转存或获取失败时返回 nil
+func (s *ClassificationService) fetchFileNames(resource *entity.Resource) []string {
+	if resource.Fid == "" || resource.CkID == nil {
+		return nil
+	}
+	accounts, err := s.cksRepo.FindByIds([]uint{*resource.CkID})
+	if err != nil || len(accounts) == 0 {
+		return nil
+	}
+	account := accounts[0]
+
+	service, err := pan.NewPanFactory().CreatePanServiceByType(toPanServiceType(account.ServiceType), &pan.PanConfig{Cookie: account.Ck})
+	if err != nil {
+		return nil
+	}
+	if setter, ok := service.(interface {
+		SetCKSRepository(repo.CksRepository, entity.Cks)
+	}); ok {
+		setter.SetCKSRepository(s.cksRepo, *account)
+	}
+
+	result, err := service.GetFiles(resource.Fid)
+	if err != nil || result == nil || !result.Success {
+		utils.Debug("[Classification] 获取文件列表失败 (资源ID: %d)", resource.ID)
+		return nil
+	}
+	return extractFileNames(result.Data)
+}
+
+// extractFileNames 从各网盘 GetFiles 返回的列表中提取文件名
+func extractFileNames(data interface{}) []string {
+	list, ok := data.([]interface{})
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, item := range list {
+		file, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"file_name", "server_filename", "name", "filename"} {
+			if name, ok := file[key].(string); ok && name != "" {
+				names = append(names, name)
+				break
+			}
+		}
+		if len(names) >= classifierMaxFiles {
+			break
+		}
+	}
+	return names
+}
+
+// toClassifierRules 将规则实体转换为规则引擎的规则
+func toClassifierRules(rules []entity.ClassificationRule) []classifier.Rule {
+	result := make([]classifier.Rule, 0, len(rules))
+	for _, rule := range rules {
+		r := classifier.Rule{
+			ID:         rule.ID,
+			Target:     rule.Target,
+			MatchType:  rule.MatchType,
+			Pattern:    rule.Pattern,
+			Confidence: rule.Confidence,
+		}
+		switch {
+		case rule.Target == classifier.TargetCategory && rule.CategoryID != nil:
+			r.TargetID = *rule.CategoryID
+			if rule.Category != nil {
+				r.TargetName = rule.Category.Name
+			}
+		case rule.Target == classifier.TargetTag && rule.TagID != nil:
+			r.TargetID = *rule.TagID
+			if rule.Tag != nil {
+				r.TargetName = rule.Tag.Name
+			}
+		default:
+			continue
+		}
+		for _, f := range strings.Split(rule.Fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				r.Fields = append(r.Fields, f)
+			}
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// parseUintList 解析逗号分隔的ID列表
+func parseUintList(s string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/classifier"
+	"gorm.io/gorm"
+)
+
+type fakeClassificationReviewRepo struct {
+	repo.ClassificationReviewRepository
+	pending *entity.ClassificationReview
+	created []*entity.ClassificationReview
+	updated []*entity.ClassificationReview
+}
+
+func (f *fakeClassificationReviewRepo) FindPendingByResourceID(resourceID uint) (*entity.ClassificationReview, error) {
+	if f.pending != nil && f.pending.ResourceID == resourceID {
+		return f.pending, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeClassificationReviewRepo) Create(review *entity.ClassificationReview) error {
+	review.ID = uint(len(f.created) + 1)
+	f.created = append(f.created, review)
+	return nil
+}
+
+func (f *fakeClassificationReviewRepo) Update(review *entity.ClassificationReview) error {
+	f.updated = append(f.updated, review)
+	return nil
+}
+
+func TestClassificationDecide(t *testing.T) {
+	result := &classifier.Result{
+		Categories: []classifier.Suggestion{{TargetID: 1, Name: "电影", Confidence: 0.9, RuleIDs: []uint{11}}},
+		Tags: []classifier.Suggestion{
+			{TargetID: 5, Name: "4K", Confidence: 0.95, RuleIDs: []uint{12}},
+			{TargetID: 6, Name: "国产", Confidence: 0.5},
+			{TargetID: 7, Name: "冷门", Confidence: 0.1},
+			{TargetID: 8, Name: "已有", Confidence: 0.99},
+		},
+	}
+	opts := DefaultClassifyOptions()
+	s := &ClassificationService{}
+
+	tests := []struct {
+		name         string
+		hasCategory  bool
+		overwrite    bool
+		wantCategory *uint
+		wantReview   bool
+	}{
+		{name: "assign category", wantCategory: uptr(1)},
+		{name: "keep existing category", hasCategory: true},
+		{name: "overwrite existing category", hasCategory: true, overwrite: true, wantCategory: uptr(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := opts
+			o.Overwrite = tt.overwrite
+			d := s.Decide(result, o, tt.hasCategory, []uint{8})
+			if !reflect.DeepEqual(d.CategoryID, tt.wantCategory) {
+				t.Fatalf("CategoryID = %v, want %v", d.CategoryID, tt.wantCategory)
+			}
+			if !reflect.DeepEqual(d.TagIDs, []uint{5}) {
+				t.Fatalf("TagIDs = %v, want [5]", d.TagIDs)
+			}
+			if len(d.ReviewTags) != 1 || d.ReviewTags[0].TargetID != 6 {
+				t.Fatalf("ReviewTags = %+v, want only tag 6", d.ReviewTags)
+			}
+			if !d.NeedsReview() {
+				t.Fatal("expected review for mid-confidence tag")
+			}
+		})
+	}
+
+	// 中等置信度分类进入审核而不是直接采纳
+	mid := &classifier.Result{Categories: []classifier.Suggestion{{TargetID: 2, Name: "剧集", Confidence: 0.5}}}
+	d := s.Decide(mid, opts, false, nil)
+	if d.CategoryID != nil || d.ReviewCategory == nil || d.ReviewCategory.TargetID != 2 {
+		t.Fatalf("mid confidence decision = %+v", d)
+	}
+
+	low := &classifier.Result{Categories: []classifier.Suggestion{{TargetID: 2, Confidence: 0.1}}}
+	if d := s.Decide(low, opts, false, nil); d.NeedsReview() || d.CategoryID != nil {
+		t.Fatalf("low confidence should be ignored: %+v", d)
+	}
+}
+
+func TestClassificationEnqueueReview(t *testing.T) {
+	reviews := &fakeClassificationReviewRepo{}
+	s := &ClassificationService{reviewRepo: reviews}
+	res := &entity.Resource{ID: 3, Title: "某电影 1080P"}
+
+	decision := &ClassificationDecision{
+		Result:         &classifier.Result{},
+		ReviewCategory: &classifier.Suggestion{TargetID: 2, Name: "剧集", Confidence: 0.5},
+		ReviewTags:     []classifier.Suggestion{{TargetID: 6, Name: "国产"}, {TargetID: 9, Name: "高清"}},
+	}
+	if err := s.EnqueueReview(res, decision, ClassificationOriginPipeline); err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews.created) != 1 {
+		t.Fatalf("created = %d, want 1", len(reviews.created))
+	}
+	got := reviews.created[0]
+	if got.Status != entity.ClassificationReviewPending || *got.CategoryID != 2 || got.TagIDs != "6,9" || got.TagNames != "国产,高清" {
+		t.Fatalf("unexpected review: %+v", got)
+	}
+
+	// 同一资源已有待审核记录时更新而不是重复创建
+	reviews.pending = got
+	decision.ReviewCategory = nil
+	if err := s.EnqueueReview(res, decision, ClassificationOriginManual); err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews.created) != 1 || len(reviews.updated) != 1 {
+		t.Fatalf("created=%d updated=%d, want 1/1", len(reviews.created), len(reviews.updated))
+	}
+	if got.CategoryID != nil || got.Origin != ClassificationOriginManual {
+		t.Fatalf("review not refreshed: %+v", got)
+	}
+
+	// 无需审核时不写入
+	if err := s.EnqueueReview(res, &ClassificationDecision{Result: &classifier.Result{}}, ClassificationOriginPipeline); err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews.created) != 1 || len(reviews.updated) != 1 {
+		t.Fatal("expected no writes for decision without review items")
+	}
+}
+
+func TestExtractFileNames(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"file_name": "a.mkv"},
+		map[string]interface{}{"server_filename": "b.mp4"},
+		map[string]interface{}{"size": 1},
+		"bad",
+	}
+	if got := extractFileNames(data); !reflect.DeepEqual(got, []string{"a.mkv", "b.mp4"}) {
+		t.Fatalf("extractFileNames = %v", got)
+	}
+	if got := extractFileNames(map[string]interface{}{}); got != nil {
+		t.Fatalf("extractFileNames(non-list) = %v", got)
+	}
+}
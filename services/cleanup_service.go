@@ -123,6 +123,23 @@ func (s *CleanupService) Run(ctx context.Context) (total, success, failed int, e
 	return total, success, failed, nil
 }
 
+// DeleteTransferredFile 立即删除单个资源转存到网盘账号中的文件并清空转存字段，
+// 文件已不存在视为成功；用于版权申述通过等需要立即下架的场景
+func (s *CleanupService) DeleteTransferredFile(res *entity.Resource) error {
+	if res.Fid == "" {
+		return nil
+	}
+	account, err := s.resolveAccount(res, make(map[uint]*entity.Cks))
+	if err != nil {
+		return err
+	}
+	if err := s.deleteFile(account, res.Fid); err != nil && !isFileNotExist(err) {
+		_ = s.resourceRepo.MarkCleanError(res.ID, truncateMsg(err.Error()), time.Now())
+		return err
+	}
+	return s.resourceRepo.MarkCleaned(res.ID, time.Now())
+}
+
 // resolveAccount 解析资源对应的账号 cookie
 // 通过 ck_id 查询账号，确保使用与转存时同一账号进行删除（防跨账号误删）
 func (s *CleanupService) resolveAccount(res *entity.Resource, cache map[uint]*entity.Cks) (*entity.Cks, error) {
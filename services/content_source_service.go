@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/crawler"
+	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
+	"github.com/ctwj/urldb/utils"
+)
+
+// ContentSourceService 内容源采集服务
+// 按内容源配置抓取条目，经游标与去重过滤后写入待处理资源（ready_resource），
+// 后续由待处理资源调度器统一入库。
+type ContentSourceService struct {
+	sourceRepo   repo.ContentSourceRepository
+	readyRepo    repo.ReadyResourceRepository
+	resourceRepo repo.ResourceRepository
+	crawler      *crawler.Crawler
+}
+
+// ContentSourceRunResult 单次抓取结果
+type ContentSourceRunResult struct {
+	SourceID   uint   `json:"source_id"`
+	SourceName string `json:"source_name"`
+	Fetched    int    `json:"fetched"`    // 抓取到的条目数
+	Skipped    int    `json:"skipped"`    // 早于游标或无网盘链接的条目数
+	Duplicated int    `json:"duplicated"` // 已处理过或链接已存在的条目数
+	Created    int    `json:"created"`    // 新增待处理资源数（按链接计）
+	Cursor     string `json:"cursor"`
+	Duration   string `json:"duration"`
+	Error      string `json:"error,omitempty"`
+}
+
+// contentSourceExtra 写入 ReadyResource.Extra 的来源信息
+type contentSourceExtra struct {
+	ContentSourceID   uint   `json:"content_source_id"`
+	ContentSourceName string `json:"content_source_name"`
+	GUID              string `json:"guid,omitempty"`
+	Link              string `json:"link,omitempty"`
+}
+
+// NewContentSourceService 创建内容源采集服务
+func NewContentSourceService(
+	sourceRepo repo.ContentSourceRepository,
+	readyRepo repo.ReadyResourceRepository,
+	resourceRepo repo.ResourceRepository,
+) *ContentSourceService {
+	c := crawler.NewCrawler(nil)
+	c.SetImportDir(contentSourceImportDir())
+	return &ContentSourceService{
+		sourceRepo:   sourceRepo,
+		readyRepo:    readyRepo,
+		resourceRepo: resourceRepo,
+		crawler:      c,
+	}
+}
+
+// contentSourceImportDir Telegram 导出文件的导入目录，由 CONTENT_SOURCE_IMPORT_DIR 配置
+func contentSourceImportDir() string {
+	if dir := os.Getenv("CONTENT_SOURCE_IMPORT_DIR"); dir != "" {
+		return dir
+	}
+	return "./data/content-source/imports"
+}
+
+// Preview 仅抓取并解析内容源，不做去重与写入，用于配置调试
+func (s *ContentSourceService) Preview(ctx context.Context, source *entity.ContentSource) ([]crawler.Item, error) {
+	cfg, err := crawler.ParseConfig(source.Config)
+	if err != nil {
+		return nil, err
+	}
+	return s.crawler.Fetch(ctx, source.Type, source.URL, cfg)
+}
+
+// RunDue 抓取所有到期的内容源
+func (s *ContentSourceService) RunDue(ctx context.Context) []*ContentSourceRunResult {
+	sources, err := s.sourceRepo.FindDue(time.Now())
+	if err != nil {
+		utils.Error("[ContentSource] 查询到期内容源失败: %v", err)
+		return nil
+	}
+
+	var results []*ContentSourceRunResult
+	for i := range sources {
+		select {
+		case <-ctx.Done():
+			return results
+		default:
+		}
+		result, _ := s.RunSource(ctx, &sources[i])
+		results = append(results, result)
+	}
+	return results
+}
+
+// RunSource 抓取单个内容源并写入待处理资源。
+// 抓取失败时错误会记录到内容源的 last_error，并同时返回。
+func (s *ContentSourceService) RunSource(ctx context.Context, source *entity.ContentSource) (*ContentSourceRunResult, error) {
+	start := time.Now()
+	result := &ContentSourceRunResult{SourceID: source.ID, SourceName: source.Name}
+
+	items, err := s.Preview(ctx, source)
+	if err != nil {
+		result.Error = err.Error()
+		result.Duration = time.Since(start).String()
+		if updErr := s.sourceRepo.UpdateRunResult(source.ID, start, "", 0, 0, err.Error()); updErr != nil {
+			utils.Error("[ContentSource] 记录抓取结果失败: %v", updErr)
+		}
+		utils.Warn("[ContentSource] 内容源 %s(%d) 抓取失败: %v", source.Name, source.ID, err)
+		return result, err
+	}
+	result.Fetched = len(items)
+
+	cursor := parseCursor(source.Cursor)
+	newCursor := cursor
+
+	// 1. 按游标与网盘链接过滤
+	var candidates []crawler.Item
+	for _, item := range items {
+		if item.PublishedAt.After(newCursor) {
+			newCursor = item.PublishedAt
+		}
+		if len(item.URLs) == 0 || (!item.PublishedAt.IsZero() && !cursor.IsZero() && !item.PublishedAt.After(cursor)) {
+			result.Skipped++
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	// 2. 按源内条目哈希去重
+	hashes := make([]string, len(candidates))
+	for i, item := range candidates {
+		hashes[i] = crawler.ItemHash(item)
+	}
+	freshHashes, err := s.sourceRepo.FilterNewItemHashes(source.ID, hashes)
+	if err != nil {
+		return s.finishWithError(result, source, start, fmt.Errorf("查询去重记录失败: %v", err))
+	}
+	fresh := make(map[string]bool, len(freshHashes))
+	for _, h := range freshHashes {
+		fresh[h] = true
+	}
+
+	// 3. 按链接与已有待处理资源、正式资源去重
+	var urls []string
+	for i, item := range candidates {
+		if fresh[hashes[i]] {
+			urls = append(urls, item.URLs...)
+		}
+	}
+	existingURLs, err := s.findExistingURLs(urls)
+	if err != nil {
+		return s.finishWithError(result, source, start, err)
+	}
+
+	// 4. 写入待处理资源并记录已处理条目
+	var processed []entity.ContentSourceItem
+	var created []entity.ReadyResource
+	for i, item := range candidates {
+		if !fresh[hashes[i]] {
+			result.Duplicated++
+			continue
+		}
+		delete(fresh, hashes[i]) // 同一批次内重复的条目只处理一次
+
+		resources, key, err := s.buildReadyResources(source, item, existingURLs)
+		if err != nil {
+			return s.finishWithError(result, source, start, err)
+		}
+		if len(resources) == 0 {
+			result.Duplicated++
+		} else {
+			created = append(created, resources...)
+		}
+		processed = append(processed, entity.ContentSourceItem{
+			SourceID: source.ID,
+			ItemHash: hashes[i],
+			Title:    truncateRunes(item.Title, 255),
+			ReadyKey: key,
+		})
+	}
+
+	if len(created) > 0 {
+		if err := s.readyRepo.BatchCreate(created); err != nil {
+			return s.finishWithError(result, source, start, fmt.Errorf("写入待处理资源失败: %v", err))
+		}
+	}
+	if err := s.sourceRepo.CreateItems(processed); err != nil {
+		utils.Error("[ContentSource] 记录已处理条目失败: %v", err)
+	}
+	result.Created = len(created)
+
+	if !newCursor.IsZero() {
+		result.Cursor = newCursor.Format(time.RFC3339)
+	}
+	result.Duration = time.Since(start).String()
+	if err := s.sourceRepo.UpdateRunResult(source.ID, start, result.Cursor, result.Fetched, result.Created, ""); err != nil {
+		utils.Error("[ContentSource] 记录抓取结果失败: %v", err)
+	}
+
+	for i := range created {
+		plugins.TriggerReadyResourceAdd(&created[i], map[string]interface{}{
+			"source":            source.Type,
+			"content_source_id": source.ID,
+		})
+	}
+
+	utils.Info("[ContentSource] 内容源 %s(%d) 抓取完成: 条目=%d 跳过=%d 重复=%d 新增=%d 耗时=%s",
+		source.Name, source.ID, result.Fetched, result.Skipped, result.Duplicated, result.Created, result.Duration)
+	return result, nil
+}
+
+// ResetCursor 重置游标并清空去重记录，下次抓取将重新处理全部条目（已存在的链接仍会被跳过）
+func (s *ContentSourceService) ResetCursor(sourceID uint) error {
+	if err := s.sourceRepo.DeleteItemsBySourceID(sourceID); err != nil {
+		return err
+	}
+	return s.sourceRepo.GetDB().Model(&entity.ContentSource{}).Where("id = ?", sourceID).
+		Updates(map[string]interface{}{"cursor": "", "last_run_at": nil}).Error
+}
+
+// buildReadyResources 为条目中尚不存在的链接构建待处理资源，同一条目的链接共用一个 key
+func (s *ContentSourceService) buildReadyResources(source *entity.ContentSource, item crawler.Item, existing map[string]bool) ([]entity.ReadyResource, string, error) {
+	var urls []string
+	for _, u := range item.URLs {
+		if !existing[u] {
+			existing[u] = true
+			urls = append(urls, u)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, "", nil
+	}
+
+	key, err := s.readyRepo.GenerateUniqueKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("生成资源key失败: %v", err)
+	}
+
+	extra, _ := json.Marshal(contentSourceExtra{
+		ContentSourceID:   source.ID,
+		ContentSourceName: source.Name,
+		GUID:              item.GUID,
+		Link:              item.Link,
+	})
+
+	category := item.Category
+	if category == "" {
+		category = source.Category
+	}
+	tags := mergeTags(source.Tags, item.Tags)
+
+	var title *string
+	if t := truncateRunes(item.Title, 255); t != "" {
+		title = &t
+	}
+
+	resources := make([]entity.ReadyResource, 0, len(urls))
+	for _, u := range urls {
+		resources = append(resources, entity.ReadyResource{
+			Title:       title,
+			Description: item.Description,
+			URL:         u,
+			Category:    category,
+			Tags:        tags,
+			Img:         truncateRunes(item.Cover, 500),
+			Source:      source.Type,
+			Extra:       string(extra),
+			Key:         key,
+		})
+	}
+	return resources, key, nil
+}
+
+// findExistingURLs 查询已存在于待处理资源或正式资源中的链接
+func (s *ContentSourceService) findExistingURLs(urls []string) (map[string]bool, error) {
+	return findExistingResourceURLs(s.readyRepo, s.resourceRepo, urls)
+}
+
+// findExistingResourceURLs 查询已存在于待处理资源或正式资源中的链接（采集类服务共用）
+func findExistingResourceURLs(readyRepo repo.ReadyResourceRepository, resourceRepo repo.ResourceRepository, urls []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(urls) == 0 {
+		return existing, nil
+	}
+
+	readyList, err := readyRepo.BatchFindByURLs(urls)
+	if err != nil {
+		return nil, fmt.Errorf("查询待处理资源失败: %v", err)
+	}
+	for _, r := range readyList {
+		existing[r.URL] = true
+	}
+
+	resources, err := resourceRepo.BatchFindByURLs(urls)
+	if err != nil {
+		return nil, fmt.Errorf("查询资源失败: %v", err)
+	}
+	for _, r := range resources {
+		existing[r.URL] = true
+	}
+	return existing, nil
+}
+
+func (s *ContentSourceService) finishWithError(result *ContentSourceRunResult, source *entity.ContentSource, start time.Time, err error) (*ContentSourceRunResult, error) {
+	result.Error = err.Error()
+	result.Duration = time.Since(start).String()
+	if updErr := s.sourceRepo.UpdateRunResult(source.ID, start, "", result.Fetched, 0, err.Error()); updErr != nil {
+		utils.Error("[ContentSource] 记录抓取结果失败: %v", updErr)
+	}
+	utils.Error("[ContentSource] 内容源 %s(%d) 处理失败: %v", source.Name, source.ID, err)
+	return result, err
+}
+
+// parseCursor 解析游标（RFC3339 时间），为空或非法时返回零值
+func parseCursor(cursor string) time.Time {
+	if cursor == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, cursor)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// mergeTags 合并默认标签与条目标签（去重，逗号分隔）
+func mergeTags(defaults string, extra []string) string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range append(strings.Split(defaults, ","), extra...) {
+		t = strings.TrimSpace(t)
+		if t != "" && !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return truncateRunes(strings.Join(tags, ","), 500)
+}
+
+// truncateRunes 按字符截断，避免超出字段长度
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+)
+
+// 纯单元测试：本地 fixture 服务器提供 RSS，fake 仓库记录写入，
+// 覆盖游标过滤、源内去重、已有链接去重与默认分类/标签合并。
+
+type fakeContentSourceRepo struct {
+	repo.ContentSourceRepository
+	items  map[string]bool
+	cursor string
+	runErr string
+}
+
+func (f *fakeContentSourceRepo) FilterNewItemHashes(_ uint, hashes []string) ([]string, error) {
+	var out []string
+	for _, h := range hashes {
+		if !f.items[h] {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeContentSourceRepo) CreateItems(items []entity.ContentSourceItem) error {
+	for _, it := range items {
+		f.items[it.ItemHash] = true
+	}
+	return nil
+}
+
+func (f *fakeContentSourceRepo) UpdateRunResult(_ uint, _ time.Time, cursor string, _, _ int, lastError string) error {
+	if cursor != "" {
+		f.cursor = cursor
+	}
+	f.runErr = lastError
+	return nil
+}
+
+type fakeContentReadyRepo struct {
+	repo.ReadyResourceRepository
+	created []entity.ReadyResource
+	keySeq  int
+}
+
+func (f *fakeContentReadyRepo) BatchFindByURLs(urls []string) ([]entity.ReadyResource, error) {
+	var out []entity.ReadyResource
+	for _, r := range f.created {
+		for _, u := range urls {
+			if r.URL == u {
+				out = append(out, r)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeContentReadyRepo) BatchCreate(resources []entity.ReadyResource) error {
+	f.created = append(f.created, resources...)
+	return nil
+}
+
+func (f *fakeContentReadyRepo) GenerateUniqueKey() (string, error) {
+	f.keySeq++
+	return string(rune('a' + f.keySeq)), nil
+}
+
+type fakeContentResourceRepo struct {
+	repo.ResourceRepository
+	urls []string
+}
+
+func (f *fakeContentResourceRepo) BatchFindByURLs(urls []string) ([]entity.Resource, error) {
+	var out []entity.Resource
+	for _, existing := range f.urls {
+		for _, u := range urls {
+			if existing == u {
+				out = append(out, entity.Resource{URL: u})
+			}
+		}
+	}
+	return out, nil
+}
+
+const contentSourceRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+<item><title>旧帖</title><guid>g1</guid><pubDate>Mon, 01 Jan 2024 00:00:00 +0000</pubDate>
+  <description>https://pan.quark.cn/s/old</description></item>
+<item><title>新帖</title><guid>g2</guid><pubDate>Wed, 03 Jan 2024 00:00:00 +0000</pubDate><category>科幻</category>
+  <description>https://pan.quark.cn/s/new https://pan.baidu.com/s/1new</description></item>
+<item><title>已入库</title><guid>g3</guid><pubDate>Wed, 03 Jan 2024 01:00:00 +0000</pubDate>
+  <description>https://pan.quark.cn/s/exists</description></item>
+<item><title>无链接</title><guid>g4</guid><pubDate>Wed, 03 Jan 2024 02:00:00 +0000</pubDate>
+  <description>nothing</description></item>
+</channel></rss>`
+
+func TestContentSourceService_RunSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(contentSourceRSS))
+	}))
+	defer srv.Close()
+
+	sourceRepo := &fakeContentSourceRepo{items: map[string]bool{}}
+	readyRepo := &fakeContentReadyRepo{}
+	resourceRepo := &fakeContentResourceRepo{urls: []string{"https://pan.quark.cn/s/exists"}}
+	svc := NewContentSourceService(sourceRepo, readyRepo, resourceRepo)
+
+	source := &entity.ContentSource{
+		ID:       7,
+		Name:     "测试订阅",
+		Type:     entity.SourceRSS,
+		URL:      srv.URL,
+		Category: "电影",
+		Tags:     "订阅, 科幻",
+		Cursor:   "2024-01-02T00:00:00Z",
+	}
+
+	result, err := svc.RunSource(context.Background(), source)
+	if err != nil {
+		t.Fatalf("RunSource: %v", err)
+	}
+	if result.Fetched != 4 || result.Skipped != 2 || result.Duplicated != 1 || result.Created != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if sourceRepo.cursor != "2024-01-03T02:00:00Z" {
+		t.Fatalf("cursor = %q", sourceRepo.cursor)
+	}
+
+	if len(readyRepo.created) != 2 {
+		t.Fatalf("created %d ready resources, want 2", len(readyRepo.created))
+	}
+	for _, r := range readyRepo.created {
+		if r.Source != entity.SourceRSS || r.Key != readyRepo.created[0].Key {
+			t.Fatalf("source/key = %q %q", r.Source, r.Key)
+		}
+		if r.Title == nil || *r.Title != "新帖" || r.Category != "科幻" || r.Tags != "订阅,科幻" {
+			t.Fatalf("unexpected ready resource: title=%v category=%q tags=%q", r.Title, r.Category, r.Tags)
+		}
+	}
+
+	// 再次抓取：游标与去重记录使全部条目被跳过
+	source.Cursor = sourceRepo.cursor
+	result, err = svc.RunSource(context.Background(), source)
+	if err != nil {
+		t.Fatalf("second RunSource: %v", err)
+	}
+	if result.Created != 0 || len(readyRepo.created) != 2 {
+		t.Fatalf("second run should create nothing: %+v", result)
+	}
+}
+
+func TestContentSourceService_RunSourceFetchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	sourceRepo := &fakeContentSourceRepo{items: map[string]bool{}}
+	svc := NewContentSourceService(sourceRepo, &fakeContentReadyRepo{}, &fakeContentResourceRepo{})
+
+	result, err := svc.RunSource(context.Background(), &entity.ContentSource{ID: 1, Type: entity.SourceRSS, URL: srv.URL})
+	if err == nil || result.Error == "" || sourceRepo.runErr == "" {
+		t.Fatalf("expected fetch error to be recorded, got err=%v result=%+v", err, result)
+	}
+}
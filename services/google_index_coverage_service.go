@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/google"
+	"github.com/ctwj/urldb/utils"
+)
+
+// googleIndexReconcileLimit 每轮最多检查的 URL 数（仍受每日配额限制）
+const googleIndexReconcileLimit = 500
+
+// GoogleIndexClient 覆盖率对账用到的 Google 接口，由 pkg/google.Client 实现
+type GoogleIndexClient interface {
+	InspectURL(url string) (*google.URLInspectionResult, error)
+	PublishURL(url string, urlType string) error
+}
+
+// GoogleIndexCoverageConfig 索引覆盖率对账配置
+type GoogleIndexCoverageConfig struct {
+	Enabled       bool   `json:"enabled"`
+	SiteURL       string `json:"site_url"`
+	DailyQuota    int    `json:"daily_quota"`
+	StaleDays     int    `json:"stale_days"`
+	AutoResubmit  bool   `json:"auto_resubmit"`
+	ResubmitLimit int    `json:"resubmit_limit"`
+}
+
+// GoogleIndexReconcileResult 一轮对账的结果
+type GoogleIndexReconcileResult struct {
+	Synced         int64  `json:"synced"`
+	Inspected      int    `json:"inspected"`
+	Indexed        int    `json:"indexed"`
+	Failed         int    `json:"failed"`
+	Resubmitted    int    `json:"resubmitted"`
+	RateLimited    bool   `json:"rate_limited"`
+	QuotaRemaining int    `json:"quota_remaining"`
+	Error          string `json:"error,omitempty"`
+}
+
+// GoogleIndexCoverageReport 覆盖率报表
+type GoogleIndexCoverageReport struct {
+	GroupBy  string                        `json:"group_by"`
+	Summary  repo.GoogleIndexCoverageRow   `json:"summary"`
+	Rows     []repo.GoogleIndexCoverageRow `json:"rows"`
+	States   []repo.GoogleIndexStateCount  `json:"states"`
+	QuotaUse int64                         `json:"quota_used_today"`
+}
+
+// GoogleIndexCoverageService Google 索引覆盖率服务：
+// 为每个可收录资源维护索引状态，按配额优先检查从未检查和过期的 URL，
+// 并在“已抓取未索引”的页面更新后自动重新提交。
+type GoogleIndexCoverageService struct {
+	repo       repo.GoogleIndexStatusRepository
+	configRepo repo.SystemConfigRepository
+
+	// 以下字段便于测试替换
+	newClient func(cfg GoogleIndexCoverageConfig) (GoogleIndexClient, error)
+	now       func() time.Time
+
+	reconcileMutex sync.Mutex
+}
+
+// NewGoogleIndexCoverageService 创建 Google 索引覆盖率服务
+func NewGoogleIndexCoverageService(statusRepo repo.GoogleIndexStatusRepository, configRepo repo.SystemConfigRepository) *GoogleIndexCoverageService {
+	s := &GoogleIndexCoverageService{
+		repo:       statusRepo,
+		configRepo: configRepo,
+		now:        utils.GetCurrentTime,
+	}
+	s.newClient = s.defaultClient
+	return s
+}
+
+// Config 读取配置，缺失或非法的项使用默认值
+func (s *GoogleIndexCoverageService) Config() GoogleIndexCoverageConfig {
+	cfg := GoogleIndexCoverageConfig{
+		DailyQuota:    entity.GoogleIndexConfigDefaultInspectQuota,
+		StaleDays:     entity.GoogleIndexConfigDefaultStaleDays,
+		ResubmitLimit: entity.GoogleIndexConfigDefaultResubmitLimit,
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+
+	cfg.Enabled, _ = s.configRepo.GetConfigBool(entity.GoogleIndexConfigKeyEnabled)
+	cfg.AutoResubmit, _ = s.configRepo.GetConfigBool(entity.GoogleIndexConfigKeyAutoResubmit)
+	if v, err := s.configRepo.GetConfigValue(entity.ConfigKeyWebsiteURL); err == nil {
+		cfg.SiteURL = strings.TrimRight(strings.TrimSpace(v), "/")
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.GoogleIndexConfigKeyInspectQuota); err == nil && v >= 0 {
+		cfg.DailyQuota = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.GoogleIndexConfigKeyStaleDays); err == nil && v > 0 {
+		cfg.StaleDays = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.GoogleIndexConfigKeyResubmitLimit); err == nil && v >= 0 {
+		cfg.ResubmitLimit = v
+	}
+	return cfg
+}
+
+// defaultClient 按配置创建 Google 客户端
+func (s *GoogleIndexCoverageService) defaultClient(cfg GoogleIndexCoverageConfig) (GoogleIndexClient, error) {
+	credentialsFile, err := s.configRepo.GetConfigValue(entity.GoogleIndexConfigKeyCredentialsFile)
+	if err != nil || credentialsFile == "" {
+		return nil, fmt.Errorf("未配置Google凭据文件")
+	}
+	client, err := google.NewClient(&google.Config{CredentialsFile: credentialsFile, SiteURL: cfg.SiteURL})
+	if err != nil {
+		return nil, fmt.Errorf("创建Google客户端失败: %v", err)
+	}
+	return client, nil
+}
+
+// Reconcile 同步资源、按配额检查索引状态并自动重新提交
+func (s *GoogleIndexCoverageService) Reconcile(ctx context.Context) (*GoogleIndexReconcileResult, error) {
+	if !s.reconcileMutex.TryLock() {
+		return nil, fmt.Errorf("索引覆盖率对账正在进行中")
+	}
+	defer s.reconcileMutex.Unlock()
+
+	cfg := s.Config()
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("Google索引功能未启用")
+	}
+	if cfg.SiteURL == "" {
+		return nil, fmt.Errorf("未配置网站地址")
+	}
+
+	result := &GoogleIndexReconcileResult{}
+	synced, err := s.repo.SyncResources(cfg.SiteURL)
+	if err != nil {
+		return nil, fmt.Errorf("同步资源失败: %v", err)
+	}
+	result.Synced = synced
+
+	now := s.now()
+	used, err := s.repo.CountInspectedSince(startOfDay(now))
+	if err != nil {
+		return nil, fmt.Errorf("统计今日检查数失败: %v", err)
+	}
+	remaining := cfg.DailyQuota - int(used)
+	if remaining < 0 {
+		remaining = 0
+	}
+	result.QuotaRemaining = remaining
+
+	var client GoogleIndexClient
+	if remaining > 0 || cfg.AutoResubmit {
+		client, err = s.newClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if remaining > 0 {
+		limit := remaining
+		if limit > googleIndexReconcileLimit {
+			limit = googleIndexReconcileLimit
+		}
+		staleBefore := now.AddDate(0, 0, -cfg.StaleDays)
+		if err := s.inspect(ctx, client, staleBefore, limit, result); err != nil {
+			return result, err
+		}
+	}
+
+	if cfg.AutoResubmit && cfg.ResubmitLimit > 0 && !result.RateLimited {
+		candidates, err := s.repo.FindResubmitCandidates(cfg.ResubmitLimit)
+		if err != nil {
+			return result, fmt.Errorf("查询待重新提交的URL失败: %v", err)
+		}
+		n, err := s.publish(ctx, client, candidates)
+		result.Resubmitted = n
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	utils.Info("Google索引覆盖率对账完成: 新增=%d 检查=%d 已索引=%d 失败=%d 重新提交=%d 剩余配额=%d",
+		result.Synced, result.Inspected, result.Indexed, result.Failed, result.Resubmitted, result.QuotaRemaining)
+	return result, nil
+}
+
+// inspect 依次检查待检查的 URL，遇到速率限制时停止
+func (s *GoogleIndexCoverageService) inspect(ctx context.Context, client GoogleIndexClient, staleBefore time.Time, limit int, result *GoogleIndexReconcileResult) error {
+	statuses, err := s.repo.FindForInspection(staleBefore, limit)
+	if err != nil {
+		return fmt.Errorf("查询待检查的URL失败: %v", err)
+	}
+
+	seen := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		if seen[status.URL] {
+			continue
+		}
+		seen[status.URL] = true
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		inspection := entity.GoogleIndexInspection{InspectedAt: s.now()}
+		res, err := client.InspectURL(status.URL)
+		if err != nil {
+			if isGoogleRateLimitError(err) {
+				result.RateLimited = true
+				result.Error = err.Error()
+				break
+			}
+			inspection.ErrorMessage = truncateRunes(err.Error(), 500)
+			result.Failed++
+		} else {
+			inspection.Verdict = res.IndexStatusResult.Verdict
+			inspection.CoverageState = res.IndexStatusResult.CoverageState
+			inspection.IndexingState = res.IndexStatusResult.IndexingState
+			inspection.PageFetchState = res.IndexStatusResult.PageFetchState
+			if t, err := time.Parse(time.RFC3339, res.IndexStatusResult.LastCrawled); err == nil {
+				inspection.LastCrawledAt = &t
+			}
+			if inspection.Verdict == entity.GoogleIndexVerdictPass {
+				result.Indexed++
+			}
+		}
+
+		if err := s.repo.RecordInspection(status.URL, inspection); err != nil {
+			utils.Error("保存URL索引状态失败: %s, %v", status.URL, err)
+		}
+		result.Inspected++
+		result.QuotaRemaining--
+	}
+	return nil
+}
+
+// publish 通过 Indexing API 重新提交 URL，返回成功数量
+func (s *GoogleIndexCoverageService) publish(ctx context.Context, client GoogleIndexClient, statuses []entity.GoogleIndexStatus) (int, error) {
+	published := 0
+	seen := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		if seen[status.URL] {
+			continue
+		}
+		seen[status.URL] = true
+		if err := ctx.Err(); err != nil {
+			return published, err
+		}
+		if err := client.PublishURL(status.URL, "URL_UPDATED"); err != nil {
+			if isGoogleRateLimitError(err) {
+				return published, err
+			}
+			utils.Warn("重新提交URL失败: %s, %v", status.URL, err)
+			continue
+		}
+		if err := s.repo.MarkResubmitted(status.URL, s.now()); err != nil {
+			utils.Error("记录URL重新提交失败: %s, %v", status.URL, err)
+		}
+		published++
+	}
+	return published, nil
+}
+
+// Resubmit 手动重新提交指定资源的详情页
+func (s *GoogleIndexCoverageService) Resubmit(ctx context.Context, resourceIDs []uint) (int, error) {
+	cfg := s.Config()
+	if !cfg.Enabled {
+		return 0, fmt.Errorf("Google索引功能未启用")
+	}
+	statuses, err := s.repo.FindByResourceIDs(resourceIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(statuses) == 0 {
+		return 0, fmt.Errorf("所选资源尚未同步索引状态")
+	}
+	client, err := s.newClient(cfg)
+	if err != nil {
+		return 0, err
+	}
+	return s.publish(ctx, client, statuses)
+}
+
+// Coverage 按维度返回覆盖率，days > 0 时只统计最近 days 天创建的资源
+func (s *GoogleIndexCoverageService) Coverage(groupBy string, days int) (*GoogleIndexCoverageReport, error) {
+	switch groupBy {
+	case "", "category", "pan", "date":
+	default:
+		return nil, fmt.Errorf("不支持的分组方式: %s", groupBy)
+	}
+
+	var since *time.Time
+	if days > 0 {
+		t := startOfDay(s.now()).AddDate(0, 0, -days+1)
+		since = &t
+	}
+
+	report := &GoogleIndexCoverageReport{GroupBy: groupBy}
+	summary, err := s.repo.Coverage("", since)
+	if err != nil {
+		return nil, err
+	}
+	if len(summary) > 0 {
+		report.Summary = summary[0]
+	}
+	if groupBy != "" {
+		if report.Rows, err = s.repo.Coverage(groupBy, since); err != nil {
+			return nil, err
+		}
+	}
+	if report.States, err = s.repo.CountByCoverageState(); err != nil {
+		return nil, err
+	}
+	if report.QuotaUse, err = s.repo.CountInspectedSince(startOfDay(s.now())); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// List 分页查询索引状态
+func (s *GoogleIndexCoverageService) List(verdict, coverageState, keyword string, page, pageSize int) ([]entity.GoogleIndexStatus, int64, error) {
+	return s.repo.FindWithFilters(verdict, coverageState, keyword, page, pageSize)
+}
+
+// isGoogleRateLimitError 是否为 Google API 的速率或配额限制错误
+func isGoogleRateLimitError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "过于频繁") || strings.Contains(msg, "429") || strings.Contains(msg, "配额")
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/google"
+)
+
+// fakeGoogleIndexStatusRepo 内存中的索引状态表，待检查与待重新提交的记录由测试直接给出
+type fakeGoogleIndexStatusRepo struct {
+	repo.GoogleIndexStatusRepository
+	pending     []entity.GoogleIndexStatus
+	candidates  []entity.GoogleIndexStatus
+	usedToday   int64
+	limit       int
+	recorded    map[string]entity.GoogleIndexInspection
+	resubmitted []string
+}
+
+func (f *fakeGoogleIndexStatusRepo) SyncResources(string) (int64, error) { return 2, nil }
+
+func (f *fakeGoogleIndexStatusRepo) CountInspectedSince(time.Time) (int64, error) {
+	return f.usedToday, nil
+}
+
+func (f *fakeGoogleIndexStatusRepo) FindForInspection(_ time.Time, limit int) ([]entity.GoogleIndexStatus, error) {
+	f.limit = limit
+	if limit < len(f.pending) {
+		return f.pending[:limit], nil
+	}
+	return f.pending, nil
+}
+
+func (f *fakeGoogleIndexStatusRepo) RecordInspection(url string, inspection entity.GoogleIndexInspection) error {
+	if f.recorded == nil {
+		f.recorded = make(map[string]entity.GoogleIndexInspection)
+	}
+	f.recorded[url] = inspection
+	return nil
+}
+
+func (f *fakeGoogleIndexStatusRepo) FindResubmitCandidates(int) ([]entity.GoogleIndexStatus, error) {
+	return f.candidates, nil
+}
+
+func (f *fakeGoogleIndexStatusRepo) MarkResubmitted(url string, _ time.Time) error {
+	f.resubmitted = append(f.resubmitted, url)
+	return nil
+}
+
+// fakeGoogleIndexClient 按 URL 返回预设的检查结果
+type fakeGoogleIndexClient struct {
+	results   map[string]*google.URLInspectionResult
+	errs      map[string]error
+	inspected []string
+	published []string
+}
+
+func (f *fakeGoogleIndexClient) InspectURL(url string) (*google.URLInspectionResult, error) {
+	f.inspected = append(f.inspected, url)
+	if err := f.errs[url]; err != nil {
+		return nil, err
+	}
+	return f.results[url], nil
+}
+
+func (f *fakeGoogleIndexClient) PublishURL(url string, _ string) error {
+	f.published = append(f.published, url)
+	return nil
+}
+
+func inspectionResult(verdict, coverage, lastCrawled string) *google.URLInspectionResult {
+	r := &google.URLInspectionResult{}
+	r.IndexStatusResult.Verdict = verdict
+	r.IndexStatusResult.CoverageState = coverage
+	r.IndexStatusResult.LastCrawled = lastCrawled
+	return r
+}
+
+func newTestCoverageService(values map[string]string, statusRepo *fakeGoogleIndexStatusRepo, client *fakeGoogleIndexClient) *GoogleIndexCoverageService {
+	base := map[string]string{
+		entity.GoogleIndexConfigKeyEnabled: "true",
+		entity.ConfigKeyWebsiteURL:         "https://pan.example.com/",
+	}
+	for k, v := range values {
+		base[k] = v
+	}
+	s := NewGoogleIndexCoverageService(statusRepo, &fakeSearchEngineConfigRepo{values: base})
+	s.newClient = func(GoogleIndexCoverageConfig) (GoogleIndexClient, error) { return client, nil }
+	s.now = func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) }
+	return s
+}
+
+func TestGoogleIndexReconcileRespectsQuota(t *testing.T) {
+	statusRepo := &fakeGoogleIndexStatusRepo{
+		usedToday: 8,
+		pending: []entity.GoogleIndexStatus{
+			{ResourceID: 1, URL: "https://pan.example.com/r/a"},
+			{ResourceID: 2, URL: "https://pan.example.com/r/a"},
+			{ResourceID: 3, URL: "https://pan.example.com/r/b"},
+			{ResourceID: 4, URL: "https://pan.example.com/r/c"},
+		},
+	}
+	client := &fakeGoogleIndexClient{
+		results: map[string]*google.URLInspectionResult{
+			"https://pan.example.com/r/a": inspectionResult(entity.GoogleIndexVerdictPass, "Submitted and indexed", "2026-02-20T08:00:00Z"),
+			"https://pan.example.com/r/b": inspectionResult("NEUTRAL", entity.GoogleIndexCoverageCrawledNotIndexed, ""),
+		},
+	}
+	s := newTestCoverageService(map[string]string{entity.GoogleIndexConfigKeyInspectQuota: "10"}, statusRepo, client)
+
+	result, err := s.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusRepo.limit != 2 {
+		t.Errorf("limit = %d, want remaining quota 2", statusRepo.limit)
+	}
+	// 同一 URL 只检查一次
+	if len(client.inspected) != 1 || result.Inspected != 1 || result.Indexed != 1 || result.QuotaRemaining != 1 {
+		t.Fatalf("inspected=%v result=%+v", client.inspected, result)
+	}
+	got := statusRepo.recorded["https://pan.example.com/r/a"]
+	if got.Verdict != entity.GoogleIndexVerdictPass || got.LastCrawledAt == nil || got.LastCrawledAt.Day() != 20 {
+		t.Errorf("recorded = %+v", got)
+	}
+}
+
+func TestGoogleIndexReconcileStopsOnRateLimit(t *testing.T) {
+	statusRepo := &fakeGoogleIndexStatusRepo{
+		pending: []entity.GoogleIndexStatus{
+			{ResourceID: 1, URL: "https://pan.example.com/r/a"},
+			{ResourceID: 2, URL: "https://pan.example.com/r/b"},
+			{ResourceID: 3, URL: "https://pan.example.com/r/c"},
+		},
+		candidates: []entity.GoogleIndexStatus{{ResourceID: 9, URL: "https://pan.example.com/r/z"}},
+	}
+	client := &fakeGoogleIndexClient{
+		errs: map[string]error{
+			"https://pan.example.com/r/a": errors.New("页面不存在"),
+			"https://pan.example.com/r/b": errors.New("请求过于频繁，请稍后重试"),
+		},
+	}
+	s := newTestCoverageService(map[string]string{entity.GoogleIndexConfigKeyAutoResubmit: "true"}, statusRepo, client)
+
+	result, err := s.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.RateLimited || result.Inspected != 1 || result.Failed != 1 || len(client.inspected) != 2 {
+		t.Fatalf("inspected=%v result=%+v", client.inspected, result)
+	}
+	if statusRepo.recorded["https://pan.example.com/r/a"].ErrorMessage == "" {
+		t.Error("inspection error should be recorded")
+	}
+	if _, ok := statusRepo.recorded["https://pan.example.com/r/b"]; ok {
+		t.Error("rate limited URL should not be marked as inspected")
+	}
+	if len(client.published) != 0 {
+		t.Errorf("should not resubmit after rate limit, published=%v", client.published)
+	}
+}
+
+func TestGoogleIndexReconcileResubmitsChangedPages(t *testing.T) {
+	statusRepo := &fakeGoogleIndexStatusRepo{
+		usedToday: 100,
+		candidates: []entity.GoogleIndexStatus{
+			{ResourceID: 1, URL: "https://pan.example.com/r/a"},
+			{ResourceID: 2, URL: "https://pan.example.com/r/a"},
+			{ResourceID: 3, URL: "https://pan.example.com/r/b"},
+		},
+	}
+	client := &fakeGoogleIndexClient{}
+	s := newTestCoverageService(map[string]string{
+		entity.GoogleIndexConfigKeyInspectQuota: "100",
+		entity.GoogleIndexConfigKeyAutoResubmit: "true",
+	}, statusRepo, client)
+
+	result, err := s.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.inspected) != 0 || result.QuotaRemaining != 0 {
+		t.Errorf("quota exhausted, inspected=%v result=%+v", client.inspected, result)
+	}
+	if result.Resubmitted != 2 || len(statusRepo.resubmitted) != 2 {
+		t.Errorf("resubmitted=%v result=%+v", statusRepo.resubmitted, result)
+	}
+}
+
+func TestGoogleIndexCoverageRejectsUnknownGroup(t *testing.T) {
+	s := newTestCoverageService(nil, &fakeGoogleIndexStatusRepo{}, &fakeGoogleIndexClient{})
+	if _, err := s.Coverage("tag", 0); err == nil {
+		t.Error("expected error for unsupported group")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cache"
 	"github.com/ctwj/urldb/utils"
 )
 
@@ -309,7 +310,7 @@ func ApplyValidityWriteback(
 	utils.Info("资源有效性翻转 - ID: %d, %v -> %v", resource.ID, !newValid, newValid)
 
 	// 清除热门资源缓存，避免失效资源继续展示在热门列表
-	utils.GetHotResourcesCache().DeletePattern("hot_resources_")
+	cache.Default().Invalidate(context.Background(), cache.Tag("resource", resource.ID))
 
 	// 同步 Meilisearch
 	if meiliManager != nil && meiliManager.IsEnabled() {
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/totp"
+	"github.com/ctwj/urldb/utils"
+
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+const (
+	// 登录失败计数窗口：窗口内同一用户名失败达到上限后锁定，直到最早的失败移出窗口
+	loginFailureWindow = 15 * time.Minute
+	// 同一用户名在窗口内允许的失败次数，成功登录后重新计数
+	loginMaxUsernameFailures = 5
+	// 同一IP在窗口内允许的失败次数，覆盖对多个用户名的撞库
+	loginMaxIPFailures = 20
+	// 登录记录保留时间
+	loginHistoryRetention = 180 * 24 * time.Hour
+	// 恢复码数量
+	recoveryCodeCount = 10
+	// 恢复码字符集，去掉易混淆的 0/o、1/l/i
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	ErrTwoFactorNotSetup       = errors.New("尚未生成两步验证密钥")
+	ErrTwoFactorAlreadyEnabled = errors.New("两步验证已启用")
+	ErrTwoFactorNotEnabled     = errors.New("两步验证未启用")
+	ErrTwoFactorCodeInvalid    = errors.New("验证码错误")
+)
+
+// LoginThrottleError 登录失败次数过多被临时锁定
+type LoginThrottleError struct {
+	RetryAfter int // 秒
+}
+
+func (e *LoginThrottleError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请 %d 分钟后再试", int(math.Ceil(float64(e.RetryAfter)/60)))
+}
+
+// LoginAlertNotifier 管理员登录告警通知，由 TelegramBotService 实现
+type LoginAlertNotifier interface {
+	NotifyAdmin(text string) error
+}
+
+// TwoFactorSetup 两步验证初始化信息
+type TwoFactorSetup struct {
+	Secret string
+	URI    string
+	QRCode string // PNG data URL
+}
+
+// LoginSecurityService 登录保护：失败限流、登录记录、两步验证与新IP登录告警
+type LoginSecurityService struct {
+	historyRepo   repo.LoginHistoryRepository
+	twoFactorRepo repo.UserTwoFactorRepository
+	configRepo    repo.SystemConfigRepository
+	notifier      LoginAlertNotifier
+	now           func() time.Time
+
+	mu          sync.Mutex
+	lastPruneAt time.Time
+}
+
+// NewLoginSecurityService 创建登录保护服务
+func NewLoginSecurityService(historyRepo repo.LoginHistoryRepository, twoFactorRepo repo.UserTwoFactorRepository, configRepo repo.SystemConfigRepository) *LoginSecurityService {
+	return &LoginSecurityService{
+		historyRepo:   historyRepo,
+		twoFactorRepo: twoFactorRepo,
+		configRepo:    configRepo,
+		now:           utils.GetCurrentTime,
+	}
+}
+
+// SetNotifier 设置管理员登录告警通知渠道
+func (s *LoginSecurityService) SetNotifier(notifier LoginAlertNotifier) {
+	s.notifier = notifier
+}
+
+// 默认实例：在 main.go 中通过 SetDefaultLoginSecurityService 注入
+var defaultLoginSecurityService *LoginSecurityService
+
+// SetDefaultLoginSecurityService 设置默认登录保护服务实例
+func SetDefaultLoginSecurityService(s *LoginSecurityService) {
+	defaultLoginSecurityService = s
+}
+
+// GetDefaultLoginSecurityService 获取默认登录保护服务实例，未初始化时为 nil
+func GetDefaultLoginSecurityService() *LoginSecurityService {
+	return defaultLoginSecurityService
+}
+
+// CheckThrottle 检查用户名与IP是否因失败次数过多被锁定
+func (s *LoginSecurityService) CheckThrottle(username, ip string) error {
+	now := s.now()
+	windowStart := now.Add(-loginFailureWindow)
+
+	since := windowStart
+	if lastSuccess, err := s.historyRepo.LastSuccessAt(username); err != nil {
+		return err
+	} else if lastSuccess != nil && lastSuccess.After(since) {
+		since = *lastSuccess
+	}
+	count, latest, err := s.historyRepo.CountFailuresByUsername(username, since)
+	if err != nil {
+		return err
+	}
+	if count >= loginMaxUsernameFailures {
+		return s.throttleError(latest, now)
+	}
+
+	count, latest, err = s.historyRepo.CountFailuresByIP(ip, windowStart)
+	if err != nil {
+		return err
+	}
+	if count >= loginMaxIPFailures {
+		return s.throttleError(latest, now)
+	}
+	return nil
+}
+
+func (s *LoginSecurityService) throttleError(latest *time.Time, now time.Time) error {
+	retryAfter := int(loginFailureWindow.Seconds())
+	if latest != nil {
+		retryAfter = int(math.Ceil(latest.Add(loginFailureWindow).Sub(now).Seconds()))
+	}
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return &LoginThrottleError{RetryAfter: retryAfter}
+}
+
+// RecordLogin 记录一次登录尝试
+func (s *LoginSecurityService) RecordLogin(userID uint, username, ip, userAgent string, success bool, reason string, twoFactor bool) {
+	history := &entity.LoginHistory{
+		UserID:        userID,
+		Username:      truncateRunes(username, 50),
+		IP:            ip,
+		UserAgent:     truncateRunes(userAgent, 255),
+		Success:       success,
+		FailureReason: reason,
+		TwoFactor:     twoFactor,
+		CreatedAt:     s.now(),
+	}
+	if err := s.historyRepo.Create(history); err != nil {
+		utils.Error("LoginSecurity - 保存登录记录失败: %v", err)
+	}
+	s.pruneHistory()
+}
+
+// ListHistory 分页查询登录记录
+func (s *LoginSecurityService) ListHistory(userID uint, username, ip string, success *bool, page, pageSize int) ([]entity.LoginHistory, int64, error) {
+	return s.historyRepo.Search(userID, username, ip, success, page, pageSize)
+}
+
+// pruneHistory 每天最多一次清理超过保留期的登录记录
+func (s *LoginSecurityService) pruneHistory() {
+	now := s.now()
+	s.mu.Lock()
+	due := now.Sub(s.lastPruneAt) >= 24*time.Hour
+	if due {
+		s.lastPruneAt = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		deleted, err := s.historyRepo.DeleteBefore(now.Add(-loginHistoryRetention))
+		if err != nil {
+			utils.Error("LoginSecurity - 清理登录记录失败: %v", err)
+		} else if deleted > 0 {
+			utils.Info("LoginSecurity - 已清理 %d 条过期登录记录", deleted)
+		}
+	}()
+}
+
+// HandleUserLogin 用户登录事件监听：管理员从未成功登录过的IP登录时发送告警。
+// 需在登录记录写入之后触发
+func (s *LoginSecurityService) HandleUserLogin(user *entity.User, data map[string]interface{}) {
+	if s.notifier == nil || user.Role != entity.RoleAdmin {
+		return
+	}
+	ip, _ := data["ip"].(string)
+	userAgent, _ := data["user_agent"].(string)
+	if ip == "" {
+		return
+	}
+
+	go func() {
+		count, err := s.historyRepo.CountSuccessFromIP(user.ID, ip)
+		if err != nil {
+			utils.Error("LoginSecurity - 查询登录IP失败: %v", err)
+			return
+		}
+		if count > 1 {
+			return
+		}
+		text := fmt.Sprintf("⚠️ <b>管理员新IP登录</b>\n用户：%s\nIP：%s\n客户端：%s\n时间：%s\n如非本人操作，请立即修改密码并吊销会话。",
+			escapeHTML(user.Username), escapeHTML(ip), escapeHTML(truncateRunes(userAgent, 120)), s.now().Format("2006-01-02 15:04:05"))
+		if err := s.notifier.NotifyAdmin(text); err != nil {
+			utils.Error("LoginSecurity - 发送登录告警失败: %v", err)
+		}
+	}()
+}
+
+// TwoFactorStatus 两步验证状态及剩余恢复码数量
+func (s *LoginSecurityService) TwoFactorStatus(userID uint) (bool, int, error) {
+	twoFactor, err := s.findTwoFactor(userID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotSetup) {
+			return false, 0, nil
+		}
+		return false, 0, err
+	}
+	if !twoFactor.Enabled {
+		return false, 0, nil
+	}
+	return true, len(twoFactor.RecoveryCodeHashes()), nil
+}
+
+// TwoFactorEnabled 用户是否已启用两步验证
+func (s *LoginSecurityService) TwoFactorEnabled(userID uint) (bool, error) {
+	enabled, _, err := s.TwoFactorStatus(userID)
+	return enabled, err
+}
+
+// SetupTwoFactor 生成新的待确认密钥，已启用时需先停用
+func (s *LoginSecurityService) SetupTwoFactor(user *entity.User) (*TwoFactorSetup, error) {
+	twoFactor, err := s.findTwoFactor(user.ID)
+	if err != nil && !errors.Is(err, ErrTwoFactorNotSetup) {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if twoFactor == nil {
+		twoFactor = &entity.UserTwoFactor{UserID: user.ID}
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.Secret = secret
+	twoFactor.LastUsedStep = 0
+	twoFactor.RecoveryCodes = ""
+	if err := s.twoFactorRepo.Save(twoFactor); err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(s.issuer(), user.Username, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorSetup{
+		Secret: secret,
+		URI:    uri,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// EnableTwoFactor 校验验证器生成的验证码后启用两步验证，返回恢复码明文（仅此一次）
+func (s *LoginSecurityService) EnableTwoFactor(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.findTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, ok := totp.Validate(twoFactor.Secret, code, s.now(), 1)
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	twoFactor.Enabled = true
+	twoFactor.EnabledAt = &now
+	twoFactor.LastUsedStep = step
+	twoFactor.SetRecoveryCodeHashes(hashes)
+	if err := s.twoFactorRepo.Save(twoFactor); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyTwoFactor 校验登录时的第二因素：6 位TOTP验证码或一次性恢复码
+func (s *LoginSecurityService) VerifyTwoFactor(userID uint, code string) error {
+	twoFactor, err := s.findTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(twoFactor.Secret, code, s.now(), 1)
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+		advanced, err := s.twoFactorRepo.AdvanceStep(twoFactor.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			// 同一验证码不能使用两次
+			return ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+
+	hash := hashRecoveryCode(code)
+	hashes := twoFactor.RecoveryCodeHashes()
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			twoFactor.SetRecoveryCodeHashes(append(hashes[:i:i], hashes[i+1:]...))
+			if err := s.twoFactorRepo.Save(twoFactor); err != nil {
+				return err
+			}
+			utils.Warn("LoginSecurity - 使用恢复码登录 - 用户ID: %d, 剩余恢复码: %d", userID, len(hashes)-1)
+			return nil
+		}
+	}
+	return ErrTwoFactorCodeInvalid
+}
+
+// DisableTwoFactor 校验第二因素后停用两步验证
+func (s *LoginSecurityService) DisableTwoFactor(userID uint, code string) error {
+	if err := s.VerifyTwoFactor(userID, code); err != nil {
+		return err
+	}
+	return s.twoFactorRepo.DeleteByUserID(userID)
+}
+
+// ResetTwoFactor 管理员重置用户的两步验证（用户丢失验证器和恢复码时）
+func (s *LoginSecurityService) ResetTwoFactor(userID uint) error {
+	return s.twoFactorRepo.DeleteByUserID(userID)
+}
+
+// RegenerateRecoveryCodes 校验第二因素后重新生成恢复码，旧恢复码全部失效
+func (s *LoginSecurityService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	if err := s.VerifyTwoFactor(userID, code); err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.findTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	twoFactor.SetRecoveryCodeHashes(hashes)
+	if err := s.twoFactorRepo.Save(twoFactor); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *LoginSecurityService) findTwoFactor(userID uint) (*entity.UserTwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotSetup
+		}
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+// issuer 验证器中显示的站点名称
+func (s *LoginSecurityService) issuer() string {
+	if s.configRepo != nil {
+		if title, err := s.configRepo.GetConfigValue(entity.ConfigKeySiteTitle); err == nil && title != "" {
+			return title
+		}
+	}
+	return "urldb"
+}
+
+// generateRecoveryCodes 生成恢复码明文（xxxx-xxxx）及其哈希
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	buf := make([]byte, 8)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 4 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		codes[i] = sb.String()
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 忽略大小写、空格与连字符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/totp"
+
+	"gorm.io/gorm"
+)
+
+type fakeLoginHistoryRepo struct {
+	repo.LoginHistoryRepository
+	mu      sync.Mutex
+	history []entity.LoginHistory
+}
+
+func (f *fakeLoginHistoryRepo) Create(h *entity.LoginHistory) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history = append(f.history, *h)
+	return nil
+}
+
+func (f *fakeLoginHistoryRepo) countFailures(match func(entity.LoginHistory) bool, since time.Time) (int64, *time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	var latest *time.Time
+	for _, h := range f.history {
+		if !h.Success && match(h) && !h.CreatedAt.Before(since) {
+			count++
+			at := h.CreatedAt
+			if latest == nil || at.After(*latest) {
+				latest = &at
+			}
+		}
+	}
+	return count, latest, nil
+}
+
+func (f *fakeLoginHistoryRepo) CountFailuresByUsername(username string, since time.Time) (int64, *time.Time, error) {
+	return f.countFailures(func(h entity.LoginHistory) bool { return h.Username == username }, since)
+}
+
+func (f *fakeLoginHistoryRepo) CountFailuresByIP(ip string, since time.Time) (int64, *time.Time, error) {
+	return f.countFailures(func(h entity.LoginHistory) bool { return h.IP == ip }, since)
+}
+
+func (f *fakeLoginHistoryRepo) LastSuccessAt(username string) (*time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var latest *time.Time
+	for _, h := range f.history {
+		if h.Success && h.Username == username && (latest == nil || h.CreatedAt.After(*latest)) {
+			at := h.CreatedAt
+			latest = &at
+		}
+	}
+	return latest, nil
+}
+
+func (f *fakeLoginHistoryRepo) CountSuccessFromIP(userID uint, ip string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	for _, h := range f.history {
+		if h.Success && h.UserID == userID && h.IP == ip {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeLoginHistoryRepo) DeleteBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeTwoFactorRepo struct {
+	repo.UserTwoFactorRepository
+	mu      sync.Mutex
+	records map[uint]*entity.UserTwoFactor
+	nextID  uint
+}
+
+func (f *fakeTwoFactorRepo) FindByUserID(userID uint) (*entity.UserTwoFactor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, ok := f.records[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (f *fakeTwoFactorRepo) Save(twoFactor *entity.UserTwoFactor) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if twoFactor.ID == 0 {
+		f.nextID++
+		twoFactor.ID = f.nextID
+	}
+	copied := *twoFactor
+	f.records[twoFactor.UserID] = &copied
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) DeleteByUserID(userID uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, userID)
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) AdvanceStep(id uint, step int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, record := range f.records {
+		if record.ID == id && record.LastUsedStep < step {
+			record.LastUsedStep = step
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeLoginNotifier struct {
+	messages chan string
+}
+
+func (f *fakeLoginNotifier) NotifyAdmin(text string) error {
+	f.messages <- text
+	return nil
+}
+
+func newTestLoginSecurityService(now *time.Time) (*LoginSecurityService, *fakeLoginHistoryRepo, *fakeTwoFactorRepo) {
+	historyRepo := &fakeLoginHistoryRepo{}
+	twoFactorRepo := &fakeTwoFactorRepo{records: map[uint]*entity.UserTwoFactor{}}
+	svc := NewLoginSecurityService(historyRepo, twoFactorRepo, nil)
+	svc.now = func() time.Time { return *now }
+	// 测试中不触发异步清理
+	svc.lastPruneAt = *now
+	return svc, historyRepo, twoFactorRepo
+}
+
+func TestLoginThrottleLocksUsernameAndResetsAfterSuccess(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, _ := newTestLoginSecurityService(&now)
+
+	for i := 0; i < loginMaxUsernameFailures; i++ {
+		if err := svc.CheckThrottle("admin", "1.1.1.1"); err != nil {
+			t.Fatalf("attempt %d throttled early: %v", i, err)
+		}
+		svc.RecordLogin(1, "admin", "1.1.1.1", "ua", false, entity.LoginFailBadPassword, false)
+		now = now.Add(time.Minute)
+	}
+
+	var throttleErr *LoginThrottleError
+	if err := svc.CheckThrottle("admin", "2.2.2.2"); !errors.As(err, &throttleErr) {
+		t.Fatalf("expected throttle for username from another IP, got %v", err)
+	}
+	// 最近一次失败在 1 分钟前，需再等 14 分钟
+	if throttleErr.RetryAfter != int((loginFailureWindow - time.Minute).Seconds()) {
+		t.Fatalf("RetryAfter = %d", throttleErr.RetryAfter)
+	}
+	if err := svc.CheckThrottle("other", "2.2.2.2"); err != nil {
+		t.Fatalf("other usernames must not be throttled: %v", err)
+	}
+
+	// 窗口过后解锁，成功登录后重新计数
+	now = now.Add(loginFailureWindow)
+	if err := svc.CheckThrottle("admin", "1.1.1.1"); err != nil {
+		t.Fatalf("expected unlock after window: %v", err)
+	}
+	svc.RecordLogin(1, "admin", "1.1.1.1", "ua", false, entity.LoginFailBadPassword, false)
+	now = now.Add(time.Second)
+	svc.RecordLogin(1, "admin", "1.1.1.1", "ua", true, "", false)
+	now = now.Add(time.Second)
+	for i := 0; i < loginMaxUsernameFailures-1; i++ {
+		svc.RecordLogin(1, "admin", "1.1.1.1", "ua", false, entity.LoginFailBadPassword, false)
+	}
+	if err := svc.CheckThrottle("admin", "1.1.1.1"); err != nil {
+		t.Fatalf("failures before the last success must not count: %v", err)
+	}
+}
+
+func TestLoginThrottleLocksIPAcrossUsernames(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, _ := newTestLoginSecurityService(&now)
+
+	for i := 0; i < loginMaxIPFailures; i++ {
+		svc.RecordLogin(0, "user"+strings.Repeat("x", i), "3.3.3.3", "ua", false, entity.LoginFailUserNotFound, false)
+	}
+	var throttleErr *LoginThrottleError
+	if err := svc.CheckThrottle("fresh", "3.3.3.3"); !errors.As(err, &throttleErr) {
+		t.Fatalf("expected IP throttle, got %v", err)
+	}
+	if err := svc.CheckThrottle("fresh", "4.4.4.4"); err != nil {
+		t.Fatalf("other IPs must not be throttled: %v", err)
+	}
+}
+
+func TestTwoFactorEnableVerifyReplayAndRecoveryCodes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, twoFactorRepo := newTestLoginSecurityService(&now)
+	user := &entity.User{ID: 7, Username: "admin"}
+
+	if err := svc.VerifyTwoFactor(user.ID, "123456"); !errors.Is(err, ErrTwoFactorNotSetup) {
+		t.Fatalf("expected not setup, got %v", err)
+	}
+	setup, err := svc.SetupTwoFactor(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(setup.QRCode, "data:image/png;base64,") || !strings.Contains(setup.URI, setup.Secret) {
+		t.Fatalf("unexpected setup: %+v", setup)
+	}
+	if enabled, _ := svc.TwoFactorEnabled(user.ID); enabled {
+		t.Fatal("2FA must stay disabled until confirmed")
+	}
+
+	if _, err := svc.EnableTwoFactor(user.ID, "000000"); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("expected invalid code, got %v", err)
+	}
+	code, _ := totp.Code(setup.Secret, totp.Step(now))
+	recoveryCodes, err := svc.EnableTwoFactor(user.ID, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes", len(recoveryCodes))
+	}
+	if stored := twoFactorRepo.records[user.ID].RecoveryCodes; strings.Contains(stored, recoveryCodes[0]) {
+		t.Fatal("recovery codes must be stored hashed")
+	}
+
+	// 启用时使用过的验证码不能再用于登录
+	if err := svc.VerifyTwoFactor(user.ID, code); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("expected replay rejection, got %v", err)
+	}
+	now = now.Add(totp.Period * time.Second)
+	code, _ = totp.Code(setup.Secret, totp.Step(now))
+	if err := svc.VerifyTwoFactor(user.ID, code); err != nil {
+		t.Fatalf("fresh code rejected: %v", err)
+	}
+	if err := svc.VerifyTwoFactor(user.ID, code); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("expected replay rejection, got %v", err)
+	}
+
+	// 恢复码忽略大小写与连字符，且只能使用一次
+	recovery := strings.ToUpper(strings.ReplaceAll(recoveryCodes[3], "-", ""))
+	if err := svc.VerifyTwoFactor(user.ID, recovery); err != nil {
+		t.Fatalf("recovery code rejected: %v", err)
+	}
+	if err := svc.VerifyTwoFactor(user.ID, recoveryCodes[3]); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("recovery code reused: %v", err)
+	}
+	if enabled, remaining, _ := svc.TwoFactorStatus(user.ID); !enabled || remaining != recoveryCodeCount-1 {
+		t.Fatalf("status = %v, %d", enabled, remaining)
+	}
+
+	if _, err := svc.SetupTwoFactor(user); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Fatalf("expected already enabled, got %v", err)
+	}
+	if err := svc.DisableTwoFactor(user.ID, recoveryCodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, _ := svc.TwoFactorEnabled(user.ID); enabled {
+		t.Fatal("2FA should be disabled")
+	}
+}
+
+func TestHandleUserLoginAlertsAdminFromNewIP(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc, _, _ := newTestLoginSecurityService(&now)
+	notifier := &fakeLoginNotifier{messages: make(chan string, 4)}
+	svc.SetNotifier(notifier)
+
+	admin := &entity.User{ID: 1, Username: "admin", Role: entity.RoleAdmin}
+	login := func(user *entity.User, ip string) {
+		svc.RecordLogin(user.ID, user.Username, ip, "Mozilla", true, "", false)
+		svc.HandleUserLogin(user, map[string]interface{}{"ip": ip, "user_agent": "Mozilla"})
+	}
+
+	login(admin, "5.5.5.5")
+	select {
+	case msg := <-notifier.messages:
+		if !strings.Contains(msg, "5.5.5.5") {
+			t.Fatalf("unexpected alert: %s", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected alert for new IP")
+	}
+
+	login(admin, "5.5.5.5")
+	login(&entity.User{ID: 2, Username: "bob", Role: entity.RoleUser}, "6.6.6.6")
+	select {
+	case msg := <-notifier.messages:
+		t.Fatalf("unexpected alert: %s", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
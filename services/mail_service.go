@@ -0,0 +1,118 @@
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	smtpSendTimeout = 30 * time.Second
+)
+
+// SMTPMailer 通过 SMTP 发送纯文本邮件。465 端口使用隐式 TLS，其余端口在服务器支持时升级 STARTTLS
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPMailerFromEnv 根据 SMTP_* 环境变量创建邮件发送器，未配置 SMTP_HOST 时返回 nil
+func NewSMTPMailerFromEnv() *SMTPMailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	mailer := &SMTPMailer{
+		Host:     host,
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if mailer.Port == "" {
+		mailer.Port = "587"
+	}
+	if mailer.From == "" {
+		mailer.From = mailer.Username
+	}
+	utils.Info("邮件发送已启用 - SMTP: %s:%s, 发件人: %s", mailer.Host, mailer.Port, mailer.From)
+	return mailer
+}
+
+// SendMail 发送一封纯文本邮件
+func (m *SMTPMailer) SendMail(to, subject, body string) error {
+	addr := net.JoinHostPort(m.Host, m.Port)
+	tlsConfig := &tls.Config{ServerName: m.Host}
+
+	var conn net.Conn
+	var err error
+	if m.Port == "465" {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpDialTimeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, smtpDialTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpSendTimeout))
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接SMTP服务器失败: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.Port != "465" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS失败: %v", err)
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("SMTP认证失败: %v", err)
+		}
+	}
+	if err := client.Mail(m.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMailMessage(m.From, to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMailMessage 组装邮件头与正文，主题按 RFC 2047 编码
+func buildMailMessage(from, to, subject, body string) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
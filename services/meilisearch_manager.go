@@ -8,6 +8,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
 	"github.com/ctwj/urldb/utils"
 )
 
@@ -275,6 +276,14 @@ func (m *MeilisearchManager) SyncResourceToMeilisearch(resource *entity.Resource
 	return m.repoMgr.ResourceRepository.MarkAsSyncedToMeilisearch([]uint{resource.ID})
 }
 
+// DeleteResourceFromMeilisearch 从Meilisearch删除资源文档（资源下架或删除时使用）
+func (m *MeilisearchManager) DeleteResourceFromMeilisearch(resourceID uint) error {
+	if m.service == nil || !m.service.IsEnabled() {
+		return fmt.Errorf("Meilisearch服务未初始化或未启用")
+	}
+	return m.service.DeleteDocument(resourceID)
+}
+
 // SyncAllResources 同步所有资源
 func (m *MeilisearchManager) SyncAllResources() (int, error) {
 	if m.service == nil || !m.service.IsEnabled() {
@@ -303,7 +312,9 @@ func (m *MeilisearchManager) SyncAllResources() (int, error) {
 	}
 	// 重新创建停止通道
 	m.syncStopChan = make(chan struct{})
+	progress := m.syncProgress
 	m.syncMutex.Unlock()
+	eventbus.Publish(eventbus.TypeMeilisearchSync, progress)
 
 	// 在goroutine中执行同步，避免阻塞
 	go func() {
@@ -311,7 +322,9 @@ func (m *MeilisearchManager) SyncAllResources() (int, error) {
 			m.syncMutex.Lock()
 			m.isSyncing = false
 			m.syncProgress.IsRunning = false
+			progress := m.syncProgress
 			m.syncMutex.Unlock()
+			eventbus.Publish(eventbus.TypeMeilisearchSync, progress)
 		}()
 
 		m.syncAllResourcesInternal()
@@ -546,7 +559,6 @@ func (m *MeilisearchManager) syncAllResourcesInternal() {
 // updateSyncProgress 更新同步进度
 func (m *MeilisearchManager) updateSyncProgress(syncedCount, currentBatch, errorMessage string) {
 	m.syncMutex.Lock()
-	defer m.syncMutex.Unlock()
 
 	if syncedCount != "" {
 		if count, err := strconv.ParseInt(syncedCount, 10, 64); err == nil {
@@ -574,6 +586,10 @@ func (m *MeilisearchManager) updateSyncProgress(syncedCount, currentBatch, error
 			m.syncProgress.EstimatedTime = fmt.Sprintf("%.0f秒", remaining)
 		}
 	}
+	progress := m.syncProgress
+	m.syncMutex.Unlock()
+
+	eventbus.Publish(eventbus.TypeMeilisearchSync, progress)
 }
 
 // GetUnsyncedCount 获取未同步资源数量
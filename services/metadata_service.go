@@ -0,0 +1,539 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/metadata"
+	"github.com/ctwj/urldb/utils"
+	"gorm.io/gorm"
+)
+
+// 元数据数据源选择
+const (
+	MetadataProviderDouban = metadata.ProviderDouban
+	MetadataProviderTMDB   = metadata.ProviderTMDB
+	MetadataProviderAuto   = "auto" // 先豆瓣，未匹配时再查 TMDB
+)
+
+// metadataFailedRetry 请求数据源失败后的重试间隔
+const metadataFailedRetry = 24 * time.Hour
+
+// metadataRequestInterval 批量处理时两次请求之间的间隔，避免触发数据源限流
+const metadataRequestInterval = 2 * time.Second
+
+// MetadataConfig 影视元数据配置
+type MetadataConfig struct {
+	Enabled     bool    `json:"enabled"`
+	Provider    string  `json:"provider"`
+	TMDBApiKey  string  `json:"tmdb_api_key"`
+	RefreshDays int     `json:"refresh_days"`
+	MinScore    float64 `json:"min_score"`
+	BatchSize   int     `json:"batch_size"`
+}
+
+// Validate 校验配置
+func (c MetadataConfig) Validate() error {
+	switch c.Provider {
+	case MetadataProviderDouban, MetadataProviderAuto:
+	case MetadataProviderTMDB:
+		if strings.TrimSpace(c.TMDBApiKey) == "" {
+			return fmt.Errorf("使用 TMDB 时必须填写 API Key")
+		}
+	default:
+		return fmt.Errorf("不支持的数据源: %s", c.Provider)
+	}
+	if c.RefreshDays < 1 || c.RefreshDays > 365 {
+		return fmt.Errorf("刷新周期须在 1-365 天之间")
+	}
+	if c.MinScore <= 0 || c.MinScore > 1 {
+		return fmt.Errorf("最低匹配度须在 (0, 1] 之间")
+	}
+	if c.BatchSize < 1 || c.BatchSize > 200 {
+		return fmt.Errorf("每轮处理数量须在 1-200 之间")
+	}
+	return nil
+}
+
+// MetadataBatchResult 批量补全/刷新结果
+type MetadataBatchResult struct {
+	Total    int `json:"total"`
+	Matched  int `json:"matched"`
+	NotFound int `json:"not_found"`
+	Failed   int `json:"failed"`
+}
+
+func (r *MetadataBatchResult) add(m *entity.ResourceMetadata) {
+	r.Total++
+	switch {
+	case m == nil || m.Status == entity.ResourceMetadataFailed:
+		r.Failed++
+	case m.HasSubject():
+		r.Matched++
+	default:
+		r.NotFound++
+	}
+}
+
+// MetadataService 影视元数据补全服务：按规范化标题与年份匹配豆瓣条目（或 TMDB 作品），
+// 结果保存在 resource_metadata 中，供搜索筛选、OG 图片与 Telegram 推送使用。
+type MetadataService struct {
+	repo         repo.ResourceMetadataRepository
+	resourceRepo repo.ResourceRepository
+	configRepo   repo.SystemConfigRepository
+
+	// 以下字段便于测试替换
+	newProviders    func(cfg MetadataConfig) []metadata.Provider
+	requestInterval time.Duration
+	now             func() time.Time
+}
+
+// NewMetadataService 创建影视元数据补全服务
+func NewMetadataService(
+	metadataRepo repo.ResourceMetadataRepository,
+	resourceRepo repo.ResourceRepository,
+	configRepo repo.SystemConfigRepository,
+) *MetadataService {
+	return &MetadataService{
+		repo:            metadataRepo,
+		resourceRepo:    resourceRepo,
+		configRepo:      configRepo,
+		newProviders:    defaultMetadataProviders,
+		requestInterval: metadataRequestInterval,
+		now:             utils.GetCurrentTime,
+	}
+}
+
+// defaultMetadataProviders 按配置创建数据源，按顺序查询
+func defaultMetadataProviders(cfg MetadataConfig) []metadata.Provider {
+	var providers []metadata.Provider
+	switch cfg.Provider {
+	case MetadataProviderTMDB:
+		providers = append(providers, metadata.NewTMDBProvider(cfg.TMDBApiKey))
+	case MetadataProviderAuto:
+		providers = append(providers, metadata.NewDoubanProvider())
+		if cfg.TMDBApiKey != "" {
+			providers = append(providers, metadata.NewTMDBProvider(cfg.TMDBApiKey))
+		}
+	default:
+		providers = append(providers, metadata.NewDoubanProvider())
+	}
+	return providers
+}
+
+// Config 读取配置，缺失或非法的项使用默认值
+func (s *MetadataService) Config() MetadataConfig {
+	cfg := MetadataConfig{
+		Provider:    entity.MetadataConfigDefaultProvider,
+		RefreshDays: entity.MetadataConfigDefaultRefreshDays,
+		MinScore:    entity.MetadataConfigDefaultMinScore,
+		BatchSize:   entity.MetadataConfigDefaultBatchSize,
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+	cfg.Enabled, _ = s.configRepo.GetConfigBool(entity.MetadataConfigKeyEnabled)
+	if v, err := s.configRepo.GetConfigValue(entity.MetadataConfigKeyProvider); err == nil && v != "" {
+		cfg.Provider = v
+	}
+	cfg.TMDBApiKey, _ = s.configRepo.GetConfigValue(entity.MetadataConfigKeyTMDBApiKey)
+	if v, err := s.configRepo.GetConfigInt(entity.MetadataConfigKeyRefreshDays); err == nil && v > 0 {
+		cfg.RefreshDays = v
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.MetadataConfigKeyMinScore); err == nil {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 && f <= 1 {
+			cfg.MinScore = f
+		}
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.MetadataConfigKeyBatchSize); err == nil && v > 0 {
+		cfg.BatchSize = v
+	}
+	return cfg
+}
+
+// SaveConfig 校验并保存配置
+func (s *MetadataService) SaveConfig(cfg MetadataConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.MetadataConfigKeyEnabled, Value: strconv.FormatBool(cfg.Enabled), Type: entity.ConfigTypeBool},
+		{Key: entity.MetadataConfigKeyProvider, Value: cfg.Provider, Type: entity.ConfigTypeString},
+		{Key: entity.MetadataConfigKeyTMDBApiKey, Value: strings.TrimSpace(cfg.TMDBApiKey), Type: entity.ConfigTypeString},
+		{Key: entity.MetadataConfigKeyRefreshDays, Value: strconv.Itoa(cfg.RefreshDays), Type: entity.ConfigTypeInt},
+		{Key: entity.MetadataConfigKeyMinScore, Value: strconv.FormatFloat(cfg.MinScore, 'f', -1, 64), Type: entity.ConfigTypeString},
+		{Key: entity.MetadataConfigKeyBatchSize, Value: strconv.Itoa(cfg.BatchSize), Type: entity.ConfigTypeInt},
+	})
+}
+
+func (s *MetadataService) provider(cfg MetadataConfig, name string) (metadata.Provider, error) {
+	for _, p := range s.newProviders(MetadataConfig{Provider: MetadataProviderAuto, TMDBApiKey: cfg.TMDBApiKey}) {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("数据源不可用: %s", name)
+}
+
+// Search 解析标题并返回各数据源的候选条目（按匹配度排序），供管理端手动绑定
+func (s *MetadataService) Search(ctx context.Context, title string) (metadata.Query, []metadata.Match, error) {
+	cfg := s.Config()
+	q := metadata.ParseTitle(title)
+	if q.Name == "" {
+		return q, nil, fmt.Errorf("无法从标题中解析片名")
+	}
+
+	var candidates []metadata.Subject
+	var errs []string
+	for _, p := range s.newProviders(cfg) {
+		subjects, err := p.Search(ctx, q)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		candidates = append(candidates, subjects...)
+	}
+	if len(candidates) == 0 && len(errs) > 0 {
+		return q, nil, errors.New(strings.Join(errs, "; "))
+	}
+	return q, metadata.Rank(q, candidates), nil
+}
+
+// Resolve 按标题匹配条目并获取详情，返回未保存的元数据（ResourceID 为 0）。
+// 数据源全部请求失败时返回状态为 failed 的元数据及错误。
+func (s *MetadataService) Resolve(ctx context.Context, title string, minScore float64) (*entity.ResourceMetadata, error) {
+	cfg := s.Config()
+	if minScore <= 0 {
+		minScore = cfg.MinScore
+	}
+	q := metadata.ParseTitle(title)
+	result := &entity.ResourceMetadata{MatchQuery: truncateRunes(q.Name, 255), Status: entity.ResourceMetadataNotFound}
+	if q.Name == "" {
+		return result, nil
+	}
+
+	var errs []string
+	for _, p := range s.newProviders(cfg) {
+		subjects, err := p.Search(ctx, q)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		best, ok := metadata.BestMatch(q, subjects, minScore)
+		if !ok {
+			continue
+		}
+
+		subject := best.Subject
+		if detail, err := p.Detail(ctx, subject.MediaType, subject.ID); err == nil {
+			subject = mergeSubject(subject, *detail)
+		} else {
+			utils.Warn("[Metadata] 获取条目详情失败，使用搜索结果: %s/%s: %v", p.Name(), subject.ID, err)
+		}
+		applySubject(result, subject)
+		result.MatchScore = best.Score
+		result.Status = entity.ResourceMetadataMatched
+		return result, nil
+	}
+
+	if len(errs) > 0 {
+		result.Status = entity.ResourceMetadataFailed
+		result.Error = truncateRunes(strings.Join(errs, "; "), 255)
+		return result, errors.New(result.Error)
+	}
+	return result, nil
+}
+
+// Save 保存资源的元数据并安排下次刷新
+func (s *MetadataService) Save(resourceID uint, m *entity.ResourceMetadata) error {
+	cfg := s.Config()
+	now := s.now()
+	m.ResourceID = resourceID
+	m.FetchedAt = &now
+	if m.Status != entity.ResourceMetadataFailed {
+		m.Error = ""
+	}
+	next := now.Add(time.Duration(cfg.RefreshDays) * 24 * time.Hour)
+	if m.Status == entity.ResourceMetadataFailed {
+		next = now.Add(metadataFailedRetry)
+	}
+	m.NextRefreshAt = &next
+	return s.repo.Upsert(m)
+}
+
+// Enrich 补全或刷新单个资源的元数据。
+// 已关联条目的资源仅重新获取条目详情（评分、集数会变化）；force 为 true 时按标题重新匹配（手动绑定除外）。
+func (s *MetadataService) Enrich(ctx context.Context, resource *entity.Resource, force bool) (*entity.ResourceMetadata, error) {
+	existing, err := s.repo.FindByResourceID(resource.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if existing.HasSubject() && (!force || existing.Status == entity.ResourceMetadataManual) {
+		return s.refreshSubject(ctx, existing)
+	}
+
+	m, resolveErr := s.Resolve(ctx, resource.Title, 0)
+	if existing != nil {
+		m.ID = existing.ID
+		// 请求失败时保留已有的条目信息
+		if m.Status == entity.ResourceMetadataFailed && existing.HasSubject() {
+			existing.Status, existing.Error = entity.ResourceMetadataFailed, m.Error
+			m = existing
+		}
+	}
+	if err := s.Save(resource.ID, m); err != nil {
+		return nil, err
+	}
+	return m, resolveErr
+}
+
+// refreshSubject 重新获取已关联条目的详情
+func (s *MetadataService) refreshSubject(ctx context.Context, m *entity.ResourceMetadata) (*entity.ResourceMetadata, error) {
+	p, err := s.provider(s.Config(), m.Provider)
+	if err != nil {
+		return nil, err
+	}
+	status := m.Status
+	detail, err := p.Detail(ctx, m.MediaType, m.SubjectID)
+	if err != nil {
+		// 刷新失败不影响已有数据，仅推迟到下次重试
+		m.Error = truncateRunes(err.Error(), 255)
+		now := s.now()
+		next := now.Add(metadataFailedRetry)
+		m.NextRefreshAt = &next
+		if saveErr := s.repo.Upsert(m); saveErr != nil {
+			return nil, saveErr
+		}
+		return m, err
+	}
+	applySubject(m, *detail)
+	m.Status = status
+	if err := s.Save(m.ResourceID, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Bind 手动将资源绑定到指定条目，之后刷新只更新该条目的详情
+func (s *MetadataService) Bind(ctx context.Context, resourceID uint, providerName, mediaType, subjectID string) (*entity.ResourceMetadata, error) {
+	if _, err := s.resourceRepo.FindByID(resourceID); err != nil {
+		return nil, err
+	}
+	p, err := s.provider(s.Config(), providerName)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := p.Detail(ctx, mediaType, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("获取条目详情失败: %v", err)
+	}
+
+	m := &entity.ResourceMetadata{}
+	if existing, err := s.repo.FindByResourceID(resourceID); err == nil {
+		m.ID = existing.ID
+		m.MatchQuery = existing.MatchQuery
+	}
+	applySubject(m, *detail)
+	m.MatchScore = 1
+	m.Status = entity.ResourceMetadataManual
+	if err := s.Save(resourceID, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Unbind 解除资源与条目的关联，并且不再自动匹配
+func (s *MetadataService) Unbind(resourceID uint) error {
+	existing, err := s.repo.FindByResourceID(resourceID)
+	if err != nil {
+		return err
+	}
+	m := &entity.ResourceMetadata{
+		ID:         existing.ID,
+		ResourceID: resourceID,
+		MatchQuery: existing.MatchQuery,
+		Status:     entity.ResourceMetadataNotFound,
+	}
+	return s.repo.Upsert(m)
+}
+
+// EnrichMissing 为尚未匹配过的资源补全元数据
+func (s *MetadataService) EnrichMissing(ctx context.Context, limit int) (*MetadataBatchResult, error) {
+	resources, err := s.repo.FindResourcesWithoutMetadata(limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &MetadataBatchResult{}
+	for i := range resources {
+		if i > 0 && !s.wait(ctx) {
+			break
+		}
+		m, err := s.Enrich(ctx, &resources[i], false)
+		if err != nil {
+			utils.Warn("[Metadata] 补全资源元数据失败: id=%d, title=%s: %v", resources[i].ID, resources[i].Title, err)
+		}
+		result.add(m)
+	}
+	return result, nil
+}
+
+// RefreshDue 刷新到期的元数据：已关联条目的更新详情，未匹配或失败的重新匹配
+func (s *MetadataService) RefreshDue(ctx context.Context, limit int) (*MetadataBatchResult, error) {
+	due, err := s.repo.FindDueForRefresh(s.now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &MetadataBatchResult{}
+	for i, m := range due {
+		if i > 0 && !s.wait(ctx) {
+			break
+		}
+		resource, err := s.resourceRepo.FindByID(m.ResourceID)
+		if err != nil {
+			// 资源已删除，清理孤立的元数据
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				_ = s.repo.DeleteByResourceID(m.ResourceID)
+			}
+			continue
+		}
+		updated, err := s.Enrich(ctx, resource, false)
+		if err != nil {
+			utils.Warn("[Metadata] 刷新资源元数据失败: id=%d: %v", m.ResourceID, err)
+		}
+		result.add(updated)
+	}
+	return result, nil
+}
+
+// wait 批量处理时的请求间隔，ctx 取消时返回 false
+func (s *MetadataService) wait(ctx context.Context) bool {
+	if s.requestInterval <= 0 {
+		return ctx.Err() == nil
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(s.requestInterval):
+		return true
+	}
+}
+
+// ForResource 获取资源已匹配的元数据，未匹配时返回 nil
+func (s *MetadataService) ForResource(resourceID uint) *entity.ResourceMetadata {
+	m, err := s.repo.FindByResourceID(resourceID)
+	if err != nil || !m.HasSubject() {
+		return nil
+	}
+	return m
+}
+
+// ForResources 批量获取资源已匹配的元数据
+func (s *MetadataService) ForResources(resourceIDs []uint) map[uint]*entity.ResourceMetadata {
+	result := make(map[uint]*entity.ResourceMetadata)
+	list, err := s.repo.FindByResourceIDs(resourceIDs)
+	if err != nil {
+		utils.Error("[Metadata] 批量获取元数据失败: %v", err)
+		return result
+	}
+	for i := range list {
+		if list[i].HasSubject() {
+			result[list[i].ResourceID] = &list[i]
+		}
+	}
+	return result
+}
+
+// ForKey 获取资源组已匹配的元数据，未匹配时返回 nil
+func (s *MetadataService) ForKey(key string) *entity.ResourceMetadata {
+	if key == "" {
+		return nil
+	}
+	m, err := s.repo.FindByKey(key)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// Stats 按匹配状态统计
+func (s *MetadataService) Stats() (map[string]int64, error) {
+	return s.repo.CountByStatus()
+}
+
+// MetadataSummary 生成一行元数据摘要，如 "2023 · 中国大陆 · 剧情/爱情 · 豆瓣 8.3"
+func MetadataSummary(m *entity.ResourceMetadata) string {
+	if !m.HasSubject() {
+		return ""
+	}
+	return metadata.Summary(metadata.Subject{
+		Provider: m.Provider,
+		Year:     m.Year,
+		Regions:  splitMetadataList(m.Regions),
+		Genres:   splitMetadataList(m.Genres),
+		Rating:   m.Rating,
+		Episodes: m.Episodes,
+	})
+}
+
+// applySubject 将条目信息写入元数据
+func applySubject(m *entity.ResourceMetadata, s metadata.Subject) {
+	m.Provider = s.Provider
+	m.SubjectID = s.ID
+	m.MediaType = s.MediaType
+	m.Title = truncateRunes(s.Title, 255)
+	m.OriginalTitle = truncateRunes(s.OriginalTitle, 255)
+	m.Year = s.Year
+	m.Regions = truncateRunes(strings.Join(s.Regions, ","), 255)
+	m.Genres = truncateRunes(strings.Join(s.Genres, ","), 255)
+	m.Rating = s.Rating
+	m.RatingCount = s.RatingCount
+	m.PosterURL = truncateRunes(s.Poster, 500)
+	m.Episodes = s.Episodes
+	m.Directors = truncateRunes(strings.Join(s.Directors, ","), 500)
+	m.Actors = truncateRunes(strings.Join(s.Actors, ","), 1000)
+	m.Summary = s.Summary
+	m.SubjectURL = truncateRunes(s.URL, 255)
+}
+
+// mergeSubject 以详情为准，详情缺失的字段使用搜索结果补齐
+func mergeSubject(base, detail metadata.Subject) metadata.Subject {
+	if detail.Title == "" {
+		detail.Title = base.Title
+	}
+	if detail.Year == 0 {
+		detail.Year = base.Year
+	}
+	if len(detail.Regions) == 0 {
+		detail.Regions = base.Regions
+	}
+	if len(detail.Genres) == 0 {
+		detail.Genres = base.Genres
+	}
+	if detail.Poster == "" {
+		detail.Poster = base.Poster
+	}
+	if detail.Rating == 0 {
+		detail.Rating, detail.RatingCount = base.Rating, base.RatingCount
+	}
+	if detail.MediaType == "" {
+		detail.MediaType = base.MediaType
+	}
+	return detail
+}
+
+func splitMetadataList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/metadata"
+	"gorm.io/gorm"
+)
+
+type fakeMetadataProvider struct {
+	subjects  []metadata.Subject
+	detail    *metadata.Subject
+	searchErr error
+	detailErr error
+	searches  int
+	details   int
+}
+
+func (f *fakeMetadataProvider) Name() string { return metadata.ProviderDouban }
+
+func (f *fakeMetadataProvider) Search(ctx context.Context, q metadata.Query) ([]metadata.Subject, error) {
+	f.searches++
+	return f.subjects, f.searchErr
+}
+
+func (f *fakeMetadataProvider) Detail(ctx context.Context, mediaType, id string) (*metadata.Subject, error) {
+	f.details++
+	if f.detailErr != nil {
+		return nil, f.detailErr
+	}
+	return f.detail, nil
+}
+
+type fakeMetadataRepo struct {
+	repo.ResourceMetadataRepository
+	byResource map[uint]*entity.ResourceMetadata
+}
+
+func (f *fakeMetadataRepo) FindByResourceID(resourceID uint) (*entity.ResourceMetadata, error) {
+	if m, ok := f.byResource[resourceID]; ok {
+		copied := *m
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeMetadataRepo) Upsert(m *entity.ResourceMetadata) error {
+	if f.byResource == nil {
+		f.byResource = map[uint]*entity.ResourceMetadata{}
+	}
+	copied := *m
+	f.byResource[m.ResourceID] = &copied
+	return nil
+}
+
+var metadataTestNow = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func newTestMetadataService(p *fakeMetadataProvider, r *fakeMetadataRepo) *MetadataService {
+	svc := NewMetadataService(r, nil, nil)
+	svc.newProviders = func(MetadataConfig) []metadata.Provider { return []metadata.Provider{p} }
+	svc.requestInterval = 0
+	svc.now = func() time.Time { return metadataTestNow }
+	return svc
+}
+
+func TestMetadataResolve(t *testing.T) {
+	subject := metadata.Subject{
+		Provider: metadata.ProviderDouban, ID: "35267208", MediaType: metadata.MediaTV,
+		Title: "繁花", Year: 2023, Rating: 8.0,
+	}
+	detail := subject
+	detail.Genres = []string{"剧情", "爱情"}
+	detail.Regions = []string{"中国大陆"}
+	detail.Episodes = 30
+
+	tests := []struct {
+		name       string
+		provider   *fakeMetadataProvider
+		wantStatus string
+		wantErr    bool
+		wantGenres string
+	}{
+		{"matched with detail", &fakeMetadataProvider{subjects: []metadata.Subject{subject}, detail: &detail}, entity.ResourceMetadataMatched, false, "剧情,爱情"},
+		{"detail failure falls back to search result", &fakeMetadataProvider{subjects: []metadata.Subject{subject}, detailErr: errors.New("timeout")}, entity.ResourceMetadataMatched, false, ""},
+		{"no candidates", &fakeMetadataProvider{}, entity.ResourceMetadataNotFound, false, ""},
+		{"low score", &fakeMetadataProvider{subjects: []metadata.Subject{{ID: "1", Title: "完全不同的片名", MediaType: metadata.MediaMovie}}}, entity.ResourceMetadataNotFound, false, ""},
+		{"provider error", &fakeMetadataProvider{searchErr: errors.New("403")}, entity.ResourceMetadataFailed, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestMetadataService(tt.provider, &fakeMetadataRepo{})
+			m, err := svc.Resolve(context.Background(), "繁花.2023.4K.全30集", 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if m.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", m.Status, tt.wantStatus)
+			}
+			if m.MatchQuery != "繁花" {
+				t.Errorf("match query = %q", m.MatchQuery)
+			}
+			if m.Genres != tt.wantGenres {
+				t.Errorf("genres = %q, want %q", m.Genres, tt.wantGenres)
+			}
+			if tt.wantStatus == entity.ResourceMetadataMatched && (m.SubjectID != "35267208" || m.Year != 2023) {
+				t.Errorf("unexpected subject: %+v", m)
+			}
+		})
+	}
+}
+
+func TestMetadataSaveSchedulesRefresh(t *testing.T) {
+	r := &fakeMetadataRepo{}
+	svc := newTestMetadataService(&fakeMetadataProvider{}, r)
+
+	for _, tt := range []struct {
+		status string
+		want   time.Duration
+	}{
+		{entity.ResourceMetadataMatched, time.Duration(entity.MetadataConfigDefaultRefreshDays) * 24 * time.Hour},
+		{entity.ResourceMetadataNotFound, time.Duration(entity.MetadataConfigDefaultRefreshDays) * 24 * time.Hour},
+		{entity.ResourceMetadataFailed, metadataFailedRetry},
+	} {
+		if err := svc.Save(7, &entity.ResourceMetadata{Status: tt.status, Error: "x"}); err != nil {
+			t.Fatal(err)
+		}
+		saved := r.byResource[7]
+		if saved.NextRefreshAt == nil || !saved.NextRefreshAt.Equal(metadataTestNow.Add(tt.want)) {
+			t.Errorf("%s: next refresh = %v", tt.status, saved.NextRefreshAt)
+		}
+		if (saved.Error != "") != (tt.status == entity.ResourceMetadataFailed) {
+			t.Errorf("%s: error = %q", tt.status, saved.Error)
+		}
+	}
+}
+
+func TestMetadataEnrichExisting(t *testing.T) {
+	updated := &metadata.Subject{Provider: metadata.ProviderDouban, ID: "1", MediaType: metadata.MediaTV, Title: "繁花", Year: 2023, Rating: 8.3, Episodes: 30}
+
+	tests := []struct {
+		name         string
+		status       string
+		force        bool
+		wantSearches int
+		wantStatus   string
+	}{
+		{"matched refreshes detail", entity.ResourceMetadataMatched, false, 0, entity.ResourceMetadataMatched},
+		{"manual is never re-matched", entity.ResourceMetadataManual, true, 0, entity.ResourceMetadataManual},
+		{"forced re-match", entity.ResourceMetadataMatched, true, 1, entity.ResourceMetadataNotFound},
+		{"not found is re-matched", entity.ResourceMetadataNotFound, false, 1, entity.ResourceMetadataNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &fakeMetadataProvider{detail: updated}
+			r := &fakeMetadataRepo{byResource: map[uint]*entity.ResourceMetadata{
+				3: {ID: 9, ResourceID: 3, Provider: metadata.ProviderDouban, SubjectID: "1", MediaType: metadata.MediaTV, Rating: 7.9, Status: tt.status},
+			}}
+			svc := newTestMetadataService(p, r)
+
+			m, err := svc.Enrich(context.Background(), &entity.Resource{ID: 3, Title: "繁花 4K"}, tt.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.searches != tt.wantSearches {
+				t.Errorf("searches = %d, want %d", p.searches, tt.wantSearches)
+			}
+			if m.Status != tt.wantStatus || m.ID != 9 {
+				t.Errorf("status = %q id = %d, want %q id 9", m.Status, m.ID, tt.wantStatus)
+			}
+			if tt.wantSearches == 0 && r.byResource[3].Rating != 8.3 {
+				t.Errorf("rating not refreshed: %v", r.byResource[3].Rating)
+			}
+		})
+	}
+}
+
+func TestMetadataEnrichKeepsSubjectOnFailure(t *testing.T) {
+	p := &fakeMetadataProvider{detailErr: errors.New("429")}
+	r := &fakeMetadataRepo{byResource: map[uint]*entity.ResourceMetadata{
+		3: {ResourceID: 3, Provider: metadata.ProviderDouban, SubjectID: "1", Title: "繁花", Rating: 7.9, Status: entity.ResourceMetadataMatched},
+	}}
+	svc := newTestMetadataService(p, r)
+
+	if _, err := svc.Enrich(context.Background(), &entity.Resource{ID: 3, Title: "繁花"}, false); err == nil {
+		t.Fatal("expected error")
+	}
+	saved := r.byResource[3]
+	if saved.Status != entity.ResourceMetadataMatched || saved.Rating != 7.9 || saved.Error == "" {
+		t.Errorf("unexpected saved metadata: %+v", saved)
+	}
+	if saved.NextRefreshAt == nil || !saved.NextRefreshAt.Equal(metadataTestNow.Add(metadataFailedRetry)) {
+		t.Errorf("next refresh = %v", saved.NextRefreshAt)
+	}
+}
+
+func TestMetadataSummaryUnmatched(t *testing.T) {
+	if got := MetadataSummary(nil); got != "" {
+		t.Errorf("nil summary = %q", got)
+	}
+	if got := MetadataSummary(&entity.ResourceMetadata{Status: entity.ResourceMetadataNotFound, Year: 2023}); got != "" {
+		t.Errorf("not found summary = %q", got)
+	}
+	m := &entity.ResourceMetadata{Provider: metadata.ProviderDouban, Status: entity.ResourceMetadataManual, Year: 2023, Regions: "中国大陆", Genres: "剧情,爱情", Rating: 8.3}
+	if got := MetadataSummary(m); got != "2023 · 中国大陆 · 剧情/爱情 · 豆瓣 8.3" {
+		t.Errorf("summary = %q", got)
+	}
+}
@@ -0,0 +1,506 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/utils"
+)
+
+// 举报升级阈值上限
+const moderationMaxEscalationThreshold = 1000
+
+var (
+	// ErrModerationAlreadyApplied 来源已有生效中的处置，需先撤销
+	ErrModerationAlreadyApplied = errors.New("该记录已有生效中的处置，请先撤销")
+	// ErrModerationAlreadyReverted 处置已撤销
+	ErrModerationAlreadyReverted = errors.New("该处置已撤销")
+)
+
+// ModerationIndex 搜索索引操作，由 MeilisearchManager 实现
+type ModerationIndex interface {
+	IsEnabled() bool
+	SyncResourceToMeilisearch(resource *entity.Resource) error
+	DeleteResourceFromMeilisearch(resourceID uint) error
+}
+
+// TransferFileDeleter 删除转存到网盘账号中的文件，由 CleanupService 实现
+type TransferFileDeleter interface {
+	DeleteTransferredFile(res *entity.Resource) error
+}
+
+// ModerationBlocklist 处置生效期间禁止资源重新入库，由 BlocklistService 实现
+type ModerationBlocklist interface {
+	AddModerationRules(actionID uint, resources []entity.Resource, blockTitle bool, operatorID uint) error
+	RemoveModerationRules(actionID uint) error
+}
+
+// ModerationNotifier 处置结果与举报升级的管理员通知渠道（Telegram 管理员会话）
+type ModerationNotifier interface {
+	NotifyAdmin(text string) error
+}
+
+// ClaimantMailer 向版权申述人发送处理结果的邮件渠道，由 SMTPMailer 实现
+type ClaimantMailer interface {
+	SendMail(to, subject, body string) error
+}
+
+// ModerationDecision 管理员对举报或版权申述的处理决定
+type ModerationDecision struct {
+	Status      string // approved / rejected / pending
+	Note        string // 处理备注，申述人可通过申述详情查看
+	Action      string // 通过时对资源的处置：hide / delete，空表示只更新状态
+	DeleteFiles bool   // 是否同时删除转存到网盘账号中的文件（不可撤销）
+	BlockTitle  bool   // 是否同时禁止相同标题重新入库（链接始终禁止）
+}
+
+// ModerationService 举报与版权申述处置：下架/删除同组资源、移出搜索索引、删除转存文件、
+// 禁止重新入库、举报自动升级、处理结果通知，以及撤销处置
+type ModerationService struct {
+	reportRepo   repo.ReportRepository
+	claimRepo    repo.CopyrightClaimRepository
+	resourceRepo repo.ResourceRepository
+	actionRepo   repo.ModerationActionRepository
+	blocklist    ModerationBlocklist
+	configRepo   repo.SystemConfigRepository
+	index        ModerationIndex
+	files        TransferFileDeleter
+	notifier     ModerationNotifier
+	mailer       ClaimantMailer
+	now          func() time.Time
+}
+
+// NewModerationService 创建内容处置服务
+func NewModerationService(
+	reportRepo repo.ReportRepository,
+	claimRepo repo.CopyrightClaimRepository,
+	resourceRepo repo.ResourceRepository,
+	actionRepo repo.ModerationActionRepository,
+	blocklist ModerationBlocklist,
+	configRepo repo.SystemConfigRepository,
+) *ModerationService {
+	return &ModerationService{
+		reportRepo:   reportRepo,
+		claimRepo:    claimRepo,
+		resourceRepo: resourceRepo,
+		actionRepo:   actionRepo,
+		blocklist:    blocklist,
+		configRepo:   configRepo,
+		now:          utils.GetCurrentTime,
+	}
+}
+
+// SetIndex 设置搜索索引，未设置时跳过索引同步
+func (s *ModerationService) SetIndex(index ModerationIndex) {
+	s.index = index
+}
+
+// SetFileDeleter 设置转存文件删除实现，未设置时无法删除转存文件
+func (s *ModerationService) SetFileDeleter(files TransferFileDeleter) {
+	s.files = files
+}
+
+// SetNotifier 设置管理员通知渠道
+func (s *ModerationService) SetNotifier(notifier ModerationNotifier) {
+	s.notifier = notifier
+}
+
+// SetMailer 设置申述人邮件通知渠道，未设置时由管理员人工联系申述人
+func (s *ModerationService) SetMailer(mailer ClaimantMailer) {
+	s.mailer = mailer
+}
+
+// ModerateClaim 处理版权申述。通过时默认将同组资源设为不公开；
+// 已通过的申述改为驳回或待处理时，自动撤销其生效中的处置
+func (s *ModerationService) ModerateClaim(id uint, d ModerationDecision, operatorID uint) (*entity.CopyrightClaim, *entity.ModerationAction, error) {
+	claim, err := s.claimRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.Status == entity.ModerationStatusApproved && d.Action == "" {
+		d.Action = entity.ModerationActionHide
+	}
+
+	action, err := s.moderate(entity.ModerationSourceCopyrightClaim, claim.ID, claim.ResourceKey, d, operatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.claimRepo.UpdateStatus(claim.ID, d.Status, &operatorID, d.Note); err != nil {
+		return nil, action, err
+	}
+
+	updated, err := s.claimRepo.GetByID(claim.ID)
+	if err != nil {
+		return nil, action, err
+	}
+	s.notifyClaimant(updated, action)
+	return updated, action, nil
+}
+
+// ModerateReport 处理举报，Action 为空时只更新状态
+func (s *ModerationService) ModerateReport(id uint, d ModerationDecision, operatorID uint) (*entity.Report, *entity.ModerationAction, error) {
+	report, err := s.reportRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	action, err := s.moderate(entity.ModerationSourceReport, report.ID, report.ResourceKey, d, operatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.reportRepo.UpdateStatus(report.ID, d.Status, &operatorID, d.Note); err != nil {
+		return nil, action, err
+	}
+
+	updated, err := s.reportRepo.GetByID(report.ID)
+	if err != nil {
+		return nil, action, err
+	}
+	eventbus.Publish(eventbus.TypeModerationResolved, map[string]interface{}{
+		"source_type":  entity.ModerationSourceReport,
+		"source_id":    updated.ID,
+		"resource_key": updated.ResourceKey,
+		"status":       updated.Status,
+		"action":       actionName(action),
+		"note":         updated.Note,
+	})
+	return updated, action, nil
+}
+
+// moderate 按决定执行或撤销处置，返回新建的处置记录（没有新处置时为 nil）
+func (s *ModerationService) moderate(sourceType string, sourceID uint, resourceKey string, d ModerationDecision, operatorID uint) (*entity.ModerationAction, error) {
+	active, err := s.actionRepo.FindActiveBySource(sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Status != entity.ModerationStatusApproved {
+		for i := range active {
+			if err := s.revert(&active[i], operatorID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	if d.Action == "" {
+		return nil, nil
+	}
+	if len(active) > 0 {
+		return nil, ErrModerationAlreadyApplied
+	}
+	return s.takeDown(sourceType, sourceID, resourceKey, d, operatorID)
+}
+
+// takeDown 对资源组执行处置：删除转存文件、下架或软删除、移出搜索索引并禁止重新入库
+func (s *ModerationService) takeDown(sourceType string, sourceID uint, resourceKey string, d ModerationDecision, operatorID uint) (*entity.ModerationAction, error) {
+	if d.Action != entity.ModerationActionHide && d.Action != entity.ModerationActionDelete {
+		return nil, fmt.Errorf("不支持的处置方式: %s", d.Action)
+	}
+	resources, err := s.resourceRepo.FindByResourceKey(resourceKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		utils.Warn("Moderation - 资源组下没有可处置的资源，仅更新状态 - Key: %s", resourceKey)
+		return nil, nil
+	}
+
+	action := &entity.ModerationAction{
+		SourceType:  sourceType,
+		SourceID:    sourceID,
+		ResourceKey: resourceKey,
+		Action:      d.Action,
+		BlockTitle:  d.BlockTitle,
+		DeleteFiles: d.DeleteFiles,
+		Note:        d.Note,
+		OperatorID:  operatorID,
+	}
+	ids := make([]uint, 0, len(resources))
+	var publicIDs []uint
+	for _, res := range resources {
+		ids = append(ids, res.ID)
+		if res.IsPublic {
+			publicIDs = append(publicIDs, res.ID)
+		}
+	}
+	action.SetResourceIDList(ids)
+	action.SetHiddenIDList(publicIDs)
+	if err := s.actionRepo.Create(action); err != nil {
+		return nil, err
+	}
+
+	// 软删除后无法再更新转存字段，需先删除转存文件
+	if d.DeleteFiles {
+		action.FilesDeleted, action.FileErrors = s.deleteFiles(resources)
+	}
+
+	for _, res := range resources {
+		switch d.Action {
+		case entity.ModerationActionHide:
+			if res.IsPublic {
+				err = s.resourceRepo.UpdateFields(res.ID, map[string]interface{}{"is_public": false})
+			}
+		case entity.ModerationActionDelete:
+			err = s.resourceRepo.Delete(res.ID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("处置资源 %d 失败: %v", res.ID, err)
+		}
+		if s.index != nil && s.index.IsEnabled() {
+			if err := s.index.DeleteResourceFromMeilisearch(res.ID); err != nil {
+				utils.Warn("Moderation - 移出搜索索引失败 - 资源ID: %d, Error: %v", res.ID, err)
+			}
+		}
+	}
+	_ = s.resourceRepo.InvalidateCache()
+
+	if err := s.blocklist.AddModerationRules(action.ID, resources, d.BlockTitle, operatorID); err != nil {
+		return nil, fmt.Errorf("添加黑名单规则失败: %v", err)
+	}
+	if err := s.actionRepo.Save(action); err != nil {
+		return nil, err
+	}
+
+	utils.Info("Moderation - 已处置资源 - 来源: %s#%d, Key: %s, 方式: %s, 资源数: %d, 删除文件: %d",
+		sourceType, sourceID, resourceKey, d.Action, len(resources), action.FilesDeleted)
+	return action, nil
+}
+
+// deleteFiles 删除资源的转存文件，返回成功数与失败信息
+func (s *ModerationService) deleteFiles(resources []entity.Resource) (int, string) {
+	if s.files == nil {
+		return 0, "未配置转存文件删除服务"
+	}
+	deleted := 0
+	var failures []string
+	for i := range resources {
+		if resources[i].Fid == "" {
+			continue
+		}
+		if err := s.files.DeleteTransferredFile(&resources[i]); err != nil {
+			failures = append(failures, fmt.Sprintf("资源%d: %v", resources[i].ID, err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, strings.Join(failures, "\n")
+}
+
+// Revert 撤销处置：恢复资源公开状态或取消删除、重新同步搜索索引并解除入库限制；
+// 已删除的转存文件无法恢复。来源没有其他生效中的处置时恢复为待处理
+func (s *ModerationService) Revert(actionID, operatorID uint) (*entity.ModerationAction, error) {
+	action, err := s.actionRepo.FindByID(actionID)
+	if err != nil {
+		return nil, err
+	}
+	if action.Reverted {
+		return nil, ErrModerationAlreadyReverted
+	}
+	if err := s.revert(action, operatorID); err != nil {
+		return nil, err
+	}
+
+	active, err := s.actionRepo.FindActiveBySource(action.SourceType, action.SourceID)
+	if err == nil && len(active) == 0 {
+		note := fmt.Sprintf("处置 #%d 已撤销", action.ID)
+		switch action.SourceType {
+		case entity.ModerationSourceCopyrightClaim:
+			err = s.claimRepo.UpdateStatus(action.SourceID, entity.ModerationStatusPending, &operatorID, note)
+		case entity.ModerationSourceReport:
+			err = s.reportRepo.UpdateStatus(action.SourceID, entity.ModerationStatusPending, &operatorID, note)
+		}
+	}
+	if err != nil {
+		utils.Warn("Moderation - 撤销后恢复来源状态失败 - 来源: %s#%d, Error: %v", action.SourceType, action.SourceID, err)
+	}
+	return action, nil
+}
+
+func (s *ModerationService) revert(action *entity.ModerationAction, operatorID uint) error {
+	ids := action.ResourceIDList()
+	switch action.Action {
+	case entity.ModerationActionDelete:
+		if err := s.resourceRepo.Restore(ids); err != nil {
+			return fmt.Errorf("恢复资源失败: %v", err)
+		}
+	case entity.ModerationActionHide:
+		for _, id := range action.HiddenIDList() {
+			if err := s.resourceRepo.UpdateFields(id, map[string]interface{}{"is_public": true}); err != nil {
+				return fmt.Errorf("恢复资源 %d 公开状态失败: %v", id, err)
+			}
+		}
+	}
+	if err := s.blocklist.RemoveModerationRules(action.ID); err != nil {
+		return fmt.Errorf("解除入库限制失败: %v", err)
+	}
+	_ = s.resourceRepo.InvalidateCache()
+
+	if s.index != nil && s.index.IsEnabled() {
+		resources, err := s.resourceRepo.FindByIDs(ids)
+		if err != nil {
+			utils.Warn("Moderation - 撤销后加载资源失败 - 处置ID: %d, Error: %v", action.ID, err)
+		}
+		for i := range resources {
+			if err := s.index.SyncResourceToMeilisearch(&resources[i]); err != nil {
+				utils.Warn("Moderation - 撤销后同步搜索索引失败 - 资源ID: %d, Error: %v", resources[i].ID, err)
+			}
+		}
+	}
+
+	now := s.now()
+	if err := s.actionRepo.MarkReverted(action.ID, operatorID, now); err != nil {
+		return err
+	}
+	action.Reverted = true
+	action.RevertedAt = &now
+	action.RevertedBy = operatorID
+	utils.Info("Moderation - 已撤销处置 - ID: %d, 来源: %s#%d", action.ID, action.SourceType, action.SourceID)
+	return nil
+}
+
+// ListActions 分页查询处置记录
+func (s *ModerationService) ListActions(sourceType string, sourceID uint, resourceKey string, page, pageSize int) ([]entity.ModerationAction, int64, error) {
+	return s.actionRepo.Search(sourceType, sourceID, resourceKey, page, pageSize)
+}
+
+// HandleReportCreated 新举报后检查同一资源的待处理举报数，达到阈值时升级并通知管理员
+func (s *ModerationService) HandleReportCreated(report *entity.Report) {
+	threshold := s.EscalationThreshold()
+	if threshold <= 0 {
+		return
+	}
+	count, err := s.reportRepo.CountByResourceKey(report.ResourceKey, entity.ModerationStatusPending, entity.ModerationStatusEscalated)
+	if err != nil {
+		utils.Warn("Moderation - 统计举报数失败 - Key: %s, Error: %v", report.ResourceKey, err)
+		return
+	}
+	if count < int64(threshold) {
+		return
+	}
+	escalated, err := s.reportRepo.UpdateStatusByResourceKey(report.ResourceKey, entity.ModerationStatusPending, entity.ModerationStatusEscalated)
+	if err != nil {
+		utils.Warn("Moderation - 升级举报失败 - Key: %s, Error: %v", report.ResourceKey, err)
+		return
+	}
+	report.Status = entity.ModerationStatusEscalated
+
+	// 仅在首次达到阈值时通知，之后的举报直接标记为已升级
+	if count != int64(threshold) {
+		return
+	}
+	utils.Info("Moderation - 举报已升级 - Key: %s, 待处理举报: %d", report.ResourceKey, count)
+	eventbus.Publish(eventbus.TypeReportEscalated, map[string]interface{}{
+		"resource_key": report.ResourceKey,
+		"count":        count,
+		"escalated":    escalated,
+	})
+	if s.notifier != nil {
+		text := fmt.Sprintf("⚠️ 举报升级\n资源 %s 已累计 %d 条待处理举报，最新原因：%s", report.ResourceKey, count, report.Reason)
+		if err := s.notifier.NotifyAdmin(text); err != nil {
+			utils.Warn("Moderation - 发送举报升级通知失败: %v", err)
+		}
+	}
+}
+
+// notifyClaimant 发布申述处理结果，并按申述人留下的邮箱发送处理结果。
+// 联系方式只用于通知申述人，不随事件推送给插件；未留邮箱或发送失败时提醒管理员人工联系
+func (s *ModerationService) notifyClaimant(claim *entity.CopyrightClaim, action *entity.ModerationAction) {
+	eventbus.Publish(eventbus.TypeModerationResolved, map[string]interface{}{
+		"source_type":  entity.ModerationSourceCopyrightClaim,
+		"source_id":    claim.ID,
+		"resource_key": claim.ResourceKey,
+		"status":       claim.Status,
+		"action":       actionName(action),
+		"note":         claim.Note,
+	})
+	if claim.Status == entity.ModerationStatusPending {
+		return
+	}
+
+	result := "已驳回"
+	if claim.Status == entity.ModerationStatusApproved {
+		result = "已通过"
+	}
+	notified := false
+	if email, ok := claimantEmail(claim.ContactInfo); ok && s.mailer != nil {
+		subject := fmt.Sprintf("版权申述 #%d 处理结果：%s", claim.ID, result)
+		body := fmt.Sprintf("%s，您好：\n\n您提交的版权申述 #%d（资源 %s）%s。\n处理说明：%s\n",
+			claim.ClaimantName, claim.ID, claim.ResourceKey, result, claim.Note)
+		if err := s.mailer.SendMail(email, subject, body); err != nil {
+			utils.Warn("Moderation - 发送申述结果邮件失败 - 申述ID: %d, Error: %v", claim.ID, err)
+		} else {
+			notified = true
+		}
+	}
+	if s.notifier == nil {
+		return
+	}
+
+	text := fmt.Sprintf("📮 版权申述 #%d %s\n已通过邮件通知申述人 %s\n处理说明：%s", claim.ID, result, claim.ClaimantName, claim.Note)
+	if !notified {
+		text = fmt.Sprintf("📮 版权申述 #%d %s\n未能通过邮件通知申述人，请通过申述中的联系方式人工联系 %s\n处理说明：%s", claim.ID, result, claim.ClaimantName, claim.Note)
+	}
+	if err := s.notifier.NotifyAdmin(text); err != nil {
+		utils.Warn("Moderation - 发送申述处理通知失败: %v", err)
+	}
+}
+
+// claimantEmailPattern 联系信息中的邮箱地址
+var claimantEmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// claimantEmail 从申述人填写的联系信息中提取邮箱，联系信息可能是邮箱、电话或二者混写
+func claimantEmail(contact string) (string, bool) {
+	addr, err := mail.ParseAddress(claimantEmailPattern.FindString(contact))
+	if err != nil {
+		return "", false
+	}
+	return addr.Address, true
+}
+
+func actionName(action *entity.ModerationAction) string {
+	if action == nil {
+		return ""
+	}
+	return action.Action
+}
+
+// EscalationThreshold 同一资源待处理举报的升级阈值，0 表示不升级
+func (s *ModerationService) EscalationThreshold() int {
+	if s.configRepo != nil {
+		if v, err := s.configRepo.GetConfigInt(entity.ModerationConfigKeyEscalationThreshold); err == nil && v >= 0 {
+			return v
+		}
+	}
+	return entity.ModerationConfigDefaultEscalationThreshold
+}
+
+// SaveEscalationThreshold 保存举报升级阈值
+func (s *ModerationService) SaveEscalationThreshold(threshold int) error {
+	if threshold < 0 || threshold > moderationMaxEscalationThreshold {
+		return fmt.Errorf("升级阈值必须在0-%d之间", moderationMaxEscalationThreshold)
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.ModerationConfigKeyEscalationThreshold, Value: strconv.Itoa(threshold), Type: entity.ConfigTypeInt},
+	})
+}
+
+// 默认实例：在 main.go 中通过 SetDefaultModerationService 注入
+var defaultModerationService *ModerationService
+
+// SetDefaultModerationService 设置默认内容处置服务实例
+func SetDefaultModerationService(s *ModerationService) {
+	defaultModerationService = s
+}
+
+// GetDefaultModerationService 获取默认内容处置服务实例，未初始化时为 nil
+func GetDefaultModerationService() *ModerationService {
+	return defaultModerationService
+}
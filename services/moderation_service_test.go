@@ -0,0 +1,389 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
+
+	"gorm.io/gorm"
+)
+
+type fakeModerationResourceRepo struct {
+	repo.ResourceRepository
+	resources map[uint]*entity.Resource
+	deleted   map[uint]bool
+}
+
+func (f *fakeModerationResourceRepo) FindByResourceKey(key string) ([]entity.Resource, error) {
+	var list []entity.Resource
+	for id := uint(1); id <= uint(len(f.resources)); id++ {
+		if res := f.resources[id]; res != nil && res.Key == key && !f.deleted[id] {
+			list = append(list, *res)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeModerationResourceRepo) FindByIDs(ids []uint) ([]entity.Resource, error) {
+	var list []entity.Resource
+	for _, id := range ids {
+		if res := f.resources[id]; res != nil && !f.deleted[id] {
+			list = append(list, *res)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeModerationResourceRepo) UpdateFields(id uint, fields map[string]interface{}) error {
+	if v, ok := fields["is_public"]; ok {
+		f.resources[id].IsPublic = v.(bool)
+	}
+	return nil
+}
+
+func (f *fakeModerationResourceRepo) Delete(id uint) error {
+	f.deleted[id] = true
+	return nil
+}
+
+func (f *fakeModerationResourceRepo) Restore(ids []uint) error {
+	for _, id := range ids {
+		delete(f.deleted, id)
+	}
+	return nil
+}
+
+func (f *fakeModerationResourceRepo) InvalidateCache() error { return nil }
+
+type fakeModerationActionRepo struct {
+	repo.ModerationActionRepository
+	actions []*entity.ModerationAction
+}
+
+func (f *fakeModerationActionRepo) Create(action *entity.ModerationAction) error {
+	action.ID = uint(len(f.actions) + 1)
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func (f *fakeModerationActionRepo) Save(action *entity.ModerationAction) error {
+	f.actions[action.ID-1] = action
+	return nil
+}
+
+func (f *fakeModerationActionRepo) FindByID(id uint) (*entity.ModerationAction, error) {
+	if id == 0 || int(id) > len(f.actions) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *f.actions[id-1]
+	return &copied, nil
+}
+
+func (f *fakeModerationActionRepo) FindActiveBySource(sourceType string, sourceID uint) ([]entity.ModerationAction, error) {
+	var list []entity.ModerationAction
+	for _, a := range f.actions {
+		if a.SourceType == sourceType && a.SourceID == sourceID && !a.Reverted {
+			list = append(list, *a)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeModerationActionRepo) MarkReverted(id, revertedBy uint, revertedAt time.Time) error {
+	f.actions[id-1].Reverted = true
+	f.actions[id-1].RevertedBy = revertedBy
+	f.actions[id-1].RevertedAt = &revertedAt
+	return nil
+}
+
+type fakeModerationClaimRepo struct {
+	repo.CopyrightClaimRepository
+	claims map[uint]*entity.CopyrightClaim
+}
+
+func (f *fakeModerationClaimRepo) GetByID(id uint) (*entity.CopyrightClaim, error) {
+	claim, ok := f.claims[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *claim
+	return &copied, nil
+}
+
+func (f *fakeModerationClaimRepo) UpdateStatus(id uint, status string, _ *uint, note string) error {
+	f.claims[id].Status = status
+	f.claims[id].Note = note
+	return nil
+}
+
+type fakeModerationReportRepo struct {
+	repo.ReportRepository
+	reports map[uint]*entity.Report
+}
+
+func (f *fakeModerationReportRepo) GetByID(id uint) (*entity.Report, error) {
+	report, ok := f.reports[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *report
+	return &copied, nil
+}
+
+func (f *fakeModerationReportRepo) UpdateStatus(id uint, status string, _ *uint, note string) error {
+	f.reports[id].Status = status
+	f.reports[id].Note = note
+	return nil
+}
+
+func (f *fakeModerationReportRepo) CountByResourceKey(resourceKey string, statuses ...string) (int64, error) {
+	var count int64
+	for _, r := range f.reports {
+		for _, status := range statuses {
+			if r.ResourceKey == resourceKey && r.Status == status {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeModerationReportRepo) UpdateStatusByResourceKey(resourceKey, fromStatus, toStatus string) (int64, error) {
+	var updated int64
+	for _, r := range f.reports {
+		if r.ResourceKey == resourceKey && r.Status == fromStatus {
+			r.Status = toStatus
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+type fakeModerationIndex struct {
+	deleted []uint
+	synced  []uint
+}
+
+func (f *fakeModerationIndex) IsEnabled() bool { return true }
+
+func (f *fakeModerationIndex) SyncResourceToMeilisearch(resource *entity.Resource) error {
+	f.synced = append(f.synced, resource.ID)
+	return nil
+}
+
+func (f *fakeModerationIndex) DeleteResourceFromMeilisearch(resourceID uint) error {
+	f.deleted = append(f.deleted, resourceID)
+	return nil
+}
+
+type fakeTransferFiles struct {
+	fail map[uint]bool
+}
+
+func (f *fakeTransferFiles) DeleteTransferredFile(res *entity.Resource) error {
+	if f.fail[res.ID] {
+		return errors.New("账号已失效")
+	}
+	return nil
+}
+
+type fakeModerationEnv struct {
+	svc       *ModerationService
+	resources *fakeModerationResourceRepo
+	actions   *fakeModerationActionRepo
+	rules     *fakeBlocklistRuleRepo
+	blocklist *BlocklistService
+	claims    *fakeModerationClaimRepo
+	reports   *fakeModerationReportRepo
+	index     *fakeModerationIndex
+}
+
+func newTestModerationService() *fakeModerationEnv {
+	env := &fakeModerationEnv{
+		resources: &fakeModerationResourceRepo{
+			resources: map[uint]*entity.Resource{
+				1: {ID: 1, Key: "k1", Title: "某电影 4K", URL: "https://pan.quark.cn/s/aaa", IsPublic: true, Fid: "f1"},
+				2: {ID: 2, Key: "k1", Title: "某电影 4K", URL: "https://pan.baidu.com/s/bbb", IsPublic: false, Fid: "f2"},
+				3: {ID: 3, Key: "k2", Title: "其他资源", URL: "https://pan.quark.cn/s/ccc", IsPublic: true},
+			},
+			deleted: map[uint]bool{},
+		},
+		actions: &fakeModerationActionRepo{},
+		rules:   &fakeBlocklistRuleRepo{},
+		claims: &fakeModerationClaimRepo{claims: map[uint]*entity.CopyrightClaim{
+			7: {ID: 7, ResourceKey: "k1", ClaimantName: "版权方", ContactInfo: "legal@example.com", Status: entity.ModerationStatusPending},
+		}},
+		reports: &fakeModerationReportRepo{reports: map[uint]*entity.Report{}},
+		index:   &fakeModerationIndex{},
+	}
+	env.blocklist = NewBlocklistService(env.rules)
+	env.svc = NewModerationService(env.reports, env.claims, env.resources, env.actions, env.blocklist, nil)
+	env.svc.SetIndex(env.index)
+	env.svc.SetFileDeleter(&fakeTransferFiles{fail: map[uint]bool{2: true}})
+	env.svc.now = func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) }
+	return env
+}
+
+func TestModerateClaimHidesResourceGroupAndRejectReverts(t *testing.T) {
+	env := newTestModerationService()
+
+	claim, action, err := env.svc.ModerateClaim(7, ModerationDecision{Status: entity.ModerationStatusApproved, Note: "权利证明有效", DeleteFiles: true, BlockTitle: true}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Status != entity.ModerationStatusApproved || action == nil || action.Action != entity.ModerationActionHide {
+		t.Fatalf("claim approval should default to hide: %+v %+v", claim, action)
+	}
+	if env.resources.resources[1].IsPublic || env.resources.resources[3].IsPublic != true {
+		t.Fatal("only resources sharing the claim key should be hidden")
+	}
+	if len(env.index.deleted) != 2 {
+		t.Fatalf("both resources must be removed from the index, got %v", env.index.deleted)
+	}
+	if action.FilesDeleted != 1 || !strings.Contains(action.FileErrors, "资源2") {
+		t.Fatalf("unexpected file deletion result: %d %q", action.FilesDeleted, action.FileErrors)
+	}
+
+	// 链接与规范化后的标题均禁止重新入库
+	if reason := env.blocklist.CheckReject(BlocklistTarget{URL: "https://pan.quark.cn/s/aaa?pwd=1234"}); reason == "" {
+		t.Fatal("url should be blocked")
+	}
+	if reason := env.blocklist.CheckReject(BlocklistTarget{URL: "https://pan.xunlei.com/s/new", Title: "  某电影   4k "}); reason == "" {
+		t.Fatal("title should be blocked")
+	}
+	if reason := env.blocklist.CheckReject(BlocklistTarget{URL: "https://pan.quark.cn/s/ccc", Title: "其他资源"}); reason != "" {
+		t.Fatalf("unrelated resource must not be blocked: %s", reason)
+	}
+
+	// 再次通过不会重复处置
+	if _, _, err := env.svc.ModerateClaim(7, ModerationDecision{Status: entity.ModerationStatusApproved}, 1); !errors.Is(err, ErrModerationAlreadyApplied) {
+		t.Fatalf("expected ErrModerationAlreadyApplied, got %v", err)
+	}
+
+	// 改为驳回：撤销处置，只恢复原本公开的资源
+	claim, _, err = env.svc.ModerateClaim(7, ModerationDecision{Status: entity.ModerationStatusRejected, Note: "复核后驳回"}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Status != entity.ModerationStatusRejected {
+		t.Fatalf("status = %s", claim.Status)
+	}
+	if !env.resources.resources[1].IsPublic || env.resources.resources[2].IsPublic {
+		t.Fatal("revert should restore the original visibility")
+	}
+	if len(env.rules.rules) != 0 || !env.actions.actions[0].Reverted || env.actions.actions[0].RevertedBy != 2 {
+		t.Fatalf("blocks should be lifted and the action marked reverted: %+v", env.actions.actions[0])
+	}
+	if len(env.index.synced) != 2 {
+		t.Fatalf("resources should be re-indexed, got %v", env.index.synced)
+	}
+}
+
+func TestModerateReportDeleteAndRevert(t *testing.T) {
+	env := newTestModerationService()
+	env.reports.reports[5] = &entity.Report{ID: 5, ResourceKey: "k1", Status: entity.ModerationStatusPending}
+
+	// 举报通过但未指定处置方式时只更新状态
+	report, action, err := env.svc.ModerateReport(5, ModerationDecision{Status: entity.ModerationStatusApproved}, 1)
+	if err != nil || action != nil || report.Status != entity.ModerationStatusApproved {
+		t.Fatalf("status-only approval: %+v %+v %v", report, action, err)
+	}
+
+	_, action, err = env.svc.ModerateReport(5, ModerationDecision{Status: entity.ModerationStatusApproved, Action: entity.ModerationActionDelete}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !env.resources.deleted[1] || !env.resources.deleted[2] || env.resources.deleted[3] {
+		t.Fatalf("unexpected deletions: %v", env.resources.deleted)
+	}
+	if action.DeleteFiles || action.FilesDeleted != 0 {
+		t.Fatal("files must not be deleted unless requested")
+	}
+
+	reverted, err := env.svc.Revert(action.ID, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reverted.Reverted || len(env.resources.deleted) != 0 {
+		t.Fatalf("resources should be restored: %v", env.resources.deleted)
+	}
+	if env.reports.reports[5].Status != entity.ModerationStatusPending {
+		t.Fatalf("report should return to pending, got %s", env.reports.reports[5].Status)
+	}
+	if _, err := env.svc.Revert(action.ID, 3); !errors.Is(err, ErrModerationAlreadyReverted) {
+		t.Fatalf("expected ErrModerationAlreadyReverted, got %v", err)
+	}
+}
+
+func TestHandleReportCreatedEscalatesOnce(t *testing.T) {
+	env := newTestModerationService()
+	notifier := &fakeLoginNotifier{messages: make(chan string, 4)}
+	env.svc.SetNotifier(notifier)
+
+	for id := uint(1); id <= 4; id++ {
+		report := &entity.Report{ID: id, ResourceKey: "k1", Reason: "链接失效", Status: entity.ModerationStatusPending}
+		env.reports.reports[id] = report
+		env.svc.HandleReportCreated(report)
+
+		wantEscalated := id >= entity.ModerationConfigDefaultEscalationThreshold
+		if got := env.reports.reports[1].Status == entity.ModerationStatusEscalated; got != wantEscalated {
+			t.Fatalf("after %d reports escalated = %v, want %v", id, got, wantEscalated)
+		}
+	}
+	if env.reports.reports[4].Status != entity.ModerationStatusEscalated {
+		t.Fatal("reports after the threshold should be escalated immediately")
+	}
+	if len(notifier.messages) != 1 {
+		t.Fatalf("admin should be notified once, got %d", len(notifier.messages))
+	}
+}
+
+type fakeClaimantMailer struct {
+	to, subject, body string
+	err               error
+}
+
+func (f *fakeClaimantMailer) SendMail(to, subject, body string) error {
+	f.to, f.subject, f.body = to, subject, body
+	return f.err
+}
+
+func TestModerateClaimMailsClaimantWithoutPublishingContact(t *testing.T) {
+	env := newTestModerationService()
+	env.claims.claims[7].ContactInfo = "电话 13800000000，邮箱 legal@example.com"
+	mailer := &fakeClaimantMailer{}
+	env.svc.SetMailer(mailer)
+	notifier := &fakeLoginNotifier{messages: make(chan string, 4)}
+	env.svc.SetNotifier(notifier)
+	sub, _ := eventbus.Default.Subscribe(eventbus.ParseFilter(eventbus.TypeModerationResolved), 4, 0)
+	defer sub.Close()
+
+	if _, _, err := env.svc.ModerateClaim(7, ModerationDecision{Status: entity.ModerationStatusRejected, Note: "证明材料不足"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if mailer.to != "legal@example.com" || !strings.Contains(mailer.subject, "已驳回") || !strings.Contains(mailer.body, "证明材料不足") {
+		t.Fatalf("claimant mail = %+v", mailer)
+	}
+	if msg := <-notifier.messages; strings.Contains(msg, "13800000000") || !strings.Contains(msg, "已通过邮件通知") {
+		t.Fatalf("admin message = %q", msg)
+	}
+	ev := <-sub.C()
+	if strings.Contains(string(ev.Data), "legal@example.com") || strings.Contains(string(ev.Data), "contact_info") {
+		t.Fatalf("event must not carry contact info: %s", ev.Data)
+	}
+
+	// 发送失败或未留邮箱时提醒管理员人工联系
+	mailer.err = errors.New("smtp down")
+	env.svc.notifyClaimant(env.claims.claims[7], nil)
+	if msg := <-notifier.messages; !strings.Contains(msg, "人工联系") {
+		t.Fatalf("admin message after mail failure = %q", msg)
+	}
+	if email, ok := claimantEmail("QQ 123456"); ok {
+		t.Fatalf("no email expected, got %q", email)
+	}
+}
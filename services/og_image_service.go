@@ -0,0 +1,478 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/ogimage"
+	"github.com/ctwj/urldb/utils"
+)
+
+// ErrOGImageResourceNotFound Key 下没有任何资源（含失效资源）
+var ErrOGImageResourceNotFound = errors.New("资源不存在")
+
+// ogImageDefaultSiteName 未配置站点名称时使用
+const ogImageDefaultSiteName = "老九网盘"
+
+// OGImageContent 绘制所需的文字内容与图片地址
+type OGImageContent struct {
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	Description string `json:"description"`
+	SiteName    string `json:"site_name"`
+	URL         string `json:"url"`
+	Cover       string `json:"cover"`
+}
+
+// OGImage 生成（或命中缓存）的图片
+type OGImage struct {
+	Data   []byte
+	Format string
+	ETag   string
+	Cached bool
+}
+
+// OGImageTemplateInfo 模板及其状态
+type OGImageTemplateInfo struct {
+	ogimage.Template
+	Version    string `json:"version"`
+	Builtin    bool   `json:"builtin"`    // 内置主题（未被自定义模板覆盖）
+	Overridden bool   `json:"overridden"` // 自定义模板覆盖了同名内置主题
+	Active     bool   `json:"active"`
+}
+
+// OGImageConfig OG图片配置
+type OGImageConfig struct {
+	ActiveTemplate   string                `json:"active_template"`
+	PrerenderEnabled bool                  `json:"prerender_enabled"`
+	Templates        []OGImageTemplateInfo `json:"templates"`
+	Layouts          []string              `json:"layouts"`   // 只读，可选布局
+	Positions        []string              `json:"positions"` // 只读，Logo/二维码可选位置
+}
+
+// ogImageLoader 加载封面与 Logo，便于测试替换
+type ogImageLoader interface {
+	Load(src string) (image.Image, error)
+}
+
+// OGImageService OG图片服务：按模板绘制资源分享图，结果按 Key + 模板版本缓存在磁盘，并为新资源预生成
+type OGImageService struct {
+	resourceRepo repo.ResourceRepository
+	configRepo   repo.SystemConfigRepository
+	cache        *ogimage.Cache
+	loader       ogImageLoader
+
+	// metadataFor 按 Key 查询影视元数据，未启用元数据服务时为 nil
+	metadataFor func(key string) *entity.ResourceMetadata
+	now         func() time.Time
+}
+
+// NewOGImageService 创建OG图片服务，cacheDir 为图片缓存目录，uploadDir 为本地上传目录
+func NewOGImageService(resourceRepo repo.ResourceRepository, configRepo repo.SystemConfigRepository, metadataService *MetadataService, cacheDir, uploadDir string) *OGImageService {
+	s := &OGImageService{
+		resourceRepo: resourceRepo,
+		configRepo:   configRepo,
+		cache:        ogimage.NewCache(cacheDir),
+		loader:       ogimage.NewImageLoader(cacheDir, uploadDir),
+		now:          utils.GetCurrentTime,
+	}
+	if metadataService != nil {
+		s.metadataFor = metadataService.ForKey
+	}
+	return s
+}
+
+// Config 读取配置与全部模板
+func (s *OGImageService) Config() OGImageConfig {
+	cfg := OGImageConfig{
+		ActiveTemplate:   entity.OGImageConfigDefaultActiveTemplate,
+		PrerenderEnabled: entity.OGImageConfigDefaultPrerenderEnabled,
+		Layouts:          ogimage.Layouts,
+		Positions:        ogimage.Positions,
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.OGImageConfigKeyActiveTemplate); err == nil && strings.TrimSpace(v) != "" {
+		cfg.ActiveTemplate = strings.TrimSpace(v)
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.OGImageConfigKeyPrerenderEnabled); err == nil && v != "" {
+		cfg.PrerenderEnabled = v == "true"
+	}
+
+	custom := s.customTemplates()
+	overridden := make(map[string]bool, len(custom))
+	for _, t := range custom {
+		overridden[t.Name] = true
+	}
+	builtin := make(map[string]bool)
+	for _, t := range ogimage.BuiltinTemplates() {
+		builtin[t.Name] = true
+		if !overridden[t.Name] {
+			cfg.Templates = append(cfg.Templates, OGImageTemplateInfo{Template: t, Version: t.Version(), Builtin: true})
+		}
+	}
+	for _, t := range custom {
+		cfg.Templates = append(cfg.Templates, OGImageTemplateInfo{Template: t, Version: t.Version(), Overridden: builtin[t.Name]})
+	}
+	for i := range cfg.Templates {
+		cfg.Templates[i].Active = cfg.Templates[i].Name == cfg.ActiveTemplate
+	}
+	return cfg
+}
+
+// SaveSettings 设置默认模板与是否预生成
+func (s *OGImageService) SaveSettings(activeTemplate string, prerenderEnabled bool) (OGImageConfig, error) {
+	activeTemplate = strings.TrimSpace(activeTemplate)
+	if _, ok := s.Template(activeTemplate); !ok {
+		return s.Config(), fmt.Errorf("模板不存在: %s", activeTemplate)
+	}
+	err := s.configRepo.UpsertConfigs([]entity.SystemConfig{
+		{Key: entity.OGImageConfigKeyActiveTemplate, Value: activeTemplate, Type: entity.ConfigTypeString},
+		{Key: entity.OGImageConfigKeyPrerenderEnabled, Value: strconv.FormatBool(prerenderEnabled), Type: entity.ConfigTypeBool},
+	})
+	if err != nil {
+		return s.Config(), err
+	}
+	return s.Config(), nil
+}
+
+// Template 按名称查找模板，自定义模板优先于同名内置主题
+func (s *OGImageService) Template(name string) (ogimage.Template, bool) {
+	for _, t := range s.customTemplates() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	for _, t := range ogimage.BuiltinTemplates() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return ogimage.Template{}, false
+}
+
+// SaveTemplate 校验并保存自定义模板，同名模板会被替换
+func (s *OGImageService) SaveTemplate(tpl ogimage.Template) (ogimage.Template, error) {
+	tpl = tpl.Normalize()
+	if err := tpl.Validate(); err != nil {
+		return tpl, err
+	}
+	custom := s.customTemplates()
+	replaced := false
+	for i := range custom {
+		if custom[i].Name == tpl.Name {
+			custom[i] = tpl
+			replaced = true
+		}
+	}
+	if !replaced {
+		custom = append(custom, tpl)
+	}
+	return tpl, s.saveCustomTemplates(custom)
+}
+
+// DeleteTemplate 删除自定义模板；删除覆盖内置主题的模板会恢复内置样式。
+// 正在使用的模板（且没有同名内置主题可回退）不能删除。
+func (s *OGImageService) DeleteTemplate(name string) error {
+	custom := s.customTemplates()
+	kept := custom[:0]
+	found := false
+	for _, t := range custom {
+		if t.Name == name {
+			found = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+	if !found {
+		return fmt.Errorf("自定义模板不存在: %s", name)
+	}
+	if s.Config().ActiveTemplate == name && !isBuiltinOGTemplate(name) {
+		return fmt.Errorf("模板正在使用中，请先切换默认模板")
+	}
+	return s.saveCustomTemplates(kept)
+}
+
+// Image 生成资源分享图，templateName 为空时使用默认模板；同一内容与模板版本只绘制一次
+func (s *OGImageService) Image(key, templateName, format, fallbackSiteURL string) (*OGImage, error) {
+	content, err := s.ContentForKey(key, fallbackSiteURL)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := s.resolveTemplate(templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := ogimage.Fingerprint(tpl, content.Title, content.Subtitle, content.Description, content.SiteName, content.URL, content.Cover)
+	etag := `"` + fingerprint + "." + format + `"`
+	if data, ok := s.cache.Get(key, fingerprint, format); ok {
+		return &OGImage{Data: data, Format: format, ETag: etag, Cached: true}, nil
+	}
+
+	data, err := s.render(tpl, content, format)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Put(key, fingerprint, format, data); err != nil {
+		utils.Warn("[OGImage] 写入缓存失败 key=%s: %v", key, err)
+	}
+	return &OGImage{Data: data, Format: format, ETag: etag}, nil
+}
+
+// Render 直接按给定内容绘制（不缓存），用于自定义参数与模板预览
+func (s *OGImageService) Render(tpl ogimage.Template, content OGImageContent, format string) (*OGImage, error) {
+	tpl = tpl.Normalize()
+	if err := tpl.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := s.render(tpl, content, format)
+	if err != nil {
+		return nil, err
+	}
+	return &OGImage{Data: data, Format: format}, nil
+}
+
+// ContentForKey 汇总资源标题、描述、封面与影视摘要；站点地址未配置时使用 fallbackSiteURL
+func (s *OGImageService) ContentForKey(key, fallbackSiteURL string) (OGImageContent, error) {
+	content := OGImageContent{SiteName: ogImageDefaultSiteName}
+	if v, err := s.configRepo.GetConfigValue(entity.ConfigKeySiteTitle); err == nil && strings.TrimSpace(v) != "" {
+		content.SiteName = strings.TrimSpace(v)
+	}
+	siteURL, _ := s.configRepo.GetConfigValue(entity.ConfigKeyWebsiteURL)
+	if siteURL = strings.TrimSpace(siteURL); siteURL == "" {
+		siteURL = fallbackSiteURL
+	}
+	if key == "" {
+		return content, nil
+	}
+	if siteURL != "" {
+		content.URL = ResourcePageURL(siteURL, key)
+	}
+
+	resources, err := s.resourceRepo.FindByKey(key)
+	if err != nil {
+		return content, err
+	}
+	if len(resources) == 0 {
+		failed, err := s.resourceRepo.FindFailedResourceMetaByKey(key)
+		if err != nil {
+			return content, err
+		}
+		if failed == nil {
+			return content, ErrOGImageResourceNotFound
+		}
+		content.Title, content.Cover = failed.Title, failed.Cover
+		return content, nil
+	}
+	for _, r := range resources {
+		if content.Title == "" {
+			content.Title = r.Title
+		}
+		if content.Description == "" {
+			content.Description = r.Description
+		}
+		if content.Cover == "" {
+			content.Cover = r.Cover
+		}
+	}
+
+	// 匹配到影视条目时补充摘要，没有封面则使用海报
+	if s.metadataFor != nil {
+		if m := s.metadataFor(key); m != nil {
+			content.Subtitle = MetadataSummary(m)
+			if content.Cover == "" {
+				content.Cover = m.PosterURL
+			}
+		}
+	}
+	return content, nil
+}
+
+// Invalidate 删除某个 Key 的缓存图片
+func (s *OGImageService) Invalidate(key string) error {
+	return s.cache.Invalidate(key)
+}
+
+// ClearCache 清空全部缓存图片
+func (s *OGImageService) ClearCache() error {
+	return s.cache.Clear()
+}
+
+// CacheStats 缓存统计
+func (s *OGImageService) CacheStats() (ogimage.CacheStats, error) {
+	return s.cache.Stats()
+}
+
+// Prerender 为游标之后新增或更新的资源预生成 PNG 与 WebP 图片，返回生成的 Key 数。
+// 首次运行只记录当前时间作为起点，不会为全部历史资源生成。
+// 图片中的资源页地址取决于网站地址，未配置时无法与请求时生成的图片共用缓存，因此不预生成。
+func (s *OGImageService) Prerender(ctx context.Context, limit int) (int, error) {
+	if siteURL, _ := s.configRepo.GetConfigValue(entity.ConfigKeyWebsiteURL); strings.TrimSpace(siteURL) == "" {
+		return 0, fmt.Errorf("未配置网站地址")
+	}
+	cursorTime, cursorID, ok := s.loadCursor()
+	if !ok {
+		return 0, s.saveCursor(s.now(), 0)
+	}
+	resources, err := s.resourceRepo.FindUpdatedAfter(cursorTime, cursorID, limit)
+	if err != nil || len(resources) == 0 {
+		return 0, err
+	}
+
+	rendered := 0
+	seen := make(map[string]bool, len(resources))
+	for i := range resources {
+		if ctx.Err() != nil {
+			break
+		}
+		key := resources[i].Key
+		if !seen[key] {
+			seen[key] = true
+			failed := false
+			for _, format := range []string{ogimage.FormatPNG, ogimage.FormatWebP} {
+				if _, err := s.Image(key, "", format, ""); err != nil {
+					utils.Warn("[OGImage] 预生成失败 key=%s format=%s: %v", key, format, err)
+					failed = true
+					break
+				}
+			}
+			if !failed {
+				rendered++
+			}
+		}
+		if err := s.saveCursor(resources[i].UpdatedAt, resources[i].ID); err != nil {
+			return rendered, err
+		}
+	}
+	return rendered, ctx.Err()
+}
+
+// resolveTemplate 取指定模板，为空时使用默认模板，默认模板不存在时回退到内置默认样式
+func (s *OGImageService) resolveTemplate(name string) (ogimage.Template, error) {
+	if name = strings.TrimSpace(name); name != "" {
+		tpl, ok := s.Template(name)
+		if !ok {
+			return tpl, fmt.Errorf("模板不存在: %s", name)
+		}
+		return tpl, nil
+	}
+	return s.ActiveTemplate(), nil
+}
+
+// ActiveTemplate 当前默认模板，配置的模板不存在时回退到内置默认样式
+func (s *OGImageService) ActiveTemplate() ogimage.Template {
+	if tpl, ok := s.Template(s.Config().ActiveTemplate); ok {
+		return tpl
+	}
+	return ogimage.DefaultTemplate()
+}
+
+func (s *OGImageService) render(tpl ogimage.Template, content OGImageContent, format string) ([]byte, error) {
+	if content.Title == "" {
+		content.Title = "老九网盘资源数据库"
+	}
+	data := ogimage.Data{
+		Title:       content.Title,
+		Subtitle:    content.Subtitle,
+		Description: content.Description,
+		SiteName:    content.SiteName,
+		URL:         content.URL,
+	}
+	if content.Cover != "" && tpl.Layout != ogimage.LayoutTextOnly {
+		if img, err := s.loader.Load(content.Cover); err == nil {
+			data.Cover = img
+		} else {
+			utils.Warn("[OGImage] 加载封面失败 %s: %v", content.Cover, err)
+		}
+	}
+	if tpl.Logo.URL != "" {
+		if img, err := s.loader.Load(tpl.Logo.URL); err == nil {
+			data.Logo = img
+		} else {
+			utils.Warn("[OGImage] 加载Logo失败 %s: %v", tpl.Logo.URL, err)
+		}
+	}
+
+	img, err := ogimage.Render(tpl, data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := ogimage.Encode(&buf, img, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *OGImageService) customTemplates() []ogimage.Template {
+	v, err := s.configRepo.GetConfigValue(entity.OGImageConfigKeyTemplates)
+	if err != nil || strings.TrimSpace(v) == "" {
+		return nil
+	}
+	var list []ogimage.Template
+	if err := json.Unmarshal([]byte(v), &list); err != nil {
+		utils.Warn("[OGImage] 解析自定义模板失败: %v", err)
+		return nil
+	}
+	for i := range list {
+		list[i] = list[i].Normalize()
+	}
+	return list
+}
+
+func (s *OGImageService) saveCustomTemplates(list []ogimage.Template) error {
+	if list == nil {
+		list = []ogimage.Template{}
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{{
+		Key:   entity.OGImageConfigKeyTemplates,
+		Value: string(data),
+		Type:  entity.ConfigTypeJSON,
+	}})
+}
+
+func (s *OGImageService) loadCursor() (time.Time, uint, bool) {
+	v, err := s.configRepo.GetConfigValue(entity.OGImageConfigKeyPrerenderCursor)
+	if err != nil || v == "" {
+		return time.Time{}, 0, false
+	}
+	parts := strings.SplitN(v, "|", 2)
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	var id uint64
+	if len(parts) == 2 {
+		id, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+	return t, uint(id), true
+}
+
+func (s *OGImageService) saveCursor(t time.Time, id uint) error {
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{{
+		Key:   entity.OGImageConfigKeyPrerenderCursor,
+		Value: t.Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(id), 10),
+		Type:  entity.ConfigTypeString,
+	}})
+}
+
+func isBuiltinOGTemplate(name string) bool {
+	for _, t := range ogimage.BuiltinTemplates() {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/ogimage"
+)
+
+// fakeOGImageResourceRepo 在 fakeSEOResourceRepo 基础上支持按更新时间游标查询
+type fakeOGImageResourceRepo struct {
+	*fakeSEOResourceRepo
+	updated []entity.Resource
+}
+
+func (f *fakeOGImageResourceRepo) FindUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error) {
+	var list []entity.Resource
+	for _, r := range f.updated {
+		if r.UpdatedAt.After(updatedAt) || (r.UpdatedAt.Equal(updatedAt) && r.ID > id) {
+			list = append(list, r)
+		}
+	}
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// fakeOGImageLoader 记录加载次数，返回固定尺寸的图片
+type fakeOGImageLoader struct {
+	loads int
+}
+
+func (f *fakeOGImageLoader) Load(src string) (image.Image, error) {
+	f.loads++
+	return image.NewRGBA(image.Rect(0, 0, 30, 45)), nil
+}
+
+func newTestOGImageService(t *testing.T, resourceRepo *fakeOGImageResourceRepo) (*OGImageService, *fakeOGImageLoader) {
+	configRepo := &fakeSearchEngineConfigRepo{values: map[string]string{
+		entity.ConfigKeyWebsiteURL: "https://pan.example.com",
+		entity.ConfigKeySiteTitle:  "老九网盘",
+	}}
+	s := NewOGImageService(resourceRepo, configRepo, nil, t.TempDir(), t.TempDir())
+	loader := &fakeOGImageLoader{}
+	s.loader = loader
+	return s, loader
+}
+
+func TestOGImageServiceCachesByContentAndTemplate(t *testing.T) {
+	resourceRepo := &fakeOGImageResourceRepo{fakeSEOResourceRepo: &fakeSEOResourceRepo{byKey: map[string][]entity.Resource{
+		"k1": {{Title: "三体", Description: "科幻剧", Cover: "https://img.example.com/k1.jpg", Key: "k1"}},
+	}}}
+	s, loader := newTestOGImageService(t, resourceRepo)
+
+	first, err := s.Image("k1", "", ogimage.FormatWebP, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Image("k1", "", ogimage.FormatWebP, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached || !second.Cached || first.ETag != second.ETag || loader.loads != 1 {
+		t.Errorf("first = %v, second = %v, loads = %d", first.Cached, second.Cached, loader.loads)
+	}
+
+	// 资源更新后指纹变化，重新绘制
+	resourceRepo.byKey["k1"][0].Title = "三体 全30集"
+	third, _ := s.Image("k1", "", ogimage.FormatWebP, "")
+	if third.Cached || third.ETag == first.ETag {
+		t.Error("updated resource should be re-rendered")
+	}
+
+	// 修改默认模板后同样失效
+	tpl, _ := s.Template("default")
+	tpl.Colors.Title = "#fbbf24"
+	if _, err := s.SaveTemplate(tpl); err != nil {
+		t.Fatal(err)
+	}
+	if fourth, _ := s.Image("k1", "", ogimage.FormatWebP, ""); fourth.Cached {
+		t.Error("template change should invalidate cache")
+	}
+
+	if _, err := s.Image("missing", "", ogimage.FormatPNG, ""); err != ErrOGImageResourceNotFound {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestOGImageServiceTemplates(t *testing.T) {
+	s, _ := newTestOGImageService(t, &fakeOGImageResourceRepo{fakeSEOResourceRepo: &fakeSEOResourceRepo{}})
+
+	if _, err := s.SaveTemplate(ogimage.Template{Name: "poster", Layout: "diagonal"}); err == nil {
+		t.Error("expected invalid layout to be rejected")
+	}
+	if _, err := s.SaveTemplate(ogimage.Template{Name: "poster", Layout: ogimage.LayoutCoverBackground}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SaveSettings("nope", true); err == nil {
+		t.Error("expected unknown active template to be rejected")
+	}
+	cfg, err := s.SaveSettings("poster", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveTemplate != "poster" || cfg.PrerenderEnabled || len(cfg.Templates) != 6 {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	if err := s.DeleteTemplate("poster"); err == nil {
+		t.Error("active template should not be deletable")
+	}
+
+	dark, _ := s.Template("dark")
+	dark.Width = 800
+	s.SaveTemplate(dark)
+	if err := s.DeleteTemplate("dark"); err != nil {
+		t.Fatal(err)
+	}
+	if restored, _ := s.Template("dark"); restored.Width != 1200 {
+		t.Errorf("deleting override should restore builtin, width = %d", restored.Width)
+	}
+	if err := s.DeleteTemplate("blue"); err == nil {
+		t.Error("builtin template should not be deletable")
+	}
+}
+
+func TestOGImageServicePrerender(t *testing.T) {
+	now := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	resourceRepo := &fakeOGImageResourceRepo{
+		fakeSEOResourceRepo: &fakeSEOResourceRepo{byKey: map[string][]entity.Resource{
+			"a": {{Title: "A", Key: "a"}},
+			"b": {{Title: "B", Key: "b"}},
+		}},
+		updated: []entity.Resource{
+			{ID: 1, Key: "a", UpdatedAt: now.Add(time.Minute)},
+			{ID: 2, Key: "a", UpdatedAt: now.Add(time.Minute)},
+			{ID: 3, Key: "b", UpdatedAt: now.Add(2 * time.Minute)},
+		},
+	}
+	s, _ := newTestOGImageService(t, resourceRepo)
+	s.now = func() time.Time { return now }
+
+	// 首次运行只记录起点
+	if n, err := s.Prerender(context.Background(), 10); err != nil || n != 0 {
+		t.Fatalf("first run n = %d, err = %v", n, err)
+	}
+	n, err := s.Prerender(context.Background(), 10)
+	if err != nil || n != 2 {
+		t.Fatalf("n = %d, err = %v", n, err)
+	}
+	if stats, _ := s.CacheStats(); stats.Keys != 2 || stats.Files != 4 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if img, _ := s.Image("b", "", ogimage.FormatPNG, ""); !img.Cached {
+		t.Error("pre-rendered image should be served from cache")
+	}
+	if n, _ := s.Prerender(context.Background(), 10); n != 0 {
+		t.Errorf("cursor should have advanced, n = %d", n)
+	}
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cache"
+	"github.com/ctwj/urldb/utils"
+
+	"gorm.io/gorm"
+)
+
+// roleNamePattern 角色标识：小写字母开头，字母数字与下划线
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+var (
+	ErrRoleNotFound  = errors.New("角色不存在")
+	ErrRoleExists    = errors.New("角色已存在")
+	ErrRoleProtected = errors.New("内置角色不可删除")
+	ErrRoleAdmin     = errors.New("管理员角色拥有全部权限，不可修改")
+)
+
+// rbacGrantsTTL 角色权限快照的有效期。修改角色后按 "role" 标签失效，启用 Redis 时
+// 其他副本通过发布订阅收到通知并丢弃快照；漏收通知或未启用 Redis 时最迟在有效期后重新加载
+const rbacGrantsTTL = time.Minute
+
+// RBACService 角色与权限服务，按角色缓存权限集合供中间件每次请求查询
+type RBACService struct {
+	roleRepo       repo.RoleRepository
+	permissionRepo repo.PermissionRepository
+	denialRepo     repo.PermissionDenialRepository
+
+	mu       sync.RWMutex
+	grants   map[string]map[string]bool // 进程内快照，每次请求直接读取；数据库不可用时沿用
+	loadedAt time.Time                  // 快照加载时间，零值表示已失效
+
+	loadMu     sync.Mutex    // 串行化重新加载，并发请求只查询一次数据库
+	generation atomic.Uint64 // 每次失效递增，加载期间发生过失效的快照不视为有效
+}
+
+// NewRBACService 创建角色与权限服务
+func NewRBACService(roleRepo repo.RoleRepository, permissionRepo repo.PermissionRepository, denialRepo repo.PermissionDenialRepository) *RBACService {
+	s := &RBACService{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		denialRepo:     denialRepo,
+		grants:         make(map[string]map[string]bool),
+	}
+	cache.Default().OnInvalidate("role", s.invalidate)
+	return s
+}
+
+var defaultRBACService *RBACService
+
+// SetDefaultRBACService 设置默认角色与权限服务实例（由 main.go 在初始化阶段调用）
+func SetDefaultRBACService(s *RBACService) {
+	defaultRBACService = s
+}
+
+// GetDefaultRBACService 获取默认角色与权限服务实例，未初始化时为 nil
+func GetDefaultRBACService() *RBACService {
+	return defaultRBACService
+}
+
+// EnsureDefaults 同步权限定义并创建缺失的内置角色，admin 角色每次都补齐全部权限
+func (s *RBACService) EnsureDefaults() error {
+	if err := s.permissionRepo.Sync(entity.PermissionCatalog); err != nil {
+		return fmt.Errorf("同步权限定义失败: %v", err)
+	}
+	for _, def := range entity.DefaultRoles {
+		role, err := s.roleRepo.FindByName(def.Role.Name)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		codes := def.Permissions
+		if def.Role.Name == entity.RoleAdmin {
+			codes = allPermissionCodes()
+		}
+		if role == nil {
+			role = &entity.Role{
+				Name:        def.Role.Name,
+				DisplayName: def.Role.DisplayName,
+				Description: def.Role.Description,
+				IsSystem:    true,
+			}
+			if err := s.roleRepo.Create(role); err != nil {
+				return fmt.Errorf("创建内置角色 %s 失败: %v", role.Name, err)
+			}
+			utils.Info("已创建内置角色: %s", role.Name)
+		} else if def.Role.Name != entity.RoleAdmin {
+			continue
+		}
+		if err := s.roleRepo.ReplacePermissions(role, codes); err != nil {
+			return fmt.Errorf("设置角色 %s 权限失败: %v", role.Name, err)
+		}
+	}
+	return s.Reload()
+}
+
+// Reload 失效所有副本的角色权限快照并从数据库重新加载
+func (s *RBACService) Reload() error {
+	cache.Default().Invalidate(context.Background(), cache.AllTag("role"))
+	s.invalidate()
+	_, err := s.loadGrants()
+	return err
+}
+
+// invalidate 丢弃权限快照，由 "role" 标签的失效回调（含其他副本的通知）调用
+func (s *RBACService) invalidate() {
+	s.generation.Add(1)
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// snapshot 返回权限快照及其是否仍然有效
+func (s *RBACService) snapshot() (map[string]map[string]bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.grants, !s.loadedAt.IsZero() && time.Since(s.loadedAt) < rbacGrantsTTL
+}
+
+// loadGrants 快照失效时从数据库加载角色权限
+func (s *RBACService) loadGrants() (map[string]map[string]bool, error) {
+	s.loadMu.Lock()
+	defer s.loadMu.Unlock()
+	if grants, ok := s.snapshot(); ok {
+		return grants, nil
+	}
+
+	generation := s.generation.Load()
+	roles, err := s.roleRepo.FindAllWithPermissions()
+	if err != nil {
+		return nil, err
+	}
+	grants := make(map[string]map[string]bool, len(roles))
+	for _, role := range roles {
+		set := make(map[string]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			set[p.Code] = true
+		}
+		grants[role.Name] = set
+	}
+	s.mu.Lock()
+	s.grants = grants
+	if s.generation.Load() == generation {
+		s.loadedAt = time.Now()
+	}
+	s.mu.Unlock()
+	return grants, nil
+}
+
+// currentGrants 当前的角色权限：快照有效时直接返回，否则重新加载，加载失败时沿用上一次的结果
+func (s *RBACService) currentGrants() map[string]map[string]bool {
+	if grants, ok := s.snapshot(); ok {
+		return grants
+	}
+	grants, err := s.loadGrants()
+	if err == nil {
+		return grants
+	}
+	utils.Warn("加载角色权限失败，沿用上次加载的权限: %v", err)
+	grants, _ = s.snapshot()
+	return grants
+}
+
+// HasPermission 判断角色是否拥有权限，admin 始终拥有全部权限
+func (s *RBACService) HasPermission(role, permission string) bool {
+	if role == entity.RoleAdmin {
+		return true
+	}
+	return s.currentGrants()[role][permission]
+}
+
+// Permissions 返回角色拥有的全部权限码（已排序）
+func (s *RBACService) Permissions(role string) []string {
+	if role == entity.RoleAdmin {
+		return allPermissionCodes()
+	}
+	set := s.currentGrants()[role]
+	codes := make([]string, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// RoleExists 判断角色是否存在
+func (s *RBACService) RoleExists(role string) bool {
+	_, ok := s.currentGrants()[role]
+	return ok
+}
+
+// ListRoles 获取全部角色及权限
+func (s *RBACService) ListRoles() ([]entity.Role, error) {
+	return s.roleRepo.FindAllWithPermissions()
+}
+
+// ListPermissions 获取全部权限定义
+func (s *RBACService) ListPermissions() []entity.Permission {
+	return entity.PermissionCatalog
+}
+
+// CreateRole 创建自定义角色
+func (s *RBACService) CreateRole(req dto.CreateRoleRequest) (*entity.Role, error) {
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, fmt.Errorf("角色标识只能包含小写字母、数字和下划线，以字母开头，长度 2-20")
+	}
+	if err := validatePermissionCodes(req.Permissions); err != nil {
+		return nil, err
+	}
+	if _, err := s.roleRepo.FindByName(req.Name); err == nil {
+		return nil, ErrRoleExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	role := &entity.Role{Name: req.Name, DisplayName: req.DisplayName, Description: req.Description}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.ReplacePermissions(role, req.Permissions); err != nil {
+		return nil, err
+	}
+	return role, s.Reload()
+}
+
+// UpdateRole 更新角色名称、描述与权限，admin 角色不可修改
+func (s *RBACService) UpdateRole(id uint, req dto.UpdateRoleRequest) (*entity.Role, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == entity.RoleAdmin {
+		return nil, ErrRoleAdmin
+	}
+	if err := validatePermissionCodes(req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role.DisplayName = req.DisplayName
+	role.Description = req.Description
+	role.Permissions = nil
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.ReplacePermissions(role, req.Permissions); err != nil {
+		return nil, err
+	}
+	return role, s.Reload()
+}
+
+// DeleteRole 删除自定义角色，内置角色与仍有用户使用的角色不可删除
+func (s *RBACService) DeleteRole(id uint) error {
+	role, err := s.findRole(id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrRoleProtected
+	}
+	count, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("仍有 %d 个用户使用该角色，请先修改这些用户的角色", count)
+	}
+	if err := s.roleRepo.DeleteWithPermissions(role.ID); err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
+// RecordDenial 记录一次权限拒绝
+func (s *RBACService) RecordDenial(denial *entity.PermissionDenial) error {
+	return s.denialRepo.Create(denial)
+}
+
+// ListDenials 分页查询权限拒绝记录
+func (s *RBACService) ListDenials(username, permission string, page, pageSize int) ([]entity.PermissionDenial, int64, error) {
+	return s.denialRepo.Search(username, permission, page, pageSize)
+}
+
+// CleanupDenials 删除指定天数之前的权限拒绝记录
+func (s *RBACService) CleanupDenials(days int) (int64, error) {
+	return s.denialRepo.DeleteBefore(utils.GetCurrentTime().Add(-time.Duration(days) * 24 * time.Hour))
+}
+
+func (s *RBACService) findRole(id uint) (*entity.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
+
+// validatePermissionCodes 校验权限码均已定义
+func validatePermissionCodes(codes []string) error {
+	known := make(map[string]bool, len(entity.PermissionCatalog))
+	for _, p := range entity.PermissionCatalog {
+		known[p.Code] = true
+	}
+	for _, code := range codes {
+		if !known[code] {
+			return fmt.Errorf("未知的权限: %s", code)
+		}
+	}
+	return nil
+}
+
+func allPermissionCodes() []string {
+	codes := make([]string, 0, len(entity.PermissionCatalog))
+	for _, p := range entity.PermissionCatalog {
+		codes = append(codes, p.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
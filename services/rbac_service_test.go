@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ctwj/urldb/db/dto"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cache"
+
+	"gorm.io/gorm"
+)
+
+type fakeRoleRepo struct {
+	repo.RoleRepository
+	roles  []*entity.Role
+	users  map[string]int64
+	nextID uint
+	loads  int
+}
+
+func (f *fakeRoleRepo) FindAllWithPermissions() ([]entity.Role, error) {
+	f.loads++
+	var roles []entity.Role
+	for _, r := range f.roles {
+		roles = append(roles, *r)
+	}
+	return roles, nil
+}
+
+func (f *fakeRoleRepo) FindByName(name string) (*entity.Role, error) {
+	for _, r := range f.roles {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) FindByID(id uint) (*entity.Role, error) {
+	for _, r := range f.roles {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) Create(role *entity.Role) error {
+	f.nextID++
+	role.ID = f.nextID
+	f.roles = append(f.roles, role)
+	return nil
+}
+
+func (f *fakeRoleRepo) Update(role *entity.Role) error { return nil }
+
+func (f *fakeRoleRepo) ReplacePermissions(role *entity.Role, codes []string) error {
+	role.Permissions = nil
+	for _, code := range codes {
+		role.Permissions = append(role.Permissions, entity.Permission{Code: code})
+	}
+	return nil
+}
+
+func (f *fakeRoleRepo) DeleteWithPermissions(id uint) error {
+	for i, r := range f.roles {
+		if r.ID == id {
+			f.roles = append(f.roles[:i], f.roles[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) CountUsers(name string) (int64, error) { return f.users[name], nil }
+
+type fakePermissionRepo struct {
+	repo.PermissionRepository
+	synced int
+}
+
+func (f *fakePermissionRepo) Sync(permissions []entity.Permission) error {
+	f.synced = len(permissions)
+	return nil
+}
+
+func newTestRBACService(t *testing.T) (*RBACService, *fakeRoleRepo) {
+	t.Helper()
+	roles := &fakeRoleRepo{users: map[string]int64{}}
+	svc := NewRBACService(roles, &fakePermissionRepo{}, nil)
+	if err := svc.EnsureDefaults(); err != nil {
+		t.Fatalf("EnsureDefaults: %v", err)
+	}
+	return svc, roles
+}
+
+func TestRBACDefaultRoles(t *testing.T) {
+	svc, roles := newTestRBACService(t)
+
+	if len(roles.roles) != len(entity.DefaultRoles) {
+		t.Fatalf("seeded %d roles, want %d", len(roles.roles), len(entity.DefaultRoles))
+	}
+	cases := []struct {
+		role, permission string
+		want             bool
+	}{
+		{entity.RoleAdmin, entity.PermissionCksManage, true},
+		{entity.RoleAdmin, "unknown:permission", true},
+		{entity.RoleEditor, entity.PermissionResourceManage, true},
+		{entity.RoleEditor, entity.PermissionReadyResourceManage, true},
+		{entity.RoleEditor, entity.PermissionCksManage, false},
+		{entity.RoleEditor, entity.PermissionSystemConfigManage, false},
+		{entity.RoleEditor, entity.PermissionPluginManage, false},
+		{entity.RoleEditor, entity.PermissionJobManage, false},
+		{entity.RoleEditor, entity.PermissionFileUpload, true},
+		{entity.RoleEditor, entity.PermissionFileManage, false},
+		{entity.RoleModerator, entity.PermissionReportManage, true},
+		{entity.RoleModerator, entity.PermissionTaxonomyManage, false},
+		{entity.RoleViewer, entity.PermissionStatsView, true},
+		{entity.RoleViewer, entity.PermissionResourceManage, false},
+		{entity.RoleUser, entity.PermissionStatsView, false},
+		{entity.RoleUser, entity.PermissionFileUpload, false},
+		{"", entity.PermissionStatsView, false},
+	}
+	for _, tc := range cases {
+		if got := svc.HasPermission(tc.role, tc.permission); got != tc.want {
+			t.Errorf("HasPermission(%q, %q) = %v, want %v", tc.role, tc.permission, got, tc.want)
+		}
+	}
+	if got := len(svc.Permissions(entity.RoleAdmin)); got != len(entity.PermissionCatalog) {
+		t.Errorf("admin permissions = %d, want %d", got, len(entity.PermissionCatalog))
+	}
+
+	// 再次初始化不覆盖已调整的内置角色权限
+	editor, _ := roles.FindByName(entity.RoleEditor)
+	roles.ReplacePermissions(editor, []string{entity.PermissionStatsView})
+	if err := svc.EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if svc.HasPermission(entity.RoleEditor, entity.PermissionResourceManage) {
+		t.Error("EnsureDefaults must not reset customized role permissions")
+	}
+}
+
+func TestRBACCustomRoleLifecycle(t *testing.T) {
+	svc, roles := newTestRBACService(t)
+
+	if _, err := svc.CreateRole(dto.CreateRoleRequest{Name: "Bad Name", DisplayName: "x"}); err == nil {
+		t.Fatal("invalid role name should be rejected")
+	}
+	if _, err := svc.CreateRole(dto.CreateRoleRequest{Name: "seo", DisplayName: "SEO", Permissions: []string{"nope"}}); err == nil {
+		t.Fatal("unknown permission should be rejected")
+	}
+	if _, err := svc.CreateRole(dto.CreateRoleRequest{Name: entity.RoleEditor, DisplayName: "x"}); !errors.Is(err, ErrRoleExists) {
+		t.Fatalf("duplicate role err = %v", err)
+	}
+
+	role, err := svc.CreateRole(dto.CreateRoleRequest{Name: "seo", DisplayName: "SEO", Permissions: []string{entity.PermissionSEOManage}})
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	if !svc.RoleExists("seo") || !svc.HasPermission("seo", entity.PermissionSEOManage) {
+		t.Fatal("new role should be cached with its permissions")
+	}
+
+	if _, err := svc.UpdateRole(role.ID, dto.UpdateRoleRequest{DisplayName: "SEO", Permissions: []string{entity.PermissionStatsView}}); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if svc.HasPermission("seo", entity.PermissionSEOManage) || !svc.HasPermission("seo", entity.PermissionStatsView) {
+		t.Fatal("permissions should be replaced on update")
+	}
+
+	admin, _ := roles.FindByName(entity.RoleAdmin)
+	if _, err := svc.UpdateRole(admin.ID, dto.UpdateRoleRequest{DisplayName: "x"}); !errors.Is(err, ErrRoleAdmin) {
+		t.Fatalf("update admin err = %v", err)
+	}
+	editor, _ := roles.FindByName(entity.RoleEditor)
+	if err := svc.DeleteRole(editor.ID); !errors.Is(err, ErrRoleProtected) {
+		t.Fatalf("delete system role err = %v", err)
+	}
+
+	roles.users["seo"] = 2
+	if err := svc.DeleteRole(role.ID); err == nil {
+		t.Fatal("role in use should not be deleted")
+	}
+	roles.users["seo"] = 0
+	if err := svc.DeleteRole(role.ID); err != nil {
+		t.Fatalf("DeleteRole: %v", err)
+	}
+	if svc.RoleExists("seo") {
+		t.Fatal("deleted role should be removed from cache")
+	}
+	if err := svc.DeleteRole(999); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("delete missing role err = %v", err)
+	}
+}
+
+// TestRBACGrantsFollowOtherReplicas 其他副本修改角色后，本副本收到失效通知即生效
+func TestRBACGrantsFollowOtherReplicas(t *testing.T) {
+	svc, roles := newTestRBACService(t)
+	if !svc.HasPermission(entity.RoleEditor, entity.PermissionTaskManage) {
+		t.Fatal("editor should start with task:manage")
+	}
+
+	// 模拟另一个副本直接修改数据库
+	editor, _ := roles.FindByName(entity.RoleEditor)
+	roles.ReplacePermissions(editor, []string{entity.PermissionStatsView})
+	loads := roles.loads
+	for i := 0; i < 100; i++ {
+		if !svc.HasPermission(entity.RoleEditor, entity.PermissionTaskManage) {
+			t.Fatal("grants should be served from the snapshot until invalidated")
+		}
+	}
+	if roles.loads != loads {
+		t.Fatalf("permission checks should not reload grants, loads = %d", roles.loads-loads)
+	}
+
+	// Redis 发布订阅的失效通知最终调用的是同一个本地失效
+	cache.Default().Invalidate(context.Background(), cache.AllTag("role"))
+	if svc.HasPermission(entity.RoleEditor, entity.PermissionTaskManage) {
+		t.Fatal("revoked permission should take effect after invalidation")
+	}
+	if roles.loads != loads+1 {
+		t.Fatalf("invalidation should reload grants once, loads = %d", roles.loads-loads)
+	}
+}
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/utils"
+	"gorm.io/gorm"
+)
+
+// 流水线阶段名称
+const (
+	PipelineStageNormalize      = "normalize"       // 规范化链接与文本，识别网盘类型
+	PipelineStageDedupe         = "dedupe"          // 按链接查重
+	PipelineStageValidity       = "validity"        // PanCheck 有效性检测
+	PipelineStageMetadata       = "metadata"        // 通过网盘账号获取标题等元数据
+	PipelineStageForbiddenWords = "forbidden_words" // 违禁词检查
+	PipelineStageCategorize     = "categorize"      // 自动分类
+	PipelineStageTag            = "tag"             // 自动打标签
+	PipelineStageClassify       = "classify"        // 规则与模型自动分类/打标签
+	PipelineStageEnrich         = "enrich"          // 匹配豆瓣/TMDB 影视元数据
+	PipelineStageCover          = "cover"           // 补全封面
+	PipelineStagePersist        = "persist"         // 写入正式资源
+	PipelineStageIndex          = "index"           // 同步搜索索引
+	PipelineStageNotify         = "notify"          // 触发插件通知
+)
+
+// 阶段所处的环节，流水线中环节只能递增：入库前 → 入库 → 入库后
+const (
+	pipelinePhasePrepare = iota
+	pipelinePhasePersist
+	pipelinePhaseAfter
+)
+
+// readyResourceStageLogRetention 阶段记录保留时长
+const readyResourceStageLogRetention = 30 * 24 * time.Hour
+
+// PipelineStageInfo 阶段说明（供管理端展示）
+type PipelineStageInfo struct {
+	Name                   string   `json:"name"`
+	Label                  string   `json:"label"`
+	Description            string   `json:"description"`
+	Required               bool     `json:"required"`                  // 必需阶段不可禁用
+	DefaultEnabled         bool     `json:"default_enabled"`           // 默认流水线是否启用
+	DefaultContinueOnError bool     `json:"default_continue_on_error"` // 失败时默认是否继续
+	Options                []string `json:"options,omitempty"`         // 支持的配置项
+	phase                  int
+}
+
+// pipelineStageInfos 全部阶段，顺序即默认执行顺序。
+// 未配置流水线时使用默认值，与原先的固定处理流程一致（违禁词、封面、通知默认关闭）。
+var pipelineStageInfos = []PipelineStageInfo{
+	{Name: PipelineStageNormalize, Label: "规范化", Description: "清理链接与文本，识别网盘类型，不支持的链接直接拒绝", Required: true, DefaultEnabled: true, phase: pipelinePhasePrepare},
+	{Name: PipelineStageDedupe, Label: "去重", Description: "链接已存在于正式资源时跳过，命中黑名单拒绝规则时提前拒绝", DefaultEnabled: true, phase: pipelinePhasePrepare},
+	{Name: PipelineStageValidity, Label: "有效性检测", Description: "PanCheck 判定失效时拒绝", DefaultEnabled: true, Options: []string{"ignore_cache"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageMetadata, Label: "获取元数据", Description: "使用网盘账号获取标题与描述", DefaultEnabled: true, Options: []string{"platforms"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageForbiddenWords, Label: "违禁词检查", Description: "命中违禁词时拒绝、隐藏或打码", Options: []string{"action", "words"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageCategorize, Label: "自动分类", Description: "按待处理资源的分类名匹配或创建分类", DefaultEnabled: true, DefaultContinueOnError: true, Options: []string{"default_category"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageTag, Label: "自动标签", Description: "按待处理资源的标签匹配或创建标签", DefaultEnabled: true, DefaultContinueOnError: true, Options: []string{"extra_tags"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageClassify, Label: "智能分类", Description: "按分类规则与本地模型推荐分类和标签，低置信度进入审核队列", DefaultContinueOnError: true, Options: []string{"assign_threshold", "review_threshold", "use_bayes", "overwrite"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageEnrich, Label: "影视元数据", Description: "按片名与年份匹配豆瓣/TMDB 条目，保存年份、地区、类型、评分与海报", DefaultContinueOnError: true, Options: []string{"min_score", "set_cover"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStageCover, Label: "补全封面", Description: "缺少封面时使用同名热播剧海报或默认封面", DefaultContinueOnError: true, Options: []string{"default_cover"}, phase: pipelinePhasePrepare},
+	{Name: PipelineStagePersist, Label: "入库", Description: "按黑名单规则拒绝、隐藏或打码后写入正式资源及标签关联", Required: true, DefaultEnabled: true, phase: pipelinePhasePersist},
+	{Name: PipelineStageIndex, Label: "搜索索引", Description: "同步到 Meilisearch，超时后转入后台继续，失败不影响入库", DefaultEnabled: true, DefaultContinueOnError: true, Options: []string{"timeout_seconds"}, phase: pipelinePhaseAfter},
+	{Name: PipelineStageNotify, Label: "插件通知", Description: "触发插件的 URL 添加事件", DefaultContinueOnError: true, phase: pipelinePhaseAfter},
+}
+
+// PipelineStageInfos 返回全部阶段说明
+func PipelineStageInfos() []PipelineStageInfo {
+	infos := make([]PipelineStageInfo, len(pipelineStageInfos))
+	copy(infos, pipelineStageInfos)
+	return infos
+}
+
+func findPipelineStageInfo(name string) (PipelineStageInfo, bool) {
+	for _, info := range pipelineStageInfos {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return PipelineStageInfo{}, false
+}
+
+// PipelineStageConfig 流水线中单个阶段的配置
+type PipelineStageConfig struct {
+	Name            string                 `json:"name"`
+	Enabled         bool                   `json:"enabled"`
+	ContinueOnError *bool                  `json:"continue_on_error,omitempty"` // 为空时使用阶段默认值
+	Options         map[string]interface{} `json:"options,omitempty"`
+}
+
+// ShouldContinueOnError 阶段失败时是否继续执行后续阶段；必需阶段失败始终终止，
+// 入库之后的阶段（索引、通知）失败只记为警告，资源已入库不再因此判定失败
+func (c PipelineStageConfig) ShouldContinueOnError() bool {
+	info, ok := findPipelineStageInfo(c.Name)
+	if !ok || info.Required {
+		return false
+	}
+	if info.phase == pipelinePhaseAfter {
+		return true
+	}
+	if c.ContinueOnError != nil {
+		return *c.ContinueOnError
+	}
+	return info.DefaultContinueOnError
+}
+
+// OptionString 读取字符串配置项
+func (c PipelineStageConfig) OptionString(key, def string) string {
+	if v, ok := c.Options[key].(string); ok && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v)
+	}
+	return def
+}
+
+// OptionBool 读取布尔配置项
+func (c PipelineStageConfig) OptionBool(key string, def bool) bool {
+	if v, ok := c.Options[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// OptionFloat 读取数值配置项
+func (c PipelineStageConfig) OptionFloat(key string, def float64) float64 {
+	if v, ok := c.Options[key].(float64); ok {
+		return v
+	}
+	return def
+}
+
+// OptionStrings 读取列表配置项，兼容 JSON 数组与逗号/换行分隔的字符串
+func (c PipelineStageConfig) OptionStrings(key string, def []string) []string {
+	switch v := c.Options[key].(type) {
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				values = append(values, strings.TrimSpace(s))
+			}
+		}
+		return values
+	case []string:
+		return v
+	case string:
+		return utils.ParseForbiddenWordsConfig(v)
+	}
+	return def
+}
+
+// DefaultPipelineStageConfigs 内置默认流水线
+func DefaultPipelineStageConfigs() []PipelineStageConfig {
+	stages := make([]PipelineStageConfig, 0, len(pipelineStageInfos))
+	for _, info := range pipelineStageInfos {
+		stages = append(stages, PipelineStageConfig{Name: info.Name, Enabled: info.DefaultEnabled})
+	}
+	return stages
+}
+
+// ParsePipelineStages 解析并校验阶段配置JSON
+func ParsePipelineStages(raw string) ([]PipelineStageConfig, error) {
+	var stages []PipelineStageConfig
+	if err := json.Unmarshal([]byte(raw), &stages); err != nil {
+		return nil, fmt.Errorf("阶段配置格式错误: %v", err)
+	}
+	if err := ValidatePipelineStages(stages); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// ValidatePipelineStages 校验阶段配置：阶段名合法且不重复，必需阶段已启用，
+// 规范化位于首位，且入库前的阶段不能排在入库之后
+func ValidatePipelineStages(stages []PipelineStageConfig) error {
+	if len(stages) == 0 {
+		return fmt.Errorf("阶段配置不能为空")
+	}
+
+	seen := make(map[string]bool, len(stages))
+	lastPhase := pipelinePhasePrepare
+	for i, stage := range stages {
+		info, ok := findPipelineStageInfo(stage.Name)
+		if !ok {
+			return fmt.Errorf("未知的阶段: %s", stage.Name)
+		}
+		if seen[stage.Name] {
+			return fmt.Errorf("阶段重复: %s", stage.Name)
+		}
+		seen[stage.Name] = true
+
+		if info.Required && !stage.Enabled {
+			return fmt.Errorf("阶段 %s 不能禁用", stage.Name)
+		}
+		if stage.Name == PipelineStageNormalize && i != 0 {
+			return fmt.Errorf("阶段 %s 必须位于首位", stage.Name)
+		}
+		if info.phase < lastPhase {
+			return fmt.Errorf("阶段 %s 必须位于 %s 之前", stage.Name, PipelineStagePersist)
+		}
+		lastPhase = info.phase
+	}
+
+	for _, info := range pipelineStageInfos {
+		if info.Required && !seen[info.Name] {
+			return fmt.Errorf("缺少必需阶段: %s", info.Name)
+		}
+	}
+	return nil
+}
+
+// ReadyResourcePipelineService 待处理资源流水线配置与阶段记录服务
+type ReadyResourcePipelineService struct {
+	pipelineRepo repo.ReadyResourcePipelineRepository
+	logRepo      repo.ReadyResourceStageLogRepository
+
+	mu          sync.RWMutex
+	cache       map[string][]PipelineStageConfig // source -> 生效的阶段配置
+	lastPruneAt time.Time
+}
+
+// NewReadyResourcePipelineService 创建待处理资源流水线服务
+func NewReadyResourcePipelineService(pipelineRepo repo.ReadyResourcePipelineRepository, logRepo repo.ReadyResourceStageLogRepository) *ReadyResourcePipelineService {
+	return &ReadyResourcePipelineService{
+		pipelineRepo: pipelineRepo,
+		logRepo:      logRepo,
+		cache:        make(map[string][]PipelineStageConfig),
+	}
+}
+
+// Resolve 获取数据来源生效的阶段配置：来源专属配置 → default 配置 → 内置默认
+func (s *ReadyResourcePipelineService) Resolve(source string) []PipelineStageConfig {
+	s.mu.RLock()
+	stages, ok := s.cache[source]
+	s.mu.RUnlock()
+	if ok {
+		return stages
+	}
+
+	stages = s.load(source)
+	if stages == nil && source != entity.ReadyResourcePipelineDefaultSource {
+		stages = s.load(entity.ReadyResourcePipelineDefaultSource)
+	}
+	if stages == nil {
+		stages = DefaultPipelineStageConfigs()
+	}
+
+	s.mu.Lock()
+	s.cache[source] = stages
+	s.mu.Unlock()
+	return stages
+}
+
+// load 读取数据库中的配置，不存在或配置无效时返回 nil
+func (s *ReadyResourcePipelineService) load(source string) []PipelineStageConfig {
+	pipeline, err := s.pipelineRepo.FindBySource(source)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			utils.Error("[Pipeline] 读取流水线配置失败 (source=%s): %v", source, err)
+		}
+		return nil
+	}
+	stages, err := ParsePipelineStages(pipeline.Stages)
+	if err != nil {
+		utils.Error("[Pipeline] 流水线配置无效，使用默认配置 (source=%s): %v", source, err)
+		return nil
+	}
+	return stages
+}
+
+// Save 保存数据来源的流水线配置
+func (s *ReadyResourcePipelineService) Save(source string, stages []PipelineStageConfig, remark string) (*entity.ReadyResourcePipeline, error) {
+	if err := ValidatePipelineStages(stages); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(stages)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := s.pipelineRepo.FindBySource(source)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if pipeline == nil {
+		pipeline = &entity.ReadyResourcePipeline{Source: source}
+	}
+	pipeline.Stages = string(raw)
+	pipeline.Remark = remark
+
+	if pipeline.ID == 0 {
+		err = s.pipelineRepo.Create(pipeline)
+	} else {
+		err = s.pipelineRepo.Update(pipeline)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return pipeline, nil
+}
+
+// Delete 删除数据来源的流水线配置，恢复使用默认流水线
+func (s *ReadyResourcePipelineService) Delete(source string) error {
+	if err := s.pipelineRepo.DeleteBySource(source); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// List 获取全部已保存的流水线配置
+func (s *ReadyResourcePipelineService) List() ([]entity.ReadyResourcePipeline, error) {
+	return s.pipelineRepo.FindAll()
+}
+
+// invalidate 清空配置缓存（default 配置变化会影响所有来源）
+func (s *ReadyResourcePipelineService) invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string][]PipelineStageConfig)
+	s.mu.Unlock()
+}
+
+// RecordStageLogs 保存阶段执行记录，并按保留期定期清理旧记录
+func (s *ReadyResourcePipelineService) RecordStageLogs(logs []entity.ReadyResourceStageLog) {
+	if err := s.logRepo.BatchCreate(logs); err != nil {
+		utils.Error("[Pipeline] 保存阶段记录失败: %v", err)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	due := now.Sub(s.lastPruneAt) >= 24*time.Hour
+	if due {
+		s.lastPruneAt = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	deleted, err := s.logRepo.DeleteBefore(now.Add(-readyResourceStageLogRetention))
+	if err != nil {
+		utils.Error("[Pipeline] 清理阶段记录失败: %v", err)
+	} else if deleted > 0 {
+		utils.Info("[Pipeline] 已清理 %d 条过期阶段记录", deleted)
+	}
+}
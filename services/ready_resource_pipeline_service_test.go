@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"gorm.io/gorm"
+)
+
+// 纯单元测试：覆盖流水线阶段配置校验、配置项读取与按来源回退的解析顺序。
+
+type fakePipelineRepo struct {
+	repo.ReadyResourcePipelineRepository
+	bySource map[string]*entity.ReadyResourcePipeline
+	lookups  int
+}
+
+func (f *fakePipelineRepo) FindBySource(source string) (*entity.ReadyResourcePipeline, error) {
+	f.lookups++
+	if p, ok := f.bySource[source]; ok {
+		return p, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func mustStagesJSON(t *testing.T, stages []PipelineStageConfig) string {
+	t.Helper()
+	raw, err := json.Marshal(stages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
+
+func TestValidatePipelineStages(t *testing.T) {
+	enabled := func(names ...string) []PipelineStageConfig {
+		stages := make([]PipelineStageConfig, 0, len(names))
+		for _, n := range names {
+			stages = append(stages, PipelineStageConfig{Name: n, Enabled: true})
+		}
+		return stages
+	}
+
+	tests := []struct {
+		name    string
+		stages  []PipelineStageConfig
+		wantErr bool
+	}{
+		{"defaults", DefaultPipelineStageConfigs(), false},
+		{"minimal", enabled(PipelineStageNormalize, PipelineStagePersist), false},
+		{"reordered prepare stages", enabled(PipelineStageNormalize, PipelineStageForbiddenWords, PipelineStageDedupe, PipelineStagePersist, PipelineStageNotify, PipelineStageIndex), false},
+		{"empty", nil, true},
+		{"unknown stage", enabled(PipelineStageNormalize, "translate", PipelineStagePersist), true},
+		{"duplicate", enabled(PipelineStageNormalize, PipelineStageDedupe, PipelineStageDedupe, PipelineStagePersist), true},
+		{"missing persist", enabled(PipelineStageNormalize, PipelineStageDedupe), true},
+		{"normalize not first", enabled(PipelineStageDedupe, PipelineStageNormalize, PipelineStagePersist), true},
+		{"prepare after persist", enabled(PipelineStageNormalize, PipelineStagePersist, PipelineStageTag), true},
+		{"post before persist", enabled(PipelineStageNormalize, PipelineStageIndex, PipelineStagePersist), true},
+		{"required disabled", []PipelineStageConfig{{Name: PipelineStageNormalize, Enabled: true}, {Name: PipelineStagePersist}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePipelineStages(tt.stages)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePipelineStages() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPipelineStageConfigOptions(t *testing.T) {
+	var stages []PipelineStageConfig
+	raw := `[{"name":"metadata","enabled":true,"continue_on_error":true,"options":{"platforms":["quark"," uc "],"ignore_cache":true}},
+	         {"name":"tag","enabled":true,"options":{"extra_tags":"a, b\nc"}},
+	         {"name":"persist","enabled":true,"continue_on_error":true}]`
+	if err := json.Unmarshal([]byte(raw), &stages); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, tag, persist := stages[0], stages[1], stages[2]
+	if got := meta.OptionStrings("platforms", nil); len(got) != 2 || got[1] != "uc" {
+		t.Fatalf("platforms = %v", got)
+	}
+	if !meta.OptionBool("ignore_cache", false) || meta.OptionBool("missing", false) {
+		t.Fatal("OptionBool mismatch")
+	}
+	if got := tag.OptionStrings("extra_tags", nil); len(got) != 3 {
+		t.Fatalf("extra_tags = %v", got)
+	}
+	if got := tag.OptionString("missing", "def"); got != "def" {
+		t.Fatalf("OptionString default = %q", got)
+	}
+
+	if !meta.ShouldContinueOnError() {
+		t.Fatal("explicit continue_on_error should win")
+	}
+	if !tag.ShouldContinueOnError() {
+		t.Fatal("tag stage continues on error by default")
+	}
+	if persist.ShouldContinueOnError() {
+		t.Fatal("required stage must never continue on error")
+	}
+
+	off := false
+	index := PipelineStageConfig{Name: PipelineStageIndex, Enabled: true, ContinueOnError: &off}
+	if !index.ShouldContinueOnError() {
+		t.Fatal("stages after persist must never fail the resource")
+	}
+}
+
+func TestReadyResourcePipelineService_Resolve(t *testing.T) {
+	custom := []PipelineStageConfig{
+		{Name: PipelineStageNormalize, Enabled: true},
+		{Name: PipelineStagePersist, Enabled: true},
+	}
+	fallback := []PipelineStageConfig{
+		{Name: PipelineStageNormalize, Enabled: true},
+		{Name: PipelineStageDedupe, Enabled: true},
+		{Name: PipelineStagePersist, Enabled: true},
+	}
+
+	pipelineRepo := &fakePipelineRepo{bySource: map[string]*entity.ReadyResourcePipeline{
+		entity.SourceRSS: {Source: entity.SourceRSS, Stages: mustStagesJSON(t, custom)},
+		entity.SourceAPI: {Source: entity.SourceAPI, Stages: `[{"name":"persist","enabled":true}]`}, // 无效配置
+	}}
+	svc := NewReadyResourcePipelineService(pipelineRepo, nil)
+
+	if got := svc.Resolve(entity.SourceRSS); len(got) != 2 {
+		t.Fatalf("custom pipeline = %+v", got)
+	}
+	if got := svc.Resolve(entity.SourceAPI); len(got) != len(pipelineStageInfos) {
+		t.Fatalf("invalid config should fall back to builtin defaults, got %+v", got)
+	}
+
+	pipelineRepo.bySource[entity.ReadyResourcePipelineDefaultSource] = &entity.ReadyResourcePipeline{
+		Source: entity.ReadyResourcePipelineDefaultSource,
+		Stages: mustStagesJSON(t, fallback),
+	}
+	svc.invalidate()
+	if got := svc.Resolve(entity.SourceHTML); len(got) != 3 {
+		t.Fatalf("default pipeline = %+v", got)
+	}
+
+	lookups := pipelineRepo.lookups
+	svc.Resolve(entity.SourceHTML)
+	if pipelineRepo.lookups != lookups {
+		t.Fatal("resolved pipeline should be cached")
+	}
+}
@@ -0,0 +1,598 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/google"
+	"github.com/ctwj/urldb/pkg/searchengine"
+	"github.com/ctwj/urldb/utils"
+)
+
+const (
+	// searchEngineResubmitInterval 成功提交后，同一 URL 在该时间内不会因资源更新而重复提交
+	searchEngineResubmitInterval = 24 * time.Hour
+	// searchEngineCollectLimit 每轮最多扫描的新增/更新资源数
+	searchEngineCollectLimit = 500
+	// searchEngineProcessLimit 每个搜索引擎每轮最多提交的 URL 数（仍受每日配额限制）
+	searchEngineProcessLimit = 2000
+)
+
+var indexNowKeyRe = regexp.MustCompile(`^[a-zA-Z0-9-]{8,128}$`)
+
+// searchEngineKeys 各搜索引擎的启用与配额配置键、默认配额
+var searchEngineKeys = map[string]struct {
+	enabled      string
+	quota        string
+	defaultQuota int
+}{
+	searchengine.EngineGoogle:     {entity.SearchEngineConfigKeyGoogleEnabled, entity.SearchEngineConfigKeyGoogleQuota, entity.SearchEngineConfigDefaultGoogleQuota},
+	searchengine.EngineBing:       {entity.SearchEngineConfigKeyBingEnabled, entity.SearchEngineConfigKeyBingQuota, entity.SearchEngineConfigDefaultBingQuota},
+	searchengine.EngineIndexNow:   {entity.SearchEngineConfigKeyIndexNowEnabled, entity.SearchEngineConfigKeyIndexNowQuota, entity.SearchEngineConfigDefaultIndexNowQuota},
+	searchengine.EngineBaidu:      {entity.SearchEngineConfigKeyBaiduEnabled, entity.SearchEngineConfigKeyBaiduQuota, entity.SearchEngineConfigDefaultBaiduQuota},
+	searchengine.EngineBaiduDaily: {entity.SearchEngineConfigKeyBaiduDailyEnabled, entity.SearchEngineConfigKeyBaiduDailyQuota, entity.SearchEngineConfigDefaultBaiduDailyQuota},
+}
+
+// SearchEngineSetting 单个搜索引擎的开关与每日配额
+type SearchEngineSetting struct {
+	Enabled    bool `json:"enabled"`
+	DailyQuota int  `json:"daily_quota"`
+}
+
+// SearchEngineConfig 搜索引擎 URL 提交配置
+type SearchEngineConfig struct {
+	AutoSubmit       bool                           `json:"auto_submit"`
+	IntervalMinutes  int                            `json:"interval_minutes"`
+	MaxAttempts      int                            `json:"max_attempts"`
+	SiteURL          string                         `json:"site_url"` // 只读，取自网站地址配置
+	Engines          map[string]SearchEngineSetting `json:"engines"`
+	IndexNowKey      string                         `json:"indexnow_key"`
+	IndexNowEndpoint string                         `json:"indexnow_endpoint"`
+	BaiduToken       string                         `json:"baidu_token"`
+}
+
+// Validate 校验配置
+func (c SearchEngineConfig) Validate() error {
+	if c.IntervalMinutes < 5 || c.IntervalMinutes > 1440 {
+		return fmt.Errorf("自动提交间隔须在 5-1440 分钟之间")
+	}
+	if c.MaxAttempts < 1 || c.MaxAttempts > 10 {
+		return fmt.Errorf("重试次数须在 1-10 之间")
+	}
+	for name, setting := range c.Engines {
+		if _, ok := searchEngineKeys[name]; !ok {
+			return fmt.Errorf("不支持的搜索引擎: %s", name)
+		}
+		if setting.DailyQuota < 0 || setting.DailyQuota > 100000 {
+			return fmt.Errorf("%s 每日配额须在 0-100000 之间", name)
+		}
+	}
+	if c.Engines[searchengine.EngineIndexNow].Enabled && c.IndexNowKey != "" && !indexNowKeyRe.MatchString(c.IndexNowKey) {
+		return fmt.Errorf("IndexNow 密钥须为 8-128 位字母、数字或连字符")
+	}
+	if (c.Engines[searchengine.EngineBaidu].Enabled || c.Engines[searchengine.EngineBaiduDaily].Enabled) && strings.TrimSpace(c.BaiduToken) == "" {
+		return fmt.Errorf("启用百度收录时必须填写推送 token")
+	}
+	return nil
+}
+
+// EnabledEngines 按提交顺序返回已启用的搜索引擎
+func (c SearchEngineConfig) EnabledEngines() []string {
+	var engines []string
+	for _, name := range searchengine.Engines {
+		if c.Engines[name].Enabled {
+			engines = append(engines, name)
+		}
+	}
+	return engines
+}
+
+// SearchEngineTarget 待提交的页面
+type SearchEngineTarget struct {
+	URL        string
+	ResourceID *uint
+}
+
+// SearchEngineRunResult 单个搜索引擎一轮提交的结果
+type SearchEngineRunResult struct {
+	Engine         string `json:"engine"`
+	Submitted      int    `json:"submitted"`
+	Succeeded      int    `json:"succeeded"`
+	Failed         int    `json:"failed"`
+	QuotaExhausted bool   `json:"quota_exhausted"`
+	Error          string `json:"error,omitempty"`
+}
+
+// SearchEngineStat 单个搜索引擎的状态
+type SearchEngineStat struct {
+	Engine         string           `json:"engine"`
+	Enabled        bool             `json:"enabled"`
+	DailyQuota     int              `json:"daily_quota"`
+	UsedToday      int              `json:"used_today"`
+	QuotaExhausted bool             `json:"quota_exhausted"`
+	Counts         map[string]int64 `json:"counts"`
+}
+
+// SearchEngineSubmitService 统一的搜索引擎 URL 提交服务：
+// 新增或更新的资源页面进入每个已启用搜索引擎的提交台账，按每日配额分批提交并记录结果。
+type SearchEngineSubmitService struct {
+	repo       repo.SearchEngineSubmissionRepository
+	configRepo repo.SystemConfigRepository
+
+	// 以下字段便于测试替换
+	newSubmitter func(engine string, cfg SearchEngineConfig) (searchengine.Submitter, error)
+	now          func() time.Time
+
+	processMutex sync.Mutex // 自动提交与手动提交不并发处理台账
+}
+
+// NewSearchEngineSubmitService 创建搜索引擎 URL 提交服务
+func NewSearchEngineSubmitService(submissionRepo repo.SearchEngineSubmissionRepository, configRepo repo.SystemConfigRepository) *SearchEngineSubmitService {
+	s := &SearchEngineSubmitService{
+		repo:       submissionRepo,
+		configRepo: configRepo,
+		now:        utils.GetCurrentTime,
+	}
+	s.newSubmitter = s.defaultSubmitter
+	return s
+}
+
+// Config 读取配置，缺失或非法的项使用默认值
+func (s *SearchEngineSubmitService) Config() SearchEngineConfig {
+	cfg := SearchEngineConfig{
+		IntervalMinutes: entity.SearchEngineConfigDefaultIntervalMinutes,
+		MaxAttempts:     entity.SearchEngineConfigDefaultMaxAttempts,
+		Engines:         make(map[string]SearchEngineSetting, len(searchEngineKeys)),
+	}
+	for name, keys := range searchEngineKeys {
+		cfg.Engines[name] = SearchEngineSetting{DailyQuota: keys.defaultQuota}
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+
+	cfg.AutoSubmit, _ = s.configRepo.GetConfigBool(entity.SearchEngineConfigKeyAutoSubmit)
+	if v, err := s.configRepo.GetConfigInt(entity.SearchEngineConfigKeyIntervalMinutes); err == nil && v > 0 {
+		cfg.IntervalMinutes = v
+	}
+	if v, err := s.configRepo.GetConfigInt(entity.SearchEngineConfigKeyMaxAttempts); err == nil && v > 0 {
+		cfg.MaxAttempts = v
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.ConfigKeyWebsiteURL); err == nil {
+		cfg.SiteURL = strings.TrimRight(strings.TrimSpace(v), "/")
+	}
+	for name, keys := range searchEngineKeys {
+		setting := cfg.Engines[name]
+		setting.Enabled, _ = s.configRepo.GetConfigBool(keys.enabled)
+		if v, err := s.configRepo.GetConfigInt(keys.quota); err == nil && v >= 0 {
+			setting.DailyQuota = v
+		}
+		cfg.Engines[name] = setting
+	}
+	cfg.IndexNowKey, _ = s.configRepo.GetConfigValue(entity.SearchEngineConfigKeyIndexNowKey)
+	cfg.IndexNowEndpoint, _ = s.configRepo.GetConfigValue(entity.SearchEngineConfigKeyIndexNowURL)
+	cfg.BaiduToken, _ = s.configRepo.GetConfigValue(entity.SearchEngineConfigKeyBaiduToken)
+	return cfg
+}
+
+// SaveConfig 校验并保存配置；启用 IndexNow 但未填写密钥时自动生成
+func (s *SearchEngineSubmitService) SaveConfig(cfg SearchEngineConfig) (SearchEngineConfig, error) {
+	cfg.IndexNowKey = strings.TrimSpace(cfg.IndexNowKey)
+	if cfg.Engines[searchengine.EngineIndexNow].Enabled && cfg.IndexNowKey == "" {
+		key, err := generateIndexNowKey()
+		if err != nil {
+			return cfg, err
+		}
+		cfg.IndexNowKey = key
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	configs := []entity.SystemConfig{
+		{Key: entity.SearchEngineConfigKeyAutoSubmit, Value: strconv.FormatBool(cfg.AutoSubmit), Type: entity.ConfigTypeBool},
+		{Key: entity.SearchEngineConfigKeyIntervalMinutes, Value: strconv.Itoa(cfg.IntervalMinutes), Type: entity.ConfigTypeInt},
+		{Key: entity.SearchEngineConfigKeyMaxAttempts, Value: strconv.Itoa(cfg.MaxAttempts), Type: entity.ConfigTypeInt},
+		{Key: entity.SearchEngineConfigKeyIndexNowKey, Value: cfg.IndexNowKey, Type: entity.ConfigTypeString},
+		{Key: entity.SearchEngineConfigKeyIndexNowURL, Value: strings.TrimSpace(cfg.IndexNowEndpoint), Type: entity.ConfigTypeString},
+		{Key: entity.SearchEngineConfigKeyBaiduToken, Value: strings.TrimSpace(cfg.BaiduToken), Type: entity.ConfigTypeString},
+	}
+	for name, setting := range cfg.Engines {
+		keys := searchEngineKeys[name]
+		configs = append(configs,
+			entity.SystemConfig{Key: keys.enabled, Value: strconv.FormatBool(setting.Enabled), Type: entity.ConfigTypeBool},
+			entity.SystemConfig{Key: keys.quota, Value: strconv.Itoa(setting.DailyQuota), Type: entity.ConfigTypeInt},
+		)
+	}
+	if err := s.configRepo.UpsertConfigs(configs); err != nil {
+		return cfg, err
+	}
+	return s.Config(), nil
+}
+
+func generateIndexNowKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// defaultSubmitter 按配置创建搜索引擎提交器
+func (s *SearchEngineSubmitService) defaultSubmitter(engine string, cfg SearchEngineConfig) (searchengine.Submitter, error) {
+	switch engine {
+	case searchengine.EngineGoogle:
+		credentialsFile, err := s.configRepo.GetConfigValue(entity.GoogleIndexConfigKeyCredentialsFile)
+		if err != nil || credentialsFile == "" {
+			return nil, fmt.Errorf("未配置Google凭据文件")
+		}
+		client, err := google.NewClient(&google.Config{CredentialsFile: credentialsFile, SiteURL: cfg.SiteURL})
+		if err != nil {
+			return nil, fmt.Errorf("创建Google客户端失败: %v", err)
+		}
+		return searchengine.NewGoogleSubmitter(client), nil
+	case searchengine.EngineBing:
+		apiKey, _ := s.configRepo.GetConfigValue(entity.BingIndexConfigKeyAPIKey)
+		return searchengine.NewBingSubmitter(cfg.SiteURL, apiKey), nil
+	case searchengine.EngineIndexNow:
+		return searchengine.NewIndexNowSubmitter(cfg.IndexNowEndpoint, cfg.SiteURL, cfg.IndexNowKey), nil
+	case searchengine.EngineBaidu, searchengine.EngineBaiduDaily:
+		return searchengine.NewBaiduSubmitter(cfg.SiteURL, cfg.BaiduToken, engine == searchengine.EngineBaiduDaily), nil
+	default:
+		return nil, fmt.Errorf("不支持的搜索引擎: %s", engine)
+	}
+}
+
+// ResourcePageURL 资源详情页地址
+func ResourcePageURL(siteURL, key string) string {
+	return strings.TrimRight(siteURL, "/") + "/r/" + key
+}
+
+// Enqueue 将页面加入所有已启用搜索引擎的提交台账，返回新排队的记录数。
+// 已在排队的记录不变；force 为 false 时，最近已成功提交的 URL 不会重复排队。
+func (s *SearchEngineSubmitService) Enqueue(targets []SearchEngineTarget, force bool) (int, error) {
+	cfg := s.Config()
+	engines := cfg.EnabledEngines()
+	if len(engines) == 0 || len(targets) == 0 {
+		return 0, nil
+	}
+
+	urls := make([]string, 0, len(targets))
+	byURL := make(map[string]SearchEngineTarget, len(targets))
+	for _, t := range targets {
+		t.URL = strings.TrimSpace(t.URL)
+		if t.URL == "" {
+			continue
+		}
+		if _, ok := byURL[t.URL]; !ok {
+			urls = append(urls, t.URL)
+		}
+		byURL[t.URL] = t
+	}
+
+	now := s.now()
+	queued := 0
+	for _, engine := range engines {
+		existing, err := s.repo.FindByEngineAndURLs(engine, urls)
+		if err != nil {
+			return queued, err
+		}
+		existingByURL := make(map[string]entity.SearchEngineSubmission, len(existing))
+		for _, e := range existing {
+			existingByURL[e.URL] = e
+		}
+
+		var changed []entity.SearchEngineSubmission
+		for _, u := range urls {
+			record, ok := existingByURL[u]
+			if ok {
+				if record.Status == entity.SearchEngineSubmissionPending {
+					continue
+				}
+				if !force && record.Status == entity.SearchEngineSubmissionSuccess &&
+					record.SubmittedAt != nil && now.Sub(*record.SubmittedAt) < searchEngineResubmitInterval {
+					continue
+				}
+			} else {
+				record = entity.SearchEngineSubmission{Engine: engine, URL: u}
+			}
+			if byURL[u].ResourceID != nil {
+				record.ResourceID = byURL[u].ResourceID
+			}
+			record.Status = entity.SearchEngineSubmissionPending
+			record.Attempts = 0
+			record.QueuedAt = now
+			changed = append(changed, record)
+		}
+		if err := s.repo.SaveAll(changed); err != nil {
+			return queued, err
+		}
+		queued += len(changed)
+	}
+	return queued, nil
+}
+
+// CollectUpdated 扫描游标之后新增或更新的公开资源并加入提交台账。
+// 首次运行只记录当前时间作为起点，不会把全部历史资源排队。
+func (s *SearchEngineSubmitService) CollectUpdated(limit int) (int, error) {
+	cfg := s.Config()
+	if cfg.SiteURL == "" {
+		return 0, fmt.Errorf("未配置网站地址")
+	}
+
+	cursorTime, cursorID, ok := s.loadCursor()
+	if !ok {
+		return 0, s.saveCursor(s.now(), 0)
+	}
+	resources, err := s.repo.FindResourcesUpdatedAfter(cursorTime, cursorID, limit)
+	if err != nil || len(resources) == 0 {
+		return 0, err
+	}
+
+	// 同一 Key 的资源共用一个详情页
+	targets := make([]SearchEngineTarget, 0, len(resources))
+	seen := make(map[string]bool, len(resources))
+	for i := range resources {
+		if seen[resources[i].Key] {
+			continue
+		}
+		seen[resources[i].Key] = true
+		id := resources[i].ID
+		targets = append(targets, SearchEngineTarget{URL: ResourcePageURL(cfg.SiteURL, resources[i].Key), ResourceID: &id})
+	}
+	queued, err := s.Enqueue(targets, false)
+	if err != nil {
+		return queued, err
+	}
+	last := resources[len(resources)-1]
+	return queued, s.saveCursor(last.UpdatedAt, last.ID)
+}
+
+func (s *SearchEngineSubmitService) loadCursor() (time.Time, uint, bool) {
+	v, err := s.configRepo.GetConfigValue(entity.SearchEngineConfigKeyCursor)
+	if err != nil || v == "" {
+		return time.Time{}, 0, false
+	}
+	parts := strings.SplitN(v, "|", 2)
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	var id uint64
+	if len(parts) == 2 {
+		id, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+	return t, uint(id), true
+}
+
+func (s *SearchEngineSubmitService) saveCursor(t time.Time, id uint) error {
+	return s.configRepo.UpsertConfigs([]entity.SystemConfig{{
+		Key:   entity.SearchEngineConfigKeyCursor,
+		Value: t.Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(id), 10),
+		Type:  entity.ConfigTypeString,
+	}})
+}
+
+// Process 按每日配额向已启用的搜索引擎提交台账中待提交的 URL
+func (s *SearchEngineSubmitService) Process(ctx context.Context) ([]SearchEngineRunResult, error) {
+	s.processMutex.Lock()
+	defer s.processMutex.Unlock()
+
+	cfg := s.Config()
+	if cfg.SiteURL == "" {
+		return nil, fmt.Errorf("未配置网站地址")
+	}
+	today := s.now().Format("2006-01-02")
+	usage, err := s.usageByEngine(today)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchEngineRunResult
+	for _, engine := range cfg.EnabledEngines() {
+		if ctx.Err() != nil {
+			break
+		}
+		result := SearchEngineRunResult{Engine: engine}
+		used := usage[engine]
+		remaining := cfg.Engines[engine].DailyQuota - used.Used
+		if used.Exhausted || remaining <= 0 {
+			result.QuotaExhausted = true
+			results = append(results, result)
+			continue
+		}
+		if remaining > searchEngineProcessLimit {
+			remaining = searchEngineProcessLimit
+		}
+
+		records, err := s.repo.FindSubmittable(engine, cfg.MaxAttempts, remaining)
+		if err != nil {
+			return results, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+		submitter, err := s.newSubmitter(engine, cfg)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			utils.Error("[SearchEngine] 创建 %s 提交器失败: %v", engine, err)
+			continue
+		}
+
+		s.submitRecords(ctx, submitter, records, today, &result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// submitRecords 分批提交并保存结果、累计配额
+func (s *SearchEngineSubmitService) submitRecords(ctx context.Context, submitter searchengine.Submitter, records []entity.SearchEngineSubmission, today string, result *SearchEngineRunResult) {
+	batch := submitter.MaxBatch()
+	if batch <= 0 {
+		batch = len(records)
+	}
+	for start := 0; start < len(records) && !result.QuotaExhausted; start += batch {
+		end := start + batch
+		if end > len(records) {
+			end = len(records)
+		}
+		chunk := records[start:end]
+		urls := make([]string, len(chunk))
+		for i := range chunk {
+			urls[i] = chunk[i].URL
+		}
+
+		submitResults, err := submitter.Submit(ctx, urls)
+		var quotaErr *searchengine.QuotaError
+		if errors.As(err, &quotaErr) {
+			result.QuotaExhausted = true
+		}
+		byURL := make(map[string]searchengine.Result, len(submitResults))
+		for _, r := range submitResults {
+			byURL[r.URL] = r
+		}
+
+		now := s.now()
+		var changed []entity.SearchEngineSubmission
+		for i := range chunk {
+			record := chunk[i]
+			r, ok := byURL[record.URL]
+			switch {
+			case ok:
+				record.Message = truncateRunes(r.Message, 500)
+				record.Status = entity.SearchEngineSubmissionFailed
+				if r.Success {
+					record.Status = entity.SearchEngineSubmissionSuccess
+					result.Succeeded++
+				} else {
+					result.Failed++
+				}
+			case err != nil && !result.QuotaExhausted && ctx.Err() == nil:
+				record.Status = entity.SearchEngineSubmissionFailed
+				record.Message = truncateRunes(err.Error(), 500)
+				result.Failed++
+			default:
+				// 配额用尽或任务取消时未提交的记录保持原状态，下次继续
+				continue
+			}
+			record.Attempts++
+			record.SubmittedAt = &now
+			changed = append(changed, record)
+		}
+		result.Submitted += len(submitResults)
+
+		if saveErr := s.repo.SaveAll(changed); saveErr != nil {
+			utils.Error("[SearchEngine] 保存 %s 提交结果失败: %v", submitter.Name(), saveErr)
+		}
+		if usageErr := s.repo.AddUsage(submitter.Name(), today, len(submitResults), result.QuotaExhausted); usageErr != nil {
+			utils.Error("[SearchEngine] 更新 %s 配额使用量失败: %v", submitter.Name(), usageErr)
+		}
+		if err != nil {
+			if result.QuotaExhausted {
+				utils.Warn("[SearchEngine] %v", err)
+			} else {
+				result.Error = err.Error()
+				utils.Error("[SearchEngine] 提交到 %s 失败: %v", submitter.Name(), err)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *SearchEngineSubmitService) usageByEngine(date string) (map[string]entity.SearchEngineQuotaUsage, error) {
+	list, err := s.repo.FindUsageByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]entity.SearchEngineQuotaUsage, len(list))
+	for _, u := range list {
+		usage[u.Engine] = u
+	}
+	return usage, nil
+}
+
+// RunOnce 收集新增/更新的资源页面并提交，供调度器调用
+func (s *SearchEngineSubmitService) RunOnce(ctx context.Context) ([]SearchEngineRunResult, error) {
+	if queued, err := s.CollectUpdated(searchEngineCollectLimit); err != nil {
+		utils.Error("[SearchEngine] 收集新增/更新资源失败: %v", err)
+	} else if queued > 0 {
+		utils.Info("[SearchEngine] 新排队 %d 条URL提交记录", queued)
+	}
+	return s.Process(ctx)
+}
+
+// EnqueueURLs 手动提交本站 URL：强制重新排队，由下一次 Process 提交
+func (s *SearchEngineSubmitService) EnqueueURLs(urls []string) (int, error) {
+	cfg := s.Config()
+	if cfg.SiteURL == "" {
+		return 0, fmt.Errorf("未配置网站地址")
+	}
+	targets := make([]SearchEngineTarget, 0, len(urls))
+	for _, u := range urls {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		if u != cfg.SiteURL && !strings.HasPrefix(u, cfg.SiteURL+"/") {
+			return 0, fmt.Errorf("URL不属于本站: %s", u)
+		}
+		targets = append(targets, SearchEngineTarget{URL: u})
+	}
+	return s.Enqueue(targets, true)
+}
+
+// RequeueFailed 将失败记录重新排队，engine 为空时处理所有搜索引擎
+func (s *SearchEngineSubmitService) RequeueFailed(engine string) (int64, error) {
+	return s.repo.RequeueFailed(engine, s.now())
+}
+
+// List 分页查询提交台账
+func (s *SearchEngineSubmitService) List(engine, status, keyword string, page, pageSize int) ([]entity.SearchEngineSubmission, int64, error) {
+	return s.repo.FindWithFilters(engine, status, keyword, page, pageSize)
+}
+
+// Stats 各搜索引擎的开关、今日配额使用量与台账状态统计
+func (s *SearchEngineSubmitService) Stats() ([]SearchEngineStat, error) {
+	cfg := s.Config()
+	usage, err := s.usageByEngine(s.now().Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.CountByEngineStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]SearchEngineStat, 0, len(searchengine.Engines))
+	index := make(map[string]int, len(searchengine.Engines))
+	for _, name := range searchengine.Engines {
+		index[name] = len(stats)
+		stats = append(stats, SearchEngineStat{
+			Engine:         name,
+			Enabled:        cfg.Engines[name].Enabled,
+			DailyQuota:     cfg.Engines[name].DailyQuota,
+			UsedToday:      usage[name].Used,
+			QuotaExhausted: usage[name].Exhausted,
+			Counts:         map[string]int64{},
+		})
+	}
+	for _, c := range counts {
+		if i, ok := index[c.Engine]; ok {
+			stats[i].Counts[c.Status] = c.Count
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/searchengine"
+)
+
+// fakeSearchEngineConfigRepo 以 map 保存系统配置
+type fakeSearchEngineConfigRepo struct {
+	repo.SystemConfigRepository
+	values map[string]string
+}
+
+func (f *fakeSearchEngineConfigRepo) GetConfigValue(key string) (string, error) {
+	return f.values[key], nil
+}
+
+func (f *fakeSearchEngineConfigRepo) GetConfigBool(key string) (bool, error) {
+	return f.values[key] == "true", nil
+}
+
+func (f *fakeSearchEngineConfigRepo) GetConfigInt(key string) (int, error) {
+	return strconv.Atoi(f.values[key])
+}
+
+func (f *fakeSearchEngineConfigRepo) UpsertConfigs(configs []entity.SystemConfig) error {
+	for _, c := range configs {
+		f.values[c.Key] = c.Value
+	}
+	return nil
+}
+
+// fakeSubmissionRepo 内存中的提交台账
+type fakeSubmissionRepo struct {
+	repo.SearchEngineSubmissionRepository
+	records   []entity.SearchEngineSubmission
+	usage     map[string]entity.SearchEngineQuotaUsage
+	resources []entity.Resource
+	nextID    uint
+}
+
+func (f *fakeSubmissionRepo) FindByEngineAndURLs(engine string, urls []string) ([]entity.SearchEngineSubmission, error) {
+	want := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		want[u] = true
+	}
+	var list []entity.SearchEngineSubmission
+	for _, r := range f.records {
+		if r.Engine == engine && want[r.URL] {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSubmissionRepo) SaveAll(list []entity.SearchEngineSubmission) error {
+	for _, item := range list {
+		if item.ID == 0 {
+			f.nextID++
+			item.ID = f.nextID
+			f.records = append(f.records, item)
+			continue
+		}
+		for i := range f.records {
+			if f.records[i].ID == item.ID {
+				f.records[i] = item
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeSubmissionRepo) FindSubmittable(engine string, maxAttempts, limit int) ([]entity.SearchEngineSubmission, error) {
+	var list []entity.SearchEngineSubmission
+	for _, r := range f.records {
+		if len(list) >= limit {
+			break
+		}
+		if r.Engine == engine && (r.Status == entity.SearchEngineSubmissionPending ||
+			(r.Status == entity.SearchEngineSubmissionFailed && r.Attempts < maxAttempts)) {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSubmissionRepo) FindUsageByDate(date string) ([]entity.SearchEngineQuotaUsage, error) {
+	var list []entity.SearchEngineQuotaUsage
+	for _, u := range f.usage {
+		if u.Date == date {
+			list = append(list, u)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSubmissionRepo) AddUsage(engine, date string, used int, exhausted bool) error {
+	if f.usage == nil {
+		f.usage = map[string]entity.SearchEngineQuotaUsage{}
+	}
+	u := f.usage[engine]
+	u.Engine, u.Date = engine, date
+	u.Used += used
+	u.Exhausted = u.Exhausted || exhausted
+	f.usage[engine] = u
+	return nil
+}
+
+func (f *fakeSubmissionRepo) FindResourcesUpdatedAfter(updatedAt time.Time, id uint, limit int) ([]entity.Resource, error) {
+	var list []entity.Resource
+	for _, r := range f.resources {
+		if r.UpdatedAt.After(updatedAt) || (r.UpdatedAt.Equal(updatedAt) && r.ID > id) {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSubmissionRepo) byURL(engine, url string) *entity.SearchEngineSubmission {
+	for i := range f.records {
+		if f.records[i].Engine == engine && f.records[i].URL == url {
+			return &f.records[i]
+		}
+	}
+	return nil
+}
+
+// fakeSearchEngineSubmitter 按 URL 返回预设结果；quotaAfter 之后的 URL 触发配额错误
+type fakeSearchEngineSubmitter struct {
+	name       string
+	maxBatch   int
+	fail       map[string]bool
+	quotaAfter int
+	sent       []string
+}
+
+func (f *fakeSearchEngineSubmitter) Name() string  { return f.name }
+func (f *fakeSearchEngineSubmitter) MaxBatch() int { return f.maxBatch }
+
+func (f *fakeSearchEngineSubmitter) Submit(_ context.Context, urls []string) ([]searchengine.Result, error) {
+	var results []searchengine.Result
+	for _, u := range urls {
+		if f.quotaAfter > 0 && len(f.sent) >= f.quotaAfter {
+			return results, &searchengine.QuotaError{Engine: f.name, Message: "quota"}
+		}
+		f.sent = append(f.sent, u)
+		results = append(results, searchengine.Result{URL: u, Success: !f.fail[u]})
+	}
+	return results, nil
+}
+
+var searchEngineTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestSearchEngineService(values map[string]string, submissionRepo *fakeSubmissionRepo, submitter *fakeSearchEngineSubmitter) *SearchEngineSubmitService {
+	base := map[string]string{
+		entity.ConfigKeyWebsiteURL:                    "https://pan.example.com/",
+		entity.SearchEngineConfigKeyIndexNowEnabled:   "true",
+		entity.SearchEngineConfigKeyIndexNowQuota:     "3",
+		entity.SearchEngineConfigKeyMaxAttempts:       "2",
+		entity.SearchEngineConfigKeyIntervalMinutes:   "30",
+		entity.SearchEngineConfigKeyIndexNowKey:       "abcdef123456",
+		entity.SearchEngineConfigKeyBaiduDailyEnabled: "false",
+	}
+	for k, v := range values {
+		base[k] = v
+	}
+	s := NewSearchEngineSubmitService(submissionRepo, &fakeSearchEngineConfigRepo{values: base})
+	s.now = func() time.Time { return searchEngineTestNow }
+	s.newSubmitter = func(engine string, _ SearchEngineConfig) (searchengine.Submitter, error) {
+		if submitter == nil {
+			return nil, errors.New("no submitter")
+		}
+		return submitter, nil
+	}
+	return s
+}
+
+func TestSearchEngineEnqueue(t *testing.T) {
+	recent := searchEngineTestNow.Add(-time.Hour)
+	old := searchEngineTestNow.Add(-48 * time.Hour)
+	tests := []struct {
+		name       string
+		existing   *entity.SearchEngineSubmission
+		force      bool
+		wantQueued int
+	}{
+		{"新URL排队", nil, false, 1},
+		{"已在排队不重复", &entity.SearchEngineSubmission{Status: entity.SearchEngineSubmissionPending}, true, 0},
+		{"近期已成功跳过", &entity.SearchEngineSubmission{Status: entity.SearchEngineSubmissionSuccess, SubmittedAt: &recent}, false, 0},
+		{"近期已成功强制排队", &entity.SearchEngineSubmission{Status: entity.SearchEngineSubmissionSuccess, SubmittedAt: &recent}, true, 1},
+		{"成功已久重新排队", &entity.SearchEngineSubmission{Status: entity.SearchEngineSubmissionSuccess, SubmittedAt: &old}, false, 1},
+		{"失败记录重置", &entity.SearchEngineSubmission{Status: entity.SearchEngineSubmissionFailed, Attempts: 2}, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "https://pan.example.com/r/abc"
+			submissionRepo := &fakeSubmissionRepo{}
+			if tt.existing != nil {
+				existing := *tt.existing
+				existing.Engine, existing.URL = searchengine.EngineIndexNow, url
+				_ = submissionRepo.SaveAll([]entity.SearchEngineSubmission{existing})
+			}
+			s := newTestSearchEngineService(nil, submissionRepo, nil)
+
+			queued, err := s.Enqueue([]SearchEngineTarget{{URL: url}, {URL: url}}, tt.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if queued != tt.wantQueued {
+				t.Errorf("queued = %d, want %d", queued, tt.wantQueued)
+			}
+			if len(submissionRepo.records) != 1 {
+				t.Fatalf("records = %d, want 1", len(submissionRepo.records))
+			}
+			if r := submissionRepo.records[0]; tt.wantQueued == 1 && (r.Status != entity.SearchEngineSubmissionPending || r.Attempts != 0) {
+				t.Errorf("record not requeued: %+v", r)
+			}
+		})
+	}
+}
+
+func TestSearchEngineProcessRespectsQuota(t *testing.T) {
+	submissionRepo := &fakeSubmissionRepo{}
+	s := newTestSearchEngineService(nil, submissionRepo, nil)
+	var targets []SearchEngineTarget
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		targets = append(targets, SearchEngineTarget{URL: ResourcePageURL("https://pan.example.com", k)})
+	}
+	if _, err := s.Enqueue(targets, false); err != nil {
+		t.Fatal(err)
+	}
+
+	submitter := &fakeSearchEngineSubmitter{name: searchengine.EngineIndexNow, maxBatch: 2,
+		fail: map[string]bool{"https://pan.example.com/r/b": true}}
+	s.newSubmitter = func(string, SearchEngineConfig) (searchengine.Submitter, error) { return submitter, nil }
+
+	results, err := s.Process(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Submitted != 3 || results[0].Succeeded != 2 || results[0].Failed != 1 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if got := submissionRepo.usage[searchengine.EngineIndexNow].Used; got != 3 {
+		t.Errorf("used = %d, want 3", got)
+	}
+	if r := submissionRepo.byURL(searchengine.EngineIndexNow, "https://pan.example.com/r/b"); r.Status != entity.SearchEngineSubmissionFailed || r.Attempts != 1 {
+		t.Errorf("failed record = %+v", r)
+	}
+	if r := submissionRepo.byURL(searchengine.EngineIndexNow, "https://pan.example.com/r/d"); r.Status != entity.SearchEngineSubmissionPending {
+		t.Errorf("record beyond quota should stay pending: %+v", r)
+	}
+
+	// 当天配额已用尽，不再提交
+	results, _ = s.Process(context.Background())
+	if len(results) != 1 || !results[0].QuotaExhausted || len(submitter.sent) != 3 {
+		t.Errorf("expected quota exhausted, results=%+v sent=%v", results, submitter.sent)
+	}
+}
+
+func TestSearchEngineProcessQuotaErrorKeepsRemaining(t *testing.T) {
+	submissionRepo := &fakeSubmissionRepo{}
+	s := newTestSearchEngineService(map[string]string{entity.SearchEngineConfigKeyIndexNowQuota: "100"}, submissionRepo, nil)
+	targets := []SearchEngineTarget{{URL: "https://pan.example.com/r/a"}, {URL: "https://pan.example.com/r/b"}, {URL: "https://pan.example.com/r/c"}}
+	if _, err := s.Enqueue(targets, false); err != nil {
+		t.Fatal(err)
+	}
+	submitter := &fakeSearchEngineSubmitter{name: searchengine.EngineIndexNow, maxBatch: 10, quotaAfter: 1}
+	s.newSubmitter = func(string, SearchEngineConfig) (searchengine.Submitter, error) { return submitter, nil }
+
+	results, err := s.Process(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].QuotaExhausted || results[0].Succeeded != 1 || results[0].Error != "" {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+	if !submissionRepo.usage[searchengine.EngineIndexNow].Exhausted {
+		t.Error("usage should be marked exhausted")
+	}
+	for _, u := range []string{"https://pan.example.com/r/b", "https://pan.example.com/r/c"} {
+		if r := submissionRepo.byURL(searchengine.EngineIndexNow, u); r.Status != entity.SearchEngineSubmissionPending || r.Attempts != 0 {
+			t.Errorf("%s should stay untouched: %+v", u, r)
+		}
+	}
+}
+
+func TestSearchEngineCollectUpdated(t *testing.T) {
+	submissionRepo := &fakeSubmissionRepo{}
+	s := newTestSearchEngineService(nil, submissionRepo, nil)
+	configRepo := s.configRepo.(*fakeSearchEngineConfigRepo)
+
+	submissionRepo.resources = []entity.Resource{{ID: 1, Key: "old", UpdatedAt: searchEngineTestNow.Add(-time.Hour)}}
+	queued, err := s.CollectUpdated(100)
+	if err != nil || queued != 0 {
+		t.Fatalf("first run queued = %d, err = %v", queued, err)
+	}
+	if configRepo.values[entity.SearchEngineConfigKeyCursor] == "" {
+		t.Fatal("first run should save cursor")
+	}
+
+	later := searchEngineTestNow.Add(time.Minute)
+	submissionRepo.resources = append(submissionRepo.resources,
+		entity.Resource{ID: 2, Key: "k1", UpdatedAt: later},
+		entity.Resource{ID: 3, Key: "k1", UpdatedAt: later},
+		entity.Resource{ID: 4, Key: "k2", UpdatedAt: later},
+	)
+	queued, err = s.CollectUpdated(100)
+	if err != nil || queued != 2 {
+		t.Fatalf("queued = %d, err = %v, want 2", queued, err)
+	}
+	if r := submissionRepo.byURL(searchengine.EngineIndexNow, "https://pan.example.com/r/k1"); r == nil || r.ResourceID == nil || *r.ResourceID != 2 {
+		t.Errorf("k1 record = %+v", r)
+	}
+
+	// 游标已推进，再次扫描不会重复排队
+	if queued, _ = s.CollectUpdated(100); queued != 0 {
+		t.Errorf("queued again = %d", queued)
+	}
+}
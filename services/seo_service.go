@@ -0,0 +1,255 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/metadata"
+	"github.com/ctwj/urldb/pkg/seo"
+)
+
+// ErrSEOResourceNotFound Key 下没有任何资源（含失效资源）
+var ErrSEOResourceNotFound = errors.New("资源不存在")
+
+var hreflangRe = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// SEOConfig 资源页 SEO 配置
+type SEOConfig struct {
+	TitleTemplate       string   `json:"title_template"`
+	DescriptionTemplate string   `json:"description_template"`
+	Hreflang            []string `json:"hreflang"`
+	Placeholders        []string `json:"placeholders"` // 只读，模板可用的占位符
+}
+
+// Validate 校验配置
+func (c SEOConfig) Validate() error {
+	if strings.TrimSpace(c.TitleTemplate) == "" {
+		return fmt.Errorf("标题模板不能为空")
+	}
+	if len([]rune(c.TitleTemplate)) > 200 || len([]rune(c.DescriptionTemplate)) > 500 {
+		return fmt.Errorf("模板过长")
+	}
+	for _, lang := range c.Hreflang {
+		if !hreflangRe.MatchString(lang) {
+			return fmt.Errorf("无效的语言代码: %s", lang)
+		}
+	}
+	return nil
+}
+
+// SEOService 资源页 SEO 数据服务：按 Key 汇总资源、分类、标签与影视信息，生成 canonical、meta 与 JSON-LD
+type SEOService struct {
+	resourceRepo repo.ResourceRepository
+	hotDramaRepo repo.HotDramaRepository
+	configRepo   repo.SystemConfigRepository
+
+	// metadataFor 按 Key 查询影视元数据，未启用元数据服务时为 nil
+	metadataFor func(key string) *entity.ResourceMetadata
+}
+
+// NewSEOService 创建资源页 SEO 数据服务
+func NewSEOService(resourceRepo repo.ResourceRepository, hotDramaRepo repo.HotDramaRepository, configRepo repo.SystemConfigRepository, metadataService *MetadataService) *SEOService {
+	s := &SEOService{
+		resourceRepo: resourceRepo,
+		hotDramaRepo: hotDramaRepo,
+		configRepo:   configRepo,
+	}
+	if metadataService != nil {
+		s.metadataFor = metadataService.ForKey
+	}
+	return s
+}
+
+// Config 读取配置，缺失的项使用默认值
+func (s *SEOService) Config() SEOConfig {
+	cfg := SEOConfig{
+		TitleTemplate:       entity.SEOConfigDefaultTitleTemplate,
+		DescriptionTemplate: entity.SEOConfigDefaultDescriptionTemplate,
+		Hreflang:            splitList(entity.SEOConfigDefaultHreflang),
+		Placeholders:        seo.Placeholders,
+	}
+	if s.configRepo == nil {
+		return cfg
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.SEOConfigKeyTitleTemplate); err == nil && strings.TrimSpace(v) != "" {
+		cfg.TitleTemplate = v
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.SEOConfigKeyDescriptionTemplate); err == nil && strings.TrimSpace(v) != "" {
+		cfg.DescriptionTemplate = v
+	}
+	if v, err := s.configRepo.GetConfigValue(entity.SEOConfigKeyHreflang); err == nil {
+		cfg.Hreflang = splitList(v)
+	}
+	return cfg
+}
+
+// SaveConfig 校验并保存配置
+func (s *SEOService) SaveConfig(cfg SEOConfig) (SEOConfig, error) {
+	cfg.TitleTemplate = strings.TrimSpace(cfg.TitleTemplate)
+	cfg.DescriptionTemplate = strings.TrimSpace(cfg.DescriptionTemplate)
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	configs := []entity.SystemConfig{
+		{Key: entity.SEOConfigKeyTitleTemplate, Value: cfg.TitleTemplate, Type: entity.ConfigTypeString},
+		{Key: entity.SEOConfigKeyDescriptionTemplate, Value: cfg.DescriptionTemplate, Type: entity.ConfigTypeString},
+		{Key: entity.SEOConfigKeyHreflang, Value: strings.Join(cfg.Hreflang, ","), Type: entity.ConfigTypeString},
+	}
+	if err := s.configRepo.UpsertConfigs(configs); err != nil {
+		return cfg, err
+	}
+	return s.Config(), nil
+}
+
+// ForKey 生成资源页的 SEO 数据；全组失效时仍返回（noindex），Key 不存在时返回 ErrSEOResourceNotFound
+func (s *SEOService) ForKey(key string) (*seo.Payload, error) {
+	res, err := s.loadResource(key)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := s.Config()
+	site := seo.Site{Languages: cfg.Hreflang}
+	site.URL, _ = s.configRepo.GetConfigValue(entity.ConfigKeyWebsiteURL)
+	site.URL = strings.TrimRight(strings.TrimSpace(site.URL), "/")
+	site.Title, _ = s.configRepo.GetConfigValue(entity.ConfigKeySiteTitle)
+	site.Description, _ = s.configRepo.GetConfigValue(entity.ConfigKeySiteDescription)
+
+	payload := seo.Build(site, *res, seo.Templates{Title: cfg.TitleTemplate, Description: cfg.DescriptionTemplate})
+	return &payload, nil
+}
+
+// loadResource 汇总同一 Key 下的资源
+func (s *SEOService) loadResource(key string) (*seo.Resource, error) {
+	resources, err := s.resourceRepo.FindByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		failed, err := s.resourceRepo.FindFailedResourceMetaByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if failed == nil {
+			return nil, ErrSEOResourceNotFound
+		}
+		return &seo.Resource{Key: key, Title: failed.Title, Cover: failed.Cover}, nil
+	}
+
+	res := &seo.Resource{Key: key, Valid: true}
+	tagSeen := make(map[string]bool)
+	panSeen := make(map[string]bool)
+	for _, r := range resources {
+		if res.Title == "" {
+			res.Title = r.Title
+		}
+		if res.Description == "" {
+			res.Description = r.Description
+		}
+		if res.Cover == "" {
+			res.Cover = r.Cover
+		}
+		if res.Category == "" && r.Category.Name != "" {
+			res.Category = r.Category.Name
+		}
+		if r.IsPublic {
+			res.Public = true
+		}
+		if res.CreatedAt.IsZero() || r.CreatedAt.Before(res.CreatedAt) {
+			res.CreatedAt = r.CreatedAt
+		}
+		if r.UpdatedAt.After(res.UpdatedAt) {
+			res.UpdatedAt = r.UpdatedAt
+		}
+		for _, t := range r.Tags {
+			if t.Name != "" && !tagSeen[t.Name] {
+				tagSeen[t.Name] = true
+				res.Tags = append(res.Tags, t.Name)
+			}
+		}
+		pan := r.Pan.Remark
+		if pan == "" {
+			pan = r.Pan.Name
+		}
+		if pan != "" && !panSeen[pan] {
+			panSeen[pan] = true
+			res.Pans = append(res.Pans, pan)
+		}
+	}
+	res.Media = s.media(key, res.Title)
+	return res, nil
+}
+
+// media 优先使用已匹配的影视元数据，其次按片名查找热播剧
+func (s *SEOService) media(key, title string) *seo.Media {
+	if s.metadataFor != nil {
+		if m := s.metadataFor(key); m.HasSubject() {
+			return &seo.Media{
+				Type:        m.MediaType,
+				Title:       m.Title,
+				Year:        m.Year,
+				Genres:      splitList(m.Genres),
+				Regions:     splitList(m.Regions),
+				Directors:   splitList(m.Directors),
+				Actors:      splitList(m.Actors),
+				Episodes:    m.Episodes,
+				Rating:      m.Rating,
+				RatingCount: m.RatingCount,
+				Poster:      m.PosterURL,
+				Summary:     MetadataSummary(m),
+				Plot:        m.Summary,
+				SameAs:      m.SubjectURL,
+			}
+		}
+	}
+
+	if s.hotDramaRepo == nil {
+		return nil
+	}
+	name := metadata.ParseTitle(title).Name
+	if name == "" {
+		return nil
+	}
+	drama, err := s.hotDramaRepo.FindByTitle(name)
+	if err != nil || drama == nil {
+		return nil
+	}
+	media := &seo.Media{
+		Title:       drama.Title,
+		Genres:      splitList(drama.Genres),
+		Regions:     splitList(drama.Region),
+		Directors:   splitList(drama.Directors),
+		Actors:      splitList(drama.Actors),
+		Rating:      drama.Rating,
+		RatingCount: drama.RatingCount,
+		Poster:      drama.PosterURL,
+		Summary:     drama.CardSubtitle,
+		SameAs:      drama.DoubanURI,
+	}
+	switch drama.Category {
+	case "电影":
+		media.Type = "movie"
+	case "电视剧":
+		media.Type = "tv"
+	}
+	fmt.Sscanf(drama.Year, "%d", &media.Year)
+	return media
+}
+
+// splitList 拆分逗号（含中文逗号、顿号、斜杠）分隔的列表并去掉空项
+func splitList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '，' || r == '、' || r == '/'
+	})
+	list := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			list = append(list, f)
+		}
+	}
+	return list
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/seo"
+)
+
+// fakeSEOResourceRepo 按 Key 返回预设的有效资源与失效资源信息
+type fakeSEOResourceRepo struct {
+	repo.ResourceRepository
+	byKey  map[string][]entity.Resource
+	failed map[string]*repo.FailedResourceMeta
+}
+
+func (f *fakeSEOResourceRepo) FindByKey(key string) ([]entity.Resource, error) {
+	return f.byKey[key], nil
+}
+
+func (f *fakeSEOResourceRepo) FindFailedResourceMetaByKey(key string) (*repo.FailedResourceMeta, error) {
+	return f.failed[key], nil
+}
+
+type fakeSEOHotDramaRepo struct {
+	repo.HotDramaRepository
+	dramas map[string]*entity.HotDrama
+}
+
+func (f *fakeSEOHotDramaRepo) FindByTitle(title string) (*entity.HotDrama, error) {
+	if d, ok := f.dramas[title]; ok {
+		return d, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func newTestSEOService(resourceRepo *fakeSEOResourceRepo, dramaRepo *fakeSEOHotDramaRepo) *SEOService {
+	configRepo := &fakeSearchEngineConfigRepo{values: map[string]string{
+		entity.ConfigKeyWebsiteURL:       "https://pan.example.com/",
+		entity.ConfigKeySiteTitle:        "老九网盘",
+		entity.ConfigKeySiteDescription:  "网盘资源搜索",
+		entity.SEOConfigKeyTitleTemplate: "{title} ({year}) - {site_title}",
+		entity.SEOConfigKeyHreflang:      "zh-CN, zh-Hans",
+	}}
+	return NewSEOService(resourceRepo, dramaRepo, configRepo, nil)
+}
+
+func TestSEOServiceForKeyAggregatesResources(t *testing.T) {
+	created := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	resourceRepo := &fakeSEOResourceRepo{byKey: map[string][]entity.Resource{
+		"k1": {
+			{Title: "狂飙 4K 全39集", Category: entity.Category{Name: "电视剧"}, Pan: entity.Pan{Name: "quark", Remark: "夸克网盘"},
+				Tags: []entity.Tag{{Name: "国产"}}, IsPublic: false, CreatedAt: created, UpdatedAt: created},
+			{Title: "狂飙", Cover: "https://img.example.com/k1.jpg", Pan: entity.Pan{Name: "baidu"},
+				Tags: []entity.Tag{{Name: "国产"}, {Name: "犯罪"}}, IsPublic: true, CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(2 * time.Hour)},
+		},
+	}}
+	dramaRepo := &fakeSEOHotDramaRepo{dramas: map[string]*entity.HotDrama{
+		"狂飙": {Title: "狂飙", Category: "电视剧", Year: "2023", Genres: "剧情,犯罪", Rating: 8.5, RatingCount: 500, DoubanURI: "https://movie.douban.com/subject/2/"},
+	}}
+	s := newTestSEOService(resourceRepo, dramaRepo)
+
+	p, err := s.ForKey("k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.NoIndex || p.Title != "狂飙 4K 全39集 (2023) - 老九网盘" || p.Image != "https://img.example.com/k1.jpg" {
+		t.Errorf("payload = %+v", p)
+	}
+	if len(p.Alternates) != 3 {
+		t.Errorf("alternates = %+v", p.Alternates)
+	}
+	work := p.JSONLD[0]
+	if work["@type"] != "TVSeries" || work["sameAs"] != "https://movie.douban.com/subject/2/" || work["datePublished"] != created.Format(time.RFC3339) {
+		t.Errorf("work = %+v", work)
+	}
+	if kw := work["keywords"]; kw != "国产,犯罪" {
+		t.Errorf("keywords = %v", kw)
+	}
+}
+
+func TestSEOServiceForKeyInvalidAndMissing(t *testing.T) {
+	resourceRepo := &fakeSEOResourceRepo{failed: map[string]*repo.FailedResourceMeta{
+		"gone": {Title: "已失效", Key: "gone"},
+	}}
+	s := newTestSEOService(resourceRepo, &fakeSEOHotDramaRepo{})
+
+	p, err := s.ForKey("gone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.NoIndex || p.Status != seo.StatusInvalid || p.Canonical != "https://pan.example.com/r/gone" {
+		t.Errorf("payload = %+v", p)
+	}
+	if _, err := s.ForKey("missing"); !errors.Is(err, ErrSEOResourceNotFound) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSEOConfigValidate(t *testing.T) {
+	s := newTestSEOService(&fakeSEOResourceRepo{}, &fakeSEOHotDramaRepo{})
+	if _, err := s.SaveConfig(SEOConfig{TitleTemplate: "{title}", Hreflang: []string{"zh_CN"}}); err == nil {
+		t.Error("expected invalid hreflang to be rejected")
+	}
+	cfg, err := s.SaveConfig(SEOConfig{TitleTemplate: " {title} | {site_title} ", DescriptionTemplate: "{description}", Hreflang: []string{"en-US"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TitleTemplate != "{title} | {site_title}" || len(cfg.Hreflang) != 1 || cfg.Hreflang[0] != "en-US" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
@@ -68,16 +68,21 @@ type TodosStats struct {
 //   - 通过构造函数注入 db 与 repoManager，避免对 package-level 全局状态的依赖
 //   - 保持与现有 GetStats handler 直接操作 DB 的模式一致（无额外抽象层）
 type StatsService struct {
-	db   *gorm.DB
-	repo *repo.RepositoryManager
+	db        *gorm.DB
+	repo      *repo.RepositoryManager
+	analytics *AnalyticsService
 }
 
 // NewStatsService 创建统计聚合服务实例
 func NewStatsService(database *gorm.DB, repoMgr *repo.RepositoryManager) *StatsService {
-	return &StatsService{
+	s := &StatsService{
 		db:   database,
 		repo: repoMgr,
 	}
+	if repoMgr != nil {
+		s.analytics = NewAnalyticsService(repoMgr.AnalyticsRepository, repoMgr.SystemConfigRepository)
+	}
+	return s
 }
 
 // GetSummary 仪表盘首屏聚合统计（含环比昨日与待办）
@@ -133,18 +138,19 @@ func (s *StatsService) GetSummary() (*StatsSummary, error) {
 	todayInvalid, _ := s.repo.ResourceRepository.CountInvalidByDate(todayStr)
 	todaySynced, _ := s.repo.ResourceRepository.CountSyncedByDate(todayStr)
 
-	// 009: 访问（获取资源）总次数 + 网盘/来源分布（均来自 resource_views）
-	viewsTotal, vErr := s.repo.ResourceViewRepository.GetTotalViews()
+	// 009: 访问（获取资源）总次数 + 网盘/来源分布
+	// 036: 改为日汇总表 + 未汇总日期的原始记录，原始记录按保留天数清理后总量不变
+	viewsTotal, vErr := s.analytics.TotalViews()
 	if vErr != nil {
 		utils.Error("GetSummary 获取访问总次数失败: %v", vErr)
 		viewsTotal = 0
 	}
-	panRows, pErr := s.repo.ResourceViewRepository.GetPanDistribution()
+	panRows, pErr := s.viewDistribution(repo.AnalyticsDimensionPan, "pan")
 	if pErr != nil {
 		utils.Error("GetSummary 获取网盘分布失败: %v", pErr)
 		panRows = nil
 	}
-	sourceRows, sErr := s.repo.ResourceViewRepository.GetSourceDistribution()
+	sourceRows, sErr := s.viewDistribution(repo.AnalyticsDimensionSource, "source")
 	if sErr != nil {
 		utils.Error("GetSummary 获取来源分布失败: %v", sErr)
 		sourceRows = nil
@@ -187,6 +193,49 @@ func (s *StatsService) GetSummary() (*StatsSummary, error) {
 	}, nil
 }
 
+// viewDistribution 全部历史的访问分布，转为 toViewDistribution 使用的行格式（keyField 为网盘名称或来源标识）
+func (s *StatsService) viewDistribution(dimension, keyField string) ([]map[string]interface{}, error) {
+	items, err := s.analytics.Leaderboard(dimension, 0, 100)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		key := item.Name
+		if dimension == repo.AnalyticsDimensionSource {
+			key = item.Key
+		}
+		rows = append(rows, map[string]interface{}{keyField: key, "count": item.Value})
+	}
+	return rows, nil
+}
+
+// ViewsTrend 最近 days 天（含今天）每日访问量，返回 {date, views}
+func (s *StatsService) ViewsTrend(days int) ([]map[string]interface{}, error) {
+	trend, err := s.analytics.Trend(repo.AnalyticsMetricViews, "", days)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]map[string]interface{}, 0, len(trend.Points))
+	for _, p := range trend.Points {
+		results = append(results, map[string]interface{}{"date": p.Date, "views": p.Value})
+	}
+	return results, nil
+}
+
+// SearchesTrend 最近 days 天（含今天）每日搜索量，返回 {date, searches}
+func (s *StatsService) SearchesTrend(days int) ([]map[string]interface{}, error) {
+	trend, err := s.analytics.Trend(repo.AnalyticsMetricSearches, "", days)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]map[string]interface{}, 0, len(trend.Points))
+	for _, p := range trend.Points {
+		results = append(results, map[string]interface{}{"date": p.Date, "searches": p.Value})
+	}
+	return results, nil
+}
+
 // toViewDistribution 将 repo 返回的分布行（map）转为前端分布项，并计算占比。
 // keyField 为分布键列名（"pan" 或 "source"）；total 为该分布的总计数，用于求 percent。
 func toViewDistribution(rows []map[string]interface{}, keyField string, total int64) []ViewDistributionItem {
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -17,11 +18,12 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/cronjob"
+	"github.com/ctwj/urldb/pkg/leader"
 	"github.com/ctwj/urldb/utils"
 	"golang.org/x/net/proxy"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/robfig/cron/v3"
 )
 
 // https://core.telegram.org/bots/api
@@ -42,8 +44,17 @@ type TelegramBotService interface {
 	HandleWebhookUpdate(c interface{})
 	CleanupDuplicateChannels() error
 	ManualPushToChannel(channelID uint) error
+	NotifyAdmin(text string) error
+	SetLeaderElector(elector *leader.Elector)
+	IsPolling() bool
 }
 
+// TelegramPollingJob 长轮询与频道推送的选主任务名
+const TelegramPollingJob = "telegram_polling"
+
+// TelegramPushJob 频道内容推送在任务调度器中的任务名，只在长轮询所在节点启用
+const TelegramPushJob = "telegram_push"
+
 type TelegramBotServiceImpl struct {
 	bot                *tgbotapi.BotAPI
 	isRunning          bool
@@ -58,11 +69,15 @@ type TelegramBotServiceImpl struct {
 	linkInFlight       sync.Map                    // 011：取链/转存去重（按 resourceID）
 	searchStatRepo     repo.SearchStatRepository   // 011-US3：搜索归因
 	resourceViewRepo   repo.ResourceViewRepository // 011-US3：取链归因
-	cronScheduler      *cron.Cron
+	channelImporter    *TelegramChannelImporter    // 频道帖子采集（channel_post）
+	metadataService    *MetadataService            // 推送消息中的影视元数据摘要
 	config             *TelegramBotConfig
 	pushHistory        map[int64][]uint // 每个频道的推送历史记录，最多100条
 	mu                 sync.RWMutex     // 用于保护pushHistory的读写锁
-	stopChan           chan struct{}    // 用于停止消息循环的channel
+	elector            *leader.Elector  // 多副本选主，长轮询与推送只在主节点运行
+	pollMu             sync.Mutex       // 保护 polling 与 pollStop
+	polling            bool             // 本节点是否在运行长轮询与推送
+	pollStop           chan struct{}    // 用于停止消息循环的channel
 }
 
 type TelegramBotConfig struct {
@@ -81,6 +96,7 @@ type TelegramBotConfig struct {
 	ProxyPassword      string
 	WelcomeEnabled     bool   // 入群欢迎开关
 	WelcomeMessage     string // 入群欢迎模板（支持 {{username}} {{chatname}} 占位符）
+	AdminChatID        int64  // 管理员通知会话ID，0 表示不发送
 }
 
 func NewTelegramBotService(
@@ -94,6 +110,8 @@ func NewTelegramBotService(
 	linkService ResourceLinkService,
 	searchStatRepo repo.SearchStatRepository,
 	resourceViewRepo repo.ResourceViewRepository,
+	channelImporter *TelegramChannelImporter,
+	metadataService *MetadataService,
 ) TelegramBotService {
 	return &TelegramBotServiceImpl{
 		isRunning:          false,
@@ -107,10 +125,10 @@ func NewTelegramBotService(
 		linkService:        linkService,
 		searchStatRepo:     searchStatRepo,
 		resourceViewRepo:   resourceViewRepo,
-		cronScheduler:      cron.New(),
+		channelImporter:    channelImporter,
+		metadataService:    metadataService,
 		config:             &TelegramBotConfig{},
 		pushHistory:        make(map[int64][]uint),
-		stopChan:           make(chan struct{}),
 	}
 }
 
@@ -141,6 +159,7 @@ func (s *TelegramBotServiceImpl) loadConfig() error {
 	// 初始化欢迎消息默认值
 	s.config.WelcomeEnabled = false
 	s.config.WelcomeMessage = entity.ConfigDefaultTelegramWelcomeMessage
+	s.config.AdminChatID = 0
 
 	// 统计配置项数量，用于汇总日志
 	configCount := 0
@@ -191,6 +210,10 @@ func (s *TelegramBotServiceImpl) loadConfig() error {
 			if config.Value != "" {
 				s.config.WelcomeMessage = config.Value
 			}
+		case entity.ConfigKeyTelegramAdminChatID:
+			if config.Value != "" {
+				fmt.Sscanf(config.Value, "%d", &s.config.AdminChatID)
+			}
 		default:
 			utils.Debug("未知Telegram配置: %s", config.Key)
 		}
@@ -314,21 +337,19 @@ func (s *TelegramBotServiceImpl) Start() error {
 	s.bot = bot
 	s.isRunning = true
 
-	// 重置停止信号channel
-	s.stopChan = make(chan struct{})
-
 	utils.Info("[TELEGRAM:SERVICE] Telegram Bot (@%s) 已启动", s.GetBotUsername())
 
-	// 启动推送调度器
-	s.startContentPusher()
-
 	// 设置 webhook（在实际部署时配置）
 	if err := s.setupWebhook(); err != nil {
 		utils.Error("[TELEGRAM:SERVICE] 设置 Webhook 失败: %v", err)
 	}
 
-	// 启动消息处理循环（长轮询模式）
-	go s.messageLoop()
+	// 启动推送调度器与消息处理循环（长轮询模式）
+	if s.elector == nil {
+		s.startPolling()
+	} else {
+		s.elector.Run(TelegramPollingJob, s.startPolling, s.stopPolling)
+	}
 
 	return nil
 }
@@ -341,20 +362,14 @@ func (s *TelegramBotServiceImpl) Stop() error {
 
 	utils.Info("[TELEGRAM:SERVICE] 开始停止 Telegram Bot 服务")
 
-	s.isRunning = false
-
-	// 安全地发送停止信号给消息循环
-	select {
-	case <-s.stopChan:
-		// channel 已经关闭
-	default:
-		// channel 未关闭，安全关闭
-		close(s.stopChan)
+	// 停止消息循环与推送调度器，配置了选主时同时释放锁，由其他节点接管
+	if s.elector == nil {
+		s.stopPolling()
+	} else {
+		s.elector.Resign(TelegramPollingJob)
 	}
 
-	if s.cronScheduler != nil {
-		s.cronScheduler.Stop()
-	}
+	s.isRunning = false
 
 	// 清理机器人实例以避免冲突
 	s.bot = nil
@@ -368,6 +383,44 @@ func (s *TelegramBotServiceImpl) IsRunning() bool {
 	return s.isRunning && s.bot != nil
 }
 
+// SetLeaderElector 设置多副本选主，需在 Start 之前调用。
+// 设置后只有主节点运行长轮询与频道推送，其他节点仍可发送消息（告警、手动推送）
+func (s *TelegramBotServiceImpl) SetLeaderElector(elector *leader.Elector) {
+	s.elector = elector
+}
+
+// IsPolling 本节点是否在运行长轮询与频道推送
+func (s *TelegramBotServiceImpl) IsPolling() bool {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	return s.polling
+}
+
+// startPolling 启动推送调度器与消息处理循环
+func (s *TelegramBotServiceImpl) startPolling() {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	if s.polling || s.bot == nil {
+		return
+	}
+	s.polling = true
+	s.pollStop = make(chan struct{})
+	s.startContentPusher()
+	go s.messageLoop(s.bot, s.pollStop)
+}
+
+// stopPolling 停止推送调度器与消息处理循环
+func (s *TelegramBotServiceImpl) stopPolling() {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	if !s.polling {
+		return
+	}
+	s.polling = false
+	close(s.pollStop)
+	cronjob.Default().Deactivate(TelegramPushJob)
+}
+
 // ReloadConfig 重新加载机器人配置
 func (s *TelegramBotServiceImpl) ReloadConfig() error {
 	utils.Info("[TELEGRAM:SERVICE] 开始重新加载配置...")
@@ -389,7 +442,8 @@ func (s *TelegramBotServiceImpl) GetRuntimeStatus() map[string]interface{} {
 		"is_running":      s.IsRunning(),
 		"bot_initialized": s.bot != nil,
 		"config_loaded":   s.config != nil,
-		"cron_running":    s.cronScheduler != nil,
+		"cron_running":    cronjob.Default().IsActive(TelegramPushJob),
+		"polling":         s.IsPolling(),
 		"username":        "",
 		"uptime":          0,
 	}
@@ -575,55 +629,171 @@ func (s *TelegramBotServiceImpl) setupWebhook() error {
 	return nil
 }
 
-// messageLoop 消息处理循环（长轮询模式）
-func (s *TelegramBotServiceImpl) messageLoop() {
+// messageLoop 消息处理循环（长轮询模式），stop 关闭后在当前这次拉取返回时退出
+func (s *TelegramBotServiceImpl) messageLoop(bot *tgbotapi.BotAPI, stop <-chan struct{}) {
 	utils.Info("[TELEGRAM:MESSAGE] 开始监听 Telegram 消息更新...")
 
-	// 确保机器人实例存在
-	if s.bot == nil {
-		utils.Error("[TELEGRAM:MESSAGE] 机器人实例为空，无法启动消息监听循环")
-		return
-	}
-
 	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-
-	updates := s.bot.GetUpdatesChan(u)
+	u.Timeout = 30
 
 	utils.Info("[TELEGRAM:MESSAGE] 消息监听循环已启动，等待消息...")
 
 	for {
 		select {
-		case <-s.stopChan:
+		case <-stop:
 			utils.Info("[TELEGRAM:MESSAGE] 收到停止信号，退出消息监听循环")
 			return
-		case update, ok := <-updates:
-			if !ok {
-				utils.Info("[TELEGRAM:MESSAGE] updates channel 已关闭，退出消息监听循环")
+		default:
+		}
+
+		updates, err := bot.GetUpdates(u)
+		if err != nil {
+			utils.Error("[TELEGRAM:MESSAGE] 拉取消息更新失败: %v，3秒后重试", err)
+			select {
+			case <-stop:
+				utils.Info("[TELEGRAM:MESSAGE] 收到停止信号，退出消息监听循环")
 				return
+			case <-time.After(3 * time.Second):
 			}
-			// 在处理消息前检查机器人是否仍在运行
-			if !s.isRunning || s.bot == nil {
-				utils.Info("[TELEGRAM:MESSAGE] 机器人已停止，忽略接收到的消息")
+			continue
+		}
+
+		for _, update := range updates {
+			// 停止后未确认的更新留给下一个主节点处理
+			select {
+			case <-stop:
+				utils.Info("[TELEGRAM:MESSAGE] 收到停止信号，退出消息监听循环")
 				return
+			default:
 			}
-			if update.Message != nil {
-				// 优先处理新成员入群事件（不进入通用消息路由）
-				if len(update.Message.NewChatMembers) > 0 {
-					utils.Info("[TELEGRAM:MESSAGE] 收到新成员入群事件 ChatID=%d 成员数=%d",
-						update.Message.Chat.ID, len(update.Message.NewChatMembers))
-					s.handleNewChatMembers(update.Message)
-				} else {
-					utils.Info("[TELEGRAM:MESSAGE] 接收到新消息更新")
-					s.handleMessage(update.Message)
-				}
-			} else if update.CallbackQuery != nil {
-				s.handleCallbackQuery(update.CallbackQuery)
-			} else {
-				utils.Debug("[TELEGRAM:MESSAGE] 接收到其他类型更新: %v", update)
+			if update.UpdateID >= u.Offset {
+				u.Offset = update.UpdateID + 1
 			}
+			s.handleUpdate(update)
+		}
+	}
+}
+
+// handleUpdate 按更新类型分发
+func (s *TelegramBotServiceImpl) handleUpdate(update tgbotapi.Update) {
+	if update.Message != nil {
+		// 优先处理新成员入群事件（不进入通用消息路由）
+		if len(update.Message.NewChatMembers) > 0 {
+			utils.Info("[TELEGRAM:MESSAGE] 收到新成员入群事件 ChatID=%d 成员数=%d",
+				update.Message.Chat.ID, len(update.Message.NewChatMembers))
+			s.handleNewChatMembers(update.Message)
+		} else {
+			utils.Info("[TELEGRAM:MESSAGE] 接收到新消息更新")
+			s.handleMessage(update.Message)
+		}
+	} else if update.CallbackQuery != nil {
+		s.handleCallbackQuery(update.CallbackQuery)
+	} else if update.ChannelPost != nil {
+		s.handleChannelPost(update.ChannelPost)
+	} else {
+		utils.Debug("[TELEGRAM:MESSAGE] 接收到其他类型更新: %v", update)
+	}
+}
+
+// handleChannelPost 处理频道帖子：已配置为采集频道时导入其中的网盘链接
+func (s *TelegramBotServiceImpl) handleChannelPost(message *tgbotapi.Message) {
+	if s.channelImporter == nil || message.Chat == nil {
+		return
+	}
+
+	post := TelegramChannelPost{
+		ChatID:    message.Chat.ID,
+		MessageID: int64(message.MessageID),
+		Date:      message.Time(),
+		Text:      channelPostText(message),
+	}
+	if !strings.Contains(post.Text, "http") {
+		return
+	}
+	if len(message.Photo) > 0 {
+		post.Cover = s.saveChannelPhoto(message.Photo[len(message.Photo)-1])
+	}
+
+	imported, err := s.channelImporter.ImportPost(post)
+	if err != nil {
+		utils.Error("[TELEGRAM:IMPORT] 导入频道帖子失败 ChatID=%d MessageID=%d: %v", post.ChatID, post.MessageID, err)
+		return
+	}
+	if imported > 0 {
+		utils.Info("[TELEGRAM:IMPORT] 频道 %s 帖子 %d 导入 %d 个链接", message.Chat.Title, message.MessageID, imported)
+	}
+}
+
+// channelPostText 拼接帖子正文（或图片说明），并把 text_link 隐藏的链接追加到末尾
+func channelPostText(message *tgbotapi.Message) string {
+	text, entities := message.Text, message.Entities
+	if text == "" {
+		text, entities = message.Caption, message.CaptionEntities
+	}
+
+	var hidden []string
+	for _, e := range entities {
+		if e.Type == "text_link" && e.URL != "" {
+			hidden = append(hidden, e.URL)
 		}
 	}
+	if len(hidden) > 0 {
+		text += "\n" + strings.Join(hidden, "\n")
+	}
+	return text
+}
+
+// saveChannelPhoto 下载频道帖子图片到上传目录，返回站内访问地址；失败返回空串。
+// Telegram 文件直链包含机器人 Token，不能直接作为封面保存。
+func (s *TelegramBotServiceImpl) saveChannelPhoto(photo tgbotapi.PhotoSize) string {
+	uploadDir := os.Getenv("UPLOAD_DIR")
+	if uploadDir == "" {
+		uploadDir = "./uploads"
+	}
+	fileName := photo.FileUniqueID + ".jpg"
+	dir := filepath.Join(uploadDir, "telegram")
+	accessURL := "/uploads/telegram/" + fileName
+
+	if _, err := os.Stat(filepath.Join(dir, fileName)); err == nil {
+		return accessURL
+	}
+
+	directURL, err := s.bot.GetFileDirectURL(photo.FileID)
+	if err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 获取图片地址失败: %v", err)
+		return ""
+	}
+	req, err := http.NewRequest(http.MethodGet, directURL, nil)
+	if err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 下载图片失败: %v", err)
+		return ""
+	}
+	resp, err := s.bot.Client.Do(req)
+	if err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 下载图片失败: %v", err)
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		utils.Warn("[TELEGRAM:IMPORT] 下载图片失败，状态码: %d", resp.StatusCode)
+		return ""
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 创建图片目录失败: %v", err)
+		return ""
+	}
+	f, err := os.Create(filepath.Join(dir, fileName))
+	if err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 保存图片失败: %v", err)
+		return ""
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		utils.Warn("[TELEGRAM:IMPORT] 保存图片失败: %v", err)
+		return ""
+	}
+	return accessURL
 }
 
 // handleMessage 处理接收到的消息
@@ -1043,6 +1213,7 @@ func (s *TelegramBotServiceImpl) handleResourceRequest(message *tgbotapi.Message
 		s.sendReply(message, "搜索服务暂时不可用，请稍后重试")
 		return
 	}
+	resources = filterBlockedResources(resources)
 
 	if len(resources) == 0 {
 		s.sendReply(message, "未找到该资源")
@@ -1144,6 +1315,7 @@ func (s *TelegramBotServiceImpl) handleSearchRequest(message *tgbotapi.Message,
 		s.sendReply(message, "搜索服务暂时不可用，请稍后重试")
 		return
 	}
+	docs = filterBlockedDocuments(docs)
 
 	// 011-US3：仅私聊计入搜索统计（群里只发启动器、不计；实际查看结果在私聊打开时由 handleSearchDeepLink 计）
 	if message.Chat.Type == "private" && s.searchStatRepo != nil {
@@ -1285,6 +1457,7 @@ func (s *TelegramBotServiceImpl) handleSearchDeepLink(message *tgbotapi.Message,
 		s.sendReply(message, "搜索服务暂时不可用，请稍后重试")
 		return
 	}
+	docs = filterBlockedDocuments(docs)
 	// 011-US3：记录搜索归因（telegram 来源）—— 每个用户私聊打开结果都计 1 次（与群里发起搜索各自独立）
 	if s.searchStatRepo != nil {
 		if err := s.searchStatRepo.RecordSearch(keyword, entity.SourceTelegram, "", "telegram-bot"); err != nil {
@@ -1434,6 +1607,7 @@ func (s *TelegramBotServiceImpl) handlePagingCallback(callback *tgbotapi.Callbac
 		s.editCallbackMessageText(callback, "搜索失败，请稍后重试。", nil)
 		return
 	}
+	docs = filterBlockedDocuments(docs)
 	if len(docs) == 0 {
 		utils.Info("[TELEGRAM:PAGING] 第 %d 页无结果 (sid=%s, 会话总数=%d)", page, sid, sess.Total)
 		s.editCallbackMessageText(callback, "该页无结果。", nil)
@@ -1482,7 +1656,7 @@ func (s *TelegramBotServiceImpl) handleGetLinkCallback(callback *tgbotapi.Callba
 
 	// 取资源
 	resource, err := s.resourceRepo.FindByID(uint(resourceID))
-	if err != nil || resource == nil {
+	if err != nil || resource == nil || !resourceAllowed(resource) {
 		s.sendChatMessage(chatID, "资源不存在或已被移除。", 0)
 		return
 	}
@@ -1683,33 +1857,44 @@ func (s *TelegramBotServiceImpl) sendReplyWithResourceAutoDelete(message *tgbota
 // startContentPusher 启动内容推送器
 func (s *TelegramBotServiceImpl) startContentPusher() {
 	// 每分钟检查一次需要推送的频道
-	s.cronScheduler.AddFunc("@every 1m", func() {
-		s.pushContentToChannels()
+	jobs := cronjob.Default()
+	err := jobs.Register(cronjob.Job{
+		Name:        TelegramPushJob,
+		Description: "推送内容到 Telegram 频道",
+		Spec:        "@every 1m",
+		Run: func(context.Context) (string, error) {
+			return s.pushContentToChannels()
+		},
 	})
-
-	s.cronScheduler.Start()
+	if err == nil {
+		err = jobs.Activate(TelegramPushJob)
+	}
+	if err != nil {
+		utils.Error("[TELEGRAM:PUSH:ERROR] 启动内容推送任务失败: %v", err)
+		return
+	}
 	utils.Info("[TELEGRAM:PUSH] 内容推送调度器已启动")
 }
 
 // pushContentToChannels 推送内容到频道
-func (s *TelegramBotServiceImpl) pushContentToChannels() {
+func (s *TelegramBotServiceImpl) pushContentToChannels() (string, error) {
 	// 获取需要推送的频道
 	channels, err := s.channelRepo.FindDueForPush()
 	if err != nil {
 		utils.Error("[TELEGRAM:PUSH:ERROR] 获取推送频道失败: %v", err)
-		return
+		return "", err
 	}
 
 	if len(channels) == 0 {
 		utils.Debug("[TELEGRAM:PUSH] 没有需要推送的频道")
-		return
+		return "没有需要推送的频道", cronjob.ErrSkipped
 	}
 
 	// 过滤出在允许推送时间段内的频道
 	validChannels := s.filterChannelsByTimeRange(channels)
 	if len(validChannels) == 0 {
 		utils.Info("[TELEGRAM:PUSH] 所有频道都不在推送时间段内")
-		return
+		return "所有频道都不在推送时间段内", cronjob.ErrSkipped
 	}
 
 	utils.Info("[TELEGRAM:PUSH] 开始推送内容到 %d 个频道（过滤前: %d 个频道）", len(validChannels), len(channels))
@@ -1717,14 +1902,15 @@ func (s *TelegramBotServiceImpl) pushContentToChannels() {
 	for _, channel := range validChannels {
 		go s.pushToChannel(channel)
 	}
+	return fmt.Sprintf("推送频道=%d", len(validChannels)), nil
 }
 
 // pushToChannel 推送内容到一个频道
 func (s *TelegramBotServiceImpl) pushToChannel(channel entity.TelegramChannel) {
 	utils.Info("[TELEGRAM:PUSH] 开始推送到频道: %s (ID: %d)", channel.ChatName, channel.ChatID)
 
-	// 1. 根据频道设置过滤资源
-	resources := s.findResourcesForChannel(channel)
+	// 1. 根据频道设置过滤资源，排除黑名单命中的资源
+	resources := filterBlockedPushResources(s.findResourcesForChannel(channel))
 	if len(resources) == 0 {
 		utils.Info("[TELEGRAM:PUSH] 频道 %s 没有可推送的内容", channel.ChatName)
 		return
@@ -1798,6 +1984,34 @@ func (s *TelegramBotServiceImpl) findResourcesForChannel(channel entity.Telegram
 	}
 }
 
+// filterBlockedPushResources 排除命中黑名单拒绝或隐藏规则的待推送资源，并对命中打码规则的标题打码
+func filterBlockedPushResources(resources []interface{}) []interface{} {
+	if defaultBlocklistService == nil {
+		return resources
+	}
+	filtered := resources[:0]
+	for _, item := range resources {
+		var resource *entity.Resource
+		switch r := item.(type) {
+		case *entity.Resource:
+			resource = r
+		case entity.Resource:
+			resource = &r
+		default:
+			filtered = append(filtered, item)
+			continue
+		}
+		m := defaultBlocklistService.Match(BlocklistTarget{URL: resource.URL, Title: resource.Title, Author: resource.Author})
+		if m.Excludes() {
+			utils.Info("[TELEGRAM:PUSH] 资源命中黑名单，跳过推送: %s (%s)", resource.Title, m.Reason())
+			continue
+		}
+		resource.Title = m.MaskTitle(resource.Title)
+		filtered = append(filtered, resource)
+	}
+	return filtered
+}
+
 // findLatestResources 查找最新资源
 func (s *TelegramBotServiceImpl) findLatestResources(channel entity.TelegramChannel, excludeResourceIDs []uint) []interface{} {
 	params := s.buildFilterParams(channel)
@@ -1992,6 +2206,14 @@ func (s *TelegramBotServiceImpl) buildPushMessage(channel entity.TelegramChannel
 
 	message := fmt.Sprintf("🆕 <b>%s</b>\n", s.cleanMessageTextForHTML(resource.Title))
 
+	var meta *entity.ResourceMetadata
+	if s.metadataService != nil {
+		meta = s.metadataService.ForResource(resource.ID)
+	}
+	if summary := MetadataSummary(meta); summary != "" {
+		message += fmt.Sprintf("🎬 %s\n", s.cleanMessageTextForHTML(summary))
+	}
+
 	if resource.Description != "" {
 		message += fmt.Sprintf("<blockquote>%s</blockquote>\n", s.cleanMessageTextForHTML(resource.Description))
 	}
@@ -2014,6 +2236,8 @@ func (s *TelegramBotServiceImpl) buildPushMessage(channel entity.TelegramChannel
 	img := ""
 	if resource.Cover != "" {
 		img = resource.Cover
+	} else if meta.HasSubject() && meta.PosterURL != "" {
+		img = meta.PosterURL
 	} else {
 		// 从 readyRepo 中取出 extra 字段，解析 JSON 获取 fid，用于构造图片URL
 		// readyResources, err := s.readyRepo.FindByKey(resource.Key)
@@ -2170,6 +2394,14 @@ func (s *TelegramBotServiceImpl) sendTextMessage(chatID int64, text string) erro
 	return err
 }
 
+// NotifyAdmin 向配置的管理员会话发送通知，机器人未运行或未配置会话时跳过
+func (s *TelegramBotServiceImpl) NotifyAdmin(text string) error {
+	if !s.isRunning || s.bot == nil || s.config.AdminChatID == 0 {
+		return nil
+	}
+	return s.sendTextMessage(s.config.AdminChatID, text)
+}
+
 // DeleteMessage 删除消息
 func (s *TelegramBotServiceImpl) DeleteMessage(chatID int64, messageID int) error {
 	if s.bot == nil {
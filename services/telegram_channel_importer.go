@@ -0,0 +1,298 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	panutils "github.com/ctwj/urldb/common"
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/crawler"
+	"github.com/ctwj/urldb/plugin-system/triggers/plugins"
+	"github.com/ctwj/urldb/utils"
+	"gorm.io/gorm"
+)
+
+// TelegramChannelPost 频道帖子（来自 channel_post 更新或导出历史）
+type TelegramChannelPost struct {
+	ChatID    int64
+	MessageID int64
+	Date      time.Time
+	Text      string // 正文或图片说明，text_link 的链接需已拼入
+	Cover     string // 封面地址（已转为站内可访问地址）
+}
+
+// TelegramImportResult 一次导入的统计
+type TelegramImportResult struct {
+	Posts    int `json:"posts"`    // 处理的帖子数
+	Filtered int `json:"filtered"` // 被过滤条件跳过的帖子数
+	Existing int `json:"existing"` // 链接均已存在的帖子数
+	Imported int `json:"imported"` // 新增待处理资源数（按链接计）
+}
+
+// telegramImportExtra 写入 ReadyResource.Extra 的频道归属信息
+type telegramImportExtra struct {
+	ChatID    int64  `json:"telegram_chat_id"`
+	ChatTitle string `json:"telegram_chat_title,omitempty"`
+	MessageID int64  `json:"telegram_message_id"`
+	PostURL   string `json:"telegram_post_url,omitempty"`
+}
+
+// TelegramChannelImporter Telegram 频道采集器
+// 将频道帖子按采集频道配置过滤后写入待处理资源，Source 为 entity.SourceTelegramChannel。
+type TelegramChannelImporter struct {
+	channelRepo  repo.TelegramImportChannelRepository
+	readyRepo    repo.ReadyResourceRepository
+	resourceRepo repo.ResourceRepository
+}
+
+// NewTelegramChannelImporter 创建 Telegram 频道采集器
+func NewTelegramChannelImporter(
+	channelRepo repo.TelegramImportChannelRepository,
+	readyRepo repo.ReadyResourceRepository,
+	resourceRepo repo.ResourceRepository,
+) *TelegramChannelImporter {
+	return &TelegramChannelImporter{
+		channelRepo:  channelRepo,
+		readyRepo:    readyRepo,
+		resourceRepo: resourceRepo,
+	}
+}
+
+// ImportPost 处理机器人收到的单条频道帖子；频道未配置或未启用时忽略
+func (i *TelegramChannelImporter) ImportPost(post TelegramChannelPost) (int, error) {
+	channel, err := i.channelRepo.FindByChatID(post.ChatID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !channel.Enabled {
+		return 0, nil
+	}
+
+	result, err := i.importPosts(channel, []TelegramChannelPost{post})
+	if err != nil {
+		return 0, err
+	}
+	return result.Imported, nil
+}
+
+// ImportExport 导入频道导出的 JSON 历史（Telegram Desktop 的 result.json）。
+// 导出文件中的频道ID不带 -100 前缀，这里不做校验，以调用方指定的采集频道为准。
+func (i *TelegramChannelImporter) ImportExport(channel *entity.TelegramImportChannel, body []byte) (*TelegramImportResult, error) {
+	var export crawler.TelegramExport
+	if err := json.Unmarshal(body, &export); err != nil {
+		return nil, fmt.Errorf("解析Telegram导出文件失败: %v", err)
+	}
+
+	posts := make([]TelegramChannelPost, 0, len(export.Messages))
+	for _, msg := range export.Messages {
+		if msg.Type != "" && msg.Type != "message" {
+			continue
+		}
+		post := TelegramChannelPost{
+			ChatID:    channel.ChatID,
+			MessageID: msg.ID,
+			Date:      msg.Time(),
+			Text:      msg.PlainText(),
+		}
+		// 导出文件中的图片为相对路径，无法直接访问，仅保留绝对地址
+		if strings.HasPrefix(msg.Photo, "http://") || strings.HasPrefix(msg.Photo, "https://") {
+			post.Cover = msg.Photo
+		}
+		posts = append(posts, post)
+	}
+	return i.importPosts(channel, posts)
+}
+
+// importPosts 过滤、去重并写入待处理资源
+func (i *TelegramChannelImporter) importPosts(channel *entity.TelegramImportChannel, posts []TelegramChannelPost) (*TelegramImportResult, error) {
+	result := &TelegramImportResult{}
+	filter := NewTelegramPostFilter(channel)
+
+	type candidate struct {
+		post  TelegramChannelPost
+		links []string
+	}
+	var candidates []candidate
+	var urls []string
+	var lastMessageID int64
+	for _, post := range posts {
+		result.Posts++
+		if post.MessageID > lastMessageID {
+			lastMessageID = post.MessageID
+		}
+		links, ok := filter.Apply(post)
+		if !ok {
+			result.Filtered++
+			continue
+		}
+		candidates = append(candidates, candidate{post: post, links: links})
+		urls = append(urls, links...)
+	}
+
+	existing, err := findExistingResourceURLs(i.readyRepo, i.resourceRepo, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []entity.ReadyResource
+	for _, c := range candidates {
+		resources, err := i.buildReadyResources(channel, c.post, c.links, existing)
+		if err != nil {
+			return nil, err
+		}
+		if len(resources) == 0 {
+			result.Existing++
+			continue
+		}
+		created = append(created, resources...)
+	}
+
+	if len(created) > 0 {
+		if err := i.readyRepo.BatchCreate(created); err != nil {
+			return nil, fmt.Errorf("写入待处理资源失败: %v", err)
+		}
+	}
+	result.Imported = len(created)
+
+	if result.Posts > 0 {
+		if err := i.channelRepo.RecordImport(channel.ID, lastMessageID, result.Imported, time.Now()); err != nil {
+			utils.Error("[TelegramImport] 记录导入状态失败: %v", err)
+		}
+	}
+
+	for idx := range created {
+		plugins.TriggerReadyResourceAdd(&created[idx], map[string]interface{}{
+			"source":           entity.SourceTelegramChannel,
+			"telegram_chat_id": channel.ChatID,
+		})
+	}
+
+	if result.Imported > 0 {
+		utils.Info("[TelegramImport] 频道 %s(%d) 导入完成: 帖子=%d 过滤=%d 已存在=%d 新增=%d",
+			channel.ChatTitle, channel.ChatID, result.Posts, result.Filtered, result.Existing, result.Imported)
+	}
+	return result, nil
+}
+
+// buildReadyResources 为帖子中尚不存在的链接构建待处理资源，同一帖子的链接共用一个 key
+func (i *TelegramChannelImporter) buildReadyResources(channel *entity.TelegramImportChannel, post TelegramChannelPost, links []string, existing map[string]bool) ([]entity.ReadyResource, error) {
+	var urls []string
+	for _, u := range links {
+		if !existing[u] {
+			existing[u] = true
+			urls = append(urls, u)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	key, err := i.readyRepo.GenerateUniqueKey()
+	if err != nil {
+		return nil, fmt.Errorf("生成资源key失败: %v", err)
+	}
+
+	extra := telegramImportExtra{
+		ChatID:    channel.ChatID,
+		ChatTitle: channel.ChatTitle,
+		MessageID: post.MessageID,
+	}
+	if channel.ChatUsername != "" {
+		extra.PostURL = fmt.Sprintf("https://t.me/%s/%d", strings.TrimPrefix(channel.ChatUsername, "@"), post.MessageID)
+	}
+	extraJSON, _ := json.Marshal(extra)
+
+	title, desc := crawler.SplitPostText(post.Text)
+	var titlePtr *string
+	if t := truncateRunes(title, 255); t != "" {
+		titlePtr = &t
+	}
+	tags := mergeTags(channel.Tags, crawler.ExtractHashtags(post.Text))
+
+	resources := make([]entity.ReadyResource, 0, len(urls))
+	for _, u := range urls {
+		resources = append(resources, entity.ReadyResource{
+			Title:       titlePtr,
+			Description: desc,
+			URL:         u,
+			Category:    channel.Category,
+			Tags:        tags,
+			Img:         truncateRunes(post.Cover, 500),
+			Source:      entity.SourceTelegramChannel,
+			Extra:       string(extraJSON),
+			Key:         key,
+		})
+	}
+	return resources, nil
+}
+
+// TelegramPostFilter 采集频道的帖子过滤条件
+type TelegramPostFilter struct {
+	include     []string
+	exclude     []string
+	platforms   map[string]bool
+	minPostDate *time.Time
+}
+
+// NewTelegramPostFilter 根据采集频道配置创建过滤器
+func NewTelegramPostFilter(channel *entity.TelegramImportChannel) *TelegramPostFilter {
+	f := &TelegramPostFilter{
+		include:     lowerAll(utils.ParseForbiddenWordsConfig(channel.IncludeKeywords)),
+		exclude:     lowerAll(utils.ParseForbiddenWordsConfig(channel.ExcludeKeywords)),
+		minPostDate: channel.MinPostDate,
+	}
+	if platforms := utils.ParseForbiddenWordsConfig(channel.Platforms); len(platforms) > 0 {
+		f.platforms = make(map[string]bool, len(platforms))
+		for _, p := range platforms {
+			f.platforms[strings.ToLower(p)] = true
+		}
+	}
+	return f
+}
+
+// Apply 判断帖子是否满足过滤条件，满足时返回允许平台的网盘链接
+func (f *TelegramPostFilter) Apply(post TelegramChannelPost) ([]string, bool) {
+	if f.minPostDate != nil && !post.Date.IsZero() && post.Date.Before(*f.minPostDate) {
+		return nil, false
+	}
+
+	text := strings.ToLower(post.Text)
+	for _, kw := range f.exclude {
+		if strings.Contains(text, kw) {
+			return nil, false
+		}
+	}
+	if len(f.include) > 0 {
+		matched := false
+		for _, kw := range f.include {
+			if strings.Contains(text, kw) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, false
+		}
+	}
+
+	var links []string
+	for _, link := range crawler.ExtractPanLinks(post.Text) {
+		if f.platforms == nil || f.platforms[panutils.ExtractServiceType(link).String()] {
+			links = append(links, link)
+		}
+	}
+	return links, len(links) > 0
+}
+
+func lowerAll(words []string) []string {
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return words
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/db/repo"
+)
+
+// 纯单元测试：覆盖采集频道过滤条件（关键词 / 平台 / 最早时间）与导出历史导入的频道归属。
+
+type fakeImportChannelRepo struct {
+	repo.TelegramImportChannelRepository
+	channel  *entity.TelegramImportChannel
+	imported int
+	lastMsg  int64
+}
+
+func (f *fakeImportChannelRepo) RecordImport(_ uint, lastMessageID int64, imported int, _ time.Time) error {
+	f.imported += imported
+	if lastMessageID > f.lastMsg {
+		f.lastMsg = lastMessageID
+	}
+	return nil
+}
+
+func TestTelegramPostFilter(t *testing.T) {
+	minDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	channel := &entity.TelegramImportChannel{
+		IncludeKeywords: "4K\n电影",
+		ExcludeKeywords: "广告,枪版",
+		Platforms:       "quark, baidu",
+		MinPostDate:     &minDate,
+	}
+	f := NewTelegramPostFilter(channel)
+	after := minDate.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		post      TelegramChannelPost
+		wantLinks []string
+	}{
+		{"match", TelegramChannelPost{Date: after, Text: "沙丘2 4k https://pan.quark.cn/s/a"}, []string{"https://pan.quark.cn/s/a"}},
+		{"platform filtered", TelegramChannelPost{Date: after, Text: "电影 https://pan.xunlei.com/s/x https://pan.baidu.com/s/1b"}, []string{"https://pan.baidu.com/s/1b"}},
+		{"only disallowed platform", TelegramChannelPost{Date: after, Text: "电影 https://pan.xunlei.com/s/x"}, nil},
+		{"no include keyword", TelegramChannelPost{Date: after, Text: "综艺 https://pan.quark.cn/s/a"}, nil},
+		{"exclude keyword", TelegramChannelPost{Date: after, Text: "电影 枪版 https://pan.quark.cn/s/a"}, nil},
+		{"too old", TelegramChannelPost{Date: minDate.Add(-time.Hour), Text: "电影 https://pan.quark.cn/s/a"}, nil},
+		{"unknown date kept", TelegramChannelPost{Text: "电影 https://pan.quark.cn/s/a"}, []string{"https://pan.quark.cn/s/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, ok := f.Apply(tt.post)
+			if ok != (len(tt.wantLinks) > 0) || len(links) != len(tt.wantLinks) {
+				t.Fatalf("Apply() = %v, %v; want %v", links, ok, tt.wantLinks)
+			}
+			for i := range links {
+				if links[i] != tt.wantLinks[i] {
+					t.Fatalf("Apply() = %v; want %v", links, tt.wantLinks)
+				}
+			}
+		})
+	}
+}
+
+func TestTelegramChannelImporter_ImportExport(t *testing.T) {
+	channel := &entity.TelegramImportChannel{
+		ID:           3,
+		ChatID:       -1001234,
+		ChatUsername: "res_channel",
+		ChatTitle:    "资源频道",
+		Category:     "电视剧",
+		Tags:         "频道",
+		Enabled:      true,
+	}
+	channelRepo := &fakeImportChannelRepo{channel: channel}
+	readyRepo := &fakeContentReadyRepo{}
+	resourceRepo := &fakeContentResourceRepo{urls: []string{"https://pan.quark.cn/s/old"}}
+	importer := NewTelegramChannelImporter(channelRepo, readyRepo, resourceRepo)
+
+	export := `{"name":"资源频道","id":1234,"messages":[
+	  {"id":10,"type":"message","date_unixtime":"1704153600","text":["名称：繁花\n",{"type":"text_link","text":"点我","href":"https://pan.quark.cn/s/fanhua"},"\n#国产"]},
+	  {"id":11,"type":"message","date_unixtime":"1704153700","text":"旧资源 https://pan.quark.cn/s/old"},
+	  {"id":12,"type":"message","date_unixtime":"1704153800","text":"闲聊"}
+	]}`
+
+	result, err := importer.ImportExport(channel, []byte(export))
+	if err != nil {
+		t.Fatalf("ImportExport: %v", err)
+	}
+	if result.Posts != 3 || result.Filtered != 1 || result.Existing != 1 || result.Imported != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if channelRepo.imported != 1 || channelRepo.lastMsg != 12 {
+		t.Fatalf("RecordImport not called correctly: imported=%d last=%d", channelRepo.imported, channelRepo.lastMsg)
+	}
+
+	r := readyRepo.created[0]
+	if r.Source != entity.SourceTelegramChannel || r.URL != "https://pan.quark.cn/s/fanhua" || r.Category != "电视剧" || r.Tags != "频道,国产" {
+		t.Fatalf("unexpected ready resource: %+v", r)
+	}
+	if r.Title == nil || *r.Title != "繁花" {
+		t.Fatalf("title = %v", r.Title)
+	}
+
+	var extra telegramImportExtra
+	if err := json.Unmarshal([]byte(r.Extra), &extra); err != nil {
+		t.Fatalf("extra: %v", err)
+	}
+	if extra.ChatID != -1001234 || extra.MessageID != 10 || extra.PostURL != "https://t.me/res_channel/10" {
+		t.Fatalf("unexpected extra: %+v", extra)
+	}
+}
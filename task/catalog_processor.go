@@ -0,0 +1,220 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/eventbus"
+	"github.com/ctwj/urldb/services"
+	"github.com/ctwj/urldb/utils"
+)
+
+// 导出文件包含未公开的资源，目录不能位于对外提供的静态目录（见 services.PublicStaticDirs）中，
+// 只能通过需要登录与权限的下载接口获取
+const (
+	// CatalogExportDir 导出文件目录
+	CatalogExportDir = "./catalog/exports"
+	// CatalogImportDir 上传的导入文件目录
+	CatalogImportDir = "./catalog/imports"
+	// catalogFileRetention 导出、导入文件的保留时间
+	catalogFileRetention = 7 * 24 * time.Hour
+)
+
+// CatalogExportInput 目录导出任务输入
+type CatalogExportInput struct {
+	Format string                 `json:"format"`
+	Filter services.CatalogFilter `json:"filter"`
+}
+
+// CatalogExportOutput 目录导出任务输出
+type CatalogExportOutput struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Format  string `json:"format"`
+	File    string `json:"file"`
+	Count   int    `json:"count"`
+	Size    int64  `json:"size"`
+	Time    string `json:"time"`
+}
+
+// CatalogImportInput 目录导入任务输入，File 为已上传到 CatalogImportDir 的文件
+type CatalogImportInput struct {
+	File     string                        `json:"file"`
+	FileName string                        `json:"file_name"`
+	Options  services.CatalogImportOptions `json:"options"`
+}
+
+// CatalogImportOutput 目录导入任务输出
+type CatalogImportOutput struct {
+	Success bool                          `json:"success"`
+	Message string                        `json:"message"`
+	Report  *services.CatalogImportReport `json:"report,omitempty"`
+	Time    string                        `json:"time"`
+}
+
+// CatalogProcessor 资源目录导出/导入任务处理器，导出与导入分别注册为两种任务类型
+type CatalogProcessor struct {
+	taskType entity.TaskType
+	service  *services.CatalogService
+}
+
+// NewCatalogExportProcessor 创建目录导出任务处理器，启动时即清理旧版本留在公开目录中的文件
+func NewCatalogExportProcessor(service *services.CatalogService) *CatalogProcessor {
+	cleanupCatalogFiles()
+	return &CatalogProcessor{taskType: entity.TaskTypeCatalogExport, service: service}
+}
+
+// NewCatalogImportProcessor 创建目录导入任务处理器
+func NewCatalogImportProcessor(service *services.CatalogService) *CatalogProcessor {
+	return &CatalogProcessor{taskType: entity.TaskTypeCatalogImport, service: service}
+}
+
+// GetTaskType 获取任务类型
+func (cp *CatalogProcessor) GetTaskType() string {
+	return string(cp.taskType)
+}
+
+// Process 处理目录导出/导入任务项
+func (cp *CatalogProcessor) Process(ctx context.Context, taskID uint, item *entity.TaskItem) error {
+	cleanupCatalogFiles()
+	if cp.taskType == entity.TaskTypeCatalogExport {
+		return cp.processExport(ctx, taskID, item)
+	}
+	return cp.processImport(ctx, taskID, item)
+}
+
+func (cp *CatalogProcessor) processExport(ctx context.Context, taskID uint, item *entity.TaskItem) error {
+	var input CatalogExportInput
+	if err := json.Unmarshal([]byte(item.InputData), &input); err != nil {
+		return fmt.Errorf("解析输入数据失败: %v", err)
+	}
+
+	if err := os.MkdirAll(CatalogExportDir, 0755); err != nil {
+		return fmt.Errorf("创建导出目录失败: %v", err)
+	}
+	path := CatalogExportPath(taskID, input.Format)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %v", err)
+	}
+
+	count, err := cp.service.Export(ctx, f, input.Format, input.Filter, cp.progress(taskID, item.ID))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("导出失败: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("保存导出文件失败: %v", err)
+	}
+
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	output := CatalogExportOutput{
+		Success: true,
+		Message: fmt.Sprintf("已导出 %d 条资源", count),
+		Format:  input.Format,
+		File:    filepath.Base(path),
+		Count:   count,
+		Size:    size,
+		Time:    utils.GetCurrentTimeString(),
+	}
+	outputJSON, _ := json.Marshal(output)
+	item.OutputData = string(outputJSON)
+	utils.Info("目录导出完成: 任务ID %d, %d 条, 文件 %s", taskID, count, path)
+	return nil
+}
+
+func (cp *CatalogProcessor) processImport(ctx context.Context, taskID uint, item *entity.TaskItem) error {
+	var input CatalogImportInput
+	if err := json.Unmarshal([]byte(item.InputData), &input); err != nil {
+		return fmt.Errorf("解析输入数据失败: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(CatalogImportDir, filepath.Base(input.File)))
+	if err != nil {
+		return fmt.Errorf("打开导入文件失败: %v", err)
+	}
+	defer f.Close()
+
+	report, err := cp.service.Import(ctx, f, input.Options, cp.progress(taskID, item.ID))
+	output := CatalogImportOutput{
+		Success: err == nil,
+		Report:  report,
+		Time:    utils.GetCurrentTimeString(),
+	}
+	if err != nil {
+		output.Message = err.Error()
+	} else if input.Options.DryRun {
+		output.Message = fmt.Sprintf("预检完成: 共 %d 条，可导入 %d 条，重复 %d 条，无效 %d 条",
+			report.Total, report.Created, report.Duplicates, report.Invalid)
+	} else {
+		output.Message = fmt.Sprintf("导入完成: 共 %d 条，新增 %d 条，重复 %d 条，无效 %d 条，失败 %d 条",
+			report.Total, report.Created, report.Duplicates, report.Invalid, report.Failed)
+	}
+	outputJSON, _ := json.Marshal(output)
+	item.OutputData = string(outputJSON)
+	if err != nil {
+		return fmt.Errorf("导入失败: %v", err)
+	}
+	utils.Info("目录导入完成: 任务ID %d, %s", taskID, output.Message)
+	return nil
+}
+
+// progress 单个任务项内的记录级进度，通过事件总线推送
+func (cp *CatalogProcessor) progress(taskID, itemID uint) services.CatalogProgress {
+	return func(processed, total int) {
+		eventbus.Publish(eventbus.TypeTaskProgress, map[string]interface{}{
+			"task_id":       taskID,
+			"task_type":     cp.taskType,
+			"item_id":       itemID,
+			"records":       processed,
+			"records_total": total,
+		})
+	}
+}
+
+// CatalogExportPath 导出任务的文件路径
+func CatalogExportPath(taskID uint, format string) string {
+	return filepath.Join(CatalogExportDir, fmt.Sprintf("catalog_%d.%s", taskID, format))
+}
+
+// legacyCatalogDirs 旧版本在公开静态目录下的导出、导入目录，其中的文件不论新旧一律删除
+var legacyCatalogDirs = []string{"./data/catalog/exports", "./data/catalog/imports"}
+
+// cleanupCatalogFiles 删除超过保留时间的导出、导入文件，以及旧版本留在公开目录中的文件
+func cleanupCatalogFiles() {
+	for _, dir := range legacyCatalogDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			utils.Warn("清理旧目录文件失败: %s: %v", dir, err)
+		}
+	}
+
+	cutoff := time.Now().Add(-catalogFileRetention)
+	for _, dir := range []string{CatalogExportDir, CatalogImportDir} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || e.IsDir() || info.ModTime().After(cutoff) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, e.Name())); err == nil {
+				utils.Debug("已清理过期目录文件: %s", e.Name())
+			}
+		}
+	}
+}
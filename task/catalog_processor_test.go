@@ -0,0 +1,34 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ctwj/urldb/services"
+)
+
+func TestCatalogFilesStayOutOfPublicDirs(t *testing.T) {
+	for _, dir := range []string{CatalogExportDir, CatalogImportDir, filepath.Dir(CatalogExportPath(1, "csv"))} {
+		if services.InPublicStaticDir(dir) {
+			t.Fatalf("%s is served without authentication", dir)
+		}
+	}
+
+	// 旧版本写在 ./data 下的导出文件在清理时删除，新目录中未过期的文件保留
+	t.Chdir(t.TempDir())
+	legacy := filepath.Join("data", "catalog", "exports", "catalog_1.csv")
+	os.MkdirAll(filepath.Dir(legacy), 0755)
+	os.WriteFile(legacy, []byte("id\n"), 0644)
+	current := CatalogExportPath(2, "csv")
+	os.MkdirAll(filepath.Dir(current), 0755)
+	os.WriteFile(current, []byte("id\n"), 0644)
+
+	cleanupCatalogFiles()
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("legacy export should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(current); err != nil {
+		t.Fatalf("current export should be kept: %v", err)
+	}
+}
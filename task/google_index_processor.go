@@ -132,7 +132,7 @@ func (gip *GoogleIndexProcessor) processURLIndexing(ctx context.Context, client
 			gip.updateTaskItemStatus(item, entity.TaskItemStatusSuccess, result.IndexStatusResult.IndexingState, result.MobileUsabilityResult.MobileFriendly, lastCrawled, 200, "")
 
 			// 更新URL状态记录
-			gip.updateURLStatus(url, result.IndexStatusResult.IndexingState, lastCrawled)
+			gip.updateURLStatus(url, result, lastCrawled)
 
 			// 添加延迟避免API限制
 			time.Sleep(100 * time.Millisecond)
@@ -407,11 +407,21 @@ func (gip *GoogleIndexProcessor) updateTaskItemStatus(item *entity.TaskItem, sta
 	}
 }
 
-// updateURLStatus 更新URL状态记录（使用任务项存储）
-func (gip *GoogleIndexProcessor) updateURLStatus(url string, indexStatus string, lastCrawled *time.Time) {
-	// 在任务项中记录URL状态，而不是使用专门的URL状态表
-	// 此功能现在通过任务系统中的TaskItem记录来跟踪
-	utils.Debug("URL状态已更新: %s, 状态: %s", url, indexStatus)
+// updateURLStatus 将检查结果同步到资源索引状态表，供覆盖率统计使用
+func (gip *GoogleIndexProcessor) updateURLStatus(url string, result *google.URLInspectionResult, lastCrawled *time.Time) {
+	inspection := entity.GoogleIndexInspection{
+		Verdict:        result.IndexStatusResult.Verdict,
+		CoverageState:  result.IndexStatusResult.CoverageState,
+		IndexingState:  result.IndexStatusResult.IndexingState,
+		PageFetchState: result.IndexStatusResult.PageFetchState,
+		LastCrawledAt:  lastCrawled,
+		InspectedAt:    time.Now(),
+	}
+	if err := gip.repoMgr.GoogleIndexStatusRepository.RecordInspection(url, inspection); err != nil {
+		utils.Error("更新URL索引状态失败: %s, %v", url, err)
+		return
+	}
+	utils.Debug("URL状态已更新: %s, 状态: %s", url, inspection.CoverageState)
 }
 
 // BatchProcessURLs 批量处理URLs
@@ -475,7 +485,7 @@ func (gip *GoogleIndexProcessor) BatchProcessURLs(ctx context.Context, urls []st
 			}
 
 			// 更新URL状态
-			gip.updateURLStatus(u, result.IndexStatusResult.IndexingState, lastCrawled)
+			gip.updateURLStatus(u, result, lastCrawled)
 
 			errChan <- nil
 		}(url)
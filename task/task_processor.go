@@ -9,6 +9,7 @@ import (
 
 	"github.com/ctwj/urldb/db/entity"
 	"github.com/ctwj/urldb/db/repo"
+	"github.com/ctwj/urldb/pkg/eventbus"
 	"github.com/ctwj/urldb/utils"
 )
 
@@ -140,6 +141,7 @@ func (tm *TaskManager) PauseTask(taskID uint) error {
 		utils.Error("更新任务状态为暂停失败: %v", err)
 		return fmt.Errorf("更新任务状态失败: %v", err)
 	}
+	publishTaskStatus(taskID, "paused", "")
 
 	utils.Info("任务 %d 暂停成功", taskID)
 	return nil
@@ -181,6 +183,8 @@ func (tm *TaskManager) StopTask(taskID uint) error {
 	err := tm.repoMgr.TaskRepository.UpdateStatus(taskID, "paused")
 	if err != nil {
 		utils.Error("更新任务状态失败: %v", err)
+	} else {
+		publishTaskStatus(taskID, "paused", "")
 	}
 
 	return nil
@@ -218,6 +222,7 @@ func (tm *TaskManager) processTask(ctx context.Context, task *entity.Task, proce
 		utils.Error("更新任务状态失败: %v", err)
 		return
 	}
+	publishTaskStatus(task.ID, "running", "")
 
 	// 更新任务开始时间
 	err = tm.repoMgr.TaskRepository.UpdateStartedAt(task.ID)
@@ -323,6 +328,16 @@ func (tm *TaskManager) processTask(ctx context.Context, task *entity.Task, proce
 			if totalItems > 0 {
 				progress := float64(processedItems) / float64(totalItems) * 100
 				tm.updateTaskProgress(task.ID, progress, processedItems, successItems, failedItems)
+				eventbus.Publish(eventbus.TypeTaskProgress, map[string]interface{}{
+					"task_id":   task.ID,
+					"task_type": task.Type,
+					"item_id":   item.ID,
+					"progress":  progress,
+					"total":     totalItems,
+					"processed": processedItems,
+					"success":   successItems,
+					"failed":    failedItems,
+				})
 			}
 
 			// 每处理10个任务项记录一次批处理进度
@@ -355,6 +370,7 @@ func (tm *TaskManager) processTask(ctx context.Context, task *entity.Task, proce
 	if err != nil {
 		utils.Error("更新任务状态失败: %v", err)
 	}
+	publishTaskStatus(task.ID, status, message)
 
 	// 如果任务完成，更新完成时间
 	if status == "completed" || status == "failed" || status == "partial_success" {
@@ -487,6 +503,7 @@ func (tm *TaskManager) markTaskFailed(taskID uint, message string) {
 	if err != nil {
 		utils.Error("标记任务失败状态失败: %v", err)
 	}
+	publishTaskStatus(taskID, "failed", message)
 
 	// 更新任务完成时间
 	err = tm.repoMgr.TaskRepository.UpdateCompletedAt(taskID)
@@ -495,6 +512,15 @@ func (tm *TaskManager) markTaskFailed(taskID uint, message string) {
 	}
 }
 
+// publishTaskStatus 推送任务状态变更事件
+func publishTaskStatus(taskID uint, status, message string) {
+	eventbus.Publish(eventbus.TypeTaskStatus, map[string]interface{}{
+		"task_id": taskID,
+		"status":  status,
+		"message": message,
+	})
+}
+
 // GetTaskStatus 获取任务状态
 func (tm *TaskManager) GetTaskStatus(taskID uint) (string, error) {
 	task, err := tm.repoMgr.TaskRepository.GetByID(taskID)
@@ -1,13 +1,19 @@
 package utils
 
 import (
-	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/ctwj/urldb/db/entity"
+	"github.com/ctwj/urldb/pkg/wordfilter"
 )
 
+// forbiddenMatchers 按违禁词列表缓存的 Aho-Corasick 匹配器，词表变化时自动重建
+var forbiddenMatchers = wordfilter.NewCache(wordfilter.DefaultOptions(), 8)
+
 // ForbiddenWordsProcessor 违禁词处理器
+// 匹配时忽略大小写、全角/半角与繁简差异，英文词按单词边界匹配，汉字词同时匹配其全拼
 type ForbiddenWordsProcessor struct{}
 
 // NewForbiddenWordsProcessor 创建违禁词处理器实例
@@ -15,6 +21,11 @@ func NewForbiddenWordsProcessor() *ForbiddenWordsProcessor {
 	return &ForbiddenWordsProcessor{}
 }
 
+// Matcher 返回违禁词列表对应的匹配器，相同词表复用已构建的自动机
+func (p *ForbiddenWordsProcessor) Matcher(forbiddenWords []string) *wordfilter.Matcher {
+	return forbiddenMatchers.Get(forbiddenWords)
+}
+
 // CheckContainsForbiddenWords 检查字符串是否包含违禁词
 // 参数：
 //   - text: 要检查的文本
@@ -28,16 +39,7 @@ func (p *ForbiddenWordsProcessor) CheckContainsForbiddenWords(text string, forbi
 		return false, nil
 	}
 
-	var matchedWords []string
-	textLower := strings.ToLower(text)
-
-	for _, word := range forbiddenWords {
-		wordLower := strings.ToLower(word)
-		if strings.Contains(textLower, wordLower) {
-			matchedWords = append(matchedWords, word)
-		}
-	}
-
+	matchedWords := p.Matcher(forbiddenWords).MatchedWords(text)
 	return len(matchedWords) > 0, matchedWords
 }
 
@@ -52,31 +54,8 @@ func (p *ForbiddenWordsProcessor) ReplaceForbiddenWords(text string, forbiddenWo
 	if len(forbiddenWords) == 0 {
 		return text
 	}
-
-	result := text
-	// 按长度降序排序，避免短词替换后影响长词的匹配
-	sortedWords := make([]string, len(forbiddenWords))
-	copy(sortedWords, forbiddenWords)
-
-	// 简单的长度排序（这里可以优化为更复杂的排序）
-	for i := 0; i < len(sortedWords)-1; i++ {
-		for j := i + 1; j < len(sortedWords); j++ {
-			if len(sortedWords[i]) < len(sortedWords[j]) {
-				sortedWords[i], sortedWords[j] = sortedWords[j], sortedWords[i]
-			}
-		}
-	}
-
-	for _, word := range sortedWords {
-		// 使用正则表达式进行不区分大小写的替换
-		// 对于中文，不使用单词边界，直接替换
-		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(word))
-		// 使用字符长度而不是字节长度
-		charCount := len([]rune(word))
-		result = re.ReplaceAllString(result, strings.Repeat("*", charCount))
-	}
-
-	return result
+	// 每个命中的字符替换为一个 *，重叠的违禁词合并替换
+	return p.Matcher(forbiddenWords).Replace(text)
 }
 
 // ReplaceForbiddenWordsWithHighlight 替换字符串中的违禁词为 *（处理高亮标记）
@@ -90,27 +69,8 @@ func (p *ForbiddenWordsProcessor) ReplaceForbiddenWordsWithHighlight(text string
 	if len(forbiddenWords) == 0 {
 		return text
 	}
-
-	// 1. 先移除所有高亮标记，获取纯文本
-	cleanText := regexp.MustCompile(`<mark>(.*?)</mark>`).ReplaceAllString(text, "$1")
-
-	// 2. 检查纯文本中是否包含违禁词
-	hasForbidden := false
-	for _, word := range forbiddenWords {
-		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(word))
-		if re.MatchString(cleanText) {
-			hasForbidden = true
-			break
-		}
-	}
-
-	// 3. 如果包含违禁词，则替换非高亮文本
-	if hasForbidden {
-		return p.ReplaceForbiddenWords(text, forbiddenWords)
-	}
-
-	// 4. 如果不包含违禁词，直接返回原文本
-	return text
+	// 匹配时跳过 <mark> 标记，被高亮拆开的违禁词同样会被替换，标记本身保留
+	return p.Matcher(forbiddenWords).ReplaceHighlighted(text)
 }
 
 // ProcessForbiddenWords 处理违禁词：检查并替换
@@ -124,8 +84,10 @@ func (p *ForbiddenWordsProcessor) ReplaceForbiddenWordsWithHighlight(text string
 //   - string: 替换后的文本
 func (p *ForbiddenWordsProcessor) ProcessForbiddenWords(text string, forbiddenWords []string) (bool, []string, string) {
 	contains, matchedWords := p.CheckContainsForbiddenWords(text, forbiddenWords)
-	replacedText := p.ReplaceForbiddenWords(text, forbiddenWords)
-	return contains, matchedWords, replacedText
+	if !contains {
+		return false, nil, text
+	}
+	return true, matchedWords, p.ReplaceForbiddenWords(text, forbiddenWords)
 }
 
 // ParseForbiddenWordsConfig 解析违禁词配置字符串
@@ -226,25 +188,23 @@ func CheckResourceForbiddenWords(title, description string, forbiddenWords []str
 		}
 	}
 
+	matcher := DefaultForbiddenWordsProcessor.Matcher(forbiddenWords)
+
 	// 分别检查标题和描述
-	titleHasForbidden, titleMatchedWords := CheckContainsForbiddenWords(title, forbiddenWords)
-	descHasForbidden, descMatchedWords := CheckContainsForbiddenWords(description, forbiddenWords)
-
-	// 合并结果
-	hasForbiddenWords := titleHasForbidden || descHasForbidden
-	var matchedWords []string
-	if titleHasForbidden {
-		matchedWords = append(matchedWords, titleMatchedWords...)
-	}
-	if descHasForbidden {
-		matchedWords = append(matchedWords, descMatchedWords...)
-	}
-	// 去重
-	matchedWords = RemoveDuplicates(matchedWords)
+	titleMatchedWords := matcher.MatchedWords(title)
+	descMatchedWords := matcher.MatchedWords(description)
+
+	// 合并结果并去重
+	matchedWords := RemoveDuplicates(append(titleMatchedWords, descMatchedWords...))
+	hasForbiddenWords := len(matchedWords) > 0
 
 	// 处理文本（替换违禁词）
-	processedTitle := ReplaceForbiddenWords(title, forbiddenWords)
-	processedDesc := ReplaceForbiddenWords(description, forbiddenWords)
+	processedTitle := title
+	processedDesc := description
+	if hasForbiddenWords {
+		processedTitle = matcher.Replace(title)
+		processedDesc = matcher.Replace(description)
+	}
 
 	return ResourceForbiddenInfo{
 		HasForbiddenWords: hasForbiddenWords,
@@ -266,7 +226,25 @@ func GetForbiddenWordsFromConfig(getConfigFunc func() (string, error)) ([]string
 	if err != nil {
 		return nil, err
 	}
-	return ParseForbiddenWordsConfig(forbiddenWords), nil
+
+	// 配置未变化时复用上次解析结果，返回的切片容量已截断，调用方追加时不会影响缓存
+	forbiddenConfigCache.mu.Lock()
+	defer forbiddenConfigCache.mu.Unlock()
+	if forbiddenConfigCache.words == nil || forbiddenConfigCache.config != forbiddenWords {
+		forbiddenConfigCache.config = forbiddenWords
+		forbiddenConfigCache.words = slices.Clip(ParseForbiddenWordsConfig(forbiddenWords))
+		if forbiddenConfigCache.words == nil {
+			forbiddenConfigCache.words = []string{}
+		}
+	}
+	return forbiddenConfigCache.words, nil
+}
+
+// forbiddenConfigCache 最近一次解析的违禁词配置
+var forbiddenConfigCache struct {
+	mu     sync.Mutex
+	config string
+	words  []string
 }
 
 // ProcessResourcesForbiddenWords 批量处理资源的违禁词
@@ -281,11 +259,12 @@ func ProcessResourcesForbiddenWords(resources []entity.Resource, forbiddenWords
 		return resources
 	}
 
+	matcher := DefaultForbiddenWordsProcessor.Matcher(forbiddenWords)
 	for i := range resources {
 		// 处理标题中的违禁词
-		resources[i].Title = ReplaceForbiddenWords(resources[i].Title, forbiddenWords)
+		resources[i].Title = matcher.Replace(resources[i].Title)
 		// 处理描述中的违禁词
-		resources[i].Description = ReplaceForbiddenWords(resources[i].Description, forbiddenWords)
+		resources[i].Description = matcher.Replace(resources[i].Description)
 	}
 
 	return resources
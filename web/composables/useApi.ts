@@ -87,11 +87,18 @@ export const useResourceApi = () => {
   const updateCopyrightClaim = (id: number, data: any) => useApiFetch(`/copyright-claims/${id}`, { method: 'PUT', body: data }).then(parseApiResponse)
   const deleteCopyrightClaim = (id: number) => useApiFetch(`/copyright-claims/${id}`, { method: 'DELETE' }).then(parseApiResponse)
 
+  // 举报与版权申述的资源处置记录
+  const getModerationActions = (params?: any) => useApiFetch('/moderation/actions', { params }).then(parseApiResponse)
+  const revertModerationAction = (id: number) => useApiFetch(`/moderation/actions/${id}/revert`, { method: 'POST' }).then(parseApiResponse)
+  const getModerationConfig = () => useApiFetch('/moderation/config').then(parseApiResponse)
+  const updateModerationConfig = (data: { escalation_threshold: number }) => useApiFetch('/moderation/config', { method: 'PUT', body: data }).then(parseApiResponse)
+
   return {
     getResources, getHotResources, getResource, getResourcesByKey, createResource, updateResource, deleteResource, searchResources, getResourcesByPan, incrementViewCount, batchDeleteResources, getResourceLink, getRelatedResources, checkResourceValidity, batchCheckResourceValidity,
     submitReport, submitCopyrightClaim,
     getReports, getReport, updateReport, deleteReport, getReportsRaw,
-    getCopyrightClaims, getCopyrightClaim, updateCopyrightClaim, deleteCopyrightClaim
+    getCopyrightClaims, getCopyrightClaim, updateCopyrightClaim, deleteCopyrightClaim,
+    getModerationActions, revertModerationAction, getModerationConfig, updateModerationConfig
   }
 }
 
@@ -102,7 +109,18 @@ export const useAuthApi = () => {
     const token = typeof window !== 'undefined' ? localStorage.getItem('token') : ''
     return useApiFetch('/auth/profile', { headers: token ? { Authorization: `Bearer ${token}` } : {} }).then(parseApiResponse)
   }
-  return { login, register, getProfile }
+  const refresh = (refreshToken: string) => useApiFetch('/auth/refresh', { method: 'POST', body: { refresh_token: refreshToken } }).then(parseApiResponse)
+  const logout = () => useApiFetch('/auth/logout', { method: 'POST' }).then(parseApiResponse)
+  const getSessions = () => useApiFetch('/auth/sessions').then(parseApiResponse)
+  const revokeSession = (sessionId: string) => useApiFetch(`/auth/sessions/${sessionId}`, { method: 'DELETE' }).then(parseApiResponse)
+  const revokeOtherSessions = () => useApiFetch('/auth/sessions', { method: 'DELETE' }).then(parseApiResponse)
+  const getTwoFactorStatus = () => useApiFetch('/auth/2fa').then(parseApiResponse)
+  const setupTwoFactor = () => useApiFetch('/auth/2fa/setup', { method: 'POST' }).then(parseApiResponse)
+  const enableTwoFactor = (code: string) => useApiFetch('/auth/2fa/enable', { method: 'POST', body: { code } }).then(parseApiResponse)
+  const disableTwoFactor = (code: string) => useApiFetch('/auth/2fa/disable', { method: 'POST', body: { code } }).then(parseApiResponse)
+  const regenerateRecoveryCodes = (code: string) => useApiFetch('/auth/2fa/recovery-codes', { method: 'POST', body: { code } }).then(parseApiResponse)
+  const getLoginHistory = (params?: any) => useApiFetch('/auth/login-history', { params }).then(parseApiResponse)
+  return { login, register, getProfile, refresh, logout, getSessions, revokeSession, revokeOtherSessions, getTwoFactorStatus, setupTwoFactor, enableTwoFactor, disableTwoFactor, regenerateRecoveryCodes, getLoginHistory }
 }
 
 export const useCategoryApi = () => {
@@ -288,7 +306,11 @@ export const useUserApi = () => {
   const updateUser = (id: number, data: any) => useApiFetch(`/users/${id}`, { method: 'PUT', body: data }).then(parseApiResponse)
   const deleteUser = (id: number) => useApiFetch(`/users/${id}`, { method: 'DELETE' }).then(parseApiResponse)
   const changePassword = (id: number, newPassword: string) => useApiFetch(`/users/${id}/password`, { method: 'PUT', body: { new_password: newPassword } }).then(parseApiResponse)
-  return { getUsers, getUser, createUser, updateUser, deleteUser, changePassword }
+  const getUserSessions = (id: number) => useApiFetch(`/users/${id}/sessions`).then(parseApiResponse)
+  const revokeUserSessions = (id: number) => useApiFetch(`/users/${id}/sessions`, { method: 'DELETE' }).then(parseApiResponse)
+  const resetUserTwoFactor = (id: number) => useApiFetch(`/users/${id}/2fa`, { method: 'DELETE' }).then(parseApiResponse)
+  const getLoginHistory = (params?: any) => useApiFetch('/login-history', { params }).then(parseApiResponse)
+  return { getUsers, getUser, createUser, updateUser, deleteUser, changePassword, getUserSessions, revokeUserSessions, resetUserTwoFactor, getLoginHistory }
 } 
 
 // 公开获取系统配置API
@@ -400,6 +422,42 @@ export const useApiAccessLogApi = () => {
   }
 }
 
+// 操作审计日志API
+export const useAuditLogApi = () => {
+  const getAuditLogs = (params?: any) => useApiFetch('/audit-logs', { params }).then(parseApiResponse)
+  const exportAuditLogs = (params?: any) => useApiFetch('/audit-logs/export', { params, responseType: 'blob' })
+  const getAuditLogConfig = () => useApiFetch('/audit-logs/config').then(parseApiResponse)
+  const updateAuditLogConfig = (data: { retention_days: number }) => useApiFetch('/audit-logs/config', { method: 'PUT', body: data }).then(parseApiResponse)
+  return { getAuditLogs, exportAuditLogs, getAuditLogConfig, updateAuditLogConfig }
+}
+
+// 调度任务状态API
+export const useSchedulerApi = () => {
+  const getSchedulerStatus = () => useApiFetch('/scheduler/status').then(parseApiResponse)
+  return { getSchedulerStatus }
+}
+
+// 黑名单规则API
+export const useBlocklistApi = () => {
+  const getBlocklistRules = (params?: any) => useApiFetch('/blocklist', { params }).then(parseApiResponse)
+  const createBlocklistRule = (data: any) => useApiFetch('/blocklist', { method: 'POST', body: data }).then(parseApiResponse)
+  const updateBlocklistRule = (id: number, data: any) => useApiFetch(`/blocklist/${id}`, { method: 'PUT', body: data }).then(parseApiResponse)
+  const deleteBlocklistRule = (id: number) => useApiFetch(`/blocklist/${id}`, { method: 'DELETE' }).then(parseApiResponse)
+  const testBlocklist = (data: { url?: string, title?: string, author?: string }) => useApiFetch('/blocklist/test', { method: 'POST', body: data }).then(parseApiResponse)
+  return { getBlocklistRules, createBlocklistRule, updateBlocklistRule, deleteBlocklistRule, testBlocklist }
+}
+
+// 定时任务API
+export const useJobApi = () => {
+  const getJobs = () => useApiFetch('/jobs').then(parseApiResponse)
+  const getJobRuns = (name: string, params?: any) => useApiFetch(`/jobs/${encodeURIComponent(name)}/runs`, { params }).then(parseApiResponse)
+  const triggerJob = (name: string) => useApiFetch(`/jobs/${encodeURIComponent(name)}/trigger`, { method: 'POST' }).then(parseApiResponse)
+  const pauseJob = (name: string) => useApiFetch(`/jobs/${encodeURIComponent(name)}/pause`, { method: 'POST' }).then(parseApiResponse)
+  const resumeJob = (name: string) => useApiFetch(`/jobs/${encodeURIComponent(name)}/resume`, { method: 'POST' }).then(parseApiResponse)
+  const updateJobSchedule = (name: string, spec: string) => useApiFetch(`/jobs/${encodeURIComponent(name)}/schedule`, { method: 'PUT', body: { spec } }).then(parseApiResponse)
+  return { getJobs, getJobRuns, triggerJob, pauseJob, resumeJob, updateJobSchedule }
+}
+
 // 系统日志管理API
 export const useSystemLogApi = () => {
   const getSystemLogs = (params?: any) => useApiFetch('/api/system-logs', { params }).then(parseApiResponse)
@@ -436,7 +494,7 @@ export const useSitemapApi = () => {
   const getSitemapStatus = () => useApiFetch('/sitemap/status').then(parseApiResponse)
   const fullGenerateSitemap = () => useApiFetch('/sitemap/full-generate', { method: 'POST' }).then(parseApiResponse)
   const getSitemapIndex = () => useApiFetch('/sitemap.xml')
-  const getSitemapPage = (page: number) => useApiFetch(`/sitemap-${page}.xml`)
+  const getSitemapPage = (page: number) => useApiFetch(`/sitemap-resources-${page}.xml.gz`)
 
   return {
     getSitemapConfig,
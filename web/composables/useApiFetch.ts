@@ -1,25 +1,56 @@
 import { useRuntimeConfig } from '#app'
 import { useUserStore } from '~/stores/user'
 
+// 认证相关接口返回 401 时不尝试刷新令牌
+const AUTH_ENDPOINTS = ['/auth/login', '/auth/refresh', '/auth/logout']
+
 export function useApiFetch<T = any>(
   url: string,
   options: any = {}
 ): Promise<T> {
+  const userStore = useUserStore()
+
+  return doFetch<T>(url, options).catch(async (error: any) => {
+    if (error?.status !== 401 || AUTH_ENDPOINTS.includes(url)) {
+      throw error
+    }
+
+    // 访问令牌过期：刷新后重试一次，刷新失败则跳转登录
+    if (!options._retried && await userStore.refreshSession()) {
+      const headers = { ...(options.headers || {}) }
+      delete headers.Authorization
+      return useApiFetch<T>(url, { ...options, headers, _retried: true })
+    }
+
+    userStore.clearSession()
+    if (process.client) {
+      window.location.href = '/login'
+    }
+    throw Object.assign(new Error('登录已过期，请重新登录'), {
+      data: error.data,
+      status: 401,
+    })
+  })
+}
+
+function doFetch<T>(url: string, options: any): Promise<T> {
   const config = useRuntimeConfig()
   const userStore = useUserStore()
   const baseURL = process.server
     ? String(config.public.apiServer)
     : String(config.public.apiBase)
 
+  const { _retried, ...fetchOptions } = options
+
   // 自动带上 token
   const headers = {
-    ...(options.headers || {}),
+    ...(fetchOptions.headers || {}),
     ...(userStore.authHeaders || {})
   }
 
   return $fetch<T>(url, {
     baseURL,
-    ...options,
+    ...fetchOptions,
     headers,
     onResponse({ response }) {
       // console.log('API响应:', {
@@ -27,19 +58,14 @@ export function useApiFetch<T = any>(
       //   data: response._data,
       //   url: url
       // })
-      
-      // 处理401认证错误
+
+      // 处理401认证错误，由 useApiFetch 统一刷新令牌或跳转登录
       if (response.status === 401 ||
         (response._data && (response._data.code === 401 || response._data.error === '无效的令牌'))
       ) {
-        userStore.logout()
-        if (process.client) {
-          window.location.href = '/login'
-        }
-        // 触发 onResponseError 逻辑
         throw Object.assign(new Error('登录已过期，请重新登录'), {
           data: response._data,
-          status: response.status,
+          status: 401,
         })
       }
 
@@ -61,27 +87,24 @@ export function useApiFetch<T = any>(
     },
     onResponseError({ error }: { error: any }) {
       console.log('error', error)
-      
+
       // 检查是否为"无效的令牌"错误
       if (error?.data?.error === '无效的令牌') {
-        // 清除用户状态
-        userStore.logout()
-        // 跳转到登录页面
-        if (process.client) {
-          window.location.href = '/login'
-        }
-        throw new Error('登录已过期，请重新登录')
+        throw Object.assign(new Error('登录已过期，请重新登录'), {
+          data: error.data,
+          status: 401,
+        })
       }
-      
+
       // 检查是否为权限错误
       if (error?.data?.error === '需要管理员权限' || error?.status === 403) {
         throw new Error('需要管理员权限，请使用管理员账号登录')
       }
-      
+
       // 统一错误提示
       // 你可以用 naive-ui 的 useMessage() 这里弹窗
       // useMessage().error(error.message)
       throw error
     }
   })
-} 
\ No newline at end of file
+}
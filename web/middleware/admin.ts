@@ -18,12 +18,9 @@ export default defineNuxtRouteMiddleware(async (to, from) => {
     return navigateTo('/login')
   }
 
-  // 检查用户是否为管理员（通常通过用户角色或权限判断）
-  // 这里可以根据具体实现来调整，例如检查 userStore.user?.is_admin 字段
-  const isAdmin = userStore.user?.is_admin || userStore.user?.role === 'admin' || userStore.user?.username === 'admin'
-
-  if (!isAdmin) {
-    console.log('admin middleware - 用户不是管理员，重定向到首页')
+  // 管理员或拥有任一后台权限的角色可以进入管理后台，具体接口由后端按权限校验
+  if (!userStore.canAccessAdmin) {
+    console.log('admin middleware - 用户没有后台权限，重定向到首页')
     return navigateTo('/')
   }
 
@@ -5,6 +5,7 @@ interface User {
   username: string
   email: string
   role: string
+  permissions?: string[]
   created_at: string
   last_login_at?: string
 }
@@ -12,6 +13,7 @@ interface User {
 interface LoginForm {
   username: string
   password: string
+  two_factor_code?: string
 }
 
 interface RegisterForm {
@@ -20,10 +22,14 @@ interface RegisterForm {
   password: string
 }
 
+// 同一页面内并发的刷新请求共用一个 Promise，避免刷新令牌被重复使用
+let refreshing: Promise<boolean> | null = null
+
 export const useUserStore = defineStore('user', {
   state: () => ({
     user: null as User | null,
     token: null as string | null,
+    refreshToken: null as string | null,
     isAuthenticated: false,
     loading: false
   }),
@@ -31,6 +37,13 @@ export const useUserStore = defineStore('user', {
   getters: {
     // 检查是否为管理员
     isAdmin: (state) => state.user?.role === 'admin',
+
+    // 是否可以进入管理后台（管理员或拥有任一后台权限的角色）
+    canAccessAdmin: (state) => state.user?.role === 'admin' || (state.user?.permissions?.length ?? 0) > 0,
+
+    // 检查是否拥有权限
+    hasPermission: (state) => (permission: string) =>
+      state.user?.role === 'admin' || !!state.user?.permissions?.includes(permission),
     
     // 获取用户信息
     userInfo: (state) => state.user,
@@ -58,6 +71,7 @@ export const useUserStore = defineStore('user', {
           if (token && userStr) {
             try {
               this.token = token
+              this.refreshToken = localStorage.getItem('refresh_token')
               this.user = JSON.parse(userStr)
               this.isAuthenticated = true
               // console.log('initAuth - 状态恢复成功:', this.user?.username)
@@ -90,17 +104,16 @@ export const useUserStore = defineStore('user', {
       const response = await authApi.login(credentials) as any
       
       console.log('login - 响应:', response)
+
+      // 已开启两步验证，需要输入验证码后重新提交
+      if (response && response.two_factor_required) {
+        return { success: false, twoFactorRequired: true }
+      }
       
       // 使用新的统一响应格式，直接检查response是否存在
       if (response && response.token && response.user) {
         const { token, user } = response
-        this.token = token
-        this.user = user
-        this.isAuthenticated = true
-        
-        // 保存到localStorage
-        localStorage.setItem('token', token)
-        localStorage.setItem('user', JSON.stringify(user))
+        this.setSession(response)
         
         console.log('login - 状态保存成功:', user.username)
         console.log('login - localStorage token:', localStorage.getItem('token') ? 'saved' : 'not saved')
@@ -117,10 +130,10 @@ export const useUserStore = defineStore('user', {
       console.error('登录错误:', error)
       console.log('login - catch 块执行，返回错误结果')
       // 处理HTTP错误响应
-      if (error.data && error.data.error) {
+      if (error.data && (error.data.error || error.data.message)) {
         return { 
           success: false, 
-          message: error.data.error 
+          message: error.data.error || error.data.message 
         }
       }
       return { 
@@ -157,14 +170,71 @@ export const useUserStore = defineStore('user', {
     }
   },
 
+    // 保存登录或刷新接口返回的令牌与用户信息
+    setSession(data: { token: string, refresh_token?: string, user: User }) {
+      this.token = data.token
+      this.refreshToken = data.refresh_token || null
+      this.user = data.user
+      this.isAuthenticated = true
+      if (typeof window !== 'undefined') {
+        localStorage.setItem('token', data.token)
+        localStorage.setItem('user', JSON.stringify(data.user))
+        if (data.refresh_token) {
+          localStorage.setItem('refresh_token', data.refresh_token)
+        } else {
+          localStorage.removeItem('refresh_token')
+        }
+      }
+    },
+
+    // 访问令牌过期后使用刷新令牌续期，成功返回 true
+    refreshSession(): Promise<boolean> {
+      if (!this.refreshToken) {
+        return Promise.resolve(false)
+      }
+      if (!refreshing) {
+        const usedToken = this.refreshToken
+        refreshing = useAuthApi().refresh(usedToken)
+          .then((data: any) => {
+            this.setSession(data)
+            return true
+          })
+          .catch(() => {
+            // 其他标签页可能已完成刷新，沿用其保存的新令牌
+            const stored = typeof window !== 'undefined' ? localStorage.getItem('refresh_token') : null
+            if (stored && stored !== usedToken) {
+              this.token = localStorage.getItem('token')
+              this.refreshToken = stored
+              return true
+            }
+            return false
+          })
+          .finally(() => {
+            refreshing = null
+          })
+      }
+      return refreshing
+    },
+
     // 登出
     logout() {
+      if (this.token) {
+        // 通知服务端吊销会话，失败不影响本地登出
+        useAuthApi().logout().catch(() => {})
+      }
+      this.clearSession()
+    },
+
+    // 清除本地登录状态
+    clearSession() {
       this.user = null
       this.token = null
+      this.refreshToken = null
       this.isAuthenticated = false
       // 清除localStorage
       if (typeof window !== 'undefined') {
         localStorage.removeItem('token')
+        localStorage.removeItem('refresh_token')
         localStorage.removeItem('user')
       }
     },